@@ -14,6 +14,12 @@
 
 package v1beta1
 
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // AuthParams contains the authentication parameters for the tenant cluster.
 type AuthParams struct {
 	CA        []byte  `json:"ca,omitempty"`
@@ -21,5 +27,37 @@ type AuthParams struct {
 	APIServer string  `json:"apiServer,omitempty"`
 	ProxyURL  *string `json:"proxyURL,omitempty"`
 
-	AwsConfig *AwsConfig `json:"awsConfig,omitempty"`
+	AwsConfig   *AwsConfig   `json:"awsConfig,omitempty"`
+	TokenConfig *TokenConfig `json:"tokenConfig,omitempty"`
+
+	// IdentityPolicy is the lifetime and rotation policy the provider cluster enforces on the identity.
+	IdentityPolicy *IdentityPolicy `json:"identityPolicy,omitempty"`
+}
+
+// IdentityPolicy defines the lifetime and the rotation policy of the credentials of an identity.
+type IdentityPolicy struct {
+	// MaxLifetime is the maximum lifetime of the credentials issued by the provider cluster.
+	MaxLifetime *metav1.Duration `json:"maxLifetime,omitempty"`
+	// RenewBefore is how long before their expiration the credentials are renewed.
+	// If unset, or not shorter than the lifetime of the credentials, they are renewed at 2/3 of their lifetime.
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// RenewalTime returns the time the credentials valid in the given period should be renewed, according to the policy.
+func (p *IdentityPolicy) RenewalTime(notBefore, notAfter time.Time) time.Time {
+	lifetime := notAfter.Sub(notBefore)
+	if p != nil && p.RenewBefore != nil && p.RenewBefore.Duration > 0 && p.RenewBefore.Duration < lifetime {
+		return notAfter.Add(-p.RenewBefore.Duration)
+	}
+	return notAfter.Add(-lifetime / 3)
+}
+
+// TokenConfig contains a short-lived bearer token issued by the provider cluster, along with the metadata to refresh it.
+type TokenConfig struct {
+	// Token is the bearer token used to authenticate with the provider cluster.
+	Token string `json:"token"`
+	// IssuedAt is the time the token has been issued.
+	IssuedAt metav1.Time `json:"issuedAt"`
+	// ExpirationTimestamp is the time the token expires, after which it must be renewed.
+	ExpirationTimestamp metav1.Time `json:"expirationTimestamp"`
 }
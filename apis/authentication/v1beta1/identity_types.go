@@ -65,6 +65,8 @@ type IdentitySpec struct {
 type IdentityStatus struct {
 	// KubeconfigSecretRef contains the reference to the secret containing the kubeconfig to access the provider cluster.
 	KubeconfigSecretRef *corev1.LocalObjectReference `json:"kubeconfigSecretRef,omitempty"`
+	// NextRotationTime is the time the credentials of the identity are going to be renewed.
+	NextRotationTime *metav1.Time `json:"nextRotationTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -72,6 +74,7 @@ type IdentityStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
 // +kubebuilder:printcolumn:name="KubeconfigSecret",type=string,JSONPath=`.status.kubeconfigSecretRef.name`,priority=1
+// +kubebuilder:printcolumn:name="NextRotation",type=date,JSONPath=`.status.nextRotationTime`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // Identity contains the information to operate in a remote cluster.
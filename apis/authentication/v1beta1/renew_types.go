@@ -46,12 +46,19 @@ type RenewSpec struct {
 	IdentityType IdentityType `json:"identityType,omitempty"`
 	// ResoruceSliceRef is the reference to the resource slice.
 	ResourceSliceRef *corev1.LocalObjectReference `json:"resourceSliceRef,omitempty"`
+	// LeaseDuration is the requested duration of the lease of the referenced ResourceSlice (or of the Tenant, if no ResourceSlice
+	// is referenced), starting from the time of the request. If set, the Renew requests the renewal of the lease, rather than of the identity.
+	LeaseDuration *metav1.Duration `json:"leaseDuration,omitempty"`
 }
 
 // RenewStatus defines the observed state of Renew.
 type RenewStatus struct {
 	// AuthParams contains the authentication parameters for the consumer cluster.
 	AuthParams *AuthParams `json:"authParams,omitempty"`
+	// LeaseExpiration is the expiration of the lease of the ResourceSlice (or of the Tenant), as renewed by the provider cluster.
+	LeaseExpiration *metav1.Time `json:"leaseExpiration,omitempty"`
+	// ObservedGeneration is the generation of the Renew whose lease renewal has been handled by the provider cluster.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -56,8 +56,25 @@ type ResourceSliceSpec struct {
 	Class ResourceSliceClass `json:"class,omitempty"`
 	// CSR is the Certificate Signing Request of the consumer cluster.
 	CSR []byte `json:"csr,omitempty"`
+	// OfferResponse is the response of the consumer cluster to the resources offered by the provider cluster,
+	// when they are less than the requested ones.
+	// +kubebuilder:validation:Enum="Accepted";"Rejected"
+	OfferResponse ResourceSliceOfferResponse `json:"offerResponse,omitempty"`
+	// OfferID is the identifier of the offer the response refers to, as reported in the status.
+	// The response is ignored if it does not match the current offer.
+	OfferID string `json:"offerID,omitempty"`
 }
 
+// ResourceSliceOfferResponse is the response of the consumer cluster to the resources offered by the provider cluster.
+type ResourceSliceOfferResponse string
+
+const (
+	// ResourceSliceOfferAccepted means that the consumer cluster accepts the offered resources.
+	ResourceSliceOfferAccepted ResourceSliceOfferResponse = "Accepted"
+	// ResourceSliceOfferRejected means that the consumer cluster rejects the offered resources.
+	ResourceSliceOfferRejected ResourceSliceOfferResponse = "Rejected"
+)
+
 // ResourceSliceConditionType represents different types of conditions that a ResourceSlice could assume.
 type ResourceSliceConditionType string
 
@@ -66,6 +83,8 @@ const (
 	ResourceSliceConditionTypeAuthentication ResourceSliceConditionType = "Authentication"
 	// ResourceSliceConditionTypeResources informs users that the resources of the ResourceSlice are in progress.
 	ResourceSliceConditionTypeResources ResourceSliceConditionType = "Resources"
+	// ResourceSliceConditionTypeLease informs users about the lease of the resources of the ResourceSlice, if time-bounded.
+	ResourceSliceConditionTypeLease ResourceSliceConditionType = "Lease"
 )
 
 // ResourceSliceConditionStatus represents different status conditions that a ResourceSlice could assume.
@@ -77,15 +96,23 @@ const (
 	ResourceSliceConditionAccepted ResourceSliceConditionStatus = "Accepted"
 	// ResourceSliceConditionDenied informs users that the resources are not available.
 	ResourceSliceConditionDenied ResourceSliceConditionStatus = "Denied"
+	// ResourceSliceConditionOffered informs users that the provider offers less resources than the requested ones,
+	// and it is waiting for the consumer to accept or reject the offer.
+	ResourceSliceConditionOffered ResourceSliceConditionStatus = "Offered"
+	// ResourceSliceConditionExpiring informs users that the lease of the resources is about to expire.
+	ResourceSliceConditionExpiring ResourceSliceConditionStatus = "Expiring"
+	// ResourceSliceConditionExpired informs users that the lease of the resources is expired,
+	// hence they are cordoned and the ResourceSlice is going to be removed.
+	ResourceSliceConditionExpired ResourceSliceConditionStatus = "Expired"
 )
 
 // ResourceSliceCondition contains details about the status of the provided ResourceSlice.
 type ResourceSliceCondition struct {
 	// Type of the condition.
-	// +kubebuilder:validation:Enum="Authentication";"Resources"
+	// +kubebuilder:validation:Enum="Authentication";"Resources";"Lease"
 	Type ResourceSliceConditionType `json:"type"`
 	// Status of the condition.
-	// +kubebuilder:validation:Enum="Accepted";"Denied"
+	// +kubebuilder:validation:Enum="Accepted";"Denied";"Offered";"Expiring";"Expired"
 	Status ResourceSliceConditionStatus `json:"status"`
 	// LastTransitionTime -> timestamp for when the condition last transitioned from one status to another.
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
@@ -101,6 +128,13 @@ type ResourceSliceStatus struct {
 	Conditions []ResourceSliceCondition `json:"conditions,omitempty"`
 	// Resources contains the slice of resources accepted.
 	Resources corev1.ResourceList `json:"resources,omitempty"`
+	// RequestedResources contains the requested resources (including the default ones) the accepted resources have been decided for.
+	RequestedResources corev1.ResourceList `json:"requestedResources,omitempty"`
+	// Offer contains the resources offered by the provider cluster and waiting for the response of the consumer, if any.
+	// The resources already accepted, if any, are kept until the offer is accepted.
+	Offer *ResourceSliceOffer `json:"offer,omitempty"`
+	// LeaseExpiration is the time the lease of the resources expires, if time-bounded.
+	LeaseExpiration *metav1.Time `json:"leaseExpiration,omitempty"`
 	// AuthParams contains the authentication parameters for the resources given by the provider cluster.
 	AuthParams *AuthParams `json:"authParams,omitempty"`
 	// StorageClasses contains the list of the storage classes offered by the cluster.
@@ -113,6 +147,26 @@ type ResourceSliceStatus struct {
 	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
 	// NodeSelector contains the selector to be applied to offloaded pods.
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Nodes contains the ready nodes of the provider cluster matching the node selector, which can host the offloaded pods.
+	Nodes []ResourceSliceNode `json:"nodes,omitempty"`
+}
+
+// ResourceSliceOffer represents the resources offered by the provider cluster, when less than the requested ones.
+type ResourceSliceOffer struct {
+	// ID identifies the offer, and it shall be set in the offerID field of the spec to respond to it.
+	ID string `json:"id"`
+	// Resources contains the offered resources.
+	Resources corev1.ResourceList `json:"resources,omitempty"`
+	// RequestedResources contains the requested resources (including the default ones) the offer has been made for.
+	RequestedResources corev1.ResourceList `json:"requestedResources,omitempty"`
+}
+
+// ResourceSliceNode represents a node of the provider cluster which can host the pods offloaded through the ResourceSlice.
+type ResourceSliceNode struct {
+	// Name is the name of the node.
+	Name string `json:"name"`
+	// Labels contains the labels of the node.
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -120,6 +174,7 @@ type ResourceSliceStatus struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Authentication",type=string,JSONPath=`.status.conditions[?(@.type=="Authentication")].status`
 // +kubebuilder:printcolumn:name="Resources",type=string,JSONPath=`.status.conditions[?(@.type=="Resources")].status`
+// +kubebuilder:printcolumn:name="Lease Expiration",type=date,JSONPath=`.status.leaseExpiration`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ResourceSlice represents a slice of resources given by the provider cluster to the consumer cluster.
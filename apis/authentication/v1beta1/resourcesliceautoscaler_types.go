@@ -0,0 +1,97 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourceSliceAutoscalerResource is the name of the resourceSliceAutoscaler resources.
+var ResourceSliceAutoscalerResource = "resourcesliceautoscalers"
+
+// ResourceSliceAutoscalerKind specifies the kind of the resourceSliceAutoscaler.
+var ResourceSliceAutoscalerKind = "ResourceSliceAutoscaler"
+
+// ResourceSliceAutoscalerGroupResource is group resource used to register these objects.
+var ResourceSliceAutoscalerGroupResource = schema.GroupResource{Group: GroupVersion.Group, Resource: ResourceSliceAutoscalerResource}
+
+// ResourceSliceAutoscalerGroupVersionResource is groupResourceVersion used to register these objects.
+var ResourceSliceAutoscalerGroupVersionResource = GroupVersion.WithResource(ResourceSliceAutoscalerResource)
+
+// ResourceSliceAutoscalerSpec defines the desired state of ResourceSliceAutoscaler.
+type ResourceSliceAutoscalerSpec struct {
+	// ResourceSliceName is the name of the ResourceSlice, in the same namespace, whose requested resources are resized.
+	ResourceSliceName string `json:"resourceSliceName"`
+	// MinResources are the minimum resources requested by the ResourceSlice.
+	MinResources corev1.ResourceList `json:"minResources,omitempty"`
+	// MaxResources are the maximum resources requested by the ResourceSlice.
+	// Only the resources listed here are resized by the autoscaler.
+	MaxResources corev1.ResourceList `json:"maxResources"`
+	// TargetUtilization is the target percentage of the requested resources used by the pods scheduled
+	// (or waiting to be scheduled) on the virtual node of the ResourceSlice.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=70
+	TargetUtilization int32 `json:"targetUtilization,omitempty"`
+	// ScaleDownDelay is the minimum amount of time between a resize of the ResourceSlice and the following scale down.
+	// +kubebuilder:default="10m"
+	ScaleDownDelay metav1.Duration `json:"scaleDownDelay,omitempty"`
+}
+
+// ResourceSliceAutoscalerStatus defines the observed state of ResourceSliceAutoscaler.
+type ResourceSliceAutoscalerStatus struct {
+	// Demand contains the resources requested by the pods scheduled on the virtual node of the ResourceSlice,
+	// and by the pending pods which could be scheduled on it.
+	Demand corev1.ResourceList `json:"demand,omitempty"`
+	// PendingPods is the number of pending pods which could be scheduled on the virtual node of the ResourceSlice.
+	PendingPods int32 `json:"pendingPods,omitempty"`
+	// Resources contains the resources currently requested by the ResourceSlice.
+	Resources corev1.ResourceList `json:"resources,omitempty"`
+	// LastScaleTime is the last time the ResourceSlice has been resized.
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=liqo,shortName=rsliceas
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="ResourceSlice",type=string,JSONPath=`.spec.resourceSliceName`
+// +kubebuilder:printcolumn:name="Target",type=integer,JSONPath=`.spec.targetUtilization`
+// +kubebuilder:printcolumn:name="Pending",type=integer,JSONPath=`.status.pendingPods`
+// +kubebuilder:printcolumn:name="Last Scale",type=date,JSONPath=`.status.lastScaleTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ResourceSliceAutoscaler resizes the resources requested by a ResourceSlice according to the demand of the consumer cluster.
+type ResourceSliceAutoscaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ResourceSliceAutoscalerSpec   `json:"spec,omitempty"`
+	Status ResourceSliceAutoscalerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ResourceSliceAutoscalerList contains a list of ResourceSliceAutoscalers.
+type ResourceSliceAutoscalerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ResourceSliceAutoscaler `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ResourceSliceAutoscaler{}, &ResourceSliceAutoscalerList{})
+}
@@ -0,0 +1,83 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+)
+
+// RevokedCertificateResource is the name of the revokedCertificate resources.
+var RevokedCertificateResource = "revokedcertificates"
+
+// RevokedCertificateKind specifies the kind of the revokedCertificate.
+var RevokedCertificateKind = "RevokedCertificate"
+
+// RevokedCertificateGroupResource is group resource used to register these objects.
+var RevokedCertificateGroupResource = schema.GroupResource{Group: GroupVersion.Group, Resource: RevokedCertificateResource}
+
+// RevokedCertificateGroupVersionResource is groupResourceVersion used to register these objects.
+var RevokedCertificateGroupVersionResource = GroupVersion.WithResource(RevokedCertificateResource)
+
+// RevokedCertificateSpec defines the desired state of RevokedCertificate.
+type RevokedCertificateSpec struct {
+	// ConsumerClusterID is the id of the consumer cluster the certificate was issued to.
+	ConsumerClusterID liqov1beta1.ClusterID `json:"consumerClusterID"`
+	// IdentityType is the type of the identity the certificate was issued for.
+	IdentityType IdentityType `json:"identityType"`
+	// Name is the name of the Tenant or ResourceSlice the certificate was issued for.
+	Name string `json:"name"`
+	// User is the user the certificate authenticates as.
+	User string `json:"user"`
+	// Groups are the groups, dedicated to the tenant, the certificate authenticates as.
+	Groups []string `json:"groups,omitempty"`
+	// NotAfter is the expiration time of the certificate, after which the revocation is no longer necessary.
+	NotAfter metav1.Time `json:"notAfter"`
+	// RevokedAt is the time the certificate has been revoked.
+	RevokedAt metav1.Time `json:"revokedAt"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=liqo,scope=Cluster,shortName=rcert
+// +kubebuilder:printcolumn:name="ClusterID",type=string,JSONPath=`.spec.consumerClusterID`
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.identityType`
+// +kubebuilder:printcolumn:name="User",type=string,JSONPath=`.spec.user`
+// +kubebuilder:printcolumn:name="Expiration",type=date,JSONPath=`.spec.notAfter`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// RevokedCertificate is a certificate issued to a consumer cluster which has been revoked. It is named after the
+// hex-encoded SHA-256 fingerprint of the certificate, and it is independent of the Tenant, so that the revocation
+// outlives its deletion. It can be safely deleted once the certificate has expired.
+type RevokedCertificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RevokedCertificateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RevokedCertificateList contains a list of RevokedCertificates.
+type RevokedCertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RevokedCertificate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RevokedCertificate{}, &RevokedCertificateList{})
+}
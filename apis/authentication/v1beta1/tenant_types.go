@@ -73,9 +73,12 @@ type TenantSpec struct {
 	// ProxyURL is the URL of the proxy used by the tenant cluster to connect to the local cluster (optional).
 	ProxyURL *string `json:"proxyURL,omitempty"`
 	// TenantCondition contains the conditions of the tenant.
-	// +kubebuilder:validation:Enum=Active;Cordoned;Drained
+	// +kubebuilder:validation:Enum=Active;Cordoned;Drained;Revoked
 	// +kubebuilder:default=Active
 	TenantCondition TenantCondition `json:"tenantCondition,omitempty"`
+	// LeaseExpiration is the time the lease of the tenant expires (optional). When expired, the tenant is drained.
+	// The leases of its ResourceSlices do not last longer.
+	LeaseExpiration *metav1.Time `json:"leaseExpiration,omitempty"`
 }
 
 // TenantCondition contains the conditions of the tenant.
@@ -89,14 +92,33 @@ const (
 	TenantConditionCordoned TenantCondition = "Cordoned"
 	// TenantConditionDrained indicates that the tenant is drained: it can't consume resources nor negotiate new ones.
 	TenantConditionDrained TenantCondition = "Drained"
+	// TenantConditionRevoked indicates that the tenant credentials are revoked: besides being drained, its permissions
+	// are removed and any request authenticated with the identities issued to it is denied. It cannot be reactivated.
+	TenantConditionRevoked TenantCondition = "Revoked"
 )
 
+// RevokedIdentity is an identity issued to the tenant whose credentials have been revoked.
+type RevokedIdentity struct {
+	// Type is the type of the revoked identity.
+	Type IdentityType `json:"type"`
+	// Name is the name of the Tenant or ResourceSlice the identity was issued for.
+	Name string `json:"name"`
+	// User is the user the identity authenticates as.
+	User string `json:"user"`
+	// Groups are the groups, dedicated to the tenant, the identity authenticates as.
+	Groups []string `json:"groups,omitempty"`
+	// RevokedAt is the time the identity has been revoked.
+	RevokedAt metav1.Time `json:"revokedAt"`
+}
+
 // TenantStatus defines the observed state of Tenant.
 type TenantStatus struct {
 	// TenantNamespace is the namespace of the tenant cluster.
 	TenantNamespace string `json:"tenantNamespace,omitempty"`
 	// AuthParams contains the authentication parameters for the consumer cluster.
 	AuthParams *AuthParams `json:"authParams,omitempty"`
+	// RevokedIdentities contains the identities issued to the tenant whose credentials have been revoked.
+	RevokedIdentities []RevokedIdentity `json:"revokedIdentities,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -20,7 +20,8 @@ package v1beta1
 
 import (
 	corev1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
-	"k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -47,6 +48,16 @@ func (in *AuthParams) DeepCopyInto(out *AuthParams) {
 		*out = new(AwsConfig)
 		**out = **in
 	}
+	if in.TokenConfig != nil {
+		in, out := &in.TokenConfig, &out.TokenConfig
+		*out = new(TokenConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IdentityPolicy != nil {
+		in, out := &in.IdentityPolicy, &out.IdentityPolicy
+		*out = new(IdentityPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuthParams.
@@ -133,6 +144,31 @@ func (in *IdentityList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdentityPolicy) DeepCopyInto(out *IdentityPolicy) {
+	*out = *in
+	if in.MaxLifetime != nil {
+		in, out := &in.MaxLifetime, &out.MaxLifetime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityPolicy.
+func (in *IdentityPolicy) DeepCopy() *IdentityPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(IdentityPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IdentitySpec) DeepCopyInto(out *IdentitySpec) {
 	*out = *in
@@ -159,9 +195,13 @@ func (in *IdentityStatus) DeepCopyInto(out *IdentityStatus) {
 	*out = *in
 	if in.KubeconfigSecretRef != nil {
 		in, out := &in.KubeconfigSecretRef, &out.KubeconfigSecretRef
-		*out = new(v1.LocalObjectReference)
+		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
+	if in.NextRotationTime != nil {
+		in, out := &in.NextRotationTime, &out.NextRotationTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityStatus.
@@ -248,7 +288,12 @@ func (in *RenewSpec) DeepCopyInto(out *RenewSpec) {
 	}
 	if in.ResourceSliceRef != nil {
 		in, out := &in.ResourceSliceRef, &out.ResourceSliceRef
-		*out = new(v1.LocalObjectReference)
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.LeaseDuration != nil {
+		in, out := &in.LeaseDuration, &out.LeaseDuration
+		*out = new(v1.Duration)
 		**out = **in
 	}
 }
@@ -271,6 +316,10 @@ func (in *RenewStatus) DeepCopyInto(out *RenewStatus) {
 		*out = new(AuthParams)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.LeaseExpiration != nil {
+		in, out := &in.LeaseExpiration, &out.LeaseExpiration
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenewStatus.
@@ -310,6 +359,128 @@ func (in *ResourceSlice) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSliceAutoscaler) DeepCopyInto(out *ResourceSliceAutoscaler) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSliceAutoscaler.
+func (in *ResourceSliceAutoscaler) DeepCopy() *ResourceSliceAutoscaler {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSliceAutoscaler)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ResourceSliceAutoscaler) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSliceAutoscalerList) DeepCopyInto(out *ResourceSliceAutoscalerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ResourceSliceAutoscaler, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSliceAutoscalerList.
+func (in *ResourceSliceAutoscalerList) DeepCopy() *ResourceSliceAutoscalerList {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSliceAutoscalerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ResourceSliceAutoscalerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSliceAutoscalerSpec) DeepCopyInto(out *ResourceSliceAutoscalerSpec) {
+	*out = *in
+	if in.MinResources != nil {
+		in, out := &in.MinResources, &out.MinResources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.MaxResources != nil {
+		in, out := &in.MaxResources, &out.MaxResources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	out.ScaleDownDelay = in.ScaleDownDelay
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSliceAutoscalerSpec.
+func (in *ResourceSliceAutoscalerSpec) DeepCopy() *ResourceSliceAutoscalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSliceAutoscalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSliceAutoscalerStatus) DeepCopyInto(out *ResourceSliceAutoscalerStatus) {
+	*out = *in
+	if in.Demand != nil {
+		in, out := &in.Demand, &out.Demand
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSliceAutoscalerStatus.
+func (in *ResourceSliceAutoscalerStatus) DeepCopy() *ResourceSliceAutoscalerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSliceAutoscalerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSliceCondition) DeepCopyInto(out *ResourceSliceCondition) {
 	*out = *in
@@ -358,6 +529,57 @@ func (in *ResourceSliceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSliceNode) DeepCopyInto(out *ResourceSliceNode) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSliceNode.
+func (in *ResourceSliceNode) DeepCopy() *ResourceSliceNode {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSliceNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceSliceOffer) DeepCopyInto(out *ResourceSliceOffer) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.RequestedResources != nil {
+		in, out := &in.RequestedResources, &out.RequestedResources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSliceOffer.
+func (in *ResourceSliceOffer) DeepCopy() *ResourceSliceOffer {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceSliceOffer)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSliceSpec) DeepCopyInto(out *ResourceSliceSpec) {
 	*out = *in
@@ -373,7 +595,7 @@ func (in *ResourceSliceSpec) DeepCopyInto(out *ResourceSliceSpec) {
 	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
-		*out = make(v1.ResourceList, len(*in))
+		*out = make(corev1.ResourceList, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val.DeepCopy()
 		}
@@ -407,11 +629,27 @@ func (in *ResourceSliceStatus) DeepCopyInto(out *ResourceSliceStatus) {
 	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
-		*out = make(v1.ResourceList, len(*in))
+		*out = make(corev1.ResourceList, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val.DeepCopy()
 		}
 	}
+	if in.RequestedResources != nil {
+		in, out := &in.RequestedResources, &out.RequestedResources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Offer != nil {
+		in, out := &in.Offer, &out.Offer
+		*out = new(ResourceSliceOffer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LeaseExpiration != nil {
+		in, out := &in.LeaseExpiration, &out.LeaseExpiration
+		*out = (*in).DeepCopy()
+	}
 	if in.AuthParams != nil {
 		in, out := &in.AuthParams, &out.AuthParams
 		*out = new(AuthParams)
@@ -446,6 +684,13 @@ func (in *ResourceSliceStatus) DeepCopyInto(out *ResourceSliceStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]ResourceSliceNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSliceStatus.
@@ -458,6 +703,107 @@ func (in *ResourceSliceStatus) DeepCopy() *ResourceSliceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevokedCertificate) DeepCopyInto(out *RevokedCertificate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevokedCertificate.
+func (in *RevokedCertificate) DeepCopy() *RevokedCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(RevokedCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RevokedCertificate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevokedCertificateList) DeepCopyInto(out *RevokedCertificateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RevokedCertificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevokedCertificateList.
+func (in *RevokedCertificateList) DeepCopy() *RevokedCertificateList {
+	if in == nil {
+		return nil
+	}
+	out := new(RevokedCertificateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RevokedCertificateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevokedCertificateSpec) DeepCopyInto(out *RevokedCertificateSpec) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+	in.RevokedAt.DeepCopyInto(&out.RevokedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevokedCertificateSpec.
+func (in *RevokedCertificateSpec) DeepCopy() *RevokedCertificateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RevokedCertificateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevokedIdentity) DeepCopyInto(out *RevokedIdentity) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.RevokedAt.DeepCopyInto(&out.RevokedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevokedIdentity.
+func (in *RevokedIdentity) DeepCopy() *RevokedIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(RevokedIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Tenant) DeepCopyInto(out *Tenant) {
 	*out = *in
@@ -545,6 +891,10 @@ func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.LeaseExpiration != nil {
+		in, out := &in.LeaseExpiration, &out.LeaseExpiration
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSpec.
@@ -565,6 +915,13 @@ func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
 		*out = new(AuthParams)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RevokedIdentities != nil {
+		in, out := &in.RevokedIdentities, &out.RevokedIdentities
+		*out = make([]RevokedIdentity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantStatus.
@@ -576,3 +933,20 @@ func (in *TenantStatus) DeepCopy() *TenantStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenConfig) DeepCopyInto(out *TokenConfig) {
+	*out = *in
+	in.IssuedAt.DeepCopyInto(&out.IssuedAt)
+	in.ExpirationTimestamp.DeepCopyInto(&out.ExpirationTimestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenConfig.
+func (in *TokenConfig) DeepCopy() *TokenConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenConfig)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -74,6 +74,12 @@ var (
 	// ShadowPodGroupVersionResource is groupResourceVersion used to register these objects.
 	ShadowPodGroupVersionResource = SchemeGroupVersion.WithResource(ShadowPodResource)
 
+	// ShadowPodPolicyResource is the resource name used to register the ShadowPodPolicy CRD.
+	ShadowPodPolicyResource = "shadowpodpolicies"
+
+	// ShadowPodPolicyGroupResource is group resource used to register these objects.
+	ShadowPodPolicyGroupResource = schema.GroupResource{Group: SchemeGroupVersion.Group, Resource: ShadowPodPolicyResource}
+
 	// ShadowEndpointSliceResource is the resource name used to register the ShadowEndpointSlice CRD.
 	ShadowEndpointSliceResource = "shadowendpointslices"
 
@@ -17,6 +17,8 @@ package v1beta1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 )
 
 // OffloadingPhaseType represents different namespaces offloading status.
@@ -76,6 +78,8 @@ const (
 	NamespaceOffloadingRequired RemoteNamespaceConditionType = "OffloadingRequired"
 	// NamespaceReady, remote Namespace is correctly created and ready to be used.
 	NamespaceReady RemoteNamespaceConditionType = "Ready"
+	// NamespaceDegraded, the remote cluster hosting the Namespace is unavailable, and the hosted workloads have been failed over.
+	NamespaceDegraded RemoteNamespaceConditionType = "Degraded"
 )
 
 // RemoteNamespaceConditions list of RemoteNamespaceCondition.
@@ -123,6 +127,43 @@ type NamespaceOffloadingSpec struct {
 	// (https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/#node-affinity).
 	// A cluster selector with no NodeSelectorTerms matches all clusters.
 	ClusterSelector corev1.NodeSelector `json:"clusterSelector,omitempty"`
+
+	// PlacementPolicy allows users to further restrict the set of remote clusters selected through the ClusterSelector,
+	// ranking them according to the preferred terms and their cost/latency scores, and selecting the best ones only.
+	// If unset, the namespace is offloaded to all the clusters matching the ClusterSelector.
+	// +kubebuilder:validation:Optional
+	PlacementPolicy *PlacementPolicy `json:"placementPolicy,omitempty"`
+}
+
+// PlacementPolicy defines how to choose the target clusters among the ones matching the ClusterSelector.
+type PlacementPolicy struct {
+	// PreferredClusters are the weighted terms used to rank the clusters matching the ClusterSelector,
+	// with the same semantic of the preferred node affinity terms (matched against the labels of the virtual nodes).
+	PreferredClusters []corev1.PreferredSchedulingTerm `json:"preferredClusters,omitempty"`
+	// CostWeight is the weight of the cost of each cluster (as specified by the liqo.io/cost annotation of the
+	// VirtualNode or ForeignCluster resources), which is subtracted from the ranking score.
+	// +kubebuilder:validation:Minimum=0
+	CostWeight int32 `json:"costWeight,omitempty"`
+	// LatencyWeight is the weight of the latency of each cluster (as specified by the liqo.io/latency annotation of the
+	// VirtualNode or ForeignCluster resources), which is subtracted from the ranking score.
+	// +kubebuilder:validation:Minimum=0
+	LatencyWeight int32 `json:"latencyWeight,omitempty"`
+	// MaxClusters is the maximum number of clusters to be selected, choosing the ones with the highest ranking score.
+	// Zero means no limit.
+	// +kubebuilder:validation:Minimum=0
+	MaxClusters int32 `json:"maxClusters,omitempty"`
+	// SpreadConstraints limit the number of clusters selected in each topology domain.
+	SpreadConstraints []ClusterSpreadConstraint `json:"spreadConstraints,omitempty"`
+}
+
+// ClusterSpreadConstraint defines the maximum number of clusters that can be selected in a given topology domain.
+type ClusterSpreadConstraint struct {
+	// TopologyKey is the key of the virtual node label identifying the topology domains (e.g., topology.kubernetes.io/region).
+	// Clusters without the label are considered as belonging to the same domain.
+	TopologyKey string `json:"topologyKey"`
+	// MaxClustersPerDomain is the maximum number of clusters that can be selected in each topology domain.
+	// +kubebuilder:validation:Minimum=1
+	MaxClustersPerDomain int32 `json:"maxClustersPerDomain"`
 }
 
 // NamespaceOffloadingStatus defines the observed state of NamespaceOffloading.
@@ -140,6 +181,8 @@ type NamespaceOffloadingStatus struct {
 	// RemoteNamespacesConditions -> allows user to verify remote Namespaces' presence and status on all remote
 	// clusters through RemoteNamespaceCondition.
 	RemoteNamespacesConditions map[string]RemoteNamespaceConditions `json:"remoteNamespacesConditions,omitempty"`
+	// SelectedClusters contains the clusters chosen through the PlacementPolicy, if specified.
+	SelectedClusters []liqov1beta1.ClusterID `json:"selectedClusters,omitempty"`
 	// The generation observed by the NamespaceOffloading controller.
 	// This field allows external tools (e.g., liqoctl) to detect whether a spec modification has already been processed
 	// or not (i.e., whether the status should be expected to be up-to-date or not), and thus act accordingly.
@@ -43,12 +43,23 @@ type QuotaSpec struct {
 	Resources corev1.ResourceList `json:"resources"`
 	// Cordoned indicates if the user is cordoned.
 	Cordoned *bool `json:"cordoned,omitempty"`
+	// Priority is the priority of the user with respect to the other users sharing the same provider cluster.
+	// When resources need to be reclaimed, the ShadowPods borrowing resources on behalf of the users with the lowest priority
+	// are preempted first. Additionally, users can preempt the ShadowPods of lower priority users to borrow resources in turn.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+	// BorrowingLimit is the maximum amount of resources the user can borrow, beyond the ones granted by the quota,
+	// from the resources not currently used by the other users. The ShadowPods borrowing resources can be preempted
+	// whenever the other users reclaim them.
+	// +optional
+	BorrowingLimit corev1.ResourceList `json:"borrowingLimit,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:categories=liqo,shortName=qt
 // +kubebuilder:printcolumn:name="Enforcement",type=string,JSONPath=`.spec.limitsEnforcement`
 // +kubebuilder:printcolumn:name="Cordoned",type=boolean,JSONPath=`.spec.cordoned`
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=`.spec.priority`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 // +kubebuilder:printcolumn:name="User",type=string,JSONPath=`.spec.user`,priority=1
 
@@ -0,0 +1,63 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ShadowJobSpec defines the desired state of ShadowJob.
+type ShadowJobSpec struct {
+	// Job is the specification of the Job to be created in the provider cluster.
+	Job batchv1.JobSpec `json:"job,omitempty"`
+}
+
+// ShadowJobStatus defines the observed state of ShadowJob.
+type ShadowJobStatus struct {
+	// Job is the status of the Job created from this ShadowJob, as observed by the operator.
+	Job batchv1.JobStatus `json:"job,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=liqo,shortName=shj;shjob
+// +kubebuilder:subresource:status
+// +genclient
+// +kubebuilder:printcolumn:name="Active",type=integer,JSONPath=`.status.job.active`
+// +kubebuilder:printcolumn:name="Succeeded",type=integer,JSONPath=`.status.job.succeeded`
+// +kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.job.failed`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ShadowJob is the Schema for the ShadowJobs API.
+type ShadowJob struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ShadowJobSpec   `json:"spec,omitempty"`
+	Status ShadowJobStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ShadowJobList contains a list of ShadowJob.
+type ShadowJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ShadowJob `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ShadowJob{}, &ShadowJobList{})
+}
@@ -31,6 +31,16 @@ type ShadowPodStatus struct {
 	// +kubebuilder:validation:Enum="Pending";"Running";"Succeeded";"Failed";"Unknown"
 	// +kubebuilder:default="Unknown"
 	Phase corev1.PodPhase `json:"phase"`
+	// Preemption is set by the provider cluster when the ShadowPod is preempted, before being deleted,
+	// to reclaim the resources it borrowed on behalf of its consumer.
+	// +optional
+	Preemption *ShadowPodPreemption `json:"preemption,omitempty"`
+}
+
+// ShadowPodPreemption contains the information about the preemption of a ShadowPod.
+type ShadowPodPreemption struct {
+	// Message is a human readable message indicating the reason of the preemption.
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,99 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+)
+
+// ShadowPodPolicyViolationReason is the reason of the errors returned when a ShadowPod violates a ShadowPodPolicy.
+const ShadowPodPolicyViolationReason metav1.StatusReason = "ShadowPodPolicyViolation"
+
+// ShadowPodPolicyRules defines the constraints the ShadowPods have to comply with.
+type ShadowPodPolicyRules struct {
+	// AllowedImageRegistries is the list of registries (optionally followed by a repository prefix, e.g., ghcr.io/liqotech)
+	// the images of the containers can be pulled from. If empty, any registry is allowed.
+	// +optional
+	AllowedImageRegistries []string `json:"allowedImageRegistries,omitempty"`
+	// ForbiddenHostPaths is the list of host paths (including their subdirectories) which cannot be mounted
+	// through hostPath volumes.
+	// +optional
+	ForbiddenHostPaths []string `json:"forbiddenHostPaths,omitempty"`
+	// ForbiddenCapabilities is the list of capabilities which cannot be added to the containers (ALL forbids any capability).
+	// Privileged containers are considered as adding all capabilities.
+	// +optional
+	ForbiddenCapabilities []corev1.Capability `json:"forbiddenCapabilities,omitempty"`
+	// RequireSeccompProfile requires all containers to be confined by a seccomp profile (i.e., RuntimeDefault or Localhost).
+	// +optional
+	RequireSeccompProfile *bool `json:"requireSeccompProfile,omitempty"`
+	// MaxContainers is the maximum number of containers (including the init and the ephemeral ones) of each pod.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxContainers *int32 `json:"maxContainers,omitempty"`
+	// AllowedVolumeTypes is the list of volume types (e.g., configMap, secret, emptyDir, persistentVolumeClaim) the pods
+	// can use, named after the corresponding fields of the volume specification. If empty, any volume type is allowed.
+	// +optional
+	AllowedVolumeTypes []string `json:"allowedVolumeTypes,omitempty"`
+}
+
+// ShadowPodPolicyOverride defines the rules overriding the default ones for a set of consumer clusters.
+type ShadowPodPolicyOverride struct {
+	// ClusterIDs is the list of consumer clusters the override applies to.
+	// +kubebuilder:validation:MinItems=1
+	ClusterIDs []liqov1beta1.ClusterID `json:"clusterIDs"`
+	// ShadowPodPolicyRules are the rules replacing the default ones. Only the rules explicitly set are replaced.
+	ShadowPodPolicyRules `json:",inline"`
+}
+
+// ShadowPodPolicySpec defines the desired state of ShadowPodPolicy.
+type ShadowPodPolicySpec struct {
+	// ShadowPodPolicyRules are the rules applied to the ShadowPods of all consumer clusters, unless overridden.
+	ShadowPodPolicyRules `json:",inline"`
+	// Overrides are the rules applied to the ShadowPods of specific consumer clusters, replacing the default ones.
+	// When multiple overrides match the same cluster, they are applied in order.
+	// +optional
+	Overrides []ShadowPodPolicyOverride `json:"overrides,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories=liqo,shortName=spp
+// +kubebuilder:printcolumn:name="Max Containers",type=integer,JSONPath=`.spec.maxContainers`
+// +kubebuilder:printcolumn:name="Seccomp",type=boolean,JSONPath=`.spec.requireSeccompProfile`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ShadowPodPolicy is the Schema for the ShadowPodPolicies API, defining the admission constraints
+// the ShadowPods created by the consumer clusters have to comply with.
+type ShadowPodPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ShadowPodPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ShadowPodPolicyList contains a list of ShadowPodPolicy.
+type ShadowPodPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ShadowPodPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ShadowPodPolicy{}, &ShadowPodPolicyList{})
+}
@@ -0,0 +1,104 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkloadMigrationPhase represents the phase of a WorkloadMigration.
+// +kubebuilder:validation:Enum="Pending";"ScalingDown";"MovingVolumes";"ScalingUp";"RollingBack";"Succeeded";"Failed"
+type WorkloadMigrationPhase string
+
+const (
+	// WorkloadMigrationPhasePending means that the migration has not started yet.
+	WorkloadMigrationPhasePending WorkloadMigrationPhase = "Pending"
+	// WorkloadMigrationPhaseScalingDown means that the workload is being scaled down.
+	WorkloadMigrationPhaseScalingDown WorkloadMigrationPhase = "ScalingDown"
+	// WorkloadMigrationPhaseMovingVolumes means that the volumes of the workload are being moved to the target node.
+	WorkloadMigrationPhaseMovingVolumes WorkloadMigrationPhase = "MovingVolumes"
+	// WorkloadMigrationPhaseScalingUp means that the workload is being retargeted to the target node and scaled up.
+	WorkloadMigrationPhaseScalingUp WorkloadMigrationPhase = "ScalingUp"
+	// WorkloadMigrationPhaseRollingBack means that the migration failed, and the original configuration is being restored.
+	WorkloadMigrationPhaseRollingBack WorkloadMigrationPhase = "RollingBack"
+	// WorkloadMigrationPhaseSucceeded means that the workload has been successfully migrated.
+	WorkloadMigrationPhaseSucceeded WorkloadMigrationPhase = "Succeeded"
+	// WorkloadMigrationPhaseFailed means that the migration failed.
+	WorkloadMigrationPhaseFailed WorkloadMigrationPhase = "Failed"
+)
+
+// WorkloadReference references the workload to be migrated.
+type WorkloadReference struct {
+	// Kind is the kind of the workload.
+	// +kubebuilder:validation:Enum="Deployment";"StatefulSet"
+	Kind string `json:"kind"`
+	// Name is the name of the workload, which lives in the same namespace of the WorkloadMigration.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// WorkloadMigrationSpec defines the desired state of WorkloadMigration.
+type WorkloadMigrationSpec struct {
+	// WorkloadRef references the workload to be migrated.
+	WorkloadRef WorkloadReference `json:"workloadRef"`
+	// TargetNode is the node (either physical or virtual) the workload is migrated to.
+	// +kubebuilder:validation:MinLength=1
+	TargetNode string `json:"targetNode"`
+}
+
+// WorkloadMigrationStatus defines the observed state of WorkloadMigration.
+type WorkloadMigrationStatus struct {
+	// Phase is the current phase of the migration.
+	Phase WorkloadMigrationPhase `json:"phase,omitempty"`
+	// OriginalReplicas is the number of replicas of the workload before the migration started.
+	OriginalReplicas *int32 `json:"originalReplicas,omitempty"`
+	// MigratedVolumes is the list of PVCs already moved to the target node.
+	MigratedVolumes []string `json:"migratedVolumes,omitempty"`
+	// Message is a human readable message about the status of the migration.
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=liqo,shortName=wlm
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.workloadRef.kind`
+// +kubebuilder:printcolumn:name="Workload",type=string,JSONPath=`.spec.workloadRef.name`
+// +kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.spec.targetNode`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// WorkloadMigration is the Schema for the WorkloadMigrations API.
+// It records the progress of the migration of a stateful workload, along with its volumes, to a different node (i.e., cluster).
+// The migration is driven by liqoctl, which also updates the status, while no controller reconciles this resource.
+type WorkloadMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WorkloadMigrationSpec   `json:"spec,omitempty"`
+	Status WorkloadMigrationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkloadMigrationList contains a list of WorkloadMigration.
+type WorkloadMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkloadMigration `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkloadMigration{}, &WorkloadMigrationList{})
+}
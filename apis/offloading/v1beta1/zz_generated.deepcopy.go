@@ -45,6 +45,21 @@ func (in *Affinity) DeepCopy() *Affinity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSpreadConstraint) DeepCopyInto(out *ClusterSpreadConstraint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpreadConstraint.
+func (in *ClusterSpreadConstraint) DeepCopy() *ClusterSpreadConstraint {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSpreadConstraint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentTemplate) DeepCopyInto(out *DeploymentTemplate) {
 	*out = *in
@@ -257,6 +272,11 @@ func (in *NamespaceOffloadingList) DeepCopyObject() runtime.Object {
 func (in *NamespaceOffloadingSpec) DeepCopyInto(out *NamespaceOffloadingSpec) {
 	*out = *in
 	in.ClusterSelector.DeepCopyInto(&out.ClusterSelector)
+	if in.PlacementPolicy != nil {
+		in, out := &in.PlacementPolicy, &out.PlacementPolicy
+		*out = new(PlacementPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceOffloadingSpec.
@@ -290,6 +310,11 @@ func (in *NamespaceOffloadingStatus) DeepCopyInto(out *NamespaceOffloadingStatus
 			(*out)[key] = outVal
 		}
 	}
+	if in.SelectedClusters != nil {
+		in, out := &in.SelectedClusters, &out.SelectedClusters
+		*out = make([]corev1beta1.ClusterID, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceOffloadingStatus.
@@ -351,6 +376,33 @@ func (in *OffloadingPatch) DeepCopy() *OffloadingPatch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementPolicy) DeepCopyInto(out *PlacementPolicy) {
+	*out = *in
+	if in.PreferredClusters != nil {
+		in, out := &in.PreferredClusters, &out.PreferredClusters
+		*out = make([]v1.PreferredSchedulingTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SpreadConstraints != nil {
+		in, out := &in.SpreadConstraints, &out.SpreadConstraints
+		*out = make([]ClusterSpreadConstraint, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementPolicy.
+func (in *PlacementPolicy) DeepCopy() *PlacementPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Quota) DeepCopyInto(out *Quota) {
 	*out = *in
@@ -424,6 +476,13 @@ func (in *QuotaSpec) DeepCopyInto(out *QuotaSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.BorrowingLimit != nil {
+		in, out := &in.BorrowingLimit, &out.BorrowingLimit
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaSpec.
@@ -577,13 +636,104 @@ func (in *ShadowEndpointSliceSpec) DeepCopy() *ShadowEndpointSliceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowJob) DeepCopyInto(out *ShadowJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowJob.
+func (in *ShadowJob) DeepCopy() *ShadowJob {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ShadowJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowJobList) DeepCopyInto(out *ShadowJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ShadowJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowJobList.
+func (in *ShadowJobList) DeepCopy() *ShadowJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ShadowJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowJobSpec) DeepCopyInto(out *ShadowJobSpec) {
+	*out = *in
+	in.Job.DeepCopyInto(&out.Job)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowJobSpec.
+func (in *ShadowJobSpec) DeepCopy() *ShadowJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowJobStatus) DeepCopyInto(out *ShadowJobStatus) {
+	*out = *in
+	in.Job.DeepCopyInto(&out.Job)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowJobStatus.
+func (in *ShadowJobStatus) DeepCopy() *ShadowJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ShadowPod) DeepCopyInto(out *ShadowPod) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowPod.
@@ -636,6 +786,168 @@ func (in *ShadowPodList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowPodPolicy) DeepCopyInto(out *ShadowPodPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowPodPolicy.
+func (in *ShadowPodPolicy) DeepCopy() *ShadowPodPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowPodPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ShadowPodPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowPodPolicyList) DeepCopyInto(out *ShadowPodPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ShadowPodPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowPodPolicyList.
+func (in *ShadowPodPolicyList) DeepCopy() *ShadowPodPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowPodPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ShadowPodPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowPodPolicyOverride) DeepCopyInto(out *ShadowPodPolicyOverride) {
+	*out = *in
+	if in.ClusterIDs != nil {
+		in, out := &in.ClusterIDs, &out.ClusterIDs
+		*out = make([]corev1beta1.ClusterID, len(*in))
+		copy(*out, *in)
+	}
+	in.ShadowPodPolicyRules.DeepCopyInto(&out.ShadowPodPolicyRules)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowPodPolicyOverride.
+func (in *ShadowPodPolicyOverride) DeepCopy() *ShadowPodPolicyOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowPodPolicyOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowPodPolicyRules) DeepCopyInto(out *ShadowPodPolicyRules) {
+	*out = *in
+	if in.AllowedImageRegistries != nil {
+		in, out := &in.AllowedImageRegistries, &out.AllowedImageRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ForbiddenHostPaths != nil {
+		in, out := &in.ForbiddenHostPaths, &out.ForbiddenHostPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ForbiddenCapabilities != nil {
+		in, out := &in.ForbiddenCapabilities, &out.ForbiddenCapabilities
+		*out = make([]v1.Capability, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequireSeccompProfile != nil {
+		in, out := &in.RequireSeccompProfile, &out.RequireSeccompProfile
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxContainers != nil {
+		in, out := &in.MaxContainers, &out.MaxContainers
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AllowedVolumeTypes != nil {
+		in, out := &in.AllowedVolumeTypes, &out.AllowedVolumeTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowPodPolicyRules.
+func (in *ShadowPodPolicyRules) DeepCopy() *ShadowPodPolicyRules {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowPodPolicyRules)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowPodPolicySpec) DeepCopyInto(out *ShadowPodPolicySpec) {
+	*out = *in
+	in.ShadowPodPolicyRules.DeepCopyInto(&out.ShadowPodPolicyRules)
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]ShadowPodPolicyOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowPodPolicySpec.
+func (in *ShadowPodPolicySpec) DeepCopy() *ShadowPodPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowPodPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShadowPodPreemption) DeepCopyInto(out *ShadowPodPreemption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowPodPreemption.
+func (in *ShadowPodPreemption) DeepCopy() *ShadowPodPreemption {
+	if in == nil {
+		return nil
+	}
+	out := new(ShadowPodPreemption)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ShadowPodSpec) DeepCopyInto(out *ShadowPodSpec) {
 	*out = *in
@@ -655,6 +967,11 @@ func (in *ShadowPodSpec) DeepCopy() *ShadowPodSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ShadowPodStatus) DeepCopyInto(out *ShadowPodStatus) {
 	*out = *in
+	if in.Preemption != nil {
+		in, out := &in.Preemption, &out.Preemption
+		*out = new(ShadowPodPreemption)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShadowPodStatus.
@@ -981,3 +1298,118 @@ func (in *VkOptionsTemplateSpec) DeepCopy() *VkOptionsTemplateSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadMigration) DeepCopyInto(out *WorkloadMigration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadMigration.
+func (in *WorkloadMigration) DeepCopy() *WorkloadMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkloadMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadMigrationList) DeepCopyInto(out *WorkloadMigrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkloadMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadMigrationList.
+func (in *WorkloadMigrationList) DeepCopy() *WorkloadMigrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadMigrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkloadMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadMigrationSpec) DeepCopyInto(out *WorkloadMigrationSpec) {
+	*out = *in
+	out.WorkloadRef = in.WorkloadRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadMigrationSpec.
+func (in *WorkloadMigrationSpec) DeepCopy() *WorkloadMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadMigrationStatus) DeepCopyInto(out *WorkloadMigrationStatus) {
+	*out = *in
+	if in.OriginalReplicas != nil {
+		in, out := &in.OriginalReplicas, &out.OriginalReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MigratedVolumes != nil {
+		in, out := &in.MigratedVolumes, &out.MigratedVolumes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadMigrationStatus.
+func (in *WorkloadMigrationStatus) DeepCopy() *WorkloadMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadReference) DeepCopyInto(out *WorkloadReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadReference.
+func (in *WorkloadReference) DeepCopy() *WorkloadReference {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadReference)
+	in.DeepCopyInto(out)
+	return out
+}
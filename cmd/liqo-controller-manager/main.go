@@ -17,6 +17,7 @@ package main
 
 import (
 	"os"
+	"strings"
 	"time"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
@@ -49,9 +50,11 @@ import (
 	identitymanager "github.com/liqotech/liqo/pkg/identityManager"
 	"github.com/liqotech/liqo/pkg/ipam"
 	remoteresourceslicecontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication/remoteresourceslice-controller"
+	resourcesliceclass "github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication/resourceslice-class"
 	foreignclustercontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/core/foreigncluster-controller"
 	ipmapping "github.com/liqotech/liqo/pkg/liqo-controller-manager/ipmapping"
 	quotacreatorcontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/quotacreator-controller"
+	tenantgccontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/tenantgc-controller"
 	virtualnodecreatorcontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/virtualnodecreator-controller"
 	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
 	argsutils "github.com/liqotech/liqo/pkg/utils/args"
@@ -87,10 +90,14 @@ func main() {
 	var ingressClasses argsutils.ClassNameList
 	var loadBalancerClasses argsutils.ClassNameList
 	var defaultNodeResources argsutils.ResourceMap
+	var resourceSliceClassEngines argsutils.StringMap
+	var resourceSliceTenantCap argsutils.ResourceMap
 	var gatewayServerResources argsutils.StringList
 	var gatewayClientResources argsutils.StringList
 	var globalLabels argsutils.StringMap
 	var globalAnnotations argsutils.StringMap
+	var namespaceDefaultRequests argsutils.ResourceMap
+	var namespaceDefaultLimits argsutils.ResourceMap
 	var apiServerAddressOverride string
 	var caOverride string
 	var trustedCA bool
@@ -148,12 +155,40 @@ func main() {
 	pflag.StringVar(&awsConfig.AwsSecretAccessKey, "aws-secret-access-key", "", "AWS IAM SecretAccessKey for the Liqo User")
 	pflag.StringVar(&awsConfig.AwsRegion, "aws-region", "", "AWS region where the local cluster is running")
 	pflag.StringVar(&awsConfig.AwsClusterName, "aws-cluster-name", "", "Name of the local EKS cluster")
+	// Token identity configurations
+	tokenIdentityEnabled := pflag.Bool("token-identity-enabled", false,
+		"Issue short-lived bearer tokens instead of certificates to the consumer clusters")
+	tokenIdentityExpiration := pflag.Duration("token-identity-expiration", time.Hour,
+		"The lifetime of the bearer tokens issued to the consumer clusters")
+	// Identity policy configurations
+	var identityPolicy identitymanager.IdentityPolicy
+	pflag.DurationVar(&identityPolicy.ControlPlaneMaxLifetime, "identity-control-plane-max-lifetime", 0,
+		"The maximum lifetime of the control plane identities issued to the consumer clusters (0 means no limit)")
+	pflag.DurationVar(&identityPolicy.ResourceSliceMaxLifetime, "identity-resource-slice-max-lifetime", 0,
+		"The maximum lifetime of the ResourceSlice identities issued to the consumer clusters (0 means no limit)")
+	pflag.DurationVar(&identityPolicy.RenewBefore, "identity-renew-before", 0,
+		"How long before their expiration the identities issued to the consumer clusters are renewed (0 means at 2/3 of their lifetime)")
+	pflag.BoolVar(&identityPolicy.RotateOnTenantUpdate, "identity-rotate-on-tenant-update", false,
+		"Rotate the identities issued to a consumer cluster whenever its Tenant is updated")
 	// Resource sharing parameters
 	pflag.Var(&clusterLabels, consts.ClusterLabelsParameter,
 		"The set of labels which characterizes the local cluster when exposed remotely as a virtual node")
 	pflag.Var(&ingressClasses, "ingress-classes", "List of ingress classes offered by the cluster. Example: \"nginx;default,traefik\"")
 	pflag.Var(&loadBalancerClasses, "load-balancer-classes", "List of load balancer classes offered by the cluster. Example:\"metallb;default\"")
 	pflag.Var(&defaultNodeResources, "default-node-resources", "Default resources assigned to the Virtual Node Pod")
+	pflag.Var(&resourceSliceClassEngines, "resource-slice-class-engines",
+		"The engines deciding the resources granted to the ResourceSlices of each class, in the form <class>=<engine>. "+
+			"Supported engines: "+strings.Join(resourcesliceclass.EngineNames(), ", ")+" (default: default=fixed)")
+	resourceSliceFreeCapacityPercentage := pflag.Int64("resource-slice-free-capacity-percentage", 50,
+		"The percentage of the free capacity of the cluster granted to each ResourceSlice by the free-capacity and counter-offer engines")
+	pflag.Var(&resourceSliceTenantCap, "resource-slice-tenant-cap",
+		"The maximum resources granted to each consumer cluster by the tenant-cap and counter-offer engines (e.g., cpu=4,memory=8Gi)")
+	resourceSliceAutoscalerSyncPeriod := pflag.Duration("resource-slice-autoscaler-sync-period", 30*time.Second,
+		"The period after which the ResourceSliceAutoscalers re-evaluate the demand of the local cluster")
+	resourceSliceLeaseDuration := pflag.Duration("resource-slice-lease-duration", 0,
+		"The default (and maximum) duration of the lease of the resources granted through each ResourceSlice (0 means not time-bounded)")
+	resourceSliceLeaseWarningPeriod := pflag.Duration("resource-slice-lease-warning-period", 24*time.Hour,
+		"The period before the expiration of a lease during which the consumer is warned to renew it")
 	pflag.Var(&globalLabels, "global-labels",
 		"The set of labels that will be added to all resources created by Liqo controllers")
 	pflag.Var(&globalAnnotations, "global-annotations",
@@ -167,13 +202,37 @@ func main() {
 	storageNamespace := pflag.String("storage-namespace", "liqo-storage", "Namespace where the liqo storage-related resources are stored")
 	// Service continuity
 	enableNodeFailureController := pflag.Bool("enable-node-failure-controller", false, "Enable the node failure controller")
+	enableFailoverController := pflag.Bool("enable-failover-controller", false,
+		"Enable the failover of the workloads hosted by virtual nodes whose provider cluster is unavailable")
+	failoverGracePeriod := pflag.Duration("failover-grace-period", 5*time.Minute,
+		"The amount of time a virtual node shall be NotReady before triggering the failover of the hosted workloads")
+	// Namespace isolation policies
+	namespacePodSecurityLevel := pflag.String("namespace-pod-security-level", "",
+		"The Pod Security Admission level enforced in the namespaces hosting the offloaded workloads. "+
+			"Supported levels: "+strings.Join(tenantnamespace.PodSecurityLevels, ", ")+" (default: not enforced)")
+	pflag.Var(&namespaceDefaultRequests, "namespace-default-requests",
+		"The default resource requests of the containers offloaded by the consumer clusters (e.g., cpu=100m,memory=128Mi)")
+	pflag.Var(&namespaceDefaultLimits, "namespace-default-limits",
+		"The default resource limits of the containers offloaded by the consumer clusters (e.g., cpu=500m,memory=512Mi)")
+	namespaceDefaultDenyNetworkPolicy := pflag.Bool("namespace-default-deny-network-policy", false,
+		"Deny the ingress traffic towards the offloaded workloads not originated by the same consumer cluster")
+	namespaceRuntimeClass := pflag.String("namespace-runtime-class", "",
+		"The runtime class enforced on the workloads offloaded by the consumer clusters (default: not enforced)")
 	// Controllers workers
 	shadowPodWorkers := pflag.Int("shadow-pod-ctrl-workers", 10, "The number of workers used to reconcile ShadowPod resources.")
+	shadowJobWorkers := pflag.Int("shadow-job-ctrl-workers", 10, "The number of workers used to reconcile ShadowJob resources.")
 	shadowEndpointSliceWorkers := pflag.Int("shadow-endpointslice-ctrl-workers", 10,
 		"The number of workers used to reconcile ShadowEndpointSlice resources.")
 
 	// CROSS MODULE
 	enableAPIServerIPRemapping := pflag.Bool("enable-api-server-ip-remapping", true, "Enable the API server IP remapping")
+	// Tenant garbage collection
+	tenantGCQuarantineTTL := pflag.Duration("tenant-gc-quarantine-ttl", 0,
+		"The period the consumer clusters shall be unreachable for, before their tenants are quarantined (0 disables the tenant garbage collection)")
+	tenantGCDeletionTTL := pflag.Duration("tenant-gc-deletion-ttl", 24*time.Hour,
+		"The period after which the remote namespaces of the quarantined tenants are deleted")
+	tenantGCDryRun := pflag.Bool("tenant-gc-dry-run", false,
+		"Only report the remote namespaces of the quarantined tenants that would be deleted")
 
 	liqoerrors.InitFlags(nil)
 	restcfg.InitFlags(nil)
@@ -255,7 +314,19 @@ func main() {
 		os.Exit(1)
 	}
 
-	namespaceManager := tenantnamespace.NewCachedManager(ctx, clientset, scheme)
+	namespacePolicies := &tenantnamespace.NamespacePolicies{
+		PodSecurityLevel:         *namespacePodSecurityLevel,
+		DefaultRequests:          namespaceDefaultRequests.ToResourceList(),
+		DefaultLimits:            namespaceDefaultLimits.ToResourceList(),
+		DefaultDenyNetworkPolicy: *namespaceDefaultDenyNetworkPolicy,
+		RuntimeClassName:         *namespaceRuntimeClass,
+	}
+	if err := namespacePolicies.Validate(); err != nil {
+		klog.Errorf("Invalid namespace policies: %v", err)
+		os.Exit(1)
+	}
+
+	namespaceManager := tenantnamespace.NewCachedManager(ctx, clientset, scheme, tenantnamespace.WithNamespacePolicies(namespacePolicies))
 
 	// Setup operators for each module:
 
@@ -308,14 +379,38 @@ func main() {
 
 	// AUTHENTICATION MODULE
 	if *authenticationEnabled {
+		for _, lifetime := range []time.Duration{identityPolicy.ControlPlaneMaxLifetime, identityPolicy.ResourceSliceMaxLifetime} {
+			// Kubernetes does not honor the requested duration of certificates shorter than 10 minutes.
+			if lifetime < 0 || (lifetime > 0 && lifetime < 10*time.Minute) {
+				klog.Fatalf("Invalid maximum identity lifetime %v: it must be either 0 or at least 10 minutes", lifetime)
+			}
+		}
+
 		var idProvider identitymanager.IdentityProvider
-		if awsConfig.IsEmpty() {
-			idProvider = identitymanager.NewCertificateIdentityProvider(ctx,
-				mgr.GetClient(), clientset, config, clusterID, namespaceManager)
-		} else {
+		switch {
+		case !awsConfig.IsEmpty():
 			idProvider = identitymanager.NewIAMIdentityProvider(ctx,
 				mgr.GetClient(), clientset, clusterID, &awsConfig, namespaceManager)
+		case *tokenIdentityEnabled:
+			idProvider = identitymanager.NewTokenIdentityProvider(ctx,
+				mgr.GetClient(), clientset, config, clusterID, namespaceManager,
+				identitymanager.NewServiceAccountTokenIssuer(mgr.GetClient(), clientset), *tokenIdentityExpiration, &identityPolicy)
+		default:
+			idProvider = identitymanager.NewCertificateIdentityProvider(ctx,
+				mgr.GetClient(), clientset, config, clusterID, namespaceManager, &identityPolicy)
+		}
+		if *resourceSliceFreeCapacityPercentage <= 0 || *resourceSliceFreeCapacityPercentage > 100 {
+			klog.Fatalf("Invalid ResourceSlice free capacity percentage %d: it must be between 1 and 100", *resourceSliceFreeCapacityPercentage)
 		}
+		resourceSliceClasses, err := resourcesliceclass.NewRegistryFromConfig(resourceSliceClassEngines.StringMap, mgr.GetClient(),
+			&resourcesliceclass.Options{
+				FreeCapacityPercentage: *resourceSliceFreeCapacityPercentage,
+				TenantCap:              resourceSliceTenantCap.ToResourceList(),
+			})
+		if err != nil {
+			klog.Fatalf("Unable to configure the ResourceSlice class engines: %v", err)
+		}
+
 		opts := &modules.AuthOption{
 			IdentityProvider:         idProvider,
 			NamespaceManager:         namespaceManager,
@@ -331,7 +426,11 @@ func main() {
 				LoadBalancerClasses:       loadBalancerClasses,
 				ClusterLabels:             clusterLabels.StringMap,
 				DefaultResourceQuantity:   defaultNodeResources.ToResourceList(),
+				LeaseDuration:             *resourceSliceLeaseDuration,
 			},
+			ResourceSliceClasses:              resourceSliceClasses,
+			ResourceSliceAutoscalerSyncPeriod: *resourceSliceAutoscalerSyncPeriod,
+			ResourceSliceLeaseWarningPeriod:   *resourceSliceLeaseWarningPeriod,
 		}
 
 		if err := modules.SetupAuthenticationModule(ctx, mgr, uncachedClient, opts); err != nil {
@@ -351,9 +450,13 @@ func main() {
 			RealStorageClassName:        *realStorageClassName,
 			StorageNamespace:            *storageNamespace,
 			EnableNodeFailureController: *enableNodeFailureController,
+			EnableFailoverController:    *enableFailoverController,
+			FailoverGracePeriod:         *failoverGracePeriod,
 			ShadowPodWorkers:            *shadowPodWorkers,
+			ShadowJobWorkers:            *shadowJobWorkers,
 			ShadowEndpointSliceWorkers:  *shadowEndpointSliceWorkers,
 			ResyncPeriod:                *resyncPeriod,
+			NamespacePolicies:           namespacePolicies,
 		}
 
 		if err := modules.SetupOffloadingModule(ctx, mgr, opts); err != nil {
@@ -382,6 +485,17 @@ func main() {
 			klog.Errorf("Unable to setup the quotacreator reconciler: %v", err)
 			os.Exit(1)
 		}
+
+		// Configure controller that garbage collects the resources of unreachable consumer clusters.
+		if *tenantGCQuarantineTTL > 0 {
+			tenantGCReconciler := tenantgccontroller.NewTenantGCReconciler(
+				mgr.GetClient(), mgr.GetAPIReader(), mgr.GetScheme(), mgr.GetEventRecorderFor("tenantgc-controller"),
+				*tenantGCQuarantineTTL, *tenantGCDeletionTTL, *tenantGCDryRun)
+			if err := tenantGCReconciler.SetupWithManager(mgr); err != nil {
+				klog.Errorf("Unable to setup the tenantgc reconciler: %v", err)
+				os.Exit(1)
+			}
+		}
 	}
 
 	// OFFLOADING MODULE & NETWORKING MODULE
@@ -17,6 +17,7 @@ package modules
 import (
 	"context"
 	"encoding/base64"
+	"time"
 
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -27,12 +28,15 @@ import (
 	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
 	identitycontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication/identity-controller"
 	identitycreatorcontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication/identitycreator-controller"
+	leasecontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication/lease-controller"
 	localrenwercontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication/localrenwer-controller"
 	localresourceslicecontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication/localresourceslice-controller"
 	noncecreatorcontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication/noncecreator-controller"
 	noncesigner "github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication/noncesigner-controller"
 	remoterenwercontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication/remoterenwer-controller"
 	remoteresourceslicecontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication/remoteresourceslice-controller"
+	resourcesliceclass "github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication/resourceslice-class"
+	resourcesliceautoscalercontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication/resourcesliceautoscaler-controller"
 	tenantcontroller "github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication/tenant-controller"
 	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
 )
@@ -47,6 +51,10 @@ type AuthOption struct {
 	CAOverrideB64            string
 	TrustedCA                bool
 	SliceStatusOptions       *remoteresourceslicecontroller.SliceStatusOptions
+	ResourceSliceClasses     *resourcesliceclass.Registry
+
+	ResourceSliceAutoscalerSyncPeriod time.Duration
+	ResourceSliceLeaseWarningPeriod   time.Duration
 }
 
 // SetupAuthenticationModule setup the authentication module and initializes its controllers .
@@ -114,17 +122,48 @@ func SetupAuthenticationModule(ctx context.Context, mgr manager.Manager, uncache
 		return err
 	}
 
+	// Configure controller that resizes the local resource slices according to the demand.
+	resourceSliceAutoscalerReconciler := resourcesliceautoscalercontroller.NewResourceSliceAutoscalerReconciler(mgr.GetClient(),
+		mgr.GetScheme(), mgr.GetEventRecorderFor("resourcesliceautoscaler-controller"),
+		opts.ResourceSliceAutoscalerSyncPeriod)
+	if err := resourceSliceAutoscalerReconciler.SetupWithManager(mgr); err != nil {
+		klog.Errorf("Unable to setup the resource slice autoscaler reconciler: %v", err)
+		return err
+	}
+
 	// Configure controller that fills the remote resource slice status.
 	remoteResourceSliceReconciler := remoteresourceslicecontroller.NewRemoteResourceSliceReconciler(mgr.GetClient(),
 		mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor("remoteresourceslice-controller"),
 		opts.IdentityProvider, opts.NamespaceManager,
 		opts.APIServerAddressOverride, caOverride, opts.TrustedCA,
-		opts.SliceStatusOptions)
+		opts.SliceStatusOptions, opts.ResourceSliceClasses)
 	if err := remoteResourceSliceReconciler.SetupWithManager(mgr); err != nil {
 		klog.Errorf("Unable to setup the remote resource slice reconciler: %v", err)
 		return err
 	}
 
+	// Configure controllers that enforce the leases of the resource slices and of the tenants.
+	localLeaseReconciler := leasecontroller.NewLocalLeaseReconciler(mgr.GetClient(), mgr.GetScheme(),
+		mgr.GetEventRecorderFor("local-lease-controller"))
+	if err := localLeaseReconciler.SetupWithManager(mgr); err != nil {
+		klog.Errorf("Unable to setup the local lease reconciler: %v", err)
+		return err
+	}
+
+	remoteLeaseReconciler := leasecontroller.NewRemoteLeaseReconciler(mgr.GetClient(), mgr.GetScheme(),
+		mgr.GetEventRecorderFor("remote-lease-controller"), opts.ResourceSliceLeaseWarningPeriod)
+	if err := remoteLeaseReconciler.SetupWithManager(mgr); err != nil {
+		klog.Errorf("Unable to setup the remote lease reconciler: %v", err)
+		return err
+	}
+
+	tenantLeaseReconciler := leasecontroller.NewTenantLeaseReconciler(mgr.GetClient(), mgr.GetScheme(),
+		mgr.GetEventRecorderFor("tenant-lease-controller"), opts.ResourceSliceLeaseWarningPeriod)
+	if err := tenantLeaseReconciler.SetupWithManager(mgr); err != nil {
+		klog.Errorf("Unable to setup the tenant lease reconciler: %v", err)
+		return err
+	}
+
 	// Configure controller that creates identity resources from resourceslices
 	identityCreatorReconciler := identitycreatorcontroller.NewIdentityCreatorReconciler(
 		mgr.GetClient(), mgr.GetScheme(), mgr.GetEventRecorderFor("identitycreator-controller"),
@@ -145,7 +184,7 @@ func SetupAuthenticationModule(ctx context.Context, mgr manager.Manager, uncache
 
 	remoteRenewerReconciler := remoterenwercontroller.NewRemoteRenewerReconciler(mgr.GetClient(), mgr.GetScheme(),
 		opts.IdentityProvider, opts.NamespaceManager,
-		opts.APIServerAddressOverride, caOverride, opts.TrustedCA,
+		opts.APIServerAddressOverride, caOverride, opts.TrustedCA, opts.SliceStatusOptions.LeaseDuration,
 		mgr.GetEventRecorderFor("remote-renewer-controller"))
 	if err := remoteRenewerReconciler.SetupWithManager(mgr); err != nil {
 		klog.Errorf("Unable to setup the remote renewer reconciler: %v", err)
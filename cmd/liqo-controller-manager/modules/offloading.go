@@ -30,11 +30,16 @@ import (
 
 	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	"github.com/liqotech/liqo/pkg/consts"
+	dsoffloadingctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/daemonsetoffloading-controller"
+	failoverctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/failover-controller"
 	mapsctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/namespacemap-controller"
 	nsoffctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/namespaceoffloading-controller"
 	nodefailurectrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/nodefailure-controller"
+	podgroupctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/podgroup-controller"
 	podstatusctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/podstatus-controller"
 	shadowepsctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/shadowendpointslice-controller"
+	shadowjobctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/shadowjob-controller"
+	shadowjobschedulerctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/shadowjobscheduler-controller"
 	shadowpodctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/shadowpod-controller"
 	liqostorageprovisioner "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/storageprovisioner"
 	virtualnodectrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/virtualnode-controller"
@@ -52,9 +57,13 @@ type OffloadingOption struct {
 	RealStorageClassName        string
 	StorageNamespace            string
 	EnableNodeFailureController bool
+	EnableFailoverController    bool
+	FailoverGracePeriod         time.Duration
 	ShadowPodWorkers            int
+	ShadowJobWorkers            int
 	ShadowEndpointSliceWorkers  int
 	ResyncPeriod                time.Duration
+	NamespacePolicies           *tenantnamespace.NamespacePolicies
 }
 
 // SetupOffloadingModule setup the offloading module and initializes its controllers.
@@ -77,7 +86,8 @@ func SetupOffloadingModule(ctx context.Context, mgr manager.Manager, opts *Offlo
 	}
 
 	namespaceMapReconciler := &mapsctrl.NamespaceMapReconciler{
-		Client: mgr.GetClient(),
+		Client:   mgr.GetClient(),
+		Policies: opts.NamespacePolicies,
 	}
 	if err = namespaceMapReconciler.SetupWithManager(mgr); err != nil {
 		klog.Errorf("Unable to setup the namespacemap reconciler: %v", err)
@@ -103,6 +113,24 @@ func SetupOffloadingModule(ctx context.Context, mgr manager.Manager, opts *Offlo
 		return err
 	}
 
+	shadowJobReconciler := &shadowjobctrl.Reconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+	if err = shadowJobReconciler.SetupWithManager(mgr, opts.ShadowJobWorkers); err != nil {
+		klog.Errorf("Unable to setup the shadowjob reconciler: %v", err)
+		return err
+	}
+
+	shadowJobSchedulerReconciler := &shadowjobschedulerctrl.JobSchedulerReconciler{
+		Client:   mgr.GetClient(),
+		Recorder: mgr.GetEventRecorderFor("shadowjob-scheduler-controller"),
+	}
+	if err = shadowJobSchedulerReconciler.SetupWithManager(mgr); err != nil {
+		klog.Errorf("Unable to setup the shadowjob scheduler reconciler: %v", err)
+		return err
+	}
+
 	shadowEpsReconciler := &shadowepsctrl.Reconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
@@ -112,6 +140,24 @@ func SetupOffloadingModule(ctx context.Context, mgr manager.Manager, opts *Offlo
 		return err
 	}
 
+	dsOffloadingReconciler := &dsoffloadingctrl.DaemonSetOffloadingReconciler{
+		Client:   mgr.GetClient(),
+		Recorder: mgr.GetEventRecorderFor("daemonset-offloading-controller"),
+	}
+	if err = dsOffloadingReconciler.SetupWithManager(mgr); err != nil {
+		klog.Errorf("Unable to setup the daemonset offloading reconciler: %v", err)
+		return err
+	}
+
+	podGroupReconciler := &podgroupctrl.PodGroupReconciler{
+		Client:   mgr.GetClient(),
+		Recorder: mgr.GetEventRecorderFor("pod-group-controller"),
+	}
+	if err = podGroupReconciler.SetupWithManager(mgr); err != nil {
+		klog.Errorf("Unable to setup the pod group reconciler: %v", err)
+		return err
+	}
+
 	if opts.EnableStorage {
 		liqoProvisioner, err := liqostorageprovisioner.NewLiqoLocalStorageProvisioner(ctx, mgr.GetClient(),
 			opts.VirtualStorageClassName, opts.StorageNamespace, opts.RealStorageClassName)
@@ -158,5 +204,17 @@ func SetupOffloadingModule(ctx context.Context, mgr manager.Manager, opts *Offlo
 		}
 	}
 
+	if opts.EnableFailoverController {
+		failoverReconciler := &failoverctrl.FailoverReconciler{
+			Client:      mgr.GetClient(),
+			Recorder:    mgr.GetEventRecorderFor("failover-controller"),
+			GracePeriod: opts.FailoverGracePeriod,
+		}
+		if err = failoverReconciler.SetupWithManager(mgr); err != nil {
+			klog.Errorf("Unable to setup the failover reconciler: %v", err)
+			return err
+		}
+	}
+
 	return nil
 }
@@ -33,8 +33,26 @@ to obtain slices of resources from. At the end of the process, the consumer clus
 be able to replicate ResourceSlices resources to the provider cluster, and to receive
 an associated Identity to consume the provided resources.
 
+When the two clusters cannot be reached from the same host, the authentication can be
+performed out-of-band, exchanging signed (and optionally encrypted) bundles as files.
+Each step involves only the local cluster:
+  1. the provider exports a challenge for the consumer cluster (--export-challenge);
+  2. the consumer imports the challenge and exports a request (--import-challenge, --export-request);
+  3. the provider imports the request and exports a response (--import-request, --export-response);
+  4. the consumer imports the response (--import-response).
+Bundles are signed with the key of the cluster generating them: the fingerprint printed
+on export must be compared, through a trusted channel, with the one of the imported challenge
+and request, either interactively or through --expected-fingerprint. The key which signed
+the challenge is pinned, and the response is accepted only if signed with the same key.
+
 Examples:
   $ {{ .Executable }} authenticate --remote-kubeconfig <provider>
+
+or, out-of-band:
+  $ {{ .Executable }} authenticate --export-challenge challenge.json --remote-cluster-id <consumer-id> # provider
+  $ {{ .Executable }} authenticate --import-challenge challenge.json --export-request request.json    # consumer
+  $ {{ .Executable }} authenticate --import-request request.json --export-response response.json      # provider
+  $ {{ .Executable }} authenticate --import-response response.json                                   # consumer
 `
 
 // newAuthenticateCommand represents the authenticate command.
@@ -50,6 +68,12 @@ func newAuthenticateCommand(ctx context.Context, f *factory.Factory) *cobra.Comm
 		Args:    cobra.NoArgs,
 
 		PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+			if options.OutOfBand() {
+				// The out-of-band authentication involves only the local cluster.
+				singleClusterPersistentPreRun(cmd, options.LocalFactory)
+				output.ExitOnErr(options.ValidateOutOfBand())
+				return
+			}
 			twoClustersPersistentPreRun(cmd, options.LocalFactory, options.RemoteFactory, factory.WithScopedPrinter)
 		},
 
@@ -74,5 +98,28 @@ func newAuthenticateCommand(ctx context.Context, f *factory.Factory) *cobra.Comm
 	cmd.Flags().BoolVar(&options.InBand, "in-band", false, "Use in-band authentication. Use it only if required and if you know what you are doing")
 	cmd.Flags().StringVar(&options.ProxyURL, "proxy-url", "", "The URL of the proxy to use for the communication with the remote cluster")
 
+	cmd.Flags().StringVar(&options.ExportChallenge, "export-challenge", "",
+		"Out-of-band authentication (provider): the file where to export the challenge for the consumer cluster")
+	cmd.Flags().Var(&options.RemoteClusterID, "remote-cluster-id",
+		"Out-of-band authentication (provider): the cluster ID of the consumer cluster the challenge is exported for")
+	cmd.Flags().StringVar(&options.ImportChallenge, "import-challenge", "",
+		"Out-of-band authentication (consumer): the file containing the challenge exported by the provider cluster")
+	cmd.Flags().StringVar(&options.ExportRequest, "export-request", "",
+		"Out-of-band authentication (consumer): the file where to export the authentication request")
+	cmd.Flags().StringVar(&options.ImportRequest, "import-request", "",
+		"Out-of-band authentication (provider): the file containing the request exported by the consumer cluster")
+	cmd.Flags().StringVar(&options.ExportResponse, "export-response", "",
+		"Out-of-band authentication (provider): the file where to export the authentication response")
+	cmd.Flags().StringVar(&options.ImportResponse, "import-response", "",
+		"Out-of-band authentication (consumer): the file containing the response exported by the provider cluster")
+	cmd.Flags().StringVar(&options.PassphraseFile, "bundle-passphrase-file", "",
+		"Out-of-band authentication: the file containing the passphrase to encrypt and decrypt the bundles (optional)")
+	cmd.Flags().StringVar(&options.ExpectedFingerprint, "expected-fingerprint", "",
+		"Out-of-band authentication: the fingerprint the imported challenge or request is required to match, as printed by the remote cluster. "+
+			"If not set, the fingerprint must be confirmed interactively")
+
+	options.LocalFactory.Printer.CheckErr(cmd.RegisterFlagCompletionFunc("remote-cluster-id",
+		completion.ClusterIDs(ctx, options.LocalFactory, completion.NoLimit)))
+
 	return cmd
 }
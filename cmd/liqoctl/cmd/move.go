@@ -16,6 +16,8 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -38,9 +40,14 @@ constraints are automatically embedded in the resulting PV, to enforce each pod
 to be scheduled on the cluster where the associated storage pools are available.
 
 This command allows to *move* a volume created in a given cluster to a different
-cluster, ensuring mounting pods will then be attracted in that location. This
-process leverages Restic to backup the source data and restore it into a volume
-in the target cluster.
+cluster, ensuring mounting pods will then be attracted in that location. By
+default, this process leverages Restic to backup the source data and restore it
+into a volume in the target cluster. Alternative data movers can be selected
+through the --data-mover flag:
+* restic-s3: Restic, with the repository backed by an S3-compatible object storage.
+* rsync: direct copy of the data across the cross-cluster network fabric.
+* csi: CSI VolumeSnapshots and clones, only between nodes of the local cluster
+  served by the same CSI backend (it cannot move volumes across clusters).
 
 Warning: only PVCs not currently mounted by any pod can
 be moved to a different cluster.
@@ -50,6 +57,33 @@ Examples:
 or
   $ {{ .Executable }} move volume database01 --namespace foo --target-node liqo-neutral-colt
       --containers-cpu-limits 1000m --containers-ram-limits 2Gi
+or
+  $ {{ .Executable }} move volume database01 --namespace foo --target-node liqo-neutral-colt
+      --data-mover restic-s3 --s3-repository s3:https://minio.example.com/liqo
+      --s3-access-key-id ACCESS_KEY_ID --s3-secret-access-key SECRET_ACCESS_KEY
+`
+
+const liqoctlMoveWorkloadLongHelp = `Move a stateful workload, along with its volumes, to a different node (i.e., cluster).
+
+This command orchestrates the migration of a Deployment or a StatefulSet, whose
+pods mount Liqo-managed PVCs, to a different cluster. In detail, it scales the
+workload down, moves all its volumes leveraging Restic (as done by the move
+volume command), constrains the pods to the target node and finally scales the
+workload back up. In case of failure, the workload is restored in its original
+location.
+
+The progress of the migration is recorded in a WorkloadMigration resource,
+created in the namespace of the workload. The resource is informative only:
+the migration is driven by this command, and creating a WorkloadMigration
+manually has no effect.
+
+Warning: the workload is unavailable during the migration, as container
+checkpointing is not supported.
+
+Examples:
+  $ {{ .Executable }} move workload statefulset database --namespace foo --target-node liqo-neutral-colt
+or
+  $ {{ .Executable }} move workload deployment webserver --namespace foo --target-node worker-023 --timeout 20m
 `
 
 // moveCmd represents the move command.
@@ -62,6 +96,7 @@ func newMoveCommand(ctx context.Context, f *factory.Factory) *cobra.Command {
 	}
 
 	liqoctlutils.AddCommand(cmd, newMoveVolumeCommand(ctx, f))
+	liqoctlutils.AddCommand(cmd, newMoveWorkloadCommand(ctx, f))
 	return cmd
 }
 
@@ -69,6 +104,7 @@ func newMoveVolumeCommand(ctx context.Context, f *factory.Factory) *cobra.Comman
 	options := &move.Options{Factory: f, ResticPassword: utils.RandomString(16)}
 	var containersCPURequests, containersCPULimits args.Quantity
 	var containersRAMRequests, containersRAMLimits args.Quantity
+	var completeDataMoverFlags func()
 
 	var cmd = &cobra.Command{
 		Use:     "volume",
@@ -84,6 +120,7 @@ func newMoveVolumeCommand(ctx context.Context, f *factory.Factory) *cobra.Comman
 			options.ContainersCPULimits = containersCPULimits.Quantity
 			options.ContainersRAMRequests = containersRAMRequests.Quantity
 			options.ContainersRAMLimits = containersRAMLimits.Quantity
+			completeDataMoverFlags()
 		},
 
 		Run: func(_ *cobra.Command, args []string) {
@@ -106,9 +143,88 @@ func newMoveVolumeCommand(ctx context.Context, f *factory.Factory) *cobra.Comman
 		"The Restic server image to use")
 	cmd.Flags().StringVar(&options.ResticImage, "restic-image", move.DefaultResticImage,
 		"The Restic image to use")
+	completeDataMoverFlags = addDataMoverFlags(f, cmd, options)
 
 	f.Printer.CheckErr(cmd.MarkFlagRequired("target-node"))
 	f.Printer.CheckErr(cmd.RegisterFlagCompletionFunc("target-node", completion.Nodes(ctx, f, completion.NoLimit)))
 
 	return cmd
 }
+
+func newMoveWorkloadCommand(ctx context.Context, f *factory.Factory) *cobra.Command {
+	options := &move.WorkloadOptions{Options: move.Options{Factory: f, ResticPassword: utils.RandomString(16)}}
+	var containersCPURequests, containersCPULimits args.Quantity
+	var containersRAMRequests, containersRAMLimits args.Quantity
+	var completeDataMoverFlags func()
+
+	var cmd = &cobra.Command{
+		Use:   "workload KIND NAME",
+		Short: "Move a stateful workload, along with its volumes, to a different node (i.e., cluster)",
+		Long:  liqoctlMoveWorkloadLongHelp,
+
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completion.Enumeration([]string{"deployment", "statefulset"}),
+
+		PreRun: func(_ *cobra.Command, _ []string) {
+			options.ContainersCPURequests = containersCPURequests.Quantity
+			options.ContainersCPULimits = containersCPULimits.Quantity
+			options.ContainersRAMRequests = containersRAMRequests.Quantity
+			options.ContainersRAMLimits = containersRAMLimits.Quantity
+			completeDataMoverFlags()
+		},
+
+		Run: func(_ *cobra.Command, args []string) {
+			kind, err := move.ParseWorkloadKind(args[0])
+			output.ExitOnErr(err)
+			options.WorkloadKind = kind
+			options.WorkloadName = args[1]
+			output.ExitOnErr(options.Run(ctx))
+		},
+	}
+
+	// The completion of the namespace flag is already registered by the move volume command, as the flag is shared.
+	f.AddNamespaceFlag(cmd.Flags())
+
+	cmd.Flags().StringVar(&options.TargetNode, "target-node", "",
+		"The target node (either physical or virtual) the workload will be moved to")
+	cmd.Flags().DurationVar(&options.Timeout, "timeout", 10*time.Minute,
+		"The timeout for the workload to be scaled down and up")
+
+	cmd.Flags().Var(&containersCPURequests, "containers-cpu-requests", "The CPU requests for the Restic containers")
+	cmd.Flags().Var(&containersCPULimits, "containers-cpu-limits", "The CPU limits for the Restic containers")
+	cmd.Flags().Var(&containersRAMRequests, "containers-ram-requests", "The RAM requests for the Restic containers")
+	cmd.Flags().Var(&containersRAMLimits, "containers-ram-limits", "The RAM limits for the Restic containers")
+	cmd.Flags().StringVar(&options.ResticServerImage, "restic-server-image", move.DefaultResticServerImage,
+		"The Restic server image to use")
+	cmd.Flags().StringVar(&options.ResticImage, "restic-image", move.DefaultResticImage,
+		"The Restic image to use")
+	completeDataMoverFlags = addDataMoverFlags(f, cmd, &options.Options)
+
+	f.Printer.CheckErr(cmd.MarkFlagRequired("target-node"))
+	f.Printer.CheckErr(cmd.RegisterFlagCompletionFunc("target-node", completion.Nodes(ctx, f, completion.NoLimit)))
+
+	return cmd
+}
+
+// addDataMoverFlags registers the flags to configure the data mover, and returns the function to be invoked
+// to finalize their parsing.
+func addDataMoverFlags(f *factory.Factory, cmd *cobra.Command, options *move.Options) func() {
+	dataMover := args.NewEnum(move.DataMoverTypes, string(move.ResticDataMover))
+
+	cmd.Flags().Var(dataMover, "data-mover", fmt.Sprintf("The mechanism leveraged to move the data of the volumes. Allowed values: %v",
+		move.DataMoverTypes))
+	cmd.Flags().StringVar(&options.S3Repository, "s3-repository", "",
+		"The S3 bucket (and optional prefix) hosting the Restic repository, e.g., s3:https://minio.example.com/liqo (restic-s3 data mover only)")
+	cmd.Flags().StringVar(&options.S3AccessKeyID, "s3-access-key-id", "",
+		"The access key ID to access the S3 repository (restic-s3 data mover only)")
+	cmd.Flags().StringVar(&options.S3SecretAccessKey, "s3-secret-access-key", "",
+		"The secret access key to access the S3 repository (restic-s3 data mover only)")
+	cmd.Flags().StringVar(&options.RsyncImage, "rsync-image", move.DefaultRsyncImage,
+		"The rsync image to use (rsync data mover only)")
+	cmd.Flags().StringVar(&options.VolumeSnapshotClass, "volume-snapshot-class", "",
+		"The VolumeSnapshotClass used to snapshot the volumes, defaulting to the default one (csi data mover only)")
+
+	f.Printer.CheckErr(cmd.RegisterFlagCompletionFunc("data-mover", completion.Enumeration(move.DataMoverTypes)))
+
+	return func() { options.DataMover = move.DataMoverType(dataMover.Value) }
+}
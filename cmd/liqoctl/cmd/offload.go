@@ -62,6 +62,8 @@ or (cluster labels in logical OR)
       --selector 'region in (europe,us-west)' --selector '!staging'
 or (output the NamespaceOffloading resource as a yaml manifest, without applying it)
   $ {{ .Executable }} offload namespace foo --output yaml
+or (output the target clusters, the placement of the pods and the reflected resources, without applying it)
+  $ {{ .Executable }} offload namespace foo --selector 'region=europe' --dry-run=plan
 `
 
 func newOffloadCommand(ctx context.Context, f *factory.Factory) *cobra.Command {
@@ -94,6 +96,7 @@ func newOffloadNamespaceCommand(ctx context.Context, f *factory.Factory) *cobra.
 	var remoteNamespaceName = ""
 
 	outputFormat := args.NewEnum([]string{"json", "yaml"}, "")
+	dryRun := args.NewEnum([]string{offload.DryRunPlan}, "")
 
 	options := offload.Options{Factory: f}
 	cmd := &cobra.Command{
@@ -110,6 +113,7 @@ func newOffloadNamespaceCommand(ctx context.Context, f *factory.Factory) *cobra.
 			options.NamespaceMappingStrategy = offloadingv1beta1.NamespaceMappingStrategyType(namespaceMappingStrategy.Value)
 			options.RemoteNamespaceName = remoteNamespaceName
 			options.OutputFormat = outputFormat.Value
+			options.DryRun = dryRun.Value
 			options.Printer.CheckErr(options.ParseClusterSelectors(selectors))
 		},
 
@@ -133,11 +137,15 @@ func newOffloadNamespaceCommand(ctx context.Context, f *factory.Factory) *cobra.
 
 	cmd.Flags().VarP(outputFormat, "output", "o",
 		"Output the resulting NamespaceOffloading resource, instead of applying it. Supported formats: json, yaml")
+	cmd.Flags().Var(dryRun, "dry-run",
+		"Output the consequences of the offloading (i.e., target clusters, pods placement and reflected resources), instead of applying it. "+
+			"Supported values: plan")
 
 	f.Printer.CheckErr(cmd.RegisterFlagCompletionFunc("pod-offloading-strategy", completion.Enumeration(podOffloadingStrategy.Allowed)))
 	f.Printer.CheckErr(cmd.RegisterFlagCompletionFunc("namespace-mapping-strategy", completion.Enumeration(namespaceMappingStrategy.Allowed)))
 	f.Printer.CheckErr(cmd.RegisterFlagCompletionFunc("selector", completion.LabelsSelector(ctx, f, completion.NoLimit)))
 	f.Printer.CheckErr(cmd.RegisterFlagCompletionFunc("output", completion.Enumeration(outputFormat.Allowed)))
+	f.Printer.CheckErr(cmd.RegisterFlagCompletionFunc("dry-run", completion.Enumeration(dryRun.Allowed)))
 
 	return cmd
 }
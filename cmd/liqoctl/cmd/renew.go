@@ -0,0 +1,127 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/liqotech/liqo/pkg/liqoctl/completion"
+	"github.com/liqotech/liqo/pkg/liqoctl/factory"
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
+	"github.com/liqotech/liqo/pkg/liqoctl/renew"
+	"github.com/liqotech/liqo/pkg/liqoctl/utils"
+)
+
+const liqoctlRenewResourceSliceLongHelp = `Renew the lease of a ResourceSlice.
+
+This command allows to renew the lease of the resources granted by a provider cluster through a
+ResourceSlice, extending it by the given duration starting from now. The provider cluster bounds
+the duration to the maximum one it allows, and removes the resources once the lease expires.
+The command must be executed on the consumer cluster.
+
+Examples:
+  $ {{ .Executable }} renew resourceslice my-rs-name --remote-cluster-id remote-cluster-id --duration 720h
+`
+
+const liqoctlRenewTenantLongHelp = `Renew the lease of the Tenant in a provider cluster.
+
+This command allows to renew the lease of the local cluster as a tenant of a provider cluster, extending
+it by the given duration starting from now. The provider cluster bounds the duration to the maximum one
+it allows, and re-activates the tenant if drained since its lease expired. The leases of the ResourceSlices
+do not last longer than the one of the tenant.
+The command must be executed on the consumer cluster.
+
+Examples:
+  $ {{ .Executable }} renew tenant --remote-cluster-id remote-cluster-id --duration 720h
+`
+
+// newRenewCommand represents the renew command.
+func newRenewCommand(ctx context.Context, f *factory.Factory) *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "renew",
+		Short: "Renew a liqo resource",
+		Long:  "Renew a liqo resource",
+		Args:  cobra.NoArgs,
+	}
+
+	utils.AddCommand(cmd, newRenewResourceSliceCommand(ctx, f))
+	utils.AddCommand(cmd, newRenewTenantCommand(ctx, f))
+
+	return cmd
+}
+
+// newRenewResourceSliceCommand represents the renew command.
+func newRenewResourceSliceCommand(ctx context.Context, f *factory.Factory) *cobra.Command {
+	options := renew.NewOptions(f)
+
+	var cmd = &cobra.Command{
+		Use:               "resourceslice",
+		Aliases:           []string{"resourceslices", "rs"},
+		Short:             "Renew the lease of a ResourceSlice",
+		Long:              liqoctlRenewResourceSliceLongHelp,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.ResourceSlices(ctx, f, 1),
+
+		Run: func(_ *cobra.Command, args []string) {
+			options.Name = args[0]
+			output.ExitOnErr(options.RunRenewResourceSlice(ctx))
+		},
+	}
+
+	options.Factory.AddFlags(cmd.PersistentFlags(), cmd.RegisterFlagCompletionFunc)
+
+	cmd.Flags().DurationVar(&options.Timeout, "timeout", 120*time.Second, "Timeout for renew completion")
+	cmd.Flags().Var(&options.ClusterID, "remote-cluster-id", "ClusterID of the provider cluster of the ResourceSlice to renew")
+	cmd.Flags().DurationVar(&options.Duration, "duration", 0,
+		"The requested duration of the lease, starting from now (0 means the maximum one allowed by the provider cluster)")
+
+	runtime.Must(cmd.MarkFlagRequired("remote-cluster-id"))
+	runtime.Must(cmd.RegisterFlagCompletionFunc("remote-cluster-id", completion.ClusterIDs(ctx, f, completion.NoLimit)))
+
+	return cmd
+}
+
+// newRenewTenantCommand represents the renew tenant command.
+func newRenewTenantCommand(ctx context.Context, f *factory.Factory) *cobra.Command {
+	options := renew.NewOptions(f)
+
+	var cmd = &cobra.Command{
+		Use:     "tenant",
+		Aliases: []string{"tenants"},
+		Short:   "Renew the lease of the Tenant in a provider cluster",
+		Long:    liqoctlRenewTenantLongHelp,
+		Args:    cobra.NoArgs,
+
+		Run: func(_ *cobra.Command, _ []string) {
+			output.ExitOnErr(options.RunRenewTenant(ctx))
+		},
+	}
+
+	options.Factory.AddFlags(cmd.PersistentFlags(), cmd.RegisterFlagCompletionFunc)
+
+	cmd.Flags().DurationVar(&options.Timeout, "timeout", 120*time.Second, "Timeout for renew completion")
+	cmd.Flags().Var(&options.ClusterID, "remote-cluster-id", "ClusterID of the provider cluster the lease of the Tenant is renewed in")
+	cmd.Flags().DurationVar(&options.Duration, "duration", 0,
+		"The requested duration of the lease, starting from now (0 means the maximum one allowed by the provider cluster)")
+
+	runtime.Must(cmd.MarkFlagRequired("remote-cluster-id"))
+	runtime.Must(cmd.RegisterFlagCompletionFunc("remote-cluster-id", completion.ClusterIDs(ctx, f, completion.NoLimit)))
+
+	return cmd
+}
@@ -133,6 +133,7 @@ func NewRootCommand(ctx context.Context) *cobra.Command {
 	utils.AddCommand(cmd, newCordonCommand(ctx, f))
 	utils.AddCommand(cmd, newUncordonCommand(ctx, f))
 	utils.AddCommand(cmd, newDrainCommand(ctx, f))
+	utils.AddCommand(cmd, newRenewCommand(ctx, f))
 	utils.AddCommand(cmd, create.NewCreateCommand(ctx, liqoResources, f))
 	utils.AddCommand(cmd, generate.NewGenerateCommand(ctx, liqoResources, f))
 	utils.AddCommand(cmd, get.NewGetCommand(ctx, liqoResources, f))
@@ -33,8 +33,14 @@ In the consumer cluster, it deletes the control plane Identity.
 In the provider cluster, it deletes the Tenant.
 The execution is prevented if any ResourceSlice or VirtualNode associated with the provider cluster is found.
 
+With --revoke, the Tenant in the provider cluster is revoked instead of deleted, regardless of the resources
+still in use: the identities issued to the consumer are recorded as revoked, their permissions are removed,
+and the revoked identities are reported. The revoked certificates are denied even if the Tenant is deleted.
+
 Examples:
   $ {{ .Executable }} unauthenticate --remote-kubeconfig <provider>
+or, to immediately revoke the identities issued to the consumer
+  $ {{ .Executable }} unauthenticate --remote-kubeconfig <provider> --revoke
 `
 
 // newUnauthenticateCommand represents the unauthenticate command.
@@ -60,6 +66,8 @@ func newUnauthenticateCommand(ctx context.Context, f *factory.Factory) *cobra.Co
 
 	cmd.PersistentFlags().DurationVar(&options.Timeout, "timeout", 2*time.Minute, "Timeout for completion")
 	cmd.PersistentFlags().BoolVar(&options.Wait, "wait", true, "Wait for the unauthentication to complete")
+	cmd.PersistentFlags().BoolVar(&options.Revoke, "revoke", false,
+		"Revoke the identities issued to the consumer cluster, instead of deleting the Tenant")
 
 	options.LocalFactory.AddFlags(cmd.PersistentFlags(), cmd.RegisterFlagCompletionFunc)
 	options.RemoteFactory.AddFlags(cmd.PersistentFlags(), cmd.RegisterFlagCompletionFunc)
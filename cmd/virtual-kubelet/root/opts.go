@@ -57,6 +57,7 @@ var DefaultReflectorsWorkers = map[resources.ResourceReflected]uint{
 	resources.ServiceAccount:        3,
 	resources.PersistentVolumeClaim: 3,
 	resources.Event:                 3,
+	resources.Job:                   0,
 }
 
 // DefaultReflectorsTypes contains the default type of reflection for each reflected resource.
@@ -69,6 +70,7 @@ var DefaultReflectorsTypes = map[resources.ResourceReflected]offloadingv1beta1.R
 	resources.ServiceAccount:        offloadingv1beta1.CustomLiqo,
 	resources.PersistentVolumeClaim: offloadingv1beta1.CustomLiqo,
 	resources.Event:                 offloadingv1beta1.DenyList,
+	resources.Job:                   offloadingv1beta1.CustomLiqo,
 }
 
 // Opts stores all the options for configuring the root virtual-kubelet command.
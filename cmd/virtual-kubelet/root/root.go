@@ -282,6 +282,8 @@ func runRootCommand(ctx context.Context, c *Opts) error {
 
 	if c.CreateNode {
 		nodeProvider := nodeprovider.NewLiqoNodeProvider(&nodecfg)
+		// Resync the reflected resources once the node is restored after a failover, to delete the orphaned remote shadowpods.
+		nodeProvider.NotifyFailoverRecovery(initCallback)
 		nodeReady = nodeProvider.StartProvider(ctx)
 
 		nodeRunner, err = node.NewNodeController(
@@ -360,7 +362,8 @@ func getVersion(config *rest.Config) string {
 }
 
 func isReflectionTypeNotCustomizable(resource resources.ResourceReflected) bool {
-	return resource == resources.Pod || resource == resources.ServiceAccount || resource == resources.PersistentVolumeClaim
+	return resource == resources.Pod || resource == resources.ServiceAccount || resource == resources.PersistentVolumeClaim ||
+		resource == resources.Job
 }
 
 func getReflectorsConfigs(c *Opts) (map[resources.ResourceReflected]offloadingv1beta1.ReflectorConfig, error) {
@@ -21,6 +21,7 @@ import (
 
 	"github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
@@ -28,6 +29,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -42,6 +44,7 @@ import (
 	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
 	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/leaderelection"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
 	argsutils "github.com/liqotech/liqo/pkg/utils/args"
 	flagsutils "github.com/liqotech/liqo/pkg/utils/flags"
 	"github.com/liqotech/liqo/pkg/utils/indexer"
@@ -50,6 +53,7 @@ import (
 	fwcfgwh "github.com/liqotech/liqo/pkg/webhooks/firewallconfiguration"
 	fcwh "github.com/liqotech/liqo/pkg/webhooks/foreigncluster"
 	nsoffwh "github.com/liqotech/liqo/pkg/webhooks/namespaceoffloading"
+	objectquotawh "github.com/liqotech/liqo/pkg/webhooks/objectquota"
 	podwh "github.com/liqotech/liqo/pkg/webhooks/pod"
 	resourceslicewh "github.com/liqotech/liqo/pkg/webhooks/resourceslice"
 	routecfgwh "github.com/liqotech/liqo/pkg/webhooks/routeconfiguration"
@@ -139,6 +143,12 @@ func main() {
 	mgr, err := ctrl.NewManager(config, ctrl.Options{
 		MapperProvider: mapper.LiqoMapperProvider(scheme),
 		Scheme:         scheme,
+		Cache: cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				// Only the ServiceAccounts backing token identities are relevant to the webhooks.
+				&corev1.ServiceAccount{}: {Label: labels.SelectorFromSet(labels.Set{authentication.TokenIdentityLabel: "true"})},
+			},
+		},
 		Metrics: server.Options{
 			BindAddress: *metricsAddr,
 		},
@@ -181,6 +191,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := mgr.Add(manager.RunnableFunc(spv.PreemptionEvictor())); err != nil {
+		klog.Errorf("Unable to add the shadowpod preemption evictor to the manager: %v", err)
+		os.Exit(1)
+	}
+
 	// Options for the virtual kubelet.
 	vkOptsDefaultTemplateRef, err := argsutils.GetObjectRefFromNamespacedName(*vkOptsDefaultTemplate)
 	if err != nil {
@@ -192,6 +207,10 @@ func main() {
 	mgr.GetWebhookServer().Register("/mutate/foreign-cluster", fcwh.NewMutator())
 	mgr.GetWebhookServer().Register("/validate/shadowpods", &webhook.Admission{Handler: spv})
 	mgr.GetWebhookServer().Register("/mutate/shadowpods", shadowpodswh.NewMutator(mgr.GetClient()))
+	mgr.GetWebhookServer().Register("/validate/shadowjobs", &webhook.Admission{Handler: shadowpodswh.NewJobValidator(spv)})
+	mgr.GetWebhookServer().Register("/mutate/shadowjobs", shadowpodswh.NewJobMutator(mgr.GetClient()))
+	mgr.GetWebhookServer().Register("/validate/reflected-objects", objectquotawh.NewValidator(mgr.GetClient(), *enableResourceValidation))
+	mgr.GetWebhookServer().Register("/mutate/reflected-objects", objectquotawh.NewMutator(mgr.GetClient()))
 	mgr.GetWebhookServer().Register("/validate/namespace-offloading", nsoffwh.New())
 	mgr.GetWebhookServer().Register("/mutate/pod", podwh.New(mgr.GetClient(), *liqoRuntimeClassName))
 	mgr.GetWebhookServer().Register("/mutate/virtualnodes", virtualnodewh.New(
@@ -202,6 +221,7 @@ func main() {
 	mgr.GetWebhookServer().Register("/validate/routeconfigurations", routecfgwh.NewValidator(mgr.GetClient()))
 	mgr.GetWebhookServer().Register("/validate/tenants", tenantwh.NewValidator(mgr.GetClient()))
 	mgr.GetWebhookServer().Register("/mutate/tenants", tenantwh.NewMutator(mgr.GetClient()))
+	mgr.GetWebhookServer().Register("/authorize/revoked-identities", tenantwh.NewRevocationAuthorizer(mgr.GetClient()))
 
 	// Register the secret controller
 	secretReconciler := secretcontroller.NewSecretReconciler(mgr.GetClient(), mgr.GetScheme(),
@@ -0,0 +1,82 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reflection
+
+import (
+	"context"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+const (
+	// heartbeatInterval is the interval between two consecutive renewals of the heartbeat Lease.
+	heartbeatInterval = 30 * time.Second
+	// heartbeatLeaseDuration is the duration of the heartbeat Lease, after which the local cluster is considered unreachable.
+	heartbeatLeaseDuration = 4 * heartbeatInterval
+)
+
+var leaseGVR = coordinationv1.SchemeGroupVersion.WithResource("leases")
+
+// renewHeartbeat renews the Lease in the remote tenant namespace signaling that the local (i.e., consumer) cluster is alive.
+func (r *Reflector) renewHeartbeat(ctx context.Context) {
+	leases := r.remoteClient.Resource(leaseGVR).Namespace(r.remoteNamespace)
+
+	var lease coordinationv1.Lease
+	existing, err := leases.Get(ctx, consts.ConsumerHeartbeatLeaseName, metav1.GetOptions{})
+	found := err == nil
+	switch {
+	case apierrors.IsNotFound(err):
+		lease.ObjectMeta = metav1.ObjectMeta{Name: consts.ConsumerHeartbeatLeaseName, Namespace: r.remoteNamespace}
+	case err != nil:
+		klog.Warningf("[%v] Failed to retrieve the heartbeat lease: %v", r.remoteClusterID, err)
+		return
+	default:
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(existing.Object, &lease); err != nil {
+			klog.Warningf("[%v] Failed to convert the heartbeat lease: %v", r.remoteClusterID, err)
+			return
+		}
+	}
+
+	lease.TypeMeta = metav1.TypeMeta{APIVersion: coordinationv1.SchemeGroupVersion.String(), Kind: "Lease"}
+	lease.Spec.HolderIdentity = ptr.To(string(r.localClusterID))
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(heartbeatLeaseDuration.Seconds()))
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: time.Now()}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&lease)
+	if err != nil {
+		klog.Warningf("[%v] Failed to convert the heartbeat lease: %v", r.remoteClusterID, err)
+		return
+	}
+
+	if !found {
+		_, err = leases.Create(ctx, &unstructured.Unstructured{Object: content}, metav1.CreateOptions{})
+	} else {
+		_, err = leases.Update(ctx, &unstructured.Unstructured{Object: content}, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		klog.Warningf("[%v] Failed to renew the heartbeat lease: %v", r.remoteClusterID, err)
+		return
+	}
+	klog.V(6).Infof("[%v] Heartbeat lease renewed", r.remoteClusterID)
+}
@@ -91,6 +91,7 @@ func (r *Reflector) Start(ctx context.Context) {
 	for i := uint(0); i < r.manager.workers; i++ {
 		go wait.Until(r.runWorker, time.Second, ctx.Done())
 	}
+	go wait.UntilWithContext(ctx, r.renewHeartbeat, heartbeatInterval)
 
 	go func() {
 		// Make sure the working queue is shutdown when the context is canceled.
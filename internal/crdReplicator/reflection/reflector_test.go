@@ -21,6 +21,8 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -196,4 +198,29 @@ var _ = Describe("Reflector tests", func() {
 			When("a remote object is present", WhenBody(CreateRemoteObject, func(rr *reflectedResource) cache.GenericNamespaceLister { return rr.remote }))
 		})
 	})
+	Describe("the renewHeartbeat function", func() {
+		getLease := func() *coordinationv1.Lease {
+			obj, err := remote.Resource(leaseGVR).Namespace(remoteNamespace).Get(ctx, consts.ConsumerHeartbeatLeaseName, v1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			var lease coordinationv1.Lease
+			Expect(runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &lease)).To(Succeed())
+			return &lease
+		}
+
+		It("should create the heartbeat lease in the remote tenant namespace", func() {
+			reflector.renewHeartbeat(ctx)
+			lease := getLease()
+			Expect(lease.Spec.HolderIdentity).To(PointTo(Equal(localClusterID)))
+			Expect(lease.Spec.RenewTime).ToNot(BeNil())
+		})
+
+		It("should renew the existing heartbeat lease", func() {
+			reflector.renewHeartbeat(ctx)
+			first := getLease().Spec.RenewTime.Time
+
+			time.Sleep(10 * time.Millisecond)
+			reflector.renewHeartbeat(ctx)
+			Expect(getLease().Spec.RenewTime.Time).To(BeTemporally(">", first))
+		})
+	})
 })
@@ -35,6 +35,10 @@ func (c *FakeOffloadingV1beta1) ShadowEndpointSlices(namespace string) v1beta1.S
 	return &FakeShadowEndpointSlices{c, namespace}
 }
 
+func (c *FakeOffloadingV1beta1) ShadowJobs(namespace string) v1beta1.ShadowJobInterface {
+	return &FakeShadowJobs{c, namespace}
+}
+
 func (c *FakeOffloadingV1beta1) ShadowPods(namespace string) v1beta1.ShadowPodInterface {
 	return &FakeShadowPods{c, namespace}
 }
@@ -29,6 +29,7 @@ type OffloadingV1beta1Interface interface {
 	RESTClient() rest.Interface
 	NamespaceMapsGetter
 	ShadowEndpointSlicesGetter
+	ShadowJobsGetter
 	ShadowPodsGetter
 	VirtualNodesGetter
 	VkOptionsTemplatesGetter
@@ -47,6 +48,10 @@ func (c *OffloadingV1beta1Client) ShadowEndpointSlices(namespace string) ShadowE
 	return newShadowEndpointSlices(c, namespace)
 }
 
+func (c *OffloadingV1beta1Client) ShadowJobs(namespace string) ShadowJobInterface {
+	return newShadowJobs(c, namespace)
+}
+
 func (c *OffloadingV1beta1Client) ShadowPods(namespace string) ShadowPodInterface {
 	return newShadowPods(c, namespace)
 }
@@ -26,6 +26,8 @@ type Interface interface {
 	NamespaceMaps() NamespaceMapInformer
 	// ShadowEndpointSlices returns a ShadowEndpointSliceInformer.
 	ShadowEndpointSlices() ShadowEndpointSliceInformer
+	// ShadowJobs returns a ShadowJobInformer.
+	ShadowJobs() ShadowJobInformer
 	// ShadowPods returns a ShadowPodInformer.
 	ShadowPods() ShadowPodInformer
 	// VirtualNodes returns a VirtualNodeInformer.
@@ -55,6 +57,11 @@ func (v *version) ShadowEndpointSlices() ShadowEndpointSliceInformer {
 	return &shadowEndpointSliceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ShadowJobs returns a ShadowJobInformer.
+func (v *version) ShadowJobs() ShadowJobInformer {
+	return &shadowJobInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // ShadowPods returns a ShadowPodInformer.
 func (v *version) ShadowPods() ShadowPodInformer {
 	return &shadowPodInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
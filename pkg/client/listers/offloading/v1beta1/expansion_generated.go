@@ -32,6 +32,14 @@ type ShadowEndpointSliceListerExpansion interface{}
 // ShadowEndpointSliceNamespaceLister.
 type ShadowEndpointSliceNamespaceListerExpansion interface{}
 
+// ShadowJobListerExpansion allows custom methods to be added to
+// ShadowJobLister.
+type ShadowJobListerExpansion interface{}
+
+// ShadowJobNamespaceListerExpansion allows custom methods to be added to
+// ShadowJobNamespaceLister.
+type ShadowJobNamespaceListerExpansion interface{}
+
 // ShadowPodListerExpansion allows custom methods to be added to
 // ShadowPodLister.
 type ShadowPodListerExpansion interface{}
@@ -52,9 +52,34 @@ const (
 	// CordonTenantAnnotation is the value of the annotation that enables the cordon of a tenant.
 	CordonTenantAnnotation = "liqo.io/cordon-tenant"
 
+	// CordonLeaseAnnotation is the value of the annotation that cordons a resource whose lease is expired.
+	CordonLeaseAnnotation = "liqo.io/cordon-lease"
+
+	// DrainedLeaseAnnotation is the annotation marking a tenant drained since its lease expired, which is re-activated
+	// once the lease is renewed.
+	DrainedLeaseAnnotation = "liqo.io/drained-lease"
+
+	// LeaseDurationAnnotation is the annotation overriding the duration of the lease of the resources granted through a ResourceSlice,
+	// when set on the ResourceSlice itself or on the Tenant of its consumer cluster (e.g., "720h").
+	LeaseDurationAnnotation = "liqo.io/lease-duration"
+
+	// TenantQuarantinedAnnotation is the annotation storing the time a tenant has been quarantined, since its
+	// consumer cluster was inactive for longer than the configured TTL.
+	TenantQuarantinedAnnotation = "liqo.io/quarantined-at"
+
+	// ConsumerHeartbeatLeaseName is the name of the Lease periodically renewed by a consumer cluster in the corresponding
+	// tenant namespace of the provider cluster, to signal it is still alive.
+	ConsumerHeartbeatLeaseName = "liqo-consumer-heartbeat"
+
+	// TenantResourceCapAnnotation is the annotation overriding the maximum amount of resources granted to a tenant
+	// by the ResourceSlice classes enforcing a cap (e.g., "cpu=4,memory=8Gi").
+	TenantResourceCapAnnotation = "liqo.io/resource-slice-cap"
+
 	// RenewAnnotation is the value of the annotation that enables the renewal of a resource.
 	RenewAnnotation = "liqo.io/renew"
 
 	// PeeringUserNameLabelKey labels all the resources created to grant peering permissions to the user doing a pering toward this cluster.
 	PeeringUserNameLabelKey = "liqo.io/peering-user-name"
+	// PeeringUserProfileLabelKey labels the ClusterRoles of the peering profiles, and the resources of the users they have been granted to.
+	PeeringUserProfileLabelKey = "liqo.io/peering-user-profile"
 )
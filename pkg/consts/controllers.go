@@ -56,28 +56,38 @@ const (
 	CtrlWGGatewayServer        = "wggatewayserver"
 
 	// Authentication.
-	CtrlIdentity            = "identity"
-	CtrlIdentityCreator     = "identity_creator"
-	CtrlRenewLocal          = "renew_local"
-	CtrlRenewRemote         = "renew_remote"
-	CtrlSecretNonceCreator  = "secret_noncecreator"
-	CtrlSecretNonceSigner   = "secret_noncesigner"
-	CtrlResourceSliceLocal  = "resourceslice_local"
-	CtrlResourceSliceRemote = "resourceslice_remote"
-	CtrlTenant              = "tenant"
+	CtrlIdentity                = "identity"
+	CtrlIdentityCreator         = "identity_creator"
+	CtrlLeaseLocal              = "lease_local"
+	CtrlLeaseRemote             = "lease_remote"
+	CtrlLeaseTenant             = "lease_tenant"
+	CtrlRenewLocal              = "renew_local"
+	CtrlRenewRemote             = "renew_remote"
+	CtrlSecretNonceCreator      = "secret_noncecreator"
+	CtrlSecretNonceSigner       = "secret_noncesigner"
+	CtrlResourceSliceLocal      = "resourceslice_local"
+	CtrlResourceSliceRemote     = "resourceslice_remote"
+	CtrlResourceSliceAutoscaler = "resourceslice_autoscaler"
+	CtrlTenant                  = "tenant"
 
 	// Offloading.
+	CtrlDaemonSetOffloading = "daemonset_offloading"
+	CtrlFailover            = "failover"
 	CtrlNamespaceMap        = "namespacemap"
 	CtrlNamespaceOffloading = "namespaceoffloading"
 	CtrlNodeFailure         = "node_failure"
+	CtrlPodGroup            = "pod_group"
 	CtrlPodStatus           = "pod_status"
 	CtrlShadowEndpointSlice = "shadowendpointslice"
+	CtrlShadowJob           = "shadowjob"
+	CtrlShadowJobScheduler  = "shadowjob_scheduler"
 	CtrlShadowPod           = "shadowpod"
 	CtrlVirtualNode         = "virtualnode"
 
 	// Cross modules.
 	CtrlResourceSliceQuotaCreator = "resourceslice_quotacreator"
 	CtrlResourceSliceVNCreator    = "resourceslice_vncreator"
+	CtrlTenantGC                  = "tenant_gc"
 	CtrlPodIPMapping              = "pod_ipmapping"
 	CtrlConfigurationIPMapping    = "configuration_ipmapping"
 )
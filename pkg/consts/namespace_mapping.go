@@ -39,4 +39,14 @@ const (
 	RemoteNamespaceOriginalNameAnnotationKey = "liqo.io/original-name"
 	// RemoteNamespaceClusterRoleName is the name of the cluster role used to grant permissions to the virtual kubelet in remote namespaces.
 	RemoteNamespaceClusterRoleName = "liqo-virtual-kubelet-remote"
+	// RemoteNamespaceRuntimeClassAnnotationKey is the annotation of a remote namespace specifying the runtime class
+	// enforced by the ShadowPod webhook on the offloaded pods.
+	RemoteNamespaceRuntimeClassAnnotationKey = "liqo.io/runtime-class"
+
+	// ClusterCostAnnotationKey is the annotation of VirtualNode and ForeignCluster resources specifying the cost of the
+	// corresponding cluster, leveraged by the NamespaceOffloading placement policy.
+	ClusterCostAnnotationKey = "liqo.io/cost"
+	// ClusterLatencyAnnotationKey is the annotation of VirtualNode and ForeignCluster resources specifying the latency
+	// (in milliseconds) towards the corresponding cluster, leveraged by the NamespaceOffloading placement policy.
+	ClusterLatencyAnnotationKey = "liqo.io/latency"
 )
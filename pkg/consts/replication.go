@@ -46,9 +46,18 @@ const (
 	ManagedByLabelKey = "liqo.io/managed-by"
 	// ManagedByShadowPodValue it the label value used to indicate that a given resource is managed by a ShadowPod.
 	ManagedByShadowPodValue = "shadowpod"
+	// ManagedByShadowJobValue it the label value used to indicate that a given resource is managed by a ShadowJob.
+	ManagedByShadowJobValue = "shadowjob"
 	// ManagedByShadowEndpointSliceValue it the label value used to indicate that a given resource is managed by a ShadowEndpointSlice.
 	ManagedByShadowEndpointSliceValue = "shadowendpointslice"
 
+	// ShadowJobManagedBy is the value of the spec.managedBy field of the Jobs that should be executed remotely through a ShadowJob,
+	// rather than having their pods reflected one by one.
+	ShadowJobManagedBy = "liqo.io/shadowjob"
+	// ShadowJobNodeAnnotationKey is the annotation set on the Jobs executed remotely through a ShadowJob and not explicitly
+	// bound to a virtual node (i.e., through the nodeName of the pod template), containing the virtual node they have been scheduled onto.
+	ShadowJobNodeAnnotationKey = "liqo.io/shadowjob-node"
+
 	// LocalResourceOwnership label key added to a resource when it is owned by a local component.
 	// Ex. Local networkconfigs are owned by the component that creates them. If the resource is replicated in
 	// a remote cluster this label is removed by the CRDReplicator.
@@ -87,4 +96,34 @@ const (
 
 	// RemoteRuntimeClassNameAnnotKey is the annotation key used to store the name of the remote pod runtimeclass.
 	RemoteRuntimeClassNameAnnotKey = "liqo.io/remote-runtime-class-name"
+
+	// RemoteNodeSelectorAnnotKey is the annotation key used to specify additional node selector constraints for the remote pod,
+	// in the key1=value1,key2=value2 format.
+	RemoteNodeSelectorAnnotKey = "liqo.io/remote-node-selector"
+
+	// RemoteAffinityAnnotKey is the annotation key used to specify additional (JSON encoded) node affinity constraints for the remote pod.
+	RemoteAffinityAnnotKey = "liqo.io/remote-affinity"
+
+	// RemoteTopologySpreadConstraintsAnnotKey is the annotation key used to specify the (JSON encoded) topology spread constraints
+	// for the remote pod, overriding the ones of the local pod.
+	RemoteTopologySpreadConstraintsAnnotKey = "liqo.io/remote-topology-spread-constraints"
+
+	// PodGroupAnnotationKey is the annotation key used to specify the group the pod belongs to, whose members shall
+	// be admitted atomically by the provider cluster.
+	PodGroupAnnotationKey = "liqo.io/pod-group"
+
+	// PodGroupMinMemberAnnotationKey is the annotation key used to specify the minimum number of members of the pod group,
+	// which shall fit altogether in the quota granted by the provider cluster.
+	PodGroupMinMemberAnnotationKey = "liqo.io/pod-group-min-member"
+
+	// PodGroupOwnerUIDAnnotationKey is the annotation key added to the members of a pod group, containing the UID of their
+	// controller, to distinguish different instances of the same workload sharing the name of the group.
+	PodGroupOwnerUIDAnnotationKey = "liqo.io/pod-group-owner-uid"
+
+	// PodGroupLabelKey is the label key added to the members of a pod group, to co-locate them in the same provider cluster.
+	PodGroupLabelKey = "liqo.io/pod-group"
+
+	// PodGroupSchedulingGate is the scheduling gate preventing the members of a pod group from being bound
+	// to a virtual node until the minimum number of members of the group has been created.
+	PodGroupSchedulingGate = "liqo.io/pod-group"
 )
@@ -19,6 +19,41 @@ const (
 	// to Liqo taint.
 	VirtualNodeTolerationKey = "virtual-node.liqo.io/not-allowed"
 
+	// ProviderUnavailableTaintKey is the key of the taint added to the virtual nodes whose provider cluster
+	// has been unavailable for longer than the failover grace period.
+	ProviderUnavailableTaintKey = "virtual-node.liqo.io/provider-unavailable"
+	// FailoverCordonedAnnotationKey is the annotation added to the virtual nodes cordoned as part of the failover
+	// process, to uncordon them once the provider cluster becomes available again.
+	FailoverCordonedAnnotationKey = "liqo.io/failover-cordoned"
+	// FailoverRecoveryPendingAnnotationKey is the annotation added by the virtual kubelet to the virtual nodes failed over,
+	// to persist across restarts that the remote resources shall be garbage collected once the node is restored.
+	FailoverRecoveryPendingAnnotationKey = "liqo.io/failover-recovery-pending"
+
+	// DaemonSetOffloadingAnnotationKey is the annotation enabling the offloading of a DaemonSet onto the virtual nodes.
+	// The DaemonSet controller keeps handling the local nodes only, while Liqo creates one offloaded pod either per
+	// provider cluster, per virtual node or per provider node, depending on the annotation value.
+	DaemonSetOffloadingAnnotationKey = "liqo.io/daemonset-offloading"
+	// DaemonSetOffloadingPerCluster is the value of the DaemonSetOffloadingAnnotationKey annotation requesting
+	// one offloaded pod for each provider cluster.
+	DaemonSetOffloadingPerCluster = "per-cluster"
+	// DaemonSetOffloadingPerVirtualNode is the value of the DaemonSetOffloadingAnnotationKey annotation requesting
+	// one offloaded pod for each virtual node. The pods are scheduled by the provider cluster within the set of nodes
+	// selected through the OffloadingPatch.
+	DaemonSetOffloadingPerVirtualNode = "per-virtual-node"
+	// DaemonSetOffloadingPerProviderNode is the value of the DaemonSetOffloadingAnnotationKey annotation requesting
+	// one offloaded pod for each provider node selected through the OffloadingPatch of the virtual nodes, among the
+	// ones exposed by the provider cluster in the status of the corresponding ResourceSlice.
+	DaemonSetOffloadingPerProviderNode = "per-provider-node"
+	// DaemonSetOffloadingProviderNodeAnnotationKey is the annotation added to the pods created on behalf of an offloaded
+	// DaemonSet in per-provider-node mode, containing the name of the provider node they are bound to.
+	DaemonSetOffloadingProviderNodeAnnotationKey = "liqo.io/daemonset-provider-node"
+	// DaemonSetOffloadingLabelKey is the label added to the pods created by Liqo on behalf of an offloaded DaemonSet,
+	// whose value is the name of the DaemonSet.
+	DaemonSetOffloadingLabelKey = "liqo.io/offloaded-daemonset"
+	// DaemonSetOffloadingTemplateHashAnnotationKey is the annotation added to the pods created on behalf of an offloaded
+	// DaemonSet, containing the hash of the pod template they have been generated from.
+	DaemonSetOffloadingTemplateHashAnnotationKey = "liqo.io/daemonset-template-hash"
+
 	// WebHookLabel used to mark the resouces related to the Liqo webhooks.
 	WebHookLabel = "liqo.io/webhook"
 
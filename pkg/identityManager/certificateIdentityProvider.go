@@ -17,6 +17,8 @@ package identitymanager
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"math/rand"
 	"strconv"
@@ -30,6 +32,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
@@ -52,6 +55,7 @@ type certificateIdentityProvider struct {
 	cl               client.Client
 	cnf              *rest.Config
 	csrWatcher       certificateSigningRequest.Watcher
+	policy           *IdentityPolicy
 }
 
 // GetRemoteCertificate retrieves a certificate issued in the past,
@@ -100,6 +104,7 @@ func (identityProvider *certificateIdentityProvider) GetRemoteCertificate(ctx co
 		return response, err
 	}
 
+	response.IssuedAt, response.TenantGeneration = getIssuedAnnotations(secret)
 	return response, nil
 }
 
@@ -126,6 +131,9 @@ func (identityProvider *certificateIdentityProvider) ApproveSigningRequest(ctx c
 			},
 		},
 	}
+	if lifetime := identityProvider.policy.MaxLifetime(options.IdentityType); lifetime > 0 {
+		cert.Spec.ExpirationSeconds = ptr.To(int32(lifetime.Seconds()))
+	}
 
 	cert, err = identityProvider.k8sClient.CertificatesV1().CertificateSigningRequests().Create(ctx, cert, metav1.CreateOptions{})
 	if err != nil {
@@ -167,6 +175,17 @@ func (identityProvider *certificateIdentityProvider) ForgeAuthParams(ctx context
 		return nil, err
 	}
 
+	needsRotation, err := identityProvider.certificateNeedsRotation(options, resp)
+	if err != nil {
+		return nil, err
+	}
+	if needsRotation {
+		klog.Infof("Rotating the %s identity %q of cluster %q", options.IdentityType, options.Name, options.Cluster)
+		if resp, err = identityProvider.ApproveSigningRequest(ctx, options); err != nil {
+			return nil, err
+		}
+	}
+
 	apiServer, err := apiserver.GetURL(ctx, identityProvider.cl, options.APIServerAddressOverride)
 	if err != nil {
 		return nil, err
@@ -179,13 +198,34 @@ func (identityProvider *certificateIdentityProvider) ForgeAuthParams(ctx context
 	}
 
 	return &authv1beta1.AuthParams{
-		CA:        ca,
-		SignedCRT: resp.Certificate,
-		APIServer: apiServer,
-		ProxyURL:  options.ProxyURL,
+		CA:             ca,
+		SignedCRT:      resp.Certificate,
+		APIServer:      apiServer,
+		ProxyURL:       options.ProxyURL,
+		IdentityPolicy: identityProvider.policy.ForIdentityType(options.IdentityType),
 	}, nil
 }
 
+// certificateNeedsRotation returns whether the given certificate, just retrieved or issued, has to be replaced by a new one.
+func (identityProvider *certificateIdentityProvider) certificateNeedsRotation(options *SigningRequestOptions,
+	resp *responsetypes.SigningRequestResponse) (bool, error) {
+	// Certificates issued before the rotation policy was introduced cannot be evaluated, hence they are retained.
+	if resp.IssuedAt.IsZero() {
+		return false, nil
+	}
+
+	block, _ := pem.Decode(resp.Certificate)
+	if block == nil {
+		return false, fmt.Errorf("failed to decode PEM block containing certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	return identityProvider.policy.needsRotation(options, resp.TenantGeneration, resp.IssuedAt, cert.NotBefore, cert.NotAfter), nil
+}
+
 func remoteCertificateSecretName(options *SigningRequestOptions) string {
 	switch options.IdentityType {
 	case authv1beta1.ResourceSliceIdentityType:
@@ -216,6 +256,7 @@ func (identityProvider *certificateIdentityProvider) storeRemoteCertificate(ctx
 		}
 		secret.Data[csrSecretKey] = options.SigningRequest
 		secret.Data[certificateSecretKey] = certificate
+		setIssuedAnnotations(secret, options)
 
 		return nil
 	})
@@ -33,11 +33,14 @@ const (
 
 const (
 	certificateExpireTimeAnnotation = "liqo.io/certificate-expire-time"
+	issuedAtAnnotation              = "liqo.io/issued-at"
+	tenantGenerationAnnotation      = "liqo.io/tenant-generation"
 )
 
 const (
 	identitySecretRoot      = "liqo-identity"
 	remoteCertificateSecret = "liqo-remote-certificate"
+	remoteIdentitySA        = "liqo-remote-identity"
 
 	privateKeySecretKey  = "private-key"
 	csrSecretKey         = "csr"
@@ -58,4 +61,8 @@ const (
 	AwsEKSClusterIDSecretKey = "awsEksClusterID" //nolint:gosec // not a credential
 	// AwsIAMUserArnSecretKey is the key used for the AWS IAM user ARN inside the secret.
 	AwsIAMUserArnSecretKey = "awsIamUserArn" //nolint:gosec // not a credential
+
+	tokenSecretKey           = "token"
+	tokenIssuedAtSecretKey   = "tokenIssuedAt"
+	tokenExpirationSecretKey = "tokenExpiration"
 )
@@ -38,7 +38,6 @@ import (
 	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
 	"github.com/liqotech/liqo/pkg/consts"
 	responsetypes "github.com/liqotech/liqo/pkg/identityManager/responseTypes"
-	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
 	"github.com/liqotech/liqo/pkg/utils/resource"
 )
 
@@ -141,24 +140,10 @@ func (identityProvider *iamIdentityProvider) ApproveSigningRequest(ctx context.C
 
 	iamSvc := iam.New(sess)
 
-	var username string
-	var organization string
-
-	switch options.IdentityType {
-	case authv1beta1.ControlPlaneIdentityType:
-		username = authentication.CommonNameControlPlaneCSR(options.Cluster)
-		organization = authentication.OrganizationControlPlaneCSR()
-	case authv1beta1.ResourceSliceIdentityType:
-		if options.ResourceSlice == nil {
-			klog.Error("resource slice is nil")
-			return response, fmt.Errorf("resource slice is nil")
-		}
-
-		username = authentication.CommonNameResourceSliceCSR(options.ResourceSlice)
-		organization = authentication.OrganizationResourceSliceCSR(options.ResourceSlice)
-	default:
-		klog.Errorf("identity type %v not supported", options.IdentityType)
-		return response, fmt.Errorf("identity type %v not supported", options.IdentityType)
+	username, organization, err := identitySubject(options)
+	if err != nil {
+		klog.Error(err)
+		return response, err
 	}
 
 	// the IAM username has to have <= 64 characters
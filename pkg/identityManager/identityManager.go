@@ -16,6 +16,7 @@ package identitymanager
 
 import (
 	"context"
+	"time"
 
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
@@ -64,10 +65,10 @@ func NewCertificateIdentityManager(ctx context.Context,
 	return newIdentityManager(ctx, cl, k8sClient, localCluster, namespaceManager, idProvider)
 }
 
-// NewCertificateIdentityProvider gets a new certificate identity approver.
+// NewCertificateIdentityProvider gets a new certificate identity approver, enforcing the given policy (if any).
 func NewCertificateIdentityProvider(ctx context.Context, cl client.Client, k8sClient kubernetes.Interface,
 	cnf *rest.Config,
-	localCluster liqov1beta1.ClusterID, namespaceManager tenantnamespace.Manager) IdentityProvider {
+	localCluster liqov1beta1.ClusterID, namespaceManager tenantnamespace.Manager, policy *IdentityPolicy) IdentityProvider {
 	req, err := labels.NewRequirement(remoteTenantCSRLabel, selection.Exists, []string{})
 	utilruntime.Must(err)
 
@@ -79,6 +80,7 @@ func NewCertificateIdentityProvider(ctx context.Context, cl client.Client, k8sCl
 		cl:               cl,
 		cnf:              cnf,
 		csrWatcher:       csrWatcher,
+		policy:           policy,
 	}
 
 	return newIdentityManager(ctx, cl, k8sClient, localCluster, namespaceManager, idProvider)
@@ -99,6 +101,22 @@ func NewIAMIdentityProvider(ctx context.Context, cl client.Client, k8sClient kub
 	return newIdentityManager(ctx, cl, k8sClient, localCluster, namespaceManager, idProvider)
 }
 
+// NewTokenIdentityProvider gets a new identity approver issuing short-lived bearer tokens through the given issuer,
+// enforcing the given policy (if any).
+func NewTokenIdentityProvider(ctx context.Context, cl client.Client, k8sClient kubernetes.Interface,
+	cnf *rest.Config, localCluster liqov1beta1.ClusterID, namespaceManager tenantnamespace.Manager,
+	issuer TokenIssuer, expiration time.Duration, policy *IdentityPolicy) IdentityProvider {
+	idProvider := &tokenIdentityProvider{
+		cl:         cl,
+		cnf:        cnf,
+		issuer:     issuer,
+		expiration: expiration,
+		policy:     policy,
+	}
+
+	return newIdentityManager(ctx, cl, k8sClient, localCluster, namespaceManager, idProvider)
+}
+
 func newIdentityManager(ctx context.Context,
 	cl client.Client, k8sClient kubernetes.Interface,
 	localCluster liqov1beta1.ClusterID,
@@ -31,7 +31,7 @@ var _ = Describe("IdentityManager", func() {
 		var stopChan chan struct{}
 
 		BeforeAll(func() {
-			_, csrBytes, err = csr.NewKeyAndRequest("foobar")
+			_, csrBytes, err = csr.NewKeyAndRequest("foobar", csr.KeyAlgorithmEd25519)
 			Expect(err).To(BeNil())
 		})
 
@@ -77,7 +77,7 @@ var _ = Describe("IdentityManager", func() {
 		It("Certificate Identity Provider", func() {
 			idProvider := NewCertificateIdentityProvider(ctx,
 				mgr.GetClient(), cluster.GetClient(), cluster.GetCfg(),
-				localCluster, namespaceManager)
+				localCluster, namespaceManager, nil)
 
 			certIDManager, ok := idProvider.(*identityManager)
 			Expect(ok).To(BeTrue())
@@ -0,0 +1,132 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identitymanager
+
+import (
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+)
+
+// IdentityPolicy defines the lifetime and rotation policy of the identities issued to the consumer clusters.
+type IdentityPolicy struct {
+	// ControlPlaneMaxLifetime is the maximum lifetime of the control plane identities (zero means no limit).
+	ControlPlaneMaxLifetime time.Duration
+	// ResourceSliceMaxLifetime is the maximum lifetime of the ResourceSlice identities (zero means no limit).
+	ResourceSliceMaxLifetime time.Duration
+	// RenewBefore is how long before their expiration the identities are renewed (zero means at 2/3 of their lifetime).
+	RenewBefore time.Duration
+	// RotateOnTenantUpdate forces the rotation of the identities whenever the spec of their Tenant changes.
+	RotateOnTenantUpdate bool
+}
+
+// MaxLifetime returns the maximum lifetime of the identities of the given type, or zero if not limited.
+func (p *IdentityPolicy) MaxLifetime(identityType authv1beta1.IdentityType) time.Duration {
+	if p == nil {
+		return 0
+	}
+
+	switch identityType {
+	case authv1beta1.ControlPlaneIdentityType:
+		return p.ControlPlaneMaxLifetime
+	case authv1beta1.ResourceSliceIdentityType:
+		return p.ResourceSliceMaxLifetime
+	default:
+		return 0
+	}
+}
+
+// ForIdentityType returns the policy advertised to the consumer cluster for the identities of the given type.
+func (p *IdentityPolicy) ForIdentityType(identityType authv1beta1.IdentityType) *authv1beta1.IdentityPolicy {
+	if p == nil {
+		return nil
+	}
+
+	var policy authv1beta1.IdentityPolicy
+	if lifetime := p.MaxLifetime(identityType); lifetime > 0 {
+		policy.MaxLifetime = &metav1.Duration{Duration: lifetime}
+	}
+	if p.RenewBefore > 0 {
+		policy.RenewBefore = &metav1.Duration{Duration: p.RenewBefore}
+	}
+
+	if policy.MaxLifetime == nil && policy.RenewBefore == nil {
+		return nil
+	}
+	return &policy
+}
+
+// needsRotation returns whether the credentials valid in the given period, issued at the given time when the Tenant
+// had the given generation, have to be replaced by new ones. Outside of renewals, they are rotated once they reach
+// the renewal time, or when the Tenant changed if so configured. Renewals are satisfied with new credentials unless
+// the current ones have been issued in the last tenth of their lifetime, which prevents reissuing them at every
+// reconciliation of the same request.
+func (p *IdentityPolicy) needsRotation(options *SigningRequestOptions, issuedGeneration int64,
+	issuedAt, notBefore, notAfter time.Time) bool {
+	if p != nil && p.RotateOnTenantUpdate && options.TenantGeneration != 0 &&
+		issuedGeneration != 0 && issuedGeneration != options.TenantGeneration {
+		return true
+	}
+
+	if options.IsUpdate {
+		return time.Since(issuedAt) > notAfter.Sub(notBefore)/10
+	}
+	return !time.Now().Before(p.ForIdentityType(options.IdentityType).RenewalTime(notBefore, notAfter))
+}
+
+// expiration returns the lifetime of the credentials to be issued, given the default one, capped by the policy.
+func (p *IdentityPolicy) expiration(identityType authv1beta1.IdentityType, defaultLifetime time.Duration) time.Duration {
+	if lifetime := p.MaxLifetime(identityType); lifetime > 0 && (defaultLifetime == 0 || lifetime < defaultLifetime) {
+		return lifetime
+	}
+	return defaultLifetime
+}
+
+// setIssuedAnnotations records in the given Secret when, and for which generation of the Tenant, the identity it
+// contains has been issued.
+func setIssuedAnnotations(secret *corev1.Secret, options *SigningRequestOptions) {
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[issuedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	if options.TenantGeneration != 0 {
+		secret.Annotations[tenantGenerationAnnotation] = strconv.FormatInt(options.TenantGeneration, 10)
+	}
+}
+
+// getIssuedAnnotations returns when, and for which generation of the Tenant, the identity contained in the given
+// Secret has been issued. Zero values are returned if unknown.
+func getIssuedAnnotations(secret *corev1.Secret) (issuedAt time.Time, generation int64) {
+	if value, ok := secret.Annotations[issuedAtAnnotation]; ok {
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			issuedAt = parsed
+		} else {
+			klog.Warningf("invalid annotation %v in secret %v/%v: %v", issuedAtAnnotation, secret.Namespace, secret.Name, err)
+		}
+	}
+	if value, ok := secret.Annotations[tenantGenerationAnnotation]; ok {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			generation = parsed
+		} else {
+			klog.Warningf("invalid annotation %v in secret %v/%v: %v", tenantGenerationAnnotation, secret.Namespace, secret.Name, err)
+		}
+	}
+	return issuedAt, generation
+}
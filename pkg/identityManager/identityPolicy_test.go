@@ -0,0 +1,91 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identitymanager
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+)
+
+var _ = Describe("Identity policy", func() {
+	var policy *IdentityPolicy
+
+	BeforeEach(func() {
+		policy = &IdentityPolicy{
+			ControlPlaneMaxLifetime:  24 * time.Hour,
+			ResourceSliceMaxLifetime: time.Hour,
+			RenewBefore:              10 * time.Minute,
+		}
+	})
+
+	It("should return the maximum lifetime of each identity type", func() {
+		Expect(policy.MaxLifetime(authv1beta1.ControlPlaneIdentityType)).To(Equal(24 * time.Hour))
+		Expect(policy.MaxLifetime(authv1beta1.ResourceSliceIdentityType)).To(Equal(time.Hour))
+		Expect((*IdentityPolicy)(nil).MaxLifetime(authv1beta1.ControlPlaneIdentityType)).To(BeZero())
+	})
+
+	It("should cap the lifetime of the issued credentials", func() {
+		Expect(policy.expiration(authv1beta1.ResourceSliceIdentityType, 2*time.Hour)).To(Equal(time.Hour))
+		Expect(policy.expiration(authv1beta1.ControlPlaneIdentityType, 2*time.Hour)).To(Equal(2 * time.Hour))
+		Expect((*IdentityPolicy)(nil).expiration(authv1beta1.ControlPlaneIdentityType, 2*time.Hour)).To(Equal(2 * time.Hour))
+	})
+
+	It("should advertise the policy of each identity type", func() {
+		advertised := policy.ForIdentityType(authv1beta1.ResourceSliceIdentityType)
+		Expect(advertised).ToNot(BeNil())
+		Expect(advertised.MaxLifetime.Duration).To(Equal(time.Hour))
+		Expect(advertised.RenewBefore.Duration).To(Equal(10 * time.Minute))
+		Expect((&IdentityPolicy{}).ForIdentityType(authv1beta1.ResourceSliceIdentityType)).To(BeNil())
+	})
+
+	When("checking whether credentials need rotation", func() {
+		var options *SigningRequestOptions
+
+		BeforeEach(func() {
+			options = &SigningRequestOptions{IdentityType: authv1beta1.ControlPlaneIdentityType, TenantGeneration: 2}
+		})
+
+		It("should rotate them once they reach the renewal time", func() {
+			issuedAt := time.Now().Add(-55 * time.Minute)
+			Expect(policy.needsRotation(options, 2, issuedAt, issuedAt, issuedAt.Add(time.Hour))).To(BeTrue())
+
+			issuedAt = time.Now().Add(-45 * time.Minute)
+			Expect(policy.needsRotation(options, 2, issuedAt, issuedAt, issuedAt.Add(time.Hour))).To(BeFalse())
+		})
+
+		It("should rotate them on renewals, unless they have just been issued", func() {
+			options.IsUpdate = true
+			issuedAt := time.Now().Add(-10 * time.Minute)
+			Expect(policy.needsRotation(options, 2, issuedAt, issuedAt, issuedAt.Add(time.Hour))).To(BeTrue())
+
+			issuedAt = time.Now()
+			Expect(policy.needsRotation(options, 2, issuedAt, issuedAt, issuedAt.Add(time.Hour))).To(BeFalse())
+		})
+
+		It("should rotate them when the Tenant changed, if configured", func() {
+			issuedAt := time.Now()
+			Expect(policy.needsRotation(options, 1, issuedAt, issuedAt, issuedAt.Add(time.Hour))).To(BeFalse())
+
+			policy.RotateOnTenantUpdate = true
+			Expect(policy.needsRotation(options, 1, issuedAt, issuedAt, issuedAt.Add(time.Hour))).To(BeTrue())
+			Expect(policy.needsRotation(options, 2, issuedAt, issuedAt, issuedAt.Add(time.Hour))).To(BeFalse())
+			Expect(policy.needsRotation(options, 0, issuedAt, issuedAt, issuedAt.Add(time.Hour))).To(BeFalse())
+		})
+	})
+})
@@ -58,6 +58,8 @@ type SigningRequestOptions struct {
 	ResourceSlice            *authv1beta1.ResourceSlice
 	ProxyURL                 *string
 	IsUpdate                 bool
+	// TenantGeneration is the generation of the Tenant the identity is issued for, used to rotate it on Tenant updates.
+	TenantGeneration int64
 }
 
 // IdentityProvider provides the interface to retrieve and approve remote cluster identities.
@@ -71,3 +73,4 @@ type IdentityProvider interface {
 
 var _ IdentityProvider = &certificateIdentityProvider{}
 var _ IdentityProvider = &iamIdentityProvider{}
+var _ IdentityProvider = &tokenIdentityProvider{}
@@ -14,6 +14,8 @@
 
 package responsetypes
 
+import "time"
+
 // SigningRequestResponseType indicates the type for a signign request response.
 type SigningRequestResponseType string
 
@@ -23,6 +25,9 @@ const (
 	SigningRequestResponseCertificate SigningRequestResponseType = "Certificate"
 	// SigningRequestResponseIAM indicates that the identity has been validated by the Amazon IAM service.
 	SigningRequestResponseIAM SigningRequestResponseType = "IAM"
+	// SigningRequestResponseToken indicates that the signing request response contains a bearer token
+	// issued by the cluster token issuer.
+	SigningRequestResponseToken SigningRequestResponseType = "Token"
 )
 
 // AwsIdentityResponse contains the information about the created IAM user and the EKS cluster.
@@ -36,6 +41,13 @@ type AwsIdentityResponse struct {
 	Region                             string
 }
 
+// TokenIdentityResponse contains the bearer token issued for the remote cluster and its refresh metadata.
+type TokenIdentityResponse struct {
+	Token               string
+	IssuedAt            time.Time
+	ExpirationTimestamp time.Time
+}
+
 // SigningRequestResponse contains the response from an Indentity Provider.
 type SigningRequestResponse struct {
 	ResponseType SigningRequestResponseType
@@ -43,4 +55,11 @@ type SigningRequestResponse struct {
 	Certificate []byte
 
 	AwsIdentityResponse AwsIdentityResponse
+
+	TokenIdentityResponse TokenIdentityResponse
+
+	// IssuedAt is the time the identity has been issued (zero if unknown).
+	IssuedAt time.Time
+	// TenantGeneration is the generation of the Tenant when the identity has been issued (zero if unknown).
+	TenantGeneration int64
 }
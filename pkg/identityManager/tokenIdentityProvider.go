@@ -0,0 +1,205 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identitymanager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	responsetypes "github.com/liqotech/liqo/pkg/identityManager/responseTypes"
+	"github.com/liqotech/liqo/pkg/utils/apiserver"
+	"github.com/liqotech/liqo/pkg/utils/resource"
+)
+
+type tokenIdentityProvider struct {
+	cl         client.Client
+	cnf        *rest.Config
+	issuer     TokenIssuer
+	expiration time.Duration
+	policy     *IdentityPolicy
+}
+
+// GetRemoteCertificate retrieves the token issued in the past, given the clusterid and the identity name.
+func (identityProvider *tokenIdentityProvider) GetRemoteCertificate(ctx context.Context,
+	options *SigningRequestOptions) (response *responsetypes.SigningRequestResponse, err error) {
+	response = &responsetypes.SigningRequestResponse{
+		ResponseType: responsetypes.SigningRequestResponseToken,
+	}
+
+	secretName := remoteCertificateSecretName(options)
+	var secret corev1.Secret
+	if err = identityProvider.cl.Get(ctx, client.ObjectKey{Name: secretName, Namespace: options.TenantNamespace}, &secret); err != nil {
+		if kerrors.IsNotFound(err) {
+			klog.V(4).Info(err)
+		} else {
+			klog.Error(err)
+		}
+		return response, err
+	}
+
+	token, ok := secret.Data[tokenSecretKey]
+	if !ok {
+		klog.Errorf("no %v key in secret %v/%v", tokenSecretKey, secret.Namespace, secret.Name)
+		err = kerrors.NewNotFound(schema.GroupResource{
+			Group:    "v1",
+			Resource: "secrets",
+		}, secretName)
+		return response, err
+	}
+
+	issuedAt, err := time.Parse(time.RFC3339, string(secret.Data[tokenIssuedAtSecretKey]))
+	if err != nil {
+		klog.Errorf("invalid %v key in secret %v/%v: %v", tokenIssuedAtSecretKey, secret.Namespace, secret.Name, err)
+		return response, err
+	}
+	expiration, err := time.Parse(time.RFC3339, string(secret.Data[tokenExpirationSecretKey]))
+	if err != nil {
+		klog.Errorf("invalid %v key in secret %v/%v: %v", tokenExpirationSecretKey, secret.Namespace, secret.Name, err)
+		return response, err
+	}
+
+	response.TokenIdentityResponse = responsetypes.TokenIdentityResponse{
+		Token:               string(token),
+		IssuedAt:            issuedAt,
+		ExpirationTimestamp: expiration,
+	}
+	response.IssuedAt, response.TenantGeneration = getIssuedAnnotations(&secret)
+	return response, nil
+}
+
+// ApproveSigningRequest issues a new token for the remote cluster, and stores it in the tenant namespace.
+// The signing request is not used, as the remote cluster authenticates through the bearer token only.
+func (identityProvider *tokenIdentityProvider) ApproveSigningRequest(ctx context.Context,
+	options *SigningRequestOptions) (response *responsetypes.SigningRequestResponse, err error) {
+	username, organization, err := identitySubject(options)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	name := remoteIdentitySA
+	if options.IdentityType == authv1beta1.ResourceSliceIdentityType {
+		name = fmt.Sprintf("%s-%s", remoteIdentitySA, options.Name)
+	}
+
+	tokenResponse, err := identityProvider.issuer.IssueToken(ctx, &TokenIssueRequest{
+		Cluster:         options.Cluster,
+		TenantNamespace: options.TenantNamespace,
+		Name:            name,
+		Username:        username,
+		Groups:          []string{organization},
+		Expiration:      identityProvider.policy.expiration(options.IdentityType, identityProvider.expiration),
+	})
+	if err != nil {
+		klog.Errorf("Unable to issue a token for cluster %q: %v", options.Cluster, err)
+		return nil, err
+	}
+
+	// Timestamps are stored with a seconds precision, hence truncate them to hand out consistent values.
+	tokenResponse.IssuedAt = tokenResponse.IssuedAt.Truncate(time.Second)
+	tokenResponse.ExpirationTimestamp = tokenResponse.ExpirationTimestamp.Truncate(time.Second)
+
+	if err = identityProvider.storeRemoteToken(ctx, options, tokenResponse); err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	return &responsetypes.SigningRequestResponse{
+		ResponseType:          responsetypes.SigningRequestResponseToken,
+		TokenIdentityResponse: *tokenResponse,
+	}, nil
+}
+
+// ForgeAuthParams returns the AuthParams carrying the token issued for the remote cluster.
+// A stored token is handed out again until it reaches its renewal time, while renewals
+// get a new one unless the current token has just been issued.
+func (identityProvider *tokenIdentityProvider) ForgeAuthParams(ctx context.Context,
+	options *SigningRequestOptions) (*authv1beta1.AuthParams, error) {
+	resp, err := identityProvider.GetRemoteCertificate(ctx, options)
+	switch {
+	case kerrors.IsNotFound(err), err == nil && identityProvider.policy.needsRotation(options, resp.TenantGeneration,
+		resp.TokenIdentityResponse.IssuedAt, resp.TokenIdentityResponse.IssuedAt, resp.TokenIdentityResponse.ExpirationTimestamp):
+		resp, err = identityProvider.ApproveSigningRequest(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	}
+
+	apiServer, err := apiserver.GetURL(ctx, identityProvider.cl, options.APIServerAddressOverride)
+	if err != nil {
+		return nil, err
+	}
+
+	ca, err := apiserver.RetrieveAPIServerCA(identityProvider.cnf,
+		options.CAOverride, options.TrustedCA)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authv1beta1.AuthParams{
+		CA:        ca,
+		APIServer: apiServer,
+		ProxyURL:  options.ProxyURL,
+		TokenConfig: &authv1beta1.TokenConfig{
+			Token:               resp.TokenIdentityResponse.Token,
+			IssuedAt:            metav1.NewTime(resp.TokenIdentityResponse.IssuedAt),
+			ExpirationTimestamp: metav1.NewTime(resp.TokenIdentityResponse.ExpirationTimestamp),
+		},
+		IdentityPolicy: identityProvider.policy.ForIdentityType(options.IdentityType),
+	}, nil
+}
+
+// storeRemoteToken stores the issued token in a Secret in the TenantNamespace.
+func (identityProvider *tokenIdentityProvider) storeRemoteToken(ctx context.Context,
+	options *SigningRequestOptions, token *responsetypes.TokenIdentityResponse) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      remoteCertificateSecretName(options),
+			Namespace: options.TenantNamespace,
+		},
+	}
+
+	_, err := resource.CreateOrUpdate(ctx, identityProvider.cl, secret, func() error {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels[consts.RemoteClusterID] = string(options.Cluster)
+
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[csrSecretKey] = options.SigningRequest
+		secret.Data[tokenSecretKey] = []byte(token.Token)
+		secret.Data[tokenIssuedAtSecretKey] = []byte(token.IssuedAt.UTC().Format(time.RFC3339))
+		secret.Data[tokenExpirationSecretKey] = []byte(token.ExpirationTimestamp.UTC().Format(time.RFC3339))
+		setIssuedAnnotations(secret, options)
+
+		return nil
+	})
+	return err
+}
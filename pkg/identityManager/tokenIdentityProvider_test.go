@@ -0,0 +1,200 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identitymanager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	responsetypes "github.com/liqotech/liqo/pkg/identityManager/responseTypes"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
+)
+
+// mockTokenIssuer mimics a token issuer, minting tokens named after the requested user.
+type mockTokenIssuer struct {
+	issued   int
+	lifetime time.Duration
+}
+
+func (issuer *mockTokenIssuer) IssueToken(_ context.Context,
+	request *TokenIssueRequest) (*responsetypes.TokenIdentityResponse, error) {
+	issuer.issued++
+	now := time.Now()
+	return &responsetypes.TokenIdentityResponse{
+		Token:               fmt.Sprintf("%s-token-%d", request.Username, issuer.issued),
+		IssuedAt:            now,
+		ExpirationTimestamp: now.Add(issuer.lifetime),
+	}, nil
+}
+
+var _ = Describe("Token Identity Provider", func() {
+	const tenantNamespace = "liqo-tenant-remote"
+
+	var (
+		cl client.Client
+	)
+
+	BeforeEach(func() {
+		cl = ctrlfake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tenantNamespace, UID: "tenant-uid"}},
+		).Build()
+	})
+
+	Context("ForgeAuthParams", func() {
+		var (
+			issuer     *mockTokenIssuer
+			idProvider *tokenIdentityProvider
+			options    *SigningRequestOptions
+		)
+
+		BeforeEach(func() {
+			issuer = &mockTokenIssuer{lifetime: time.Hour}
+			idProvider = &tokenIdentityProvider{cl: cl, cnf: &rest.Config{}, issuer: issuer, expiration: time.Hour}
+			options = &SigningRequestOptions{
+				Cluster:                  remoteCluster,
+				TenantNamespace:          tenantNamespace,
+				IdentityType:             authv1beta1.ControlPlaneIdentityType,
+				APIServerAddressOverride: "remote.example.com:6443",
+				CAOverride:               []byte("ca"),
+			}
+		})
+
+		setIssuedAt := func(issuedAt time.Time) {
+			var secret corev1.Secret
+			Expect(cl.Get(ctx, client.ObjectKey{Name: remoteCertificateSecret, Namespace: tenantNamespace}, &secret)).To(Succeed())
+			secret.Data[tokenIssuedAtSecretKey] = []byte(issuedAt.UTC().Format(time.RFC3339))
+			secret.Data[tokenExpirationSecretKey] = []byte(issuedAt.Add(time.Hour).UTC().Format(time.RFC3339))
+			Expect(cl.Update(ctx, &secret)).To(Succeed())
+		}
+
+		It("should issue a token and reuse it while valid", func() {
+			authParams, err := idProvider.ForgeAuthParams(ctx, options)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(authParams.APIServer).To(Equal("https://remote.example.com:6443"))
+			Expect(authParams.CA).To(Equal([]byte("ca")))
+			Expect(authParams.SignedCRT).To(BeEmpty())
+			Expect(authParams.TokenConfig).ToNot(BeNil())
+			Expect(authParams.TokenConfig.Token).To(Equal(string(remoteCluster) + "-token-1"))
+			Expect(authParams.TokenConfig.ExpirationTimestamp.Sub(authParams.TokenConfig.IssuedAt.Time)).To(Equal(time.Hour))
+
+			authParams, err = idProvider.ForgeAuthParams(ctx, options)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(authParams.TokenConfig.Token).To(Equal(string(remoteCluster) + "-token-1"))
+			Expect(issuer.issued).To(Equal(1))
+		})
+
+		It("should refresh a token approaching its expiration", func() {
+			_, err := idProvider.ForgeAuthParams(ctx, options)
+			Expect(err).ToNot(HaveOccurred())
+			setIssuedAt(time.Now().Add(-45 * time.Minute))
+
+			authParams, err := idProvider.ForgeAuthParams(ctx, options)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(authParams.TokenConfig.Token).To(Equal(string(remoteCluster) + "-token-2"))
+		})
+
+		It("should renew a token only if not just issued", func() {
+			options.IsUpdate = true
+			_, err := idProvider.ForgeAuthParams(ctx, options)
+			Expect(err).ToNot(HaveOccurred())
+
+			authParams, err := idProvider.ForgeAuthParams(ctx, options)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(authParams.TokenConfig.Token).To(Equal(string(remoteCluster) + "-token-1"))
+
+			setIssuedAt(time.Now().Add(-10 * time.Minute))
+			authParams, err = idProvider.ForgeAuthParams(ctx, options)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(authParams.TokenConfig.Token).To(Equal(string(remoteCluster) + "-token-2"))
+		})
+	})
+
+	Context("ServiceAccount token issuer", func() {
+		It("should request a token for a ServiceAccount bound to the same roles of the remote cluster", func() {
+			selector := map[string]string{consts.RemoteClusterID: string(remoteCluster)}
+			Expect(cl.Create(ctx, &rbacv1.RoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "tenant", Namespace: tenantNamespace, Labels: selector},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: string(remoteCluster)}},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "tenant"},
+			})).To(Succeed())
+			Expect(cl.Create(ctx, &rbacv1.ClusterRoleBinding{
+				ObjectMeta: metav1.ObjectMeta{Name: "slices", Labels: selector},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: string(remoteCluster)}},
+				RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "slices"},
+			})).To(Succeed())
+
+			expiration := metav1.NewTime(time.Now().Add(time.Hour).Truncate(time.Second))
+			clientset := fake.NewSimpleClientset()
+			clientset.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if action.GetSubresource() != "token" {
+					return false, nil, nil
+				}
+				tr := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenRequest).DeepCopy()
+				Expect(tr.Spec.ExpirationSeconds).To(HaveValue(BeEquivalentTo(3600)))
+				tr.Status = authenticationv1.TokenRequestStatus{Token: "sa-token", ExpirationTimestamp: expiration}
+				return true, tr, nil
+			})
+
+			resp, err := NewServiceAccountTokenIssuer(cl, clientset).IssueToken(ctx, &TokenIssueRequest{
+				Cluster:         remoteCluster,
+				TenantNamespace: tenantNamespace,
+				Name:            remoteIdentitySA,
+				Username:        string(remoteCluster),
+				Groups:          []string{"liqo.io"},
+				Expiration:      time.Hour,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.Token).To(Equal("sa-token"))
+			Expect(resp.ExpirationTimestamp).To(BeTemporally("==", expiration.Time))
+
+			var sa corev1.ServiceAccount
+			Expect(cl.Get(ctx, client.ObjectKey{Name: remoteIdentitySA, Namespace: tenantNamespace}, &sa)).To(Succeed())
+			Expect(sa.Labels).To(HaveKeyWithValue(authentication.TokenIdentityLabel, "true"))
+			Expect(sa.Annotations).To(HaveKeyWithValue(authentication.TokenIdentityUserAnnotation, string(remoteCluster)))
+			Expect(sa.Annotations).To(HaveKeyWithValue(authentication.TokenIdentityGroupsAnnotation, "liqo.io"))
+
+			saSubject := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: remoteIdentitySA, Namespace: tenantNamespace}
+			var roleBinding rbacv1.RoleBinding
+			Expect(cl.Get(ctx, client.ObjectKey{Name: "tenant", Namespace: tenantNamespace}, &roleBinding)).To(Succeed())
+			Expect(roleBinding.Subjects).To(ContainElement(saSubject))
+
+			// The ServiceAccount stands for a different group, hence it is not granted the corresponding permissions.
+			var clusterRoleBinding rbacv1.ClusterRoleBinding
+			Expect(cl.Get(ctx, client.ObjectKey{Name: "slices"}, &clusterRoleBinding)).To(Succeed())
+			Expect(clusterRoleBinding.Subjects).ToNot(ContainElement(saSubject))
+
+			var clusterRoles rbacv1.ClusterRoleList
+			Expect(cl.List(ctx, &clusterRoles)).To(Succeed())
+			Expect(clusterRoles.Items).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,178 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identitymanager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	responsetypes "github.com/liqotech/liqo/pkg/identityManager/responseTypes"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
+	"github.com/liqotech/liqo/pkg/utils/resource"
+)
+
+// TokenIssuer issues the bearer tokens handed out to remote clusters by the token identity provider.
+// The only implementation relies on the TokenRequest API of a dedicated ServiceAccount, while external
+// (e.g., OIDC) issuers are currently not supported.
+type TokenIssuer interface {
+	IssueToken(ctx context.Context, request *TokenIssueRequest) (*responsetypes.TokenIdentityResponse, error)
+}
+
+// TokenIssueRequest contains the parameters of a token to be issued for a remote cluster.
+type TokenIssueRequest struct {
+	Cluster         liqov1beta1.ClusterID
+	TenantNamespace string
+	// Name identifies the issued identity within the tenant namespace.
+	Name string
+	// Username and Groups are the identity the remote cluster stands for, matching the subject
+	// of the certificates issued by the certificate identity provider.
+	Username   string
+	Groups     []string
+	Expiration time.Duration
+}
+
+var _ TokenIssuer = &serviceAccountTokenIssuer{}
+
+type serviceAccountTokenIssuer struct {
+	cl        client.Client
+	k8sClient kubernetes.Interface
+}
+
+// NewServiceAccountTokenIssuer returns a TokenIssuer requesting tokens for a dedicated ServiceAccount
+// created in the tenant namespace. The ServiceAccount is bound to the same roles of the remote cluster
+// user and groups, so that the permissions granted to the remote cluster apply unchanged.
+func NewServiceAccountTokenIssuer(cl client.Client, k8sClient kubernetes.Interface) TokenIssuer {
+	return &serviceAccountTokenIssuer{
+		cl:        cl,
+		k8sClient: k8sClient,
+	}
+}
+
+// IssueToken ensures the ServiceAccount associated with the request, and requests a new token for it.
+func (issuer *serviceAccountTokenIssuer) IssueToken(ctx context.Context,
+	request *TokenIssueRequest) (*responsetypes.TokenIdentityResponse, error) {
+	sa, err := issuer.ensureServiceAccount(ctx, request)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	if err := issuer.ensureBindings(ctx, request, sa); err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: ptr.To(int64(request.Expiration.Seconds())),
+		},
+	}
+	tokenRequest, err = issuer.k8sClient.CoreV1().ServiceAccounts(sa.Namespace).CreateToken(ctx,
+		sa.Name, tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		klog.Errorf("Unable to request a token for ServiceAccount %s/%s: %v", sa.Namespace, sa.Name, err)
+		return nil, err
+	}
+
+	return &responsetypes.TokenIdentityResponse{
+		Token:               tokenRequest.Status.Token,
+		IssuedAt:            time.Now(),
+		ExpirationTimestamp: tokenRequest.Status.ExpirationTimestamp.Time,
+	}, nil
+}
+
+func (issuer *serviceAccountTokenIssuer) ensureServiceAccount(ctx context.Context,
+	request *TokenIssueRequest) (*corev1.ServiceAccount, error) {
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      request.Name,
+			Namespace: request.TenantNamespace,
+		},
+	}
+
+	_, err := resource.CreateOrUpdate(ctx, issuer.cl, sa, func() error {
+		if sa.Labels == nil {
+			sa.Labels = map[string]string{}
+		}
+		sa.Labels[consts.RemoteClusterID] = string(request.Cluster)
+		sa.Labels[consts.K8sAppManagedByKey] = consts.LiqoAppLabelValue
+		sa.Labels[authentication.TokenIdentityLabel] = "true"
+
+		if sa.Annotations == nil {
+			sa.Annotations = map[string]string{}
+		}
+		sa.Annotations[authentication.TokenIdentityUserAnnotation] = request.Username
+		sa.Annotations[authentication.TokenIdentityGroupsAnnotation] = strings.Join(request.Groups, ",")
+		return nil
+	})
+	return sa, err
+}
+
+// ensureBindings adds the ServiceAccount to the subjects of the existing RoleBindings and ClusterRoleBindings granting permissions
+// to the remote cluster user and groups. The bindings created afterwards include the ServiceAccount since their creation.
+func (issuer *serviceAccountTokenIssuer) ensureBindings(ctx context.Context,
+	request *TokenIssueRequest, sa *corev1.ServiceAccount) error {
+	selector := client.MatchingLabels{consts.RemoteClusterID: string(request.Cluster)}
+	subject := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: sa.Name, Namespace: sa.Namespace}
+	matches := func(subjects []rbacv1.Subject) bool {
+		return len(authentication.TokenIdentitySubjects([]corev1.ServiceAccount{*sa}, subjects)) > 0 &&
+			!slices.Contains(subjects, subject)
+	}
+
+	var roleBindings rbacv1.RoleBindingList
+	if err := issuer.cl.List(ctx, &roleBindings, selector); err != nil {
+		return fmt.Errorf("unable to list the RoleBindings of cluster %q: %w", request.Cluster, err)
+	}
+	for i := range roleBindings.Items {
+		binding := &roleBindings.Items[i]
+		if !matches(binding.Subjects) {
+			continue
+		}
+		binding.Subjects = append(binding.Subjects, subject)
+		if err := issuer.cl.Update(ctx, binding); err != nil {
+			return fmt.Errorf("unable to bind ServiceAccount %q through RoleBinding %q: %w", klog.KObj(sa), klog.KObj(binding), err)
+		}
+	}
+
+	var clusterRoleBindings rbacv1.ClusterRoleBindingList
+	if err := issuer.cl.List(ctx, &clusterRoleBindings, selector); err != nil {
+		return fmt.Errorf("unable to list the ClusterRoleBindings of cluster %q: %w", request.Cluster, err)
+	}
+	for i := range clusterRoleBindings.Items {
+		binding := &clusterRoleBindings.Items[i]
+		if !matches(binding.Subjects) {
+			continue
+		}
+		binding.Subjects = append(binding.Subjects, subject)
+		if err := issuer.cl.Update(ctx, binding); err != nil {
+			return fmt.Errorf("unable to bind ServiceAccount %q through ClusterRoleBinding %q: %w", klog.KObj(sa), binding.Name, err)
+		}
+	}
+	return nil
+}
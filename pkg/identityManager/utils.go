@@ -16,10 +16,13 @@ package identitymanager
 
 import (
 	"context"
+	"fmt"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
 	responsetypes "github.com/liqotech/liqo/pkg/identityManager/responseTypes"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
 )
 
 // EnsureCertificate ensures that the certificate is present with the identity provider.
@@ -37,3 +40,19 @@ func EnsureCertificate(ctx context.Context, idp IdentityProvider, options *Signi
 
 	return resp, nil
 }
+
+// identitySubject returns the username and the organization the remote cluster is granted, depending on the identity type.
+func identitySubject(options *SigningRequestOptions) (username, organization string, err error) {
+	switch options.IdentityType {
+	case authv1beta1.ControlPlaneIdentityType:
+		return authentication.CommonNameControlPlaneCSR(options.Cluster), authentication.OrganizationControlPlaneCSR(), nil
+	case authv1beta1.ResourceSliceIdentityType:
+		if options.ResourceSlice == nil {
+			return "", "", fmt.Errorf("resource slice is nil")
+		}
+		return authentication.CommonNameResourceSliceCSR(options.ResourceSlice),
+			authentication.OrganizationResourceSliceCSR(options.ResourceSlice), nil
+	default:
+		return "", "", fmt.Errorf("identity type %v not supported", options.IdentityType)
+	}
+}
@@ -16,6 +16,7 @@ package authentication
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/sha256"
@@ -27,6 +28,7 @@ import (
 
 	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
 	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	csrutil "github.com/liqotech/liqo/pkg/utils/csr"
 )
 
 // CSRChecker is a function that checks a CSR.
@@ -69,7 +71,8 @@ func GenerateCSRForControlPlane(key ed25519.PrivateKey, clusterID liqov1beta1.Cl
 }
 
 // GenerateCSRForPeerUser generates a new CSR given a private key and the clusterID from which the peering will start.
-func GenerateCSRForPeerUser(key ed25519.PrivateKey, clusterID liqov1beta1.ClusterID) (csrBytes []byte, userCN string, err error) {
+// Differently from the other identities, the key is not bound to the one of the cluster, hence any supported algorithm can be used.
+func GenerateCSRForPeerUser(key crypto.Signer, clusterID liqov1beta1.ClusterID) (csrBytes []byte, userCN string, err error) {
 	userCN, err = commonNamePeerUser(clusterID)
 	if err != nil {
 		return nil, "", fmt.Errorf("unable to generate user CN: %w", err)
@@ -187,7 +190,12 @@ func checkCSR(csr, publicKey []byte, checkPublicKey bool, commonName, organizati
 	return nil
 }
 
-func generateCSR(key ed25519.PrivateKey, commonName, organization string) (csrBytes []byte, err error) {
+func generateCSR(key crypto.Signer, commonName, organization string) (csrBytes []byte, err error) {
+	signatureAlgorithm, err := csrutil.SignatureAlgorithm(key)
+	if err != nil {
+		return nil, err
+	}
+
 	asn1Subj, err := asn1.Marshal(pkix.Name{CommonName: commonName, Organization: []string{organization}}.ToRDNSequence())
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal subject information: %w", err)
@@ -195,7 +203,7 @@ func generateCSR(key ed25519.PrivateKey, commonName, organization string) (csrBy
 
 	template := x509.CertificateRequest{
 		RawSubject:         asn1Subj,
-		SignatureAlgorithm: x509.PureEd25519,
+		SignatureAlgorithm: signatureAlgorithm,
 	}
 
 	csrBytes, err = x509.CreateCertificateRequest(rand.Reader, &template, key)
@@ -59,9 +59,16 @@ func MutateKubeconfigSecret(secret *corev1.Secret, identity *authv1beta1.Identit
 		secret.Annotations[consts.RemoteTenantNamespaceAnnotKey] = *namespace
 	}
 
-	kubeconfig, err := kubeconfigutils.GenerateKubeconfig(identity.Name, string(identity.Spec.ClusterID),
-		identity.Spec.AuthParams.APIServer, identity.Spec.AuthParams.CA, identity.Spec.AuthParams.SignedCRT, clientKey,
-		identity.Spec.AuthParams.ProxyURL, namespace)
+	var kubeconfig []byte
+	var err error
+	if tokenConfig := identity.Spec.AuthParams.TokenConfig; tokenConfig != nil {
+		kubeconfig, err = kubeconfigutils.GenerateTokenKubeconfig(identity.Name, string(identity.Spec.ClusterID),
+			identity.Spec.AuthParams.APIServer, identity.Spec.AuthParams.CA, tokenConfig.Token, identity.Spec.AuthParams.ProxyURL, namespace)
+	} else {
+		kubeconfig, err = kubeconfigutils.GenerateKubeconfig(identity.Name, string(identity.Spec.ClusterID),
+			identity.Spec.AuthParams.APIServer, identity.Spec.AuthParams.CA, identity.Spec.AuthParams.SignedCRT, clientKey,
+			identity.Spec.AuthParams.ProxyURL, namespace)
+	}
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,61 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leasecontroller
+
+import (
+	"fmt"
+	"time"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+)
+
+// leaseState describes the state of a lease at a given time.
+type leaseState struct {
+	status  authv1beta1.ResourceSliceConditionStatus
+	reason  string
+	message string
+	// requeueAfter is the time after which the state of the lease changes (zero if it does not change anymore).
+	requeueAfter time.Duration
+}
+
+// getLeaseState returns the state of a lease expiring at the given time, warning about the expiration
+// when less than the warning period is left.
+func getLeaseState(expiration, now time.Time, warningPeriod time.Duration) *leaseState {
+	remaining := expiration.Sub(now)
+	at := expiration.Format(time.RFC3339)
+
+	switch {
+	case remaining <= 0:
+		return &leaseState{
+			status:  authv1beta1.ResourceSliceConditionExpired,
+			reason:  "LeaseExpired",
+			message: fmt.Sprintf("The lease expired at %s: the resources are cordoned, drained and then removed", at),
+		}
+	case remaining <= warningPeriod:
+		return &leaseState{
+			status:       authv1beta1.ResourceSliceConditionExpiring,
+			reason:       "LeaseExpiring",
+			message:      fmt.Sprintf("The lease expires at %s: renew it to preserve the resources", at),
+			requeueAfter: remaining,
+		}
+	default:
+		return &leaseState{
+			status:       authv1beta1.ResourceSliceConditionAccepted,
+			reason:       "LeaseActive",
+			message:      fmt.Sprintf("The lease expires at %s", at),
+			requeueAfter: remaining - warningPeriod,
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leasecontroller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
+)
+
+const (
+	namespace  = "liqo-tenant-consumer"
+	consumerID = liqov1beta1.ClusterID("consumer")
+)
+
+var _ = Describe("Lease", func() {
+	var (
+		ctx    context.Context
+		scheme *runtime.Scheme
+		now    time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Now()
+		scheme = runtime.NewScheme()
+		Expect(authv1beta1.AddToScheme(scheme)).To(Succeed())
+		Expect(offloadingv1beta1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	newTenant := func(expiration *metav1.Time) *authv1beta1.Tenant {
+		return &authv1beta1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{Name: "consumer", Namespace: namespace,
+				Labels: map[string]string{consts.RemoteClusterID: string(consumerID)}},
+			Spec: authv1beta1.TenantSpec{
+				ClusterID:       consumerID,
+				TenantCondition: authv1beta1.TenantConditionActive,
+				LeaseExpiration: expiration,
+			},
+		}
+	}
+
+	newResourceSlice := func(expiration *metav1.Time) *authv1beta1.ResourceSlice {
+		id := consumerID
+		return &authv1beta1.ResourceSlice{
+			ObjectMeta: metav1.ObjectMeta{Name: "slice", Namespace: namespace},
+			Spec:       authv1beta1.ResourceSliceSpec{ConsumerClusterID: &id},
+			Status:     authv1beta1.ResourceSliceStatus{LeaseExpiration: expiration},
+		}
+	}
+
+	DescribeTable("the getLeaseState function",
+		func(remaining time.Duration, status authv1beta1.ResourceSliceConditionStatus, requeueAfter time.Duration) {
+			state := getLeaseState(now.Add(remaining), now, 24*time.Hour)
+			Expect(state.status).To(Equal(status))
+			Expect(state.requeueAfter).To(Equal(requeueAfter))
+		},
+		Entry("active lease", 72*time.Hour, authv1beta1.ResourceSliceConditionAccepted, 48*time.Hour),
+		Entry("expiring lease", time.Hour, authv1beta1.ResourceSliceConditionExpiring, time.Hour),
+		Entry("expired lease", -time.Hour, authv1beta1.ResourceSliceConditionExpired, time.Duration(0)),
+	)
+
+	Describe("the lease helpers", func() {
+		It("should honor the precedence of the lease durations", func() {
+			rs, tenant := newResourceSlice(nil), newTenant(nil)
+			Expect(authentication.LeaseDuration(rs, tenant, time.Hour)).To(Equal(time.Hour))
+
+			tenant.Annotations = map[string]string{consts.LeaseDurationAnnotation: "2h"}
+			Expect(authentication.LeaseDuration(rs, tenant, time.Hour)).To(Equal(2 * time.Hour))
+
+			rs.Annotations = map[string]string{consts.LeaseDurationAnnotation: "3h"}
+			Expect(authentication.LeaseDuration(rs, tenant, time.Hour)).To(Equal(3 * time.Hour))
+
+			rs.Annotations[consts.LeaseDurationAnnotation] = "invalid"
+			_, err := authentication.LeaseDuration(rs, tenant, time.Hour)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should bound the lease by the one of the tenant", func() {
+			Expect(authentication.LeaseExpiration(now, 0, newTenant(nil))).To(BeNil())
+			Expect(authentication.LeaseExpiration(now, time.Hour, newTenant(nil)).Time).To(Equal(now.Add(time.Hour)))
+
+			tenantExpiration := &metav1.Time{Time: now.Add(time.Minute)}
+			Expect(authentication.LeaseExpiration(now, time.Hour, newTenant(tenantExpiration))).To(Equal(tenantExpiration))
+			Expect(authentication.LeaseExpiration(now, 0, newTenant(tenantExpiration))).To(Equal(tenantExpiration))
+		})
+	})
+
+	Describe("the RemoteLeaseReconciler", func() {
+		reconcile := func(rs *authv1beta1.ResourceSlice, tenant *authv1beta1.Tenant) (*authv1beta1.ResourceSlice, ctrl.Result) {
+			cl := fake.NewClientBuilder().WithScheme(scheme).
+				WithStatusSubresource(&authv1beta1.ResourceSlice{}).
+				WithObjects(rs, tenant).Build()
+			r := NewRemoteLeaseReconciler(cl, scheme, record.NewFakeRecorder(10), 24*time.Hour)
+
+			res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(rs)})
+			Expect(err).ToNot(HaveOccurred())
+
+			var updated authv1beta1.ResourceSlice
+			Expect(cl.Get(ctx, client.ObjectKeyFromObject(rs), &updated)).To(Succeed())
+			return &updated, res
+		}
+
+		It("should leave the ResourceSlices without lease untouched", func() {
+			rs, res := reconcile(newResourceSlice(nil), newTenant(nil))
+			Expect(authentication.GetCondition(rs, authv1beta1.ResourceSliceConditionTypeLease)).To(BeNil())
+			Expect(res.RequeueAfter).To(BeZero())
+		})
+
+		It("should warn about the expiring leases", func() {
+			rs, res := reconcile(newResourceSlice(&metav1.Time{Time: now.Add(time.Hour)}), newTenant(nil))
+			cond := authentication.GetCondition(rs, authv1beta1.ResourceSliceConditionTypeLease)
+			Expect(cond).ToNot(BeNil())
+			Expect(cond.Status).To(Equal(authv1beta1.ResourceSliceConditionExpiring))
+			Expect(rs.Annotations).ToNot(HaveKey(consts.CordonLeaseAnnotation))
+			Expect(res.RequeueAfter).To(BeNumerically("~", time.Hour, time.Minute))
+		})
+
+		It("should cordon the resources once the lease of the tenant expires", func() {
+			rs, _ := reconcile(newResourceSlice(&metav1.Time{Time: now.Add(time.Hour)}),
+				newTenant(&metav1.Time{Time: now.Add(-time.Minute)}))
+			cond := authentication.GetCondition(rs, authv1beta1.ResourceSliceConditionTypeLease)
+			Expect(cond).ToNot(BeNil())
+			Expect(cond.Status).To(Equal(authv1beta1.ResourceSliceConditionExpired))
+			Expect(rs.Annotations).To(HaveKeyWithValue(consts.CordonLeaseAnnotation, "true"))
+		})
+	})
+
+	Describe("the LocalLeaseReconciler", func() {
+		It("should drain the virtual node and then delete the ResourceSlice once the lease expires", func() {
+			rs := newResourceSlice(nil)
+			rs.Status.Conditions = []authv1beta1.ResourceSliceCondition{{
+				Type: authv1beta1.ResourceSliceConditionTypeLease, Status: authv1beta1.ResourceSliceConditionExpired,
+			}}
+			// The name of the virtual node differs from the one of the ResourceSlice, which is referenced through the label.
+			vn := &offloadingv1beta1.VirtualNode{ObjectMeta: metav1.ObjectMeta{Name: "custom", Namespace: rs.Namespace,
+				Labels: map[string]string{consts.ResourceSliceNameLabelKey: rs.Name}}}
+			other := &offloadingv1beta1.VirtualNode{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: rs.Namespace,
+				Labels: map[string]string{consts.ResourceSliceNameLabelKey: "other"}}}
+
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(rs, vn, other).Build()
+			r := NewLocalLeaseReconciler(cl, scheme, record.NewFakeRecorder(10))
+			req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(rs)}
+
+			res, err := r.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(res.RequeueAfter).To(Equal(virtualNodeDrainCheckPeriod))
+			Expect(cl.Get(ctx, client.ObjectKeyFromObject(vn), vn)).ToNot(Succeed())
+			Expect(cl.Get(ctx, client.ObjectKeyFromObject(other), other)).To(Succeed())
+			Expect(cl.Get(ctx, req.NamespacedName, rs)).To(Succeed())
+
+			_, err = r.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cl.Get(ctx, req.NamespacedName, rs)).ToNot(Succeed())
+		})
+	})
+
+	Describe("the TenantLeaseReconciler", func() {
+		reconcile := func(tenant *authv1beta1.Tenant) (*authv1beta1.Tenant, ctrl.Result) {
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tenant).Build()
+			r := NewTenantLeaseReconciler(cl, scheme, record.NewFakeRecorder(10), 24*time.Hour)
+
+			res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(tenant)})
+			Expect(err).ToNot(HaveOccurred())
+
+			var updated authv1beta1.Tenant
+			Expect(cl.Get(ctx, client.ObjectKeyFromObject(tenant), &updated)).To(Succeed())
+			return &updated, res
+		}
+
+		It("should requeue the tenants whose lease is active", func() {
+			tenant, res := reconcile(newTenant(&metav1.Time{Time: now.Add(48 * time.Hour)}))
+			Expect(tenant.Spec.TenantCondition).To(Equal(authv1beta1.TenantConditionActive))
+			Expect(res.RequeueAfter).To(BeNumerically("~", 24*time.Hour, time.Minute))
+		})
+
+		It("should drain the tenants whose lease is expired", func() {
+			tenant, _ := reconcile(newTenant(&metav1.Time{Time: now.Add(-time.Minute)}))
+			Expect(tenant.Spec.TenantCondition).To(Equal(authv1beta1.TenantConditionDrained))
+			Expect(tenant.Annotations).To(HaveKeyWithValue(consts.DrainedLeaseAnnotation, "true"))
+		})
+
+		It("should unmark the tenants re-activated after being drained because of their lease", func() {
+			tenant := newTenant(&metav1.Time{Time: now.Add(48 * time.Hour)})
+			tenant.Annotations = map[string]string{consts.DrainedLeaseAnnotation: "true"}
+			tenant, _ = reconcile(tenant)
+			Expect(tenant.Spec.TenantCondition).To(Equal(authv1beta1.TenantConditionActive))
+			Expect(tenant.Annotations).ToNot(HaveKey(consts.DrainedLeaseAnnotation))
+		})
+	})
+})
@@ -0,0 +1,159 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leasecontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	klog "k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	"github.com/liqotech/liqo/internal/crdReplicator/reflection"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+)
+
+// virtualNodeDrainCheckPeriod is the period after which the removal of the VirtualNode of an expired ResourceSlice is checked again.
+const virtualNodeDrainCheckPeriod = 10 * time.Second
+
+// NewLocalLeaseReconciler returns a new LocalLeaseReconciler.
+func NewLocalLeaseReconciler(cl client.Client, s *runtime.Scheme, recorder record.EventRecorder) *LocalLeaseReconciler {
+	return &LocalLeaseReconciler{
+		Client: cl,
+		Scheme: s,
+
+		eventRecorder: recorder,
+	}
+}
+
+// LocalLeaseReconciler reacts to the leases of the ResourceSlices of the local cluster, as enforced by the provider:
+// it warns ahead of the expiration, and drains and removes the resources once expired.
+type LocalLeaseReconciler struct {
+	client.Client
+	*runtime.Scheme
+
+	eventRecorder record.EventRecorder
+}
+
+// cluster-role
+// +kubebuilder:rbac:groups=authentication.liqo.io,resources=resourceslices,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=virtualnodes,verbs=get;list;watch;delete
+
+// Reconcile the lease of a local ResourceSlice.
+func (r *LocalLeaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var resourceSlice authv1beta1.ResourceSlice
+	if err := r.Get(ctx, req.NamespacedName, &resourceSlice); err != nil {
+		if kerrors.IsNotFound(err) {
+			klog.V(4).Infof("ResourceSlice %q not found", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("Unable to get ResourceSlice %q: %v", req.NamespacedName, err)
+		return ctrl.Result{}, err
+	}
+
+	if !resourceSlice.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	cond := authentication.GetCondition(&resourceSlice, authv1beta1.ResourceSliceConditionTypeLease)
+	if cond == nil {
+		return ctrl.Result{}, nil
+	}
+
+	switch cond.Status {
+	case authv1beta1.ResourceSliceConditionExpiring:
+		r.eventRecorder.Event(&resourceSlice, corev1.EventTypeWarning, cond.Reason, cond.Message)
+		return ctrl.Result{}, nil
+	case authv1beta1.ResourceSliceConditionExpired:
+		return r.handleExpiredLease(ctx, &resourceSlice)
+	default:
+		return ctrl.Result{}, nil
+	}
+}
+
+// handleExpiredLease removes the resources of a ResourceSlice whose lease is expired: the VirtualNode is deleted first,
+// so that it is cordoned and drained, and the ResourceSlice is deleted once the VirtualNode is gone.
+func (r *LocalLeaseReconciler) handleExpiredLease(ctx context.Context, resourceSlice *authv1beta1.ResourceSlice) (ctrl.Result, error) {
+	key := client.ObjectKeyFromObject(resourceSlice)
+
+	// The VirtualNodes are resolved through the reference to the ResourceSlice, as their names may differ from the ResourceSlice one.
+	virtualNodes, err := getters.ListVirtualNodesByResourceSlice(ctx, r.Client, resourceSlice)
+	if err != nil {
+		klog.Errorf("Unable to get the VirtualNodes of ResourceSlice %q: %v", key, err)
+		return ctrl.Result{}, err
+	}
+
+	if len(virtualNodes) > 0 {
+		for i := range virtualNodes {
+			virtualNode := &virtualNodes[i]
+			if !virtualNode.DeletionTimestamp.IsZero() {
+				continue
+			}
+			if err := r.Delete(ctx, virtualNode); client.IgnoreNotFound(err) != nil {
+				klog.Errorf("Unable to delete the VirtualNode %q of ResourceSlice %q: %v", virtualNode.Name, key, err)
+				return ctrl.Result{}, err
+			}
+			klog.Infof("Lease of ResourceSlice %q expired: draining VirtualNode %q", key, virtualNode.Name)
+			r.eventRecorder.Event(resourceSlice, corev1.EventTypeWarning, "LeaseExpired",
+				fmt.Sprintf("The lease expired: the virtual node %q is being drained", virtualNode.Name))
+		}
+		return ctrl.Result{RequeueAfter: virtualNodeDrainCheckPeriod}, nil
+	}
+
+	if err := r.Delete(ctx, resourceSlice); client.IgnoreNotFound(err) != nil {
+		klog.Errorf("Unable to delete ResourceSlice %q: %v", key, err)
+		return ctrl.Result{}, err
+	}
+	klog.Infof("Lease of ResourceSlice %q expired: ResourceSlice deleted", key)
+	r.eventRecorder.Event(resourceSlice, corev1.EventTypeWarning, "LeaseExpired", "The lease expired: the resourceslice has been deleted")
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LocalLeaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// generate the predicate to filter just the ResourceSlices created by the local cluster checking crdReplicator labels
+	localResSliceFilter, err := predicate.LabelSelectorPredicate(reflection.LocalResourcesLabelSelector())
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlLeaseLocal).
+		For(&authv1beta1.ResourceSlice{}, builder.WithPredicates(localResSliceFilter, withLeaseCondition())).
+		Complete(r)
+}
+
+// withLeaseCondition filters the ResourceSlices whose lease is expiring or expired.
+func withLeaseCondition() predicate.Funcs {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		rs, ok := obj.(*authv1beta1.ResourceSlice)
+		if !ok {
+			return false
+		}
+		cond := authentication.GetCondition(rs, authv1beta1.ResourceSliceConditionTypeLease)
+		return cond != nil && cond.Status != authv1beta1.ResourceSliceConditionAccepted
+	})
+}
@@ -0,0 +1,177 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leasecontroller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	klog "k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	"github.com/liqotech/liqo/internal/crdReplicator/reflection"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
+)
+
+// NewRemoteLeaseReconciler returns a new RemoteLeaseReconciler.
+func NewRemoteLeaseReconciler(cl client.Client, s *runtime.Scheme,
+	recorder record.EventRecorder, warningPeriod time.Duration) *RemoteLeaseReconciler {
+	return &RemoteLeaseReconciler{
+		Client: cl,
+		Scheme: s,
+
+		eventRecorder: recorder,
+		warningPeriod: warningPeriod,
+	}
+}
+
+// RemoteLeaseReconciler enforces the leases of the ResourceSlices of the consumer clusters:
+// it warns ahead of the expiration, and cordons the resources once expired.
+type RemoteLeaseReconciler struct {
+	client.Client
+	*runtime.Scheme
+
+	eventRecorder record.EventRecorder
+	warningPeriod time.Duration
+}
+
+// cluster-role
+// +kubebuilder:rbac:groups=authentication.liqo.io,resources=resourceslices;resourceslices/status,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=authentication.liqo.io,resources=tenants,verbs=get;list;watch
+
+// Reconcile the lease of a replicated ResourceSlice.
+func (r *RemoteLeaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var resourceSlice authv1beta1.ResourceSlice
+	if err := r.Get(ctx, req.NamespacedName, &resourceSlice); err != nil {
+		if kerrors.IsNotFound(err) {
+			klog.V(4).Infof("ResourceSlice %q not found", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("Unable to get ResourceSlice %q: %v", req.NamespacedName, err)
+		return ctrl.Result{}, err
+	}
+
+	if resourceSlice.Spec.ConsumerClusterID == nil || !resourceSlice.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	tenant, err := getters.GetTenantByClusterID(ctx, r.Client, *resourceSlice.Spec.ConsumerClusterID, resourceSlice.Namespace)
+	if err != nil {
+		klog.Errorf("Unable to get the Tenant for the ResourceSlice %q: %s", req.NamespacedName, err)
+		return ctrl.Result{}, err
+	}
+
+	// The lease of the ResourceSlice starts when the resources are granted, and it is bounded by the one of the tenant.
+	expiration := authentication.EffectiveLeaseExpiration(&resourceSlice, tenant)
+	if expiration == nil {
+		return ctrl.Result{}, r.enforceLeaseCordon(ctx, &resourceSlice, false)
+	}
+
+	state := getLeaseState(expiration.Time, time.Now(), r.warningPeriod)
+	switch authentication.EnsureCondition(&resourceSlice, authv1beta1.ResourceSliceConditionTypeLease,
+		state.status, state.reason, state.message) {
+	case controllerutil.OperationResultCreated, controllerutil.OperationResultUpdated:
+		if err := r.Status().Update(ctx, &resourceSlice); err != nil {
+			klog.Errorf("Unable to update the lease of ResourceSlice %q: %v", req.NamespacedName, err)
+			return ctrl.Result{}, err
+		}
+		klog.Infof("ResourceSlice %q: %s", req.NamespacedName, state.message)
+		if state.status != authv1beta1.ResourceSliceConditionAccepted {
+			r.eventRecorder.Event(&resourceSlice, corev1.EventTypeWarning, state.reason, state.message)
+		}
+	default:
+	}
+
+	expired := state.status == authv1beta1.ResourceSliceConditionExpired
+	if err := r.enforceLeaseCordon(ctx, &resourceSlice, expired); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: state.requeueAfter}, nil
+}
+
+// enforceLeaseCordon cordons (or uncordons) the resources of the ResourceSlice, depending on whether its lease is expired.
+func (r *RemoteLeaseReconciler) enforceLeaseCordon(ctx context.Context, resourceSlice *authv1beta1.ResourceSlice, cordoned bool) error {
+	if _, found := resourceSlice.Annotations[consts.CordonLeaseAnnotation]; found == cordoned {
+		return nil
+	}
+
+	if cordoned {
+		if resourceSlice.Annotations == nil {
+			resourceSlice.Annotations = map[string]string{}
+		}
+		resourceSlice.Annotations[consts.CordonLeaseAnnotation] = "true"
+	} else {
+		delete(resourceSlice.Annotations, consts.CordonLeaseAnnotation)
+	}
+
+	if err := r.Update(ctx, resourceSlice); err != nil {
+		klog.Errorf("Unable to update the lease cordon of ResourceSlice %q: %v", client.ObjectKeyFromObject(resourceSlice), err)
+		return err
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RemoteLeaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// generate the predicate to filter just the ResourceSlices created by the remote cluster checking crdReplicator labels
+	remoteResSliceFilter, err := predicate.LabelSelectorPredicate(reflection.ReplicatedResourcesLabelSelector())
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlLeaseRemote).
+		For(&authv1beta1.ResourceSlice{}, builder.WithPredicates(remoteResSliceFilter)).
+		Watches(&authv1beta1.Tenant{}, handler.EnqueueRequestsFromMapFunc(r.resourceSlicesEnquer())).
+		Complete(r)
+}
+
+func (r *RemoteLeaseReconciler) resourceSlicesEnquer() func(ctx context.Context, obj client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		tenant, ok := obj.(*authv1beta1.Tenant)
+		if !ok || tenant.Spec.ClusterID == "" {
+			return nil
+		}
+
+		resSlices, err := getters.ListResourceSlicesByLabel(ctx, r.Client, tenant.Namespace,
+			liqolabels.RemoteLabelSelectorForCluster(string(tenant.Spec.ClusterID)))
+		if err != nil {
+			klog.Errorf("Failed to retrieve ResourceSlices for Tenant %q: %v", tenant.Name, err)
+			return nil
+		}
+
+		reqs := make([]reconcile.Request, len(resSlices))
+		for i := range resSlices {
+			reqs[i] = reconcile.Request{NamespacedName: types.NamespacedName{Name: resSlices[i].Name, Namespace: resSlices[i].Namespace}}
+		}
+		return reqs
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leasecontroller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	klog "k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+// NewTenantLeaseReconciler returns a new TenantLeaseReconciler.
+func NewTenantLeaseReconciler(cl client.Client, s *runtime.Scheme,
+	recorder record.EventRecorder, warningPeriod time.Duration) *TenantLeaseReconciler {
+	return &TenantLeaseReconciler{
+		Client: cl,
+		Scheme: s,
+
+		eventRecorder: recorder,
+		warningPeriod: warningPeriod,
+	}
+}
+
+// TenantLeaseReconciler enforces the leases of the tenants: it warns ahead of the expiration,
+// and drains the tenant once expired.
+type TenantLeaseReconciler struct {
+	client.Client
+	*runtime.Scheme
+
+	eventRecorder record.EventRecorder
+	warningPeriod time.Duration
+}
+
+// cluster-role
+// +kubebuilder:rbac:groups=authentication.liqo.io,resources=tenants,verbs=get;list;watch;update;patch
+
+// Reconcile the lease of a tenant.
+func (r *TenantLeaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var tenant authv1beta1.Tenant
+	if err := r.Get(ctx, req.NamespacedName, &tenant); err != nil {
+		if kerrors.IsNotFound(err) {
+			klog.V(4).Infof("Tenant %q not found", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("Unable to get Tenant %q: %v", req.NamespacedName, err)
+		return ctrl.Result{}, err
+	}
+
+	if !tenant.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// The tenant has been re-activated in the meanwhile, hence it is not drained because of its lease anymore.
+	if _, found := tenant.Annotations[consts.DrainedLeaseAnnotation]; found && tenant.Spec.TenantCondition != authv1beta1.TenantConditionDrained {
+		delete(tenant.Annotations, consts.DrainedLeaseAnnotation)
+		if err := r.Update(ctx, &tenant); err != nil {
+			klog.Errorf("Unable to update Tenant %q: %v", req.NamespacedName, err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	if tenant.Spec.LeaseExpiration == nil {
+		return ctrl.Result{}, nil
+	}
+
+	switch tenant.Spec.TenantCondition {
+	case authv1beta1.TenantConditionDrained, authv1beta1.TenantConditionRevoked:
+		// The tenant can't consume resources anymore, hence there is nothing to enforce.
+		return ctrl.Result{}, nil
+	default:
+	}
+
+	state := getLeaseState(tenant.Spec.LeaseExpiration.Time, time.Now(), r.warningPeriod)
+	switch state.status {
+	case authv1beta1.ResourceSliceConditionExpired:
+		tenant.Spec.TenantCondition = authv1beta1.TenantConditionDrained
+		// Mark the tenant as drained because of its lease, to re-activate it once the lease is renewed.
+		if tenant.Annotations == nil {
+			tenant.Annotations = map[string]string{}
+		}
+		tenant.Annotations[consts.DrainedLeaseAnnotation] = "true"
+		if err := r.Update(ctx, &tenant); err != nil {
+			klog.Errorf("Unable to drain Tenant %q: %v", req.NamespacedName, err)
+			return ctrl.Result{}, err
+		}
+		klog.Infof("Lease of Tenant %q expired: tenant drained", req.NamespacedName)
+		r.eventRecorder.Event(&tenant, corev1.EventTypeWarning, state.reason, state.message)
+	case authv1beta1.ResourceSliceConditionExpiring:
+		r.eventRecorder.Event(&tenant, corev1.EventTypeWarning, state.reason, state.message)
+	default:
+	}
+
+	return ctrl.Result{RequeueAfter: state.requeueAfter}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TenantLeaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlLeaseTenant).
+		For(&authv1beta1.Tenant{}).
+		Complete(r)
+}
@@ -0,0 +1,75 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authentication
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+// LeaseDuration returns the duration of the lease of the resources granted through the given ResourceSlice, as set by the
+// annotation of the ResourceSlice, of the Tenant of its consumer, or the default one, in order of precedence.
+// Zero means that the lease is not time-bounded (unless the lease of the tenant is).
+func LeaseDuration(resourceSlice *authv1beta1.ResourceSlice, tenant *authv1beta1.Tenant, defaultDuration time.Duration) (time.Duration, error) {
+	for _, annotations := range []map[string]string{resourceSlice.GetAnnotations(), tenant.GetAnnotations()} {
+		if value, found := annotations[consts.LeaseDurationAnnotation]; found {
+			duration, err := time.ParseDuration(value)
+			if err != nil || duration < 0 {
+				return 0, fmt.Errorf("invalid %q annotation %q", consts.LeaseDurationAnnotation, value)
+			}
+			return duration, nil
+		}
+	}
+	return defaultDuration, nil
+}
+
+// TenantLeaseDuration returns the maximum duration of a renewal of the lease of the given Tenant, as set by its annotation
+// or the default one, in order of precedence. Zero means that the renewal is not bounded.
+func TenantLeaseDuration(tenant *authv1beta1.Tenant, defaultDuration time.Duration) (time.Duration, error) {
+	return LeaseDuration(&authv1beta1.ResourceSlice{}, tenant, defaultDuration)
+}
+
+// LeaseExpiration returns the expiration of a lease of the given duration starting at the given time,
+// bounded by the lease of the tenant, if any. Nil means that the lease is not time-bounded.
+func LeaseExpiration(start time.Time, duration time.Duration, tenant *authv1beta1.Tenant) *metav1.Time {
+	var expiration *metav1.Time
+	if duration > 0 {
+		expiration = &metav1.Time{Time: start.Add(duration)}
+	}
+	return earliest(expiration, tenant.Spec.LeaseExpiration)
+}
+
+// EffectiveLeaseExpiration returns the expiration of the lease of the given ResourceSlice, bounded by the lease of the tenant.
+func EffectiveLeaseExpiration(resourceSlice *authv1beta1.ResourceSlice, tenant *authv1beta1.Tenant) *metav1.Time {
+	return earliest(resourceSlice.Status.LeaseExpiration, tenant.Spec.LeaseExpiration)
+}
+
+func earliest(a, b *metav1.Time) *metav1.Time {
+	switch {
+	case a == nil && b == nil:
+		return nil
+	case a == nil:
+		return b.DeepCopy()
+	case b == nil || a.Before(b):
+		return a.DeepCopy()
+	default:
+		return b.DeepCopy()
+	}
+}
@@ -76,7 +76,7 @@ func NewLocalRenewerReconciler(cl client.Client, s *runtime.Scheme,
 // The function first retrieves the Identity object and checks if it should be
 // renewed using the shouldRenew function. Renewal can be triggered either by
 // the presence of a "liqo.io/renew" annotation set to true, or by the certificate
-// approaching its expiration time (2/3 of its lifetime).
+// approaching its expiration time (by default, 2/3 of its lifetime).
 //
 // If the Identity does not need renewal, it removes the current Renew object
 // if present and returns a requeue time calculated by the shouldRenew function.
@@ -138,10 +138,11 @@ func (r *LocalRenewerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 // If the annotation is present, it immediately triggers renewal regardless of certificate status.
 //
 // Otherwise, it retrieves the kubeconfig secret referenced by the Identity and checks the
-// signed certificate within. The function calculates the certificate's lifetime
-// and determines if a renewal is required based on the 2/3 life rule.
+// signed certificate (or the bearer token) within. The function calculates the credentials'
+// renewal time, according to the identity policy advertised by the provider (by default,
+// the 2/3 life rule), records it in the Identity status and determines if a renewal is required.
 // If the certificate is not near expiration, it calculates the next check time
-// as the remaining time until the 2/3 point plus a 10% buffer.
+// as the remaining time until the renewal time plus a 10% buffer.
 // If the certificate is near expiration, it checks if a Renew object already exists.
 //
 // Args:
@@ -164,33 +165,23 @@ func (r *LocalRenewerReconciler) shouldRenew(ctx context.Context, identity *auth
 		return false, requeueIn, fmt.Errorf("identity %s/%s has no kubeconfig secret reference", identity.Namespace, identity.Name)
 	}
 
-	// Get the signed certificate from the kubeconfig
-	signedCrt := identity.Spec.AuthParams.SignedCRT
-	if len(signedCrt) == 0 {
-		return false, requeueIn, fmt.Errorf("identity %s/%s has no signed certificate", identity.Namespace, identity.Name)
-	}
-
-	// Parse the certificate to get its expiration time
-	block, _ := pem.Decode(signedCrt)
-	if block == nil {
-		return false, requeueIn, fmt.Errorf("failed to decode PEM block containing certificate")
-	}
-
-	cert, err := x509.ParseCertificate(block.Bytes)
+	notBefore, notAfter, err := credentialsValidity(&identity.Spec.AuthParams)
 	if err != nil {
-		return false, requeueIn, fmt.Errorf("failed to parse certificate: %w", err)
+		return false, requeueIn, fmt.Errorf("identity %s/%s: %w", identity.Namespace, identity.Name, err)
 	}
 
-	// Calculate if we need to renew based on 2/3 life rule
-	lifetime := cert.NotAfter.Sub(cert.NotBefore)
-	twoThirdsPoint := cert.NotAfter.Add(-lifetime / 3)
+	// Calculate if we need to renew based on the policy of the provider (by default, the 2/3 life rule)
+	renewalTime := identity.Spec.AuthParams.IdentityPolicy.RenewalTime(notBefore, notAfter)
+	if err := r.updateNextRotationTime(ctx, identity, renewalTime); err != nil {
+		return false, requeueIn, err
+	}
 
-	if time.Now().Before(twoThirdsPoint) {
-		// Calculate requeue time as the remaining time until the 2/3 point of the certificate expiration time + 10%
-		timeUntilTwoThirds := time.Until(twoThirdsPoint)
-		requeueIn = timeUntilTwoThirds * 11 / 10
+	if time.Now().Before(renewalTime) {
+		// Calculate requeue time as the remaining time until the renewal time + 10%
+		timeUntilRenewal := time.Until(renewalTime)
+		requeueIn = timeUntilRenewal * 11 / 10
 
-		klog.V(4).Infof("Certificate not ready for renewal, will check again in %v", requeueIn)
+		klog.V(4).Infof("Credentials not ready for renewal, will check again in %v", requeueIn)
 		return false, requeueIn, nil
 	}
 
@@ -208,6 +199,48 @@ func (r *LocalRenewerReconciler) shouldRenew(ctx context.Context, identity *auth
 	return true, requeueIn, nil // No existing Renew, proceed with creation
 }
 
+// updateNextRotationTime records the given renewal time of the credentials in the Identity status, if changed.
+func (r *LocalRenewerReconciler) updateNextRotationTime(ctx context.Context, identity *authv1beta1.Identity, renewalTime time.Time) error {
+	next := metav1.NewTime(renewalTime.Truncate(time.Second))
+	if identity.Status.NextRotationTime != nil && identity.Status.NextRotationTime.Equal(&next) {
+		return nil
+	}
+
+	original := identity.DeepCopy()
+	identity.Status.NextRotationTime = &next
+	if err := r.Status().Patch(ctx, identity, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("unable to update the next rotation time of identity %s/%s: %w", identity.Namespace, identity.Name, err)
+	}
+	return nil
+}
+
+// credentialsValidity returns the validity period of the credentials contained in the given AuthParams,
+// either a bearer token along with its refresh metadata or a signed certificate.
+func credentialsValidity(authParams *authv1beta1.AuthParams) (notBefore, notAfter time.Time, err error) {
+	if authParams.TokenConfig != nil {
+		return authParams.TokenConfig.IssuedAt.Time, authParams.TokenConfig.ExpirationTimestamp.Time, nil
+	}
+
+	// Get the signed certificate from the kubeconfig
+	signedCrt := authParams.SignedCRT
+	if len(signedCrt) == 0 {
+		return notBefore, notAfter, fmt.Errorf("no signed certificate")
+	}
+
+	// Parse the certificate to get its expiration time
+	block, _ := pem.Decode(signedCrt)
+	if block == nil {
+		return notBefore, notAfter, fmt.Errorf("failed to decode PEM block containing certificate")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return notBefore, notAfter, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	return cert.NotBefore, cert.NotAfter, nil
+}
+
 // enforceRenew enforces the creation of a Renew object for the given Identity.
 //
 // The function creates a Renew object with the same name and namespace as the given Identity.
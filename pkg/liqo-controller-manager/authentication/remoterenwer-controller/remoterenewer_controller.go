@@ -17,7 +17,9 @@ package remoterenwercontroller
 import (
 	"context"
 	"fmt"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
@@ -30,6 +32,7 @@ import (
 	"github.com/liqotech/liqo/internal/crdReplicator/reflection"
 	"github.com/liqotech/liqo/pkg/consts"
 	identitymanager "github.com/liqotech/liqo/pkg/identityManager"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
 	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
 	"github.com/liqotech/liqo/pkg/utils/events"
 	"github.com/liqotech/liqo/pkg/utils/getters"
@@ -45,6 +48,7 @@ type RemoteRenewerReconciler struct {
 	APIServerAddressOverride string
 	CAOverride               []byte
 	TrustedCA                bool
+	LeaseDuration            time.Duration
 	recorder                 record.EventRecorder
 }
 
@@ -53,7 +57,7 @@ func NewRemoteRenewerReconciler(cl client.Client, s *runtime.Scheme,
 	identityProvider identitymanager.IdentityProvider,
 	namespaceManager tenantnamespace.Manager,
 	apiServerAddressOverride string, caOverride []byte, trustedCA bool,
-	recorder record.EventRecorder) *RemoteRenewerReconciler {
+	leaseDuration time.Duration, recorder record.EventRecorder) *RemoteRenewerReconciler {
 	return &RemoteRenewerReconciler{
 		Client: cl,
 		Scheme: s,
@@ -63,6 +67,7 @@ func NewRemoteRenewerReconciler(cl client.Client, s *runtime.Scheme,
 		APIServerAddressOverride: apiServerAddressOverride,
 		CAOverride:               caOverride,
 		TrustedCA:                trustedCA,
+		LeaseDuration:            leaseDuration,
 		recorder:                 recorder,
 	}
 }
@@ -79,6 +84,8 @@ func NewRemoteRenewerReconciler(cl client.Client, s *runtime.Scheme,
 //
 // The function first retrieves the Renew object and the related tenant and namespace.
 // If the namespace of the Renew object doesn't match with the tenant namespace, it skips the reconciliation.
+// If the Renew requests the renewal of the lease of a resource slice, it calls the handleLeaseRenew function,
+// while if no resource slice is referenced, it calls the handleTenantLeaseRenew function.
 // Then, it calls the handleRenew function to generate the certificate for the renew and update the Renew object.
 // Finally, if the renew is for a control plane or a resource slice, it calls the updateTenantStatusOnRenew or
 // updateResourceSliceStatusOnRenew function to update the tenant or resource slice status.
@@ -110,6 +117,13 @@ func (r *RemoteRenewerReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	if tenant.Spec.TenantCondition == authv1beta1.TenantConditionRevoked {
+		klog.Warningf("Skipping Renew %q as the Tenant %q is revoked", req.NamespacedName, tenant.Name)
+		events.EventWithOptions(r.recorder, &renew, "Skipping renewal as the tenant is revoked",
+			&events.Option{EventType: events.Warning, Reason: "TenantRevoked"})
+		return ctrl.Result{}, nil
+	}
+
 	var resourceSlice *authv1beta1.ResourceSlice
 	if renew.Spec.ResourceSliceRef != nil {
 		resourceSlice = &authv1beta1.ResourceSlice{}
@@ -121,6 +135,13 @@ func (r *RemoteRenewerReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		}
 	}
 
+	switch {
+	case renew.Spec.LeaseDuration != nil && resourceSlice == nil:
+		return ctrl.Result{}, r.handleTenantLeaseRenew(ctx, &renew, tenant)
+	case renew.Spec.LeaseDuration != nil:
+		return ctrl.Result{}, r.handleLeaseRenew(ctx, &renew, tenant, resourceSlice)
+	}
+
 	if err := r.handleRenew(ctx, &renew, tenant, resourceSlice); err != nil {
 		klog.Errorf("Unable to handle Renew %q: %s", req.NamespacedName, err)
 		events.EventWithOptions(r.recorder, &renew, fmt.Sprintf("Failed to handle renewal: %s", err),
@@ -243,6 +264,7 @@ func (r *RemoteRenewerReconciler) handleRenew(ctx context.Context,
 		ResourceSlice:            resourceSlice,
 		ProxyURL:                 tenant.Spec.ProxyURL,
 		IsUpdate:                 true,
+		TenantGeneration:         tenant.Generation,
 	})
 	if err != nil {
 		klog.Errorf("Unable to forge the AuthParams for the Renew %q: %s", renew.Name, err)
@@ -257,3 +279,127 @@ func (r *RemoteRenewerReconciler) handleRenew(ctx context.Context,
 
 	return nil
 }
+
+// handleLeaseRenew handles a Renew object requesting the renewal of the lease of a ResourceSlice,
+// extending the lease from the current time by the requested duration, bounded by the maximum one.
+//
+// Args:
+//   - ctx: the context of the request
+//   - renew: the Renew object to be handled
+//   - tenant: the Tenant object associated with the Renew
+//   - resourceSlice: the ResourceSlice object whose lease is renewed
+//
+// Returns:
+//   - error: the error occurred during the handling, if any
+func (r *RemoteRenewerReconciler) handleLeaseRenew(ctx context.Context,
+	renew *authv1beta1.Renew,
+	tenant *authv1beta1.Tenant,
+	resourceSlice *authv1beta1.ResourceSlice) error {
+	if renew.Status.ObservedGeneration == renew.Generation {
+		klog.V(4).Infof("Lease renewal of Renew %q already handled", renew.Name)
+		return nil
+	}
+
+	maxDuration, err := authentication.LeaseDuration(resourceSlice, tenant, r.LeaseDuration)
+	if err != nil {
+		klog.Errorf("Unable to get the lease duration of ResourceSlice %q: %s", resourceSlice.Name, err)
+		events.EventWithOptions(r.recorder, renew, fmt.Sprintf("Failed to renew the lease: %s", err),
+			&events.Option{EventType: events.Error, Reason: "InvalidLeaseDuration"})
+		return nil
+	}
+
+	duration := renew.Spec.LeaseDuration.Duration
+	if maxDuration > 0 && (duration <= 0 || duration > maxDuration) {
+		duration = maxDuration
+	}
+
+	resourceSlice.Status.LeaseExpiration = authentication.LeaseExpiration(time.Now(), duration, tenant)
+	if err := r.Status().Update(ctx, resourceSlice); err != nil {
+		klog.Errorf("Failed to update the lease of ResourceSlice %q: %s", resourceSlice.Name, err)
+		return err
+	}
+
+	renew.Status.LeaseExpiration = resourceSlice.Status.LeaseExpiration
+	renew.Status.ObservedGeneration = renew.Generation
+	if err := r.Status().Update(ctx, renew); err != nil {
+		klog.Errorf("Failed to update Renew status for %q: %s", renew.Name, err)
+		return err
+	}
+
+	msg := "Successfully renewed lease (not time-bounded)"
+	if renew.Status.LeaseExpiration != nil {
+		msg = fmt.Sprintf("Successfully renewed lease until %s", renew.Status.LeaseExpiration.Format(time.RFC3339))
+	}
+	klog.Infof("Renew %q: %s", renew.Name, msg)
+	events.Event(r.recorder, renew, msg)
+	return nil
+}
+
+// handleTenantLeaseRenew handles a Renew object requesting the renewal of the lease of the Tenant, extending the lease
+// from the current time by the requested duration, bounded by the maximum one. If the tenant has been drained since
+// its lease expired, it is re-activated.
+//
+// Args:
+//   - ctx: the context of the request
+//   - renew: the Renew object to be handled
+//   - tenant: the Tenant object whose lease is renewed
+//
+// Returns:
+//   - error: the error occurred during the handling, if any
+func (r *RemoteRenewerReconciler) handleTenantLeaseRenew(ctx context.Context,
+	renew *authv1beta1.Renew,
+	tenant *authv1beta1.Tenant) error {
+	if renew.Status.ObservedGeneration == renew.Generation {
+		klog.V(4).Infof("Lease renewal of Renew %q already handled", renew.Name)
+		return nil
+	}
+
+	// If the lease of the tenant is not time-bounded, there is nothing to renew.
+	if tenant.Spec.LeaseExpiration != nil {
+		maxDuration, err := authentication.TenantLeaseDuration(tenant, r.LeaseDuration)
+		if err != nil {
+			klog.Errorf("Unable to get the lease duration of Tenant %q: %s", tenant.Name, err)
+			events.EventWithOptions(r.recorder, renew, fmt.Sprintf("Failed to renew the lease: %s", err),
+				&events.Option{EventType: events.Error, Reason: "InvalidLeaseDuration"})
+			return nil
+		}
+
+		duration := renew.Spec.LeaseDuration.Duration
+		if maxDuration > 0 && (duration <= 0 || duration > maxDuration) {
+			duration = maxDuration
+		}
+		if duration <= 0 {
+			// The lease of the tenant has been explicitly bounded, hence it cannot be renewed as not time-bounded.
+			klog.Warningf("Skipping lease renewal of Renew %q as no duration is requested nor configured", renew.Name)
+			events.EventWithOptions(r.recorder, renew, "Skipping lease renewal as no duration is requested nor configured",
+				&events.Option{EventType: events.Warning, Reason: "InvalidLeaseDuration"})
+			return nil
+		}
+
+		tenant.Spec.LeaseExpiration = &metav1.Time{Time: time.Now().Add(duration)}
+		if _, found := tenant.Annotations[consts.DrainedLeaseAnnotation]; found &&
+			tenant.Spec.TenantCondition == authv1beta1.TenantConditionDrained {
+			tenant.Spec.TenantCondition = authv1beta1.TenantConditionActive
+		}
+		delete(tenant.Annotations, consts.DrainedLeaseAnnotation)
+		if err := r.Update(ctx, tenant); err != nil {
+			klog.Errorf("Failed to update the lease of Tenant %q: %s", tenant.Name, err)
+			return err
+		}
+	}
+
+	renew.Status.LeaseExpiration = tenant.Spec.LeaseExpiration.DeepCopy()
+	renew.Status.ObservedGeneration = renew.Generation
+	if err := r.Status().Update(ctx, renew); err != nil {
+		klog.Errorf("Failed to update Renew status for %q: %s", renew.Name, err)
+		return err
+	}
+
+	msg := "Successfully renewed tenant lease (not time-bounded)"
+	if renew.Status.LeaseExpiration != nil {
+		msg = fmt.Sprintf("Successfully renewed tenant lease until %s", renew.Status.LeaseExpiration.Format(time.RFC3339))
+	}
+	klog.Infof("Renew %q: %s", renew.Name, msg)
+	events.Event(r.recorder, renew, msg)
+	return nil
+}
@@ -17,13 +17,16 @@ package remoteresourceslicecontroller
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	klog "k8s.io/klog/v2"
@@ -31,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -40,7 +44,9 @@ import (
 	"github.com/liqotech/liqo/pkg/consts"
 	identitymanager "github.com/liqotech/liqo/pkg/identityManager"
 	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
+	resourcesliceclass "github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication/resourceslice-class"
 	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
+	"github.com/liqotech/liqo/pkg/utils"
 	"github.com/liqotech/liqo/pkg/utils/getters"
 	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
 )
@@ -51,7 +57,12 @@ func NewRemoteResourceSliceReconciler(cl client.Client, s *runtime.Scheme, confi
 	identityProvider identitymanager.IdentityProvider,
 	namespaceManager tenantnamespace.Manager,
 	apiServerAddressOverride string, caOverride []byte, trustedCA bool,
-	sliceStatusOptions *SliceStatusOptions) *RemoteResourceSliceReconciler {
+	sliceStatusOptions *SliceStatusOptions,
+	classes *resourcesliceclass.Registry) *RemoteResourceSliceReconciler {
+	if classes == nil {
+		classes = resourcesliceclass.NewDefaultRegistry()
+	}
+
 	return &RemoteResourceSliceReconciler{
 		Client: cl,
 		Scheme: s,
@@ -67,10 +78,7 @@ func NewRemoteResourceSliceReconciler(cl client.Client, s *runtime.Scheme, confi
 
 		sliceStatusOptions: sliceStatusOptions,
 
-		reconciledClasses: []authv1beta1.ResourceSliceClass{
-			authv1beta1.ResourceSliceClassDefault,
-			authv1beta1.ResourceSliceClassUnknown,
-		},
+		classes: classes,
 	}
 }
 
@@ -90,13 +98,14 @@ type RemoteResourceSliceReconciler struct {
 
 	sliceStatusOptions *SliceStatusOptions
 
-	reconciledClasses []authv1beta1.ResourceSliceClass
+	classes *resourcesliceclass.Registry
 }
 
 // cluster-role
 // +kubebuilder:rbac:groups=authentication.liqo.io,resources=resourceslices;resourceslices/status,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=authentication.liqo.io,resources=tenants,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=nodes;pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups=storage,resources=storageclasses,verbs=get;list;watch
 
 // Reconcile replicated ResourceSlice resources.
@@ -179,6 +188,13 @@ func (r *RemoteResourceSliceReconciler) Reconcile(ctx context.Context, req ctrl.
 
 func (r *RemoteResourceSliceReconciler) handleAuthenticationStatus(ctx context.Context,
 	resourceSlice *authv1beta1.ResourceSlice, tenant *authv1beta1.Tenant) error {
+	// do not issue any identity to a revoked tenant
+	if tenant.Spec.TenantCondition == authv1beta1.TenantConditionRevoked {
+		resourceSlice.Status.AuthParams = nil
+		denyAuthentication(resourceSlice, r.eventRecorder)
+		return nil
+	}
+
 	// check that the CSR is valid
 	shouldCheckPublicKey := authv1beta1.GetAuthzPolicyValue(tenant.Spec.AuthzPolicy) != authv1beta1.TolerateNoHandshake
 	if err := authentication.CheckCSRForResourceSlice(tenant.Spec.PublicKey, resourceSlice, shouldCheckPublicKey); err != nil {
@@ -201,6 +217,7 @@ func (r *RemoteResourceSliceReconciler) handleAuthenticationStatus(ctx context.C
 		TrustedCA:                r.trustedCA,
 		ResourceSlice:            resourceSlice,
 		ProxyURL:                 tenant.Spec.ProxyURL,
+		TenantGeneration:         tenant.Generation,
 	})
 	if err != nil {
 		klog.Errorf("Unable to forge the AuthParams for the ResourceSlice %q: %s", client.ObjectKeyFromObject(resourceSlice), err)
@@ -219,36 +236,67 @@ func (r *RemoteResourceSliceReconciler) handleAuthenticationStatus(ctx context.C
 
 func (r *RemoteResourceSliceReconciler) handleResourcesStatus(ctx context.Context,
 	resourceSlice *authv1beta1.ResourceSlice, tenant *authv1beta1.Tenant) error {
-	var err error
-
 	switch tenant.Spec.TenantCondition {
 	case authv1beta1.TenantConditionActive:
-		// If the ResourceSlice is not of the default class, the resource status is leaved as it is and the update is
+		// The nodes are refreshed at every reconciliation, as they change independently of the requested resources.
+		nodes, err := getNodes(ctx, r.Client, resourceSlice.Status.NodeSelector)
+		if err != nil {
+			klog.Errorf("Unable to get the Nodes for the ResourceSlice %q: %s", client.ObjectKeyFromObject(resourceSlice), err)
+			r.eventRecorder.Event(resourceSlice, corev1.EventTypeWarning, "NodesFailed", err.Error())
+			return err
+		}
+		resourceSlice.Status.Nodes = nodes
+
+		// If the ResourceSlice is of a class not handled by any engine, the resource status is leaved as it is and the update is
 		// demanded to external controllers/plugins.
-		if !isInResourceClasses(resourceSlice, r.reconciledClasses...) {
-			klog.V(6).Infof("ResourceSlice %q is not of the default class, the resource status is leaved as it is",
-				client.ObjectKeyFromObject(resourceSlice))
+		engine, found := r.classes.Get(resourceSlice.Spec.Class)
+		if !found {
+			klog.V(6).Infof("ResourceSlice %q is of the class %q not handled by any engine, the resource status is leaved as it is",
+				client.ObjectKeyFromObject(resourceSlice), resourceSlice.Spec.Class)
 			return nil
 		}
 
-		// Default class: accept requested resources and set the default values for the resources not specified.
-		findOrDefault := func(resource corev1.ResourceName, val resource.Quantity) resource.Quantity {
-			v, ok := resourceSlice.Spec.Resources[resource]
-			if ok {
-				return v
-			}
-			return val
+		// The requested resources are the ones in the spec, plus the default values for the resources not specified.
+		requested := corev1.ResourceList{}
+		for k, v := range r.sliceStatusOptions.DefaultResourceQuantity {
+			requested[k] = v
+		}
+		for k, v := range resourceSlice.Spec.Resources {
+			requested[k] = v
 		}
 
-		if resourceSlice.Status.Resources == nil {
-			resourceSlice.Status.Resources = corev1.ResourceList{}
+		// Once accepted, the resources are not decided again (possibly reclaiming them) unless the request changes.
+		resCond := authentication.GetCondition(resourceSlice, authv1beta1.ResourceSliceConditionTypeResources)
+		accepted := resCond != nil && resCond.Status == authv1beta1.ResourceSliceConditionAccepted
+		if accepted && resourceSlice.Status.RequestedResources == nil {
+			// The resources have been accepted before the requested ones were recorded.
+			resourceSlice.Status.RequestedResources = requested
+		}
+		if accepted && resourcesliceclass.EqualResources(resourceSlice.Status.RequestedResources, requested) {
+			klog.V(6).Infof("ResourceSlice %q resources already accepted for the current request", client.ObjectKeyFromObject(resourceSlice))
+			// Any offer made for a different request is no longer pending.
+			resourceSlice.Status.Offer = nil
+			return nil
 		}
 
-		for k, v := range r.sliceStatusOptions.DefaultResourceQuantity {
-			resourceSlice.Status.Resources[k] = findOrDefault(k, v)
+		result, err := engine.Decide(ctx, &resourcesliceclass.Request{ResourceSlice: resourceSlice, Tenant: tenant, Requested: requested})
+		if err != nil {
+			klog.Errorf("Unable to decide the resources of the ResourceSlice %q: %s", client.ObjectKeyFromObject(resourceSlice), err)
+			r.eventRecorder.Event(resourceSlice, corev1.EventTypeWarning, "ResourcesDecisionFailed", err.Error())
+			return err
 		}
-		for k, v := range resourceSlice.Spec.Resources {
-			resourceSlice.Status.Resources[k] = v
+
+		if result.Decision == resourcesliceclass.DecisionDeny {
+			resourceSlice.Status.Offer = nil
+			if accepted {
+				// The updated request cannot be satisfied, but the resources already accepted are kept.
+				klog.Infof("ResourceSlice %q resources update denied: %s", client.ObjectKeyFromObject(resourceSlice), result.Message)
+				r.eventRecorder.Event(resourceSlice, corev1.EventTypeWarning, "ResourcesUpdateDenied", result.Message)
+				return nil
+			}
+			klog.Infof("ResourceSlice %q resources denied: %s", client.ObjectKeyFromObject(resourceSlice), result.Message)
+			denyResources(resourceSlice, r.eventRecorder)
+			return nil
 		}
 
 		resourceSlice.Status.StorageClasses, err = getStorageClasses(ctx, r.Client, r.sliceStatusOptions)
@@ -262,6 +310,39 @@ func (r *RemoteResourceSliceReconciler) handleResourcesStatus(ctx context.Contex
 		resourceSlice.Status.LoadBalancerClasses = getLoadBalancerClasses(r.sliceStatusOptions)
 		resourceSlice.Status.NodeLabels = getNodeLabels(r.sliceStatusOptions)
 
+		if result.Decision == resourcesliceclass.DecisionOffer {
+			// The offer is reported separately, so that the resources already accepted (if any) are kept until it is accepted.
+			offer := &authv1beta1.ResourceSliceOffer{
+				ID:                 resourcesliceclass.OfferID(requested, result.Resources),
+				Resources:          result.Resources,
+				RequestedResources: requested,
+			}
+			if accepted {
+				if resourceSlice.Status.Offer == nil || resourceSlice.Status.Offer.ID != offer.ID {
+					klog.Infof("ResourceSlice %q resources update offered: %s", client.ObjectKeyFromObject(resourceSlice), result.Message)
+					r.eventRecorder.Event(resourceSlice, corev1.EventTypeNormal, "ResourceSliceResourcesOffered", result.Message)
+				}
+				resourceSlice.Status.Offer = offer
+				return nil
+			}
+			resourceSlice.Status.Offer = offer
+			offerResources(resourceSlice, result.Message, r.eventRecorder)
+			return nil
+		}
+
+		resourceSlice.Status.Resources = result.Resources
+		resourceSlice.Status.RequestedResources = requested
+		resourceSlice.Status.Offer = nil
+
+		// The lease of the resources, if time-bounded, starts when they are granted for the first time.
+		if resourceSlice.Status.LeaseExpiration == nil {
+			leaseDuration, err := authentication.LeaseDuration(resourceSlice, tenant, r.sliceStatusOptions.LeaseDuration)
+			if err != nil {
+				klog.Errorf("Unable to get the lease duration of the ResourceSlice %q: %s", client.ObjectKeyFromObject(resourceSlice), err)
+				r.eventRecorder.Event(resourceSlice, corev1.EventTypeWarning, "InvalidLeaseDuration", err.Error())
+			}
+			resourceSlice.Status.LeaseExpiration = authentication.LeaseExpiration(time.Now(), leaseDuration, tenant)
+		}
 		acceptResources(resourceSlice, r.eventRecorder)
 	case authv1beta1.TenantConditionCordoned:
 		// Only deny if the resources are not already accepted.
@@ -269,7 +350,7 @@ func (r *RemoteResourceSliceReconciler) handleResourcesStatus(ctx context.Contex
 		if resCond == nil || resCond.Status == "" {
 			denyResources(resourceSlice, r.eventRecorder)
 		}
-	case authv1beta1.TenantConditionDrained:
+	case authv1beta1.TenantConditionDrained, authv1beta1.TenantConditionRevoked:
 		denyResources(resourceSlice, r.eventRecorder)
 	}
 
@@ -292,9 +373,52 @@ func (r *RemoteResourceSliceReconciler) SetupWithManager(mgr ctrl.Manager) error
 			builder.WithPredicates(predicate.And(remoteResSliceFilter, withCSR(), predicate.GenerationChangedPredicate{})),
 		).
 		Watches(&authv1beta1.Tenant{}, handler.EnqueueRequestsFromMapFunc(r.resourceSlicesEnquer())).
+		// The nodes exposed in the status of the ResourceSlices are refreshed whenever a physical node changes.
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.remoteResourceSlicesEnquer()),
+			builder.WithPredicates(physicalNodes(), nodesChangedPredicate())).
 		Complete(r)
 }
 
+func (r *RemoteResourceSliceReconciler) remoteResourceSlicesEnquer() func(ctx context.Context, obj client.Object) []reconcile.Request {
+	return func(ctx context.Context, _ client.Object) []reconcile.Request {
+		labelSelector := reflection.ReplicatedResourcesLabelSelector()
+		selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+		utilruntime.Must(err)
+
+		resSlices, err := getters.ListResourceSlicesByLabel(ctx, r.Client, corev1.NamespaceAll, selector)
+		if err != nil {
+			klog.Errorf("Failed to retrieve the remote ResourceSlices: %v", err)
+			return nil
+		}
+
+		reqs := make([]reconcile.Request, len(resSlices))
+		for i := range resSlices {
+			reqs[i] = reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&resSlices[i])}
+		}
+		return reqs
+	}
+}
+
+// physicalNodes filters out the virtual nodes, which are never exposed in the status of the ResourceSlices.
+func physicalNodes() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetLabels()[consts.TypeLabel] != consts.TypeNode
+	})
+}
+
+// nodesChangedPredicate triggers a reconciliation when a node is added or removed, or when any of the fields
+// affecting whether it is exposed in the status of the ResourceSlices (i.e., labels, schedulability and readiness) changes.
+func nodesChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, okOld := e.ObjectOld.(*corev1.Node)
+			newNode, okNew := e.ObjectNew.(*corev1.Node)
+			return okOld && okNew && (!reflect.DeepEqual(oldNode.Labels, newNode.Labels) ||
+				oldNode.Spec.Unschedulable != newNode.Spec.Unschedulable || utils.IsNodeReady(oldNode) != utils.IsNodeReady(newNode))
+		},
+	}
+}
+
 func (r *RemoteResourceSliceReconciler) resourceSlicesEnquer() func(ctx context.Context, obj client.Object) []reconcile.Request {
 	return func(ctx context.Context, obj client.Object) []reconcile.Request {
 		tenant, ok := obj.(*authv1beta1.Tenant)
@@ -393,6 +517,24 @@ func acceptResources(resourceSlice *authv1beta1.ResourceSlice, er record.EventRe
 	}
 }
 
+func offerResources(resourceSlice *authv1beta1.ResourceSlice, message string, er record.EventRecorder) {
+	switch authentication.EnsureCondition(
+		resourceSlice,
+		authv1beta1.ResourceSliceConditionTypeResources,
+		authv1beta1.ResourceSliceConditionOffered,
+		"ResourceSliceResourcesOffered",
+		message,
+	) {
+	case controllerutil.OperationResultNone:
+		klog.V(4).Infof("ResourceSlice resources %q already offered", resourceSlice.Name)
+	case controllerutil.OperationResultUpdated, controllerutil.OperationResultCreated:
+		klog.Infof("ResourceSlice resources %q offered: %s", resourceSlice.Name, message)
+		er.Event(resourceSlice, corev1.EventTypeNormal, "ResourceSliceResourcesOffered", message)
+	default:
+		return
+	}
+}
+
 func denyResources(resourceSlice *authv1beta1.ResourceSlice, er record.EventRecorder) {
 	switch authentication.EnsureCondition(
 		resourceSlice,
@@ -414,15 +556,6 @@ func denyResources(resourceSlice *authv1beta1.ResourceSlice, er record.EventReco
 	}
 }
 
-func isInResourceClasses(resourceSlice *authv1beta1.ResourceSlice, classes ...authv1beta1.ResourceSliceClass) bool {
-	for _, class := range classes {
-		if resourceSlice.Spec.Class == class {
-			return true
-		}
-	}
-	return false
-}
-
 // validateRSNamespace makes sure that the ResourceSlice has been created in the tenant namespace dedicated to the consumer cluster.
 func validateRSNamespace(ctx context.Context, c client.Client, namespace, consumerClusterID string) error {
 	var tenantNamespace corev1.Namespace
@@ -17,12 +17,19 @@ package remoteresourceslicecontroller
 import (
 	"context"
 	"sort"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
 	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils"
 	argutils "github.com/liqotech/liqo/pkg/utils/args"
 )
 
@@ -34,6 +41,7 @@ type SliceStatusOptions struct {
 	LoadBalancerClasses       argutils.ClassNameList
 	ClusterLabels             map[string]string
 	DefaultResourceQuantity   corev1.ResourceList
+	LeaseDuration             time.Duration
 }
 
 func getIngressClasses(opts *SliceStatusOptions) []liqov1beta1.IngressType {
@@ -104,3 +112,30 @@ func getNodeLabels(opts *SliceStatusOptions) map[string]string {
 	}
 	return opts.ClusterLabels
 }
+
+// getNodes returns the ready and schedulable physical nodes matching the given node selector, which can host the offloaded pods.
+func getNodes(ctx context.Context, cl client.Client, nodeSelector map[string]string) ([]authv1beta1.ResourceSliceNode, error) {
+	// Virtual nodes are excluded, as the pods offloaded through the ResourceSlice cannot be further offloaded to them.
+	physical, err := labels.NewRequirement(consts.TypeLabel, selection.NotIn, []string{consts.TypeNode})
+	utilruntime.Must(err)
+
+	nodeList := &corev1.NodeList{}
+	selector := labels.SelectorFromSet(nodeSelector).Add(*physical)
+	if err := cl.List(ctx, nodeList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+
+	nodes := make([]authv1beta1.ResourceSliceNode, 0, len(nodeList.Items))
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		if node.Spec.Unschedulable || !utils.IsNodeReady(node) {
+			continue
+		}
+		nodes = append(nodes, authv1beta1.ResourceSliceNode{Name: node.Name, Labels: node.Labels})
+	}
+
+	// sort the nodes by name to have a deterministic order
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	return nodes, nil
+}
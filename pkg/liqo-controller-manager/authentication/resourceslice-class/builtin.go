@@ -0,0 +1,194 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcesliceclass
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	argsutils "github.com/liqotech/liqo/pkg/utils/args"
+)
+
+// fixedEngine grants the requested resources as they are.
+type fixedEngine struct{}
+
+// Decide implements the Engine interface.
+func (e *fixedEngine) Decide(_ context.Context, req *Request) (*Result, error) {
+	return &Result{Decision: DecisionAccept, Resources: req.Requested.DeepCopy(), Message: "Requested resources granted"}, nil
+}
+
+// freeCapacityEngine grants each ResourceSlice up to a fixed percentage of the allocatable capacity of the local cluster,
+// bounded by the capacity neither requested by the local pods nor granted to the other ResourceSlices.
+type freeCapacityEngine struct {
+	cl         client.Client
+	percentage int64
+}
+
+// Decide implements the Engine interface.
+func (e *freeCapacityEngine) Decide(ctx context.Context, req *Request) (*Result, error) {
+	allocatable, free, err := Capacity(ctx, e.cl)
+	if err != nil {
+		return nil, err
+	}
+
+	// The resources granted to the ResourceSlices are reserved, regardless of the offloaded pods actually using them.
+	outstanding, err := GrantedToOthers(ctx, e.cl, req.ResourceSlice)
+	if err != nil {
+		return nil, err
+	}
+
+	available := corev1.ResourceList{}
+	for name, quantity := range allocatable {
+		share := percentageOf(name, quantity, e.percentage)
+		remaining := free[name].DeepCopy()
+		if reserved, found := outstanding[name]; found {
+			remaining.Sub(reserved)
+		}
+		if remaining.Cmp(share) < 0 {
+			share = remaining
+		}
+		if share.Sign() < 0 {
+			share = *resource.NewQuantity(0, quantity.Format)
+		}
+		available[name] = share
+	}
+
+	offer := bound(req.Requested, available)
+	if names := exhausted(req.Requested, offer); len(names) > 0 {
+		return &Result{Decision: DecisionDeny, Message: fmt.Sprintf("No free capacity for %s", strings.Join(names, ", "))}, nil
+	}
+	return &Result{Decision: DecisionAccept, Resources: offer,
+		Message: fmt.Sprintf("Granted %s, based on %d%% of the allocatable capacity", formatResources(offer), e.percentage)}, nil
+}
+
+// percentageOf returns the given percentage of the quantity, preserving its format.
+func percentageOf(name corev1.ResourceName, quantity resource.Quantity, percentage int64) resource.Quantity {
+	if name == corev1.ResourceCPU {
+		return *resource.NewMilliQuantity(quantity.MilliValue()*percentage/100, quantity.Format)
+	}
+	return *resource.NewQuantity(quantity.Value()*percentage/100, quantity.Format)
+}
+
+// tenantCapEngine grants the requested resources up to a cap on the total resources granted to each tenant,
+// across all its ResourceSlices.
+type tenantCapEngine struct {
+	cl         client.Client
+	defaultCap corev1.ResourceList
+}
+
+// Decide implements the Engine interface.
+func (e *tenantCapEngine) Decide(ctx context.Context, req *Request) (*Result, error) {
+	caps, err := TenantCap(req.Tenant, e.defaultCap)
+	if err != nil {
+		return nil, err
+	}
+	if len(caps) == 0 {
+		return &Result{Decision: DecisionAccept, Resources: req.Requested.DeepCopy(), Message: "Requested resources granted"}, nil
+	}
+
+	granted, err := GrantedToConsumer(ctx, e.cl, req.ResourceSlice)
+	if err != nil {
+		return nil, err
+	}
+
+	available := corev1.ResourceList{}
+	for name, limit := range caps {
+		remaining := limit.DeepCopy()
+		if current, found := granted[name]; found {
+			remaining.Sub(current)
+		}
+		if remaining.Sign() < 0 {
+			remaining = *resource.NewQuantity(0, limit.Format)
+		}
+		available[name] = remaining
+	}
+
+	offer := bound(req.Requested, available)
+	if names := exhausted(req.Requested, offer); len(names) > 0 {
+		return &Result{Decision: DecisionDeny, Message: fmt.Sprintf("Tenant cap reached for %s", strings.Join(names, ", "))}, nil
+	}
+	return &Result{Decision: DecisionAccept, Resources: offer,
+		Message: fmt.Sprintf("Granted %s, within the tenant cap %s", formatResources(offer), formatResources(caps))}, nil
+}
+
+// TenantCap returns the maximum amount of resources granted to the given tenant, overriding the default cap
+// with the one set in the tenant annotation, if any.
+func TenantCap(tenant *authv1beta1.Tenant, defaultCap corev1.ResourceList) (corev1.ResourceList, error) {
+	caps := defaultCap.DeepCopy()
+	if caps == nil {
+		caps = corev1.ResourceList{}
+	}
+
+	value, found := tenant.Annotations[consts.TenantResourceCapAnnotation]
+	if !found || value == "" {
+		return caps, nil
+	}
+
+	var override argsutils.ResourceMap
+	if err := override.Set(value); err != nil {
+		return nil, fmt.Errorf("invalid %q annotation of Tenant %q: %w", consts.TenantResourceCapAnnotation, tenant.Name, err)
+	}
+	for name, quantity := range override.ToResourceList() {
+		caps[name] = quantity
+	}
+	return caps, nil
+}
+
+// counterOfferEngine proposes the resources which can be granted, as bounded by the free capacity and the tenant cap,
+// when they are less than the requested ones, and waits for the consumer to accept or reject the offer.
+type counterOfferEngine struct {
+	bounds []Engine
+}
+
+// Decide implements the Engine interface.
+func (e *counterOfferEngine) Decide(ctx context.Context, req *Request) (*Result, error) {
+	offer := req.Requested
+	for _, engine := range e.bounds {
+		res, err := engine.Decide(ctx, &Request{ResourceSlice: req.ResourceSlice, Tenant: req.Tenant, Requested: offer})
+		if err != nil {
+			return nil, err
+		}
+		if res.Decision == DecisionDeny {
+			return res, nil
+		}
+		offer = res.Resources
+	}
+
+	// The response of the consumer applies to the current offer only, hence it is ignored if either the
+	// offered or the requested resources changed in the meanwhile.
+	id := OfferID(req.Requested, offer)
+	responded := req.ResourceSlice.Spec.OfferID == id
+
+	switch {
+	case EqualResources(offer, req.Requested):
+		return &Result{Decision: DecisionAccept, Resources: offer, Message: "Requested resources granted"}, nil
+	case responded && req.ResourceSlice.Spec.OfferResponse == authv1beta1.ResourceSliceOfferRejected:
+		return &Result{Decision: DecisionDeny, Message: fmt.Sprintf("Offer of %s rejected by the consumer", formatResources(offer))}, nil
+	case responded && req.ResourceSlice.Spec.OfferResponse == authv1beta1.ResourceSliceOfferAccepted:
+		return &Result{Decision: DecisionAccept, Resources: offer,
+			Message: fmt.Sprintf("Offer of %s accepted by the consumer", formatResources(offer))}, nil
+	default:
+		return &Result{Decision: DecisionOffer, Resources: offer,
+			Message: fmt.Sprintf("Offered %s instead of the requested %s: set spec.offerID to %q and spec.offerResponse "+
+				"to accept or reject the offer", formatResources(offer), formatResources(req.Requested), id)}, nil
+	}
+}
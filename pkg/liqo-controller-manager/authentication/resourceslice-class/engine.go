@@ -0,0 +1,186 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcesliceclass
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+)
+
+// Decision is the outcome of the evaluation of a ResourceSlice by an engine.
+type Decision string
+
+const (
+	// DecisionAccept grants the resources of the result to the consumer.
+	DecisionAccept Decision = "Accept"
+	// DecisionOffer proposes the resources of the result to the consumer, which has to accept or reject them.
+	DecisionOffer Decision = "Offer"
+	// DecisionDeny denies the resources to the consumer.
+	DecisionDeny Decision = "Deny"
+)
+
+// Request contains the information to decide the resources granted to a ResourceSlice.
+type Request struct {
+	// ResourceSlice is the ResourceSlice to be evaluated.
+	ResourceSlice *authv1beta1.ResourceSlice
+	// Tenant is the Tenant of the consumer cluster which created the ResourceSlice.
+	Tenant *authv1beta1.Tenant
+	// Requested are the resources requested by the consumer, including the default ones not explicitly set in the ResourceSlice.
+	Requested corev1.ResourceList
+}
+
+// Result is the decision of an engine about a ResourceSlice.
+type Result struct {
+	// Decision is the outcome of the evaluation.
+	Decision Decision
+	// Resources are the resources granted (or offered) to the consumer.
+	Resources corev1.ResourceList
+	// Message is a human-readable explanation of the decision.
+	Message string
+}
+
+// OfferID returns the identifier of the offer of the given resources, in response to the given request.
+// It changes whenever either of them changes, so that the responses of the consumer are tied to a specific offer.
+func OfferID(requested, offered corev1.ResourceList) string {
+	canonical := func(resources corev1.ResourceList) string {
+		items := make([]string, 0, len(resources))
+		for name, quantity := range resources {
+			items = append(items, fmt.Sprintf("%s=%d", name, quantity.MilliValue()))
+		}
+		sort.Strings(items)
+		return strings.Join(items, ",")
+	}
+
+	hash := sha256.Sum256([]byte(canonical(requested) + ";" + canonical(offered)))
+	return hex.EncodeToString(hash[:8])
+}
+
+// Engine decides the resources granted to the ResourceSlices of a class, given the requested resources,
+// the tenant of the consumer and the usage of the local cluster.
+type Engine interface {
+	Decide(ctx context.Context, req *Request) (*Result, error)
+}
+
+// Names of the built-in engines.
+const (
+	// EngineFixed grants the requested resources as they are.
+	EngineFixed = "fixed"
+	// EngineFreeCapacity grants up to a fixed percentage of the free capacity of the local cluster.
+	EngineFreeCapacity = "free-capacity"
+	// EngineTenantCap grants the requested resources up to a cap on the total resources granted to each tenant.
+	EngineTenantCap = "tenant-cap"
+	// EngineCounterOffer proposes the resources which can be granted, if less than the requested ones,
+	// and waits for the consumer to accept or reject the offer.
+	EngineCounterOffer = "counter-offer"
+)
+
+// EngineNames returns the names of the built-in engines.
+func EngineNames() []string {
+	return []string{EngineFixed, EngineFreeCapacity, EngineTenantCap, EngineCounterOffer}
+}
+
+// Options contains the parameters of the built-in engines.
+type Options struct {
+	// FreeCapacityPercentage is the percentage of the free capacity of the local cluster granted to each ResourceSlice.
+	FreeCapacityPercentage int64
+	// TenantCap is the maximum amount of resources granted to each tenant, unless overridden by the tenant annotation.
+	TenantCap corev1.ResourceList
+}
+
+// NewEngine returns the built-in engine with the given name.
+func NewEngine(name string, cl client.Client, opts *Options) (Engine, error) {
+	switch name {
+	case EngineFixed:
+		return &fixedEngine{}, nil
+	case EngineFreeCapacity:
+		return &freeCapacityEngine{cl: cl, percentage: opts.FreeCapacityPercentage}, nil
+	case EngineTenantCap:
+		return &tenantCapEngine{cl: cl, defaultCap: opts.TenantCap}, nil
+	case EngineCounterOffer:
+		return &counterOfferEngine{bounds: []Engine{
+			&freeCapacityEngine{cl: cl, percentage: opts.FreeCapacityPercentage},
+			&tenantCapEngine{cl: cl, defaultCap: opts.TenantCap},
+		}}, nil
+	default:
+		return nil, fmt.Errorf("unknown ResourceSlice class engine %q (supported: %s)", name, strings.Join(EngineNames(), ", "))
+	}
+}
+
+// Registry maps the ResourceSlice classes to the engines handling them.
+// The ResourceSlices whose class is not registered are left to external controllers.
+type Registry struct {
+	engines map[authv1beta1.ResourceSliceClass]Engine
+}
+
+// NewRegistry returns a new empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{engines: map[authv1beta1.ResourceSliceClass]Engine{}}
+}
+
+// NewDefaultRegistry returns a Registry handling the default (and unset) class with the fixed engine.
+func NewDefaultRegistry() *Registry {
+	registry := NewRegistry()
+	registry.Register(authv1beta1.ResourceSliceClassDefault, &fixedEngine{})
+	registry.Register(authv1beta1.ResourceSliceClassUnknown, &fixedEngine{})
+	return registry
+}
+
+// NewRegistryFromConfig returns a Registry handling the given classes with the built-in engines they are mapped to.
+// The ResourceSlices with no class are handled as the ones of the default class.
+func NewRegistryFromConfig(classes map[string]string, cl client.Client, opts *Options) (*Registry, error) {
+	registry := NewDefaultRegistry()
+	for class, name := range classes {
+		engine, err := NewEngine(name, cl, opts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid engine for ResourceSlice class %q: %w", class, err)
+		}
+
+		registry.Register(authv1beta1.ResourceSliceClass(class), engine)
+		if authv1beta1.ResourceSliceClass(class) == authv1beta1.ResourceSliceClassDefault {
+			registry.Register(authv1beta1.ResourceSliceClassUnknown, engine)
+		}
+	}
+	return registry, nil
+}
+
+// Register registers the engine handling the given class, replacing the previous one.
+func (r *Registry) Register(class authv1beta1.ResourceSliceClass, engine Engine) {
+	r.engines[class] = engine
+}
+
+// Get returns the engine handling the given class, if any.
+func (r *Registry) Get(class authv1beta1.ResourceSliceClass) (Engine, bool) {
+	engine, found := r.engines[class]
+	return engine, found
+}
+
+// Classes returns the classes handled by the registry, sorted by name.
+func (r *Registry) Classes() []authv1beta1.ResourceSliceClass {
+	classes := make([]authv1beta1.ResourceSliceClass, 0, len(r.engines))
+	for class := range r.engines {
+		classes = append(classes, class)
+	}
+	sort.Slice(classes, func(i, j int) bool { return classes[i] < classes[j] })
+	return classes
+}
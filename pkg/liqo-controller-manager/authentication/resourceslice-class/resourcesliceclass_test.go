@@ -0,0 +1,323 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcesliceclass
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+const (
+	tenantNamespace = "liqo-tenant-consumer"
+	consumerID      = liqov1beta1.ClusterID("consumer")
+)
+
+func resources(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}
+}
+
+func node(name, cpu, memory string, ready bool) *corev1.Node {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: resources(cpu, memory),
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func pod(name, nodeName, cpu, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{
+				Name:      "main",
+				Resources: corev1.ResourceRequirements{Requests: resources(cpu, memory)},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func resourceSlice(name string, requested corev1.ResourceList, granted corev1.ResourceList) *authv1beta1.ResourceSlice {
+	consumer := consumerID
+	rs := &authv1beta1.ResourceSlice{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: tenantNamespace},
+		Spec:       authv1beta1.ResourceSliceSpec{ConsumerClusterID: &consumer, Resources: requested},
+	}
+	if granted != nil {
+		rs.Status.Resources = granted
+		rs.Status.Conditions = []authv1beta1.ResourceSliceCondition{{
+			Type:   authv1beta1.ResourceSliceConditionTypeResources,
+			Status: authv1beta1.ResourceSliceConditionAccepted,
+		}}
+	}
+	return rs
+}
+
+func replicated(rs *authv1beta1.ResourceSlice) *authv1beta1.ResourceSlice {
+	rs.Labels = map[string]string{consts.ReplicationOriginLabel: string(consumerID), consts.ReplicationStatusLabel: "true"}
+	return rs
+}
+
+func offloaded(p *corev1.Pod) *corev1.Pod {
+	p.Labels = map[string]string{consts.ManagedByLabelKey: consts.ManagedByShadowPodValue}
+	return p
+}
+
+var _ = Describe("ResourceSlice class engines", func() {
+	var (
+		ctx    context.Context
+		scheme *runtime.Scheme
+		tenant *authv1beta1.Tenant
+		opts   *Options
+	)
+
+	newClient := func(objs ...client.Object) client.Client {
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	decide := func(cl client.Client, name string, rs *authv1beta1.ResourceSlice) *Result {
+		engine, err := NewEngine(name, cl, opts)
+		Expect(err).ToNot(HaveOccurred())
+		res, err := engine.Decide(ctx, &Request{ResourceSlice: rs, Tenant: tenant, Requested: rs.Spec.Resources})
+		Expect(err).ToNot(HaveOccurred())
+		return res
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(authv1beta1.AddToScheme(scheme)).To(Succeed())
+
+		tenant = &authv1beta1.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "consumer", Namespace: tenantNamespace}}
+		opts = &Options{FreeCapacityPercentage: 50}
+	})
+
+	Describe("the registry", func() {
+		It("should handle the default and unset classes with the fixed engine by default", func() {
+			registry := NewDefaultRegistry()
+			Expect(registry.Classes()).To(ConsistOf(authv1beta1.ResourceSliceClassDefault, authv1beta1.ResourceSliceClassUnknown))
+			engine, found := registry.Get(authv1beta1.ResourceSliceClassUnknown)
+			Expect(found).To(BeTrue())
+			Expect(engine).To(BeAssignableToTypeOf(&fixedEngine{}))
+
+			_, found = registry.Get("custom")
+			Expect(found).To(BeFalse())
+		})
+
+		It("should map the configured classes to the built-in engines", func() {
+			registry, err := NewRegistryFromConfig(map[string]string{"default": EngineTenantCap, "shared": EngineCounterOffer}, newClient(), opts)
+			Expect(err).ToNot(HaveOccurred())
+
+			engine, found := registry.Get(authv1beta1.ResourceSliceClassUnknown)
+			Expect(found).To(BeTrue())
+			Expect(engine).To(BeAssignableToTypeOf(&tenantCapEngine{}))
+			engine, found = registry.Get("shared")
+			Expect(found).To(BeTrue())
+			Expect(engine).To(BeAssignableToTypeOf(&counterOfferEngine{}))
+		})
+
+		It("should fail with an unknown engine", func() {
+			_, err := NewRegistryFromConfig(map[string]string{"shared": "unknown"}, newClient(), opts)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("the fixed engine", func() {
+		It("should grant the requested resources", func() {
+			res := decide(newClient(), EngineFixed, resourceSlice("rs", resources("100", "1Ti"), nil))
+			Expect(res.Decision).To(Equal(DecisionAccept))
+			Expect(res.Resources).To(Equal(resources("100", "1Ti")))
+		})
+	})
+
+	Describe("the free-capacity engine", func() {
+		var cl client.Client
+
+		BeforeEach(func() {
+			cl = newClient(
+				node("node-1", "4", "8Gi", true), node("node-2", "4", "8Gi", true), node("node-3", "16", "64Gi", false),
+				pod("pod-1", "node-1", "2", "4Gi"), pod("pod-2", "node-3", "8", "32Gi"),
+			)
+		})
+
+		It("should compute the allocatable and free capacity of the ready nodes", func() {
+			allocatable, free, err := Capacity(ctx, cl)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(EqualResources(allocatable, resources("8", "16Gi"))).To(BeTrue())
+			Expect(EqualResources(free, resources("6", "12Gi"))).To(BeTrue())
+		})
+
+		It("should not account for the pods offloaded by the consumer clusters", func() {
+			Expect(cl.Create(ctx, offloaded(pod("offloaded", "node-2", "2", "2Gi")))).To(Succeed())
+			_, free, err := Capacity(ctx, cl)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(EqualResources(free, resources("6", "12Gi"))).To(BeTrue())
+		})
+
+		It("should grant the requested resources within the allocatable capacity share", func() {
+			res := decide(cl, EngineFreeCapacity, resourceSlice("rs", resources("2", "4Gi"), nil))
+			Expect(res.Decision).To(Equal(DecisionAccept))
+			Expect(EqualResources(res.Resources, resources("2", "4Gi"))).To(BeTrue())
+		})
+
+		It("should bound the requested resources by the allocatable capacity share", func() {
+			res := decide(cl, EngineFreeCapacity, resourceSlice("rs", resources("8", "4Gi"), nil))
+			Expect(res.Decision).To(Equal(DecisionAccept))
+			Expect(EqualResources(res.Resources, resources("4", "4Gi"))).To(BeTrue())
+		})
+
+		It("should subtract the resources granted to the other ResourceSlices from the free capacity", func() {
+			Expect(cl.Create(ctx, replicated(resourceSlice("other", nil, resources("3", "2Gi"))))).To(Succeed())
+			res := decide(cl, EngineFreeCapacity, resourceSlice("rs", resources("8", "4Gi"), nil))
+			Expect(res.Decision).To(Equal(DecisionAccept))
+			Expect(EqualResources(res.Resources, resources("3", "4Gi"))).To(BeTrue())
+		})
+
+		It("should not add back the resources already granted to the ResourceSlice", func() {
+			Expect(cl.Create(ctx, replicated(resourceSlice("other", nil, resources("3", "2Gi"))))).To(Succeed())
+			res := decide(cl, EngineFreeCapacity, replicated(resourceSlice("rs", resources("8", "4Gi"), resources("3", "4Gi"))))
+			Expect(res.Decision).To(Equal(DecisionAccept))
+			Expect(EqualResources(res.Resources, resources("3", "4Gi"))).To(BeTrue())
+		})
+
+		It("should deny the ResourceSlice when no capacity is left", func() {
+			cl = newClient(node("node-1", "4", "8Gi", true), pod("pod-1", "node-1", "4", "1Gi"))
+			res := decide(cl, EngineFreeCapacity, resourceSlice("rs", resources("1", "1Gi"), nil))
+			Expect(res.Decision).To(Equal(DecisionDeny))
+		})
+	})
+
+	Describe("the tenant-cap engine", func() {
+		It("should grant the requested resources when no cap is set", func() {
+			res := decide(newClient(), EngineTenantCap, resourceSlice("rs", resources("100", "1Ti"), nil))
+			Expect(res.Decision).To(Equal(DecisionAccept))
+			Expect(res.Resources).To(Equal(resources("100", "1Ti")))
+		})
+
+		It("should bound the requested resources by the cap minus the resources granted to the other ResourceSlices", func() {
+			opts.TenantCap = resources("8", "16Gi")
+			cl := newClient(resourceSlice("other", nil, resources("6", "4Gi")))
+			res := decide(cl, EngineTenantCap, resourceSlice("rs", resources("4", "4Gi"), nil))
+			Expect(res.Decision).To(Equal(DecisionAccept))
+			Expect(EqualResources(res.Resources, resources("2", "4Gi"))).To(BeTrue())
+		})
+
+		It("should honor the cap overridden by the tenant annotation", func() {
+			opts.TenantCap = resources("8", "16Gi")
+			tenant.Annotations = map[string]string{consts.TenantResourceCapAnnotation: "cpu=1"}
+			res := decide(newClient(), EngineTenantCap, resourceSlice("rs", resources("4", "4Gi"), nil))
+			Expect(res.Decision).To(Equal(DecisionAccept))
+			Expect(EqualResources(res.Resources, resources("1", "4Gi"))).To(BeTrue())
+		})
+
+		It("should deny the ResourceSlice when the cap is reached", func() {
+			opts.TenantCap = resources("8", "16Gi")
+			cl := newClient(resourceSlice("other", nil, resources("8", "4Gi")))
+			res := decide(cl, EngineTenantCap, resourceSlice("rs", resources("4", "4Gi"), nil))
+			Expect(res.Decision).To(Equal(DecisionDeny))
+		})
+	})
+
+	Describe("the counter-offer engine", func() {
+		var cl client.Client
+
+		BeforeEach(func() {
+			cl = newClient(node("node-1", "8", "16Gi", true))
+			opts.TenantCap = resources("2", "16Gi")
+		})
+
+		It("should grant the requested resources when available", func() {
+			res := decide(cl, EngineCounterOffer, resourceSlice("rs", resources("1", "1Gi"), nil))
+			Expect(res.Decision).To(Equal(DecisionAccept))
+		})
+
+		It("should offer the available resources when less than the requested ones", func() {
+			res := decide(cl, EngineCounterOffer, resourceSlice("rs", resources("4", "1Gi"), nil))
+			Expect(res.Decision).To(Equal(DecisionOffer))
+			Expect(EqualResources(res.Resources, resources("2", "1Gi"))).To(BeTrue())
+			Expect(res.Message).To(ContainSubstring("offerResponse"))
+			Expect(res.Message).To(ContainSubstring(OfferID(resources("4", "1Gi"), resources("2", "1Gi"))))
+		})
+
+		It("should grant the offered resources once accepted by the consumer", func() {
+			rs := resourceSlice("rs", resources("4", "1Gi"), nil)
+			rs.Spec.OfferResponse = authv1beta1.ResourceSliceOfferAccepted
+			rs.Spec.OfferID = OfferID(resources("4", "1Gi"), resources("2", "1Gi"))
+			res := decide(cl, EngineCounterOffer, rs)
+			Expect(res.Decision).To(Equal(DecisionAccept))
+			Expect(EqualResources(res.Resources, resources("2", "1Gi"))).To(BeTrue())
+		})
+
+		It("should deny the ResourceSlice once the offer is rejected by the consumer", func() {
+			rs := resourceSlice("rs", resources("4", "1Gi"), nil)
+			rs.Spec.OfferResponse = authv1beta1.ResourceSliceOfferRejected
+			rs.Spec.OfferID = OfferID(resources("4", "1Gi"), resources("2", "1Gi"))
+			res := decide(cl, EngineCounterOffer, rs)
+			Expect(res.Decision).To(Equal(DecisionDeny))
+		})
+
+		It("should ignore a response not referring to any offer", func() {
+			rs := resourceSlice("rs", resources("4", "1Gi"), nil)
+			rs.Spec.OfferResponse = authv1beta1.ResourceSliceOfferAccepted
+			res := decide(cl, EngineCounterOffer, rs)
+			Expect(res.Decision).To(Equal(DecisionOffer))
+		})
+
+		It("should ignore the response to a previous offer, once the request changes", func() {
+			rs := resourceSlice("rs", resources("8", "1Gi"), nil)
+			rs.Spec.OfferResponse = authv1beta1.ResourceSliceOfferAccepted
+			rs.Spec.OfferID = OfferID(resources("4", "1Gi"), resources("2", "1Gi"))
+			res := decide(cl, EngineCounterOffer, rs)
+			Expect(res.Decision).To(Equal(DecisionOffer))
+			Expect(EqualResources(res.Resources, resources("2", "1Gi"))).To(BeTrue())
+		})
+	})
+
+	Describe("the OfferID function", func() {
+		It("should not depend on the format of the quantities", func() {
+			Expect(OfferID(resources("1", "1Gi"), resources("1000m", "1024Mi"))).To(
+				Equal(OfferID(resources("1000m", "1024Mi"), resources("1", "1Gi"))))
+		})
+
+		It("should change when either the requested or the offered resources change", func() {
+			id := OfferID(resources("4", "1Gi"), resources("2", "1Gi"))
+			Expect(OfferID(resources("8", "1Gi"), resources("2", "1Gi"))).ToNot(Equal(id))
+			Expect(OfferID(resources("4", "1Gi"), resources("3", "1Gi"))).ToNot(Equal(id))
+		})
+	})
+})
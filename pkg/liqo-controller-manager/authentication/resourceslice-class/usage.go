@@ -0,0 +1,220 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcesliceclass
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	"github.com/liqotech/liqo/internal/crdReplicator/reflection"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+// Capacity returns the allocatable resources of the ready physical nodes of the local cluster, and the free ones,
+// i.e., the allocatable resources minus the ones requested by the local pods running on them. The pods offloaded
+// by the consumer clusters are not accounted for, as they use the resources granted to the ResourceSlices.
+func Capacity(ctx context.Context, cl client.Client) (allocatable, free corev1.ResourceList, err error) {
+	nodes, err := getters.ListPhysicalNodes(ctx, cl)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list the physical nodes: %w", err)
+	}
+
+	allocatable = corev1.ResourceList{}
+	schedulable := map[string]struct{}{}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if node.Spec.Unschedulable || !isNodeReady(node) {
+			continue
+		}
+
+		schedulable[node.Name] = struct{}{}
+		addResources(allocatable, node.Status.Allocatable)
+	}
+
+	var pods corev1.PodList
+	if err := cl.List(ctx, &pods); err != nil {
+		return nil, nil, fmt.Errorf("unable to list the pods: %w", err)
+	}
+
+	free = allocatable.DeepCopy()
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if _, found := schedulable[pod.Spec.NodeName]; !found || isOffloaded(pod) ||
+			pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		requests, _ := resourcehelper.PodRequestsAndLimits(pod)
+		for name, quantity := range requests {
+			if current, found := free[name]; found {
+				current.Sub(quantity)
+				free[name] = current
+			}
+		}
+	}
+
+	for name, quantity := range free {
+		if quantity.Sign() < 0 {
+			free[name] = *resource.NewQuantity(0, quantity.Format)
+		}
+	}
+	return allocatable, free, nil
+}
+
+// isOffloaded returns whether the given pod has been offloaded by a consumer cluster.
+func isOffloaded(pod *corev1.Pod) bool {
+	if pod.Labels[consts.ManagedByLabelKey] == consts.ManagedByShadowPodValue {
+		return true
+	}
+	_, found := pod.Labels[forge.LiqoOriginClusterNodeName]
+	return found
+}
+
+// GrantedToOthers returns the resources granted by the local cluster to all the ResourceSlices (of any consumer)
+// whose resources have been accepted, except the given one.
+func GrantedToOthers(ctx context.Context, cl client.Client, resourceSlice *authv1beta1.ResourceSlice) (corev1.ResourceList, error) {
+	// Only the ResourceSlices replicated by the consumer clusters request resources from the local cluster.
+	labelSelector := reflection.ReplicatedResourcesLabelSelector()
+	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	var resourceSlices authv1beta1.ResourceSliceList
+	if err := cl.List(ctx, &resourceSlices, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("unable to list the ResourceSlices: %w", err)
+	}
+
+	granted := corev1.ResourceList{}
+	for i := range resourceSlices.Items {
+		other := &resourceSlices.Items[i]
+		if other.Namespace == resourceSlice.Namespace && other.Name == resourceSlice.Name {
+			continue
+		}
+		addResources(granted, grantedResources(other))
+	}
+	return granted, nil
+}
+
+// GrantedToConsumer returns the resources granted to the consumer cluster of the given ResourceSlice
+// through its other ResourceSlices whose resources have been accepted.
+func GrantedToConsumer(ctx context.Context, cl client.Client, resourceSlice *authv1beta1.ResourceSlice) (corev1.ResourceList, error) {
+	var resourceSlices authv1beta1.ResourceSliceList
+	if err := cl.List(ctx, &resourceSlices, client.InNamespace(resourceSlice.Namespace)); err != nil {
+		return nil, fmt.Errorf("unable to list the ResourceSlices in namespace %q: %w", resourceSlice.Namespace, err)
+	}
+
+	granted := corev1.ResourceList{}
+	for i := range resourceSlices.Items {
+		other := &resourceSlices.Items[i]
+		if other.Name == resourceSlice.Name || other.Spec.ConsumerClusterID == nil || resourceSlice.Spec.ConsumerClusterID == nil ||
+			*other.Spec.ConsumerClusterID != *resourceSlice.Spec.ConsumerClusterID {
+			continue
+		}
+		addResources(granted, grantedResources(other))
+	}
+	return granted, nil
+}
+
+// grantedResources returns the resources currently granted to the given ResourceSlice.
+func grantedResources(resourceSlice *authv1beta1.ResourceSlice) corev1.ResourceList {
+	cond := authentication.GetCondition(resourceSlice, authv1beta1.ResourceSliceConditionTypeResources)
+	if cond == nil || cond.Status != authv1beta1.ResourceSliceConditionAccepted {
+		return nil
+	}
+	return resourceSlice.Status.Resources
+}
+
+// bound returns the requested resources, each one limited to the available quantity, if any.
+func bound(requested, available corev1.ResourceList) corev1.ResourceList {
+	bounded := requested.DeepCopy()
+	for name, quantity := range bounded {
+		if limit, found := available[name]; found && limit.Cmp(quantity) < 0 {
+			bounded[name] = limit.DeepCopy()
+		}
+	}
+	return bounded
+}
+
+// exhausted returns the names of the resources which are not available at all, sorted by name.
+func exhausted(requested, bounded corev1.ResourceList) []string {
+	var names []string
+	for name, quantity := range bounded {
+		if req := requested[name]; quantity.Sign() <= 0 && req.Sign() > 0 {
+			names = append(names, string(name))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EqualResources returns whether the two lists contain the same quantities of the same resources.
+func EqualResources(a, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, quantity := range a {
+		other, found := b[name]
+		if !found || quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// formatResources returns a human-readable representation of the given resources.
+func formatResources(resources corev1.ResourceList) string {
+	items := make([]string, 0, len(resources))
+	for name, quantity := range resources {
+		items = append(items, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ", ")
+}
+
+// addResources adds the resources of src to dst.
+func addResources(dst, src corev1.ResourceList) {
+	for name, quantity := range src {
+		current, found := dst[name]
+		if !found {
+			dst[name] = quantity.DeepCopy()
+			continue
+		}
+		current.Add(quantity)
+		dst[name] = current
+	}
+}
+
+// isNodeReady returns whether the given node is ready.
+func isNodeReady(node *corev1.Node) bool {
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == corev1.NodeReady {
+			return node.Status.Conditions[i].Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcesliceautoscalercontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8shelper "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	"github.com/liqotech/liqo/pkg/utils/indexer"
+)
+
+// demand contains the resources requested by the pods which are (or could be) scheduled on a virtual node.
+type demand struct {
+	resources   corev1.ResourceList
+	pendingPods int32
+}
+
+// computeDemand returns the resources requested by the pods scheduled on the given node, and by the pending pods
+// which could be scheduled on it, since they tolerate its taints and match its required node affinity. Each pending
+// pod is accounted for by a single node among the candidate ones, so that it does not scale up multiple ResourceSlices.
+func computeDemand(ctx context.Context, cl client.Client, node *corev1.Node, candidates []*corev1.Node) (*demand, error) {
+	result := &demand{resources: corev1.ResourceList{}}
+
+	var scheduled corev1.PodList
+	if err := cl.List(ctx, &scheduled, client.MatchingFields{indexer.FieldNodeNameFromPod: node.Name}); err != nil {
+		return nil, fmt.Errorf("unable to list the pods scheduled on node %q: %w", node.Name, err)
+	}
+	for i := range scheduled.Items {
+		if !isTerminated(&scheduled.Items[i]) {
+			addResources(result.resources, podRequests(&scheduled.Items[i]))
+		}
+	}
+
+	var unscheduled corev1.PodList
+	if err := cl.List(ctx, &unscheduled, client.MatchingFields{indexer.FieldNodeNameFromPod: ""}); err != nil {
+		return nil, fmt.Errorf("unable to list the pending pods: %w", err)
+	}
+	for i := range unscheduled.Items {
+		if pod := &unscheduled.Items[i]; isUnschedulable(pod) && assignedNode(pod, node, candidates) == node.Name {
+			addResources(result.resources, podRequests(pod))
+			result.pendingPods++
+		}
+	}
+
+	return result, nil
+}
+
+// scale returns the resources to be requested by the ResourceSlice, so that the demand amounts to the target utilization,
+// within the bounds of the autoscaler. Scale ups are applied immediately, while scale downs are applied only if no pod is
+// pending and the scale down delay has elapsed since the last resize.
+func scale(autoscaler *authv1beta1.ResourceSliceAutoscaler, current corev1.ResourceList, d *demand, now time.Time) corev1.ResourceList {
+	canScaleDown := d.pendingPods == 0 && (autoscaler.Status.LastScaleTime == nil ||
+		now.Sub(autoscaler.Status.LastScaleTime.Time) >= autoscaler.Spec.ScaleDownDelay.Duration)
+
+	target := int64(autoscaler.Spec.TargetUtilization)
+	if target <= 0 || target > 100 {
+		target = 100
+	}
+
+	desired := current.DeepCopy()
+	if desired == nil {
+		desired = corev1.ResourceList{}
+	}
+	for name, upper := range autoscaler.Spec.MaxResources {
+		quantity := targetQuantity(name, d.resources[name], target)
+		if lower, found := autoscaler.Spec.MinResources[name]; found && quantity.Cmp(lower) < 0 {
+			quantity = lower.DeepCopy()
+		}
+		if quantity.Cmp(upper) > 0 {
+			quantity = upper.DeepCopy()
+		}
+
+		value, found := current[name]
+		switch {
+		case !found, quantity.Cmp(value) > 0:
+			desired[name] = quantity
+		case quantity.Cmp(value) < 0 && canScaleDown:
+			desired[name] = quantity
+		}
+	}
+	return desired
+}
+
+// targetQuantity returns the quantity such that the demanded one amounts to the given target percentage of it.
+func targetQuantity(name corev1.ResourceName, demanded resource.Quantity, target int64) resource.Quantity {
+	if name == corev1.ResourceCPU {
+		return *resource.NewMilliQuantity(ceilDiv(demanded.MilliValue()*100, target), resource.DecimalSI)
+	}
+	return *resource.NewQuantity(ceilDiv(demanded.Value()*100, target), demanded.Format)
+}
+
+func ceilDiv(a, b int64) int64 {
+	return (a + b - 1) / b
+}
+
+// equalResources returns whether the two resource lists contain the same quantities.
+func equalResources(a, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, quantity := range a {
+		if other, found := b[name]; !found || other.Cmp(quantity) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func addResources(total, add corev1.ResourceList) {
+	for name, quantity := range add {
+		if current, found := total[name]; found {
+			current.Add(quantity)
+			total[name] = current
+		} else {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests, _ := resourcehelper.PodRequestsAndLimits(pod)
+	requests[corev1.ResourcePods] = *resource.NewQuantity(1, resource.DecimalSI)
+	return requests
+}
+
+func isTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// isUnschedulable returns whether the scheduler failed to find a node for the given pod.
+func isUnschedulable(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodPending || pod.DeletionTimestamp != nil {
+		return false
+	}
+	for i := range pod.Status.Conditions {
+		cond := &pod.Status.Conditions[i]
+		if cond.Type == corev1.PodScheduled {
+			return cond.Status == corev1.ConditionFalse && cond.Reason == corev1.PodReasonUnschedulable
+		}
+	}
+	return false
+}
+
+// assignedNode returns the name of the node the given pending pod is accounted for by, i.e., the first one (by name),
+// among the given node and the candidate ones, it could be scheduled on. An empty string is returned if none.
+func assignedNode(pod *corev1.Pod, node *corev1.Node, candidates []*corev1.Node) string {
+	assigned := ""
+	for _, candidate := range append([]*corev1.Node{node}, candidates...) {
+		if (assigned == "" || candidate.Name < assigned) && couldBeScheduledOn(pod, candidate) {
+			assigned = candidate.Name
+		}
+	}
+	return assigned
+}
+
+// couldBeScheduledOn returns whether the given pod tolerates the taints of the given node and matches its required node affinity.
+func couldBeScheduledOn(pod *corev1.Pod, node *corev1.Node) bool {
+	if _, untolerated := k8shelper.FindMatchingUntoleratedTaint(node.Spec.Taints, pod.Spec.Tolerations, func(t *corev1.Taint) bool {
+		return t.Effect == corev1.TaintEffectNoSchedule || t.Effect == corev1.TaintEffectNoExecute
+	}); untolerated {
+		return false
+	}
+	match, err := nodeaffinity.GetRequiredNodeAffinity(pod).Match(node)
+	return err == nil && match
+}
+
+// formatResources returns a compact representation of the given resources, sorted by name.
+func formatResources(resources corev1.ResourceList) string {
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	values := make([]string, len(names))
+	for i, name := range names {
+		quantity := resources[corev1.ResourceName(name)]
+		values[i] = fmt.Sprintf("%s=%s", name, quantity.String())
+	}
+	return strings.Join(values, ",")
+}
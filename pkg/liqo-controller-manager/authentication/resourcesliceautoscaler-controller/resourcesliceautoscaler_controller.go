@@ -0,0 +1,178 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcesliceautoscalercontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	klog "k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+)
+
+// NewResourceSliceAutoscalerReconciler returns a new ResourceSliceAutoscalerReconciler.
+func NewResourceSliceAutoscalerReconciler(cl client.Client, s *runtime.Scheme,
+	recorder record.EventRecorder, syncPeriod time.Duration) *ResourceSliceAutoscalerReconciler {
+	return &ResourceSliceAutoscalerReconciler{
+		Client: cl,
+		Scheme: s,
+
+		eventRecorder: recorder,
+		syncPeriod:    syncPeriod,
+	}
+}
+
+// ResourceSliceAutoscalerReconciler reconciles a ResourceSliceAutoscaler object.
+type ResourceSliceAutoscalerReconciler struct {
+	client.Client
+	*runtime.Scheme
+
+	eventRecorder record.EventRecorder
+	syncPeriod    time.Duration
+}
+
+// cluster-role
+// +kubebuilder:rbac:groups=authentication.liqo.io,resources=resourcesliceautoscalers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=authentication.liqo.io,resources=resourcesliceautoscalers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=authentication.liqo.io,resources=resourceslices,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=virtualnodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=nodes;pods,verbs=get;list;watch
+
+// Reconcile resizes the ResourceSlice referenced by a ResourceSliceAutoscaler according to the demand.
+func (r *ResourceSliceAutoscalerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var autoscaler authv1beta1.ResourceSliceAutoscaler
+	if err := r.Get(ctx, req.NamespacedName, &autoscaler); err != nil {
+		if kerrors.IsNotFound(err) {
+			klog.V(4).Infof("ResourceSliceAutoscaler %q not found", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("Unable to get ResourceSliceAutoscaler %q: %v", req.NamespacedName, err)
+		return ctrl.Result{}, err
+	}
+
+	rsKey := types.NamespacedName{Name: autoscaler.Spec.ResourceSliceName, Namespace: autoscaler.Namespace}
+	resourceSlice, node, err := r.getResourceSliceAndNode(ctx, &autoscaler)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			klog.V(4).Infof("ResourceSliceAutoscaler %q: %v", req.NamespacedName, err)
+			return ctrl.Result{RequeueAfter: r.syncPeriod}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// The pending pods are accounted for by a single ResourceSlice, among the autoscaled ones they could be scheduled on.
+	candidates, err := r.autoscaledNodes(ctx)
+	if err != nil {
+		klog.Errorf("Unable to get the nodes of the autoscaled ResourceSlices: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	d, err := computeDemand(ctx, r.Client, node, candidates)
+	if err != nil {
+		klog.Errorf("Unable to compute the demand of ResourceSliceAutoscaler %q: %v", req.NamespacedName, err)
+		return ctrl.Result{}, err
+	}
+
+	now := time.Now()
+	desired := scale(&autoscaler, resourceSlice.Spec.Resources, d, now)
+	if !equalResources(desired, resourceSlice.Spec.Resources) {
+		previous := resourceSlice.Spec.Resources
+		resourceSlice.Spec.Resources = desired
+		if err := r.Update(ctx, resourceSlice); err != nil {
+			klog.Errorf("Unable to resize ResourceSlice %q: %v", rsKey, err)
+			return ctrl.Result{}, err
+		}
+
+		msg := fmt.Sprintf("ResourceSlice %q resized from %s to %s", rsKey.Name, formatResources(previous), formatResources(desired))
+		klog.Infof("ResourceSliceAutoscaler %q: %s", req.NamespacedName, msg)
+		r.eventRecorder.Event(&autoscaler, corev1.EventTypeNormal, "ResourceSliceResized", msg)
+		autoscaler.Status.LastScaleTime = &metav1.Time{Time: now}
+	}
+
+	autoscaler.Status.Demand = d.resources
+	autoscaler.Status.PendingPods = d.pendingPods
+	autoscaler.Status.Resources = resourceSlice.Spec.Resources
+	if err := r.Status().Update(ctx, &autoscaler); err != nil {
+		klog.Errorf("Unable to update the status of ResourceSliceAutoscaler %q: %v", req.NamespacedName, err)
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: r.syncPeriod}, nil
+}
+
+// getResourceSliceAndNode returns the ResourceSlice referenced by the given autoscaler and the node of its virtual node.
+// A NotFound error is returned if any of them does not exist (yet).
+func (r *ResourceSliceAutoscalerReconciler) getResourceSliceAndNode(ctx context.Context,
+	autoscaler *authv1beta1.ResourceSliceAutoscaler) (*authv1beta1.ResourceSlice, *corev1.Node, error) {
+	var resourceSlice authv1beta1.ResourceSlice
+	rsKey := types.NamespacedName{Name: autoscaler.Spec.ResourceSliceName, Namespace: autoscaler.Namespace}
+	if err := r.Get(ctx, rsKey, &resourceSlice); err != nil {
+		return nil, nil, fmt.Errorf("unable to get the ResourceSlice %q: %w", rsKey, err)
+	}
+
+	virtualNode, err := getters.GetVirtualNodeByResourceSlice(ctx, r.Client, &resourceSlice)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	node, err := getters.GetNodeFromVirtualNode(ctx, r.Client, virtualNode)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to get the Node of VirtualNode %q: %w", client.ObjectKeyFromObject(virtualNode), err)
+	}
+	return &resourceSlice, node, nil
+}
+
+// autoscaledNodes returns the nodes of the virtual nodes of all the ResourceSlices with an autoscaler.
+func (r *ResourceSliceAutoscalerReconciler) autoscaledNodes(ctx context.Context) ([]*corev1.Node, error) {
+	var autoscalers authv1beta1.ResourceSliceAutoscalerList
+	if err := r.List(ctx, &autoscalers); err != nil {
+		return nil, fmt.Errorf("unable to list the ResourceSliceAutoscalers: %w", err)
+	}
+
+	nodes := make([]*corev1.Node, 0, len(autoscalers.Items))
+	for i := range autoscalers.Items {
+		_, node, err := r.getResourceSliceAndNode(ctx, &autoscalers.Items[i])
+		switch {
+		case kerrors.IsNotFound(err):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ResourceSliceAutoscalerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlResourceSliceAutoscaler).
+		// The autoscaler is periodically requeued, hence the updates of its status do not need to trigger a reconciliation.
+		For(&authv1beta1.ResourceSliceAutoscaler{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
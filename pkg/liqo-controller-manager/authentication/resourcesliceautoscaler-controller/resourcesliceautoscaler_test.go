@@ -0,0 +1,170 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcesliceautoscalercontroller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	"github.com/liqotech/liqo/pkg/utils/indexer"
+)
+
+func resources(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}
+}
+
+var _ = Describe("ResourceSliceAutoscaler", func() {
+	Describe("the scale function", func() {
+		var (
+			autoscaler *authv1beta1.ResourceSliceAutoscaler
+			now        time.Time
+		)
+
+		BeforeEach(func() {
+			now = time.Now()
+			autoscaler = &authv1beta1.ResourceSliceAutoscaler{
+				Spec: authv1beta1.ResourceSliceAutoscalerSpec{
+					MinResources:      resources("1", "1Gi"),
+					MaxResources:      resources("8", "16Gi"),
+					TargetUtilization: 50,
+					ScaleDownDelay:    metav1.Duration{Duration: 10 * time.Minute},
+				},
+			}
+		})
+
+		It("should scale up according to the target utilization", func() {
+			desired := scale(autoscaler, resources("2", "2Gi"), &demand{resources: resources("1500m", "2Gi")}, now)
+			Expect(equalResources(desired, resources("3", "4Gi"))).To(BeTrue())
+		})
+
+		It("should not exceed the maximum resources", func() {
+			desired := scale(autoscaler, resources("2", "2Gi"), &demand{resources: resources("10", "2Gi"), pendingPods: 3}, now)
+			Expect(equalResources(desired, resources("8", "4Gi"))).To(BeTrue())
+		})
+
+		It("should not go below the minimum resources", func() {
+			desired := scale(autoscaler, resources("2", "2Gi"), &demand{resources: corev1.ResourceList{}}, now)
+			Expect(equalResources(desired, resources("1", "1Gi"))).To(BeTrue())
+		})
+
+		It("should not scale down before the delay has elapsed since the last resize", func() {
+			autoscaler.Status.LastScaleTime = &metav1.Time{Time: now.Add(-time.Minute)}
+			desired := scale(autoscaler, resources("4", "2Gi"), &demand{resources: resources("1", "2Gi")}, now)
+			Expect(equalResources(desired, resources("4", "4Gi"))).To(BeTrue())
+		})
+
+		It("should not scale down while pods are pending", func() {
+			desired := scale(autoscaler, resources("4", "8Gi"), &demand{resources: resources("1", "8Gi"), pendingPods: 1}, now)
+			Expect(equalResources(desired, resources("4", "16Gi"))).To(BeTrue())
+		})
+
+		It("should leave the resources not bounded by the autoscaler unchanged", func() {
+			current := resources("2", "2Gi")
+			current[corev1.ResourcePods] = resource.MustParse("110")
+			desired := scale(autoscaler, current, &demand{resources: resources("1", "1Gi")}, now)
+			Expect(desired).To(HaveKeyWithValue(corev1.ResourcePods, resource.MustParse("110")))
+		})
+	})
+
+	Describe("the computeDemand function", func() {
+		var (
+			cl   client.Client
+			node *corev1.Node
+		)
+
+		pod := func(name, nodeName string, phase corev1.PodPhase, cpu string) *corev1.Pod {
+			p := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: corev1.PodSpec{
+					NodeName: nodeName,
+					Containers: []corev1.Container{{Name: "main", Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+					}}},
+				},
+				Status: corev1.PodStatus{Phase: phase},
+			}
+			if nodeName == "" {
+				p.Status.Conditions = []corev1.PodCondition{{
+					Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable,
+				}}
+			}
+			return p
+		}
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+			node = &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "virtual-node", Labels: map[string]string{"zone": "remote"}},
+				Spec: corev1.NodeSpec{Taints: []corev1.Taint{{
+					Key: "virtual-node.liqo.io/not-allowed", Value: "true", Effect: corev1.TaintEffectNoExecute,
+				}}},
+			}
+
+			tolerating := pod("pending-tolerating", "", corev1.PodPending, "2")
+			tolerating.Spec.Tolerations = []corev1.Toleration{{Key: "virtual-node.liqo.io/not-allowed", Operator: corev1.TolerationOpExists}}
+			otherZone := pod("pending-other-zone", "", corev1.PodPending, "4")
+			otherZone.Spec.Tolerations = tolerating.Spec.Tolerations
+			otherZone.Spec.NodeSelector = map[string]string{"zone": "local"}
+
+			cl = fake.NewClientBuilder().WithScheme(scheme).
+				WithIndex(&corev1.Pod{}, indexer.FieldNodeNameFromPod, indexer.ExtractNodeName).
+				WithObjects(
+					pod("running", "virtual-node", corev1.PodRunning, "1"),
+					pod("succeeded", "virtual-node", corev1.PodSucceeded, "8"),
+					pod("elsewhere", "local-node", corev1.PodRunning, "8"),
+					pod("pending-not-tolerating", "", corev1.PodPending, "8"),
+					tolerating, otherZone,
+				).Build()
+		})
+
+		It("should account for the scheduled and the pending pods which could be scheduled on the node", func() {
+			d, err := computeDemand(context.Background(), cl, node, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(d.pendingPods).To(BeNumerically("==", 1))
+			Expect(d.resources.Cpu().Cmp(resource.MustParse("3"))).To(BeZero())
+			Expect(d.resources.Pods().Cmp(resource.MustParse("2"))).To(BeZero())
+		})
+
+		It("should account for each pending pod on a single node among the candidate ones", func() {
+			other := node.DeepCopy()
+			other.Name = "another-virtual-node"
+
+			d, err := computeDemand(context.Background(), cl, node, []*corev1.Node{node, other})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(d.pendingPods).To(BeNumerically("==", 0))
+			Expect(d.resources.Cpu().Cmp(resource.MustParse("1"))).To(BeZero())
+
+			d, err = computeDemand(context.Background(), cl, other, []*corev1.Node{node, other})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(d.pendingPods).To(BeNumerically("==", 1))
+			Expect(d.resources.Cpu().Cmp(resource.MustParse("2"))).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,78 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authentication
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+// x509CredentialIDPrefix is the prefix of the credential ID the API server associates with the requests
+// authenticated through a client certificate, followed by the hex-encoded SHA-256 fingerprint of the certificate.
+const x509CredentialIDPrefix = "X509SHA256="
+
+// ForgeRevokedCertificate forges the RevokedCertificate recording the revocation of the given PEM-encoded certificate,
+// issued for the given identity. It is named after the fingerprint of the certificate.
+func ForgeRevokedCertificate(signedCrt []byte, identity *authv1beta1.RevokedIdentity,
+	clusterID liqov1beta1.ClusterID) (*authv1beta1.RevokedCertificate, error) {
+	block, _ := pem.Decode(signedCrt)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block containing certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	fingerprint := sha256.Sum256(cert.Raw)
+	return &authv1beta1.RevokedCertificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   hex.EncodeToString(fingerprint[:]),
+			Labels: map[string]string{consts.RemoteClusterID: string(clusterID)},
+		},
+		Spec: authv1beta1.RevokedCertificateSpec{
+			ConsumerClusterID: clusterID,
+			IdentityType:      identity.Type,
+			Name:              identity.Name,
+			User:              identity.User,
+			Groups:            identity.Groups,
+			NotAfter:          metav1.NewTime(cert.NotAfter),
+			RevokedAt:         identity.RevokedAt,
+		},
+	}, nil
+}
+
+// CertificateFingerprint returns the hex-encoded SHA-256 fingerprint of the client certificate the request described
+// by the given SubjectAccessReview has been authenticated with, and whether the API server provided it at all.
+func CertificateFingerprint(spec *authorizationv1.SubjectAccessReviewSpec) (string, bool) {
+	for _, id := range spec.Extra[serviceaccount.CredentialIDKey] {
+		if fingerprint, found := strings.CutPrefix(id, x509CredentialIDPrefix); found {
+			return strings.ToLower(fingerprint), true
+		}
+	}
+	return "", false
+}
@@ -18,10 +18,12 @@ import (
 	"context"
 	"crypto/ed25519"
 	"fmt"
+	"slices"
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
@@ -31,6 +33,7 @@ import (
 	controllerutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	"github.com/liqotech/liqo/pkg/consts"
 	identitymanager "github.com/liqotech/liqo/pkg/identityManager"
 	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
@@ -95,6 +98,7 @@ func NewTenantReconciler(cl client.Client, scheme *runtime.Scheme, config *rest.
 // cluster-role
 // +kubebuilder:rbac:groups=authentication.liqo.io,resources=tenants;tenants/status,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=authentication.liqo.io,resources=tenants;tenants/finalizers,verbs=update
+// +kubebuilder:rbac:groups=authentication.liqo.io,resources=revokedcertificates,verbs=get;list;watch;create
 // +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=namespaces/finalizers,verbs=update
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;deletecollection;delete
@@ -103,6 +107,10 @@ func NewTenantReconciler(cl client.Client, scheme *runtime.Scheme, config *rest.
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings/finalizers,verbs=update
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=serviceaccounts/token,verbs=create
+// The permissions on the heartbeat Leases are granted to the consumer control plane in the tenant namespace.
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
 
 // Reconcile manages the lifecycle of a Tenant.
 func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
@@ -137,7 +145,7 @@ func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 	}
 
 	// If the Tenant is drained we remove the binding of cluster roles used to replicate resources and
-	// delete all replicated resources.
+	// delete all replicated resources. If it is revoked, we additionally revoke the issued identities.
 	switch tenant.Spec.TenantCondition {
 	case authv1beta1.TenantConditionDrained:
 		if err := r.handleTenantDrained(ctx, tenant); err != nil {
@@ -145,6 +153,12 @@ func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 			return ctrl.Result{}, err
 		}
 		return ctrl.Result{}, nil
+	case authv1beta1.TenantConditionRevoked:
+		if err := r.handleTenantRevoked(ctx, tenant); err != nil {
+			klog.Errorf("Unable to handle revoked Tenant %q: %s", req.Name, err)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
 	case authv1beta1.TenantConditionCordoned:
 		if err := r.handleTenantCordoned(ctx, tenant); err != nil {
 			klog.Errorf("Unable to handle cordoned Tenant %q: %s", req.Name, err)
@@ -235,6 +249,7 @@ func (r *TenantReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res
 			CAOverride:               r.CAOverride,
 			TrustedCA:                r.TrustedCA,
 			ProxyURL:                 tenant.Spec.ProxyURL,
+			TenantGeneration:         tenant.Generation,
 		})
 		if err != nil {
 			klog.Errorf("Unable to forge the AuthParams for the Tenant %q: %s", req.Name, err)
@@ -420,3 +435,128 @@ func (r *TenantReconciler) handleTenantDrained(ctx context.Context, tenant *auth
 
 	return nil
 }
+
+// handleTenantRevoked revokes the identities issued to the tenant. Their certificates are recorded as RevokedCertificates,
+// which outlive the Tenant and are looked up by the revocation authorizer to deny their requests, the identities are
+// reported in the Tenant status, the ServiceAccounts backing token identities are deleted, and the AuthParams are no
+// longer advertised. Then, the tenant is drained.
+func (r *TenantReconciler) handleTenantRevoked(ctx context.Context, tenant *authv1beta1.Tenant) error {
+	resSlices, err := getters.ListResourceSlicesByLabel(ctx, r.Client, corev1.NamespaceAll,
+		liqolabels.RemoteLabelSelectorForCluster(string(tenant.Spec.ClusterID)))
+	if err != nil {
+		klog.Errorf("Failed to retrieve ResourceSlices for Tenant %q: %v", tenant.Name, err)
+		return err
+	}
+
+	var renews authv1beta1.RenewList
+	if err := r.List(ctx, &renews, client.InNamespace(tenant.Status.TenantNamespace)); err != nil {
+		klog.Errorf("Failed to retrieve the Renews for Tenant %q: %v", tenant.Name, err)
+		return err
+	}
+
+	now := metav1.Now()
+	revoked := []authv1beta1.RevokedIdentity{{
+		Type:      authv1beta1.ControlPlaneIdentityType,
+		Name:      tenant.Name,
+		User:      authentication.CommonNameControlPlaneCSR(tenant.Spec.ClusterID),
+		RevokedAt: now,
+	}}
+	// certificates contains the certificates issued for each revoked identity (including the renewed ones).
+	certificates := [][][]byte{signedCertificates(tenant.Status.AuthParams)}
+	for i := range resSlices {
+		if resSlices[i].Spec.ConsumerClusterID == nil {
+			continue
+		}
+		revoked = append(revoked, authv1beta1.RevokedIdentity{
+			Type:      authv1beta1.ResourceSliceIdentityType,
+			Name:      resSlices[i].Name,
+			User:      authentication.CommonNameResourceSliceCSR(&resSlices[i]),
+			Groups:    []string{authentication.OrganizationResourceSliceCSR(&resSlices[i])},
+			RevokedAt: now,
+		})
+		certificates = append(certificates, signedCertificates(resSlices[i].Status.AuthParams))
+	}
+	for i := range renews.Items {
+		renew := &renews.Items[i]
+		if renew.Spec.ConsumerClusterID != tenant.Spec.ClusterID {
+			continue
+		}
+		idx := slices.IndexFunc(revoked, func(id authv1beta1.RevokedIdentity) bool {
+			return id.Type == renew.Spec.IdentityType &&
+				(id.Type == authv1beta1.ControlPlaneIdentityType || (renew.Spec.ResourceSliceRef != nil && id.Name == renew.Spec.ResourceSliceRef.Name))
+		})
+		if idx >= 0 {
+			certificates[idx] = append(certificates[idx], signedCertificates(renew.Status.AuthParams)...)
+		}
+	}
+
+	// The revoked certificates are persisted before the AuthParams are dropped, and independently of the Tenant,
+	// so that they keep being denied in case the Tenant is deleted.
+	for i := range revoked {
+		for _, crt := range certificates[i] {
+			if err := r.ensureRevokedCertificate(ctx, crt, &revoked[i], tenant.Spec.ClusterID); err != nil {
+				klog.Errorf("Unable to revoke the certificate of %s identity %q of Tenant %q: %s", revoked[i].Type, revoked[i].Name, tenant.Name, err)
+				return err
+			}
+		}
+	}
+
+	for i := range revoked {
+		if slices.ContainsFunc(tenant.Status.RevokedIdentities, func(id authv1beta1.RevokedIdentity) bool {
+			return id.Type == revoked[i].Type && id.Name == revoked[i].Name
+		}) {
+			continue
+		}
+		tenant.Status.RevokedIdentities = append(tenant.Status.RevokedIdentities, revoked[i])
+	}
+	tenant.Status.AuthParams = nil
+
+	if err := r.Client.Status().Update(ctx, tenant); err != nil {
+		klog.Errorf("Unable to update the revoked identities of Tenant %q: %s", tenant.Name, err)
+		return err
+	}
+	r.EventRecorder.Event(tenant, corev1.EventTypeNormal, "IdentitiesRevoked",
+		fmt.Sprintf("%d identities revoked", len(tenant.Status.RevokedIdentities)))
+
+	// Delete the ServiceAccounts backing token identities, which invalidates the tokens issued for them.
+	var serviceAccounts corev1.ServiceAccountList
+	if err := r.List(ctx, &serviceAccounts, client.InNamespace(tenant.Namespace), client.MatchingLabels{
+		consts.RemoteClusterID:            string(tenant.Spec.ClusterID),
+		authentication.TokenIdentityLabel: "true",
+	}); err != nil {
+		klog.Errorf("Failed to retrieve the token ServiceAccounts for Tenant %q: %v", tenant.Name, err)
+		return err
+	}
+	for i := range serviceAccounts.Items {
+		if err := client.IgnoreNotFound(r.Delete(ctx, &serviceAccounts.Items[i])); err != nil {
+			klog.Errorf("Failed to delete ServiceAccount %q for Tenant %q: %v",
+				client.ObjectKeyFromObject(&serviceAccounts.Items[i]), tenant.Name, err)
+			return err
+		}
+	}
+
+	return r.handleTenantDrained(ctx, tenant)
+}
+
+// ensureRevokedCertificate records the revocation of the given certificate, issued for the given identity.
+func (r *TenantReconciler) ensureRevokedCertificate(ctx context.Context, signedCrt []byte,
+	identity *authv1beta1.RevokedIdentity, clusterID liqov1beta1.ClusterID) error {
+	revokedCertificate, err := authentication.ForgeRevokedCertificate(signedCrt, identity, clusterID)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Create(ctx, revokedCertificate); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	klog.V(4).Infof("Certificate %q of %s identity %q revoked", revokedCertificate.Name, identity.Type, identity.Name)
+	return nil
+}
+
+// signedCertificates returns the certificates carried by the given AuthParams, if any.
+func signedCertificates(authParams *authv1beta1.AuthParams) [][]byte {
+	if authParams == nil || len(authParams.SignedCRT) == 0 {
+		return nil
+	}
+	return [][]byte{authParams.SignedCRT}
+}
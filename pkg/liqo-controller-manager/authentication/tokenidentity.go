@@ -0,0 +1,108 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authentication
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// TokenIdentityLabel is the label used to identify the ServiceAccounts backing token identities.
+	TokenIdentityLabel = "liqo.io/token-identity"
+	// TokenIdentityUserAnnotation is the annotation storing the user of the certificate identity a token identity stands for.
+	TokenIdentityUserAnnotation = "liqo.io/token-identity-user"
+	// TokenIdentityGroupsAnnotation is the annotation storing the (comma separated) groups of the certificate identity
+	// a token identity stands for.
+	TokenIdentityGroupsAnnotation = "liqo.io/token-identity-groups"
+)
+
+// TokenIdentityUser returns the user and groups of the certificate identity the given ServiceAccount stands for,
+// and whether the ServiceAccount backs a token identity at all.
+func TokenIdentityUser(sa *corev1.ServiceAccount) (user string, groups []string, ok bool) {
+	if sa.Labels[TokenIdentityLabel] != "true" || sa.Annotations[TokenIdentityUserAnnotation] == "" {
+		return "", nil, false
+	}
+
+	if value := sa.Annotations[TokenIdentityGroupsAnnotation]; value != "" {
+		groups = strings.Split(value, ",")
+	}
+	return sa.Annotations[TokenIdentityUserAnnotation], groups, true
+}
+
+// TokenIdentitySubjects returns the subjects of the ServiceAccounts (among the given ones) backing the token identities which stand
+// for any of the given user and group subjects, so that they can be granted the same permissions of the certificate identities.
+func TokenIdentitySubjects(serviceAccounts []corev1.ServiceAccount, subjects []rbacv1.Subject) []rbacv1.Subject {
+	var result []rbacv1.Subject
+	for i := range serviceAccounts {
+		user, groups, ok := TokenIdentityUser(&serviceAccounts[i])
+		if !ok {
+			continue
+		}
+
+		if slices.ContainsFunc(subjects, func(subject rbacv1.Subject) bool {
+			return (subject.Kind == rbacv1.UserKind && subject.Name == user) ||
+				(subject.Kind == rbacv1.GroupKind && slices.Contains(groups, subject.Name))
+		}) {
+			result = append(result, rbacv1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      serviceAccounts[i].Name,
+				Namespace: serviceAccounts[i].Namespace,
+			})
+		}
+	}
+	return result
+}
+
+// MergeSubjects returns the given subjects, extended with the additional ones not already present.
+func MergeSubjects(subjects []rbacv1.Subject, additional ...rbacv1.Subject) []rbacv1.Subject {
+	for i := range additional {
+		if !slices.Contains(subjects, additional[i]) {
+			subjects = append(subjects, additional[i])
+		}
+	}
+	return subjects
+}
+
+// ResolveUser returns the user and groups a request is performed on behalf of. The requests authenticated through the token of a
+// ServiceAccount backing a token identity are mapped to the user and groups of the equivalent certificate identity, so that they
+// are handled the same way (e.g., the same quota applies), while all the others are returned unchanged.
+func ResolveUser(ctx context.Context, cl client.Client, user string, groups []string) (string, []string, error) {
+	namespace, name, err := serviceaccount.SplitUsername(user)
+	if err != nil {
+		// Not a ServiceAccount.
+		return user, groups, nil
+	}
+
+	var sa corev1.ServiceAccount
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &sa); err != nil {
+		if apierrors.IsNotFound(err) {
+			return user, groups, nil
+		}
+		return "", nil, err
+	}
+
+	if identityUser, identityGroups, ok := TokenIdentityUser(&sa); ok {
+		return identityUser, identityGroups, nil
+	}
+	return user, groups, nil
+}
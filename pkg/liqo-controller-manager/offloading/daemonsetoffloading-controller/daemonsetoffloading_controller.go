@@ -0,0 +1,581 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemonsetoffloadingctrl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	offloadingutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/utils"
+	"github.com/liqotech/liqo/pkg/utils"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+	podutils "github.com/liqotech/liqo/pkg/utils/pod"
+)
+
+// OffloadedPodsReadyCondition is the type of the DaemonSet condition reporting whether all the offloaded pods are ready.
+const OffloadedPodsReadyCondition appsv1.DaemonSetConditionType = "LiqoOffloadedPodsReady"
+
+// DaemonSetOffloadingReconciler reconciles the DaemonSets opted in for the offloading onto the virtual nodes,
+// creating one pod bound to a virtual node either for each provider cluster, for each virtual node or for each provider node.
+type DaemonSetOffloadingReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+}
+
+// target identifies where an offloaded pod shall run, i.e., the virtual node it is bound to and, in per-provider-node mode,
+// the provider node it is pinned to.
+type target struct {
+	node         *corev1.Node
+	providerNode string
+}
+
+func (t *target) key() string {
+	return fmt.Sprintf("%s/%s", t.node.Name, t.providerNode)
+}
+
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=namespaceoffloadings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=virtualnodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=authentication.liqo.io,resources=resourceslices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile DaemonSet objects.
+func (r *DaemonSetOffloadingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var ds appsv1.DaemonSet
+	if err := r.Get(ctx, req.NamespacedName, &ds); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The DaemonSet has been deleted, hence make sure that no offloaded pod is left behind.
+			klog.V(4).Infof("daemonset %q not found", req.NamespacedName)
+			return ctrl.Result{}, r.cleanup(ctx, req.Namespace, req.Name)
+		}
+		klog.Errorf("an error occurred while getting daemonset %q: %v", req.NamespacedName, err)
+		return ctrl.Result{}, err
+	}
+
+	mode, enabled := ds.Annotations[consts.DaemonSetOffloadingAnnotationKey]
+	if !enabled || !ds.DeletionTimestamp.IsZero() {
+		if err := r.cleanup(ctx, ds.Namespace, ds.Name); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, r.updateCondition(ctx, &ds, nil)
+	}
+
+	if mode != consts.DaemonSetOffloadingPerCluster && mode != consts.DaemonSetOffloadingPerVirtualNode &&
+		mode != consts.DaemonSetOffloadingPerProviderNode {
+		klog.Warningf("daemonset %q specifies an unknown offloading mode %q", req.NamespacedName, mode)
+		r.Recorder.Eventf(&ds, corev1.EventTypeWarning, "InvalidOffloadingMode", "Unknown offloading mode %q (supported: %s, %s, %s)", mode,
+			consts.DaemonSetOffloadingPerCluster, consts.DaemonSetOffloadingPerVirtualNode, consts.DaemonSetOffloadingPerProviderNode)
+		return ctrl.Result{}, nil
+	}
+
+	hash, err := offloadingutils.DaemonSetTemplateHash(&ds.Spec.Template)
+	if err != nil {
+		klog.Errorf("unable to compute the template hash of daemonset %q: %v", req.NamespacedName, err)
+		return ctrl.Result{}, err
+	}
+
+	pods, err := r.listPods(ctx, ds.Namespace, ds.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	targets, err := r.targets(ctx, &ds, mode, pods)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Delete the pods no longer matching the desired state, including those created on behalf of a previous DaemonSet
+	// with the same name. They are recreated once the deletion completes.
+	current := make(map[string]*corev1.Pod, len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		current[pod.Name] = pod
+
+		if !pod.DeletionTimestamp.IsZero() || isUpToDate(pod, &ds, targets, hash) {
+			continue
+		}
+
+		if err := client.IgnoreNotFound(r.Delete(ctx, pod)); err != nil {
+			klog.Errorf("unable to delete pod %q of daemonset %q: %v", klog.KObj(pod), req.NamespacedName, err)
+			return ctrl.Result{}, err
+		}
+		klog.Infof("pod %q of daemonset %q deleted, as outdated", klog.KObj(pod), req.NamespacedName)
+	}
+
+	// Create the missing pods.
+	for name, tgt := range targets {
+		if _, found := current[name]; found {
+			continue
+		}
+
+		pod, err := forgePod(&ds, tgt, name, hash)
+		if err != nil {
+			klog.Errorf("unable to forge pod %q of daemonset %q: %v", name, req.NamespacedName, err)
+			r.Recorder.Eventf(&ds, corev1.EventTypeWarning, "FailedCreate", "Error forging offloaded pod for node %s: %v", tgt.node.Name, err)
+			return ctrl.Result{}, nil
+		}
+
+		if err := r.Create(ctx, pod); err != nil && !apierrors.IsAlreadyExists(err) {
+			klog.Errorf("unable to create pod %q of daemonset %q: %v", klog.KObj(pod), req.NamespacedName, err)
+			r.Recorder.Eventf(&ds, corev1.EventTypeWarning, "FailedCreate", "Error creating offloaded pod for node %s: %v", tgt.node.Name, err)
+			return ctrl.Result{}, err
+		}
+		klog.Infof("pod %q of daemonset %q created on virtual node %s", klog.KObj(pod), req.NamespacedName, tgt.node.Name)
+		r.Recorder.Eventf(&ds, corev1.EventTypeNormal, "SuccessfulCreate", "Created offloaded pod %s on node %s", pod.Name, tgt.node.Name)
+	}
+
+	ready := 0
+	for name := range targets {
+		if pod, found := current[name]; found && pod.DeletionTimestamp.IsZero() {
+			if isReady, _ := podutils.IsPodReady(pod); isReady {
+				ready++
+			}
+		}
+	}
+
+	condition := appsv1.DaemonSetCondition{
+		Type:    OffloadedPodsReadyCondition,
+		Status:  corev1.ConditionTrue,
+		Reason:  "OffloadedPodsReady",
+		Message: fmt.Sprintf("%d/%d offloaded pods are ready", ready, len(targets)),
+	}
+	if ready < len(targets) {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "OffloadedPodsNotReady"
+	}
+	return ctrl.Result{}, r.updateCondition(ctx, &ds, &condition)
+}
+
+// isUpToDate returns whether the given pod, created on behalf of the given DaemonSet, matches the desired state.
+func isUpToDate(pod *corev1.Pod, ds *appsv1.DaemonSet, targets map[string]*target, hash string) bool {
+	tgt, desired := targets[pod.Name]
+	return desired && offloadingutils.IsOffloadedDaemonSetPod(pod, ds) && pod.Spec.NodeName == tgt.node.Name &&
+		pod.Annotations[consts.DaemonSetOffloadingTemplateHashAnnotationKey] == hash &&
+		pod.Annotations[consts.DaemonSetOffloadingProviderNodeAnnotationKey] == tgt.providerNode &&
+		pod.Status.Phase != corev1.PodFailed && pod.Status.Phase != corev1.PodSucceeded
+}
+
+// targets returns where the given DaemonSet shall be offloaded to, indexed by the name of the corresponding pod.
+// Only the virtual nodes associated with the clusters where the namespace has been successfully offloaded are considered.
+func (r *DaemonSetOffloadingReconciler) targets(ctx context.Context, ds *appsv1.DaemonSet,
+	mode string, pods []corev1.Pod) (map[string]*target, error) {
+	var nsoff offloadingv1beta1.NamespaceOffloading
+	if err := r.Get(ctx, types.NamespacedName{Namespace: ds.Namespace, Name: consts.DefaultNamespaceOffloadingName}, &nsoff); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("namespace %q is not offloaded, daemonset %q has no target nodes", ds.Namespace, klog.KObj(ds))
+			return map[string]*target{}, nil
+		}
+		klog.Errorf("unable to get the namespaceoffloading for namespace %q: %v", ds.Namespace, err)
+		return nil, err
+	}
+
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, client.MatchingLabels{consts.TypeLabel: consts.TypeNode}); err != nil {
+		klog.Errorf("unable to list virtual nodes: %v", err)
+		return nil, err
+	}
+
+	// Sort the nodes, to get a deterministic choice in case of multiple virtual nodes targeting the same cluster.
+	sort.Slice(nodes.Items, func(i, j int) bool { return nodes.Items[i].Name < nodes.Items[j].Name })
+
+	hosting := make(map[string]bool, len(pods))
+	for i := range pods {
+		if pods[i].DeletionTimestamp.IsZero() && offloadingutils.IsOffloadedDaemonSetPod(&pods[i], ds) {
+			hosting[(&target{node: &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: pods[i].Spec.NodeName}},
+				providerNode: pods[i].Annotations[consts.DaemonSetOffloadingProviderNodeAnnotationKey]}).key()] = true
+		}
+	}
+
+	// Stick to the target already hosting the pod, if any, and prefer ready nodes otherwise.
+	selected := make(map[string]*target)
+	selectTarget := func(key string, candidate *target) {
+		previous, found := selected[key]
+		if !found || (!hosting[previous.key()] &&
+			(hosting[candidate.key()] || (!utils.IsNodeReady(previous.node) && utils.IsNodeReady(candidate.node)))) {
+			selected[key] = candidate
+		}
+	}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		clusterID := node.Labels[consts.RemoteClusterID]
+		if !node.DeletionTimestamp.IsZero() || !offloadingutils.IsRemoteNamespaceReady(&nsoff, clusterID) {
+			continue
+		}
+
+		switch mode {
+		case consts.DaemonSetOffloadingPerVirtualNode:
+			selected[node.Name] = &target{node: node}
+		case consts.DaemonSetOffloadingPerCluster:
+			selectTarget(clusterID, &target{node: node})
+		case consts.DaemonSetOffloadingPerProviderNode:
+			providerNodes, err := r.providerNodes(ctx, node)
+			if err != nil {
+				return nil, err
+			}
+			// Multiple virtual nodes may select the same provider node, which shall host a single pod anyway.
+			for _, providerNode := range providerNodes {
+				selectTarget(fmt.Sprintf("%s/%s", clusterID, providerNode), &target{node: node, providerNode: providerNode})
+			}
+		}
+	}
+
+	targets := make(map[string]*target, len(selected))
+	for _, tgt := range selected {
+		targets[podName(ds, tgt)] = tgt
+	}
+	return targets, nil
+}
+
+// providerNodes returns the provider nodes selected through the OffloadingPatch of the given virtual node, among the ones
+// exposed by the provider cluster in the status of the associated ResourceSlice. No provider node is returned for the
+// virtual nodes not associated with any ResourceSlice.
+func (r *DaemonSetOffloadingReconciler) providerNodes(ctx context.Context, node *corev1.Node) ([]string, error) {
+	virtualNodes, err := getters.ListVirtualNodesByClusterID(ctx, r.Client, liqov1beta1.ClusterID(node.Labels[consts.RemoteClusterID]))
+	if err != nil {
+		klog.Errorf("unable to list the virtualnodes of node %q: %v", node.Name, err)
+		return nil, err
+	}
+
+	var virtualNode *offloadingv1beta1.VirtualNode
+	for i := range virtualNodes {
+		if virtualNodes[i].Name == node.Name {
+			virtualNode = &virtualNodes[i]
+			break
+		}
+	}
+	if virtualNode == nil {
+		klog.V(4).Infof("virtualnode %q not found, no provider node selected", node.Name)
+		return nil, nil
+	}
+
+	sliceName, found := virtualNode.Labels[consts.ResourceSliceNameLabelKey]
+	if owner := metav1.GetControllerOf(virtualNode); !found && owner != nil && owner.Kind == authv1beta1.ResourceSliceKind {
+		sliceName, found = owner.Name, true
+	}
+	if !found {
+		klog.V(4).Infof("virtualnode %q is not associated with any resourceslice, no provider node selected", klog.KObj(virtualNode))
+		return nil, nil
+	}
+
+	var resourceSlice authv1beta1.ResourceSlice
+	if err := r.Get(ctx, types.NamespacedName{Namespace: virtualNode.Namespace, Name: sliceName}, &resourceSlice); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("resourceslice %q not found, no provider node selected", klog.KRef(virtualNode.Namespace, sliceName))
+			return nil, nil
+		}
+		klog.Errorf("unable to get resourceslice %q: %v", klog.KRef(virtualNode.Namespace, sliceName), err)
+		return nil, err
+	}
+
+	// The OffloadingPatch constraints are applied to the offloaded pods, hence they further restrict the candidate nodes.
+	selector := &corev1.Pod{}
+	if patch := virtualNode.Spec.OffloadingPatch; patch != nil {
+		selector.Spec.NodeSelector = patch.NodeSelector
+		if patch.Affinity != nil && patch.Affinity.NodeAffinity != nil {
+			selector.Spec.Affinity = &corev1.Affinity{NodeAffinity: patch.Affinity.NodeAffinity}
+		}
+	}
+	required := nodeaffinity.GetRequiredNodeAffinity(selector)
+
+	var providerNodes []string
+	for i := range resourceSlice.Status.Nodes {
+		providerNode := &resourceSlice.Status.Nodes[i]
+		match, err := required.Match(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: providerNode.Name, Labels: providerNode.Labels}})
+		if err != nil {
+			klog.Warningf("invalid offloading patch of virtualnode %q: %v", klog.KObj(virtualNode), err)
+			return nil, nil
+		}
+		if match {
+			providerNodes = append(providerNodes, providerNode.Name)
+		}
+	}
+	return providerNodes, nil
+}
+
+// listPods returns the pods created on behalf of the DaemonSet with the given name, including those created on behalf
+// of a previous DaemonSet with the same name, while excluding any other pod carrying the same label.
+func (r *DaemonSetOffloadingReconciler) listPods(ctx context.Context, namespace, name string) ([]corev1.Pod, error) {
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(namespace), client.MatchingLabels{consts.DaemonSetOffloadingLabelKey: name}); err != nil {
+		klog.Errorf("unable to list the pods of daemonset %q: %v", klog.KRef(namespace, name), err)
+		return nil, err
+	}
+
+	owned := make([]corev1.Pod, 0, len(pods.Items))
+	for i := range pods.Items {
+		if offloadingutils.OffloadedDaemonSetReference(&pods.Items[i]) != nil {
+			owned = append(owned, pods.Items[i])
+		}
+	}
+	return owned, nil
+}
+
+// cleanup deletes all the pods created on behalf of the given DaemonSet.
+func (r *DaemonSetOffloadingReconciler) cleanup(ctx context.Context, namespace, name string) error {
+	pods, err := r.listPods(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	for i := range pods {
+		if !pods[i].DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := client.IgnoreNotFound(r.Delete(ctx, &pods[i])); err != nil {
+			klog.Errorf("unable to delete pod %q: %v", klog.KObj(&pods[i]), err)
+			return err
+		}
+		klog.Infof("pod %q deleted, as daemonset %q is no longer offloaded", klog.KObj(&pods[i]), klog.KRef(namespace, name))
+	}
+	return nil
+}
+
+// updateCondition sets the given condition in the status of the given DaemonSet, removing it if condition is nil.
+// The status is patched with optimistic locking, to avoid overwriting the conditions concurrently set by other writers.
+func (r *DaemonSetOffloadingReconciler) updateCondition(ctx context.Context, ds *appsv1.DaemonSet, condition *appsv1.DaemonSetCondition) error {
+	original := ds.DeepCopy()
+
+	conditions := make([]appsv1.DaemonSetCondition, 0, len(ds.Status.Conditions)+1)
+	var current *appsv1.DaemonSetCondition
+	for i := range ds.Status.Conditions {
+		if ds.Status.Conditions[i].Type == OffloadedPodsReadyCondition {
+			current = &ds.Status.Conditions[i]
+			continue
+		}
+		conditions = append(conditions, ds.Status.Conditions[i])
+	}
+
+	switch {
+	case condition == nil && current == nil:
+		return nil
+	case condition != nil && current != nil && current.Status == condition.Status &&
+		current.Reason == condition.Reason && current.Message == condition.Message:
+		return nil
+	case condition != nil:
+		condition.LastTransitionTime = metav1.Now()
+		if current != nil && current.Status == condition.Status {
+			condition.LastTransitionTime = current.LastTransitionTime
+		}
+		conditions = append(conditions, *condition)
+	}
+
+	ds.Status.Conditions = conditions
+	if err := r.Status().Patch(ctx, ds, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		klog.Errorf("unable to update the offloading condition of daemonset %q: %v", klog.KObj(ds), err)
+		return err
+	}
+	return nil
+}
+
+// podName returns the name of the pod created on behalf of the given DaemonSet for the given target.
+// In per-provider-node mode, the name of the provider node is hashed, to avoid exceeding the maximum name length.
+func podName(ds *appsv1.DaemonSet, tgt *target) string {
+	if tgt.providerNode == "" {
+		return fmt.Sprintf("%s-%s", ds.Name, tgt.node.Name)
+	}
+	hash := sha256.Sum256([]byte(tgt.providerNode))
+	return fmt.Sprintf("%s-%s-%s", ds.Name, tgt.node.Name, hex.EncodeToString(hash[:])[:10])
+}
+
+// forgePod forges the pod to be created on behalf of the given DaemonSet for the given target.
+// The pod is controlled by the virtual node, so that the DaemonSet controller does not attempt to adopt it, while it is
+// also owned by the DaemonSet, to distinguish it from any other pod carrying the same label.
+func forgePod(ds *appsv1.DaemonSet, tgt *target, name, hash string) (*corev1.Pod, error) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   ds.Namespace,
+			Labels:      make(map[string]string, len(ds.Spec.Template.Labels)+1),
+			Annotations: make(map[string]string, len(ds.Spec.Template.Annotations)+3),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(tgt.node, corev1.SchemeGroupVersion.WithKind("Node")),
+				{APIVersion: appsv1.SchemeGroupVersion.String(), Kind: "DaemonSet", Name: ds.Name, UID: ds.UID},
+			},
+		},
+		Spec: *ds.Spec.Template.Spec.DeepCopy(),
+	}
+
+	for key, value := range ds.Spec.Template.Labels {
+		pod.Labels[key] = value
+	}
+	pod.Labels[consts.DaemonSetOffloadingLabelKey] = ds.Name
+
+	for key, value := range ds.Spec.Template.Annotations {
+		pod.Annotations[key] = value
+	}
+	pod.Annotations[consts.DaemonSetOffloadingTemplateHashAnnotationKey] = hash
+
+	// Pin the pod to the provider node through the remote node affinity, in addition to the one possibly set in the template.
+	if tgt.providerNode != "" {
+		affinity, err := providerNodeAffinity(pod.Annotations[consts.RemoteAffinityAnnotKey], tgt.providerNode)
+		if err != nil {
+			return nil, err
+		}
+		pod.Annotations[consts.RemoteAffinityAnnotKey] = affinity
+		pod.Annotations[consts.DaemonSetOffloadingProviderNodeAnnotationKey] = tgt.providerNode
+	}
+
+	// Bind the pod to the virtual node, bypassing the scheduler.
+	pod.Spec.NodeName = tgt.node.Name
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, corev1.Toleration{
+		Key:      consts.VirtualNodeTolerationKey,
+		Operator: corev1.TolerationOpExists,
+		Effect:   corev1.TaintEffectNoExecute,
+	})
+
+	return pod, nil
+}
+
+// providerNodeAffinity returns the (JSON encoded) remote node affinity requiring the given provider node, ANDed with the given one.
+func providerNodeAffinity(value, providerNode string) (string, error) {
+	var affinity corev1.NodeAffinity
+	if value != "" {
+		if err := json.Unmarshal([]byte(value), &affinity); err != nil {
+			return "", fmt.Errorf("failed to parse %q annotation: %w", consts.RemoteAffinityAnnotKey, err)
+		}
+	}
+
+	if affinity.RequiredDuringSchedulingIgnoredDuringExecution == nil ||
+		len(affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) == 0 {
+		affinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{}}}
+	}
+
+	// Node selector terms are ORed, hence the requirement is added to each of them.
+	terms := affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	for i := range terms {
+		terms[i].MatchFields = append(terms[i].MatchFields, corev1.NodeSelectorRequirement{
+			Key: metav1.ObjectNameField, Operator: corev1.NodeSelectorOpIn, Values: []string{providerNode},
+		})
+	}
+
+	marshaled, err := json.Marshal(&affinity)
+	if err != nil {
+		return "", err
+	}
+	return string(marshaled), nil
+}
+
+// SetupWithManager monitors the offloaded DaemonSets, together with the corresponding pods, the virtual nodes and the NamespaceOffloadings.
+func (r *DaemonSetOffloadingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	offloaded := func(object client.Object) bool {
+		_, found := object.GetAnnotations()[consts.DaemonSetOffloadingAnnotationKey]
+		return found
+	}
+
+	// Reconcile also the DaemonSets whose annotation has been removed, to delete the corresponding pods.
+	daemonSets := predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return offloaded(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return offloaded(e.ObjectOld) || offloaded(e.ObjectNew) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return offloaded(e.Object) },
+		GenericFunc: func(e event.GenericEvent) bool { return offloaded(e.Object) },
+	}
+
+	offloadedPods := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		_, found := object.GetLabels()[consts.DaemonSetOffloadingLabelKey]
+		return found
+	})
+
+	virtualNodes := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetLabels()[consts.TypeLabel] == consts.TypeNode
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlDaemonSetOffloading).
+		For(&appsv1.DaemonSet{}, builder.WithPredicates(daemonSets)).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(
+			func(_ context.Context, object client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{
+					Namespace: object.GetNamespace(), Name: object.GetLabels()[consts.DaemonSetOffloadingLabelKey]}}}
+			}), builder.WithPredicates(offloadedPods)).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(
+			func(ctx context.Context, _ client.Object) []reconcile.Request { return r.offloadedDaemonSets(ctx) }),
+			builder.WithPredicates(virtualNodes, predicate.Or(predicate.LabelChangedPredicate{}, readinessChangedPredicate()))).
+		Watches(&offloadingv1beta1.NamespaceOffloading{}, handler.EnqueueRequestsFromMapFunc(
+			func(ctx context.Context, object client.Object) []reconcile.Request {
+				return r.offloadedDaemonSets(ctx, client.InNamespace(object.GetNamespace()))
+			})).
+		// The provider nodes exposed by the ResourceSlices determine the targets in per-provider-node mode.
+		Watches(&authv1beta1.ResourceSlice{}, handler.EnqueueRequestsFromMapFunc(
+			func(ctx context.Context, _ client.Object) []reconcile.Request { return r.offloadedDaemonSets(ctx) }),
+			builder.WithPredicates(providerNodesChangedPredicate())).
+		Complete(r)
+}
+
+// offloadedDaemonSets returns the reconcile requests for the offloaded DaemonSets matching the given options.
+func (r *DaemonSetOffloadingReconciler) offloadedDaemonSets(ctx context.Context, opts ...client.ListOption) []reconcile.Request {
+	var daemonSets appsv1.DaemonSetList
+	if err := r.List(ctx, &daemonSets, opts...); err != nil {
+		klog.Errorf("unable to list daemonsets: %v", err)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range daemonSets.Items {
+		if _, found := daemonSets.Items[i].Annotations[consts.DaemonSetOffloadingAnnotationKey]; found {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&daemonSets.Items[i])})
+		}
+	}
+	return requests
+}
+
+// readinessChangedPredicate triggers a reconciliation when the readiness of a node changes, which may affect the per-cluster selection.
+func readinessChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, okOld := e.ObjectOld.(*corev1.Node)
+			newNode, okNew := e.ObjectNew.(*corev1.Node)
+			return okOld && okNew && utils.IsNodeReady(oldNode) != utils.IsNodeReady(newNode)
+		},
+	}
+}
+
+// providerNodesChangedPredicate triggers a reconciliation when the provider nodes exposed by a ResourceSlice change.
+func providerNodesChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSlice, okOld := e.ObjectOld.(*authv1beta1.ResourceSlice)
+			newSlice, okNew := e.ObjectNew.(*authv1beta1.ResourceSlice)
+			return okOld && okNew && !reflect.DeepEqual(oldSlice.Status.Nodes, newSlice.Status.Nodes)
+		},
+	}
+}
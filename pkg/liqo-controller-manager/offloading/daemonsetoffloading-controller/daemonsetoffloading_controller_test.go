@@ -0,0 +1,360 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemonsetoffloadingctrl
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubectl/pkg/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	offloadingutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/utils"
+)
+
+var _ = Describe("DaemonSetOffloadingController", func() {
+	const (
+		ns     string = "default"
+		dsName string = "log-shipper"
+	)
+
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *DaemonSetOffloadingReconciler
+		ds         *appsv1.DaemonSet
+		objects    []client.Object
+
+		reqDs = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: dsName}}
+
+		newNode = func(name, clusterID string) *corev1.Node {
+			return &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{consts.TypeLabel: consts.TypeNode, consts.RemoteClusterID: clusterID},
+				},
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				},
+			}
+		}
+
+		newNamespaceOffloading = func(readyClusters ...string) *offloadingv1beta1.NamespaceOffloading {
+			conditions := map[string]offloadingv1beta1.RemoteNamespaceConditions{
+				"cluster-failed": {{Type: offloadingv1beta1.NamespaceReady, Status: corev1.ConditionFalse}},
+			}
+			for _, cluster := range readyClusters {
+				conditions[cluster] = offloadingv1beta1.RemoteNamespaceConditions{{
+					Type: offloadingv1beta1.NamespaceReady, Status: corev1.ConditionTrue}}
+			}
+			return &offloadingv1beta1.NamespaceOffloading{
+				ObjectMeta: metav1.ObjectMeta{Name: consts.DefaultNamespaceOffloadingName, Namespace: ns},
+				Status:     offloadingv1beta1.NamespaceOffloadingStatus{RemoteNamespacesConditions: conditions},
+			}
+		}
+
+		listPods = func() []corev1.Pod {
+			var pods corev1.PodList
+			Expect(fakeClient.List(ctx, &pods, client.InNamespace(ns))).To(Succeed())
+			return pods.Items
+		}
+
+		podNames = func() []string {
+			var names []string
+			for _, pod := range listPods() {
+				names = append(names, pod.Name)
+			}
+			return names
+		}
+
+		forge = func(nodeName, clusterID, hash string) *corev1.Pod {
+			pod, err := forgePod(ds, &target{node: newNode(nodeName, clusterID)}, dsName+"-"+nodeName, hash)
+			Expect(err).ToNot(HaveOccurred())
+			return pod
+		}
+
+		newVirtualNode func(name, clusterID, slice string, nodeSelector map[string]string) *offloadingv1beta1.VirtualNode
+
+		getCondition = func() *appsv1.DaemonSetCondition {
+			var current appsv1.DaemonSet
+			Expect(fakeClient.Get(ctx, reqDs.NamespacedName, &current)).To(Succeed())
+			for i := range current.Status.Conditions {
+				if current.Status.Conditions[i].Type == OffloadedPodsReadyCondition {
+					return &current.Status.Conditions[i]
+				}
+			}
+			return nil
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ds = &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        dsName,
+				Namespace:   ns,
+				UID:         "ds-uid",
+				Annotations: map[string]string{consts.DaemonSetOffloadingAnnotationKey: consts.DaemonSetOffloadingPerCluster},
+			},
+			Spec: appsv1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": dsName}},
+					Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "shipper", Image: "shipper:v1"}}},
+				},
+			},
+		}
+		objects = []client.Object{
+			newNode("liqo-cluster-a-1", "cluster-a"), newNode("liqo-cluster-a-2", "cluster-a"),
+			newNode("liqo-cluster-b", "cluster-b"), newNode("liqo-cluster-failed", "cluster-failed"),
+			newNamespaceOffloading("cluster-a", "cluster-b"),
+		}
+	})
+
+	JustBeforeEach(func() {
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(objects, ds)...).
+			WithStatusSubresource(&appsv1.DaemonSet{}).Build()
+		reconciler = &DaemonSetOffloadingReconciler{Client: fakeClient, Recorder: record.NewFakeRecorder(10)}
+	})
+
+	Describe("the Reconcile function", func() {
+		JustBeforeEach(func() {
+			_, err := reconciler.Reconcile(ctx, reqDs)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		When("the per-cluster mode is selected", func() {
+			It("should create one pod for each cluster where the namespace is ready", func() {
+				Expect(podNames()).To(ConsistOf("log-shipper-liqo-cluster-a-1", "log-shipper-liqo-cluster-b"))
+			})
+
+			It("should forge the pods from the DaemonSet template", func() {
+				for _, pod := range listPods() {
+					Expect(pod.Spec.NodeName).To(HaveSuffix(pod.Name[len(dsName)+1:]))
+					Expect(pod.Spec.Containers).To(Equal(ds.Spec.Template.Spec.Containers))
+					Expect(pod.Labels).To(HaveKeyWithValue("app", dsName))
+					Expect(pod.Labels).To(HaveKeyWithValue(consts.DaemonSetOffloadingLabelKey, dsName))
+					Expect(pod.Annotations).To(HaveKey(consts.DaemonSetOffloadingTemplateHashAnnotationKey))
+					Expect(pod.Spec.Tolerations).To(ContainElement(HaveField("Key", consts.VirtualNodeTolerationKey)))
+					Expect(metav1.GetControllerOf(&pod)).To(PointTo(HaveField("Kind", "Node")))
+					Expect(pod.OwnerReferences).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+						"Kind": Equal("DaemonSet"), "Name": Equal(dsName), "UID": Equal(ds.UID), "Controller": BeNil(),
+					})))
+				}
+			})
+
+			It("should report that the offloaded pods are not ready", func() {
+				Expect(getCondition()).To(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Status":  Equal(corev1.ConditionFalse),
+					"Reason":  Equal("OffloadedPodsNotReady"),
+					"Message": Equal("0/2 offloaded pods are ready"),
+				})))
+			})
+		})
+
+		When("the per-cluster mode is selected and a pod is already running on a different node of the same cluster", func() {
+			BeforeEach(func() {
+				hash, err := offloadingutils.DaemonSetTemplateHash(&ds.Spec.Template)
+				Expect(err).ToNot(HaveOccurred())
+				pod := forge("liqo-cluster-a-2", "cluster-a", hash)
+				pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+				objects = append(objects, pod)
+			})
+
+			It("should keep the existing pod", func() {
+				Expect(podNames()).To(ConsistOf("log-shipper-liqo-cluster-a-2", "log-shipper-liqo-cluster-b"))
+			})
+
+			It("should report the ready offloaded pods", func() {
+				Expect(getCondition()).To(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Status":  Equal(corev1.ConditionFalse),
+					"Message": Equal("1/2 offloaded pods are ready"),
+				})))
+			})
+		})
+
+		When("the per-virtual-node mode is selected", func() {
+			BeforeEach(func() {
+				ds.Annotations[consts.DaemonSetOffloadingAnnotationKey] = consts.DaemonSetOffloadingPerVirtualNode
+			})
+
+			It("should create one pod for each virtual node where the namespace is ready", func() {
+				Expect(podNames()).To(ConsistOf("log-shipper-liqo-cluster-a-1", "log-shipper-liqo-cluster-a-2", "log-shipper-liqo-cluster-b"))
+			})
+		})
+
+		When("the per-provider-node mode is selected", func() {
+			newVirtualNode = func(name, clusterID, slice string, nodeSelector map[string]string) *offloadingv1beta1.VirtualNode {
+				return &offloadingv1beta1.VirtualNode{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "liqo-tenant-" + clusterID, Labels: map[string]string{
+						consts.RemoteClusterID: clusterID, consts.ResourceSliceNameLabelKey: slice}},
+					Spec: offloadingv1beta1.VirtualNodeSpec{OffloadingPatch: &offloadingv1beta1.OffloadingPatch{NodeSelector: nodeSelector}},
+				}
+			}
+
+			BeforeEach(func() {
+				ds.Annotations[consts.DaemonSetOffloadingAnnotationKey] = consts.DaemonSetOffloadingPerProviderNode
+				objects = append(objects,
+					newVirtualNode("liqo-cluster-a-1", "cluster-a", "slice", map[string]string{"zone": "z1"}),
+					newVirtualNode("liqo-cluster-a-2", "cluster-a", "slice", nil),
+					&authv1beta1.ResourceSlice{
+						ObjectMeta: metav1.ObjectMeta{Name: "slice", Namespace: "liqo-tenant-cluster-a"},
+						Status: authv1beta1.ResourceSliceStatus{Nodes: []authv1beta1.ResourceSliceNode{
+							{Name: "provider-1", Labels: map[string]string{"zone": "z1"}},
+							{Name: "provider-2", Labels: map[string]string{"zone": "z1"}},
+							{Name: "provider-3", Labels: map[string]string{"zone": "z2"}},
+						}},
+					})
+			})
+
+			It("should create one pod for each provider node selected by the virtual nodes", func() {
+				providerNodes := map[string]string{}
+				for _, pod := range listPods() {
+					providerNodes[pod.Annotations[consts.DaemonSetOffloadingProviderNodeAnnotationKey]] = pod.Spec.NodeName
+				}
+				Expect(providerNodes).To(Equal(map[string]string{
+					"provider-1": "liqo-cluster-a-1", "provider-2": "liqo-cluster-a-1", "provider-3": "liqo-cluster-a-2",
+				}))
+			})
+
+			It("should pin each pod to the corresponding provider node", func() {
+				for _, pod := range listPods() {
+					var affinity corev1.NodeAffinity
+					Expect(json.Unmarshal([]byte(pod.Annotations[consts.RemoteAffinityAnnotKey]), &affinity)).To(Succeed())
+					Expect(affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(ConsistOf(
+						HaveField("MatchFields", ConsistOf(corev1.NodeSelectorRequirement{Key: "metadata.name",
+							Operator: corev1.NodeSelectorOpIn, Values: []string{pod.Annotations[consts.DaemonSetOffloadingProviderNodeAnnotationKey]}}))))
+				}
+			})
+		})
+
+		When("a pod carries the label of the DaemonSet, but it is not owned by it", func() {
+			BeforeEach(func() {
+				objects = append(objects, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "user-pod", Namespace: ns,
+					Labels: map[string]string{consts.DaemonSetOffloadingLabelKey: dsName}}})
+			})
+
+			It("should not delete the pod", func() {
+				Expect(podNames()).To(ConsistOf("user-pod", "log-shipper-liqo-cluster-a-1", "log-shipper-liqo-cluster-b"))
+			})
+		})
+
+		When("a pod has been created on behalf of a previous DaemonSet with the same name", func() {
+			BeforeEach(func() {
+				hash, err := offloadingutils.DaemonSetTemplateHash(&ds.Spec.Template)
+				Expect(err).ToNot(HaveOccurred())
+				pod := forge("liqo-cluster-b", "cluster-b", hash)
+				pod.OwnerReferences[1].UID = "previous-uid"
+				objects = append(objects, pod)
+			})
+
+			It("should delete the pod", func() {
+				Expect(podNames()).To(ConsistOf("log-shipper-liqo-cluster-a-1"))
+			})
+		})
+
+		When("an unknown mode is selected", func() {
+			BeforeEach(func() {
+				ds.Annotations[consts.DaemonSetOffloadingAnnotationKey] = "per-galaxy"
+			})
+
+			It("should not create any pod", func() {
+				Expect(listPods()).To(BeEmpty())
+			})
+		})
+
+		When("the namespace is not offloaded", func() {
+			BeforeEach(func() {
+				objects = objects[:4]
+			})
+
+			It("should not create any pod", func() {
+				Expect(listPods()).To(BeEmpty())
+			})
+
+			It("should report that all the (zero) offloaded pods are ready", func() {
+				Expect(getCondition()).To(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Status":  Equal(corev1.ConditionTrue),
+					"Reason":  Equal("OffloadedPodsReady"),
+					"Message": Equal("0/0 offloaded pods are ready"),
+				})))
+			})
+		})
+
+		When("a pod has been generated from an outdated template", func() {
+			BeforeEach(func() {
+				objects = append(objects, forge("liqo-cluster-b", "cluster-b", "outdated"))
+			})
+
+			It("should delete the outdated pod", func() {
+				Expect(podNames()).To(ConsistOf("log-shipper-liqo-cluster-a-1"))
+			})
+		})
+
+		When("a pod is no longer desired", func() {
+			BeforeEach(func() {
+				objects = append(objects, forge("liqo-cluster-failed", "cluster-failed", ""))
+			})
+
+			It("should delete the pod", func() {
+				Expect(podNames()).To(ConsistOf("log-shipper-liqo-cluster-a-1", "log-shipper-liqo-cluster-b"))
+			})
+		})
+
+		When("the DaemonSet is no longer offloaded", func() {
+			BeforeEach(func() {
+				objects = append(objects, forge("liqo-cluster-b", "cluster-b", ""))
+				ds.Annotations = nil
+				ds.Status.Conditions = []appsv1.DaemonSetCondition{
+					{Type: OffloadedPodsReadyCondition, Status: corev1.ConditionTrue},
+					{Type: "Other", Status: corev1.ConditionTrue},
+				}
+			})
+
+			It("should delete the offloaded pods", func() {
+				Expect(listPods()).To(BeEmpty())
+			})
+
+			It("should remove the offloading condition", func() {
+				Expect(getCondition()).To(BeNil())
+			})
+		})
+	})
+
+	When("the DaemonSet has been deleted", func() {
+		BeforeEach(func() {
+			objects = append(objects, forge("liqo-cluster-b", "cluster-b", ""))
+		})
+
+		It("should delete the offloaded pods", func() {
+			Expect(fakeClient.Delete(ctx, ds)).To(Succeed())
+			_, err := reconciler.Reconcile(ctx, reqDs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(listPods()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,20 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package daemonsetoffloadingctrl contains a controller that handles the offloading of the DaemonSets
+// opted in through the liqo.io/daemonset-offloading annotation. Rather than letting the DaemonSet
+// controller target the virtual nodes, it creates one pod bound to a virtual node either for each
+// provider cluster or for each virtual node. These pods are then reflected by the virtual kubelet
+// as ShadowPods, and their readiness is aggregated back into an annotation of the DaemonSet.
+package daemonsetoffloadingctrl
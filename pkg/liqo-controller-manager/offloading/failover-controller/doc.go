@@ -0,0 +1,20 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package failoverctrl contains a controller that handles the failover of the workloads
+// offloaded to a provider cluster which becomes unavailable. Once the grace period expires,
+// the corresponding virtual node is tainted and cordoned, and the pods hosted on it are evicted
+// (honoring PodDisruptionBudgets), so that they can be rescheduled elsewhere.
+// The virtual node is restored as soon as the provider cluster becomes available again.
+package failoverctrl
@@ -0,0 +1,211 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package failoverctrl
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils"
+	"github.com/liqotech/liqo/pkg/utils/indexer"
+)
+
+// evictionRetryPeriod is the period after which the eviction of the pods still hosted by a failed over node is retried.
+const evictionRetryPeriod = 10 * time.Second
+
+// FailoverReconciler reconciles the virtual nodes, triggering the failover of the hosted workloads
+// when the corresponding provider cluster becomes unavailable.
+type FailoverReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+
+	// GracePeriod is the amount of time a virtual node shall be NotReady before triggering the failover.
+	GracePeriod time.Duration
+}
+
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=core,resources=pods/eviction,verbs=create
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile virtual nodes objects.
+func (r *FailoverReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var node corev1.Node
+	if err := r.Get(ctx, client.ObjectKey{Name: req.Name}, &node); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("node %s not found", req.Name)
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("an error occurred while getting node %s: %v", req.Name, err)
+		return ctrl.Result{}, err
+	}
+
+	if utils.IsNodeReady(&node) {
+		// The provider cluster is available, hence restore the node in case it had been previously failed over.
+		if utils.IsNodeFailedOver(&node) {
+			return ctrl.Result{}, r.recover(ctx, &node)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !utils.IsNodeFailedOver(&node) {
+		// Wait for the grace period to expire before triggering the failover, to tolerate transient failures.
+		if elapsed := time.Since(notReadySince(&node)); elapsed < r.GracePeriod {
+			klog.V(4).Infof("node %s is NotReady since %v, waiting for the failover grace period to expire", node.Name, elapsed.Round(time.Second))
+			return ctrl.Result{RequeueAfter: r.GracePeriod - elapsed}, nil
+		}
+
+		if err := r.failover(ctx, &node); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return r.evictPods(ctx, &node)
+}
+
+// failover taints and cordons the given node, to prevent new pods from being scheduled on it.
+func (r *FailoverReconciler) failover(ctx context.Context, node *corev1.Node) error {
+	original := node.DeepCopy()
+	node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{
+		Key:       consts.ProviderUnavailableTaintKey,
+		Effect:    corev1.TaintEffectNoSchedule,
+		TimeAdded: ptr.To(metav1.Now()),
+	})
+
+	// Keep track of whether the node has been cordoned by us, to avoid uncordoning it on recovery if cordoned by the user.
+	if !node.Spec.Unschedulable {
+		node.Spec.Unschedulable = true
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[consts.FailoverCordonedAnnotationKey] = "true"
+	}
+
+	if err := r.Patch(ctx, node, client.MergeFrom(original)); err != nil {
+		klog.Errorf("unable to taint and cordon node %s: %v", node.Name, err)
+		return err
+	}
+
+	klog.Warningf("provider of node %s unavailable for longer than %v: failover started", node.Name, r.GracePeriod)
+	r.Recorder.Eventf(node, corev1.EventTypeWarning, "FailoverStarted",
+		"The provider cluster has been unavailable for longer than %v, evicting the hosted pods", r.GracePeriod)
+	return nil
+}
+
+// recover removes the failover taint from the given node, and uncordons it if previously cordoned by the failover process.
+func (r *FailoverReconciler) recover(ctx context.Context, node *corev1.Node) error {
+	original := node.DeepCopy()
+
+	taints := make([]corev1.Taint, 0, len(node.Spec.Taints))
+	for i := range node.Spec.Taints {
+		if node.Spec.Taints[i].Key != consts.ProviderUnavailableTaintKey {
+			taints = append(taints, node.Spec.Taints[i])
+		}
+	}
+	node.Spec.Taints = taints
+
+	if _, found := node.Annotations[consts.FailoverCordonedAnnotationKey]; found {
+		node.Spec.Unschedulable = false
+		delete(node.Annotations, consts.FailoverCordonedAnnotationKey)
+	}
+
+	if err := r.Patch(ctx, node, client.MergeFrom(original)); err != nil {
+		klog.Errorf("unable to restore node %s: %v", node.Name, err)
+		return err
+	}
+
+	klog.Infof("provider of node %s available again: failover completed", node.Name)
+	r.Recorder.Event(node, corev1.EventTypeNormal, "FailoverCompleted", "The provider cluster is available again")
+	return nil
+}
+
+// evictPods evicts the offloaded pods hosted by the given node through the Eviction API, hence honoring PodDisruptionBudgets.
+// Pods already terminating are forcefully deleted, since the unavailable provider cannot confirm their termination.
+func (r *FailoverReconciler) evictPods(ctx context.Context, node *corev1.Node) (ctrl.Result, error) {
+	var pods corev1.PodList
+	offloadedPodSelector := client.MatchingLabelsSelector{Selector: labels.Set{consts.LocalPodLabelKey: consts.LocalPodLabelValue}.AsSelector()}
+	nodePodSelector := client.MatchingFieldsSelector{Selector: fields.OneTermEqualSelector(indexer.FieldNodeNameFromPod, node.Name)}
+	if err := r.List(ctx, &pods, offloadedPodSelector, nodePodSelector); err != nil {
+		klog.Errorf("unable to list pods: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+
+		if !pod.DeletionTimestamp.IsZero() {
+			if err := client.IgnoreNotFound(r.Delete(ctx, pod, client.GracePeriodSeconds(0))); err != nil {
+				klog.Errorf("unable to delete pod %q: %v", klog.KObj(pod), err)
+				return ctrl.Result{}, err
+			}
+			klog.Infof("pod %q running on failed over node %s deleted", klog.KObj(pod), node.Name)
+			continue
+		}
+
+		eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}}
+		switch err := r.SubResource("eviction").Create(ctx, pod, eviction); {
+		case apierrors.IsTooManyRequests(err):
+			// The eviction is currently forbidden by a PodDisruptionBudget, it will be retried later.
+			klog.V(4).Infof("eviction of pod %q running on failed over node %s blocked by disruption budget", klog.KObj(pod), node.Name)
+		case client.IgnoreNotFound(err) != nil:
+			klog.Errorf("unable to evict pod %q: %v", klog.KObj(pod), err)
+			return ctrl.Result{}, err
+		default:
+			klog.Infof("pod %q running on failed over node %s evicted", klog.KObj(pod), node.Name)
+		}
+	}
+
+	if len(pods.Items) > 0 {
+		// Check again later, to forcefully delete the evicted pods and retry the blocked evictions.
+		return ctrl.Result{RequeueAfter: evictionRetryPeriod}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// notReadySince returns the time since when the given node is not ready.
+func notReadySince(node *corev1.Node) time.Time {
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == corev1.NodeReady {
+			return node.Status.Conditions[i].LastTransitionTime.Time
+		}
+	}
+	return node.CreationTimestamp.Time
+}
+
+// SetupWithManager monitors the virtual nodes.
+func (r *FailoverReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	virtualNodes := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetLabels()[consts.TypeLabel] == consts.TypeNode
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlFailover).
+		For(&corev1.Node{}, builder.WithPredicates(virtualNodes)).
+		Complete(r)
+}
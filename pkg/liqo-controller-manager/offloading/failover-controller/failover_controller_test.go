@@ -0,0 +1,184 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package failoverctrl
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubectl/pkg/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils"
+	"github.com/liqotech/liqo/pkg/utils/indexer"
+)
+
+var _ = Describe("FailoverController", func() {
+	const (
+		ns          string = "default"
+		nodeName    string = "liqo-remote"
+		podName     string = "test-pod"
+		gracePeriod        = time.Minute
+	)
+
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		result     ctrl.Result
+		node       *corev1.Node
+
+		reqNode = ctrl.Request{NamespacedName: types.NamespacedName{Name: nodeName}}
+
+		newNode = func(ready bool, since time.Duration) *corev1.Node {
+			status := corev1.ConditionFalse
+			if ready {
+				status = corev1.ConditionTrue
+			}
+
+			return &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   nodeName,
+					Labels: map[string]string{consts.TypeLabel: consts.TypeNode},
+				},
+				Status: corev1.NodeStatus{
+					Conditions: []corev1.NodeCondition{{
+						Type:               corev1.NodeReady,
+						Status:             status,
+						LastTransitionTime: metav1.NewTime(time.Now().Add(-since)),
+					}},
+				},
+			}
+		}
+
+		newPod = func() *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      podName,
+					Namespace: ns,
+					Labels:    map[string]string{consts.LocalPodLabelKey: consts.LocalPodLabelValue},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}},
+					NodeName:   nodeName,
+				},
+			}
+		}
+
+		failedOver = func(node *corev1.Node, cordonedByUs bool) *corev1.Node {
+			node.Spec.Unschedulable = true
+			node.Spec.Taints = []corev1.Taint{{Key: consts.ProviderUnavailableTaintKey, Effect: corev1.TaintEffectNoSchedule}}
+			if cordonedByUs {
+				node.Annotations = map[string]string{consts.FailoverCordonedAnnotationKey: "true"}
+			}
+			return node
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+	})
+
+	JustBeforeEach(func() {
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithIndex(&corev1.Pod{}, indexer.FieldNodeNameFromPod, indexer.ExtractNodeName).
+			WithObjects(node, newPod()).
+			Build()
+
+		r := &FailoverReconciler{
+			Client:      fakeClient,
+			Recorder:    record.NewFakeRecorder(10),
+			GracePeriod: gracePeriod,
+		}
+
+		var err error
+		result, err = r.Reconcile(ctx, reqNode)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	getNode := func() *corev1.Node {
+		var updated corev1.Node
+		Expect(fakeClient.Get(ctx, reqNode.NamespacedName, &updated)).To(Succeed())
+		return &updated
+	}
+
+	When("the node is ready", func() {
+		BeforeEach(func() { node = newNode(true, time.Hour) })
+
+		It("should not alter the node and the pods", func() {
+			Expect(utils.IsNodeFailedOver(getNode())).To(BeFalse())
+			Expect(getNode().Spec.Unschedulable).To(BeFalse())
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: ns, Name: podName}, &corev1.Pod{})).To(Succeed())
+		})
+	})
+
+	When("the node is not ready since less than the grace period", func() {
+		BeforeEach(func() { node = newNode(false, 10*time.Second) })
+
+		It("should wait for the grace period to expire", func() {
+			Expect(result.RequeueAfter).To(BeNumerically("~", gracePeriod-10*time.Second, time.Second))
+			Expect(utils.IsNodeFailedOver(getNode())).To(BeFalse())
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: ns, Name: podName}, &corev1.Pod{})).To(Succeed())
+		})
+	})
+
+	When("the node is not ready since more than the grace period", func() {
+		BeforeEach(func() { node = newNode(false, 2*gracePeriod) })
+
+		It("should taint and cordon the node", func() {
+			updated := getNode()
+			Expect(utils.IsNodeFailedOver(updated)).To(BeTrue())
+			Expect(updated.Spec.Unschedulable).To(BeTrue())
+			Expect(updated.Annotations).To(HaveKeyWithValue(consts.FailoverCordonedAnnotationKey, "true"))
+		})
+
+		It("should evict the hosted pods", func() {
+			err := fakeClient.Get(ctx, types.NamespacedName{Namespace: ns, Name: podName}, &corev1.Pod{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			Expect(result.RequeueAfter).To(Equal(evictionRetryPeriod))
+		})
+	})
+
+	When("the node is ready again after having been cordoned by the failover", func() {
+		BeforeEach(func() { node = failedOver(newNode(true, time.Second), true) })
+
+		It("should remove the taint and uncordon the node", func() {
+			updated := getNode()
+			Expect(utils.IsNodeFailedOver(updated)).To(BeFalse())
+			Expect(updated.Spec.Unschedulable).To(BeFalse())
+			Expect(updated.Annotations).ToNot(HaveKey(consts.FailoverCordonedAnnotationKey))
+		})
+	})
+
+	When("the node is ready again after having been cordoned by the user", func() {
+		BeforeEach(func() { node = failedOver(newNode(true, time.Second), false) })
+
+		It("should remove the taint, but keep the node cordoned", func() {
+			updated := getNode()
+			Expect(utils.IsNodeFailedOver(updated)).To(BeFalse())
+			Expect(updated.Spec.Unschedulable).To(BeTrue())
+		})
+	})
+})
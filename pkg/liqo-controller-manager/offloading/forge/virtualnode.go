@@ -27,6 +27,7 @@ import (
 	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
 	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/resources"
 )
 
 // VirtualNodeOptions contains the options to forge a VirtualNode resource.
@@ -128,7 +129,7 @@ func VirtualNodeOptionsFromResourceSlice(resourceSlice *authv1beta1.ResourceSlic
 		KubeconfigSecretRef:  corev1.LocalObjectReference{Name: kubeconfigSecretName},
 		VkOptionsTemplateRef: vkOptionsTemplateRef,
 
-		ResourceList:        resourceSlice.Status.Resources,
+		ResourceList:        resources.NodeResources(resourceSlice.Status.Resources),
 		StorageClasses:      resourceSlice.Status.StorageClasses,
 		IngressClasses:      resourceSlice.Status.IngressClasses,
 		LoadBalancerClasses: resourceSlice.Status.LoadBalancerClasses,
@@ -19,7 +19,9 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -29,10 +31,14 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
 	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
+	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
 	"github.com/liqotech/liqo/pkg/utils"
 	liqoerrors "github.com/liqotech/liqo/pkg/utils/errors"
+	"github.com/liqotech/liqo/pkg/utils/getters"
 	"github.com/liqotech/liqo/pkg/utils/resource"
 )
 
@@ -65,6 +71,7 @@ func (r *NamespaceMapReconciler) createNamespace(ctx context.Context, name, orig
 				},
 			},
 		}
+		r.Policies.MutateOffloadingNamespace(&namespace)
 
 		if err := liqoerrors.IgnoreAlreadyExists(r.Create(ctx, &namespace)); err != nil {
 			return false, fmt.Errorf("failed to create namespace %q: %w", name, err)
@@ -79,6 +86,17 @@ func (r *NamespaceMapReconciler) createNamespace(ctx context.Context, name, orig
 		return false, err
 	}
 
+	// The virtual kubelets authenticating through token identities are granted the same permissions of the certificate ones.
+	subject := rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: origin}
+	var serviceAccounts corev1.ServiceAccountList
+	if err := r.List(ctx, &serviceAccounts, client.InNamespace(nm.GetNamespace()), client.MatchingLabels{
+		consts.RemoteClusterID:            origin,
+		authentication.TokenIdentityLabel: "true",
+	}); err != nil {
+		return true, fmt.Errorf("failed to list the token ServiceAccounts of cluster %q: %w", origin, err)
+	}
+	tokenSubjects := authentication.TokenIdentitySubjects(serviceAccounts.Items, []rbacv1.Subject{subject})
+
 	// Make sure the appropriate role binding is present in the namespace for virtual kubelet operations.
 	// The rolebinding is named after the tenant namespace name, since that is guaranteed to be unique.
 	// This will simplify the support for remote namespaces associated with multiple origins.
@@ -93,9 +111,10 @@ func (r *NamespaceMapReconciler) createNamespace(ctx context.Context, name, orig
 		})
 
 		if binding.CreationTimestamp.IsZero() {
-			binding.Subjects = []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: rbacv1.GroupKind, Name: origin}}
+			binding.Subjects = []rbacv1.Subject{subject}
 			binding.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: consts.RemoteNamespaceClusterRoleName}
 		}
+		binding.Subjects = authentication.MergeSubjects(binding.Subjects, tokenSubjects...)
 
 		return nil
 	})
@@ -104,9 +123,98 @@ func (r *NamespaceMapReconciler) createNamespace(ctx context.Context, name, orig
 	}
 
 	klog.V(utils.FromResult(result)).Infof("RoleBinding %q successfully enforced (with %v operation)", klog.KObj(&binding), result)
+
+	if err := r.enforcePolicies(ctx, &namespace, nmID, liqov1beta1.ClusterID(origin)); err != nil {
+		return true, err
+	}
 	return true, nil
 }
 
+// enforcePolicies enforces the configured runtime policies (i.e., the Pod Security Admission level, the runtime class,
+// the LimitRange and the NetworkPolicy) in a remote namespace.
+func (r *NamespaceMapReconciler) enforcePolicies(ctx context.Context, namespace *corev1.Namespace,
+	nmID string, origin liqov1beta1.ClusterID) error {
+	if r.Policies == nil {
+		return nil
+	}
+
+	original := namespace.DeepCopy()
+	r.Policies.MutateOffloadingNamespace(namespace)
+	if !equality.Semantic.DeepEqual(original.Labels, namespace.Labels) ||
+		!equality.Semantic.DeepEqual(original.Annotations, namespace.Annotations) {
+		if err := r.Update(ctx, namespace); err != nil {
+			return fmt.Errorf("failed to enforce the policies of namespace %q: %w", namespace.Name, err)
+		}
+		klog.Infof("Policies of namespace %q successfully enforced", namespace.Name)
+	}
+
+	mutateMeta := func(obj client.Object) {
+		obj.SetAnnotations(labels.Merge(obj.GetAnnotations(), map[string]string{
+			consts.RemoteNamespaceManagedByAnnotationKey: nmID,
+		}))
+		obj.SetLabels(labels.Merge(obj.GetLabels(), map[string]string{
+			consts.K8sAppManagedByKey: consts.LiqoAppLabelValue,
+			consts.RemoteClusterID:    string(origin),
+		}))
+	}
+
+	if r.Policies.HasLimitRange() {
+		limitRange := corev1.LimitRange{ObjectMeta: metav1.ObjectMeta{Namespace: namespace.Name, Name: tenantnamespace.LimitRangeName}}
+		result, err := resource.CreateOrUpdate(ctx, r.Client, &limitRange, func() error {
+			mutateMeta(&limitRange)
+			r.Policies.MutateLimitRange(&limitRange)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to enforce limit range %q: %w", klog.KObj(&limitRange), err)
+		}
+		klog.V(utils.FromResult(result)).Infof("LimitRange %q successfully enforced (with %v operation)", klog.KObj(&limitRange), result)
+	}
+
+	if r.Policies.HasNetworkPolicy() {
+		cidrs, err := r.remoteCIDRs(ctx, origin)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve the CIDRs of cluster %q: %w", origin, err)
+		}
+
+		policy := networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: namespace.Name, Name: tenantnamespace.NetworkPolicyName}}
+		result, err := resource.CreateOrUpdate(ctx, r.Client, &policy, func() error {
+			mutateMeta(&policy)
+			r.Policies.MutateNetworkPolicy(&policy, origin, cidrs)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to enforce network policy %q: %w", klog.KObj(&policy), err)
+		}
+		klog.V(utils.FromResult(result)).Infof("NetworkPolicy %q successfully enforced (with %v operation)", klog.KObj(&policy), result)
+	}
+
+	return nil
+}
+
+// remoteCIDRs returns the CIDRs of the given remote cluster (as remapped by the local one), which the traffic
+// received through the gateway is originated from. No CIDR is returned if the network is not configured.
+func (r *NamespaceMapReconciler) remoteCIDRs(ctx context.Context, cluster liqov1beta1.ClusterID) ([]string, error) {
+	configuration, err := getters.GetConfigurationByClusterID(ctx, r.Client, cluster, corev1.NamespaceAll)
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	remote := &configuration.Spec.Remote
+	if configuration.Status.Remote != nil {
+		remote = configuration.Status.Remote
+	}
+
+	cidrs := make([]string, 0, len(remote.CIDR.Pod)+len(remote.CIDR.External))
+	for _, cidr := range append(remote.CIDR.Pod, remote.CIDR.External...) {
+		cidrs = append(cidrs, cidr.String())
+	}
+	return cidrs, nil
+}
+
 // For every entry of DesiredMapping create remote Namespace if it has not already being created.
 // ensureNamespacesExistence tries to create all the remote namespaces requested in DesiredMapping (NamespaceMap->Spec->DesiredMapping).
 func (r *NamespaceMapReconciler) ensureNamespacesExistence(ctx context.Context, nm *offloadingv1beta1.NamespaceMap) error {
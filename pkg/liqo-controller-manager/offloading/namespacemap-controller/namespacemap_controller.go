@@ -18,6 +18,7 @@ import (
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -31,19 +32,28 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
 	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
 	"github.com/liqotech/liqo/internal/crdReplicator/reflection"
 	"github.com/liqotech/liqo/pkg/consts"
+	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
 )
 
 // NamespaceMapReconciler creates remote namespaces and updates NamespaceMaps Status.
 type NamespaceMapReconciler struct {
 	client.Client
+
+	// Policies are the runtime policies enforced in the remote namespaces (optional).
+	Policies *tenantnamespace.NamespacePolicies
 }
 
 // cluster-role
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=limitranges,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=configurations,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core.liqo.io,resources=foreignclusters,verbs=get;list;watch
 // +kubebuilder:rbac:groups=offloading.liqo.io,resources=namespacemaps,verbs=get;watch;list;update;patch;create;delete
 // +kubebuilder:rbac:groups=offloading.liqo.io,resources=namespacemaps/finalizers,verbs=get;update;patch
@@ -109,12 +119,42 @@ func (r *NamespaceMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlNamespaceMap).
+	b := ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlNamespaceMap).
 		For(&offloadingv1beta1.NamespaceMap{}, builder.WithPredicates(filter)).
 		// It is not possible to use Owns, since a namespaced object cannot own a non-namespaced one,
 		// and cross namespace owners are disallowed by design.
 		// https://kubernetes.io/docs/concepts/overview/working-with-objects/owners-dependents/.
 		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(enqueuer)).
-		Watches(&rbacv1.RoleBinding{}, handler.EnqueueRequestsFromMapFunc(enqueuer)).
-		Complete(r)
+		Watches(&rbacv1.RoleBinding{}, handler.EnqueueRequestsFromMapFunc(enqueuer))
+
+	if r.Policies.HasLimitRange() {
+		b = b.Watches(&corev1.LimitRange{}, handler.EnqueueRequestsFromMapFunc(enqueuer))
+	}
+	if r.Policies.HasNetworkPolicy() {
+		// The NetworkPolicies depend on the CIDRs of the remote clusters, hence they are updated when the network configuration changes.
+		b = b.Watches(&networkingv1.NetworkPolicy{}, handler.EnqueueRequestsFromMapFunc(enqueuer)).
+			Watches(&networkingv1beta1.Configuration{}, handler.EnqueueRequestsFromMapFunc(r.configurationEnqueuer))
+	}
+
+	return b.Complete(r)
+}
+
+// configurationEnqueuer enqueues the NamespaceMaps of the remote cluster of the given network Configuration.
+func (r *NamespaceMapReconciler) configurationEnqueuer(ctx context.Context, obj client.Object) []reconcile.Request {
+	cluster, found := obj.GetLabels()[consts.RemoteClusterID]
+	if !found {
+		return nil
+	}
+
+	var namespaceMaps offloadingv1beta1.NamespaceMapList
+	if err := r.List(ctx, &namespaceMaps, client.MatchingLabels{consts.ReplicationOriginLabel: cluster}); err != nil {
+		klog.Errorf("Failed to retrieve the NamespaceMaps of cluster %q: %v", cluster, err)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, len(namespaceMaps.Items))
+	for i := range namespaceMaps.Items {
+		requests[i] = reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&namespaceMaps.Items[i])}
+	}
+	return requests
 }
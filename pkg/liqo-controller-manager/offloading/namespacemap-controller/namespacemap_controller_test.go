@@ -21,7 +21,9 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	controllerruntime "sigs.k8s.io/controller-runtime"
@@ -29,9 +31,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	ctrlutils "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
+	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
 	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
 	namespacemapctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/namespacemap-controller"
+	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
 	. "github.com/liqotech/liqo/pkg/utils/testutil"
 )
 
@@ -41,8 +45,9 @@ var _ = Describe("Enforcement logic", func() {
 		clientBuilder fake.ClientBuilder
 		reconciler    namespacemapctrl.NamespaceMapReconciler
 
-		nm  offloadingv1beta1.NamespaceMap
-		err error
+		nm       offloadingv1beta1.NamespaceMap
+		policies *tenantnamespace.NamespacePolicies
+		err      error
 	)
 
 	BeforeEach(func() {
@@ -52,12 +57,13 @@ var _ = Describe("Enforcement logic", func() {
 			Name: "name", Namespace: "tenant-namespace",
 			Labels: map[string]string{liqoconst.ReplicationOriginLabel: "origin"}},
 		}
+		policies = nil
 	})
 
 	JustBeforeEach(func() {
 		reconciler = namespacemapctrl.NamespaceMapReconciler{Client: clientBuilder.WithObjects(&nm).
 			WithStatusSubresource(&offloadingv1beta1.NamespaceMap{}).
-			Build()}
+			Build(), Policies: policies}
 		_, err = reconciler.Reconcile(ctx, controllerruntime.Request{NamespacedName: client.ObjectKeyFromObject(&nm)})
 	})
 
@@ -120,6 +126,54 @@ var _ = Describe("Enforcement logic", func() {
 				Describe("perform checks", func() { SuccessWhenBody() })
 			})
 
+			When("the namespace policies are configured", func() {
+				BeforeEach(func() {
+					policies = &tenantnamespace.NamespacePolicies{
+						PodSecurityLevel:         "baseline",
+						DefaultLimits:            corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+						DefaultDenyNetworkPolicy: true,
+						RuntimeClassName:         "gvisor",
+					}
+					clientBuilder.WithObjects(&networkingv1beta1.Configuration{
+						ObjectMeta: metav1.ObjectMeta{Name: "origin", Namespace: "tenant-namespace",
+							Labels: map[string]string{liqoconst.RemoteClusterID: "origin"}},
+						Status: networkingv1beta1.ConfigurationStatus{Remote: &networkingv1beta1.ClusterConfig{
+							CIDR: networkingv1beta1.ClusterConfigCIDR{
+								Pod:      []networkingv1beta1.CIDR{"10.100.0.0/16"},
+								External: []networkingv1beta1.CIDR{"10.200.0.0/16"},
+							},
+						}},
+					})
+				})
+
+				Describe("perform checks", func() { SuccessWhenBody() })
+
+				It("should configure the pod security level and the runtime class of the namespace", func() {
+					var namespace corev1.Namespace
+					Expect(reconciler.Get(ctx, types.NamespacedName{Name: "namespace-remote"}, &namespace)).To(Succeed())
+					Expect(namespace.GetLabels()).To(HaveKeyWithValue("pod-security.kubernetes.io/enforce", "baseline"))
+					Expect(namespace.GetAnnotations()).To(HaveKeyWithValue(liqoconst.RemoteNamespaceRuntimeClassAnnotationKey, "gvisor"))
+				})
+				It("should correctly ensure the limitrange is present", func() {
+					var limitRange corev1.LimitRange
+					Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "namespace-remote", Name: tenantnamespace.LimitRangeName},
+						&limitRange)).To(Succeed())
+					Expect(limitRange.Spec.Limits).To(HaveLen(1))
+					Expect(limitRange.Spec.Limits[0].Default.Memory().Cmp(resource.MustParse("512Mi"))).To(BeZero())
+					Expect(limitRange.GetAnnotations()).To(HaveKeyWithValue(liqoconst.RemoteNamespaceManagedByAnnotationKey, "tenant-namespace/name"))
+				})
+				It("should correctly ensure the networkpolicy is present", func() {
+					var policy networkingv1.NetworkPolicy
+					Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "namespace-remote", Name: tenantnamespace.NetworkPolicyName},
+						&policy)).To(Succeed())
+					Expect(policy.Spec.Ingress).To(HaveLen(1))
+					Expect(policy.Spec.Ingress[0].From).To(ContainElements(
+						networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: "10.100.0.0/16"}},
+						networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: "10.200.0.0/16"}},
+					))
+				})
+			})
+
 			When("the namespace already exists but it is not managed by the NamespaceMap", func() {
 				BeforeEach(func() {
 					namespace := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "namespace-remote"}}
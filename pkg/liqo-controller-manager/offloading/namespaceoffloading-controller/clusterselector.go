@@ -17,6 +17,7 @@ package nsoffctrl
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -26,6 +27,7 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
 	"github.com/liqotech/liqo/internal/crdReplicator/reflection"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
@@ -48,6 +50,12 @@ func (r *NamespaceOffloadingReconciler) enforceClusterSelector(ctx context.Conte
 			len(clusterIDs), len(clusterIDMap))
 	}
 
+	selected, err := r.selectClustersByPlacementPolicy(ctx, nsoff, virtualNodes)
+	if err != nil {
+		r.Recorder.Eventf(nsoff, corev1.EventTypeWarning, "Invalid", "Invalid PlacementPolicy: %v", err)
+		return fmt.Errorf("invalid PlacementPolicy: %w", err)
+	}
+
 	var returnErr error
 	for i := range virtualNodes.Items {
 		match, err := MatchVirtualNodeSelectorTerms(ctx, r.Client, &virtualNodes.Items[i], &nsoff.Spec.ClusterSelector)
@@ -57,6 +65,11 @@ func (r *NamespaceOffloadingReconciler) enforceClusterSelector(ctx context.Conte
 			return fmt.Errorf("invalid ClusterSelector: %w", err)
 		}
 
+		// In case a placement policy is specified, only the clusters chosen by the policy are targeted.
+		if nsoff.Spec.PlacementPolicy != nil {
+			match = slices.Contains(selected, virtualNodes.Items[i].Spec.ClusterID)
+		}
+
 		if match {
 			if err = addDesiredMapping(ctx, r.Client, nsoff.Namespace, r.remoteNamespaceName(nsoff),
 				clusterIDMap[string(virtualNodes.Items[i].Spec.ClusterID)]); err != nil {
@@ -74,6 +87,42 @@ func (r *NamespaceOffloadingReconciler) enforceClusterSelector(ctx context.Conte
 	return returnErr
 }
 
+// selectClustersByPlacementPolicy returns the clusters chosen according to the placement policy among the ones matching
+// the ClusterSelector, and records them in the NamespaceOffloading status. It is a no-op if no policy is specified.
+func (r *NamespaceOffloadingReconciler) selectClustersByPlacementPolicy(ctx context.Context,
+	nsoff *offloadingv1beta1.NamespaceOffloading, virtualNodes *offloadingv1beta1.VirtualNodeList) ([]liqov1beta1.ClusterID, error) {
+	if nsoff.Spec.PlacementPolicy == nil {
+		nsoff.Status.SelectedClusters = nil
+		return nil, nil
+	}
+
+	var candidates []clusterCandidate
+	for i := range virtualNodes.Items {
+		n, err := virtualnodeutils.ForgeFakeNodeFromVirtualNode(ctx, r.Client, &virtualNodes.Items[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to forge fake node from VirtualNode: %w", err)
+		}
+
+		match, err := k8shelper.MatchNodeSelectorTerms(n, &nsoff.Spec.ClusterSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ClusterSelector: %w", err)
+		}
+		if len(nsoff.Spec.ClusterSelector.NodeSelectorTerms) > 0 && !match {
+			continue
+		}
+
+		candidate, err := newClusterCandidate(ctx, r.Client, n, &virtualNodes.Items[i], nsoff.Spec.PlacementPolicy)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, *candidate)
+	}
+
+	nsoff.Status.SelectedClusters = selectClusters(candidates, nsoff.Spec.PlacementPolicy)
+	klog.V(4).Infof("NamespaceOffloading %q: clusters %v selected through the placement policy", klog.KObj(nsoff), nsoff.Status.SelectedClusters)
+	return nsoff.Status.SelectedClusters, nil
+}
+
 func (r *NamespaceOffloadingReconciler) getClusterIDMap(ctx context.Context) (map[string]*offloadingv1beta1.NamespaceMap, error) {
 	// Build the selector to consider only local NamespaceMaps.
 	metals := reflection.LocalResourcesLabelSelector()
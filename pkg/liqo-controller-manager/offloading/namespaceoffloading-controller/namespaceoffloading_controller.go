@@ -59,6 +59,7 @@ const (
 // +kubebuilder:rbac:groups=offloading.liqo.io,resources=virtualnode, verbs=get;list;watch;patch;update
 // +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core.liqo.io,resources=foreignclusters,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile implements the NamespaceOffloading reconciliation logic.
@@ -136,6 +137,8 @@ func (r *NamespaceOffloadingReconciler) SetupWithManager(mgr ctrl.Manager) error
 		Watches(&offloadingv1beta1.NamespaceMap{}, r.namespaceMapHandlers()).
 		Watches(&offloadingv1beta1.VirtualNode{}, r.enqueueAll()).
 		Watches(&corev1.Node{}, r.enqueueAll()).
+		// ForeignClusters are watched to react to changes of the cost/latency scores leveraged by the placement policy.
+		Watches(&liqov1beta1.ForeignCluster{}, r.enqueueAll()).
 		Complete(r)
 }
 
@@ -0,0 +1,144 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsoffctrl
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8shelper "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	foreignclusterutils "github.com/liqotech/liqo/pkg/utils/foreigncluster"
+)
+
+// clusterCandidate represents a remote cluster matching the ClusterSelector, along with its ranking score.
+type clusterCandidate struct {
+	clusterID liqov1beta1.ClusterID
+	labels    labels.Set
+	score     float64
+}
+
+// newClusterCandidate returns the candidate corresponding to the given virtual node, computing its ranking score
+// according to the preferred terms and the cost/latency weights of the placement policy.
+func newClusterCandidate(ctx context.Context, cl client.Client, node *corev1.Node,
+	virtualNode *offloadingv1beta1.VirtualNode, policy *offloadingv1beta1.PlacementPolicy) (*clusterCandidate, error) {
+	candidate := clusterCandidate{clusterID: virtualNode.Spec.ClusterID, labels: node.GetLabels()}
+
+	for i := range policy.PreferredClusters {
+		term := &policy.PreferredClusters[i]
+		match, err := k8shelper.MatchNodeSelectorTerms(node, &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{term.Preference}})
+		if err != nil {
+			return nil, fmt.Errorf("invalid preferred cluster term: %w", err)
+		}
+		if match {
+			candidate.score += float64(term.Weight)
+		}
+	}
+
+	if policy.CostWeight == 0 && policy.LatencyWeight == 0 {
+		return &candidate, nil
+	}
+
+	cost, err := getClusterScore(ctx, cl, virtualNode, liqoconst.ClusterCostAnnotationKey)
+	if err != nil {
+		return nil, err
+	}
+	latency, err := getClusterScore(ctx, cl, virtualNode, liqoconst.ClusterLatencyAnnotationKey)
+	if err != nil {
+		return nil, err
+	}
+
+	candidate.score -= float64(policy.CostWeight)*cost + float64(policy.LatencyWeight)*latency
+	return &candidate, nil
+}
+
+// getClusterScore returns the value of the given annotation, retrieved from the VirtualNode or, if not present,
+// from the corresponding ForeignCluster. Zero is returned if the annotation is not set in either of them.
+func getClusterScore(ctx context.Context, cl client.Client, virtualNode *offloadingv1beta1.VirtualNode, key string) (float64, error) {
+	value, found := virtualNode.GetAnnotations()[key]
+	if !found {
+		fc, err := foreignclusterutils.GetForeignClusterByID(ctx, cl, virtualNode.Spec.ClusterID)
+		if client.IgnoreNotFound(err) != nil {
+			return 0, fmt.Errorf("failed to retrieve ForeignCluster %q: %w", virtualNode.Spec.ClusterID, err)
+		}
+		if fc != nil {
+			value, found = fc.GetAnnotations()[key]
+		}
+	}
+
+	if !found {
+		return 0, nil
+	}
+
+	score, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		klog.Warningf("Invalid %q annotation for cluster %q: %v", key, virtualNode.Spec.ClusterID, err)
+		return 0, nil
+	}
+	return score, nil
+}
+
+// selectClusters returns the clusters chosen among the candidates, according to the given placement policy.
+// Candidates are ranked by decreasing score (ties are broken by cluster ID, to ensure a stable outcome), and then
+// selected as long as the maximum number of clusters and the spread constraints are not violated.
+func selectClusters(candidates []clusterCandidate, policy *offloadingv1beta1.PlacementPolicy) []liqov1beta1.ClusterID {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].clusterID < candidates[j].clusterID
+	})
+
+	selected := []liqov1beta1.ClusterID{}
+	domains := make([]map[string]int32, len(policy.SpreadConstraints))
+	for i := range domains {
+		domains[i] = map[string]int32{}
+	}
+
+outer:
+	for i := range candidates {
+		if policy.MaxClusters > 0 && len(selected) >= int(policy.MaxClusters) {
+			break
+		}
+
+		if slices.Contains(selected, candidates[i].clusterID) {
+			continue
+		}
+
+		for j := range policy.SpreadConstraints {
+			domain := candidates[i].labels[policy.SpreadConstraints[j].TopologyKey]
+			if domains[j][domain] >= policy.SpreadConstraints[j].MaxClustersPerDomain {
+				continue outer
+			}
+		}
+
+		for j := range policy.SpreadConstraints {
+			domains[j][candidates[i].labels[policy.SpreadConstraints[j].TopologyKey]]++
+		}
+		selected = append(selected, candidates[i].clusterID)
+	}
+
+	return selected
+}
@@ -0,0 +1,55 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsoffctrl
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/labels"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+)
+
+var _ = Describe("Placement policy", func() {
+	var candidates []clusterCandidate
+
+	BeforeEach(func() {
+		candidates = []clusterCandidate{
+			{clusterID: "cluster-a", labels: labels.Set{"region": regionA}, score: 10},
+			{clusterID: "cluster-b", labels: labels.Set{"region": regionA}, score: 20},
+			{clusterID: "cluster-c", labels: labels.Set{"region": regionB}, score: 5},
+			{clusterID: "cluster-d", labels: labels.Set{"region": regionB}, score: 5},
+		}
+	})
+
+	DescribeTable("selectClusters",
+		func(policy *offloadingv1beta1.PlacementPolicy, expected []liqov1beta1.ClusterID) {
+			Expect(selectClusters(candidates, policy)).To(Equal(expected))
+		},
+		Entry("should rank all clusters by score when no limit is configured",
+			&offloadingv1beta1.PlacementPolicy{},
+			[]liqov1beta1.ClusterID{"cluster-b", "cluster-a", "cluster-c", "cluster-d"}),
+		Entry("should select at most MaxClusters clusters",
+			&offloadingv1beta1.PlacementPolicy{MaxClusters: 2},
+			[]liqov1beta1.ClusterID{"cluster-b", "cluster-a"}),
+		Entry("should honor the spread constraints",
+			&offloadingv1beta1.PlacementPolicy{
+				MaxClusters:       2,
+				SpreadConstraints: []offloadingv1beta1.ClusterSpreadConstraint{{TopologyKey: "region", MaxClustersPerDomain: 1}},
+			},
+			[]liqov1beta1.ClusterID{"cluster-b", "cluster-c"}),
+	)
+})
@@ -21,8 +21,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils"
 	foreignclusterutils "github.com/liqotech/liqo/pkg/utils/foreigncluster"
 )
 
@@ -37,8 +40,14 @@ func (r *NamespaceOffloadingReconciler) enforceStatus(ctx context.Context, nsoff
 	// Remove the conditions for the clusters which do no longer exist.
 	ensureRemoteConditionsConsistence(nsoff, nsmaps)
 
+	// Retrieve the remote clusters whose workloads have been failed over.
+	degraded, err := r.getFailedOverClusters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the failed over clusters: %w", err)
+	}
+
 	// Fill the conditions corresponding to each remote cluster.
-	required, ready, failed := setRemoteConditionsForEveryCluster(nsoff, nsmaps)
+	required, ready, failed := setRemoteConditionsForEveryCluster(nsoff, nsmaps, degraded)
 
 	// Configure the global status given the conditions.
 	setNamespaceOffloadingStatus(nsoff, required, ready, failed)
@@ -51,6 +60,22 @@ func (r *NamespaceOffloadingReconciler) enforceStatus(ctx context.Context, nsoff
 	return nil
 }
 
+// getFailedOverClusters returns the set of remote clusters associated with a virtual node that has been failed over.
+func (r *NamespaceOffloadingReconciler) getFailedOverClusters(ctx context.Context) (map[string]bool, error) {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, client.MatchingLabels{liqoconst.TypeLabel: liqoconst.TypeNode}); err != nil {
+		return nil, err
+	}
+
+	degraded := map[string]bool{}
+	for i := range nodes.Items {
+		if utils.IsNodeFailedOver(&nodes.Items[i]) {
+			degraded[nodes.Items[i].Labels[liqoconst.RemoteClusterID]] = true
+		}
+	}
+	return degraded, nil
+}
+
 // remoteNamespaceName returns the remapped name corresponding to a given namespace.
 func (r *NamespaceOffloadingReconciler) remoteNamespaceName(nsoff *offloadingv1beta1.NamespaceOffloading) string {
 	switch nsoff.Spec.NamespaceMappingStrategy {
@@ -87,12 +112,12 @@ outer:
 // setRemoteConditionsForEveryCluster configures the conditions depending on whether the namespace has been offloaded, and its status.
 // It additionally returns the number of clusters selected as targets for offloading, and the number of ready and failed ones.
 func setRemoteConditionsForEveryCluster(nsoff *offloadingv1beta1.NamespaceOffloading,
-	nsmaps map[string]*offloadingv1beta1.NamespaceMap) (requestedCount, readyCount, failedCount uint) {
+	nsmaps map[string]*offloadingv1beta1.NamespaceMap, degraded map[string]bool) (requestedCount, readyCount, failedCount uint) {
 	if nsoff.Status.RemoteNamespacesConditions == nil {
 		nsoff.Status.RemoteNamespacesConditions = map[string]offloadingv1beta1.RemoteNamespaceConditions{}
 	}
 
-	for _, nsmap := range nsmaps {
+	for clusterID, nsmap := range nsmaps {
 		// Get the information for the NamespaceOffloadingRequired condition.
 		_, requested := nsmap.Spec.DesiredMapping[nsoff.Namespace]
 		if requested {
@@ -115,9 +140,13 @@ func setRemoteConditionsForEveryCluster(nsoff *offloadingv1beta1.NamespaceOffloa
 			delete(nsoff.Status.RemoteNamespacesConditions, nsmap.GetName())
 		} else {
 			// Otherwise, set the appropriate conditions.
-			setRemoteCondition(nsoff, nsmap.GetName(), nsoffRequiredCondition(requested))
+			setRemoteCondition(nsoff, nsmap.GetName(), nsoffRequiredCondition(requested, nsoff.Spec.PlacementPolicy != nil))
 			if requested || phase != "" {
 				setRemoteCondition(nsoff, nsmap.GetName(), nsoffReadyCondition(phase))
+				// The degraded condition is added only once the cluster is failed over, and then kept up-to-date.
+				if degraded[clusterID] || hasRemoteCondition(nsoff, nsmap.GetName(), offloadingv1beta1.NamespaceDegraded) {
+					setRemoteCondition(nsoff, nsmap.GetName(), nsoffDegradedCondition(degraded[clusterID]))
+				}
 			}
 		}
 	}
@@ -147,15 +176,34 @@ func setRemoteCondition(nsoff *offloadingv1beta1.NamespaceOffloading, nmname str
 	nsoff.Status.RemoteNamespacesConditions[nmname] = append(conditions, *condition)
 }
 
+// hasRemoteCondition returns whether the conditions referring to a single remote cluster include one of the given type.
+func hasRemoteCondition(nsoff *offloadingv1beta1.NamespaceOffloading, nmname string, conditionType offloadingv1beta1.RemoteNamespaceConditionType) bool {
+	for _, condition := range nsoff.Status.RemoteNamespacesConditions[nmname] {
+		if condition.Type == conditionType {
+			return true
+		}
+	}
+	return false
+}
+
 // nsoffRequiredCondition returns a condition stating whether the namespace shall be offladed to the remote cluster or not.
-func nsoffRequiredCondition(required bool) *offloadingv1beta1.RemoteNamespaceCondition {
+func nsoffRequiredCondition(required, placementPolicy bool) *offloadingv1beta1.RemoteNamespaceCondition {
 	condition := &offloadingv1beta1.RemoteNamespaceCondition{Type: offloadingv1beta1.NamespaceOffloadingRequired, LastTransitionTime: metav1.Now()}
 
-	if required {
+	switch {
+	case required && placementPolicy:
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "ClusterSelectedByPlacementPolicy"
+		condition.Message = "The remote cluster has been selected through the ClusterSelector and PlacementPolicy fields"
+	case required:
 		condition.Status = corev1.ConditionTrue
 		condition.Reason = "ClusterSelected"
 		condition.Message = "The remote cluster has been selected through the ClusterSelector field"
-	} else {
+	case placementPolicy:
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "ClusterNotSelectedByPlacementPolicy"
+		condition.Message = "The remote cluster has not been selected through the ClusterSelector and PlacementPolicy fields"
+	default:
 		condition.Status = corev1.ConditionFalse
 		condition.Reason = "ClusterNotSelected"
 		condition.Message = "The remote cluster has not been selected through the ClusterSelector field"
@@ -207,3 +255,20 @@ func setNamespaceOffloadingStatus(nsoff *offloadingv1beta1.NamespaceOffloading,
 		nsoff.Status.OffloadingPhase = offloadingv1beta1.InProgressOffloadingPhaseType
 	}
 }
+
+// nsoffDegradedCondition returns a condition stating whether the remote cluster hosting the namespace has been failed over.
+func nsoffDegradedCondition(degraded bool) *offloadingv1beta1.RemoteNamespaceCondition {
+	condition := &offloadingv1beta1.RemoteNamespaceCondition{Type: offloadingv1beta1.NamespaceDegraded, LastTransitionTime: metav1.Now()}
+
+	if degraded {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "ProviderUnavailable"
+		condition.Message = "The remote cluster is unavailable, and the hosted workloads have been failed over"
+	} else {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "ProviderAvailable"
+		condition.Message = "The remote cluster is available"
+	}
+
+	return condition
+}
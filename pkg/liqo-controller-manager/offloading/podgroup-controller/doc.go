@@ -0,0 +1,19 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package podgroupctrl contains a controller that implements the consumer side of the gang scheduling of
+// the pod groups. The members of a pod group are created with a scheduling gate, which is removed from all
+// of them only once the minimum number of members of the group exists, so that they are bound to the
+// virtual nodes altogether rather than one at a time.
+package podgroupctrl
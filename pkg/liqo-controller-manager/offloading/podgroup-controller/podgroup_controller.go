@@ -0,0 +1,129 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podgroupctrl
+
+import (
+	"context"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+// PodGroupReconciler releases the members of a pod group for scheduling once the minimum number of members has been created.
+// Reconcile requests are keyed by the namespace and the name of the pod group.
+type PodGroupReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile pod groups.
+func (r *PodGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.InNamespace(req.Namespace), client.MatchingLabels{consts.PodGroupLabelKey: req.Name}); err != nil {
+		klog.Errorf("an error occurred while listing the members of pod group %q: %v", req.NamespacedName, err)
+		return ctrl.Result{}, err
+	}
+
+	var members, gated []*corev1.Pod
+	minMember := 1
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !pod.DeletionTimestamp.IsZero() || pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded {
+			continue
+		}
+
+		members = append(members, pod)
+		if isGated(pod) {
+			gated = append(gated, pod)
+		}
+		if value, err := strconv.Atoi(pod.Annotations[consts.PodGroupMinMemberAnnotationKey]); err == nil && value > minMember {
+			minMember = value
+		}
+	}
+
+	if len(gated) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	if len(members) < minMember {
+		klog.V(4).Infof("pod group %q has %d out of %d members, waiting for the remaining ones", req.NamespacedName, len(members), minMember)
+		return ctrl.Result{}, nil
+	}
+
+	for _, pod := range gated {
+		original := pod.DeepCopy()
+		pod.Spec.SchedulingGates = removeGate(pod.Spec.SchedulingGates)
+		if err := r.Patch(ctx, pod, client.MergeFrom(original)); err != nil {
+			klog.Errorf("unable to release pod %q of pod group %q for scheduling: %v", klog.KObj(pod), req.NamespacedName, err)
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		r.Recorder.Eventf(pod, corev1.EventTypeNormal, "PodGroupReleased",
+			"Pod group %s reached %d out of %d members, releasing the pod for scheduling", req.Name, len(members), minMember)
+	}
+
+	klog.Infof("pod group %q released for scheduling (%d members)", req.NamespacedName, len(members))
+	return ctrl.Result{}, nil
+}
+
+// isGated returns whether the given pod is prevented from being scheduled by the pod group scheduling gate.
+func isGated(pod *corev1.Pod) bool {
+	for i := range pod.Spec.SchedulingGates {
+		if pod.Spec.SchedulingGates[i].Name == consts.PodGroupSchedulingGate {
+			return true
+		}
+	}
+	return false
+}
+
+// removeGate returns the given scheduling gates, except for the pod group one.
+func removeGate(gates []corev1.PodSchedulingGate) []corev1.PodSchedulingGate {
+	var output []corev1.PodSchedulingGate
+	for i := range gates {
+		if gates[i].Name != consts.PodGroupSchedulingGate {
+			output = append(output, gates[i])
+		}
+	}
+	return output
+}
+
+// SetupWithManager monitors the members of the pod groups, enqueueing the corresponding group.
+func (r *PodGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	members := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		_, found := object.GetLabels()[consts.PodGroupLabelKey]
+		return found
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlPodGroup).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(
+			func(_ context.Context, object client.Object) []reconcile.Request {
+				return []reconcile.Request{{NamespacedName: types.NamespacedName{
+					Namespace: object.GetNamespace(), Name: object.GetLabels()[consts.PodGroupLabelKey]}}}
+			}), builder.WithPredicates(members)).
+		Complete(r)
+}
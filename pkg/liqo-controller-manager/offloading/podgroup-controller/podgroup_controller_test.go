@@ -0,0 +1,103 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podgroupctrl
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubectl/pkg/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("PodGroup controller", func() {
+	const (
+		namespace = "namespace"
+		group     = "group"
+	)
+
+	var (
+		ctx        context.Context
+		cl         client.Client
+		reconciler *PodGroupReconciler
+		objects    []client.Object
+	)
+
+	forgeMember := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name, Namespace: namespace,
+				Labels:      map[string]string{consts.PodGroupLabelKey: group},
+				Annotations: map[string]string{consts.PodGroupAnnotationKey: group, consts.PodGroupMinMemberAnnotationKey: "3"},
+			},
+			Spec: corev1.PodSpec{SchedulingGates: []corev1.PodSchedulingGate{
+				{Name: "other"}, {Name: consts.PodGroupSchedulingGate}}},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	gates := func(name string) []corev1.PodSchedulingGate {
+		var pod corev1.Pod
+		Expect(cl.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &pod)).To(Succeed())
+		return pod.Spec.SchedulingGates
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		objects = nil
+	})
+
+	JustBeforeEach(func() {
+		cl = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build()
+		reconciler = &PodGroupReconciler{Client: cl, Recorder: record.NewFakeRecorder(10)}
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: group}})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	When("the minimum number of members has not been created yet", func() {
+		BeforeEach(func() {
+			objects = append(objects, forgeMember("foo", corev1.PodPending), forgeMember("bar", corev1.PodPending),
+				forgeMember("baz", corev1.PodFailed))
+		})
+
+		It("should keep all the members gated", func() {
+			Expect(gates("foo")).To(ContainElement(corev1.PodSchedulingGate{Name: consts.PodGroupSchedulingGate}))
+			Expect(gates("bar")).To(ContainElement(corev1.PodSchedulingGate{Name: consts.PodGroupSchedulingGate}))
+		})
+	})
+
+	When("the minimum number of members has been created", func() {
+		BeforeEach(func() {
+			objects = append(objects, forgeMember("foo", corev1.PodPending), forgeMember("bar", corev1.PodPending),
+				forgeMember("baz", corev1.PodPending))
+		})
+
+		It("should release all the members, preserving the other gates", func() {
+			for _, name := range []string{"foo", "bar", "baz"} {
+				Expect(gates(name)).To(ConsistOf(corev1.PodSchedulingGate{Name: "other"}))
+			}
+		})
+	})
+})
@@ -0,0 +1,167 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowjobctrl
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	jobutils "github.com/liqotech/liqo/pkg/utils/job"
+)
+
+// Reconciler reconciles a ShadowJob object.
+type Reconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=shadowjobs,verbs=get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=shadowjobs/finalizers,verbs=get;update;patch
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=shadowjobs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile ShadowJobs objects.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	nsName := req.NamespacedName
+	klog.V(4).Infof("reconcile shadowjob %s", nsName)
+	shadowJob := offloadingv1beta1.ShadowJob{}
+	if err := r.Get(ctx, nsName, &shadowJob); err != nil {
+		err = client.IgnoreNotFound(err)
+		if err == nil {
+			klog.V(4).Infof("skip: shadowjob %s not found", nsName)
+		}
+		return ctrl.Result{}, err
+	}
+
+	// update shadowjob labels to include the "managed-by", persisting them before the status is updated.
+	if shadowJob.Labels[consts.ManagedByLabelKey] != consts.ManagedByShadowJobValue {
+		original := shadowJob.DeepCopy()
+		shadowJob.SetLabels(labels.Merge(shadowJob.Labels, labels.Set{consts.ManagedByLabelKey: consts.ManagedByShadowJobValue}))
+		if err := r.Patch(ctx, &shadowJob, client.MergeFrom(original)); err != nil {
+			klog.Errorf("unable to update labels of shadowjob %q: %v", klog.KObj(&shadowJob), err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	existingJob := batchv1.Job{}
+	if err := r.Get(ctx, nsName, &existingJob); err != nil && !errors.IsNotFound(err) {
+		klog.Errorf("unable to get job %s: %v", nsName, err)
+		return ctrl.Result{}, err
+	} else if err == nil {
+		// Propagate the fields which can be modified at run time (e.g., to suspend or scale the job).
+		if err := r.enforceJobSpec(ctx, &shadowJob, &existingJob); err != nil {
+			klog.Errorf("unable to update job %q: %v", klog.KObj(&existingJob), err)
+			return ctrl.Result{}, err
+		}
+
+		// Update ShadowJob status same as Job status
+		if equality.Semantic.DeepEqual(shadowJob.Status.Job, existingJob.Status) {
+			return ctrl.Result{}, nil
+		}
+
+		shadowJob.Status.Job = *existingJob.Status.DeepCopy()
+		if err := r.Client.Status().Update(ctx, &shadowJob); err != nil {
+			klog.Errorf("unable to update status of shadowjob %q: %v", klog.KObj(&shadowJob), err)
+			return ctrl.Result{}, err
+		}
+
+		klog.V(4).Infof("updated status of shadowjob %q", klog.KObj(&shadowJob))
+		return ctrl.Result{}, nil
+	}
+
+	// Do not recreate the job if it already completed (e.g., it has been garbage collected after the TTL expiration).
+	if jobutils.IsFinished(&shadowJob.Status.Job) {
+		klog.V(4).Infof("skip: shadowjob %s already finished", nsName)
+		return ctrl.Result{}, nil
+	}
+
+	// ...Else, a brand new Job must be created, based on the shadowjob.
+	newJob := batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        nsName.Name,
+			Namespace:   nsName.Namespace,
+			Labels:      shadowJob.Labels,
+			Annotations: shadowJob.Annotations,
+		},
+		Spec: *shadowJob.Spec.Job.DeepCopy(),
+	}
+
+	// The job is handled by the built-in controller of the provider cluster, which also generates the selector.
+	newJob.Spec.ManagedBy = nil
+	newJob.Spec.Selector = nil
+	newJob.Spec.ManualSelector = nil
+
+	utilruntime.Must(ctrl.SetControllerReference(&shadowJob, &newJob, r.Scheme))
+
+	if err := r.Create(ctx, &newJob, client.FieldOwner("shadow-job")); err != nil {
+		klog.Errorf("unable to create job for shadowjob %q: %v", klog.KObj(&shadowJob), err)
+		return ctrl.Result{}, err
+	}
+
+	klog.Infof("created job %q for shadowjob %q", klog.KObj(&newJob), klog.KObj(&shadowJob))
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager monitors ShadowJobs and the Jobs they own.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, workers int) error {
+	// Trigger a reconciliation only for Delete and Update Events.
+	reconciledPredicates := predicate.Funcs{
+		DeleteFunc:  func(_ event.DeleteEvent) bool { return true },
+		CreateFunc:  func(_ event.CreateEvent) bool { return false },
+		UpdateFunc:  func(_ event.UpdateEvent) bool { return true },
+		GenericFunc: func(_ event.GenericEvent) bool { return false },
+	}
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlShadowJob).
+		For(&offloadingv1beta1.ShadowJob{}).
+		Owns(&batchv1.Job{}, builder.WithPredicates(reconciledPredicates)).
+		WithOptions(controller.Options{MaxConcurrentReconciles: workers}).
+		Complete(r)
+}
+
+// enforceJobSpec updates the job fields which can be modified at run time, if they differ from the ones specified in the shadowjob.
+func (r *Reconciler) enforceJobSpec(ctx context.Context, shadowJob *offloadingv1beta1.ShadowJob, job *batchv1.Job) error {
+	desired := &shadowJob.Spec.Job
+	if equality.Semantic.DeepEqual(job.Spec.Parallelism, desired.Parallelism) &&
+		equality.Semantic.DeepEqual(job.Spec.Suspend, desired.Suspend) &&
+		equality.Semantic.DeepEqual(job.Spec.ActiveDeadlineSeconds, desired.ActiveDeadlineSeconds) {
+		return nil
+	}
+
+	job.Spec.Parallelism = desired.Parallelism
+	job.Spec.Suspend = desired.Suspend
+	job.Spec.ActiveDeadlineSeconds = desired.ActiveDeadlineSeconds
+	if err := r.Update(ctx, job, client.FieldOwner("shadow-job")); err != nil {
+		return err
+	}
+
+	klog.Infof("updated job %q according to shadowjob %q", klog.KObj(job), klog.KObj(shadowJob))
+	return nil
+}
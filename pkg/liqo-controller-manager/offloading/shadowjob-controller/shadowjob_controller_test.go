@@ -0,0 +1,164 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowjobctrl
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubectl/pkg/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+var _ = Describe("ShadowJob Controller", func() {
+	const (
+		namespace = "default"
+		name      = "test-shadowjob"
+	)
+
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *Reconciler
+		shadowJob  *offloadingv1beta1.ShadowJob
+		objects    []client.Object
+
+		req = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		shadowJob = &offloadingv1beta1.ShadowJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				UID:       "shadowjob-uid",
+				Labels:    map[string]string{forge.LiqoOriginClusterIDKey: "origin-cluster-id"},
+			},
+			Spec: offloadingv1beta1.ShadowJobSpec{
+				Job: batchv1.JobSpec{
+					Parallelism:    ptr.To[int32](2),
+					ManagedBy:      ptr.To(consts.ShadowJobManagedBy),
+					ManualSelector: ptr.To(true),
+					Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"foo": "bar"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"foo": "bar"}},
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Containers:    []corev1.Container{{Name: "test", Image: "busybox"}},
+						},
+					},
+				},
+			},
+		}
+		objects = nil
+	})
+
+	JustBeforeEach(func() {
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(append(objects, shadowJob)...).
+			WithStatusSubresource(&offloadingv1beta1.ShadowJob{}).Build()
+		reconciler = &Reconciler{Client: fakeClient, Scheme: scheme.Scheme}
+	})
+
+	getJob := func() (*batchv1.Job, error) {
+		var job batchv1.Job
+		err := fakeClient.Get(ctx, req.NamespacedName, &job)
+		return &job, err
+	}
+
+	When("the job does not exist", func() {
+		JustBeforeEach(func() {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should create the job, owned by the shadowjob", func() {
+			job, err := getJob()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job.Labels).To(HaveKeyWithValue(consts.ManagedByLabelKey, consts.ManagedByShadowJobValue))
+			Expect(job.Labels).To(HaveKeyWithValue(forge.LiqoOriginClusterIDKey, "origin-cluster-id"))
+			Expect(job.Spec.Parallelism).To(PointTo(BeNumerically("==", 2)))
+			Expect(metav1.IsControlledBy(job, shadowJob)).To(BeTrue())
+		})
+
+		It("should delegate the job to the built-in controller", func() {
+			job, err := getJob()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job.Spec.ManagedBy).To(BeNil())
+			Expect(job.Spec.ManualSelector).To(BeNil())
+			Expect(job.Spec.Selector).To(BeNil())
+		})
+
+		When("the shadowjob is already finished", func() {
+			BeforeEach(func() {
+				shadowJob.Status.Job.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+			})
+
+			It("should not recreate the job", func() {
+				_, err := getJob()
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	When("the job already exists", func() {
+		BeforeEach(func() {
+			objects = append(objects, &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec:       batchv1.JobSpec{Parallelism: ptr.To[int32](1)},
+				Status:     batchv1.JobStatus{Active: 1, Succeeded: 3},
+			})
+		})
+
+		JustBeforeEach(func() {
+			_, err := reconciler.Reconcile(ctx, req)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should propagate the parallelism to the job", func() {
+			job, err := getJob()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job.Spec.Parallelism).To(PointTo(BeNumerically("==", 2)))
+		})
+
+		It("should mirror the job status in the shadowjob", func() {
+			var updated offloadingv1beta1.ShadowJob
+			Expect(fakeClient.Get(ctx, req.NamespacedName, &updated)).To(Succeed())
+			Expect(updated.Status.Job.Active).To(BeNumerically("==", 1))
+			Expect(updated.Status.Job.Succeeded).To(BeNumerically("==", 3))
+		})
+
+		It("should persist the managed-by label on the shadowjob", func() {
+			var updated offloadingv1beta1.ShadowJob
+			Expect(fakeClient.Get(ctx, req.NamespacedName, &updated)).To(Succeed())
+			Expect(updated.Labels).To(HaveKeyWithValue(consts.ManagedByLabelKey, consts.ManagedByShadowJobValue))
+			Expect(updated.Labels).To(HaveKeyWithValue(forge.LiqoOriginClusterIDKey, "origin-cluster-id"))
+		})
+	})
+})
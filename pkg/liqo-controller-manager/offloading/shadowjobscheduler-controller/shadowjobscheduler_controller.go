@@ -0,0 +1,178 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowjobschedulerctrl
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	k8shelper "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	offloadingutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/utils"
+	"github.com/liqotech/liqo/pkg/utils"
+	jobutils "github.com/liqotech/liqo/pkg/utils/job"
+)
+
+// retryPeriod is the period after which the scheduling of a job is retried, in case no virtual node is suitable.
+const retryPeriod = time.Minute
+
+// JobSchedulerReconciler schedules onto a virtual node the Jobs to be executed remotely through ShadowJobs which are not
+// explicitly bound to any of them, recording the selected one in the liqo.io/shadowjob-node annotation. The Jobs are
+// then picked up by the virtual kubelet of the selected virtual node.
+type JobSchedulerReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=namespaceoffloadings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile Job objects.
+func (r *JobSchedulerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var job batchv1.Job
+	if err := r.Get(ctx, req.NamespacedName, &job); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("job %q not found", req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("an error occurred while getting job %q: %v", req.NamespacedName, err)
+		return ctrl.Result{}, err
+	}
+
+	if !needsScheduling(&job) {
+		return ctrl.Result{}, nil
+	}
+
+	nodes, err := r.candidateNodes(ctx, &job)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(nodes) == 0 {
+		klog.Warningf("no virtual node is suitable for job %q, retrying in %v", req.NamespacedName, retryPeriod)
+		r.Recorder.Event(&job, corev1.EventTypeWarning, "FailedScheduling", "No virtual node is suitable for the remote execution of the job")
+		return ctrl.Result{RequeueAfter: retryPeriod}, nil
+	}
+
+	// Prefer the virtual node with the largest amount of allocatable CPU, falling back to the name to get a deterministic choice.
+	sort.Slice(nodes, func(i, j int) bool {
+		if cmp := nodes[i].Status.Allocatable.Cpu().Cmp(*nodes[j].Status.Allocatable.Cpu()); cmp != 0 {
+			return cmp > 0
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+	selected := nodes[0]
+
+	original := job.DeepCopy()
+	if job.Annotations == nil {
+		job.Annotations = map[string]string{}
+	}
+	job.Annotations[consts.ShadowJobNodeAnnotationKey] = selected.Name
+	if err := r.Patch(ctx, &job, client.MergeFrom(original)); err != nil {
+		klog.Errorf("unable to schedule job %q onto virtual node %q: %v", req.NamespacedName, selected.Name, err)
+		return ctrl.Result{}, err
+	}
+
+	klog.Infof("job %q scheduled onto virtual node %q", req.NamespacedName, selected.Name)
+	r.Recorder.Eventf(&job, corev1.EventTypeNormal, "Scheduled", "Scheduled onto virtual node %s", selected.Name)
+	return ctrl.Result{}, nil
+}
+
+// candidateNodes returns the ready virtual nodes the given job could be scheduled onto, according to the scheduling
+// constraints of its pod template. Only the virtual nodes associated with the clusters where the namespace
+// has been successfully offloaded are considered.
+func (r *JobSchedulerReconciler) candidateNodes(ctx context.Context, job *batchv1.Job) ([]*corev1.Node, error) {
+	var nsoff offloadingv1beta1.NamespaceOffloading
+	if err := r.Get(ctx, types.NamespacedName{Namespace: job.Namespace, Name: consts.DefaultNamespaceOffloadingName}, &nsoff); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("namespace %q is not offloaded, job %q has no candidate nodes", job.Namespace, klog.KObj(job))
+			return nil, nil
+		}
+		klog.Errorf("unable to get the namespaceoffloading for namespace %q: %v", job.Namespace, err)
+		return nil, err
+	}
+
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes, client.MatchingLabels{consts.TypeLabel: consts.TypeNode}); err != nil {
+		klog.Errorf("unable to list virtual nodes: %v", err)
+		return nil, err
+	}
+
+	pod := &corev1.Pod{ObjectMeta: job.Spec.Template.ObjectMeta, Spec: job.Spec.Template.Spec}
+
+	var candidates []*corev1.Node
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if !node.DeletionTimestamp.IsZero() || node.Spec.Unschedulable || !utils.IsNodeReady(node) ||
+			!offloadingutils.IsRemoteNamespaceReady(&nsoff, node.Labels[consts.RemoteClusterID]) || !couldBeScheduledOn(pod, node) {
+			continue
+		}
+		candidates = append(candidates, node)
+	}
+	return candidates, nil
+}
+
+// needsScheduling returns whether the given job is to be executed remotely through a ShadowJob, but it is not yet bound to any virtual node.
+func needsScheduling(job *batchv1.Job) bool {
+	if !isShadowJob(job) || job.Spec.Template.Spec.NodeName != "" || !job.DeletionTimestamp.IsZero() || jobutils.IsFinished(&job.Status) {
+		return false
+	}
+	_, scheduled := job.Annotations[consts.ShadowJobNodeAnnotationKey]
+	return !scheduled
+}
+
+// isShadowJob returns whether the given job is to be executed remotely through a ShadowJob.
+func isShadowJob(job *batchv1.Job) bool {
+	return job.Spec.ManagedBy != nil && *job.Spec.ManagedBy == consts.ShadowJobManagedBy
+}
+
+// couldBeScheduledOn returns whether the given pod tolerates the taints of the given node and matches its required node affinity.
+func couldBeScheduledOn(pod *corev1.Pod, node *corev1.Node) bool {
+	if _, untolerated := k8shelper.FindMatchingUntoleratedTaint(node.Spec.Taints, pod.Spec.Tolerations, func(t *corev1.Taint) bool {
+		return t.Effect == corev1.TaintEffectNoSchedule || t.Effect == corev1.TaintEffectNoExecute
+	}); untolerated {
+		return false
+	}
+	match, err := nodeaffinity.GetRequiredNodeAffinity(pod).Match(node)
+	return err == nil && match
+}
+
+// SetupWithManager monitors the Jobs to be executed remotely through ShadowJobs.
+func (r *JobSchedulerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	shadowJobs := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		job, ok := object.(*batchv1.Job)
+		return ok && isShadowJob(job)
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlShadowJobScheduler).
+		For(&batchv1.Job{}, builder.WithPredicates(shadowJobs)).
+		Complete(r)
+}
@@ -0,0 +1,172 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowjobschedulerctrl
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubectl/pkg/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("ShadowJobSchedulerController", func() {
+	const (
+		ns      string = "default"
+		jobName string = "batch"
+	)
+
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *JobSchedulerReconciler
+		job        *batchv1.Job
+		objects    []client.Object
+		res        ctrl.Result
+
+		reqJob = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: jobName}}
+
+		newNode = func(name, clusterID, cpu string) *corev1.Node {
+			return &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   name,
+					Labels: map[string]string{consts.TypeLabel: consts.TypeNode, consts.RemoteClusterID: clusterID},
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+					Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				},
+			}
+		}
+
+		scheduledNode = func() string {
+			var current batchv1.Job
+			Expect(fakeClient.Get(ctx, reqJob.NamespacedName, &current)).To(Succeed())
+			return current.Annotations[consts.ShadowJobNodeAnnotationKey]
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		job = &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: jobName, Namespace: ns},
+			Spec: batchv1.JobSpec{
+				ManagedBy: ptr.To(consts.ShadowJobManagedBy),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						RestartPolicy: corev1.RestartPolicyNever,
+						Containers:    []corev1.Container{{Name: "batch", Image: "batch:v1"}},
+					},
+				},
+			},
+		}
+		objects = []client.Object{
+			newNode("liqo-cluster-a", "cluster-a", "4"), newNode("liqo-cluster-b", "cluster-b", "8"),
+			newNode("liqo-cluster-failed", "cluster-failed", "16"),
+			&offloadingv1beta1.NamespaceOffloading{
+				ObjectMeta: metav1.ObjectMeta{Name: consts.DefaultNamespaceOffloadingName, Namespace: ns},
+				Status: offloadingv1beta1.NamespaceOffloadingStatus{RemoteNamespacesConditions: map[string]offloadingv1beta1.RemoteNamespaceConditions{
+					"cluster-a":      {{Type: offloadingv1beta1.NamespaceReady, Status: corev1.ConditionTrue}},
+					"cluster-b":      {{Type: offloadingv1beta1.NamespaceReady, Status: corev1.ConditionTrue}},
+					"cluster-failed": {{Type: offloadingv1beta1.NamespaceReady, Status: corev1.ConditionFalse}},
+				}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(objects, job)...).Build()
+		reconciler = &JobSchedulerReconciler{Client: fakeClient, Recorder: record.NewFakeRecorder(10)}
+
+		var err error
+		res, err = reconciler.Reconcile(ctx, reqJob)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	When("multiple virtual nodes are suitable", func() {
+		It("should schedule the job onto the one with the largest allocatable CPU", func() {
+			Expect(scheduledNode()).To(Equal("liqo-cluster-b"))
+		})
+	})
+
+	When("the pod template requires a specific cluster", func() {
+		BeforeEach(func() {
+			job.Spec.Template.Spec.NodeSelector = map[string]string{consts.RemoteClusterID: "cluster-a"}
+		})
+
+		It("should schedule the job onto a virtual node matching the constraints", func() {
+			Expect(scheduledNode()).To(Equal("liqo-cluster-a"))
+		})
+	})
+
+	When("the virtual nodes are tainted", func() {
+		BeforeEach(func() {
+			for _, object := range objects {
+				if node, ok := object.(*corev1.Node); ok {
+					node.Spec.Taints = []corev1.Taint{{Key: consts.VirtualNodeTolerationKey, Effect: corev1.TaintEffectNoExecute}}
+				}
+			}
+		})
+
+		It("should not schedule the job, if the taints are not tolerated", func() {
+			Expect(scheduledNode()).To(BeEmpty())
+			Expect(res.RequeueAfter).To(Equal(retryPeriod))
+		})
+	})
+
+	When("the job is explicitly bound to a virtual node", func() {
+		BeforeEach(func() {
+			job.Spec.Template.Spec.NodeName = "liqo-cluster-a"
+		})
+
+		It("should not schedule the job", func() {
+			Expect(scheduledNode()).To(BeEmpty())
+		})
+	})
+
+	When("the job is not to be executed remotely", func() {
+		BeforeEach(func() {
+			job.Spec.ManagedBy = nil
+		})
+
+		It("should not schedule the job", func() {
+			Expect(scheduledNode()).To(BeEmpty())
+		})
+	})
+
+	When("the namespace is not offloaded", func() {
+		BeforeEach(func() {
+			objects = objects[:3]
+		})
+
+		It("should not schedule the job, and retry later", func() {
+			Expect(scheduledNode()).To(BeEmpty())
+			Expect(res.RequeueAfter).To(Equal(retryPeriod))
+		})
+	})
+})
@@ -39,6 +39,7 @@ import (
 	"github.com/liqotech/liqo/pkg/utils"
 	clientutils "github.com/liqotech/liqo/pkg/utils/clients"
 	ipamips "github.com/liqotech/liqo/pkg/utils/ipam/mapping"
+	podutils "github.com/liqotech/liqo/pkg/utils/pod"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
 )
 
@@ -51,6 +52,7 @@ type Reconciler struct {
 // +kubebuilder:rbac:groups=offloading.liqo.io,resources=shadowpods,verbs=get;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups=offloading.liqo.io,resources=shadowpods/finalizers,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods/ephemeralcontainers;pods/resize,verbs=update;patch
 // +kubebuilder:rbac:groups=offloading.liqo.io,resources=shadowpods/status,verbs=get;update;patch
 
 // Reconcile ShadowPods objects.
@@ -98,6 +100,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			return ctrl.Result{}, err
 		}
 
+		// Propagate the ephemeral containers added to the shadowpod (e.g., through kubectl debug).
+		if err := r.enforceEphemeralContainers(ctx, &shadowPod, &existingPod); err != nil {
+			klog.Errorf("unable to update ephemeral containers of pod %q: %v", klog.KObj(&existingPod), err)
+			return ctrl.Result{}, err
+		}
+
+		// Propagate the in-place resize of the shadowpod containers.
+		if err := r.enforceContainersResources(ctx, &shadowPod, &existingPod); err != nil {
+			klog.Errorf("unable to resize pod %q: %v", klog.KObj(&existingPod), err)
+			return ctrl.Result{}, err
+		}
+
 		// Update ShadowPod status same as Pod status
 		shadowPod.Status.Phase = existingPod.Status.DeepCopy().Phase
 		if newErr := r.Client.Status().Update(ctx, &shadowPod); newErr != nil {
@@ -124,9 +138,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			Labels:      shadowPod.Labels,
 			Annotations: shadowPod.Annotations,
 		},
-		Spec: shadowPod.Spec.Pod,
+		Spec: *shadowPod.Spec.Pod.DeepCopy(),
 	}
 
+	// Ephemeral containers cannot be specified at creation time, and they are added afterwards through the dedicated subresource.
+	newPod.Spec.EphemeralContainers = nil
+
 	// Mutate PodSpec
 	if err := r.mutatePodSpec(ctx, &newPod.Spec, remoteClusterID); err != nil {
 		klog.Errorf("unable to mutate pod spec for shadowpod %q: %v", klog.KObj(&shadowPod), err)
@@ -142,7 +159,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	klog.Infof("created pod %q for shadowpod %q", klog.KObj(&newPod), klog.KObj(&shadowPod))
 
-	return ctrl.Result{}, nil
+	// Requeue the shadowpod, to add the ephemeral containers to the newly created pod.
+	return ctrl.Result{Requeue: len(shadowPod.Spec.Pod.EphemeralContainers) > 0}, nil
 }
 
 // SetupWithManager monitors only updates on ShadowPods.
@@ -161,6 +179,55 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, workers int) error {
 		Complete(r)
 }
 
+// enforceEphemeralContainers adds to the pod the ephemeral containers specified in the shadowpod and not yet present.
+// Ephemeral containers can be neither modified nor removed, hence the ones already present are left untouched.
+func (r *Reconciler) enforceEphemeralContainers(ctx context.Context, shadowPod *offloadingv1beta1.ShadowPod, pod *corev1.Pod) error {
+	updated := pod.DeepCopy()
+	for i := range shadowPod.Spec.Pod.EphemeralContainers {
+		container := &shadowPod.Spec.Pod.EphemeralContainers[i]
+		if !slices.ContainsFunc(pod.Spec.EphemeralContainers, func(ec corev1.EphemeralContainer) bool { return ec.Name == container.Name }) {
+			updated.Spec.EphemeralContainers = append(updated.Spec.EphemeralContainers, *container.DeepCopy())
+		}
+	}
+
+	if len(updated.Spec.EphemeralContainers) == len(pod.Spec.EphemeralContainers) {
+		return nil
+	}
+
+	if err := r.SubResource("ephemeralcontainers").Update(ctx, updated); err != nil {
+		return err
+	}
+
+	klog.Infof("added %d ephemeral containers to pod %q", len(updated.Spec.EphemeralContainers)-len(pod.Spec.EphemeralContainers), klog.KObj(pod))
+	updated.DeepCopyInto(pod)
+	return nil
+}
+
+// enforceContainersResources resizes in-place the pod containers whose resources differ from the ones specified in the shadowpod.
+func (r *Reconciler) enforceContainersResources(ctx context.Context, shadowPod *offloadingv1beta1.ShadowPod, pod *corev1.Pod) error {
+	if podutils.AreContainersResourcesEqual(pod.Spec.Containers, shadowPod.Spec.Pod.Containers) {
+		return nil
+	}
+
+	updated := pod.DeepCopy()
+	updated.Spec.Containers = forge.RemoteContainersResources(updated.Spec.Containers, shadowPod.Spec.Pod.Containers)
+
+	err := r.SubResource("resize").Update(ctx, updated)
+	if errors.IsNotFound(err) {
+		// The resize subresource is not available in older Kubernetes versions,
+		// where in-place resize is performed directly updating the pod specifications.
+		klog.V(4).Infof("resize subresource not available for pod %q, falling back to pod update", klog.KObj(pod))
+		err = r.Update(ctx, updated, client.FieldOwner("shadow-pod"))
+	}
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("resized pod %q according to shadowpod %q", klog.KObj(pod), klog.KObj(shadowPod))
+	updated.DeepCopyInto(pod)
+	return nil
+}
+
 func (r *Reconciler) mutatePodSpec(ctx context.Context,
 	podSpec *corev1.PodSpec, remoteClusterID liqov1beta1.ClusterID) error {
 	if len(podSpec.HostAliases) == 0 {
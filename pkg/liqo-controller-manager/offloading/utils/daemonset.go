@@ -0,0 +1,67 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+// DaemonSetTemplateHash returns the hash of the given pod template, to detect whether the offloaded pods are outdated.
+func DaemonSetTemplateHash(template *corev1.PodTemplateSpec) (string, error) {
+	marshaled, err := json.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(marshaled)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// OffloadedDaemonSetReference returns the reference to the DaemonSet the given pod has been created on behalf of by Liqo,
+// or nil if the pod is not labeled accordingly, it does not carry the template hash, or it is not owned by that DaemonSet.
+// The DaemonSet is not the controller of the pod, as the DaemonSet controller would otherwise claim and delete it,
+// since the virtual nodes are not tolerated by the template. The controller is the virtual node the pod is bound to.
+func OffloadedDaemonSetReference(pod *corev1.Pod) *metav1.OwnerReference {
+	name, found := pod.Labels[consts.DaemonSetOffloadingLabelKey]
+	if !found {
+		return nil
+	}
+	if _, found := pod.Annotations[consts.DaemonSetOffloadingTemplateHashAnnotationKey]; !found {
+		return nil
+	}
+
+	for i := range pod.OwnerReferences {
+		owner := &pod.OwnerReferences[i]
+		if owner.Kind == "DaemonSet" && owner.APIVersion == appsv1.SchemeGroupVersion.String() && owner.Name == name &&
+			(owner.Controller == nil || !*owner.Controller) {
+			return owner
+		}
+	}
+	return nil
+}
+
+// IsOffloadedDaemonSetPod returns whether the given pod has been created by Liqo on behalf of the given DaemonSet.
+func IsOffloadedDaemonSetPod(pod *corev1.Pod, ds *appsv1.DaemonSet) bool {
+	owner := OffloadedDaemonSetReference(pod)
+	return owner != nil && owner.UID == ds.UID
+}
@@ -0,0 +1,31 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+)
+
+// IsRemoteNamespaceReady returns whether the remote namespace has been successfully created in the given cluster.
+func IsRemoteNamespaceReady(nsoff *offloadingv1beta1.NamespaceOffloading, clusterID string) bool {
+	for _, condition := range nsoff.Status.RemoteNamespacesConditions[clusterID] {
+		if condition.Type == offloadingv1beta1.NamespaceReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -128,7 +128,10 @@ func hasToBeCordoned(resourceSlice *authv1beta1.ResourceSlice) bool {
 	v, ok = resourceSlice.Annotations[consts.CordonTenantAnnotation]
 	tenantCordoned := ok && !isFalse(v)
 
-	return sliceCordoned || tenantCordoned
+	v, ok = resourceSlice.Annotations[consts.CordonLeaseAnnotation]
+	leaseCordoned := ok && !isFalse(v)
+
+	return sliceCordoned || tenantCordoned || leaseCordoned
 }
 
 // SetupWithManager register the QuotaCreatorReconciler to the manager.
@@ -0,0 +1,19 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tenantgccontroller implements the provider-side controller that garbage collects the resources
+// offloaded by consumer clusters which disappeared without unpeering. Tenants whose consumer has been inactive
+// for longer than a configurable TTL are first quarantined (i.e., cordoned), and then, after a further TTL,
+// the remote namespaces hosting the offloaded resources are deleted.
+package tenantgccontroller
@@ -0,0 +1,331 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenantgccontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/internal/crdReplicator/reflection"
+	"github.com/liqotech/liqo/pkg/consts"
+	fcutils "github.com/liqotech/liqo/pkg/utils/foreigncluster"
+)
+
+// heartbeatCheckInterval is the interval between two consecutive checks of the heartbeat of a consumer cluster.
+const heartbeatCheckInterval = time.Minute
+
+// TenantGCReconciler quarantines the tenants whose consumer cluster has been unreachable for longer than the
+// configured TTL, and eventually garbage collects the corresponding remote namespaces.
+type TenantGCReconciler struct {
+	client.Client
+	Scheme         *runtime.Scheme
+	EventsRecorder record.EventRecorder
+
+	// APIReader is used to retrieve the heartbeat Leases, without caching all the Leases of the cluster.
+	APIReader client.Reader
+
+	// QuarantineTTL is the period after the expiration of the heartbeat Lease after which a tenant is quarantined.
+	QuarantineTTL time.Duration
+	// DeletionTTL is the period after which the remote namespaces of a quarantined tenant are deleted.
+	DeletionTTL time.Duration
+	// DryRun configures the controller to only report the resources that would be deleted.
+	DryRun bool
+}
+
+// NewTenantGCReconciler returns a new TenantGCReconciler.
+func NewTenantGCReconciler(cl client.Client, apiReader client.Reader, s *runtime.Scheme, er record.EventRecorder,
+	quarantineTTL, deletionTTL time.Duration, dryRun bool) *TenantGCReconciler {
+	return &TenantGCReconciler{
+		Client:         cl,
+		Scheme:         s,
+		EventsRecorder: er,
+		APIReader:      apiReader,
+
+		QuarantineTTL: quarantineTTL,
+		DeletionTTL:   deletionTTL,
+		DryRun:        dryRun,
+	}
+}
+
+// cluster-role
+// +kubebuilder:rbac:groups=authentication.liqo.io,resources=tenants,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get
+// +kubebuilder:rbac:groups=core.liqo.io,resources=foreignclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=namespacemaps,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=shadowpods;shadowjobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=services;secrets;persistentvolumeclaims,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// Reconcile checks the heartbeat of the consumer cluster associated with a Tenant, and garbage collects its resources if necessary.
+func (r *TenantGCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	tenant := &authv1beta1.Tenant{}
+	if err := r.Get(ctx, req.NamespacedName, tenant); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("unable to get the Tenant %q: %w", req.NamespacedName, err)
+	}
+
+	if !tenant.DeletionTimestamp.IsZero() || tenant.Status.TenantNamespace == "" {
+		return ctrl.Result{}, nil
+	}
+
+	nsmaps, err := r.getNamespaceMaps(ctx, tenant)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	lastHeartbeat, lost, err := r.getLastHeartbeat(ctx, tenant)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	inactivity := time.Since(lastHeartbeat)
+	quarantinedAt, quarantined := getQuarantineTime(tenant)
+
+	switch {
+	case !lost || inactivity < r.QuarantineTTL:
+		// Periodically check the heartbeat, to detect the loss of connectivity with the consumer cluster.
+		requeue := r.QuarantineTTL - inactivity
+		if !lost || requeue > heartbeatCheckInterval {
+			requeue = heartbeatCheckInterval
+		}
+
+		// The consumer cluster is active, hence restore the tenant in case it had been previously quarantined.
+		if quarantined {
+			if err := r.restore(ctx, tenant); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{RequeueAfter: requeue}, nil
+
+	case !quarantined:
+		// Do not interfere with tenants explicitly cordoned or drained by the administrator.
+		if tenant.Spec.TenantCondition != authv1beta1.TenantConditionActive {
+			return ctrl.Result{}, nil
+		}
+		if err := r.quarantine(ctx, tenant, inactivity); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.report(ctx, tenant, nsmaps, "Quarantined tenant: the following resources will be deleted after %v", r.DeletionTTL)
+		return ctrl.Result{RequeueAfter: r.DeletionTTL}, nil
+
+	case time.Since(quarantinedAt) < r.DeletionTTL:
+		return ctrl.Result{RequeueAfter: r.DeletionTTL - time.Since(quarantinedAt)}, nil
+
+	case r.DryRun:
+		r.report(ctx, tenant, nsmaps, "Dry-run: the following resources would be deleted")
+		return ctrl.Result{}, nil
+
+	default:
+		return ctrl.Result{}, r.collect(ctx, tenant, nsmaps)
+	}
+}
+
+// getNamespaceMaps returns the NamespaceMaps replicated by the consumer cluster in the tenant namespace.
+func (r *TenantGCReconciler) getNamespaceMaps(ctx context.Context, tenant *authv1beta1.Tenant) ([]offloadingv1beta1.NamespaceMap, error) {
+	var nsmaps offloadingv1beta1.NamespaceMapList
+	if err := r.List(ctx, &nsmaps, client.InNamespace(tenant.Status.TenantNamespace), replicatedResourcesSelector()); err != nil {
+		return nil, fmt.Errorf("unable to list the NamespaceMaps of Tenant %q: %w", tenant.Name, err)
+	}
+	return nsmaps.Items, nil
+}
+
+// getLastHeartbeat returns the last renewal time of the heartbeat Lease maintained by the consumer cluster associated
+// with the given Tenant in the tenant namespace, and whether the connectivity with the consumer cluster is confirmed to be lost.
+// The connectivity is not considered lost if the heartbeat Lease has never been created (e.g., by consumer clusters not
+// supporting it), as well as if the API server of the consumer cluster is reported as reachable by the ForeignCluster.
+func (r *TenantGCReconciler) getLastHeartbeat(ctx context.Context, tenant *authv1beta1.Tenant) (last time.Time, lost bool, err error) {
+	var lease coordinationv1.Lease
+	key := types.NamespacedName{Namespace: tenant.Status.TenantNamespace, Name: consts.ConsumerHeartbeatLeaseName}
+	if err := r.APIReader.Get(ctx, key, &lease); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.V(4).Infof("Heartbeat lease of Tenant %q not found, assuming the consumer cluster is active", tenant.Name)
+			return time.Now(), false, nil
+		}
+		return time.Time{}, false, fmt.Errorf("unable to get the heartbeat lease of Tenant %q: %w", tenant.Name, err)
+	}
+
+	last = lease.CreationTimestamp.Time
+	if lease.Spec.RenewTime != nil {
+		last = lease.Spec.RenewTime.Time
+	}
+	if lease.Spec.LeaseDurationSeconds != nil && time.Since(last) < time.Duration(*lease.Spec.LeaseDurationSeconds)*time.Second {
+		return last, false, nil
+	}
+
+	fc, err := fcutils.GetForeignClusterByID(ctx, r.Client, tenant.Spec.ClusterID)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return time.Time{}, false, fmt.Errorf("unable to get the ForeignCluster of Tenant %q: %w", tenant.Name, err)
+	case fcutils.GetAPIServerStatus(fc) == liqov1beta1.ConditionStatusEstablished:
+		klog.V(4).Infof("Heartbeat lease of Tenant %q expired, but the API server of the consumer cluster is reachable", tenant.Name)
+		return time.Now(), false, nil
+	}
+
+	return last, true, nil
+}
+
+// quarantine cordons the given Tenant, recording the time it has been quarantined.
+func (r *TenantGCReconciler) quarantine(ctx context.Context, tenant *authv1beta1.Tenant, inactivity time.Duration) error {
+	original := tenant.DeepCopy()
+	tenant.Spec.TenantCondition = authv1beta1.TenantConditionCordoned
+	if tenant.Annotations == nil {
+		tenant.Annotations = map[string]string{}
+	}
+	tenant.Annotations[consts.TenantQuarantinedAnnotation] = time.Now().UTC().Format(time.RFC3339)
+
+	if err := r.Patch(ctx, tenant, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("unable to quarantine Tenant %q: %w", tenant.Name, err)
+	}
+
+	klog.Warningf("Tenant %q quarantined, as the consumer cluster %q has been unreachable for %v",
+		tenant.Name, tenant.Spec.ClusterID, inactivity.Round(time.Second))
+	r.EventsRecorder.Eventf(tenant, corev1.EventTypeWarning, "TenantQuarantined",
+		"The consumer cluster has been unreachable for %v", inactivity.Round(time.Second))
+	return nil
+}
+
+// restore uncordons the given quarantined Tenant, since the consumer cluster is active again.
+func (r *TenantGCReconciler) restore(ctx context.Context, tenant *authv1beta1.Tenant) error {
+	original := tenant.DeepCopy()
+	if tenant.Spec.TenantCondition == authv1beta1.TenantConditionCordoned {
+		tenant.Spec.TenantCondition = authv1beta1.TenantConditionActive
+	}
+	delete(tenant.Annotations, consts.TenantQuarantinedAnnotation)
+
+	if err := r.Patch(ctx, tenant, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("unable to restore Tenant %q: %w", tenant.Name, err)
+	}
+
+	klog.Infof("Tenant %q restored, as the consumer cluster %q is active again", tenant.Name, tenant.Spec.ClusterID)
+	r.EventsRecorder.Event(tenant, corev1.EventTypeNormal, "TenantRestored", "The consumer cluster is active again")
+	return nil
+}
+
+// collect deletes the NamespaceMaps replicated by the consumer cluster, hence triggering the deletion of the
+// corresponding remote namespaces, along with all the resources offloaded therein.
+func (r *TenantGCReconciler) collect(ctx context.Context, tenant *authv1beta1.Tenant, nsmaps []offloadingv1beta1.NamespaceMap) error {
+	if len(nsmaps) == 0 {
+		return nil
+	}
+
+	r.report(ctx, tenant, nsmaps, "Deleting the following resources")
+	for i := range nsmaps {
+		if !nsmaps[i].DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := client.IgnoreNotFound(r.Delete(ctx, &nsmaps[i])); err != nil {
+			return fmt.Errorf("unable to delete NamespaceMap %q: %w", klog.KObj(&nsmaps[i]), err)
+		}
+		klog.Infof("NamespaceMap %q of quarantined Tenant %q deleted", klog.KObj(&nsmaps[i]), tenant.Name)
+	}
+
+	r.EventsRecorder.Event(tenant, corev1.EventTypeWarning, "TenantCollected", "The remote namespaces of the inactive consumer cluster have been deleted")
+	return nil
+}
+
+// report outputs the remote namespaces (and the resources they contain) associated with the given NamespaceMaps.
+func (r *TenantGCReconciler) report(ctx context.Context, tenant *authv1beta1.Tenant,
+	nsmaps []offloadingv1beta1.NamespaceMap, format string, args ...interface{}) {
+	var entries []string
+	for i := range nsmaps {
+		for _, mapping := range nsmaps[i].Status.CurrentMapping {
+			entries = append(entries, fmt.Sprintf("namespace %q (%s)", mapping.RemoteNamespace, r.countResources(ctx, mapping.RemoteNamespace)))
+		}
+	}
+
+	if len(entries) == 0 {
+		entries = append(entries, "no remote namespaces")
+	}
+
+	message := fmt.Sprintf(format, args...) + ": " + strings.Join(entries, ", ")
+	klog.Infof("Tenant %q: %s", tenant.Name, message)
+	r.EventsRecorder.Event(tenant, corev1.EventTypeWarning, "OrphanedResourcesReport", message)
+}
+
+// countResources returns a summary of the offloaded resources hosted by the given namespace.
+func (r *TenantGCReconciler) countResources(ctx context.Context, namespace string) string {
+	lists := []struct {
+		kind string
+		list client.ObjectList
+	}{
+		{"shadowpods", &offloadingv1beta1.ShadowPodList{}},
+		{"shadowjobs", &offloadingv1beta1.ShadowJobList{}},
+		{"services", &corev1.ServiceList{}},
+		{"secrets", &corev1.SecretList{}},
+		{"persistentvolumeclaims", &corev1.PersistentVolumeClaimList{}},
+	}
+
+	counts := make([]string, 0, len(lists))
+	for i := range lists {
+		if err := r.List(ctx, lists[i].list, client.InNamespace(namespace)); err != nil {
+			klog.Warningf("Failed to list the %s in namespace %q: %v", lists[i].kind, namespace, err)
+			continue
+		}
+		counts = append(counts, fmt.Sprintf("%d %s", meta.LenList(lists[i].list), lists[i].kind))
+	}
+	return strings.Join(counts, ", ")
+}
+
+// getQuarantineTime returns the time the given Tenant has been quarantined, and whether it is quarantined.
+func getQuarantineTime(tenant *authv1beta1.Tenant) (time.Time, bool) {
+	value, found := tenant.Annotations[consts.TenantQuarantinedAnnotation]
+	if !found {
+		return time.Time{}, false
+	}
+
+	quarantinedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		klog.Warningf("Invalid %q annotation for Tenant %q: %v", consts.TenantQuarantinedAnnotation, tenant.Name, err)
+		return time.Now(), true
+	}
+	return quarantinedAt, true
+}
+
+func replicatedResourcesSelector() client.MatchingLabelsSelector {
+	selector := reflection.ReplicatedResourcesLabelSelector()
+	s, err := metav1.LabelSelectorAsSelector(&selector)
+	if err != nil {
+		// The selector is statically defined, hence it is always valid.
+		return client.MatchingLabelsSelector{Selector: labels.Nothing()}
+	}
+	return client.MatchingLabelsSelector{Selector: s}
+}
+
+// SetupWithManager register the TenantGCReconciler to the manager.
+func (r *TenantGCReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named(consts.CtrlTenantGC).
+		For(&authv1beta1.Tenant{}).
+		Complete(r)
+}
@@ -0,0 +1,248 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenantgccontroller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubectl/pkg/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("TenantGCController", func() {
+	const (
+		tenantName    = "tenant"
+		tenantNs      = "liqo-tenant-consumer"
+		remoteNs      = "foo-consumer"
+		consumerID    = "consumer"
+		quarantineTTL = time.Hour
+		deletionTTL   = 2 * time.Hour
+	)
+
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *TenantGCReconciler
+		result     ctrl.Result
+		err        error
+
+		tenant   *authv1beta1.Tenant
+		lease    *coordinationv1.Lease
+		nsmap    *offloadingv1beta1.NamespaceMap
+		objects  []client.Object
+		req      = ctrl.Request{NamespacedName: types.NamespacedName{Name: tenantName, Namespace: tenantNs}}
+		replLbls = map[string]string{
+			consts.ReplicationOriginLabel: consumerID,
+			consts.ReplicationStatusLabel: "true",
+		}
+
+		renewedAt = func(ago time.Duration) *metav1.MicroTime {
+			return &metav1.MicroTime{Time: time.Now().Add(-ago)}
+		}
+
+		quarantinedSince = func(ago time.Duration) {
+			tenant.Spec.TenantCondition = authv1beta1.TenantConditionCordoned
+			tenant.Annotations = map[string]string{
+				consts.TenantQuarantinedAnnotation: time.Now().Add(-ago).UTC().Format(time.RFC3339),
+			}
+		}
+
+		getTenant = func() *authv1beta1.Tenant {
+			var t authv1beta1.Tenant
+			Expect(fakeClient.Get(ctx, req.NamespacedName, &t)).To(Succeed())
+			return &t
+		}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		objects = nil
+
+		tenant = &authv1beta1.Tenant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: tenantName, Namespace: tenantNs,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-10 * deletionTTL)),
+			},
+			Spec: authv1beta1.TenantSpec{
+				ClusterID:       liqov1beta1.ClusterID(consumerID),
+				TenantCondition: authv1beta1.TenantConditionActive,
+			},
+			Status: authv1beta1.TenantStatus{TenantNamespace: tenantNs},
+		}
+
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: consts.ConsumerHeartbeatLeaseName, Namespace: tenantNs,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-10 * deletionTTL)),
+			},
+			Spec: coordinationv1.LeaseSpec{LeaseDurationSeconds: ptr.To[int32](120), RenewTime: renewedAt(30 * time.Second)},
+		}
+
+		nsmap = &offloadingv1beta1.NamespaceMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "nsmap", Namespace: tenantNs, Labels: replLbls,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-10 * deletionTTL)),
+			},
+			Status: offloadingv1beta1.NamespaceMapStatus{
+				CurrentMapping: map[string]offloadingv1beta1.RemoteNamespaceStatus{
+					"foo": {RemoteNamespace: remoteNs, Phase: offloadingv1beta1.MappingAccepted},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		objects = append(objects, tenant, nsmap)
+		if lease != nil {
+			objects = append(objects, lease)
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objects...).Build()
+		reconciler = NewTenantGCReconciler(fakeClient, fakeClient, scheme.Scheme, record.NewFakeRecorder(100), quarantineTTL, deletionTTL, false)
+	})
+
+	When("the consumer cluster is active", func() {
+		JustBeforeEach(func() { result, err = reconciler.Reconcile(ctx, req) })
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should not quarantine the tenant", func() {
+			Expect(getTenant().Spec.TenantCondition).To(Equal(authv1beta1.TenantConditionActive))
+			Expect(getTenant().Annotations).ToNot(HaveKey(consts.TenantQuarantinedAnnotation))
+		})
+		It("should requeue to check the heartbeat again", func() {
+			Expect(result.RequeueAfter).To(Equal(heartbeatCheckInterval))
+		})
+
+		When("the tenant had been previously quarantined", func() {
+			BeforeEach(func() { quarantinedSince(time.Minute) })
+
+			It("should restore the tenant", func() {
+				Expect(getTenant().Spec.TenantCondition).To(Equal(authv1beta1.TenantConditionActive))
+				Expect(getTenant().Annotations).ToNot(HaveKey(consts.TenantQuarantinedAnnotation))
+			})
+		})
+	})
+
+	When("the consumer cluster does not renew the heartbeat lease", func() {
+		BeforeEach(func() { lease = nil })
+		JustBeforeEach(func() { result, err = reconciler.Reconcile(ctx, req) })
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should not quarantine the tenant, as the loss of connectivity cannot be confirmed", func() {
+			Expect(getTenant().Spec.TenantCondition).To(Equal(authv1beta1.TenantConditionActive))
+			Expect(getTenant().Annotations).ToNot(HaveKey(consts.TenantQuarantinedAnnotation))
+		})
+	})
+
+	When("the heartbeat lease expired, but the API server of the consumer cluster is reachable", func() {
+		BeforeEach(func() {
+			lease.Spec.RenewTime = renewedAt(2 * quarantineTTL)
+			objects = []client.Object{&liqov1beta1.ForeignCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: consumerID, Labels: map[string]string{consts.RemoteClusterID: consumerID}},
+				Spec:       liqov1beta1.ForeignClusterSpec{ClusterID: consumerID},
+				Status: liqov1beta1.ForeignClusterStatus{Conditions: []liqov1beta1.Condition{{
+					Type: liqov1beta1.APIServerStatusCondition, Status: liqov1beta1.ConditionStatusEstablished,
+				}}},
+			}}
+		})
+		JustBeforeEach(func() { result, err = reconciler.Reconcile(ctx, req) })
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should not quarantine the tenant", func() {
+			Expect(getTenant().Spec.TenantCondition).To(Equal(authv1beta1.TenantConditionActive))
+			Expect(getTenant().Annotations).ToNot(HaveKey(consts.TenantQuarantinedAnnotation))
+		})
+	})
+
+	When("the consumer cluster is unreachable", func() {
+		BeforeEach(func() { lease.Spec.RenewTime = renewedAt(2 * quarantineTTL) })
+
+		Context("the tenant is not yet quarantined", func() {
+			JustBeforeEach(func() { result, err = reconciler.Reconcile(ctx, req) })
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("should quarantine the tenant", func() {
+				Expect(getTenant().Spec.TenantCondition).To(Equal(authv1beta1.TenantConditionCordoned))
+				Expect(getTenant().Annotations).To(HaveKey(consts.TenantQuarantinedAnnotation))
+			})
+			It("should requeue after the deletion TTL", func() { Expect(result.RequeueAfter).To(Equal(deletionTTL)) })
+		})
+
+		Context("the tenant has been drained by the administrator", func() {
+			BeforeEach(func() { tenant.Spec.TenantCondition = authv1beta1.TenantConditionDrained })
+			JustBeforeEach(func() { result, err = reconciler.Reconcile(ctx, req) })
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("should not modify the tenant", func() {
+				Expect(getTenant().Spec.TenantCondition).To(Equal(authv1beta1.TenantConditionDrained))
+				Expect(getTenant().Annotations).ToNot(HaveKey(consts.TenantQuarantinedAnnotation))
+			})
+		})
+
+		Context("the tenant has been quarantined less than the deletion TTL ago", func() {
+			BeforeEach(func() { quarantinedSince(time.Hour) })
+			JustBeforeEach(func() { result, err = reconciler.Reconcile(ctx, req) })
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("should not delete the NamespaceMap", func() {
+				Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(nsmap), &offloadingv1beta1.NamespaceMap{})).To(Succeed())
+			})
+			It("should requeue once the deletion TTL is elapsed", func() {
+				Expect(result.RequeueAfter).To(BeNumerically("~", deletionTTL-time.Hour, time.Minute))
+			})
+		})
+
+		Context("the tenant has been quarantined more than the deletion TTL ago", func() {
+			BeforeEach(func() { quarantinedSince(2 * deletionTTL) })
+
+			When("the dry-run mode is enabled", func() {
+				JustBeforeEach(func() {
+					reconciler.DryRun = true
+					result, err = reconciler.Reconcile(ctx, req)
+				})
+
+				It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+				It("should not delete the NamespaceMap", func() {
+					Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(nsmap), &offloadingv1beta1.NamespaceMap{})).To(Succeed())
+				})
+			})
+
+			When("the dry-run mode is disabled", func() {
+				JustBeforeEach(func() { result, err = reconciler.Reconcile(ctx, req) })
+
+				It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+				It("should delete the NamespaceMap", func() {
+					err := fakeClient.Get(ctx, client.ObjectKeyFromObject(nsmap), &offloadingv1beta1.NamespaceMap{})
+					Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+		})
+	})
+})
@@ -176,5 +176,9 @@ func allConditionsAccepted(rs *authv1beta1.ResourceSlice) bool {
 	resourcesCond := authentication.GetCondition(rs, authv1beta1.ResourceSliceConditionTypeResources)
 	resourcesAccepted := resourcesCond != nil && resourcesCond.Status == authv1beta1.ResourceSliceConditionAccepted
 
-	return authAccepted && resourcesAccepted
+	// The virtual node of a ResourceSlice whose lease is expired is drained and removed, hence it must not be recreated.
+	leaseCond := authentication.GetCondition(rs, authv1beta1.ResourceSliceConditionTypeLease)
+	leaseExpired := leaseCond != nil && leaseCond.Status == authv1beta1.ResourceSliceConditionExpired
+
+	return authAccepted && resourcesAccepted && !leaseExpired
 }
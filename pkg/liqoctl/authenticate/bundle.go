@@ -0,0 +1,228 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authenticate
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+)
+
+// BundleVersion is the version of the format of the authentication bundles.
+const BundleVersion = "v1"
+
+// RemoteKeyAnnotation is the annotation of the tenant namespace of the provider cluster, in the consumer cluster,
+// storing the (base64 encoded) public key which signed the challenge, and which is required to sign the response.
+const RemoteKeyAnnotation = "liqo.io/out-of-band-remote-key"
+
+// BundleKind is the kind of the content of an authentication bundle.
+type BundleKind string
+
+const (
+	// BundleKindChallenge is the kind of the bundle carrying the nonce generated by the provider cluster.
+	BundleKindChallenge BundleKind = "AuthenticationChallenge"
+	// BundleKindRequest is the kind of the bundle carrying the Tenant generated by the consumer cluster.
+	BundleKindRequest BundleKind = "AuthenticationRequest"
+	// BundleKindResponse is the kind of the bundle carrying the Identity generated by the provider cluster.
+	BundleKindResponse BundleKind = "AuthenticationResponse"
+)
+
+// Bundle is a signed, and optionally encrypted, envelope carrying a step of the authentication between
+// two clusters which cannot reach each other, to be exchanged as a file.
+type Bundle struct {
+	Version string     `json:"version"`
+	Kind    BundleKind `json:"kind"`
+	// ClusterID is the ID of the cluster which generated the bundle.
+	ClusterID liqov1beta1.ClusterID `json:"clusterID"`
+	// PublicKey is the Ed25519 public key of the cluster which generated the bundle.
+	PublicKey []byte `json:"publicKey"`
+	// Signature is the signature of the (plaintext) payload, performed with the key of the cluster.
+	Signature []byte `json:"signature"`
+	// Encryption contains the parameters to decrypt the payload, if encrypted.
+	Encryption *BundleEncryption `json:"encryption,omitempty"`
+	// Payload is the JSON-encoded content of the bundle, possibly encrypted.
+	Payload []byte `json:"payload"`
+}
+
+// BundleEncryption contains the parameters of the encryption of the payload of a bundle,
+// performed with AES-GCM and a key derived from a passphrase through scrypt.
+type BundleEncryption struct {
+	Salt  []byte `json:"salt"`
+	Nonce []byte `json:"nonce"`
+}
+
+// Challenge is the content of the bundle generated by the provider cluster to start the authentication.
+type Challenge struct {
+	// TenantNamespace is the tenant namespace of the consumer cluster in the provider cluster.
+	TenantNamespace string `json:"tenantNamespace"`
+	// Nonce is the nonce to be signed by the consumer cluster.
+	Nonce []byte `json:"nonce"`
+}
+
+// Request is the content of the bundle generated by the consumer cluster in response to a challenge.
+type Request struct {
+	// TenantNamespace is the tenant namespace of the provider cluster in the consumer cluster.
+	TenantNamespace string `json:"tenantNamespace"`
+	// Tenant is the Tenant to be applied in the provider cluster, carrying the signed nonce and the CSR.
+	Tenant *authv1beta1.Tenant `json:"tenant"`
+}
+
+// Response is the content of the bundle generated by the provider cluster to complete the authentication.
+type Response struct {
+	// Identity is the Identity to be applied in the consumer cluster, carrying the resulting AuthParams.
+	Identity *authv1beta1.Identity `json:"identity"`
+}
+
+// scrypt parameters to derive the encryption key from the passphrase.
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	saltLen      = 16
+)
+
+// SealBundle returns a new bundle of the given kind, carrying the given content signed with the key of the cluster.
+// If a passphrase is provided, the content is also encrypted.
+func SealBundle(kind BundleKind, clusterID liqov1beta1.ClusterID, key ed25519.PrivateKey,
+	content interface{}, passphrase []byte) ([]byte, error) {
+	payload, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the bundle content: %w", err)
+	}
+
+	bundle := Bundle{
+		Version:   BundleVersion,
+		Kind:      kind,
+		ClusterID: clusterID,
+		PublicKey: key.Public().(ed25519.PublicKey),
+	}
+	bundle.Signature = ed25519.Sign(key, bundle.signedMessage(payload))
+
+	if len(passphrase) > 0 {
+		bundle.Encryption = &BundleEncryption{Salt: make([]byte, saltLen)}
+		if _, err := rand.Read(bundle.Encryption.Salt); err != nil {
+			return nil, fmt.Errorf("failed to generate the encryption salt: %w", err)
+		}
+
+		aead, err := newAEAD(passphrase, bundle.Encryption.Salt)
+		if err != nil {
+			return nil, err
+		}
+
+		bundle.Encryption.Nonce = make([]byte, aead.NonceSize())
+		if _, err := rand.Read(bundle.Encryption.Nonce); err != nil {
+			return nil, fmt.Errorf("failed to generate the encryption nonce: %w", err)
+		}
+		payload = aead.Seal(nil, bundle.Encryption.Nonce, payload, bundle.additionalData())
+	}
+
+	bundle.Payload = payload
+	return json.MarshalIndent(&bundle, "", "  ")
+}
+
+// OpenBundle decodes a bundle of the given kind, decrypting it with the given passphrase if encrypted,
+// verifies its signature and decodes its payload into the given content.
+func OpenBundle(data []byte, kind BundleKind, passphrase []byte, content interface{}) (*Bundle, error) {
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to decode the bundle: %w", err)
+	}
+
+	switch {
+	case bundle.Version != BundleVersion:
+		return nil, fmt.Errorf("unsupported bundle version %q", bundle.Version)
+	case bundle.Kind != kind:
+		return nil, fmt.Errorf("unexpected bundle kind %q, expected %q", bundle.Kind, kind)
+	case len(bundle.PublicKey) != ed25519.PublicKeySize:
+		return nil, fmt.Errorf("invalid bundle public key")
+	}
+
+	payload := bundle.Payload
+	if bundle.Encryption != nil {
+		if len(passphrase) == 0 {
+			return nil, errors.New("the bundle is encrypted, but no passphrase has been provided")
+		}
+
+		aead, err := newAEAD(passphrase, bundle.Encryption.Salt)
+		if err != nil {
+			return nil, err
+		}
+		if len(bundle.Encryption.Nonce) != aead.NonceSize() {
+			return nil, errors.New("invalid bundle encryption nonce")
+		}
+
+		if payload, err = aead.Open(nil, bundle.Encryption.Nonce, payload, bundle.additionalData()); err != nil {
+			return nil, errors.New("failed to decrypt the bundle: wrong passphrase or corrupted bundle")
+		}
+	}
+
+	if !ed25519.Verify(bundle.PublicKey, bundle.signedMessage(payload), bundle.Signature) {
+		return nil, errors.New("invalid bundle signature")
+	}
+
+	if err := json.Unmarshal(payload, content); err != nil {
+		return nil, fmt.Errorf("failed to decode the bundle content: %w", err)
+	}
+	return &bundle, nil
+}
+
+// Fingerprint returns the fingerprint of the public key of the cluster which generated the bundle,
+// to be compared out-of-band with the one printed when it has been generated.
+func (b *Bundle) Fingerprint() string {
+	return KeyFingerprint(b.PublicKey)
+}
+
+// KeyFingerprint returns the fingerprint of the given public key.
+func KeyFingerprint(publicKey []byte) string {
+	hash := sha256.Sum256(publicKey)
+	return "SHA256:" + hex.EncodeToString(hash[:])
+}
+
+// signedMessage returns the message signed by the cluster which generated the bundle,
+// which binds the payload to the kind of the bundle and to the identity of the cluster.
+func (b *Bundle) signedMessage(payload []byte) []byte {
+	return append(b.additionalData(), payload...)
+}
+
+// additionalData returns the metadata of the bundle authenticated by the encryption alongside the payload.
+func (b *Bundle) additionalData() []byte {
+	return []byte(fmt.Sprintf("%s\n%s\n%s\n", b.Version, b.Kind, b.ClusterID))
+}
+
+// newAEAD returns the AES-GCM cipher with the key derived from the given passphrase and salt.
+func newAEAD(passphrase, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive the encryption key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize the cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
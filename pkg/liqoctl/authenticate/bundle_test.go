@@ -0,0 +1,190 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authenticate
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	"github.com/liqotech/liqo/pkg/liqoctl/factory"
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
+	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
+)
+
+var _ = Describe("Bundle", func() {
+	var (
+		key       ed25519.PrivateKey
+		challenge Challenge
+	)
+
+	BeforeEach(func() {
+		var err error
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		challenge = Challenge{TenantNamespace: "liqo-tenant-consumer", Nonce: []byte("nonce")}
+	})
+
+	DescribeTable("sealing and opening a bundle",
+		func(sealPassphrase, openPassphrase []byte, matchErr string) {
+			data, err := SealBundle(BundleKindChallenge, "provider", key, &challenge, sealPassphrase)
+			Expect(err).ToNot(HaveOccurred())
+
+			var opened Challenge
+			bundle, err := OpenBundle(data, BundleKindChallenge, openPassphrase, &opened)
+			if matchErr != "" {
+				Expect(err).To(MatchError(ContainSubstring(matchErr)))
+				return
+			}
+			Expect(err).ToNot(HaveOccurred())
+			Expect(opened).To(Equal(challenge))
+			Expect(bundle.ClusterID).To(Equal(liqov1beta1.ClusterID("provider")))
+			Expect(bundle.Fingerprint()).To(Equal(KeyFingerprint(key.Public().(ed25519.PublicKey))))
+		},
+		Entry("plaintext", nil, nil, ""),
+		Entry("encrypted", []byte("secret"), []byte("secret"), ""),
+		Entry("encrypted, wrong passphrase", []byte("secret"), []byte("wrong"), "wrong passphrase"),
+		Entry("encrypted, missing passphrase", []byte("secret"), nil, "no passphrase"),
+	)
+
+	It("should not leak the content of an encrypted bundle", func() {
+		data, err := SealBundle(BundleKindChallenge, "provider", key, &challenge, []byte("secret"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).ToNot(ContainSubstring(challenge.TenantNamespace))
+	})
+
+	It("should reject a bundle of a different kind", func() {
+		data, err := SealBundle(BundleKindChallenge, "provider", key, &challenge, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = OpenBundle(data, BundleKindRequest, nil, &Request{})
+		Expect(err).To(MatchError(ContainSubstring("unexpected bundle kind")))
+	})
+
+	DescribeTable("tampering with a bundle",
+		func(tamper func(bundle *Bundle)) {
+			data, err := SealBundle(BundleKindChallenge, "provider", key, &challenge, nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			var bundle Bundle
+			Expect(json.Unmarshal(data, &bundle)).To(Succeed())
+			tamper(&bundle)
+			data, err = json.Marshal(&bundle)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = OpenBundle(data, BundleKindChallenge, nil, &Challenge{})
+			Expect(err).To(MatchError(ContainSubstring("invalid bundle signature")))
+		},
+		Entry("payload", func(bundle *Bundle) {
+			bundle.Payload = []byte(`{"tenantNamespace":"other","nonce":"bm9uY2U="}`)
+		}),
+		Entry("cluster ID", func(bundle *Bundle) { bundle.ClusterID = "other" }),
+		Entry("public key", func(bundle *Bundle) {
+			public, _, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			bundle.PublicKey = public
+		}),
+	)
+})
+
+var _ = Describe("Bundle verification", func() {
+	var (
+		ctx      context.Context
+		consumer *Cluster
+		key      ed25519.PrivateKey
+		bundle   *Bundle
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		clientset := k8sfake.NewSimpleClientset()
+		consumer = &Cluster{
+			local:                 &factory.Factory{KubeClient: clientset, Printer: output.NewFakePrinter(GinkgoWriter)},
+			localNamespaceManager: tenantnamespace.NewManager(clientset, scheme.Scheme),
+			LocalClusterID:        "consumer",
+		}
+
+		var err error
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		bundle = &Bundle{ClusterID: "provider", PublicKey: key.Public().(ed25519.PublicKey)}
+	})
+
+	It("should accept a bundle matching the expected fingerprint", func() {
+		Expect(consumer.FingerprintVerifier(bundle.Fingerprint())(bundle)).To(Succeed())
+	})
+
+	It("should reject a bundle not matching the expected fingerprint", func() {
+		Expect(consumer.FingerprintVerifier("SHA256:0000")(bundle)).To(MatchError(ContainSubstring("does not match")))
+	})
+
+	It("should reject a response if no challenge has been imported", func() {
+		Expect(consumer.PinnedKeyVerifier(ctx)(bundle)).ToNot(Succeed())
+	})
+
+	When("the key of the provider has been pinned", func() {
+		BeforeEach(func() {
+			Expect(consumer.EnsureTenantNamespace(ctx, "provider")).To(Succeed())
+			Expect(consumer.PinRemoteKey(ctx, bundle.PublicKey)).To(Succeed())
+		})
+
+		It("should accept a response signed with the same key", func() {
+			Expect(consumer.PinnedKeyVerifier(ctx)(bundle)).To(Succeed())
+		})
+
+		It("should reject a response signed with a different key", func() {
+			rogue, _, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			bundle.PublicKey = rogue
+			Expect(consumer.PinnedKeyVerifier(ctx)(bundle)).To(MatchError(ContainSubstring("not been signed with the key")))
+		})
+	})
+})
+
+var _ = Describe("Out-of-band options validation", func() {
+	DescribeTable("ValidateOutOfBand",
+		func(options *Options, matchErr string) {
+			err := options.ValidateOutOfBand()
+			if matchErr == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(matchErr)))
+			}
+		},
+		Entry("export challenge", withClusterID(&Options{ExportChallenge: "c"}, "consumer"), ""),
+		Entry("export challenge without cluster ID", &Options{ExportChallenge: "c"}, "requires --remote-cluster-id"),
+		Entry("import challenge", &Options{ImportChallenge: "c", ExportRequest: "r"}, ""),
+		Entry("import challenge without export", &Options{ImportChallenge: "c"}, "must be set together"),
+		Entry("import request", &Options{ImportRequest: "r", ExportResponse: "s"}, ""),
+		Entry("import response", &Options{ImportResponse: "s"}, ""),
+		Entry("import response with fingerprint", &Options{ImportResponse: "s", ExpectedFingerprint: "SHA256:00"}, "not supported"),
+		Entry("mixed steps", &Options{ImportRequest: "r", ExportResponse: "s", ImportResponse: "s"}, "cannot be combined"),
+		Entry("cluster ID when importing", withClusterID(&Options{ImportResponse: "s"}, "consumer"), "only with --export-challenge"),
+		Entry("in-band", &Options{ImportResponse: "s", InBand: true}, "not supported"),
+	)
+})
+
+func withClusterID(options *Options, clusterID string) *Options {
+	utilruntime.Must(options.RemoteClusterID.Set(clusterID))
+	return options
+}
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/liqotech/liqo/pkg/liqoctl/factory"
+	argsutils "github.com/liqotech/liqo/pkg/utils/args"
 )
 
 // Options encapsulates the arguments of the authenticate command.
@@ -29,6 +30,18 @@ type Options struct {
 
 	InBand   bool
 	ProxyURL string
+
+	// Parameters of the out-of-band authentication, performed by exchanging bundles as files.
+	RemoteClusterID argsutils.ClusterIDFlags
+	ExportChallenge string
+	ImportChallenge string
+	ExportRequest   string
+	ImportRequest   string
+	ExportResponse  string
+	ImportResponse  string
+	PassphraseFile  string
+
+	ExpectedFingerprint string
 }
 
 // NewOptions returns a new Options struct.
@@ -43,6 +56,10 @@ func (o *Options) RunAuthenticate(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
 	defer cancel()
 
+	if o.OutOfBand() {
+		return o.runOutOfBand(ctx)
+	}
+
 	// Create and initialize cluster consumer.
 	consumer := NewCluster(o.LocalFactory)
 	if err := consumer.SetLocalClusterID(ctx); err != nil {
@@ -0,0 +1,380 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authenticate
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
+)
+
+// OutOfBand returns whether the authentication has to be performed out-of-band, exchanging bundles as files.
+func (o *Options) OutOfBand() bool {
+	return o.ExportChallenge != "" || o.ImportChallenge != "" || o.ExportRequest != "" ||
+		o.ImportRequest != "" || o.ExportResponse != "" || o.ImportResponse != ""
+}
+
+// ValidateOutOfBand checks that the out-of-band parameters describe exactly one step of the authentication.
+func (o *Options) ValidateOutOfBand() error {
+	if o.InBand || o.ProxyURL != "" {
+		return errors.New("--in-band and --proxy-url are not supported with the out-of-band authentication")
+	}
+
+	switch {
+	case o.ExportChallenge != "":
+		if o.ImportChallenge != "" || o.ExportRequest != "" || o.ImportRequest != "" || o.ExportResponse != "" || o.ImportResponse != "" {
+			return errors.New("--export-challenge cannot be combined with other bundle flags")
+		}
+		if o.RemoteClusterID.GetClusterID() == "" {
+			return errors.New("--export-challenge requires --remote-cluster-id to be set to the ID of the consumer cluster")
+		}
+		if o.ExpectedFingerprint != "" {
+			return errors.New("--expected-fingerprint is supported only when importing a bundle")
+		}
+	case o.ImportChallenge != "" || o.ExportRequest != "":
+		if o.ImportChallenge == "" || o.ExportRequest == "" {
+			return errors.New("--import-challenge and --export-request must be set together")
+		}
+		if o.ImportRequest != "" || o.ExportResponse != "" || o.ImportResponse != "" {
+			return errors.New("--import-challenge cannot be combined with other bundle flags")
+		}
+	case o.ImportRequest != "" || o.ExportResponse != "":
+		if o.ImportRequest == "" || o.ExportResponse == "" {
+			return errors.New("--import-request and --export-response must be set together")
+		}
+		if o.ImportResponse != "" {
+			return errors.New("--import-request cannot be combined with other bundle flags")
+		}
+	case o.ImportResponse != "":
+		if o.ExpectedFingerprint != "" {
+			return errors.New("--expected-fingerprint is not supported with --import-response, " +
+				"which is verified against the key that signed the challenge")
+		}
+	}
+
+	if o.ExportChallenge == "" && o.RemoteClusterID.GetClusterID() != "" {
+		return errors.New("--remote-cluster-id is supported only with --export-challenge")
+	}
+	return nil
+}
+
+// runOutOfBand performs the step of the out-of-band authentication selected by the options.
+// The authentication is split into four steps, each one involving only the local cluster:
+//  1. the provider exports a challenge, carrying the nonce to be signed by the consumer;
+//  2. the consumer imports the challenge and exports a request, carrying the Tenant with the signed nonce and the CSR;
+//  3. the provider imports the request, applies the Tenant and exports a response, carrying the Identity;
+//  4. the consumer imports the response and applies the Identity.
+func (o *Options) runOutOfBand(ctx context.Context) error {
+	if err := o.ValidateOutOfBand(); err != nil {
+		return err
+	}
+
+	passphrase, err := o.readPassphrase()
+	if err != nil {
+		return err
+	}
+
+	cluster := NewCluster(o.LocalFactory)
+	if err := cluster.SetLocalClusterID(ctx); err != nil {
+		return err
+	}
+
+	switch {
+	case o.ExportChallenge != "":
+		return o.exportChallenge(ctx, cluster, passphrase)
+	case o.ImportChallenge != "":
+		return o.exportRequest(ctx, cluster, passphrase)
+	case o.ImportRequest != "":
+		return o.exportResponse(ctx, cluster, passphrase)
+	default:
+		return o.importResponse(ctx, cluster, passphrase)
+	}
+}
+
+// exportChallenge generates the nonce for the consumer cluster, and exports it in a challenge bundle.
+func (o *Options) exportChallenge(ctx context.Context, provider *Cluster, passphrase []byte) error {
+	if err := provider.EnsureTenantNamespace(ctx, o.RemoteClusterID.GetClusterID()); err != nil {
+		return err
+	}
+
+	nonce, err := provider.EnsureNonce(ctx)
+	if err != nil {
+		return err
+	}
+
+	challenge := Challenge{TenantNamespace: provider.TenantNamespace, Nonce: nonce}
+	return provider.ExportBundle(ctx, o.ExportChallenge, BundleKindChallenge, &challenge, passphrase)
+}
+
+// exportRequest signs the nonce carried by the challenge bundle, and exports the resulting Tenant in a request bundle.
+func (o *Options) exportRequest(ctx context.Context, consumer *Cluster, passphrase []byte) error {
+	var challenge Challenge
+	bundle, err := consumer.ImportBundle(o.ImportChallenge, BundleKindChallenge, passphrase,
+		consumer.FingerprintVerifier(o.ExpectedFingerprint), &challenge)
+	if err != nil {
+		return err
+	}
+
+	if err := consumer.EnsureTenantNamespace(ctx, bundle.ClusterID); err != nil {
+		return err
+	}
+
+	// The key of the provider is pinned, so that only a response signed by the same cluster is accepted.
+	if err := consumer.PinRemoteKey(ctx, bundle.PublicKey); err != nil {
+		return err
+	}
+
+	signedNonce, err := consumer.EnsureSignedNonce(ctx, challenge.Nonce)
+	if err != nil {
+		return err
+	}
+
+	tenant, err := consumer.GenerateTenant(ctx, signedNonce, challenge.TenantNamespace, &o.ProxyURL)
+	if err != nil {
+		return err
+	}
+
+	request := Request{TenantNamespace: consumer.TenantNamespace, Tenant: tenant}
+	return consumer.ExportBundle(ctx, o.ExportRequest, BundleKindRequest, &request, passphrase)
+}
+
+// exportResponse applies the Tenant carried by the request bundle, and exports the resulting Identity in a response bundle.
+func (o *Options) exportResponse(ctx context.Context, provider *Cluster, passphrase []byte) error {
+	var request Request
+	bundle, err := provider.ImportBundle(o.ImportRequest, BundleKindRequest, passphrase,
+		provider.FingerprintVerifier(o.ExpectedFingerprint), &request)
+	if err != nil {
+		return err
+	}
+
+	// The Tenant must have been generated by the same cluster that signed the bundle.
+	switch {
+	case request.Tenant == nil:
+		return errors.New("the request bundle does not contain a tenant")
+	case request.Tenant.Spec.ClusterID != bundle.ClusterID:
+		return fmt.Errorf("the tenant refers to cluster %q, but the bundle has been generated by cluster %q",
+			request.Tenant.Spec.ClusterID, bundle.ClusterID)
+	case !bytes.Equal(request.Tenant.Spec.PublicKey, bundle.PublicKey):
+		return errors.New("the public key of the tenant does not match the one that signed the bundle")
+	}
+
+	if err := provider.EnsureTenantNamespace(ctx, bundle.ClusterID); err != nil {
+		return err
+	}
+	if request.Tenant.Namespace != provider.TenantNamespace {
+		return fmt.Errorf("the tenant targets namespace %q, while the tenant namespace of cluster %q is %q",
+			request.Tenant.Namespace, bundle.ClusterID, provider.TenantNamespace)
+	}
+
+	if err := provider.EnsureTenant(ctx, request.Tenant); err != nil {
+		return err
+	}
+
+	identity, err := provider.GenerateIdentity(ctx, request.TenantNamespace)
+	if err != nil {
+		return err
+	}
+
+	response := Response{Identity: identity}
+	return provider.ExportBundle(ctx, o.ExportResponse, BundleKindResponse, &response, passphrase)
+}
+
+// importResponse applies the Identity carried by the response bundle.
+func (o *Options) importResponse(ctx context.Context, consumer *Cluster, passphrase []byte) error {
+	var response Response
+	bundle, err := consumer.ImportBundle(o.ImportResponse, BundleKindResponse, passphrase, consumer.PinnedKeyVerifier(ctx), &response)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case response.Identity == nil:
+		return errors.New("the response bundle does not contain an identity")
+	case response.Identity.Spec.ClusterID != bundle.ClusterID:
+		return fmt.Errorf("the identity refers to cluster %q, but the bundle has been generated by cluster %q",
+			response.Identity.Spec.ClusterID, bundle.ClusterID)
+	}
+
+	if err := consumer.EnsureTenantNamespace(ctx, bundle.ClusterID); err != nil {
+		return err
+	}
+	if response.Identity.Namespace != consumer.TenantNamespace {
+		return fmt.Errorf("the identity targets namespace %q, while the tenant namespace of cluster %q is %q",
+			response.Identity.Namespace, bundle.ClusterID, consumer.TenantNamespace)
+	}
+
+	return consumer.EnsureIdentity(ctx, response.Identity)
+}
+
+// readPassphrase returns the passphrase to encrypt and decrypt the bundles, if configured.
+func (o *Options) readPassphrase() ([]byte, error) {
+	if o.PassphraseFile == "" {
+		return nil, nil
+	}
+
+	passphrase, err := os.ReadFile(filepath.Clean(o.PassphraseFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the bundle passphrase: %w", err)
+	}
+
+	passphrase = bytes.TrimRight(passphrase, "\r\n")
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("the bundle passphrase file %q is empty", o.PassphraseFile)
+	}
+	return passphrase, nil
+}
+
+// ExportBundle seals the given content in a bundle signed with the key of the local cluster, and writes it to the given file.
+func (c *Cluster) ExportBundle(ctx context.Context, path string, kind BundleKind, content interface{}, passphrase []byte) error {
+	s := c.local.Printer.StartSpinner(fmt.Sprintf("Exporting %s bundle", kind))
+
+	privateKey, _, err := authentication.GetClusterKeys(ctx, c.local.CRClient, c.local.LiqoNamespace)
+	if err != nil {
+		s.Fail(fmt.Sprintf("Unable to retrieve the cluster keys: %v", output.PrettyErr(err)))
+		return err
+	}
+
+	data, err := SealBundle(kind, c.LocalClusterID, privateKey, content, passphrase)
+	if err != nil {
+		s.Fail(fmt.Sprintf("Unable to generate the %s bundle: %v", kind, output.PrettyErr(err)))
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Clean(path), data, 0o600); err != nil {
+		s.Fail(fmt.Sprintf("Unable to write the %s bundle: %v", kind, output.PrettyErr(err)))
+		return err
+	}
+	s.Success(fmt.Sprintf("%s bundle written to %q", kind, path))
+
+	c.local.Printer.Info.Printfln("Bundle fingerprint: %s. Share it with the remote cluster administrator "+
+		"through a trusted channel to allow them to verify the origin of the bundle", KeyFingerprint(privateKey.Public().(ed25519.PublicKey)))
+	return nil
+}
+
+// BundleVerifier is a function verifying that a bundle has been generated by the expected cluster.
+type BundleVerifier func(bundle *Bundle) error
+
+// ImportBundle reads the bundle of the given kind from the given file, and decodes its content after verifying its signature.
+// The bundle is rejected unless the verifier confirms it has been generated by the expected cluster.
+func (c *Cluster) ImportBundle(path string, kind BundleKind, passphrase []byte, verify BundleVerifier,
+	content interface{}) (*Bundle, error) {
+	s := c.local.Printer.StartSpinner(fmt.Sprintf("Importing %s bundle", kind))
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		s.Fail(fmt.Sprintf("Unable to read the %s bundle: %v", kind, output.PrettyErr(err)))
+		return nil, err
+	}
+
+	bundle, err := OpenBundle(data, kind, passphrase, content)
+	if err != nil {
+		s.Fail(fmt.Sprintf("Unable to import the %s bundle: %v", kind, output.PrettyErr(err)))
+		return nil, err
+	}
+
+	if bundle.ClusterID == "" || bundle.ClusterID == c.LocalClusterID {
+		err = fmt.Errorf("the bundle has been generated by an invalid cluster %q", bundle.ClusterID)
+		s.Fail(err.Error())
+		return nil, err
+	}
+	s.Success(fmt.Sprintf("%s bundle generated by cluster %q correctly decoded", kind, bundle.ClusterID))
+
+	if err := verify(bundle); err != nil {
+		c.local.Printer.Error.Printfln("Unable to verify the origin of the %s bundle: %v", kind, output.PrettyErr(err))
+		return nil, err
+	}
+	c.local.Printer.Success.Printfln("Origin of the %s bundle correctly verified", kind)
+	return bundle, nil
+}
+
+// FingerprintVerifier returns a BundleVerifier requiring the bundle to match the given fingerprint. If it is not set,
+// the user is asked to confirm that the fingerprint of the bundle matches the one reported by the remote administrator.
+func (c *Cluster) FingerprintVerifier(expectedFingerprint string) BundleVerifier {
+	return func(bundle *Bundle) error {
+		if expectedFingerprint != "" {
+			if expectedFingerprint != bundle.Fingerprint() {
+				return fmt.Errorf("the bundle fingerprint %s does not match the expected one", bundle.Fingerprint())
+			}
+			return nil
+		}
+
+		c.local.Printer.Info.Printfln("Bundle fingerprint: %s", bundle.Fingerprint())
+		if err := c.local.Printer.AskConfirmQuestion("Does the fingerprint match the one reported by the remote cluster administrator?"); err != nil {
+			return errors.New("the bundle fingerprint has not been confirmed: set --expected-fingerprint to verify it non-interactively")
+		}
+		return nil
+	}
+}
+
+// PinnedKeyVerifier returns a BundleVerifier requiring the bundle to be signed with the key of the remote cluster
+// pinned when importing the challenge.
+func (c *Cluster) PinnedKeyVerifier(ctx context.Context) BundleVerifier {
+	return func(bundle *Bundle) error {
+		namespace, err := c.localNamespaceManager.GetNamespace(ctx, bundle.ClusterID)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve the tenant namespace of cluster %q, make sure the challenge has been imported: %w",
+				bundle.ClusterID, err)
+		}
+
+		pinned, found := namespace.Annotations[RemoteKeyAnnotation]
+		if !found {
+			return fmt.Errorf("no challenge has been imported from cluster %q", bundle.ClusterID)
+		}
+		if pinned != base64.StdEncoding.EncodeToString(bundle.PublicKey) {
+			return fmt.Errorf("the bundle has not been signed with the key of the cluster %q which generated the challenge", bundle.ClusterID)
+		}
+		return nil
+	}
+}
+
+// PinRemoteKey records the public key of the remote cluster in its tenant namespace, to verify the following bundles.
+func (c *Cluster) PinRemoteKey(ctx context.Context, publicKey []byte) error {
+	s := c.local.Printer.StartSpinner("Pinning the key of the remote cluster")
+
+	namespace, err := c.localNamespaceManager.GetNamespace(ctx, c.RemoteClusterID)
+	if err != nil {
+		s.Fail(fmt.Sprintf("Unable to retrieve the tenant namespace: %v", output.PrettyErr(err)))
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{
+		"annotations": map[string]string{RemoteKeyAnnotation: base64.StdEncoding.EncodeToString(publicKey)},
+	}})
+	if err != nil {
+		s.Fail(fmt.Sprintf("Unable to pin the key of the remote cluster: %v", output.PrettyErr(err)))
+		return err
+	}
+
+	if _, err := c.local.KubeClient.CoreV1().Namespaces().Patch(ctx, namespace.Name, types.MergePatchType,
+		patch, metav1.PatchOptions{}); err != nil {
+		s.Fail(fmt.Sprintf("Unable to pin the key of the remote cluster: %v", output.PrettyErr(err)))
+		return err
+	}
+
+	s.Success("Key of the remote cluster correctly pinned")
+	return nil
+}
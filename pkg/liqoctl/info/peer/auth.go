@@ -19,8 +19,11 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
@@ -44,11 +47,12 @@ const (
 
 // ResourceSliceStatus contains info about a ResourceSliceStatus CR.
 type ResourceSliceStatus struct {
-	Name      string              `json:"name"`
-	Action    ResourceSliceAction `json:"action"`
-	Accepted  bool                `json:"accepted"`
-	Alerts    []string            `json:"alerts,omitempty"`
-	Resources corev1.ResourceList `json:"resources"`
+	Name            string              `json:"name"`
+	Action          ResourceSliceAction `json:"action"`
+	Accepted        bool                `json:"accepted"`
+	Alerts          []string            `json:"alerts,omitempty"`
+	Resources       corev1.ResourceList `json:"resources"`
+	LeaseExpiration *metav1.Time        `json:"leaseExpiration,omitempty"`
 }
 
 // Auth contains some info about the current status of the authentication module.
@@ -124,6 +128,9 @@ func (ac *AuthChecker) FormatForClusterID(clusterID liqov1beta1.ClusterID, optio
 					main.AddEntryWarning("Alerts", slice.Alerts...)
 				}
 				currSliceSection.AddEntry("Action", string(slice.Action))
+				if slice.LeaseExpiration != nil {
+					currSliceSection.AddEntry("Lease expiration", formatLeaseExpiration(slice.LeaseExpiration.Time))
+				}
 
 				resourcesSection := currSliceSection.AddSection("Resources")
 				for resource, quantity := range slice.Resources {
@@ -180,8 +187,12 @@ func (ac *AuthChecker) collectResourceSlices(resourceSlices []authv1beta1.Resour
 		accepted := len(resSlice.Status.Conditions) > 0
 		alerts := []string{}
 		for _, condition := range resSlice.Status.Conditions {
-			isConditionAccepted := condition.Status == authv1beta1.ResourceSliceConditionAccepted
-			if !isConditionAccepted {
+			switch {
+			case condition.Status == authv1beta1.ResourceSliceConditionAccepted:
+			case condition.Status == authv1beta1.ResourceSliceConditionExpiring:
+				// The resources are still granted, although the lease is about to expire.
+				alerts = append(alerts, condition.Message)
+			default:
 				accepted = false
 				alerts = append(alerts, condition.Message)
 			}
@@ -194,15 +205,25 @@ func (ac *AuthChecker) collectResourceSlices(resourceSlices []authv1beta1.Resour
 		}
 
 		authStatus.ResourceSlices = append(authStatus.ResourceSlices, ResourceSliceStatus{
-			Name:      resSlice.Name,
-			Action:    action,
-			Accepted:  accepted,
-			Alerts:    alerts,
-			Resources: resSlice.Status.Resources,
+			Name:            resSlice.Name,
+			Action:          action,
+			Accepted:        accepted,
+			Alerts:          alerts,
+			Resources:       resSlice.Status.Resources,
+			LeaseExpiration: resSlice.Status.LeaseExpiration,
 		})
 	}
 }
 
+// formatLeaseExpiration returns the expiration time of a lease, along with the time left.
+func formatLeaseExpiration(expiration time.Time) string {
+	remaining := time.Until(expiration)
+	if remaining <= 0 {
+		return fmt.Sprintf("%s (expired)", expiration.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s (in %s)", expiration.Format(time.RFC3339), duration.HumanDuration(remaining))
+}
+
 func (ac *AuthChecker) collectAPIAddress(ctx context.Context, cl client.Client, clusterID liqov1beta1.ClusterID, authStatus *Auth) error {
 	identity, err := getters.GetControlPlaneIdentityByClusterID(ctx, cl, clusterID)
 	if err != nil {
@@ -20,12 +20,14 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/pterm/pterm"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -151,6 +153,24 @@ var _ = Describe("AuthChecker tests", func() {
 				Expect(len(authStatus.ResourceSlices)).To(Equal(1), "One single resource slice expected")
 				Expect(authStatus.ResourceSlices[0].Action).To(Equal(ProvidingAction),
 					"Unexpected action: ResourceSlice has replication status label set to true")
+
+				By("Checking that an expiring lease raises an alert without denying the ResourceSlice")
+				rs = testutil.FakeResourceSlice("rs01", liqov1beta1.ClusterID(localClusterID), liqov1beta1.ClusterID(remoteClusterID),
+					authv1beta1.ResourceSliceConditionAccepted, expectedResourceList)
+				expectedAlert = "The lease is expiring"
+				rs.Status.LeaseExpiration = &metav1.Time{Time: time.Now().Add(time.Hour)}
+				rs.Status.Conditions = append(rs.Status.Conditions, authv1beta1.ResourceSliceCondition{
+					Type:    authv1beta1.ResourceSliceConditionTypeLease,
+					Status:  authv1beta1.ResourceSliceConditionExpiring,
+					Message: expectedAlert,
+				})
+
+				authStatus = Auth{}
+				ac.collectResourceSlices([]authv1beta1.ResourceSlice{*rs}, &authStatus)
+				Expect(len(authStatus.ResourceSlices)).To(Equal(1), "One single resource slice expected")
+				Expect(authStatus.ResourceSlices[0].Accepted).To(BeTrue(), "Expiring lease, expected resource slice to be accepted")
+				Expect(authStatus.ResourceSlices[0].Alerts).To(ContainElements(expectedAlert), "Alert not found in ResourceSlice alerts")
+				Expect(authStatus.ResourceSlices[0].LeaseExpiration).To(Equal(rs.Status.LeaseExpiration), "Unexpected lease expiration")
 			})
 		})
 
@@ -18,9 +18,12 @@ const (
 	liqoStorageNamespace = "liqo-storage"
 	resticRegistry       = "restic-registry"
 	resticPort           = 8000
+	rsyncPort            = 873
 
 	// DefaultResticServerImage is the default image used for the restic server.
 	DefaultResticServerImage = "restic/rest-server:0.11.0"
 	// DefaultResticImage is the default image used for the restic client.
 	DefaultResticImage = "restic/restic:0.14.0"
+	// DefaultRsyncImage is the default image used for the rsync server and client.
+	DefaultRsyncImage = "instrumentisto/rsync-ssh:alpine"
 )
@@ -0,0 +1,175 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package move
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// volumeSnapshotGVK is the GroupVersionKind of the CSI VolumeSnapshots.
+var volumeSnapshotGVK = schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshot"}
+
+// csiDataMover transfers the data through a CSI VolumeSnapshot of the real volume, which is then cloned into
+// the new one. Since the real PVCs are hosted in the liqo-storage namespace, it requires both the origin and
+// the target nodes to be served by the same CSI backend of the local cluster: moving volumes across clusters,
+// even if sharing the same CSI backend, is not supported.
+type csiDataMover struct {
+	*Options
+
+	snapshots []string
+}
+
+var _ DataMover = &csiDataMover{}
+
+// Setup checks that all volumes can be moved through CSI snapshots.
+func (m *csiDataMover) Setup(_ context.Context, volumes []*Volume, targetNode *corev1.Node) error {
+	nodes := []*corev1.Node{targetNode}
+	for _, volume := range volumes {
+		nodes = append(nodes, volume.OriginNode)
+	}
+
+	for _, node := range nodes {
+		if !isLocalNode(node) {
+			return fmt.Errorf("the %s data mover requires both the origin and the target nodes to be served by "+
+				"the CSI backend of the local cluster, but node %q is virtual", CSIDataMover, node.Name)
+		}
+	}
+	return nil
+}
+
+// Snapshot creates a VolumeSnapshot of the real PVC backing the given volume, and waits for it to be ready.
+func (m *csiDataMover) Snapshot(ctx context.Context, volume *Volume) error {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	snapshot.SetName(volumeSnapshotName(volume))
+	snapshot.SetNamespace(liqoStorageNamespace)
+
+	// The real PVCs are named after the UID of the corresponding virtual PVC.
+	spec := map[string]interface{}{
+		"source": map[string]interface{}{"persistentVolumeClaimName": string(volume.PVC.GetUID())},
+	}
+	if m.VolumeSnapshotClass != "" {
+		spec["volumeSnapshotClassName"] = m.VolumeSnapshotClass
+	}
+	if err := unstructured.SetNestedMap(snapshot.Object, spec, "spec"); err != nil {
+		return err
+	}
+
+	if err := m.CRClient.Create(ctx, snapshot); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	m.snapshots = append(m.snapshots, snapshot.GetName())
+
+	return wait.PollUntilContextTimeout(ctx, 5*time.Second, 5*time.Minute, true, func(ctx context.Context) (bool, error) {
+		if err := m.CRClient.Get(ctx, client.ObjectKeyFromObject(snapshot), snapshot); err != nil {
+			return false, err
+		}
+		if message, found, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message"); found {
+			return false, fmt.Errorf("failed to create VolumeSnapshot %q: %s", snapshot.GetName(), message)
+		}
+		ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse")
+		return ready, nil
+	})
+}
+
+// ForgePvc configures the new PVC to be populated from the VolumeSnapshot of the given volume.
+// The data source is propagated by the storage provisioner to the corresponding real PVC.
+func (m *csiDataMover) ForgePvc(volume *Volume, pvc *corev1.PersistentVolumeClaim) {
+	pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+		APIGroup: ptr.To(volumeSnapshotGVK.Group),
+		Kind:     volumeSnapshotGVK.Kind,
+		Name:     volumeSnapshotName(volume),
+	}
+}
+
+// Restore binds the new PVC to the given node, hence triggering its provisioning as a clone of the VolumeSnapshot.
+func (m *csiDataMover) Restore(ctx context.Context, _ *Volume, pvc *corev1.PersistentVolumeClaim, node *corev1.Node) error {
+	job, err := m.createBinderJob(ctx, pvc, node.Name)
+	if err != nil {
+		return err
+	}
+	return waitForJob(ctx, m.CRClient, job)
+}
+
+// Commit does not release any resource, as the VolumeSnapshots are deleted upon cleanup.
+func (m *csiDataMover) Commit(_ context.Context, _ *Volume) error { return nil }
+
+// Cleanup deletes the VolumeSnapshots created during the transfer.
+func (m *csiDataMover) Cleanup(ctx context.Context) error {
+	for _, name := range m.snapshots {
+		snapshot := &unstructured.Unstructured{}
+		snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+		snapshot.SetName(name)
+		snapshot.SetNamespace(liqoStorageNamespace)
+		if err := client.IgnoreNotFound(m.CRClient.Delete(ctx, snapshot)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createBinderJob creates a job mounting the given PVC in the given node, hence triggering its binding.
+func (m *csiDataMover) createBinderJob(ctx context.Context, pvc *corev1.PersistentVolumeClaim, nodeName string) (*batchv1.Job, error) {
+	job := batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "liqo-binder-",
+			Namespace:    pvc.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			TTLSecondsAfterFinished: ptr.To[int32](10),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Affinity: forgeNodeAffinity(nodeName),
+					Containers: []corev1.Container{{
+						Name:            "binder",
+						Image:           m.ResticImage,
+						ImagePullPolicy: corev1.PullIfNotPresent,
+						Args:            []string{"version"},
+						Resources:       m.forgeContainerResources(),
+						VolumeMounts:    []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+					}},
+					RestartPolicy: corev1.RestartPolicyOnFailure,
+					Volumes: []corev1.Volume{{
+						Name: "data",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	if err := m.CRClient.Create(ctx, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func volumeSnapshotName(volume *Volume) string {
+	return fmt.Sprintf("liqo-move-%s", volume.PVC.GetUID())
+}
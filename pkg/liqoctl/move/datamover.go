@@ -0,0 +1,110 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package move
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/liqotech/liqo/pkg/utils"
+)
+
+// DataMoverType identifies the mechanism leveraged to transfer the data of the volumes.
+type DataMoverType string
+
+const (
+	// ResticDataMover transfers the data through a temporary restic repository hosted in the cluster.
+	ResticDataMover DataMoverType = "restic"
+	// ResticS3DataMover transfers the data through a restic repository backed by an S3-compatible object storage.
+	ResticS3DataMover DataMoverType = "restic-s3"
+	// RsyncDataMover transfers the data through rsync, across the cross-cluster network fabric.
+	RsyncDataMover DataMoverType = "rsync"
+	// CSIDataMover transfers the data through CSI VolumeSnapshots and clones, between nodes of the local cluster only.
+	CSIDataMover DataMoverType = "csi"
+)
+
+// DataMoverTypes is the list of supported data mover types.
+var DataMoverTypes = []string{string(ResticDataMover), string(ResticS3DataMover), string(RsyncDataMover), string(CSIDataMover)}
+
+// Volume represents a PVC to be moved, along with the node it is currently bound to.
+type Volume struct {
+	PVC        *corev1.PersistentVolumeClaim
+	OriginNode *corev1.Node
+}
+
+// DataMover abstracts the mechanism leveraged to transfer the data of the volumes to the target node.
+type DataMover interface {
+	// Setup prepares the resources required to move the given volumes to the target node.
+	Setup(ctx context.Context, volumes []*Volume, targetNode *corev1.Node) error
+	// Snapshot preserves the data of the given volume, so that it survives the deletion of the original PVC.
+	Snapshot(ctx context.Context, volume *Volume) error
+	// ForgePvc mutates the PVC replacing the original one, before it is created.
+	ForgePvc(volume *Volume, pvc *corev1.PersistentVolumeClaim)
+	// Restore populates the PVC replacing the original one with the preserved data, binding it to the given node.
+	Restore(ctx context.Context, volume *Volume, pvc *corev1.PersistentVolumeClaim, node *corev1.Node) error
+	// Commit releases the data preserved for the given volume, once it has been restored. It is invoked only after
+	// all volumes have been moved, since the volume can no longer be rolled back to its origin node afterwards.
+	Commit(ctx context.Context, volume *Volume) error
+	// Cleanup releases the resources created by the data mover.
+	Cleanup(ctx context.Context) error
+}
+
+func (o *Options) dataMoverType() DataMoverType {
+	if o.DataMover == "" {
+		return ResticDataMover
+	}
+	return o.DataMover
+}
+
+func (o *Options) newDataMover() (DataMover, error) {
+	switch o.dataMoverType() {
+	case ResticDataMover:
+		return &resticDataMover{Options: o}, nil
+	case ResticS3DataMover:
+		if o.S3Repository == "" {
+			return nil, fmt.Errorf("the S3 repository must be specified when using the %s data mover", ResticS3DataMover)
+		}
+		return &resticS3DataMover{Options: o}, nil
+	case RsyncDataMover:
+		return &rsyncDataMover{Options: o}, nil
+	case CSIDataMover:
+		return &csiDataMover{Options: o}, nil
+	default:
+		return nil, fmt.Errorf("unsupported data mover %q (supported: %v)", o.DataMover, DataMoverTypes)
+	}
+}
+
+func isLocalNode(node *corev1.Node) bool {
+	return !utils.IsVirtualNode(node)
+}
+
+// forgeNodeAffinity returns the affinity constraining a pod to the given node.
+func forgeNodeAffinity(nodeName string) *corev1.Affinity {
+	return &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{
+						Key:      corev1.LabelHostname,
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   []string{nodeName},
+					}},
+				}},
+			},
+		},
+	}
+}
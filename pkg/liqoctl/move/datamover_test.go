@@ -0,0 +1,317 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package move
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqoctl/factory"
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
+)
+
+var _ = Context("Data movers", func() {
+	var (
+		ctx = context.Background()
+		cl  client.Client
+		o   *Options
+	)
+
+	newVolume := func(name string, local bool) *Volume {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-" + name, Labels: map[string]string{}}}
+		if !local {
+			node.Labels[liqoconst.TypeLabel] = liqoconst.TypeNode
+		}
+		return &Volume{
+			PVC: &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name + "-uid")},
+				Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-" + name},
+			},
+			OriginNode: node,
+		}
+	}
+
+	// succeedJobs marks the jobs as succeeded upon creation, as no controller runs them in the fake client.
+	succeedJobs := interceptor.Funcs{
+		Create: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if job, ok := obj.(*batchv1.Job); ok {
+				job.Status.Succeeded = 1
+			}
+			return cl.Create(ctx, obj, opts...)
+		},
+	}
+
+	BeforeEach(func() {
+		cl = fake.NewClientBuilder().Build()
+		o = &Options{
+			Factory:        &factory.Factory{CRClient: cl, Printer: output.NewFakePrinter(GinkgoWriter)},
+			ResticPassword: "password",
+			ResticImage:    DefaultResticImage,
+			RsyncImage:     DefaultRsyncImage,
+		}
+	})
+
+	DescribeTable("newDataMover",
+		func(moverType DataMoverType, s3Repository string, expected DataMover, shouldFail bool) {
+			o.DataMover = moverType
+			o.S3Repository = s3Repository
+			mover, err := o.newDataMover()
+			if shouldFail {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mover).To(BeAssignableToTypeOf(expected))
+		},
+		Entry("default", DataMoverType(""), "", &resticDataMover{}, false),
+		Entry("restic", ResticDataMover, "", &resticDataMover{}, false),
+		Entry("restic-s3", ResticS3DataMover, "s3:http://minio:9000/liqo", &resticS3DataMover{}, false),
+		Entry("restic-s3 without repository", ResticS3DataMover, "", nil, true),
+		Entry("rsync", RsyncDataMover, "", &rsyncDataMover{}, false),
+		Entry("csi", CSIDataMover, "", &csiDataMover{}, false),
+		Entry("unknown", DataMoverType("foo"), "", nil, true),
+	)
+
+	Context("restic-s3 data mover", func() {
+		var mover *resticS3DataMover
+
+		BeforeEach(func() {
+			o.S3Repository = "http://minio.minio.svc:9000/liqo/"
+			o.S3AccessKeyID = "access-key-id"
+			o.S3SecretAccessKey = "secret-access-key"
+			mover = &resticS3DataMover{Options: o}
+		})
+
+		It("should forge the repository URL", func() {
+			Expect(mover.repositoryURL()).To(Equal("s3:http://minio.minio.svc:9000/liqo/"))
+		})
+
+		When("the data mover is set up", func() {
+			var volume *Volume
+
+			BeforeEach(func() {
+				volume = newVolume("pvc1", false)
+				Expect(mover.Setup(ctx, []*Volume{volume, newVolume("pvc2", true)}, volume.OriginNode)).To(Succeed())
+			})
+
+			It("should create the secret with the credentials", func() {
+				var secret corev1.Secret
+				Expect(cl.Get(ctx, types.NamespacedName{Name: resticCredentialsSecretName, Namespace: "default"}, &secret)).To(Succeed())
+				Expect(secret.StringData).To(HaveKeyWithValue("AWS_ACCESS_KEY_ID", "access-key-id"))
+				Expect(secret.StringData).To(HaveKeyWithValue("AWS_SECRET_ACCESS_KEY", "secret-access-key"))
+			})
+
+			It("should configure the restic jobs to use the credentials", func() {
+				job, err := o.createSnapshotterJob(ctx, volume.PVC, mover.repositoryURL())
+				// The PV does not exist in the fake client.
+				Expect(err).To(HaveOccurred())
+				Expect(job).To(BeNil())
+
+				job, err = o.createRestorerJob(ctx, volume.PVC, volume.PVC, mover.repositoryURL())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(job.Spec.Template.Spec.Containers[0].EnvFrom).To(ConsistOf(corev1.EnvFromSource{
+					SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: resticCredentialsSecretName}},
+				}))
+				Expect(job.Spec.Template.Spec.Containers[0].Args).To(ContainElement("s3:http://minio.minio.svc:9000/liqo/pvc1-uid"))
+			})
+
+			It("should restore the volume from the S3 repository in the target node", func() {
+				cl = fake.NewClientBuilder().WithInterceptorFuncs(succeedJobs).Build()
+				o.CRClient = cl
+
+				target := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "target"}}
+				pvc := volume.PVC.DeepCopy()
+				Expect(mover.Restore(ctx, volume, pvc, target)).To(Succeed())
+				// The snapshots are retained in the S3 repository, hence committing does not release anything.
+				Expect(mover.Commit(ctx, volume)).To(Succeed())
+
+				var jobs batchv1.JobList
+				Expect(cl.List(ctx, &jobs)).To(Succeed())
+				Expect(jobs.Items).To(HaveLen(1))
+				Expect(jobs.Items[0].Spec.Template.Spec.Affinity).To(Equal(forgeNodeAffinity("target")))
+				Expect(jobs.Items[0].Spec.Template.Spec.Containers[0].Args).To(ContainElement("s3:http://minio.minio.svc:9000/liqo/pvc1-uid"))
+			})
+
+			It("should remove the secret upon cleanup", func() {
+				Expect(mover.Cleanup(ctx)).To(Succeed())
+				err := cl.Get(ctx, types.NamespacedName{Name: resticCredentialsSecretName, Namespace: "default"}, &corev1.Secret{})
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+	})
+
+	Context("rsync data mover", func() {
+		var (
+			mover  *rsyncDataMover
+			volume *Volume
+		)
+
+		BeforeEach(func() {
+			volume = newVolume("pvc1", true)
+			pv := &corev1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: volume.PVC.Spec.VolumeName},
+				Spec:       corev1.PersistentVolumeSpec{PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete},
+			}
+			cl = fake.NewClientBuilder().WithObjects(pv).WithInterceptorFuncs(succeedJobs).Build()
+			o.CRClient = cl
+			mover = &rsyncDataMover{Options: o}
+			Expect(mover.Setup(ctx, []*Volume{volume}, volume.OriginNode)).To(Succeed())
+		})
+
+		It("should retain the PV upon snapshot", func() {
+			Expect(mover.Snapshot(ctx, volume)).To(Succeed())
+
+			var pv corev1.PersistentVolume
+			Expect(cl.Get(ctx, types.NamespacedName{Name: volume.PVC.Spec.VolumeName}, &pv)).To(Succeed())
+			Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+			Expect(mover.reclaimPolicies).To(HaveKeyWithValue(pv.Name, corev1.PersistentVolumeReclaimDelete))
+		})
+
+		It("should preserve the original data until the volume is committed", func() {
+			target := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "target"}}
+			Expect(mover.Snapshot(ctx, volume)).To(Succeed())
+			Expect(mover.Restore(ctx, volume, volume.PVC.DeepCopy(), target)).To(Succeed())
+
+			var pv corev1.PersistentVolume
+			Expect(cl.Get(ctx, types.NamespacedName{Name: volume.PVC.Spec.VolumeName}, &pv)).To(Succeed())
+			Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimRetain))
+			Expect(pv.Spec.ClaimRef.Name).To(Equal("liqo-rsync-pvc1-uid"))
+			Expect(cl.Get(ctx, types.NamespacedName{Name: "liqo-rsync-pvc1-uid", Namespace: "default"},
+				&corev1.PersistentVolumeClaim{})).To(Succeed())
+
+			// The volume can still be rolled back, restoring the retained data in its origin node.
+			Expect(mover.Restore(ctx, volume, volume.PVC.DeepCopy(), volume.OriginNode)).To(Succeed())
+
+			Expect(mover.Commit(ctx, volume)).To(Succeed())
+			Expect(cl.Get(ctx, types.NamespacedName{Name: volume.PVC.Spec.VolumeName}, &pv)).To(Succeed())
+			Expect(pv.Spec.PersistentVolumeReclaimPolicy).To(Equal(corev1.PersistentVolumeReclaimDelete))
+			Expect(mover.reclaimPolicies).To(BeEmpty())
+			err := cl.Get(ctx, types.NamespacedName{Name: "liqo-rsync-pvc1-uid", Namespace: "default"}, &corev1.PersistentVolumeClaim{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should fail the snapshot if the PVC is not bound", func() {
+			volume.PVC.Spec.VolumeName = ""
+			Expect(mover.Snapshot(ctx, volume)).ToNot(Succeed())
+		})
+
+		It("should forge the rsync server constrained to the target node", func() {
+			job, err := mover.createRsyncServer(ctx, "liqo-rsync-pvc1", volume.PVC, "target")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job.Spec.Template.Labels).To(HaveKeyWithValue("app", "liqo-rsync-pvc1"))
+			Expect(job.Spec.Template.Spec.Affinity).To(Equal(forgeNodeAffinity("target")))
+			Expect(job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("pvc1"))
+
+			var service corev1.Service
+			Expect(cl.Get(ctx, types.NamespacedName{Name: "liqo-rsync-pvc1", Namespace: "default"}, &service)).To(Succeed())
+			Expect(service.Spec.Selector).To(HaveKeyWithValue("app", "liqo-rsync-pvc1"))
+		})
+
+		It("should forge the rsync server admitting the authenticated client only", func() {
+			job, err := mover.createRsyncServer(ctx, "liqo-rsync-pvc1", volume.PVC, "target")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job.Spec.Template.Spec.Containers[0].Args[0]).To(ContainSubstring("auth users = liqo"))
+			Expect(job.Spec.Template.Spec.Containers[0].Args[0]).To(ContainSubstring("secrets file = /etc/rsyncd/rsyncd.secrets"))
+			Expect(job.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("VolumeSource.Secret.SecretName", "liqo-rsync-pvc1")))
+
+			var policy networkingv1.NetworkPolicy
+			Expect(cl.Get(ctx, types.NamespacedName{Name: "liqo-rsync-pvc1", Namespace: "default"}, &policy)).To(Succeed())
+			Expect(policy.Spec.PodSelector.MatchLabels).To(HaveKeyWithValue("app", "liqo-rsync-pvc1"))
+			Expect(policy.Spec.Ingress).To(HaveLen(1))
+			Expect(policy.Spec.Ingress[0].From).To(ConsistOf(HaveField("PodSelector.MatchLabels",
+				HaveKeyWithValue("app", "liqo-rsync-pvc1-client"))))
+		})
+
+		It("should generate different credentials for each move", func() {
+			var first, second corev1.Secret
+			Expect(mover.createRsyncCredentials(ctx, "liqo-rsync-pvc1", "default")).To(Succeed())
+			Expect(cl.Get(ctx, types.NamespacedName{Name: "liqo-rsync-pvc1", Namespace: "default"}, &first)).To(Succeed())
+			Expect(first.StringData).To(HaveKeyWithValue(rsyncSecretsKey, "liqo:"+first.StringData[rsyncPasswordKey]+"\n"))
+
+			Expect(mover.createRsyncCredentials(ctx, "liqo-rsync-pvc1", "default")).To(Succeed())
+			Expect(cl.Get(ctx, types.NamespacedName{Name: "liqo-rsync-pvc1", Namespace: "default"}, &second)).To(Succeed())
+			Expect(second.StringData[rsyncPasswordKey]).ToNot(Equal(first.StringData[rsyncPasswordKey]))
+		})
+
+		It("should forge the rsync client copying to the server", func() {
+			job, err := mover.createRsyncClientJob(ctx, "liqo-rsync-pvc1", volume.PVC)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job.Spec.Template.Labels).To(HaveKeyWithValue("app", "liqo-rsync-pvc1-client"))
+			Expect(job.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{
+				"-a", "--delete", "/source/", "rsync://liqo@liqo-rsync-pvc1:873/volume/"}))
+			Expect(job.Spec.Template.Spec.Containers[0].Env).To(ConsistOf(HaveField("ValueFrom.SecretKeyRef.Key", rsyncPasswordKey)))
+		})
+
+		It("should tear down the server and the credentials once the data has been restored", func() {
+			target := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "target"}}
+			Expect(mover.Snapshot(ctx, volume)).To(Succeed())
+			Expect(mover.Restore(ctx, volume, volume.PVC.DeepCopy(), target)).To(Succeed())
+
+			key := types.NamespacedName{Name: "liqo-rsync-pvc1-uid", Namespace: "default"}
+			Expect(apierrors.IsNotFound(cl.Get(ctx, key, &corev1.Secret{}))).To(BeTrue())
+			Expect(apierrors.IsNotFound(cl.Get(ctx, key, &networkingv1.NetworkPolicy{}))).To(BeTrue())
+		})
+	})
+
+	Context("csi data mover", func() {
+		var mover *csiDataMover
+
+		BeforeEach(func() { mover = &csiDataMover{Options: o} })
+
+		It("should accept local volumes moved to local nodes", func() {
+			volume := newVolume("pvc1", true)
+			Expect(mover.Setup(ctx, []*Volume{volume}, volume.OriginNode)).To(Succeed())
+		})
+
+		It("should reject volumes involving virtual nodes", func() {
+			local, remote := newVolume("pvc1", true), newVolume("pvc2", false)
+			Expect(mover.Setup(ctx, []*Volume{local}, remote.OriginNode)).ToNot(Succeed())
+			Expect(mover.Setup(ctx, []*Volume{remote}, local.OriginNode)).ToNot(Succeed())
+		})
+
+		It("should configure the new PVC to be cloned from the snapshot", func() {
+			volume := newVolume("pvc1", true)
+			pvc := volume.PVC.DeepCopy()
+			mover.ForgePvc(volume, pvc)
+			Expect(pvc.Spec.DataSource).To(Equal(&corev1.TypedLocalObjectReference{
+				APIGroup: ptr.To("snapshot.storage.k8s.io"),
+				Kind:     "VolumeSnapshot",
+				Name:     "liqo-move-pvc1-uid",
+			}))
+		})
+
+		It("should bind the new PVC to the given node", func() {
+			volume := newVolume("pvc1", true)
+			job, err := mover.createBinderJob(ctx, volume.PVC, "target")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(job).To(BeAssignableToTypeOf(&batchv1.Job{}))
+			Expect(job.Spec.Template.Spec.Affinity).To(Equal(forgeNodeAffinity("target")))
+			Expect(job.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("pvc1"))
+		})
+	})
+})
@@ -24,7 +24,6 @@ import (
 
 	"github.com/liqotech/liqo/pkg/liqoctl/factory"
 	"github.com/liqotech/liqo/pkg/liqoctl/output"
-	"github.com/liqotech/liqo/pkg/utils"
 	"github.com/liqotech/liqo/pkg/utils/pod"
 )
 
@@ -42,13 +41,23 @@ type Options struct {
 
 	ResticServerImage string
 	ResticImage       string
+
+	DataMover DataMoverType
+
+	S3Repository      string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+
+	RsyncImage string
+
+	VolumeSnapshotClass string
+
+	// resticCredentialsSecret is the name of the secret containing the credentials to access the restic repository (if any).
+	resticCredentialsSecret string
 }
 
 // Run implements the move volume command.
 func (o *Options) Run(ctx context.Context) error {
-	// we need a context that is not canceled even if the user press Ctrl+C
-	deferCtx := context.Background()
-
 	s := o.Printer.StartSpinner("Running pre-flight checks")
 
 	var pvc corev1.PersistentVolumeClaim
@@ -64,96 +73,115 @@ func (o *Options) Run(ctx context.Context) error {
 	}
 	s.Success("Pre-flight checks passed")
 
-	s = o.Printer.StartSpinner("Offloading the liqo-storage namespace")
+	return o.moveVolumes(ctx, &pvc)
+}
 
-	var targetNode corev1.Node
-	if err := o.CRClient.Get(ctx, client.ObjectKey{Name: o.TargetNode}, &targetNode); err != nil {
-		s.Fail("Failed to get target node: ", output.PrettyErr(err))
-		return err
-	}
-	targetIsLocal := !utils.IsVirtualNode(&targetNode)
+// moveVolumes moves the given PVCs (not currently mounted by any pod) to the target node.
+// All volumes are snapshotted before moving any of them, so that in case of failure
+// the ones already moved can be restored in their origin node.
+func (o *Options) moveVolumes(ctx context.Context, pvcs ...*corev1.PersistentVolumeClaim) error {
+	// we need a context that is not canceled even if the user press Ctrl+C
+	deferCtx := context.Background()
 
-	originIsLocal, originNode, err := isLocalVolume(ctx, o.CRClient, &pvc)
+	mover, err := o.newDataMover()
 	if err != nil {
-		s.Fail("Failed to check if the volume is local: ", output.PrettyErr(err))
 		return err
 	}
 
-	if err = offloadLiqoStorageNamespace(ctx, o.CRClient, originNode, &targetNode); err != nil {
-		s.Fail("Failed to offload the liqo-storage namespace: ", output.PrettyErr(err))
+	s := o.Printer.StartSpinner("Retrieving the volumes to be moved")
+
+	var targetNode corev1.Node
+	if err := o.CRClient.Get(ctx, client.ObjectKey{Name: o.TargetNode}, &targetNode); err != nil {
+		s.Fail("Failed to get target node: ", output.PrettyErr(err))
 		return err
 	}
-	s.Success("Liqo-storage namespace offloaded")
 
-	defer func() {
-		s = o.Printer.StartSpinner("Repatriating the liqo-storage namespace")
+	volumes := make([]*Volume, len(pvcs))
+	for i := range pvcs {
+		_, originNode, err := isLocalVolume(ctx, o.CRClient, pvcs[i])
+		if err != nil {
+			s.Fail("Failed to check if the volume is local: ", output.PrettyErr(err))
+			return err
+		}
+		volumes[i] = &Volume{PVC: pvcs[i], OriginNode: originNode}
+	}
+	s.Success("Volumes retrieved")
 
-		if err := repatriateLiqoStorageNamespace(deferCtx, o.CRClient); err != nil {
-			s.Fail("Failed to repatriate the liqo-storage namespace: ", output.PrettyErr(err))
-			return
+	defer func() {
+		if err := mover.Cleanup(deferCtx); err != nil {
+			o.Printer.Warning.Printfln("Failed to clean up the %s data mover: %v", o.dataMoverType(), output.PrettyErr(err))
 		}
-		s.Success("Repatriated the liqo-storage namespace")
 	}()
 
-	s = o.Printer.StartSpinner("Ensuring restic repository")
-
-	if err := o.ensureResticRepository(ctx, &pvc); err != nil {
-		s.Fail("Failed to ensure restic repository: ", output.PrettyErr(err))
+	if err := mover.Setup(ctx, volumes, &targetNode); err != nil {
 		return err
 	}
-	s.Success("Ensured restic repository")
 
-	defer func() {
-		s = o.Printer.StartSpinner("Removing restic repository")
+	for _, volume := range volumes {
+		s = o.Printer.StartSpinner(fmt.Sprintf("Taking snapshot of volume %q", volume.PVC.Name))
 
-		if err = deleteResticRepository(deferCtx, o.CRClient); err != nil {
-			s.Fail("Failed to remove restic repository: ", output.PrettyErr(err))
-			return
+		if err := mover.Snapshot(ctx, volume); err != nil {
+			s.Fail("Failed to take snapshot: ", output.PrettyErr(err))
+			return err
 		}
-		s.Success("Removed restic repository")
-	}()
+		s.Success(fmt.Sprintf("Snapshot of volume %q taken", volume.PVC.Name))
+	}
 
-	s = o.Printer.StartSpinner("Waiting for restic repository to be up and running")
+	for i, volume := range volumes {
+		s = o.Printer.StartSpinner(fmt.Sprintf("Moving volume %q", volume.PVC.Name))
+
+		if err := o.moveVolume(ctx, mover, volume, &targetNode); err != nil {
+			s.Fail("Failed to move volume: ", output.PrettyErr(err))
+			// Restore the volumes already moved (including the current one) in their origin node.
+			// This is possible since no volume has been committed yet, hence their data is still preserved.
+			for j := i; j >= 0; j-- {
+				o.rollbackVolume(deferCtx, mover, volumes[j])
+			}
+			return err
+		}
+		s.Success(fmt.Sprintf("Volume %q moved", volume.PVC.Name))
+	}
 
-	if err = waitForResticRepository(ctx, o.CRClient); err != nil {
-		s.Fail("Failed to wait for restic repository to be up and running: ", output.PrettyErr(err))
-		return err
+	// All volumes have been moved, hence the preserved data can be released.
+	for _, volume := range volumes {
+		if err := mover.Commit(deferCtx, volume); err != nil {
+			o.Printer.Warning.Printfln("Failed to release the original data of volume %q: %v", volume.PVC.Name, output.PrettyErr(err))
+		}
 	}
-	s.Success("Restic repository is up and running")
 
-	s = o.Printer.StartSpinner("Taking snapshot")
+	return nil
+}
 
-	originResticRepositoryURL, err := getResticRepositoryURL(ctx, o.CRClient, originIsLocal)
+// moveVolume recreates the given PVC, and restores the corresponding snapshot into the new volume in the given node.
+func (o *Options) moveVolume(ctx context.Context, mover DataMover, volume *Volume, node *corev1.Node) error {
+	newPvc, err := recreatePvc(ctx, o.CRClient, volume.PVC, func(pvc *corev1.PersistentVolumeClaim) {
+		mover.ForgePvc(volume, pvc)
+	})
 	if err != nil {
-		s.Fail("Failed to get origin restic repository URL: ", output.PrettyErr(err))
-		return err
+		return fmt.Errorf("failed to recreate PVC: %w", err)
 	}
-	if err = o.takeSnapshot(ctx, &pvc, originResticRepositoryURL); err != nil {
-		s.Fail("Failed to take snapshot: ", output.PrettyErr(err))
-		return err
+
+	if err = mover.Restore(ctx, volume, newPvc, node); err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
 	}
-	s.Success("Snapshot taken")
+	return nil
+}
 
-	s = o.Printer.StartSpinner("Moving the volume")
+// rollbackVolume restores the snapshot of the given volume into a new PVC in its origin node.
+// The preserved data is released only if the rollback succeeds, and retained otherwise.
+func (o *Options) rollbackVolume(ctx context.Context, mover DataMover, volume *Volume) {
+	s := o.Printer.StartSpinner(fmt.Sprintf("Restoring volume %q in node %q", volume.PVC.Name, volume.OriginNode.Name))
 
-	newPvc, err := recreatePvc(ctx, o.CRClient, &pvc)
-	if err != nil {
-		s.Fail("Failed to recreate PVC: ", output.PrettyErr(err))
-		return err
+	if err := o.moveVolume(ctx, mover, volume, volume.OriginNode); err != nil {
+		s.Fail(fmt.Sprintf("Failed to restore volume %q (the snapshot is identified by %q): %v",
+			volume.PVC.Name, volume.PVC.GetUID(), output.PrettyErr(err)))
+		return
 	}
+	s.Success(fmt.Sprintf("Volume %q restored in node %q", volume.PVC.Name, volume.OriginNode.Name))
 
-	targetResticRepositoryURL, err := getResticRepositoryURL(ctx, o.CRClient, targetIsLocal)
-	if err != nil {
-		s.Fail("Failed to get target restic repository URL: ", output.PrettyErr(err))
-		return err
+	if err := mover.Commit(ctx, volume); err != nil {
+		o.Printer.Warning.Printfln("Failed to release the original data of volume %q: %v", volume.PVC.Name, output.PrettyErr(err))
 	}
-	if err = o.restoreSnapshot(ctx, &pvc, newPvc, targetResticRepositoryURL); err != nil {
-		s.Fail("Failed to restore snapshot: ", output.PrettyErr(err))
-		return err
-	}
-
-	s.Success("Restore completed")
-	return nil
 }
 
 func getResticRepositoryURL(ctx context.Context, cl client.Client, isLocal bool) (string, error) {
@@ -174,3 +202,16 @@ func getResticRepositoryURL(ctx context.Context, cl client.Client, isLocal bool)
 func (o *Options) forgeContainerResources() corev1.ResourceRequirements {
 	return pod.ForgeContainerResources(o.ContainersCPURequests, o.ContainersCPULimits, o.ContainersRAMRequests, o.ContainersRAMLimits)
 }
+
+func (o *Options) forgeResticEnv() []corev1.EnvVar {
+	return []corev1.EnvVar{{Name: "RESTIC_PASSWORD", Value: o.ResticPassword}}
+}
+
+func (o *Options) forgeResticEnvFrom() []corev1.EnvFromSource {
+	if o.resticCredentialsSecret == "" {
+		return nil
+	}
+	return []corev1.EnvFromSource{{
+		SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: o.resticCredentialsSecret}},
+	}}
+}
@@ -0,0 +1,211 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package move
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/liqoctl/factory"
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
+)
+
+var _ = Context("Move Workloads", func() {
+	var ctx = context.Background()
+
+	DescribeTable("ParseWorkloadKind",
+		func(kind, expected string, shouldFail bool) {
+			parsed, err := ParseWorkloadKind(kind)
+			if shouldFail {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).ToNot(HaveOccurred())
+			Expect(parsed).To(Equal(expected))
+		},
+		Entry("deployment", "Deployment", WorkloadKindDeployment, false),
+		Entry("abbreviated deployment", "deploy", WorkloadKindDeployment, false),
+		Entry("statefulset", "statefulset", WorkloadKindStatefulSet, false),
+		Entry("abbreviated statefulset", "sts", WorkloadKindStatefulSet, false),
+		Entry("unsupported kind", "daemonset", "", true),
+	)
+
+	Context("retargetNodeAffinity", func() {
+		const target = "liqo-target"
+
+		hostnameIn := func(values ...string) corev1.NodeSelectorRequirement {
+			return corev1.NodeSelectorRequirement{Key: corev1.LabelHostname, Operator: corev1.NodeSelectorOpIn, Values: values}
+		}
+
+		It("should create the required terms if not present", func() {
+			affinity := retargetNodeAffinity(nil, target)
+			Expect(affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(ConsistOf(
+				corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{hostnameIn(target)}},
+			))
+		})
+
+		It("should add the constraint to the existing terms, replacing the previous hostname ones", func() {
+			zone := corev1.NodeSelectorRequirement{Key: corev1.LabelTopologyZone, Operator: corev1.NodeSelectorOpExists}
+			original := &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{MatchExpressions: []corev1.NodeSelectorRequirement{zone, hostnameIn("origin")}},
+						{MatchExpressions: []corev1.NodeSelectorRequirement{hostnameIn("other")}},
+					},
+				},
+			}
+
+			affinity := retargetNodeAffinity(original, target)
+			Expect(affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(ConsistOf(
+				corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{hostnameIn(target), zone}},
+				corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{hostnameIn(target)}},
+			))
+			// The original affinity shall not be modified.
+			Expect(original.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions).
+				To(ConsistOf(zone, hostnameIn("origin")))
+		})
+	})
+
+	Context("getWorkloadVolumes", func() {
+		var (
+			cl   client.Client
+			pvcs []*corev1.PersistentVolumeClaim
+			err  error
+		)
+
+		newPvc := func(name string) *corev1.PersistentVolumeClaim {
+			return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		}
+
+		names := func(pvcs []*corev1.PersistentVolumeClaim) []string {
+			var names []string
+			for _, pvc := range pvcs {
+				names = append(names, pvc.Name)
+			}
+			return names
+		}
+
+		When("the workload is a Deployment", func() {
+			BeforeEach(func() {
+				deploy := &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "default"},
+					Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+						Volumes: []corev1.Volume{
+							{Name: "data", VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+							{Name: "config", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+						},
+					}}},
+				}
+				cl = fake.NewClientBuilder().WithObjects(newPvc("data"), newPvc("other")).Build()
+				pvcs, err = getWorkloadVolumes(ctx, cl, deploy)
+			})
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("should return the mounted PVCs", func() { Expect(names(pvcs)).To(ConsistOf("data")) })
+		})
+
+		When("the workload is a StatefulSet", func() {
+			BeforeEach(func() {
+				sts := &appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "sts", Namespace: "default"},
+					Spec: appsv1.StatefulSetSpec{
+						Replicas: ptr.To[int32](3),
+						VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+							{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+						},
+					},
+				}
+				// The PVC of the third replica has not been created yet.
+				cl = fake.NewClientBuilder().WithObjects(newPvc("data-sts-0"), newPvc("data-sts-1")).Build()
+				pvcs, err = getWorkloadVolumes(ctx, cl, sts)
+			})
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("should return the existing PVCs generated from the claim templates", func() {
+				Expect(names(pvcs)).To(ConsistOf("data-sts-0", "data-sts-1"))
+			})
+		})
+	})
+
+	Context("totalStorageRequests", func() {
+		It("should sum the storage requests of the given PVCs", func() {
+			withRequests := func(quantity string) *corev1.PersistentVolumeClaim {
+				return &corev1.PersistentVolumeClaim{Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(quantity)},
+					},
+				}}
+			}
+
+			total := totalStorageRequests(withRequests("1Gi"), withRequests("512Mi"))
+			Expect(total.Cmp(resource.MustParse("1536Mi"))).To(BeZero())
+		})
+	})
+	Context("rollback", func() {
+		var (
+			cl        client.Client
+			o         *WorkloadOptions
+			original  *appsv1.Deployment
+			migration *offloadingv1beta1.WorkloadMigration
+			err       error
+		)
+
+		BeforeEach(func() {
+			original = &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "default"},
+				Spec: appsv1.DeploymentSpec{Replicas: ptr.To[int32](3), Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "app"}}}}},
+			}
+
+			// The workload has already been retargeted to the target node.
+			retargeted := original.DeepCopy()
+			retargeted.Spec.Template.Spec.Affinity = &corev1.Affinity{NodeAffinity: retargetNodeAffinity(nil, "liqo-target")}
+
+			cl = fake.NewClientBuilder().WithObjects(retargeted).Build()
+			o = &WorkloadOptions{
+				Options:      Options{Factory: &factory.Factory{CRClient: cl, Printer: output.NewFakePrinter(GinkgoWriter)}},
+				WorkloadKind: WorkloadKindDeployment, WorkloadName: original.Name,
+			}
+			migration = &offloadingv1beta1.WorkloadMigration{ObjectMeta: metav1.ObjectMeta{Name: "migration", Namespace: "default"}}
+		})
+
+		JustBeforeEach(func() {
+			err = o.rollback(ctx, migration, original, nil, errors.New("failure"))
+		})
+
+		It("should return the original error", func() { Expect(err).To(MatchError("failure")) })
+		It("should restore the original placement and replicas", func() {
+			var deploy appsv1.Deployment
+			Expect(cl.Get(ctx, client.ObjectKeyFromObject(original), &deploy)).To(Succeed())
+			Expect(deploy.Spec.Template.Spec.Affinity).To(BeNil())
+			Expect(deploy.Spec.Replicas).To(Equal(ptr.To[int32](3)))
+		})
+		It("should mark the migration as failed", func() {
+			Expect(migration.Status.Phase).To(Equal(offloadingv1beta1.WorkloadMigrationPhaseFailed))
+		})
+	})
+})
@@ -17,6 +17,7 @@ package move
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -31,7 +32,7 @@ import (
 	"github.com/liqotech/liqo/pkg/utils"
 )
 
-func offloadLiqoStorageNamespace(ctx context.Context, cl client.Client, originNode, targetNode *corev1.Node) error {
+func offloadLiqoStorageNamespace(ctx context.Context, cl client.Client, nodes ...*corev1.Node) error {
 	namespaceOffloading := &offloadingv1beta1.NamespaceOffloading{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      liqoconst.DefaultNamespaceOffloadingName,
@@ -47,7 +48,7 @@ func offloadLiqoStorageNamespace(ctx context.Context, cl client.Client, originNo
 							{
 								Key:      "kubernetes.io/hostname",
 								Operator: corev1.NodeSelectorOpIn,
-								Values:   getRemoteNodeNames(originNode, targetNode),
+								Values:   getRemoteNodeNames(nodes...),
 							},
 						},
 					},
@@ -76,7 +77,7 @@ func repatriateLiqoStorageNamespace(ctx context.Context, cl client.Client) error
 func getRemoteNodeNames(nodes ...*corev1.Node) []string {
 	var remoteNodes []string
 	for _, node := range nodes {
-		if utils.IsVirtualNode(node) {
+		if utils.IsVirtualNode(node) && !slices.Contains(remoteNodes, node.Name) {
 			remoteNodes = append(remoteNodes, node.Name)
 		}
 	}
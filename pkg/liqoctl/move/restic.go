@@ -30,7 +30,7 @@ import (
 	"github.com/liqotech/liqo/pkg/utils/resource"
 )
 
-func (o *Options) ensureResticRepository(ctx context.Context, targetPvc *corev1.PersistentVolumeClaim) error {
+func (o *Options) ensureResticRepository(ctx context.Context, targetPvcs ...*corev1.PersistentVolumeClaim) error {
 	svc := corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      resticRegistry,
@@ -127,7 +127,7 @@ func (o *Options) ensureResticRepository(ctx context.Context, targetPvc *corev1.
 						},
 						Resources: corev1.VolumeResourceRequirements{
 							Requests: corev1.ResourceList{
-								corev1.ResourceStorage: targetPvc.Spec.Resources.Requests[corev1.ResourceStorage],
+								corev1.ResourceStorage: totalStorageRequests(targetPvcs...),
 							},
 						},
 					},
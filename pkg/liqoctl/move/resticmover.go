@@ -0,0 +1,206 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package move
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
+)
+
+const resticCredentialsSecretName = "liqo-move-restic-credentials"
+
+// resticDataMover transfers the data through a temporary restic repository hosted in the liqo-storage namespace,
+// which is offloaded to the remote clusters involved in the transfer.
+type resticDataMover struct {
+	*Options
+
+	offloaded, repositoryCreated bool
+}
+
+var _ DataMover = &resticDataMover{}
+
+// Setup offloads the liqo-storage namespace and starts the temporary restic repository.
+func (m *resticDataMover) Setup(ctx context.Context, volumes []*Volume, targetNode *corev1.Node) error {
+	s := m.Printer.StartSpinner("Offloading the liqo-storage namespace")
+
+	nodes := []*corev1.Node{targetNode}
+	pvcs := make([]*corev1.PersistentVolumeClaim, 0, len(volumes))
+	for _, volume := range volumes {
+		nodes = append(nodes, volume.OriginNode)
+		pvcs = append(pvcs, volume.PVC)
+	}
+
+	if err := offloadLiqoStorageNamespace(ctx, m.CRClient, nodes...); err != nil {
+		s.Fail("Failed to offload the liqo-storage namespace: ", output.PrettyErr(err))
+		return err
+	}
+	m.offloaded = true
+	s.Success("Liqo-storage namespace offloaded")
+
+	s = m.Printer.StartSpinner("Ensuring restic repository")
+
+	if err := m.ensureResticRepository(ctx, pvcs...); err != nil {
+		s.Fail("Failed to ensure restic repository: ", output.PrettyErr(err))
+		return err
+	}
+	m.repositoryCreated = true
+	s.Success("Ensured restic repository")
+
+	s = m.Printer.StartSpinner("Waiting for restic repository to be up and running")
+
+	if err := waitForResticRepository(ctx, m.CRClient); err != nil {
+		s.Fail("Failed to wait for restic repository to be up and running: ", output.PrettyErr(err))
+		return err
+	}
+	s.Success("Restic repository is up and running")
+	return nil
+}
+
+// Snapshot backs up the given volume to the restic repository.
+func (m *resticDataMover) Snapshot(ctx context.Context, volume *Volume) error {
+	url, err := getResticRepositoryURL(ctx, m.CRClient, isLocalNode(volume.OriginNode))
+	if err != nil {
+		return fmt.Errorf("failed to get origin restic repository URL: %w", err)
+	}
+	return m.takeSnapshot(ctx, volume.PVC, url)
+}
+
+// ForgePvc does not alter the new PVC.
+func (m *resticDataMover) ForgePvc(_ *Volume, _ *corev1.PersistentVolumeClaim) {}
+
+// Restore restores the backup of the given volume from the restic repository.
+func (m *resticDataMover) Restore(ctx context.Context, volume *Volume, pvc *corev1.PersistentVolumeClaim, node *corev1.Node) error {
+	url, err := getResticRepositoryURL(ctx, m.CRClient, isLocalNode(node))
+	if err != nil {
+		return fmt.Errorf("failed to get target restic repository URL: %w", err)
+	}
+
+	opts := *m.Options
+	opts.TargetNode = node.Name
+	return opts.restoreSnapshot(ctx, volume.PVC, pvc, url)
+}
+
+// Commit does not release any resource, as the restic repository is removed upon cleanup.
+func (m *resticDataMover) Commit(_ context.Context, _ *Volume) error { return nil }
+
+// Cleanup removes the restic repository and repatriates the liqo-storage namespace.
+func (m *resticDataMover) Cleanup(ctx context.Context) error {
+	if m.repositoryCreated {
+		s := m.Printer.StartSpinner("Removing restic repository")
+
+		if err := deleteResticRepository(ctx, m.CRClient); err != nil {
+			s.Fail("Failed to remove restic repository: ", output.PrettyErr(err))
+		} else {
+			s.Success("Removed restic repository")
+		}
+	}
+
+	if m.offloaded {
+		s := m.Printer.StartSpinner("Repatriating the liqo-storage namespace")
+
+		if err := repatriateLiqoStorageNamespace(ctx, m.CRClient); err != nil {
+			s.Fail("Failed to repatriate the liqo-storage namespace: ", output.PrettyErr(err))
+			return err
+		}
+		s.Success("Repatriated the liqo-storage namespace")
+	}
+	return nil
+}
+
+// resticS3DataMover transfers the data through a restic repository backed by an S3-compatible object storage
+// (e.g., MinIO) reachable from all clusters, hence avoiding to stream large volumes through the temporary repository.
+// The snapshots are retained in the object storage after the transfer.
+type resticS3DataMover struct {
+	*Options
+
+	namespaces []string
+}
+
+var _ DataMover = &resticS3DataMover{}
+
+// Setup creates the secret containing the credentials to access the S3 repository in the namespaces of the volumes.
+func (m *resticS3DataMover) Setup(ctx context.Context, volumes []*Volume, _ *corev1.Node) error {
+	s := m.Printer.StartSpinner("Configuring the credentials to access the S3 repository")
+
+	for _, volume := range volumes {
+		namespace := volume.PVC.Namespace
+		if slices.Contains(m.namespaces, namespace) {
+			continue
+		}
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: resticCredentialsSecretName, Namespace: namespace},
+			StringData: map[string]string{
+				"AWS_ACCESS_KEY_ID":     m.S3AccessKeyID,
+				"AWS_SECRET_ACCESS_KEY": m.S3SecretAccessKey,
+			},
+		}
+		if err := m.CRClient.Create(ctx, secret); err != nil {
+			s.Fail("Failed to create the secret containing the S3 credentials: ", output.PrettyErr(err))
+			return err
+		}
+		m.namespaces = append(m.namespaces, namespace)
+	}
+
+	m.resticCredentialsSecret = resticCredentialsSecretName
+	s.Success("Configured the credentials to access the S3 repository")
+	return nil
+}
+
+// Snapshot backs up the given volume to the S3 repository.
+func (m *resticS3DataMover) Snapshot(ctx context.Context, volume *Volume) error {
+	return m.takeSnapshot(ctx, volume.PVC, m.repositoryURL())
+}
+
+// ForgePvc does not alter the new PVC.
+func (m *resticS3DataMover) ForgePvc(_ *Volume, _ *corev1.PersistentVolumeClaim) {}
+
+// Restore restores the backup of the given volume from the S3 repository.
+func (m *resticS3DataMover) Restore(ctx context.Context, volume *Volume, pvc *corev1.PersistentVolumeClaim, node *corev1.Node) error {
+	opts := *m.Options
+	opts.TargetNode = node.Name
+	return opts.restoreSnapshot(ctx, volume.PVC, pvc, m.repositoryURL())
+}
+
+// Commit does not release any resource, as the snapshots are retained in the S3 repository.
+func (m *resticS3DataMover) Commit(_ context.Context, _ *Volume) error { return nil }
+
+// Cleanup removes the secrets containing the S3 credentials.
+func (m *resticS3DataMover) Cleanup(ctx context.Context) error {
+	for _, namespace := range m.namespaces {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: resticCredentialsSecretName, Namespace: namespace}}
+		if err := client.IgnoreNotFound(m.CRClient.Delete(ctx, secret)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// repositoryURL returns the base URL of the restic repository, which is suffixed with the UID of each PVC.
+func (m *resticS3DataMover) repositoryURL() string {
+	url := m.S3Repository
+	if !strings.HasPrefix(url, "s3:") {
+		url = "s3:" + url
+	}
+	return strings.TrimSuffix(url, "/") + "/"
+}
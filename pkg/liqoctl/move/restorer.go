@@ -46,23 +46,7 @@ func (o *Options) createRestorerJob(ctx context.Context,
 			TTLSecondsAfterFinished: pointer.Int32Ptr(10),
 			Template: corev1.PodTemplateSpec{
 				Spec: corev1.PodSpec{
-					Affinity: &corev1.Affinity{
-						NodeAffinity: &corev1.NodeAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
-								NodeSelectorTerms: []corev1.NodeSelectorTerm{
-									{
-										MatchExpressions: []corev1.NodeSelectorRequirement{
-											{
-												Key:      "kubernetes.io/hostname",
-												Operator: corev1.NodeSelectorOpIn,
-												Values:   []string{o.TargetNode},
-											},
-										},
-									},
-								},
-							},
-						},
-					},
+					Affinity: forgeNodeAffinity(o.TargetNode),
 					Containers: []corev1.Container{
 						{
 							Name:            "restic",
@@ -74,12 +58,8 @@ func (o *Options) createRestorerJob(ctx context.Context,
 								"restore", "latest",
 								"--target", "/restore",
 							},
-							Env: []corev1.EnvVar{
-								{
-									Name:  "RESTIC_PASSWORD",
-									Value: o.ResticPassword,
-								},
-							},
+							Env:       o.forgeResticEnv(),
+							EnvFrom:   o.forgeResticEnvFrom(),
 							Resources: o.forgeContainerResources(),
 							VolumeMounts: []corev1.VolumeMount{
 								{
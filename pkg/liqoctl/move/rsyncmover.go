@@ -0,0 +1,339 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package move
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// rsyncUser is the user the rsync client authenticates as.
+	rsyncUser = "liqo"
+	// rsyncSecretsKey is the key of the secret containing the rsyncd secrets file.
+	rsyncSecretsKey = "rsyncd.secrets"
+	// rsyncPasswordKey is the key of the secret containing the password of the rsync client.
+	rsyncPasswordKey = "password"
+)
+
+// rsyncDataMover transfers the data through rsync, directly from the original volume to the new one,
+// across the cross-cluster network fabric. The original PV is retained after the deletion of the PVC,
+// and bound to a source PVC mounted by the rsync client until all volumes have been moved.
+type rsyncDataMover struct {
+	*Options
+
+	// reclaimPolicies stores the original reclaim policy of the PVs retained during the transfer.
+	reclaimPolicies map[string]corev1.PersistentVolumeReclaimPolicy
+}
+
+var _ DataMover = &rsyncDataMover{}
+
+// Setup does not require any preliminary operation.
+func (m *rsyncDataMover) Setup(_ context.Context, _ []*Volume, _ *corev1.Node) error {
+	m.reclaimPolicies = make(map[string]corev1.PersistentVolumeReclaimPolicy)
+	return nil
+}
+
+// Snapshot configures the PV bound to the given volume to be retained after the deletion of the PVC.
+func (m *rsyncDataMover) Snapshot(ctx context.Context, volume *Volume) error {
+	if volume.PVC.Spec.VolumeName == "" {
+		return fmt.Errorf("the PVC %s/%s is not bound to any PV", volume.PVC.Namespace, volume.PVC.Name)
+	}
+
+	var pv corev1.PersistentVolume
+	if err := m.CRClient.Get(ctx, client.ObjectKey{Name: volume.PVC.Spec.VolumeName}, &pv); err != nil {
+		return err
+	}
+
+	if _, found := m.reclaimPolicies[pv.Name]; !found {
+		m.reclaimPolicies[pv.Name] = pv.Spec.PersistentVolumeReclaimPolicy
+	}
+	return m.setReclaimPolicy(ctx, &pv, corev1.PersistentVolumeReclaimRetain)
+}
+
+// ForgePvc does not alter the new PVC.
+func (m *rsyncDataMover) ForgePvc(_ *Volume, _ *corev1.PersistentVolumeClaim) {}
+
+// Restore copies the data from the retained PV to the new PVC, through an rsync server bound to the given node.
+func (m *rsyncDataMover) Restore(ctx context.Context, volume *Volume, pvc *corev1.PersistentVolumeClaim, node *corev1.Node) error {
+	name := fmt.Sprintf("liqo-rsync-%s", shortUID(volume.PVC))
+
+	// Bind the retained PV to the source PVC.
+	var pv corev1.PersistentVolume
+	if err := m.CRClient.Get(ctx, client.ObjectKey{Name: volume.PVC.Spec.VolumeName}, &pv); err != nil {
+		return err
+	}
+	pv.Spec.ClaimRef = &corev1.ObjectReference{Namespace: pvc.Namespace, Name: name}
+	if err := m.CRClient.Update(ctx, &pv); err != nil {
+		return err
+	}
+
+	source := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: pvc.Namespace, Labels: pvc.Labels},
+		Spec:       *volume.PVC.Spec.DeepCopy(),
+	}
+	if err := m.CRClient.Create(ctx, source); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	// The server is always torn down, as a new one (with new credentials) is created in case of rollback.
+	var server *batchv1.Job
+	defer func() {
+		if server != nil {
+			_ = m.CRClient.Delete(context.Background(), server, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		}
+		meta := metav1.ObjectMeta{Name: name, Namespace: pvc.Namespace}
+		_ = client.IgnoreNotFound(m.CRClient.Delete(context.Background(), &corev1.Service{ObjectMeta: meta}))
+		_ = client.IgnoreNotFound(m.CRClient.Delete(context.Background(), &networkingv1.NetworkPolicy{ObjectMeta: meta}))
+		_ = client.IgnoreNotFound(m.CRClient.Delete(context.Background(), &corev1.Secret{ObjectMeta: meta}))
+	}()
+
+	if err := m.createRsyncCredentials(ctx, name, pvc.Namespace); err != nil {
+		return err
+	}
+
+	server, err := m.createRsyncServer(ctx, name, pvc, node.Name)
+	if err != nil {
+		return err
+	}
+
+	job, err := m.createRsyncClientJob(ctx, name, source)
+	if err != nil {
+		return err
+	}
+	// The original PV is kept bound to the source PVC until the volume is committed, to support rollbacks.
+	return waitForJob(ctx, m.CRClient, job)
+}
+
+// Commit restores the reclaim policy of the retained PV and deletes the source PVC, hence releasing the original data.
+func (m *rsyncDataMover) Commit(ctx context.Context, volume *Volume) error {
+	var pv corev1.PersistentVolume
+	if err := m.CRClient.Get(ctx, client.ObjectKey{Name: volume.PVC.Spec.VolumeName}, &pv); err != nil {
+		return err
+	}
+
+	if policy, found := m.reclaimPolicies[pv.Name]; found {
+		if err := m.setReclaimPolicy(ctx, &pv, policy); err != nil {
+			return err
+		}
+		delete(m.reclaimPolicies, pv.Name)
+	}
+
+	source := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+		Name: fmt.Sprintf("liqo-rsync-%s", shortUID(volume.PVC)), Namespace: volume.PVC.Namespace}}
+	return client.IgnoreNotFound(m.CRClient.Delete(ctx, source))
+}
+
+// Cleanup warns about the PVs still retained, which hold the original data of the volumes not moved.
+func (m *rsyncDataMover) Cleanup(_ context.Context) error {
+	for pv := range m.reclaimPolicies {
+		m.Printer.Warning.Printfln("The PV %q has been retained, as it holds the original data of a volume not moved", pv)
+	}
+	return nil
+}
+
+func (m *rsyncDataMover) setReclaimPolicy(ctx context.Context, pv *corev1.PersistentVolume, policy corev1.PersistentVolumeReclaimPolicy) error {
+	if pv.Spec.PersistentVolumeReclaimPolicy == policy {
+		return nil
+	}
+	original := pv.DeepCopy()
+	pv.Spec.PersistentVolumeReclaimPolicy = policy
+	return m.CRClient.Patch(ctx, pv, client.MergeFrom(original))
+}
+
+// createRsyncCredentials creates the secret containing the credentials generated for a single volume move,
+// both in the format expected by the rsync daemon and by the client.
+func (m *rsyncDataMover) createRsyncCredentials(ctx context.Context, name, namespace string) error {
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		return fmt.Errorf("failed to generate the rsync password: %w", err)
+	}
+	password := hex.EncodeToString(buffer)
+
+	// A stale secret may exist in case of a previous failure, and it is replaced to avoid reusing the credentials.
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	if err := client.IgnoreNotFound(m.CRClient.Delete(ctx, secret)); err != nil {
+		return err
+	}
+
+	secret.StringData = map[string]string{
+		rsyncSecretsKey:  fmt.Sprintf("%s:%s\n", rsyncUser, password),
+		rsyncPasswordKey: password,
+	}
+	return m.CRClient.Create(ctx, secret)
+}
+
+// createRsyncServer creates the rsync server (and the corresponding service) mounting the given PVC in the given node.
+// The daemon requires the clients to authenticate with the credentials stored in the secret with the same name,
+// and a network policy admits the connections from the rsync client only.
+func (m *rsyncDataMover) createRsyncServer(ctx context.Context, name string,
+	pvc *corev1.PersistentVolumeClaim, nodeName string) (*batchv1.Job, error) {
+	labels := map[string]string{"app": name}
+
+	policy := networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: pvc.Namespace},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: labels},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{
+					PodSelector: &metav1.LabelSelector{MatchLabels: rsyncClientLabels(name)},
+				}},
+				Ports: []networkingv1.NetworkPolicyPort{{
+					Protocol: ptr.To(corev1.ProtocolTCP),
+					Port:     ptr.To(intstr.FromInt(rsyncPort)),
+				}},
+			}},
+		},
+	}
+	if err := m.CRClient.Create(ctx, &policy); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, err
+	}
+
+	service := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: pvc.Namespace},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports: []corev1.ServicePort{{
+				Port:       rsyncPort,
+				TargetPort: intstr.FromInt(rsyncPort),
+				Protocol:   corev1.ProtocolTCP,
+			}},
+		},
+	}
+	if err := m.CRClient.Create(ctx, &service); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, err
+	}
+
+	config := fmt.Sprintf("[volume]\n\tpath = /data\n\tread only = false\n\tuid = root\n\tgid = root\n"+
+		"\tauth users = %s\n\tsecrets file = /etc/rsyncd/%s\n", rsyncUser, rsyncSecretsKey)
+	job := batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "liqo-rsync-server-",
+			Namespace:    pvc.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Affinity: forgeNodeAffinity(nodeName),
+					Containers: []corev1.Container{{
+						Name:            "rsync",
+						Image:           m.RsyncImage,
+						ImagePullPolicy: corev1.PullIfNotPresent,
+						Command:         []string{"sh", "-c"},
+						Args: []string{fmt.Sprintf("printf '%s' > /tmp/rsyncd.conf && "+
+							"exec rsync --daemon --no-detach --config=/tmp/rsyncd.conf --port=%d --log-file=/dev/stdout", config, rsyncPort)},
+						Ports:     []corev1.ContainerPort{{ContainerPort: rsyncPort, Protocol: corev1.ProtocolTCP}},
+						Resources: m.forgeContainerResources(),
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "data", MountPath: "/data"},
+							{Name: "secrets", MountPath: "/etc/rsyncd", ReadOnly: true},
+						},
+					}},
+					RestartPolicy: corev1.RestartPolicyOnFailure,
+					Volumes: []corev1.Volume{{
+						Name: "data",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name},
+						},
+					}, {
+						Name: "secrets",
+						VolumeSource: corev1.VolumeSource{
+							// The rsync daemon refuses secrets files readable by other users.
+							Secret: &corev1.SecretVolumeSource{SecretName: name, DefaultMode: ptr.To[int32](0o400),
+								Items: []corev1.KeyToPath{{Key: rsyncSecretsKey, Path: rsyncSecretsKey}}},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	if err := m.CRClient.Create(ctx, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// createRsyncClientJob creates the job copying the content of the source PVC to the rsync server.
+func (m *rsyncDataMover) createRsyncClientJob(ctx context.Context, server string, source *corev1.PersistentVolumeClaim) (*batchv1.Job, error) {
+	job := batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "liqo-rsync-client-",
+			Namespace:    source.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			TTLSecondsAfterFinished: ptr.To[int32](10),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: rsyncClientLabels(server)},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:            "rsync",
+						Image:           m.RsyncImage,
+						ImagePullPolicy: corev1.PullIfNotPresent,
+						Command:         []string{"rsync"},
+						// The server is addressed by its short name, since the namespace may be remapped in remote clusters.
+						Args: []string{"-a", "--delete", "/source/", fmt.Sprintf("rsync://%s@%s:%d/volume/", rsyncUser, server, rsyncPort)},
+						Env: []corev1.EnvVar{{
+							Name: "RSYNC_PASSWORD",
+							ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: server}, Key: rsyncPasswordKey}},
+						}},
+						Resources:    m.forgeContainerResources(),
+						VolumeMounts: []corev1.VolumeMount{{Name: "source", MountPath: "/source", ReadOnly: true}},
+					}},
+					RestartPolicy: corev1.RestartPolicyOnFailure,
+					Volumes: []corev1.Volume{{
+						Name: "source",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: source.Name, ReadOnly: true},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	if err := m.CRClient.Create(ctx, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// rsyncClientLabels returns the labels identifying the rsync client connecting to the given server.
+func rsyncClientLabels(server string) map[string]string {
+	return map[string]string{"app": server + "-client"}
+}
+
+// shortUID returns a short identifier of the given PVC, suitable to be used in resource names.
+func shortUID(pvc *corev1.PersistentVolumeClaim) string {
+	uid := string(pvc.GetUID())
+	if len(uid) > 8 {
+		return uid[:8]
+	}
+	return uid
+}
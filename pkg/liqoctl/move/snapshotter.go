@@ -61,12 +61,8 @@ func (o *Options) createSnapshotterJob(ctx context.Context, pvc *corev1.Persiste
 								fmt.Sprintf("%s%s", resticRepositoryURL, pvc.GetUID()),
 								"init",
 							},
-							Env: []corev1.EnvVar{
-								{
-									Name:  "RESTIC_PASSWORD",
-									Value: o.ResticPassword,
-								},
-							},
+							Env:       o.forgeResticEnv(),
+							EnvFrom:   o.forgeResticEnvFrom(),
 							Resources: o.forgeContainerResources(),
 						},
 					},
@@ -81,12 +77,8 @@ func (o *Options) createSnapshotterJob(ctx context.Context, pvc *corev1.Persiste
 								"backup", ".",
 								"--host", "liqo",
 							},
-							Env: []corev1.EnvVar{
-								{
-									Name:  "RESTIC_PASSWORD",
-									Value: o.ResticPassword,
-								},
-							},
+							Env:        o.forgeResticEnv(),
+							EnvFrom:    o.forgeResticEnvFrom(),
 							Resources:  o.forgeContainerResources(),
 							WorkingDir: "/backup",
 							VolumeMounts: []corev1.VolumeMount{
@@ -21,6 +21,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
@@ -66,7 +67,8 @@ func checkNoMounter(ctx context.Context, cl client.Client, pvc *corev1.Persisten
 	return nil
 }
 
-func recreatePvc(ctx context.Context, cl client.Client, oldPvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
+func recreatePvc(ctx context.Context, cl client.Client, oldPvc *corev1.PersistentVolumeClaim,
+	mutators ...func(*corev1.PersistentVolumeClaim)) (*corev1.PersistentVolumeClaim, error) {
 	newPvc := corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      oldPvc.Name,
@@ -76,8 +78,11 @@ func recreatePvc(ctx context.Context, cl client.Client, oldPvc *corev1.Persisten
 		Spec: *oldPvc.Spec.DeepCopy(),
 	}
 	newPvc.Spec.VolumeName = ""
+	for _, mutate := range mutators {
+		mutate(&newPvc)
+	}
 
-	if err := cl.Delete(ctx, oldPvc); err != nil {
+	if err := client.IgnoreNotFound(cl.Delete(ctx, oldPvc)); err != nil {
 		return nil, err
 	}
 
@@ -96,3 +101,11 @@ func recreatePvc(ctx context.Context, cl client.Client, oldPvc *corev1.Persisten
 
 	return &newPvc, nil
 }
+
+func totalStorageRequests(pvcs ...*corev1.PersistentVolumeClaim) resource.Quantity {
+	var total resource.Quantity
+	for _, pvc := range pvcs {
+		total.Add(pvc.Spec.Resources.Requests[corev1.ResourceStorage])
+	}
+	return total
+}
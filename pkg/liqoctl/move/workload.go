@@ -0,0 +1,427 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package move
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
+)
+
+const (
+	// WorkloadKindDeployment is the kind of Deployment workloads.
+	WorkloadKindDeployment = "Deployment"
+	// WorkloadKindStatefulSet is the kind of StatefulSet workloads.
+	WorkloadKindStatefulSet = "StatefulSet"
+)
+
+// WorkloadOptions encapsulates the arguments of the move workload command.
+type WorkloadOptions struct {
+	Options
+
+	WorkloadKind string
+	WorkloadName string
+
+	Timeout time.Duration
+}
+
+// Run implements the move workload command.
+func (o *WorkloadOptions) Run(ctx context.Context) error {
+	// we need a context that is not canceled even if the user press Ctrl+C
+	deferCtx := context.Background()
+
+	s := o.Printer.StartSpinner("Running pre-flight checks")
+
+	workload, err := getWorkload(ctx, o.CRClient, o.Namespace, o.WorkloadKind, o.WorkloadName)
+	if err != nil {
+		s.Fail(fmt.Sprintf("Failed to get %s %s/%s: %v", o.WorkloadKind, o.Namespace, o.WorkloadName, output.PrettyErr(err)))
+		return err
+	}
+
+	if err := o.CRClient.Get(ctx, client.ObjectKey{Name: o.TargetNode}, &corev1.Node{}); err != nil {
+		s.Fail("Failed to get target node: ", output.PrettyErr(err))
+		return err
+	}
+
+	pvcs, err := getWorkloadVolumes(ctx, o.CRClient, workload)
+	if err != nil {
+		s.Fail("Failed to retrieve the volumes of the workload: ", output.PrettyErr(err))
+		return err
+	}
+	// The origin node of each volume, to restore the volumes in case the migration fails after they have been moved.
+	origins := make(map[string]string, len(pvcs))
+	for i := range pvcs {
+		_, origin, err := isLocalVolume(ctx, o.CRClient, pvcs[i])
+		if err != nil {
+			s.Fail("Failed to retrieve the origin of the volume: ", output.PrettyErr(err))
+			return err
+		}
+		origins[pvcs[i].Name] = origin.Name
+	}
+
+	migration, err := o.createWorkloadMigration(ctx)
+	if err != nil {
+		s.Fail("Failed to create the WorkloadMigration: ", output.PrettyErr(err))
+		return err
+	}
+	s.Success("Pre-flight checks passed")
+
+	original := workload.DeepCopyObject().(client.Object)
+	replicas := getReplicas(workload)
+
+	s = o.Printer.StartSpinner("Scaling down the workload")
+	migration.Status.OriginalReplicas = ptr.To(replicas)
+	o.updatePhase(ctx, migration, offloadingv1beta1.WorkloadMigrationPhaseScalingDown, "")
+
+	if err := scaleWorkload(ctx, o.CRClient, workload, 0); err != nil {
+		s.Fail("Failed to scale down the workload: ", output.PrettyErr(err))
+		return o.rollback(deferCtx, migration, original, nil, err)
+	}
+	if err := o.waitForNoPods(ctx, workload); err != nil {
+		s.Fail("Failed to wait for the pods of the workload to be terminated: ", output.PrettyErr(err))
+		return o.rollback(deferCtx, migration, original, nil, err)
+	}
+	s.Success("Workload scaled down")
+
+	o.updatePhase(ctx, migration, offloadingv1beta1.WorkloadMigrationPhaseMovingVolumes, "")
+	if err := o.moveVolumes(ctx, pvcs...); err != nil {
+		// The volumes already moved are restored in their origin node by moveVolumes.
+		return o.rollback(deferCtx, migration, original, nil, err)
+	}
+	for i := range pvcs {
+		migration.Status.MigratedVolumes = append(migration.Status.MigratedVolumes, pvcs[i].Name)
+	}
+
+	s = o.Printer.StartSpinner("Retargeting and scaling up the workload")
+	o.updatePhase(ctx, migration, offloadingv1beta1.WorkloadMigrationPhaseScalingUp, "")
+
+	if err := o.retargetWorkload(ctx, workload, replicas); err != nil {
+		s.Fail("Failed to retarget the workload: ", output.PrettyErr(err))
+		return o.rollback(deferCtx, migration, original, origins, err)
+	}
+	if err := o.waitForReadyReplicas(ctx, workload, replicas); err != nil {
+		s.Fail("Failed to wait for the workload to be ready: ", output.PrettyErr(err))
+		return o.rollback(deferCtx, migration, original, origins, err)
+	}
+	s.Success(fmt.Sprintf("Workload moved to node %q", o.TargetNode))
+
+	o.updatePhase(ctx, migration, offloadingv1beta1.WorkloadMigrationPhaseSucceeded, "")
+	return nil
+}
+
+// rollback restores the original configuration of the workload (i.e., its pod template and number of replicas),
+// after the failure of the migration. In case the volumes have already been moved (i.e., origins is not empty),
+// the workload is scaled down again, and the migrated volumes are moved back to the corresponding origin node.
+func (o *WorkloadOptions) rollback(ctx context.Context, migration *offloadingv1beta1.WorkloadMigration,
+	original client.Object, origins map[string]string, cause error) error {
+	o.updatePhase(ctx, migration, offloadingv1beta1.WorkloadMigrationPhaseRollingBack, cause.Error())
+
+	if len(origins) > 0 && len(migration.Status.MigratedVolumes) > 0 {
+		if err := o.restoreVolumes(ctx, original, migration.Status.MigratedVolumes, origins); err != nil {
+			o.Printer.Error.Printfln("Failed to restore the volumes in their origin node, the workload is left scaled down: %v",
+				output.PrettyErr(err))
+			o.updatePhase(ctx, migration, offloadingv1beta1.WorkloadMigrationPhaseFailed, cause.Error())
+			return cause
+		}
+		migration.Status.MigratedVolumes = nil
+	}
+
+	s := o.Printer.StartSpinner("Rolling back the workload")
+	workload, err := getWorkload(ctx, o.CRClient, original.GetNamespace(), o.WorkloadKind, original.GetName())
+	if err == nil {
+		*getPodTemplate(workload) = *getPodTemplate(original).DeepCopy()
+		setReplicas(workload, getReplicas(original))
+		err = o.CRClient.Update(ctx, workload)
+	}
+	if err != nil {
+		s.Fail("Failed to restore the original placement and replicas of the workload: ", output.PrettyErr(err))
+	} else {
+		s.Success("Workload rolled back")
+	}
+
+	o.updatePhase(ctx, migration, offloadingv1beta1.WorkloadMigrationPhaseFailed, cause.Error())
+	return cause
+}
+
+// restoreVolumes scales down the given workload, and moves the given volumes back to their origin node.
+func (o *WorkloadOptions) restoreVolumes(ctx context.Context, workload client.Object, names []string, origins map[string]string) error {
+	s := o.Printer.StartSpinner("Scaling down the workload")
+	if err := scaleWorkload(ctx, o.CRClient, workload.DeepCopyObject().(client.Object), 0); err != nil {
+		s.Fail("Failed to scale down the workload: ", output.PrettyErr(err))
+		return err
+	}
+	if err := o.waitForNoPods(ctx, workload); err != nil {
+		s.Fail("Failed to wait for the pods of the workload to be terminated: ", output.PrettyErr(err))
+		return err
+	}
+	s.Success("Workload scaled down")
+
+	// Group the volumes by origin node, as each invocation of moveVolumes targets a single node.
+	byOrigin := make(map[string][]*corev1.PersistentVolumeClaim)
+	for _, name := range names {
+		var pvc corev1.PersistentVolumeClaim
+		if err := o.CRClient.Get(ctx, client.ObjectKey{Namespace: workload.GetNamespace(), Name: name}, &pvc); err != nil {
+			return err
+		}
+		byOrigin[origins[name]] = append(byOrigin[origins[name]], &pvc)
+	}
+
+	for origin, pvcs := range byOrigin {
+		opts := o.Options
+		opts.TargetNode = origin
+		if err := opts.moveVolumes(ctx, pvcs...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *WorkloadOptions) createWorkloadMigration(ctx context.Context) (*offloadingv1beta1.WorkloadMigration, error) {
+	migration := &offloadingv1beta1.WorkloadMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-", o.WorkloadName),
+			Namespace:    o.Namespace,
+		},
+		Spec: offloadingv1beta1.WorkloadMigrationSpec{
+			WorkloadRef: offloadingv1beta1.WorkloadReference{Kind: o.WorkloadKind, Name: o.WorkloadName},
+			TargetNode:  o.TargetNode,
+		},
+	}
+
+	if err := o.CRClient.Create(ctx, migration); err != nil {
+		return nil, err
+	}
+
+	o.updatePhase(ctx, migration, offloadingv1beta1.WorkloadMigrationPhasePending, "")
+	return migration, nil
+}
+
+// updatePhase updates the status of the WorkloadMigration. Failures are only reported, since the status
+// is informative and shall not cause the migration to fail.
+func (o *WorkloadOptions) updatePhase(ctx context.Context, migration *offloadingv1beta1.WorkloadMigration,
+	phase offloadingv1beta1.WorkloadMigrationPhase, message string) {
+	migration.Status.Phase = phase
+	migration.Status.Message = message
+	if err := o.CRClient.Status().Update(ctx, migration); err != nil {
+		o.Printer.Warning.Printfln("Failed to update the status of WorkloadMigration %q: %v", migration.Name, output.PrettyErr(err))
+	}
+}
+
+// retargetWorkload constrains the pods of the workload to the target node, and restores the given number of replicas.
+func (o *WorkloadOptions) retargetWorkload(ctx context.Context, workload client.Object, replicas int32) error {
+	if err := o.CRClient.Get(ctx, client.ObjectKeyFromObject(workload), workload); err != nil {
+		return err
+	}
+
+	template := getPodTemplate(workload)
+	if template.Spec.Affinity == nil {
+		template.Spec.Affinity = &corev1.Affinity{}
+	}
+	template.Spec.Affinity.NodeAffinity = retargetNodeAffinity(template.Spec.Affinity.NodeAffinity, o.TargetNode)
+	setReplicas(workload, replicas)
+
+	return o.CRClient.Update(ctx, workload)
+}
+
+// retargetNodeAffinity returns a copy of the given node affinity, with the required terms
+// additionally constrained to the given node (replacing possible previous constraints on the hostname).
+func retargetNodeAffinity(affinity *corev1.NodeAffinity, nodeName string) *corev1.NodeAffinity {
+	requirement := corev1.NodeSelectorRequirement{
+		Key:      corev1.LabelHostname,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   []string{nodeName},
+	}
+
+	affinity = affinity.DeepCopy()
+	if affinity == nil {
+		affinity = &corev1.NodeAffinity{}
+	}
+	if affinity.RequiredDuringSchedulingIgnoredDuringExecution == nil ||
+		len(affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) == 0 {
+		affinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{}},
+		}
+	}
+
+	terms := affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	for i := range terms {
+		expressions := []corev1.NodeSelectorRequirement{requirement}
+		for _, expression := range terms[i].MatchExpressions {
+			if expression.Key != corev1.LabelHostname {
+				expressions = append(expressions, expression)
+			}
+		}
+		terms[i].MatchExpressions = expressions
+	}
+	return affinity
+}
+
+func (o *WorkloadOptions) waitForNoPods(ctx context.Context, workload client.Object) error {
+	selector, err := metav1.LabelSelectorAsSelector(getSelector(workload))
+	if err != nil {
+		return err
+	}
+
+	return wait.PollUntilContextTimeout(ctx, 2*time.Second, o.Timeout, true, func(ctx context.Context) (bool, error) {
+		var pods corev1.PodList
+		if err := o.CRClient.List(ctx, &pods, client.InNamespace(workload.GetNamespace()),
+			client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return false, err
+		}
+		return len(pods.Items) == 0, nil
+	})
+}
+
+func (o *WorkloadOptions) waitForReadyReplicas(ctx context.Context, workload client.Object, replicas int32) error {
+	return wait.PollUntilContextTimeout(ctx, 2*time.Second, o.Timeout, true, func(ctx context.Context) (bool, error) {
+		if err := o.CRClient.Get(ctx, client.ObjectKeyFromObject(workload), workload); err != nil {
+			return false, err
+		}
+		return getReadyReplicas(workload) >= replicas, nil
+	})
+}
+
+// getWorkloadVolumes returns the PVCs mounted by the pods of the given workload.
+func getWorkloadVolumes(ctx context.Context, cl client.Client, workload client.Object) ([]*corev1.PersistentVolumeClaim, error) {
+	var names []string
+	for i := range getPodTemplate(workload).Spec.Volumes {
+		if claim := getPodTemplate(workload).Spec.Volumes[i].PersistentVolumeClaim; claim != nil {
+			names = append(names, claim.ClaimName)
+		}
+	}
+
+	// The PVCs generated from the claim templates of StatefulSets are named <template>-<statefulset>-<ordinal>.
+	if sts, ok := workload.(*appsv1.StatefulSet); ok {
+		for i := range sts.Spec.VolumeClaimTemplates {
+			for ordinal := int32(0); ordinal < getReplicas(sts); ordinal++ {
+				names = append(names, fmt.Sprintf("%s-%s-%d", sts.Spec.VolumeClaimTemplates[i].Name, sts.Name, ordinal))
+			}
+		}
+	}
+
+	pvcs := make([]*corev1.PersistentVolumeClaim, 0, len(names))
+	for _, name := range names {
+		var pvc corev1.PersistentVolumeClaim
+		if err := cl.Get(ctx, client.ObjectKey{Namespace: workload.GetNamespace(), Name: name}, &pvc); err != nil {
+			if apierrors.IsNotFound(err) {
+				// The PVC has not been created yet, hence there is no data to be moved.
+				continue
+			}
+			return nil, err
+		}
+		pvcs = append(pvcs, &pvc)
+	}
+	return pvcs, nil
+}
+
+func getWorkload(ctx context.Context, cl client.Client, namespace, kind, name string) (client.Object, error) {
+	var workload client.Object
+	switch kind {
+	case WorkloadKindDeployment:
+		workload = &appsv1.Deployment{}
+	case WorkloadKindStatefulSet:
+		workload = &appsv1.StatefulSet{}
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %q", kind)
+	}
+
+	if err := cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, workload); err != nil {
+		return nil, err
+	}
+	return workload, nil
+}
+
+func scaleWorkload(ctx context.Context, cl client.Client, workload client.Object, replicas int32) error {
+	if err := cl.Get(ctx, client.ObjectKeyFromObject(workload), workload); err != nil {
+		return err
+	}
+	setReplicas(workload, replicas)
+	return cl.Update(ctx, workload)
+}
+
+func getReplicas(workload client.Object) int32 {
+	var replicas *int32
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		replicas = w.Spec.Replicas
+	case *appsv1.StatefulSet:
+		replicas = w.Spec.Replicas
+	}
+	// The number of replicas defaults to 1 if unset.
+	return ptr.Deref(replicas, 1)
+}
+
+func setReplicas(workload client.Object, replicas int32) {
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		w.Spec.Replicas = ptr.To(replicas)
+	case *appsv1.StatefulSet:
+		w.Spec.Replicas = ptr.To(replicas)
+	}
+}
+
+func getReadyReplicas(workload client.Object) int32 {
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		return w.Status.ReadyReplicas
+	case *appsv1.StatefulSet:
+		return w.Status.ReadyReplicas
+	}
+	return 0
+}
+
+func getPodTemplate(workload client.Object) *corev1.PodTemplateSpec {
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		return &w.Spec.Template
+	case *appsv1.StatefulSet:
+		return &w.Spec.Template
+	}
+	return &corev1.PodTemplateSpec{}
+}
+
+func getSelector(workload client.Object) *metav1.LabelSelector {
+	switch w := workload.(type) {
+	case *appsv1.Deployment:
+		return w.Spec.Selector
+	case *appsv1.StatefulSet:
+		return w.Spec.Selector
+	}
+	return &metav1.LabelSelector{}
+}
+
+// ParseWorkloadKind returns the workload kind corresponding to the given (case insensitive, possibly abbreviated) name.
+func ParseWorkloadKind(kind string) (string, error) {
+	switch strings.ToLower(kind) {
+	case "deployment", "deployments", "deploy":
+		return WorkloadKindDeployment, nil
+	case "statefulset", "statefulsets", "sts":
+		return WorkloadKindStatefulSet, nil
+	default:
+		return "", fmt.Errorf("unsupported workload kind %q (supported: deployment, statefulset)", kind)
+	}
+}
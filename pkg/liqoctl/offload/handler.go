@@ -43,6 +43,7 @@ type Options struct {
 	ClusterSelector          [][]metav1.LabelSelectorRequirement
 
 	OutputFormat string
+	DryRun       string
 
 	Timeout time.Duration
 }
@@ -72,6 +73,11 @@ func (o *Options) Run(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
 	defer cancel()
 
+	// Output the consequences of the offloading, instead of applying it.
+	if o.DryRun == DryRunPlan {
+		return o.plan(ctx)
+	}
+
 	// Output the NamespaceOffloading resource, instead of applying it.
 	if o.OutputFormat != "" {
 		o.Printer.CheckErr(o.output())
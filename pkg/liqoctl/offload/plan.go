@@ -0,0 +1,425 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offload
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	nsoffctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/namespaceoffloading-controller"
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
+	liqoutils "github.com/liqotech/liqo/pkg/utils"
+	foreignclusterutils "github.com/liqotech/liqo/pkg/utils/foreigncluster"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+	virtualnodeutils "github.com/liqotech/liqo/pkg/utils/virtualnode"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/resources"
+	vkforge "github.com/liqotech/liqo/pkg/vkMachinery/forge"
+)
+
+// DryRunPlan is the value of the dry-run flag to output the plan of the offloading, instead of applying it.
+const DryRunPlan = "plan"
+
+// Plan describes the consequences of offloading a namespace, given the current state of the cluster.
+type Plan struct {
+	Clusters []ClusterPlan
+	Pods     []PodPlan
+}
+
+// ClusterPlan describes the consequences of offloading a namespace for a given virtual node.
+type ClusterPlan struct {
+	VirtualNode     string
+	ClusterID       string
+	Selected        bool
+	RemoteNamespace string
+	// Headroom is the amount of resources of the virtual node not yet requested by the pods scheduled on it.
+	Headroom corev1.ResourceList
+	// Reflected contains, for each kind of resource, the reflection type and the names of the objects which would be reflected.
+	Reflected []ReflectionPlan
+}
+
+// ReflectionPlan describes which objects of a given kind would be reflected to a remote cluster.
+type ReflectionPlan struct {
+	Kind      string
+	Type      offloadingv1beta1.ReflectionType
+	Reflected []string
+	Skipped   []string
+}
+
+// PodPlan describes whether a pod could be scheduled on a virtual node, if (re)created.
+type PodPlan struct {
+	Name string
+	// Target is the virtual node the pod would fit in, if any.
+	Target string
+	// Reason explains why the pod would not be offloaded, if no target is found.
+	Reason string
+}
+
+// plan computes and outputs the plan of the offloading, without applying it.
+func (o *Options) plan(ctx context.Context) error {
+	s := o.Printer.StartSpinner(fmt.Sprintf("Computing the offloading plan for namespace %q", o.Namespace))
+	plan, err := o.ComputePlan(ctx)
+	if err != nil {
+		s.Fail(fmt.Sprintf("Failed computing the offloading plan: %v", output.PrettyErr(err)))
+		return err
+	}
+	s.Success(fmt.Sprintf("Offloading plan for namespace %q computed (nothing has been applied)", o.Namespace))
+
+	o.Printer.BoxSetTitle(fmt.Sprintf("Offloading plan for namespace %q", o.Namespace))
+	o.Printer.BoxPrintln(plan.format().SprintForBox(o.Printer))
+	return nil
+}
+
+// ComputePlan computes the plan of the offloading, given the current state of the cluster.
+func (o *Options) ComputePlan(ctx context.Context) (*Plan, error) {
+	virtualNodes, err := getters.ListVirtualNodesByLabels(ctx, o.CRClient, labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve VirtualNodes: %w", err)
+	}
+	sort.Slice(virtualNodes.Items, func(i, j int) bool { return virtualNodes.Items[i].Name < virtualNodes.Items[j].Name })
+
+	remoteNamespace, err := o.remoteNamespaceName(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var services corev1.ServiceList
+	var configmaps corev1.ConfigMapList
+	var secrets corev1.SecretList
+	var pods corev1.PodList
+	for _, list := range []client.ObjectList{&services, &configmaps, &secrets, &pods} {
+		if err := o.CRClient.List(ctx, list, client.InNamespace(o.Namespace)); err != nil {
+			return nil, fmt.Errorf("failed to retrieve the objects in namespace %q: %w", o.Namespace, err)
+		}
+	}
+
+	plan := &Plan{}
+	selector := toNodeSelector(o.ClusterSelector)
+	nodes := map[string]*corev1.Node{}
+	for i := range virtualNodes.Items {
+		vn := &virtualNodes.Items[i]
+		match, err := nsoffctrl.MatchVirtualNodeSelectorTerms(ctx, o.CRClient, vn, &selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cluster selector: %w", err)
+		}
+
+		cluster := ClusterPlan{VirtualNode: vn.Name, ClusterID: string(vn.Spec.ClusterID), Selected: match}
+		if match {
+			if nodes[vn.Name], err = virtualnodeutils.ForgeFakeNodeFromVirtualNode(ctx, o.CRClient, vn); err != nil {
+				return nil, err
+			}
+			if cluster.Headroom, err = o.headroom(ctx, vn); err != nil {
+				return nil, err
+			}
+			cluster.RemoteNamespace = remoteNamespace
+			cluster.Reflected = planReflection(reflectionTypes(vn), services.Items, configmaps.Items, secrets.Items)
+		}
+		plan.Clusters = append(plan.Clusters, cluster)
+	}
+
+	for i := range pods.Items {
+		if pod := o.planPod(&pods.Items[i], plan.Clusters, nodes); pod != nil {
+			plan.Pods = append(plan.Pods, *pod)
+		}
+	}
+
+	return plan, nil
+}
+
+// remoteNamespaceName returns the name of the remote namespace, according to the NamespaceMappingStrategy.
+func (o *Options) remoteNamespaceName(ctx context.Context) (string, error) {
+	switch o.NamespaceMappingStrategy {
+	case offloadingv1beta1.EnforceSameNameMappingStrategyType:
+		return o.Namespace, nil
+	case offloadingv1beta1.SelectedNameMappingStrategyType:
+		return o.RemoteNamespaceName, nil
+	default:
+		clusterID, err := liqoutils.GetClusterIDWithControllerClient(ctx, o.CRClient, corev1.NamespaceAll)
+		if err != nil {
+			return "", fmt.Errorf("failed to retrieve the local cluster ID: %w", err)
+		}
+		return o.Namespace + "-" + foreignclusterutils.UniqueName(clusterID), nil
+	}
+}
+
+// headroom returns the amount of resources of the virtual node not yet requested by the pods scheduled on it.
+func (o *Options) headroom(ctx context.Context, vn *offloadingv1beta1.VirtualNode) (corev1.ResourceList, error) {
+	var pods corev1.PodList
+	if err := o.CRClient.List(ctx, &pods, client.MatchingFields{"spec.nodeName": vn.Name}); err != nil {
+		return nil, fmt.Errorf("failed to retrieve the pods scheduled on virtual node %q: %w", vn.Name, err)
+	}
+
+	headroom := vn.Spec.ResourceQuota.Hard.DeepCopy()
+	if headroom == nil {
+		headroom = corev1.ResourceList{}
+	}
+	for i := range pods.Items {
+		if isTerminated(&pods.Items[i]) {
+			continue
+		}
+		subtract(headroom, podRequests(&pods.Items[i]))
+	}
+	return headroom, nil
+}
+
+// planPod returns whether the given pod could be scheduled on one of the selected virtual nodes, consuming the headroom.
+// It returns nil for pods already terminated.
+func (o *Options) planPod(pod *corev1.Pod, clusters []ClusterPlan, nodes map[string]*corev1.Node) *PodPlan {
+	if isTerminated(pod) {
+		return nil
+	}
+
+	result := &PodPlan{Name: pod.Name}
+	for i := range clusters {
+		if pod.Spec.NodeName == clusters[i].VirtualNode {
+			result.Target, result.Reason = clusters[i].VirtualNode, "already offloaded"
+			return result
+		}
+	}
+
+	switch {
+	case o.PodOffloadingStrategy == offloadingv1beta1.LocalPodOffloadingStrategyType:
+		result.Reason = fmt.Sprintf("the pod offloading strategy is %s", o.PodOffloadingStrategy)
+		return result
+	case isDaemonSetPod(pod):
+		result.Reason = "managed by a DaemonSet"
+		return result
+	case len(nodes) == 0:
+		result.Reason = "no cluster matches the selector"
+		return result
+	}
+
+	requests := podRequests(pod)
+	affinity := nodeaffinity.GetRequiredNodeAffinity(pod)
+	var candidates []string
+	for i := range clusters {
+		node, selected := nodes[clusters[i].VirtualNode]
+		if !selected {
+			continue
+		}
+		if match, _ := affinity.Match(node); !match {
+			continue
+		}
+
+		candidates = append(candidates, clusters[i].VirtualNode)
+		if fits(clusters[i].Headroom, requests) {
+			subtract(clusters[i].Headroom, requests)
+			result.Target = clusters[i].VirtualNode
+			return result
+		}
+	}
+
+	if len(candidates) == 0 {
+		result.Reason = "the node selector/affinity does not match any selected cluster"
+	} else {
+		result.Reason = fmt.Sprintf("does not fit in the available resources of %s", strings.Join(candidates, ", "))
+	}
+	return result
+}
+
+// reflectionTypes returns the reflection types configured for the virtual kubelet of the given virtual node.
+func reflectionTypes(vn *offloadingv1beta1.VirtualNode) map[resources.ResourceReflected]offloadingv1beta1.ReflectionType {
+	types := map[resources.ResourceReflected]offloadingv1beta1.ReflectionType{
+		resources.Service:   offloadingv1beta1.DenyList,
+		resources.ConfigMap: offloadingv1beta1.DenyList,
+		resources.Secret:    offloadingv1beta1.DenyList,
+	}
+	if vn.Spec.Template == nil {
+		return types
+	}
+
+	for i := range vn.Spec.Template.Spec.Template.Spec.Containers {
+		for _, arg := range vn.Spec.Template.Spec.Template.Spec.Containers[i].Args {
+			if !strings.Contains(arg, "=") {
+				continue
+			}
+			key, value := vkforge.DestringifyArgument(arg)
+			for resource := range types {
+				if key == fmt.Sprintf("--%s-reflection-type", resource) {
+					types[resource] = offloadingv1beta1.ReflectionType(value)
+				}
+			}
+		}
+	}
+	return types
+}
+
+// planReflection returns which objects would be reflected to a remote cluster, given the configured reflection types.
+func planReflection(types map[resources.ResourceReflected]offloadingv1beta1.ReflectionType,
+	services []corev1.Service, configmaps []corev1.ConfigMap, secrets []corev1.Secret) []ReflectionPlan {
+	svcPlan := ReflectionPlan{Kind: "Services", Type: types[resources.Service]}
+	for i := range services {
+		svcPlan.add(services[i].Name, shouldReflect(&services[i], svcPlan.Type))
+	}
+
+	cmPlan := ReflectionPlan{Kind: "ConfigMaps", Type: types[resources.ConfigMap]}
+	for i := range configmaps {
+		reflected := shouldReflect(&configmaps[i], cmPlan.Type)
+		// The root CA configmap is reflected independently of the reflection policy, unless explicitly marked otherwise.
+		if configmaps[i].Name == forge.RootCAConfigMapName {
+			reflected = shouldReflect(&configmaps[i], offloadingv1beta1.DenyList)
+		}
+		cmPlan.add(configmaps[i].Name, reflected)
+	}
+
+	secretPlan := ReflectionPlan{Kind: "Secrets", Type: types[resources.Secret]}
+	for i := range secrets {
+		// Secrets containing service account tokens are managed by a dedicated reflector.
+		if secrets[i].Type == corev1.SecretTypeServiceAccountToken {
+			secretPlan.add(secrets[i].Name, false)
+			continue
+		}
+		secretPlan.add(secrets[i].Name, shouldReflect(&secrets[i], secretPlan.Type))
+	}
+
+	return []ReflectionPlan{svcPlan, cmPlan, secretPlan}
+}
+
+func (rp *ReflectionPlan) add(name string, reflected bool) {
+	if reflected {
+		rp.Reflected = append(rp.Reflected, name)
+	} else {
+		rp.Skipped = append(rp.Skipped, name)
+	}
+}
+
+// shouldReflect returns whether the given object would be reflected, according to the reflection type.
+func shouldReflect(obj client.Object, reflectionType offloadingv1beta1.ReflectionType) bool {
+	switch reflectionType {
+	case offloadingv1beta1.AllowList:
+		value, ok := obj.GetAnnotations()[consts.AllowReflectionAnnotationKey]
+		return ok && !strings.EqualFold(value, "false")
+	case offloadingv1beta1.DenyList:
+		value, ok := obj.GetAnnotations()[consts.SkipReflectionAnnotationKey]
+		return !ok || strings.EqualFold(value, "false")
+	default:
+		return false
+	}
+}
+
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests, _ := resourcehelper.PodRequestsAndLimits(pod)
+	requests[corev1.ResourcePods] = *resource.NewQuantity(1, resource.DecimalSI)
+	return requests
+}
+
+// fits returns whether the given requests fit in the headroom. Resources not limited by the headroom are ignored.
+func fits(headroom, requests corev1.ResourceList) bool {
+	for key, value := range requests {
+		if available, found := headroom[key]; found && available.Cmp(value) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func subtract(headroom, requests corev1.ResourceList) {
+	for key, value := range requests {
+		if available, found := headroom[key]; found {
+			available.Sub(value)
+			headroom[key] = available
+		}
+	}
+}
+
+func isTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+func isDaemonSetPod(pod *corev1.Pod) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == "DaemonSet" && ref.APIVersion == appsv1.SchemeGroupVersion.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// format returns the plan formatted as an output section.
+func (p *Plan) format() output.Section {
+	main := output.NewRootSection()
+
+	clusters := main.AddSection("Target clusters")
+	for i := range p.Clusters {
+		cluster := &p.Clusters[i]
+		if !cluster.Selected {
+			clusters.AddSectionFailure(cluster.VirtualNode).AddEntry("Cluster ID", cluster.ClusterID).
+				AddEntry("Status", "not matching the cluster selector")
+			continue
+		}
+
+		section := clusters.AddSectionSuccess(cluster.VirtualNode)
+		section.AddEntry("Cluster ID", cluster.ClusterID)
+		section.AddEntry("Remote namespace", cluster.RemoteNamespace)
+		section.AddEntry("Headroom", formatResources(cluster.Headroom))
+		for _, rp := range cluster.Reflected {
+			reflection := section.AddSectionWithDetail(rp.Kind, string(rp.Type))
+			reflection.AddEntry("Reflected", orNone(rp.Reflected)...)
+			reflection.AddEntryWarning("Not reflected", orNone(rp.Skipped)...)
+		}
+	}
+
+	pods := main.AddSectionWithDetail("Pods", "placement if (re)created, as existing pods are not rescheduled")
+	for i := range p.Pods {
+		switch {
+		case p.Pods[i].Reason != "" && p.Pods[i].Target != "":
+			pods.AddEntry(p.Pods[i].Name, fmt.Sprintf("%s (%s)", p.Pods[i].Target, p.Pods[i].Reason))
+		case p.Pods[i].Target != "":
+			pods.AddEntry(p.Pods[i].Name, fmt.Sprintf("schedulable on %s", p.Pods[i].Target))
+		default:
+			pods.AddEntryWarning(p.Pods[i].Name, fmt.Sprintf("not offloaded: %s", p.Pods[i].Reason))
+		}
+	}
+
+	return main
+}
+
+func formatResources(resources corev1.ResourceList) string {
+	if len(resources) == 0 {
+		return "unknown"
+	}
+
+	var names []string
+	for key := range resources {
+		names = append(names, string(key))
+	}
+	sort.Strings(names)
+
+	var formatted []string
+	for _, name := range names {
+		value := resources[corev1.ResourceName(name)]
+		formatted = append(formatted, fmt.Sprintf("%s=%s", name, value.String()))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func orNone(values []string) []string {
+	if len(values) == 0 {
+		return []string{"none"}
+	}
+	return values
+}
@@ -0,0 +1,190 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offload_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqoctl/factory"
+	"github.com/liqotech/liqo/pkg/liqoctl/offload"
+)
+
+var _ = Describe("Offloading plan", func() {
+	const namespace = "foo"
+
+	var (
+		ctx     context.Context
+		objects []client.Object
+		options offload.Options
+		plan    *offload.Plan
+		err     error
+	)
+
+	forgeVirtualNode := func(name, region string, args ...string) *offloadingv1beta1.VirtualNode {
+		return &offloadingv1beta1.VirtualNode{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "liqo-tenant-" + name},
+			Spec: offloadingv1beta1.VirtualNodeSpec{
+				ClusterID: liqov1beta1.ClusterID("cluster-" + name),
+				Labels:    map[string]string{"region": region},
+				ResourceQuota: corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("4Gi"),
+				}},
+				Template: &offloadingv1beta1.DeploymentTemplate{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "virtual-kubelet", Args: args}}},
+				}}},
+			},
+		}
+	}
+
+	forgePod := func(name, nodeName, cpu string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: corev1.PodSpec{NodeName: nodeName, Containers: []corev1.Container{{Name: "main", Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+			}}}},
+		}
+	}
+
+	podPlan := func(name string) offload.PodPlan {
+		for _, pod := range plan.Pods {
+			if pod.Name == name {
+				return pod
+			}
+		}
+		Fail("pod " + name + " not found in the plan")
+		return offload.PodPlan{}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		daemonset := forgePod("daemonset", "", "100m")
+		daemonset.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "ds", UID: "uid"}}
+		completed := forgePod("completed", "", "100m")
+		completed.Status.Phase = corev1.PodSucceeded
+
+		objects = []client.Object{
+			forgeVirtualNode("europe", "europe"),
+			forgeVirtualNode("us", "us", "--service-reflection-type=AllowList"),
+			forgePod("running-on-europe", "europe", "1"),
+			forgePod("small", "", "500m"),
+			forgePod("large", "", "1500m"),
+			daemonset, completed,
+			&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: namespace}},
+			&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc-skipped", Namespace: namespace,
+				Annotations: map[string]string{consts.SkipReflectionAnnotationKey: "true"}}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "kube-root-ca.crt", Namespace: namespace}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: namespace}, Type: corev1.SecretTypeServiceAccountToken},
+		}
+
+		options = offload.Options{
+			Namespace:                namespace,
+			PodOffloadingStrategy:    offloadingv1beta1.LocalAndRemotePodOffloadingStrategyType,
+			NamespaceMappingStrategy: offloadingv1beta1.EnforceSameNameMappingStrategyType,
+		}
+	})
+
+	JustBeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(offloadingv1beta1.AddToScheme(scheme)).To(Succeed())
+
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+			WithIndex(&corev1.Pod{}, "spec.nodeName", func(obj client.Object) []string { return []string{obj.(*corev1.Pod).Spec.NodeName} }).
+			Build()
+		options.Factory = &factory.Factory{CRClient: cl}
+		plan, err = options.ComputePlan(ctx)
+	})
+
+	When("a single cluster matches the selector", func() {
+		BeforeEach(func() { Expect(options.ParseClusterSelectors([]string{"region=europe"})).To(Succeed()) })
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should select the matching clusters only", func() {
+			Expect(plan.Clusters).To(HaveLen(2))
+			Expect(plan.Clusters[0].Selected).To(BeTrue())
+			Expect(plan.Clusters[0].RemoteNamespace).To(Equal(namespace))
+			Expect(plan.Clusters[1].Selected).To(BeFalse())
+		})
+		It("should compute the headroom, considering the pods already offloaded", func() {
+			Expect(plan.Clusters[0].Headroom.Cpu().MilliValue()).To(BeNumerically("==", 500))
+		})
+		It("should place the pods fitting in the headroom", func() {
+			Expect(podPlan("small").Target).To(Equal("europe"))
+			Expect(podPlan("small").Reason).To(BeEmpty())
+		})
+		It("should report the pods not fitting in the headroom", func() {
+			Expect(podPlan("large").Target).To(BeEmpty())
+			Expect(podPlan("large").Reason).To(ContainSubstring("does not fit"))
+		})
+		It("should report the pods not offloaded", func() {
+			Expect(podPlan("daemonset").Reason).To(ContainSubstring("DaemonSet"))
+			Expect(podPlan("running-on-europe").Reason).To(Equal("already offloaded"))
+			Expect(plan.Pods).ToNot(ContainElement(HaveField("Name", "completed")))
+		})
+		It("should report the reflected objects", func() {
+			reflected := plan.Clusters[0].Reflected
+			Expect(reflected).To(HaveLen(3))
+			Expect(reflected[0].Type).To(Equal(offloadingv1beta1.DenyList))
+			Expect(reflected[0].Reflected).To(ConsistOf("svc"))
+			Expect(reflected[0].Skipped).To(ConsistOf("svc-skipped"))
+			Expect(reflected[1].Reflected).To(ConsistOf("kube-root-ca.crt"))
+			Expect(reflected[2].Skipped).To(ConsistOf("token"))
+		})
+	})
+
+	When("the selected cluster is configured with an AllowList policy", func() {
+		BeforeEach(func() {
+			options.NamespaceMappingStrategy = offloadingv1beta1.SelectedNameMappingStrategyType
+			options.RemoteNamespaceName = "bar"
+			Expect(options.ParseClusterSelectors([]string{"region=us"})).To(Succeed())
+		})
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should use the selected remote namespace name", func() {
+			Expect(plan.Clusters[1].RemoteNamespace).To(Equal("bar"))
+		})
+		It("should not reflect the services without the allow annotation", func() {
+			Expect(plan.Clusters[1].Reflected[0].Type).To(Equal(offloadingv1beta1.AllowList))
+			Expect(plan.Clusters[1].Reflected[0].Reflected).To(BeEmpty())
+		})
+		It("should place the pods on the selected cluster", func() {
+			Expect(podPlan("small").Target).To(Equal("us"))
+			Expect(podPlan("large").Target).To(Equal("us"))
+		})
+	})
+
+	When("the pod offloading strategy is Local", func() {
+		BeforeEach(func() { options.PodOffloadingStrategy = offloadingv1beta1.LocalPodOffloadingStrategyType })
+
+		It("should not offload any pod", func() {
+			Expect(podPlan("small").Target).To(BeEmpty())
+			Expect(podPlan("small").Reason).To(ContainSubstring("Local"))
+		})
+	})
+})
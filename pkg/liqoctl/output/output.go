@@ -129,6 +129,15 @@ func (p *Printer) AskConfirm(cmdName string, skip bool) error {
 	return nil
 }
 
+// AskConfirmQuestion asks the user to positively answer the given question, returning an error otherwise.
+func (p *Printer) AskConfirmQuestion(question string) error {
+	r, e := confirm.Show(question)
+	if e != nil || !r {
+		return errors.New("action aborted")
+	}
+	return nil
+}
+
 // BoxPrintln prints a message through the box printer.
 func (p *Printer) BoxPrintln(text string) {
 	// create a string long as the box width
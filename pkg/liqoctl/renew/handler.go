@@ -0,0 +1,154 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renew
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqoctl/factory"
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
+	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
+	liqoutils "github.com/liqotech/liqo/pkg/utils"
+	argsutils "github.com/liqotech/liqo/pkg/utils/args"
+)
+
+// Options encapsulates the arguments of the renew command.
+type Options struct {
+	*factory.Factory
+
+	Name      string
+	ClusterID argsutils.ClusterIDFlags
+	Duration  time.Duration
+
+	Timeout time.Duration
+}
+
+// NewOptions returns a new Options struct.
+func NewOptions(f *factory.Factory) *Options {
+	return &Options{
+		Factory: f,
+	}
+}
+
+// LeaseRenewName returns the name of the Renew requesting the renewal of the lease of the given ResourceSlice.
+func LeaseRenewName(resourceSliceName string) string {
+	return resourceSliceName + "-lease"
+}
+
+// TenantLeaseRenewName is the name of the Renew requesting the renewal of the lease of the Tenant.
+const TenantLeaseRenewName = "tenant-lease"
+
+// RunRenewResourceSlice renews the lease of a ResourceSlice.
+func (o *Options) RunRenewResourceSlice(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	ns, err := o.tenantNamespace(ctx)
+	if err != nil {
+		return err
+	}
+
+	var rs authv1beta1.ResourceSlice
+	if err := o.CRClient.Get(ctx, client.ObjectKey{Name: o.Name, Namespace: ns}, &rs); err != nil {
+		o.Printer.CheckErr(fmt.Errorf("unable to get ResourceSlice: %v", output.PrettyErr(err)))
+		return err
+	}
+
+	return o.renewLease(ctx, LeaseRenewName(rs.Name), ns, &corev1.LocalObjectReference{Name: rs.Name},
+		fmt.Sprintf("ResourceSlice %q", o.Name))
+}
+
+// RunRenewTenant renews the lease of the Tenant of the local cluster in the provider cluster.
+func (o *Options) RunRenewTenant(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
+	defer cancel()
+
+	ns, err := o.tenantNamespace(ctx)
+	if err != nil {
+		return err
+	}
+
+	return o.renewLease(ctx, TenantLeaseRenewName, ns, nil, fmt.Sprintf("Tenant in cluster %q", o.ClusterID.GetClusterID()))
+}
+
+// tenantNamespace returns the name of the tenant namespace of the provider cluster.
+func (o *Options) tenantNamespace(ctx context.Context) (string, error) {
+	namespaceManager := tenantnamespace.NewManager(o.Factory.KubeClient, o.Factory.CRClient.Scheme())
+
+	ns, err := namespaceManager.GetNamespace(ctx, o.ClusterID.GetClusterID())
+	if err != nil {
+		o.Printer.CheckErr(fmt.Errorf("unable to get tenant namespace: %v", output.PrettyErr(err)))
+		return "", err
+	}
+	return ns.Name, nil
+}
+
+// renewLease requests the provider cluster to renew the lease of the given ResourceSlice (or of the Tenant, if nil),
+// and waits for the request to be handled.
+func (o *Options) renewLease(ctx context.Context, name, namespace string, rsRef *corev1.LocalObjectReference, subject string) error {
+	localClusterID, err := liqoutils.GetClusterIDWithControllerClient(ctx, o.CRClient, o.LiqoNamespace)
+	if err != nil {
+		o.Printer.CheckErr(fmt.Errorf("an error occurred while retrieving cluster identity: %v", output.PrettyErr(err)))
+		return err
+	}
+
+	renew := &authv1beta1.Renew{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, o.CRClient, renew, func() error {
+		if renew.Labels == nil {
+			renew.Labels = make(map[string]string)
+		}
+		renew.Labels[consts.ReplicationRequestedLabel] = consts.ReplicationRequestedLabelValue
+		renew.Labels[consts.ReplicationDestinationLabel] = string(o.ClusterID.GetClusterID())
+		renew.Labels[consts.RemoteClusterID] = string(o.ClusterID.GetClusterID())
+
+		renew.Spec.ConsumerClusterID = localClusterID
+		renew.Spec.ResourceSliceRef = rsRef
+		renew.Spec.LeaseDuration = &metav1.Duration{Duration: o.Duration}
+		return nil
+	}); err != nil {
+		o.Printer.CheckErr(fmt.Errorf("unable to request the lease renewal: %v", output.PrettyErr(err)))
+		return err
+	}
+
+	// Wait for the provider cluster to handle the request.
+	s := o.Printer.StartSpinner("Waiting for the lease to be renewed by the provider cluster")
+	if err := wait.PollUntilContextCancel(ctx, time.Second, true, func(ctx context.Context) (bool, error) {
+		if err := o.CRClient.Get(ctx, client.ObjectKeyFromObject(renew), renew); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		return renew.Status.ObservedGeneration == renew.Generation, nil
+	}); err != nil {
+		s.Fail(fmt.Sprintf("Unable to renew the lease of %s: %v", subject, output.PrettyErr(err)))
+		return err
+	}
+
+	if renew.Status.LeaseExpiration == nil {
+		s.Success(fmt.Sprintf("Lease of %s renewed: it is not time-bounded", subject))
+	} else {
+		s.Success(fmt.Sprintf("Lease of %s renewed until %s", subject, renew.Status.LeaseExpiration.Format(time.RFC3339)))
+	}
+
+	return nil
+}
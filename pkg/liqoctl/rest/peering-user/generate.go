@@ -17,15 +17,19 @@ package peeringuser
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/util/runtime"
 
 	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	"github.com/liqotech/liqo/pkg/liqoctl/completion"
 	"github.com/liqotech/liqo/pkg/liqoctl/output"
 	"github.com/liqotech/liqo/pkg/liqoctl/rest"
 	"github.com/liqotech/liqo/pkg/liqoctl/rest/peering-user/userfactory"
 	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
+	"github.com/liqotech/liqo/pkg/utils/args"
+	"github.com/liqotech/liqo/pkg/utils/csr"
 )
 
 const liqoctlGeneratePeeringUserHelp = `Generate a new user with the permissions to peer with this cluster.
@@ -33,11 +37,27 @@ const liqoctlGeneratePeeringUserHelp = `Generate a new user with the permissions
 This command generates a user with the minimum permissions to peer with this cluster, from the cluster with
 the given cluster ID, and returns a kubeconfig to be used to create or destroy the peering.
 
+By default, the user is granted all the permissions required by liqoctl peer. A narrower profile can be selected,
+whose permissions are bound only in the tenant namespace of the remote cluster (plus read-only access to the
+Liqo configuration, for the networking and authentication profiles):
+  * networking: establish the network connectivity (liqoctl network)
+  * authentication: authenticate with this cluster (liqoctl authenticate)
+  * offloading: manage the ResourceSlices requesting resources from this cluster
+  * observer: read-only access to the status of the peering, without access to the secrets
+
+The private key of the user is generated with the Ed25519 algorithm by default, while ECDSA (P-256) and RSA (2048 bits)
+keys can be requested for compatibility with the tools not supporting it.
+
 Examples:
-  $ {{ .Executable }} generate peering-user --consumer-cluster-id=<cluster-id>`
+  $ {{ .Executable }} generate peering-user --consumer-cluster-id=<cluster-id>
+or
+  $ {{ .Executable }} generate peering-user --consumer-cluster-id=<cluster-id> --profile=observer --key-algorithm=ECDSA`
 
 // Generate generates a Nonce.
 func (o *Options) Generate(ctx context.Context, options *rest.GenerateOptions) *cobra.Command {
+	o.profile = args.NewEnum(userfactory.Profiles(), string(userfactory.ProfileFull))
+	o.keyAlgorithm = args.NewEnum(csr.KeyAlgorithms(), string(csr.KeyAlgorithmEd25519))
+
 	cmd := &cobra.Command{
 		Use:   "peering-user",
 		Short: "Generate a new user with the permissions to peer with this cluster",
@@ -57,7 +77,14 @@ func (o *Options) Generate(ctx context.Context, options *rest.GenerateOptions) *
 
 	cmd.Flags().Var(&o.clusterID, "consumer-cluster-id", "The cluster ID of the cluster from which peering will be performed")
 
+	cmd.Flags().Var(o.profile, "profile", fmt.Sprintf("The set of permissions granted to the user. Supported profiles: %s",
+		strings.Join(userfactory.Profiles(), ", ")))
+	cmd.Flags().Var(o.keyAlgorithm, "key-algorithm", fmt.Sprintf("The algorithm of the private key of the user. Supported algorithms: %s",
+		strings.Join(csr.KeyAlgorithms(), ", ")))
+
 	runtime.Must(cmd.MarkFlagRequired("consumer-cluster-id"))
+	runtime.Must(cmd.RegisterFlagCompletionFunc("profile", completion.Enumeration(o.profile.Allowed)))
+	runtime.Must(cmd.RegisterFlagCompletionFunc("key-algorithm", completion.Enumeration(o.keyAlgorithm.Allowed)))
 
 	return cmd
 }
@@ -78,12 +105,14 @@ func (o *Options) handleGenerate(ctx context.Context) error {
 	}
 
 	spinner := opts.Printer.StartSpinner("Generating a user for peering with this cluster")
-	kubeconfig, err := userfactory.GeneratePeerUser(ctx, clusterID, tenantNs.Name, opts.Factory)
+	profile := userfactory.Profile(o.profile.Value)
+	keyAlgorithm := csr.KeyAlgorithm(o.keyAlgorithm.Value)
+	kubeconfig, err := userfactory.GeneratePeerUser(ctx, clusterID, tenantNs.Name, profile, keyAlgorithm, opts.Factory)
 	if err != nil {
 		spinner.Fail(err)
 		return err
 	}
-	spinner.Success("User generated successfully")
+	spinner.Success(fmt.Sprintf("User with profile %q generated successfully", profile))
 
 	fmt.Println(kubeconfig)
 	return nil
@@ -16,13 +16,150 @@ package peeringuser
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/runtime"
 
+	"github.com/liqotech/liqo/pkg/liqoctl/completion"
+	"github.com/liqotech/liqo/pkg/liqoctl/output"
 	"github.com/liqotech/liqo/pkg/liqoctl/rest"
+	"github.com/liqotech/liqo/pkg/liqoctl/rest/peering-user/userfactory"
 )
 
+const liqoctlGetPeeringUserLongHelp = `List the users generated to peer with this cluster.
+
+This command lists the peering users, along with the cluster they have been generated for, the profile
+they have been granted and the expiration of their credentials.
+
+When the path of an API server audit log (in the JSON lines format) is provided, the listing also reports
+the number of requests performed with the credentials of each user, the addresses they have been performed
+from and the time of the most recent one. Note that only the requests recorded by the audit policy of the
+API server are accounted for.
+
+Examples:
+  $ {{ .Executable }} get peering-user
+or
+  $ {{ .Executable }} get peering-user --consumer-cluster-id=<cluster-id> --audit-log=/var/log/kubernetes/audit.log`
+
 // Get implements the get command.
-func (o *Options) Get(_ context.Context, _ *rest.GetOptions) *cobra.Command {
-	panic("not implemented")
+func (o *Options) Get(ctx context.Context, options *rest.GetOptions) *cobra.Command {
+	o.getOptions = options
+
+	cmd := &cobra.Command{
+		Use:     "peering-user",
+		Aliases: []string{"peering-users"},
+		Short:   "List the users generated to peer with this cluster",
+		Long:    liqoctlGetPeeringUserLongHelp,
+		Args:    cobra.NoArgs,
+
+		PreRun: func(_ *cobra.Command, _ []string) {
+			o.getOptions = options
+		},
+
+		Run: func(_ *cobra.Command, _ []string) {
+			output.ExitOnErr(o.handleGet(ctx))
+		},
+	}
+
+	cmd.Flags().Var(&o.clusterID, "consumer-cluster-id", "Show only the user generated for the cluster with the given cluster ID")
+	cmd.Flags().StringVar(&o.auditLog, "audit-log", "", "The path of the API server audit log to retrieve the usage of the users from")
+
+	runtime.Must(cmd.RegisterFlagCompletionFunc("consumer-cluster-id", completion.ClusterIDs(ctx,
+		o.getOptions.Factory, completion.NoLimit)))
+
+	return cmd
+}
+
+func (o *Options) handleGet(ctx context.Context) error {
+	opts := o.getOptions
+
+	users, err := userfactory.ListPeerUsers(ctx, opts.CRClient)
+	if err != nil {
+		opts.Printer.CheckErr(fmt.Errorf("unable to list peering users: %v", output.PrettyErr(err)))
+		return err
+	}
+
+	if clusterID := o.clusterID.GetClusterID(); clusterID != "" {
+		filtered := users[:0]
+		for i := range users {
+			if users[i].ClusterID == clusterID {
+				filtered = append(filtered, users[i])
+			}
+		}
+		users = filtered
+	}
+
+	if len(users) == 0 {
+		opts.Printer.Info.Println("No peering users found")
+		return nil
+	}
+
+	var usages map[string]*userfactory.Usage
+	if o.auditLog != "" {
+		if usages, err = o.readUsages(users); err != nil {
+			opts.Printer.CheckErr(err)
+			return err
+		}
+	}
+
+	header := []string{"Cluster ID", "Profile", "Tenant namespace", "Created", "Expiration"}
+	if usages != nil {
+		header = append(header, "Requests", "Last seen", "Source IPs")
+	}
+
+	data := pterm.TableData{header}
+	for i := range users {
+		user := &users[i]
+		row := []string{string(user.ClusterID), string(user.Profile), user.TenantNamespace,
+			formatTime(&user.CreationTime), formatTime(user.Expiration)}
+
+		if usages != nil {
+			usage := usages[user.CommonName]
+			if usage == nil || usage.Requests == 0 {
+				row = append(row, "0", "never", "-")
+			} else {
+				row = append(row, strconv.Itoa(usage.Requests), formatTime(&usage.LastSeen), strings.Join(usage.SourceIPs, ", "))
+			}
+		}
+		data = append(data, row)
+	}
+
+	return opts.Printer.Table.WithData(data).Render()
+}
+
+// readUsages retrieves the usage of the given users from the configured audit log.
+func (o *Options) readUsages(users []userfactory.PeerUser) (map[string]*userfactory.Usage, error) {
+	file, err := os.Open(filepath.Clean(o.auditLog))
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the audit log: %w", err)
+	}
+	defer file.Close()
+
+	commonNames := make([]string, 0, len(users))
+	for i := range users {
+		if users[i].CommonName != "" {
+			commonNames = append(commonNames, users[i].CommonName)
+		}
+	}
+
+	usages, err := userfactory.ParseAuditLog(file, commonNames)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the audit log: %w", err)
+	}
+	return usages, nil
+}
+
+// formatTime returns the given time in a human-readable format.
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.RFC3339)
 }
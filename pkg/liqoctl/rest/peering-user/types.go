@@ -23,10 +23,14 @@ import (
 // Options encapsulates the arguments of the token command.
 type Options struct {
 	generateOptions  *rest.GenerateOptions
+	getOptions       *rest.GetOptions
 	deleteOptions    *rest.DeleteOptions
 	namespaceManager tenantnamespace.Manager
 
-	clusterID args.ClusterIDFlags
+	clusterID    args.ClusterIDFlags
+	profile      *args.StringEnum
+	keyAlgorithm *args.StringEnum
+	auditLog     string
 }
 
 var _ rest.API = &Options{}
@@ -40,6 +44,7 @@ func PeeringUser() rest.API {
 func (o *Options) APIOptions() *rest.APIOptions {
 	return &rest.APIOptions{
 		EnableGenerate: true,
+		EnableGet:      true,
 		EnableDelete:   true,
 	}
 }
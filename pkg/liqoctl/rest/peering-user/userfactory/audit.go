@@ -0,0 +1,105 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userfactory
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// maxAuditEventSize is the maximum size of a single event in the audit log.
+const maxAuditEventSize = 1 << 20
+
+// Usage summarizes the requests performed with the credentials of a peering user, as recorded in the API server audit log.
+type Usage struct {
+	// SourceIPs are the addresses the requests have been performed from.
+	SourceIPs []string
+	// Requests is the number of requests performed.
+	Requests int
+	// LastSeen is the time of the most recent request.
+	LastSeen time.Time
+}
+
+// ParseAuditLog returns the usage of the given users, identified by the common name of their certificate,
+// reading the events from an API server audit log in the JSON lines format.
+func ParseAuditLog(r io.Reader, commonNames []string) (map[string]*Usage, error) {
+	usages := make(map[string]*Usage, len(commonNames))
+	for _, cn := range commonNames {
+		usages[cn] = &Usage{}
+	}
+
+	sourceIPs := map[string]map[string]struct{}{}
+	auditIDs := map[string]map[types.UID]struct{}{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxAuditEventSize)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var event auditv1.Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("invalid audit event at line %d: %w", line, err)
+		}
+
+		usage, found := usages[event.User.Username]
+		if !found {
+			continue
+		}
+
+		// The same request may be recorded once per stage: count it only once.
+		if _, ok := auditIDs[event.User.Username]; !ok {
+			auditIDs[event.User.Username] = map[types.UID]struct{}{}
+			sourceIPs[event.User.Username] = map[string]struct{}{}
+		}
+		if _, ok := auditIDs[event.User.Username][event.AuditID]; !ok {
+			auditIDs[event.User.Username][event.AuditID] = struct{}{}
+			usage.Requests++
+		}
+
+		for _, ip := range event.SourceIPs {
+			sourceIPs[event.User.Username][ip] = struct{}{}
+		}
+
+		timestamp := event.StageTimestamp.Time
+		if timestamp.IsZero() {
+			timestamp = event.RequestReceivedTimestamp.Time
+		}
+		if timestamp.After(usage.LastSeen) {
+			usage.LastSeen = timestamp
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the audit log: %w", err)
+	}
+
+	for cn, ips := range sourceIPs {
+		for ip := range ips {
+			usages[cn].SourceIPs = append(usages[cn].SourceIPs, ip)
+		}
+		sort.Strings(usages[cn].SourceIPs)
+	}
+	return usages, nil
+}
@@ -17,8 +17,6 @@ package userfactory
 
 import (
 	"context"
-	"crypto/ed25519"
-	"crypto/rand"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
@@ -41,8 +39,10 @@ import (
 	kubeconfigutils "github.com/liqotech/liqo/pkg/utils/kubeconfig"
 )
 
-// GeneratePeerUser generates a new user to peer with the local cluster and returns its kubeconfig.
-func GeneratePeerUser(ctx context.Context, clusterID liqov1beta1.ClusterID, tenantNsName string, opts *factory.Factory) (string, error) {
+// GeneratePeerUser generates a new user, granted the permissions of the given profile, to peer with the local cluster and returns its kubeconfig.
+// The private key of the user is generated with the given algorithm.
+func GeneratePeerUser(ctx context.Context, clusterID liqov1beta1.ClusterID, tenantNsName string, profile Profile,
+	keyAlgorithm certificateSigningRequest.KeyAlgorithm, opts *factory.Factory) (string, error) {
 	if exists, err := IsExistingPeerUser(ctx, opts.CRClient, clusterID); err != nil {
 		return "", fmt.Errorf("unable to check if the user already exists: %w", err)
 	} else if exists {
@@ -57,7 +57,7 @@ func GeneratePeerUser(ctx context.Context, clusterID liqov1beta1.ClusterID, tena
 	}
 
 	// Forge a new pair of keys.
-	_, private, err := ed25519.GenerateKey(rand.Reader)
+	private, err := certificateSigningRequest.NewKey(keyAlgorithm)
 	if err != nil {
 		return "", fmt.Errorf("error while generating token credentials: %w", err)
 	}
@@ -69,12 +69,12 @@ func GeneratePeerUser(ctx context.Context, clusterID liqov1beta1.ClusterID, tena
 	}
 
 	// Sign the csr to generate the certificate
-	cert, err := generateSignedCert(ctx, opts.CRClient, opts.KubeClient, csr, clusterID)
+	cert, err := generateSignedCert(ctx, opts.CRClient, opts.KubeClient, csr, clusterID, profile)
 	if err != nil {
 		return "", fmt.Errorf("unable to generate certificate for the user: %w", err)
 	}
 
-	if err := EnsureRoles(ctx, opts.CRClient, clusterID, userCN, tenantNsName); err != nil {
+	if err := EnsureRoles(ctx, opts.CRClient, clusterID, userCN, tenantNsName, profile); err != nil {
 		return "", fmt.Errorf("unable to ensure roles: %w", err)
 	}
 
@@ -135,13 +135,16 @@ func generateSignedCert(
 	clientset kubernetes.Interface,
 	csr []byte,
 	clusterID liqov1beta1.ClusterID,
+	profile Profile,
 ) ([]byte, error) {
 	userName := GetUserNameFromClusterID(clusterID)
 	cert := &certv1.CertificateSigningRequest{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: userName,
 			Labels: map[string]string{
-				consts.PeeringUserNameLabelKey: userName,
+				consts.PeeringUserNameLabelKey:    userName,
+				consts.PeeringUserProfileLabelKey: string(profile),
+				consts.RemoteClusterID:            string(clusterID),
 			},
 		},
 		Spec: certv1.CertificateSigningRequestSpec{
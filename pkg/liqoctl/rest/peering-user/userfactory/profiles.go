@@ -0,0 +1,81 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userfactory
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+// Profile is a named set of permissions which can be granted to a peering user on its tenant namespace.
+type Profile string
+
+const (
+	// ProfileFull grants all the permissions required to peer with the local cluster via liqoctl peer.
+	ProfileFull Profile = "full"
+	// ProfileNetworking grants the permissions required to establish the network connectivity via liqoctl network.
+	ProfileNetworking Profile = "networking"
+	// ProfileAuthentication grants the permissions required to authenticate via liqoctl authenticate.
+	ProfileAuthentication Profile = "authentication"
+	// ProfileOffloading grants the permissions required to manage the ResourceSlices in the tenant namespace.
+	ProfileOffloading Profile = "offloading"
+	// ProfileObserver grants the read-only permissions to inspect the status of the peering, without access to the secrets.
+	ProfileObserver Profile = "observer"
+)
+
+// Profiles returns the names of the supported peering profiles.
+func Profiles() []string {
+	return []string{string(ProfileFull), string(ProfileNetworking), string(ProfileAuthentication),
+		string(ProfileOffloading), string(ProfileObserver)}
+}
+
+// grantsLiqoNsReader returns whether the profile requires to read the configuration stored in the Liqo namespace
+// (e.g., the cluster ID and the gateway templates), in addition to the permissions on the tenant namespace.
+func (p Profile) grantsLiqoNsReader() bool {
+	return p == ProfileFull || p == ProfileNetworking || p == ProfileAuthentication
+}
+
+// clusterRoleSelector returns the options to select the ClusterRole with the permissions of the profile.
+func (p Profile) clusterRoleSelector() *client.ListOptions {
+	if p == ProfileFull {
+		return &peeringUserLabel
+	}
+
+	return &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{
+			"app.kubernetes.io/component":     "peering-user-profile",
+			consts.PeeringUserProfileLabelKey: string(p),
+		}),
+	}
+}
+
+// tenantNsRoleBindingName returns the name of the RoleBinding granting the permissions of the profile in the tenant namespace.
+func (p Profile) tenantNsRoleBindingName(userName string) string {
+	if p == ProfileFull {
+		return userName + "-tenant-ns-writer"
+	}
+	return userName + "-tenant-ns-" + string(p)
+}
+
+// profileFromLabels returns the profile stored in the given labels, defaulting to the full one for users
+// generated before the introduction of the profiles.
+func profileFromLabels(lbls map[string]string) Profile {
+	if profile, ok := lbls[consts.PeeringUserProfileLabelKey]; ok && profile != "" {
+		return Profile(profile)
+	}
+	return ProfileFull
+}
@@ -35,13 +35,15 @@ var peeringUserLabel = client.ListOptions{
 	}),
 }
 
-// EnsureRoles ensures that the required roles are created and bound to the user.
-func EnsureRoles(ctx context.Context, c client.Client, clusterID liqov1beta1.ClusterID, userCN, tenantNsName string) error {
-	if err := ensureLiqoNsReaderRole(ctx, c, userCN, clusterID); err != nil {
-		return err
+// EnsureRoles ensures that the roles of the given profile are bound to the user.
+func EnsureRoles(ctx context.Context, c client.Client, clusterID liqov1beta1.ClusterID, userCN, tenantNsName string, profile Profile) error {
+	if profile.grantsLiqoNsReader() {
+		if err := ensureLiqoNsReaderRole(ctx, c, userCN, clusterID, profile); err != nil {
+			return err
+		}
 	}
 
-	if err := ensureTenantNsWriterRole(ctx, c, userCN, clusterID, tenantNsName); err != nil {
+	if err := ensureTenantNsWriterRole(ctx, c, userCN, clusterID, tenantNsName, profile); err != nil {
 		return err
 	}
 
@@ -103,7 +105,7 @@ func getUserLabelSelector(userName string) labels.Selector {
 }
 
 // ensureLiqoNsReaderRole ensures that the peering-user Role is bound to the user in the Liqo namespace.
-func ensureLiqoNsReaderRole(ctx context.Context, c client.Client, userCN string, clusterID liqov1beta1.ClusterID) error {
+func ensureLiqoNsReaderRole(ctx context.Context, c client.Client, userCN string, clusterID liqov1beta1.ClusterID, profile Profile) error {
 	var peeringUserRoleList rbacv1.RoleList
 	if err := c.List(ctx, &peeringUserRoleList, &peeringUserLabel); err != nil {
 		return fmt.Errorf("unable to get peering-user Role from liqo namespace: %w", err)
@@ -124,7 +126,8 @@ func ensureLiqoNsReaderRole(ctx context.Context, c client.Client, userCN string,
 			Name:      fmt.Sprintf("%s-liqo-ns-reader", userName),
 			Namespace: peeringUserRole.Namespace,
 			Labels: map[string]string{
-				consts.PeeringUserNameLabelKey: userName,
+				consts.PeeringUserNameLabelKey:    userName,
+				consts.PeeringUserProfileLabelKey: string(profile),
 			},
 		},
 		Subjects: []rbacv1.Subject{
@@ -148,16 +151,18 @@ func ensureLiqoNsReaderRole(ctx context.Context, c client.Client, userCN string,
 	return nil
 }
 
-func ensureTenantNsWriterRole(ctx context.Context, c client.Client, userCN string, clusterID liqov1beta1.ClusterID, tenantNsName string) error {
+// ensureTenantNsWriterRole ensures that the ClusterRole of the given profile is bound to the user in the tenant namespace.
+func ensureTenantNsWriterRole(ctx context.Context, c client.Client, userCN string, clusterID liqov1beta1.ClusterID,
+	tenantNsName string, profile Profile) error {
 	var peeringClusterRoles rbacv1.ClusterRoleList
-	if err := c.List(ctx, &peeringClusterRoles, &peeringUserLabel); err != nil {
-		return fmt.Errorf("unable to get peering-user role from liqo namespace: %w", err)
+	if err := c.List(ctx, &peeringClusterRoles, profile.clusterRoleSelector()); err != nil {
+		return fmt.Errorf("unable to get peering-user role for profile %q: %w", profile, err)
 	}
 
 	if nRoles := len(peeringClusterRoles.Items); nRoles == 0 {
-		return fmt.Errorf("no peering-user ClusterRole found")
+		return fmt.Errorf("no peering-user ClusterRole found for profile %q", profile)
 	} else if nRoles > 1 {
-		return fmt.Errorf("multiple peering-user ClusterRoles found ")
+		return fmt.Errorf("multiple peering-user ClusterRoles found for profile %q", profile)
 	}
 
 	// bind the ClusterRole to the userName user
@@ -165,10 +170,11 @@ func ensureTenantNsWriterRole(ctx context.Context, c client.Client, userCN strin
 	peeringUserClusterRole := peeringClusterRoles.Items[0]
 	clusterRoleBinding := &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-tenant-ns-writer", userName),
+			Name:      profile.tenantNsRoleBindingName(userName),
 			Namespace: tenantNsName,
 			Labels: map[string]string{
-				consts.PeeringUserNameLabelKey: userName,
+				consts.PeeringUserNameLabelKey:    userName,
+				consts.PeeringUserProfileLabelKey: string(profile),
 			},
 		},
 		Subjects: []rbacv1.Subject{
@@ -0,0 +1,145 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userfactory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("Peering profiles", func() {
+	const (
+		clusterID = liqov1beta1.ClusterID("consumer")
+		userCN    = "liqo-peer-user-consumer-0123"
+		tenantNs  = "liqo-tenant-consumer"
+	)
+
+	var (
+		ctx context.Context
+		cl  client.Client
+	)
+
+	clusterRole := func(name string, lbls map[string]string) *rbacv1.ClusterRole {
+		return &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: lbls}}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+
+		objects := []client.Object{
+			clusterRole("liqo-peering-user-tenant-ns", map[string]string{"app.kubernetes.io/component": "peering-user"}),
+			&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: "liqo-peering-user-liqo-ns", Namespace: "liqo",
+				Labels: map[string]string{"app.kubernetes.io/component": "peering-user"}}},
+		}
+		for _, profile := range []Profile{ProfileNetworking, ProfileAuthentication, ProfileOffloading, ProfileObserver} {
+			objects = append(objects, clusterRole("liqo-peering-user-profile-"+string(profile), map[string]string{
+				"app.kubernetes.io/component":     "peering-user-profile",
+				consts.PeeringUserProfileLabelKey: string(profile),
+			}))
+		}
+		cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	})
+
+	boundRoles := func() map[string]string {
+		var roleBindings rbacv1.RoleBindingList
+		Expect(cl.List(ctx, &roleBindings)).To(Succeed())
+
+		roles := map[string]string{}
+		for i := range roleBindings.Items {
+			roles[roleBindings.Items[i].Namespace] = roleBindings.Items[i].RoleRef.Name
+		}
+		return roles
+	}
+
+	It("should bind the default roles with the full profile", func() {
+		Expect(EnsureRoles(ctx, cl, clusterID, userCN, tenantNs, ProfileFull)).To(Succeed())
+		Expect(boundRoles()).To(Equal(map[string]string{
+			"liqo":   "liqo-peering-user-liqo-ns",
+			tenantNs: "liqo-peering-user-tenant-ns",
+		}))
+	})
+
+	It("should bind the liqo namespace reader along with the networking profile", func() {
+		Expect(EnsureRoles(ctx, cl, clusterID, userCN, tenantNs, ProfileNetworking)).To(Succeed())
+		Expect(boundRoles()).To(Equal(map[string]string{
+			"liqo":   "liqo-peering-user-liqo-ns",
+			tenantNs: "liqo-peering-user-profile-networking",
+		}))
+	})
+
+	It("should bind the observer profile only in the tenant namespace", func() {
+		Expect(EnsureRoles(ctx, cl, clusterID, userCN, tenantNs, ProfileObserver)).To(Succeed())
+		Expect(boundRoles()).To(Equal(map[string]string{tenantNs: "liqo-peering-user-profile-observer"}))
+	})
+
+	It("should list the generated users along with their profile", func() {
+		Expect(EnsureRoles(ctx, cl, clusterID, userCN, tenantNs, ProfileOffloading)).To(Succeed())
+
+		users, err := ListPeerUsers(ctx, cl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(users).To(HaveLen(1))
+		Expect(users[0].ClusterID).To(Equal(clusterID))
+		Expect(users[0].CommonName).To(Equal(userCN))
+		Expect(users[0].Profile).To(Equal(ProfileOffloading))
+		Expect(users[0].TenantNamespace).To(Equal(tenantNs))
+	})
+})
+
+var _ = Describe("ParseAuditLog", func() {
+	const auditLog = `
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","auditID":"a1","stage":"RequestReceived","user":{"username":"alice"},` +
+		`"sourceIPs":["10.0.0.1"],"stageTimestamp":"2025-01-01T10:00:00.000000Z"}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","auditID":"a1","stage":"ResponseComplete","user":{"username":"alice"},` +
+		`"sourceIPs":["10.0.0.1"],"stageTimestamp":"2025-01-01T10:00:01.000000Z"}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","auditID":"a2","stage":"ResponseComplete","user":{"username":"alice"},` +
+		`"sourceIPs":["10.0.0.2"],"stageTimestamp":"2025-01-01T09:00:00.000000Z"}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","auditID":"a3","stage":"ResponseComplete","user":{"username":"admin"},` +
+		`"sourceIPs":["10.0.0.3"],"stageTimestamp":"2025-01-01T11:00:00.000000Z"}
+`
+
+	It("should summarize the requests of the given users", func() {
+		usages, err := ParseAuditLog(strings.NewReader(auditLog), []string{"alice", "bob"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(usages).To(HaveLen(2))
+
+		Expect(usages["alice"].Requests).To(Equal(2))
+		Expect(usages["alice"].SourceIPs).To(Equal([]string{"10.0.0.1", "10.0.0.2"}))
+		Expect(usages["alice"].LastSeen).To(BeTemporally("==", time.Date(2025, 1, 1, 10, 0, 1, 0, time.UTC)))
+
+		Expect(usages["bob"].Requests).To(BeZero())
+		Expect(usages["bob"].SourceIPs).To(BeEmpty())
+	})
+
+	It("should fail with a malformed audit log", func() {
+		_, err := ParseAuditLog(strings.NewReader("not-json\n"), []string{"alice"})
+		Expect(err).To(MatchError(ContainSubstring("line 1")))
+	})
+})
@@ -0,0 +1,132 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userfactory
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	certv1 "k8s.io/api/certificates/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+// PeerUser describes a user generated to peer with the local cluster.
+type PeerUser struct {
+	// Name is the name of the user, which identifies the resources created to grant its permissions.
+	Name string
+	// CommonName is the common name of the certificate of the user, as seen by the API server.
+	CommonName string
+	// ClusterID is the ID of the cluster the user has been generated for.
+	ClusterID liqov1beta1.ClusterID
+	// Profile is the peering profile granted to the user.
+	Profile Profile
+	// TenantNamespace is the tenant namespace the permissions of the user are bound to.
+	TenantNamespace string
+	// CreationTime is the time the user has been generated.
+	CreationTime time.Time
+	// Expiration is the expiration time of the certificate of the user, if known.
+	Expiration *time.Time
+}
+
+// ListPeerUsers returns the users generated to peer with the local cluster, sorted by name.
+func ListPeerUsers(ctx context.Context, c client.Client) ([]PeerUser, error) {
+	selector := labels.NewSelector()
+	req, err := labels.NewRequirement(consts.PeeringUserNameLabelKey, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	selector = selector.Add(*req)
+
+	var roleBindings rbacv1.RoleBindingList
+	if err := c.List(ctx, &roleBindings, &client.ListOptions{LabelSelector: selector}); err != nil {
+		return nil, fmt.Errorf("unable to list the RoleBindings of the peering users: %w", err)
+	}
+
+	users := map[string]*PeerUser{}
+	for i := range roleBindings.Items {
+		rb := &roleBindings.Items[i]
+		userName := rb.Labels[consts.PeeringUserNameLabelKey]
+
+		user, found := users[userName]
+		if !found {
+			user = &PeerUser{
+				Name:         userName,
+				ClusterID:    liqov1beta1.ClusterID(strings.TrimPrefix(userName, GetUserNameFromClusterID(""))),
+				Profile:      profileFromLabels(rb.Labels),
+				CreationTime: rb.CreationTimestamp.Time,
+			}
+			users[userName] = user
+		}
+
+		for j := range rb.Subjects {
+			if rb.Subjects[j].Kind == rbacv1.UserKind {
+				user.CommonName = rb.Subjects[j].Name
+			}
+		}
+
+		// The liqo namespace reader binding is shared by multiple profiles: the tenant namespace is the one of the other binding.
+		if rb.Name != fmt.Sprintf("%s-liqo-ns-reader", userName) {
+			user.TenantNamespace = rb.Namespace
+		}
+	}
+
+	var csrs certv1.CertificateSigningRequestList
+	if err := c.List(ctx, &csrs, &client.ListOptions{LabelSelector: selector}); err != nil {
+		return nil, fmt.Errorf("unable to list the CertificateSigningRequests of the peering users: %w", err)
+	}
+
+	for i := range csrs.Items {
+		user, found := users[csrs.Items[i].Labels[consts.PeeringUserNameLabelKey]]
+		if !found {
+			continue
+		}
+		if clusterID, ok := csrs.Items[i].Labels[consts.RemoteClusterID]; ok {
+			user.ClusterID = liqov1beta1.ClusterID(clusterID)
+		}
+		user.Expiration = certificateExpiration(csrs.Items[i].Status.Certificate)
+	}
+
+	list := make([]PeerUser, 0, len(users))
+	for _, user := range users {
+		list = append(list, *user)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// certificateExpiration returns the expiration time of the given PEM-encoded certificate, if it can be parsed.
+func certificateExpiration(certificate []byte) *time.Time {
+	block, _ := pem.Decode(certificate)
+	if block == nil {
+		return nil
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+	return &cert.NotAfter
+}
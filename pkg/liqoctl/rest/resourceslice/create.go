@@ -136,6 +136,13 @@ func (o *Options) HandleCreate(ctx context.Context) error {
 		return err
 	}
 	resourcesCondition := authentication.GetCondition(resourceSlice, authv1beta1.ResourceSliceConditionTypeResources)
+	if resourcesCondition != nil && resourcesCondition.Status == authv1beta1.ResourceSliceConditionOffered && resourceSlice.Status.Offer != nil {
+		opts.Printer.Warning.Printfln("ResourceSlice resources offered: %s", resourcesCondition.Message)
+		opts.Printer.Info.Printfln("Accept the offer with: kubectl patch resourceslices.authentication.liqo.io %s -n %s --type merge -p "+
+			"'{\"spec\":{\"offerID\":\"%s\",\"offerResponse\":\"%s\"}}'", resourceSlice.Name, resourceSlice.Namespace,
+			resourceSlice.Status.Offer.ID, authv1beta1.ResourceSliceOfferAccepted)
+		return nil
+	}
 	if resourcesCondition == nil || resourcesCondition.Status != authv1beta1.ResourceSliceConditionAccepted {
 		opts.Printer.Warning.Printfln("ResourceSlice resources not accepted. The provider cluster may have cordoned the tenant or the resourceslice")
 		return nil
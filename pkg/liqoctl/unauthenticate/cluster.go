@@ -17,12 +17,14 @@ package unauthenticate
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
 	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/liqoctl/factory"
@@ -142,6 +144,66 @@ func (c *Cluster) DeleteTenant(ctx context.Context, consumerClusterID liqov1beta
 	return nil
 }
 
+// RevokeTenant revokes the identities issued to the consumer cluster, given its cluster id, without deleting the Tenant,
+// which reports the revoked identities. If wait is true, it waits for the revocation to be completed.
+func (c *Cluster) RevokeTenant(ctx context.Context, consumerClusterID liqov1beta1.ClusterID, wait bool) (*authv1beta1.Tenant, error) {
+	s := c.local.Printer.StartSpinner("Revoking tenant")
+	tenantNamespace, err := c.tenantNamespaceManager.GetNamespace(ctx, consumerClusterID)
+	if err != nil {
+		s.Fail("Error while retrieving tenant namespace: ", output.PrettyErr(err))
+		return nil, err
+	}
+
+	tenant, err := getters.GetTenantByClusterID(ctx, c.local.CRClient, consumerClusterID, tenantNamespace.Name)
+	if err != nil {
+		s.Fail("Error while retrieving tenant: ", output.PrettyErr(err))
+		return nil, err
+	}
+
+	if tenant.Spec.TenantCondition == authv1beta1.TenantConditionRevoked {
+		s.Success("Tenant already revoked")
+	} else {
+		tenant.Spec.TenantCondition = authv1beta1.TenantConditionRevoked
+		if err := c.local.CRClient.Update(ctx, tenant); err != nil {
+			s.Fail("Error while revoking tenant: ", output.PrettyErr(err))
+			return nil, err
+		}
+		s.Success("Tenant marked as revoked")
+	}
+
+	if !wait {
+		return tenant, nil
+	}
+
+	if err := c.waiter.ForTenantRevocation(ctx, consumerClusterID, tenantNamespace.Name); err != nil {
+		return nil, err
+	}
+	return getters.GetTenantByClusterID(ctx, c.local.CRClient, consumerClusterID, tenantNamespace.Name)
+}
+
+// PrintRevokedIdentities prints the identities revoked from the given tenant.
+func (c *Cluster) PrintRevokedIdentities(tenant *authv1beta1.Tenant) {
+	if len(tenant.Status.RevokedIdentities) == 0 {
+		c.local.Printer.Warning.Printfln("No identity of tenant %q has been revoked yet", tenant.Name)
+		return
+	}
+
+	main := output.NewRootSection()
+	for i := range tenant.Status.RevokedIdentities {
+		id := &tenant.Status.RevokedIdentities[i]
+		section := main.AddSectionFailure(id.Name)
+		section.AddEntry("Type", string(id.Type))
+		section.AddEntry("User", id.User)
+		if len(id.Groups) > 0 {
+			section.AddEntry("Groups", id.Groups...)
+		}
+		section.AddEntry("Revoked at", id.RevokedAt.Format(time.RFC3339))
+	}
+
+	c.local.Printer.BoxSetTitle(fmt.Sprintf("Revoked identities of tenant %q", tenant.Name))
+	c.local.Printer.BoxPrintln(main.SprintForBox(c.local.Printer))
+}
+
 // DeleteTenantNamespace deletes a tenant namespace given the remote cluster id.
 func (c *Cluster) DeleteTenantNamespace(ctx context.Context, remoteClusterID liqov1beta1.ClusterID, waitForActualDeletion bool) error {
 	s := c.local.Printer.StartSpinner("Deleting tenant namespace")
@@ -28,6 +28,7 @@ type Options struct {
 
 	Timeout time.Duration
 	Wait    bool
+	Revoke  bool
 }
 
 // NewOptions returns a new Options struct.
@@ -41,6 +42,7 @@ func NewOptions(localFactory *factory.Factory) *Options {
 // In the consumer cluster, it deletes the control plane Identity.
 // In the provider cluster, it deletes the Tenant.
 // The execution is prevented if any ResourceSlice or VirtualNode associated with the provider cluster is found.
+// If Revoke is set, the Tenant is revoked instead of deleted, and the revoked identities are reported.
 func (o *Options) RunUnauthenticate(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, o.Timeout)
 	defer cancel()
@@ -57,6 +59,10 @@ func (o *Options) RunUnauthenticate(ctx context.Context) error {
 		return err
 	}
 
+	if o.Revoke {
+		return o.revoke(ctx, consumer, provider)
+	}
+
 	// Check if any resourceslice is still present on consumer cluster
 	if err := consumer.CheckLeftoverResourceSlices(ctx, provider.localClusterID); err != nil {
 		return err
@@ -74,3 +80,17 @@ func (o *Options) RunUnauthenticate(ctx context.Context) error {
 
 	return nil
 }
+
+// revoke revokes the identities issued by the provider cluster to the consumer one, regardless of the resources
+// still in use, and deletes the control plane Identity on the consumer cluster.
+func (o *Options) revoke(ctx context.Context, consumer, provider *Cluster) error {
+	// Revoke tenant on provider cluster
+	tenant, err := provider.RevokeTenant(ctx, consumer.localClusterID, o.Wait)
+	if err != nil {
+		return err
+	}
+	provider.PrintRevokedIdentities(tenant)
+
+	// Delete control plane Identity on consumer cluster
+	return consumer.DeleteControlPlaneIdentity(ctx, provider.localClusterID)
+}
@@ -426,6 +426,25 @@ func (w *Waiter) ForTenantStatus(ctx context.Context, remoteClusterID liqov1beta
 	return nil
 }
 
+// ForTenantRevocation waits until the identities of the tenant have been revoked or the timeout expires.
+func (w *Waiter) ForTenantRevocation(ctx context.Context, remoteClusterID liqov1beta1.ClusterID, tenantNamespace string) error {
+	s := w.Printer.StartSpinner("Waiting for tenant identities to be revoked")
+	err := wait.PollUntilContextCancel(ctx, 1*time.Second, true, func(ctx context.Context) (done bool, err error) {
+		tenant, err := getters.GetTenantByClusterID(ctx, w.CRClient, remoteClusterID, tenantNamespace)
+		if err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+
+		return tenant.Status.AuthParams == nil && len(tenant.Status.RevokedIdentities) > 0, nil
+	})
+	if err != nil {
+		s.Fail(fmt.Sprintf("Failed waiting for tenant identities to be revoked: %s", output.PrettyErr(err)))
+		return err
+	}
+	s.Success("Tenant identities revoked")
+	return nil
+}
+
 // ForIdentityStatus waits until the identity status has been updated or the timeout expires.
 func (w *Waiter) ForIdentityStatus(ctx context.Context, remoteClusterID liqov1beta1.ClusterID) error {
 	s := w.Printer.StartSpinner("Waiting for identity status to be filled")
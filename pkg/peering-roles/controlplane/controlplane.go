@@ -24,3 +24,5 @@ package controlplane
 
 // +kubebuilder:rbac:groups=offloading.liqo.io,resources=namespacemaps,verbs=get;update;patch;list;watch;delete;create;deletecollection
 // +kubebuilder:rbac:groups=offloading.liqo.io,resources=namespacemaps/status,verbs=get;update;patch;list;watch;delete;create;deletecollection
+
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;update;create
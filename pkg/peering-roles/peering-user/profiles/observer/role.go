@@ -0,0 +1,26 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package observer contains the read-only permissions on the tenant namespace to inspect the status of the peering
+// with the provider cluster, without access to the secrets. Tenants and ResourceSlices are excluded as well, since their
+// status carries the credentials issued to the consumer cluster.
+package observer
+
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=configurations;gatewayclients;gatewayservers;publickeies;connections,verbs=get;list;watch
+// +kubebuilder:rbac:groups=networking.liqo.io,resources=gatewayclients/status;gatewayservers/status,verbs=get
+// +kubebuilder:rbac:groups=ipam.liqo.io,resources=ips,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get
@@ -0,0 +1,22 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+// Package offloading contains the permissions required on the tenant namespace to manage the ResourceSlices
+// requesting resources from the provider cluster, once the authentication has been established.
+package offloading
+
+// +kubebuilder:rbac:groups=authentication.liqo.io,resources=resourceslices,verbs=create;update;delete;get;list;watch
+// +kubebuilder:rbac:groups=authentication.liqo.io,resources=resourceslices/status,verbs=get
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get
@@ -41,14 +41,17 @@ var (
 
 type metricHandler struct {
 	*httprouter.Router
-	scraper Scraper
+	scraper        Scraper
+	summaryScraper SummaryScraper
 }
 
 // GetHTTPHandler returns a handler for the metrics API.
 func GetHTTPHandler(restClient rest.Interface, cl client.Client) (http.Handler, error) {
+	scraper := NewAPIServiceScraper(restClient, cl)
 	router := &metricHandler{
-		Router:  httprouter.New(),
-		scraper: NewAPIServiceScraper(restClient, cl),
+		Router:         httprouter.New(),
+		scraper:        scraper,
+		summaryScraper: scraper.(SummaryScraper),
 	}
 
 	// Return empty api resource list.
@@ -73,11 +76,11 @@ func health(w http.ResponseWriter, req *http.Request, ps httprouter.Params) {
 	list.Kind = "APIResourceList"
 	list.GroupVersion = "metrics.liqo.io/v1beta1"
 	list.APIVersion = "v1beta1"
-	list.APIResources = []metav1.APIResource{
-		{
-			Name:       "scrape/metrics",
+	for _, path := range append(availablePaths, SummaryPath) {
+		list.APIResources = append(list.APIResources, metav1.APIResource{
+			Name:       "scrape/" + path,
 			Namespaced: false,
-		},
+		})
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -143,6 +146,11 @@ func (handler *metricHandler) metricHTTP(w http.ResponseWriter, req *http.Reques
 		path = path + "/" + subpath
 	}
 
+	if path == SummaryPath {
+		handler.summaryHTTP(w, req, clusterID)
+		return
+	}
+
 	if !handler.isValidPath(path) {
 		klog.Errorf("invalid path: %s", path)
 		w.WriteHeader(http.StatusBadRequest)
@@ -164,6 +172,25 @@ func (handler *metricHandler) metricHTTP(w http.ResponseWriter, req *http.Reques
 	metrics.Write(w)
 }
 
+func (handler *metricHandler) summaryHTTP(w http.ResponseWriter, req *http.Request, clusterID string) {
+	summary, err := handler.summaryScraper.ScrapeSummary(req.Context(), clusterID)
+	if err != nil {
+		klog.Errorf("failed to scrape stats summary: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		if _, err = w.Write([]byte(err.Error())); err != nil {
+			klog.Errorf("failed to write error: %s", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(summary); err != nil {
+		klog.Errorf("failed to write response: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
 func (handler *metricHandler) isValidPath(path string) bool {
 	for _, p := range availablePaths {
 		if path == p {
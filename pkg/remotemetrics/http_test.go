@@ -0,0 +1,45 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotemetrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Context("HTTP handler", func() {
+
+	It("should list all the available scrape paths", func() {
+		recorder := httptest.NewRecorder()
+		health(recorder, httptest.NewRequest(http.MethodGet, basePath, http.NoBody), nil)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var list metav1.APIResourceList
+		Expect(json.NewDecoder(recorder.Body).Decode(&list)).To(Succeed())
+
+		var names []string
+		for i := range list.APIResources {
+			names = append(names, list.APIResources[i].Name)
+		}
+		Expect(names).To(ConsistOf("scrape/metrics", "scrape/metrics/cadvisor", "scrape/metrics/resource",
+			"scrape/metrics/probes", "scrape/stats/summary"))
+	})
+
+})
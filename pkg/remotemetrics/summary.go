@@ -0,0 +1,147 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotemetrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/virtual-kubelet/virtual-kubelet/node/api/statsv1alpha1"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// SummaryPath is the path exposing the kubelet stats summary.
+	SummaryPath = "stats/summary"
+)
+
+// RetrieveSummary retrieves the stats summary of the pods offloaded by the given cluster through the metrics API.
+func RetrieveSummary(ctx context.Context, restClient rest.Interface, clusterID string) (*statsv1alpha1.Summary, error) {
+	data, err := restClient.Get().AbsPath(basePath, "scrape", clusterID, SummaryPath).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the stats summary: %w", err)
+	}
+
+	var summary statsv1alpha1.Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("failed to decode the stats summary: %w", err)
+	}
+
+	return &summary, nil
+}
+
+// ScrapeSummary scrapes the stats summary from the API server for the given clusterID, retaining the pods
+// owned by the remote cluster only, and translating their namespace name with the original one.
+func (s *apiServiceScraper) ScrapeSummary(ctx context.Context, clusterID string) (*statsv1alpha1.Summary, error) {
+	nodes := s.resourceManager.GetNodeNames(ctx)
+	namespaces := s.resourceManager.GetNamespaces(ctx, clusterID)
+
+	var lock sync.Mutex
+	summary := &statsv1alpha1.Summary{}
+
+	errGroup, ctx := errgroup.WithContext(ctx)
+	for i := range nodes {
+		node := nodes[i]
+		// run each scraper in a separate goroutine
+		errGroup.Go(func() error {
+			pods, err := s.getPodStats(ctx, node, namespaces, s.resourceManager.GetPodNames(ctx, clusterID, node))
+			if err != nil {
+				return err
+			}
+
+			lock.Lock()
+			defer lock.Unlock()
+			summary.Pods = append(summary.Pods, pods...)
+			return nil
+		})
+	}
+
+	if err := errGroup.Wait(); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// getPodStats retrieves the stats summary of the given node, and returns the stats of the given pods.
+func (s *apiServiceScraper) getPodStats(ctx context.Context, nodeName string,
+	namespaces []MappedNamespace, pods []string) ([]statsv1alpha1.PodStats, error) {
+	data, err := s.rawGetter.get(ctx, nodeName, SummaryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// The node has not been found, hence there are no stats to return.
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var nodeSummary statsv1alpha1.Summary
+	if err := json.Unmarshal(data, &nodeSummary); err != nil {
+		return nil, fmt.Errorf("failed to decode stats summary of node %q: %w", nodeName, err)
+	}
+
+	var stats []statsv1alpha1.PodStats
+	for i := range nodeSummary.Pods {
+		podStats := &nodeSummary.Pods[i]
+		namespace, found := mapNamespace(podStats.PodRef.Namespace, namespaces)
+		if !found || !slices.Contains(pods, podStats.PodRef.Name) {
+			klog.V(5).Infof("Ignored stats of pod %s/%s", podStats.PodRef.Namespace, podStats.PodRef.Name)
+			continue
+		}
+
+		podStats.PodRef.Namespace = namespace
+		mapVolumeStats(podStats.VolumeStats, namespaces)
+		stats = append(stats, *podStats)
+	}
+
+	return stats, nil
+}
+
+// mapVolumeStats translates the namespace of the PVCs referenced by the given volume stats with the original one.
+// The name is left unchanged, as the remote PVCs are named after the local ones, while the references to the PVCs
+// not belonging to any of the mapped namespaces are dropped.
+func mapVolumeStats(volumeStats []statsv1alpha1.VolumeStats, namespaces []MappedNamespace) {
+	for i := range volumeStats {
+		pvcRef := volumeStats[i].PVCRef
+		if pvcRef == nil {
+			continue
+		}
+
+		namespace, found := mapNamespace(pvcRef.Namespace, namespaces)
+		if !found {
+			klog.V(5).Infof("Ignored reference to PVC %s/%s", pvcRef.Namespace, pvcRef.Name)
+			volumeStats[i].PVCRef = nil
+			continue
+		}
+
+		pvcRef.Namespace = namespace
+	}
+}
+
+// mapNamespace returns the original name of the given namespace, and whether it is one of the mapped ones.
+func mapNamespace(namespace string, namespaces []MappedNamespace) (string, bool) {
+	for i := range namespaces {
+		if namespaces[i].Namespace == namespace {
+			return namespaces[i].OriginalName, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,117 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remotemetrics
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	"github.com/virtual-kubelet/virtual-kubelet/node/api/statsv1alpha1"
+)
+
+var _ = Context("SummaryScraper", func() {
+
+	var scraper SummaryScraper
+	var summary *statsv1alpha1.Summary
+	var err error
+
+	forgeSummary := func(pods ...statsv1alpha1.PodReference) []byte {
+		nodeSummary := statsv1alpha1.Summary{Node: statsv1alpha1.NodeStats{NodeName: "node"}}
+		for i := range pods {
+			nodeSummary.Pods = append(nodeSummary.Pods, statsv1alpha1.PodStats{
+				PodRef: pods[i],
+				VolumeStats: []statsv1alpha1.VolumeStats{
+					{Name: "data", PVCRef: &statsv1alpha1.PVCReference{Namespace: pods[i].Namespace, Name: "pvc-" + pods[i].Name}},
+					{Name: "foreign", PVCRef: &statsv1alpha1.PVCReference{Namespace: "foreign", Name: "pvc"}},
+					{Name: "tmp"},
+				},
+			})
+		}
+		data, err := json.Marshal(nodeSummary)
+		Expect(err).ToNot(HaveOccurred())
+		return data
+	}
+
+	BeforeEach(func() {
+		scraper = &apiServiceScraper{
+			resourceManager: &fakeResourceGetter{
+				nodes: []string{"node1", "node2", "node3"},
+				namespaces: map[string][]MappedNamespace{
+					"cluster1": {{Namespace: "namespace1", OriginalName: "original_namespace1"}},
+					"cluster2": {{Namespace: "namespace2", OriginalName: "original_namespace2"}},
+				},
+				pods: map[string]map[string][]string{
+					"node1": {
+						"cluster1": {"pod1", "pod2"},
+						"cluster2": {"pod3"},
+					},
+					"node2": {
+						"cluster1": {"pod5"},
+					},
+					"node3": {},
+				},
+			},
+			rawGetter: &fakeRawGetter{
+				data: map[string][]byte{
+					"node1": forgeSummary(
+						statsv1alpha1.PodReference{Namespace: "namespace1", Name: "pod1"},
+						statsv1alpha1.PodReference{Namespace: "namespace1", Name: "pod2"},
+						statsv1alpha1.PodReference{Namespace: "namespace2", Name: "pod3"},
+						statsv1alpha1.PodReference{Namespace: "namespace1", Name: "other"},
+					),
+					"node2": forgeSummary(statsv1alpha1.PodReference{Namespace: "namespace1", Name: "pod5"}),
+					"node3": []byte(""),
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		summary, err = scraper.ScrapeSummary(context.Background(), "cluster1")
+	})
+
+	It("should scrape the stats of the pods of the given cluster", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary).ToNot(BeNil())
+
+		var refs []statsv1alpha1.PodReference
+		for i := range summary.Pods {
+			refs = append(refs, summary.Pods[i].PodRef)
+		}
+		Expect(refs).To(ConsistOf(
+			statsv1alpha1.PodReference{Namespace: "original_namespace1", Name: "pod1"},
+			statsv1alpha1.PodReference{Namespace: "original_namespace1", Name: "pod2"},
+			statsv1alpha1.PodReference{Namespace: "original_namespace1", Name: "pod5"},
+		))
+	})
+
+	It("should translate the references to the PVCs of the pods", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(summary).ToNot(BeNil())
+		Expect(summary.Pods).ToNot(BeEmpty())
+
+		for i := range summary.Pods {
+			Expect(summary.Pods[i].VolumeStats).To(HaveLen(3))
+			Expect(summary.Pods[i].VolumeStats[0].PVCRef).To(PointTo(Equal(statsv1alpha1.PVCReference{
+				Namespace: "original_namespace1", Name: "pvc-" + summary.Pods[i].PodRef.Name})))
+			Expect(summary.Pods[i].VolumeStats[1].PVCRef).To(BeNil())
+			Expect(summary.Pods[i].VolumeStats[2].PVCRef).To(BeNil())
+		}
+	})
+
+})
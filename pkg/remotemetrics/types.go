@@ -14,13 +14,22 @@
 
 package remotemetrics
 
-import "context"
+import (
+	"context"
+
+	"github.com/virtual-kubelet/virtual-kubelet/node/api/statsv1alpha1"
+)
 
 // Scraper is the interface for a remote metrics scraper.
 type Scraper interface {
 	Scrape(ctx context.Context, path, clusterID string) (Metrics, error)
 }
 
+// SummaryScraper is the interface for a remote stats summary scraper.
+type SummaryScraper interface {
+	ScrapeSummary(ctx context.Context, clusterID string) (*statsv1alpha1.Summary, error)
+}
+
 // MappedNamespace contains both the original and the mapped namespace names.
 type MappedNamespace struct {
 	Namespace    string
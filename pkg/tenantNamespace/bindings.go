@@ -16,18 +16,22 @@ package tenantnamespace
 
 import (
 	"context"
+	"fmt"
 	"maps"
+	"slices"
 
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
 	"github.com/liqotech/liqo/pkg/utils/resource"
 )
 
@@ -82,6 +86,15 @@ func (nm *tenantNamespaceManager) bindClusterRole(ctx context.Context, cluster l
 
 	annotations := resource.GetGlobalAnnotations()
 
+	subjects, err := nm.forgeSubjects(ctx, cluster, namespace.Name, rbacv1.Subject{
+		Kind:     rbacv1.UserKind,
+		APIGroup: rbacv1.GroupName,
+		Name:     string(cluster),
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	rb := &rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
@@ -89,13 +102,7 @@ func (nm *tenantNamespaceManager) bindClusterRole(ctx context.Context, cluster l
 			Labels:      labels,
 			Annotations: annotations,
 		},
-		Subjects: []rbacv1.Subject{
-			{
-				Kind:     rbacv1.UserKind,
-				APIGroup: rbacv1.GroupName,
-				Name:     string(cluster),
-			},
-		},
+		Subjects: subjects,
 		RoleRef: rbacv1.RoleRef{
 			APIGroup: rbacv1.GroupName,
 			Kind:     "ClusterRole",
@@ -112,11 +119,34 @@ func (nm *tenantNamespaceManager) bindClusterRole(ctx context.Context, cluster l
 	resource.AddGlobalLabels(rb)
 	resource.AddGlobalAnnotations(rb)
 
-	rb, err := nm.client.RbacV1().RoleBindings(namespace.Name).Create(ctx, rb, metav1.CreateOptions{})
+	created, err := nm.client.RbacV1().RoleBindings(namespace.Name).Create(ctx, rb, metav1.CreateOptions{})
 	if apierrors.IsAlreadyExists(err) {
-		return nm.client.RbacV1().RoleBindings(namespace.Name).Get(ctx, name, metav1.GetOptions{})
+		existing, err := nm.client.RbacV1().RoleBindings(namespace.Name).Get(ctx, name, metav1.GetOptions{})
+		if err != nil || len(authentication.MergeSubjects(slices.Clone(existing.Subjects), subjects...)) == len(existing.Subjects) {
+			return existing, err
+		}
+		existing.Subjects = authentication.MergeSubjects(existing.Subjects, subjects...)
+		return nm.client.RbacV1().RoleBindings(namespace.Name).Update(ctx, existing, metav1.UpdateOptions{})
 	}
-	return rb, err
+	return created, err
+}
+
+// forgeSubjects returns the given subject of a certificate identity, along with the ones of the ServiceAccounts backing the
+// token identities issued to the same cluster (in the given tenant namespace) which stand for it.
+func (nm *tenantNamespaceManager) forgeSubjects(ctx context.Context, cluster liqov1beta1.ClusterID,
+	tenantNamespace string, subject rbacv1.Subject) ([]rbacv1.Subject, error) {
+	serviceAccounts, err := nm.client.CoreV1().ServiceAccounts(tenantNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{
+			consts.RemoteClusterID:            string(cluster),
+			authentication.TokenIdentityLabel: "true",
+		}).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the token ServiceAccounts of cluster %q: %w", cluster, err)
+	}
+
+	subjects := []rbacv1.Subject{subject}
+	return append(subjects, authentication.TokenIdentitySubjects(serviceAccounts.Items, subjects)...), nil
 }
 
 // delete a RoleBinding in the given Namespace.
@@ -158,19 +188,28 @@ func (nm *tenantNamespaceManager) bindClusterRoleClusterWide(ctx context.Context
 	maps.Copy(labels, resource.GetGlobalLabels())
 
 	annotations := resource.GetGlobalAnnotations()
+
+	namespace, err := nm.GetNamespace(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	subjects, err := nm.forgeSubjects(ctx, cluster, namespace.Name, rbacv1.Subject{
+		Kind:     rbacv1.GroupKind,
+		APIGroup: rbacv1.GroupName,
+		Name:     string(cluster),
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	crb := &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
 			Labels:      labels,
 			Annotations: annotations,
 		},
-		Subjects: []rbacv1.Subject{
-			{
-				Kind:     rbacv1.GroupKind,
-				APIGroup: rbacv1.GroupName,
-				Name:     string(cluster),
-			},
-		},
+		Subjects: subjects,
 		RoleRef: rbacv1.RoleRef{
 			APIGroup: rbacv1.GroupName,
 			Kind:     "ClusterRole",
@@ -187,11 +226,16 @@ func (nm *tenantNamespaceManager) bindClusterRoleClusterWide(ctx context.Context
 	resource.AddGlobalLabels(crb)
 	resource.AddGlobalAnnotations(crb)
 
-	crb, err := nm.client.RbacV1().ClusterRoleBindings().Create(ctx, crb, metav1.CreateOptions{})
+	created, err := nm.client.RbacV1().ClusterRoleBindings().Create(ctx, crb, metav1.CreateOptions{})
 	if apierrors.IsAlreadyExists(err) {
-		return nm.client.RbacV1().ClusterRoleBindings().Get(ctx, name, metav1.GetOptions{})
+		existing, err := nm.client.RbacV1().ClusterRoleBindings().Get(ctx, name, metav1.GetOptions{})
+		if err != nil || len(authentication.MergeSubjects(slices.Clone(existing.Subjects), subjects...)) == len(existing.Subjects) {
+			return existing, err
+		}
+		existing.Subjects = authentication.MergeSubjects(existing.Subjects, subjects...)
+		return nm.client.RbacV1().ClusterRoleBindings().Update(ctx, existing, metav1.UpdateOptions{})
 	}
-	return crb, err
+	return created, err
 }
 
 // UnbindClusterRolesClusterWide deletes ClusterRoleBindings for the given ClusterRoles.
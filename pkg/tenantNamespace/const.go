@@ -19,4 +19,10 @@ const (
 	NamePrefix = "liqo-tenant"
 
 	roleBindingRoot = "liqo-binding"
+
+	// LimitRangeName is the name of the LimitRange enforcing the default resources of the containers
+	// in the namespaces of the remote clusters.
+	LimitRangeName = "liqo-default-limits"
+	// NetworkPolicyName is the name of the NetworkPolicy isolating the namespaces of the remote clusters.
+	NetworkPolicyName = "liqo-default-deny"
 )
@@ -17,6 +17,7 @@ package tenantnamespace
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -42,10 +43,24 @@ type tenantNamespaceManager struct {
 	listNamespaces            namespaceLister
 	getNamespaceByDefaultName namespaceGetter
 	scheme                    *runtime.Scheme
+	policies                  *NamespacePolicies
+	// enforced maps the names of the tenant namespaces the runtime policies have been enforced in to their UIDs,
+	// as the policies do not change at runtime.
+	enforced sync.Map
+}
+
+// Option is a function that configures a TenantNamespaceManager.
+type Option func(*tenantNamespaceManager)
+
+// WithNamespacePolicies configures the runtime policies applied to the tenant namespaces.
+func WithNamespacePolicies(policies *NamespacePolicies) Option {
+	return func(nm *tenantNamespaceManager) {
+		nm.policies = policies
+	}
 }
 
 // NewManager creates a new TenantNamespaceManager object.
-func NewManager(client kubernetes.Interface, scheme *runtime.Scheme) Manager {
+func NewManager(client kubernetes.Interface, scheme *runtime.Scheme, opts ...Option) Manager {
 	listNamespaces := func(ctx context.Context, selector labels.Selector) (ret []*v1.Namespace, err error) {
 		ns, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
 		if err != nil {
@@ -66,16 +81,20 @@ func NewManager(client kubernetes.Interface, scheme *runtime.Scheme) Manager {
 		return
 	}
 
-	return &tenantNamespaceManager{
+	nm := &tenantNamespaceManager{
 		client:                    client,
 		listNamespaces:            listNamespaces,
 		getNamespaceByDefaultName: getNamespace,
 		scheme:                    scheme,
 	}
+	for _, opt := range opts {
+		opt(nm)
+	}
+	return nm
 }
 
 // NewCachedManager creates a new TenantNamespaceManager object, supporting cached retrieval of namespaces for increased efficiency.
-func NewCachedManager(ctx context.Context, client kubernetes.Interface, scheme *runtime.Scheme) Manager {
+func NewCachedManager(ctx context.Context, client kubernetes.Interface, scheme *runtime.Scheme, opts ...Option) Manager {
 	// Here, we create a new namepace lister, so that it is possible to perform cached get/list operations.
 	// The informer factory is configured with an appropriate filter to cache only tenant namespaces.
 	req, err := labels.NewRequirement(consts.TenantNamespaceLabel, selection.Exists, []string{})
@@ -92,19 +111,24 @@ func NewCachedManager(ctx context.Context, client kubernetes.Interface, scheme *
 	factory.Start(ctx.Done())
 	factory.WaitForCacheSync(ctx.Done())
 
-	return &tenantNamespaceManager{
+	nm := &tenantNamespaceManager{
 		client:         client,
 		listNamespaces: listNamespaces,
 		scheme:         scheme,
 	}
+	for _, opt := range opts {
+		opt(nm)
+	}
+	return nm
 }
 
-// CreateNamespace creates a new Tenant Namespace given the clusterid
+// CreateNamespace creates a new Tenant Namespace given the clusterid, and enforces the configured runtime policies
+// (only once per namespace).
 // This method is idempotent, multiple calls of it will not lead to multiple namespace creations.
 func (nm *tenantNamespaceManager) CreateNamespace(ctx context.Context, cluster liqov1beta1.ClusterID) (ns *v1.Namespace, err error) {
 	// Let immediately check if the namespace already exists, since this might be cached and thus fast
 	if ns, err = nm.GetNamespace(ctx, cluster); err == nil {
-		return ns, nil
+		return ns, nm.ensurePolicies(ctx, ns, cluster)
 	} else if !kerrors.IsNotFound(err) {
 		klog.Error(err)
 		return nil, err
@@ -122,7 +146,7 @@ func (nm *tenantNamespaceManager) CreateNamespace(ctx context.Context, cluster l
 	}
 
 	klog.V(4).Infof("Namespace %v created for the remote cluster %v", ns.Name, cluster)
-	return ns, nil
+	return ns, nm.ensurePolicies(ctx, ns, cluster)
 }
 
 // ForgeNamespace returns a Tenant Namespace resource object given name and clusterid.
@@ -0,0 +1,205 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenantnamespace
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+const (
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+	podSecurityAuditLabel   = "pod-security.kubernetes.io/audit"
+	podSecurityWarnLabel    = "pod-security.kubernetes.io/warn"
+)
+
+// PodSecurityLevels are the supported Pod Security Admission levels.
+var PodSecurityLevels = []string{"privileged", "baseline", "restricted"}
+
+// NamespacePolicies defines the runtime policies applied to the namespaces hosting the resources
+// and the workloads of the remote clusters.
+type NamespacePolicies struct {
+	// PodSecurityLevel is the Pod Security Admission level enforced in the namespaces hosting the offloaded workloads.
+	// It is not enforced in the tenant namespaces, as hosting the privileged Liqo gateways.
+	PodSecurityLevel string
+	// DefaultRequests are the default resource requests of the containers, enforced through a LimitRange.
+	DefaultRequests corev1.ResourceList
+	// DefaultLimits are the default resource limits of the containers, enforced through a LimitRange.
+	DefaultLimits corev1.ResourceList
+	// DefaultDenyNetworkPolicy enables a NetworkPolicy denying the ingress traffic not originated by the same remote cluster.
+	DefaultDenyNetworkPolicy bool
+	// RuntimeClassName is the runtime class enforced by the ShadowPod webhook on the offloaded pods.
+	RuntimeClassName string
+}
+
+// Validate checks whether the policies are valid.
+func (p *NamespacePolicies) Validate() error {
+	if p.PodSecurityLevel == "" {
+		return nil
+	}
+	for _, level := range PodSecurityLevels {
+		if p.PodSecurityLevel == level {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid pod security level %q (supported: %v)", p.PodSecurityLevel, PodSecurityLevels)
+}
+
+// HasLimitRange returns whether a LimitRange has to be enforced in the namespaces.
+func (p *NamespacePolicies) HasLimitRange() bool {
+	return p != nil && (len(p.DefaultRequests) > 0 || len(p.DefaultLimits) > 0)
+}
+
+// HasNetworkPolicy returns whether a NetworkPolicy has to be enforced in the namespaces.
+func (p *NamespacePolicies) HasNetworkPolicy() bool {
+	return p != nil && p.DefaultDenyNetworkPolicy
+}
+
+// MutateOffloadingNamespace configures the labels and the annotations of a namespace hosting offloaded workloads.
+func (p *NamespacePolicies) MutateOffloadingNamespace(namespace *corev1.Namespace) {
+	if p == nil {
+		return
+	}
+
+	if p.PodSecurityLevel != "" {
+		if namespace.Labels == nil {
+			namespace.Labels = map[string]string{}
+		}
+		maps.Copy(namespace.Labels, map[string]string{
+			podSecurityEnforceLabel: p.PodSecurityLevel,
+			podSecurityAuditLabel:   p.PodSecurityLevel,
+			podSecurityWarnLabel:    p.PodSecurityLevel,
+		})
+	}
+
+	if p.RuntimeClassName != "" {
+		if namespace.Annotations == nil {
+			namespace.Annotations = map[string]string{}
+		}
+		namespace.Annotations[consts.RemoteNamespaceRuntimeClassAnnotationKey] = p.RuntimeClassName
+	}
+}
+
+// MutateLimitRange configures the LimitRange enforcing the default resources of the containers.
+func (p *NamespacePolicies) MutateLimitRange(limitRange *corev1.LimitRange) {
+	limitRange.Spec.Limits = []corev1.LimitRangeItem{{
+		Type:           corev1.LimitTypeContainer,
+		Default:        p.DefaultLimits.DeepCopy(),
+		DefaultRequest: p.DefaultRequests.DeepCopy(),
+	}}
+}
+
+// MutateNetworkPolicy configures the NetworkPolicy denying the ingress traffic towards the pods of the namespace, except for
+// the one originated by the same namespace, by the other namespaces of the given remote cluster (including the tenant namespace
+// hosting its gateway), and by the given CIDRs (i.e., the ones of the remote cluster, as seen through the gateway).
+// The Liqo components (e.g., the gateways and the virtual kubelets) are not affected.
+func (p *NamespacePolicies) MutateNetworkPolicy(policy *networkingv1.NetworkPolicy, cluster liqov1beta1.ClusterID, cidrs []string) {
+	peers := []networkingv1.NetworkPolicyPeer{
+		{PodSelector: &metav1.LabelSelector{}},
+		{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{consts.RemoteClusterID: string(cluster)}}},
+	}
+	for _, cidr := range cidrs {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+	}
+
+	policy.Spec = networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: consts.NetworkingComponentKey, Operator: metav1.LabelSelectorOpDoesNotExist},
+			{Key: consts.OffloadingComponentKey, Operator: metav1.LabelSelectorOpDoesNotExist},
+		}},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: peers}},
+	}
+}
+
+// ensurePolicies enforces the runtime policies in the given tenant namespace, unless already enforced by this manager.
+func (nm *tenantNamespaceManager) ensurePolicies(ctx context.Context, namespace *corev1.Namespace, cluster liqov1beta1.ClusterID) error {
+	if uid, found := nm.enforced.Load(namespace.Name); found && uid == namespace.UID {
+		return nil
+	}
+
+	if err := nm.enforcePolicies(ctx, namespace, cluster); err != nil {
+		return err
+	}
+	nm.enforced.Store(namespace.Name, namespace.UID)
+	return nil
+}
+
+// enforcePolicies enforces the LimitRange and the NetworkPolicy configured by the runtime policies in the given tenant namespace.
+func (nm *tenantNamespaceManager) enforcePolicies(ctx context.Context, namespace *corev1.Namespace, cluster liqov1beta1.ClusterID) error {
+	if nm.policies.HasLimitRange() {
+		limitRanges := nm.client.CoreV1().LimitRanges(namespace.Name)
+		limitRange, err := limitRanges.Get(ctx, LimitRangeName, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			limitRange = &corev1.LimitRange{ObjectMeta: forgePolicyObjectMeta(LimitRangeName, namespace.Name, cluster)}
+			nm.policies.MutateLimitRange(limitRange)
+			_, err = limitRanges.Create(ctx, limitRange, metav1.CreateOptions{})
+		} else if err == nil {
+			original := limitRange.Spec.DeepCopy()
+			nm.policies.MutateLimitRange(limitRange)
+			if !equality.Semantic.DeepEqual(original, &limitRange.Spec) {
+				_, err = limitRanges.Update(ctx, limitRange, metav1.UpdateOptions{})
+			}
+		}
+		if err != nil {
+			klog.Errorf("Failed to enforce LimitRange in tenant namespace %q: %v", namespace.Name, err)
+			return err
+		}
+	}
+
+	if nm.policies.HasNetworkPolicy() {
+		networkPolicies := nm.client.NetworkingV1().NetworkPolicies(namespace.Name)
+		policy, err := networkPolicies.Get(ctx, NetworkPolicyName, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			policy = &networkingv1.NetworkPolicy{ObjectMeta: forgePolicyObjectMeta(NetworkPolicyName, namespace.Name, cluster)}
+			nm.policies.MutateNetworkPolicy(policy, cluster, nil)
+			_, err = networkPolicies.Create(ctx, policy, metav1.CreateOptions{})
+		} else if err == nil {
+			original := policy.Spec.DeepCopy()
+			nm.policies.MutateNetworkPolicy(policy, cluster, nil)
+			if !equality.Semantic.DeepEqual(original, &policy.Spec) {
+				_, err = networkPolicies.Update(ctx, policy, metav1.UpdateOptions{})
+			}
+		}
+		if err != nil {
+			klog.Errorf("Failed to enforce NetworkPolicy in tenant namespace %q: %v", namespace.Name, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func forgePolicyObjectMeta(name, namespace string, cluster liqov1beta1.ClusterID) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels: map[string]string{
+			consts.K8sAppManagedByKey: consts.LiqoAppLabelValue,
+			consts.RemoteClusterID:    string(cluster),
+		},
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenantnamespace
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("NamespacePolicies", func() {
+	var policies *NamespacePolicies
+
+	BeforeEach(func() {
+		policies = &NamespacePolicies{
+			PodSecurityLevel:         "restricted",
+			DefaultRequests:          corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			DefaultLimits:            corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			DefaultDenyNetworkPolicy: true,
+			RuntimeClassName:         "gvisor",
+		}
+	})
+
+	Describe("the Validate function", func() {
+		It("should accept the supported pod security levels", func() {
+			for _, level := range append(PodSecurityLevels, "") {
+				policies.PodSecurityLevel = level
+				Expect(policies.Validate()).To(Succeed())
+			}
+		})
+
+		It("should reject an unknown pod security level", func() {
+			policies.PodSecurityLevel = "strict"
+			Expect(policies.Validate()).ToNot(Succeed())
+		})
+	})
+
+	Describe("the MutateOffloadingNamespace function", func() {
+		It("should configure the pod security labels and the runtime class annotation", func() {
+			namespace := corev1.Namespace{}
+			policies.MutateOffloadingNamespace(&namespace)
+			Expect(namespace.Labels).To(HaveKeyWithValue(podSecurityEnforceLabel, "restricted"))
+			Expect(namespace.Labels).To(HaveKeyWithValue(podSecurityAuditLabel, "restricted"))
+			Expect(namespace.Labels).To(HaveKeyWithValue(podSecurityWarnLabel, "restricted"))
+			Expect(namespace.Annotations).To(HaveKeyWithValue(consts.RemoteNamespaceRuntimeClassAnnotationKey, "gvisor"))
+		})
+
+		It("should not modify the namespace if no policy is configured", func() {
+			namespace := corev1.Namespace{}
+			var nilPolicies *NamespacePolicies
+			nilPolicies.MutateOffloadingNamespace(&namespace)
+			(&NamespacePolicies{}).MutateOffloadingNamespace(&namespace)
+			Expect(namespace.Labels).To(BeEmpty())
+			Expect(namespace.Annotations).To(BeEmpty())
+		})
+	})
+
+	Describe("the MutateNetworkPolicy function", func() {
+		It("should allow the traffic originated by the same remote cluster", func() {
+			policy := networkingv1.NetworkPolicy{}
+			policies.MutateNetworkPolicy(&policy, "remote-cluster-id", []string{"10.0.0.0/16"})
+			Expect(policy.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress))
+			Expect(policy.Spec.PodSelector.MatchExpressions).To(HaveLen(2))
+			Expect(policy.Spec.Ingress).To(HaveLen(1))
+			Expect(policy.Spec.Ingress[0].From).To(ConsistOf(
+				networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{}},
+				networkingv1.NetworkPolicyPeer{NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{consts.RemoteClusterID: "remote-cluster-id"}}},
+				networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/16"}},
+			))
+		})
+	})
+
+	Describe("the enforcePolicies function", func() {
+		var (
+			clientset *fake.Clientset
+			manager   *tenantNamespaceManager
+			namespace *corev1.Namespace
+			cluster   liqov1beta1.ClusterID
+		)
+
+		BeforeEach(func() {
+			clientset = fake.NewSimpleClientset()
+			manager = NewManager(clientset, nil, WithNamespacePolicies(policies)).(*tenantNamespaceManager)
+			namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "liqo-tenant-remote"}}
+			cluster = "remote-cluster-id"
+		})
+
+		It("should create and align the LimitRange and the NetworkPolicy", func() {
+			Expect(manager.enforcePolicies(ctx, namespace, cluster)).To(Succeed())
+
+			limitRange, err := clientset.CoreV1().LimitRanges(namespace.Name).Get(ctx, LimitRangeName, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(limitRange.Labels).To(HaveKeyWithValue(consts.RemoteClusterID, string(cluster)))
+			Expect(limitRange.Spec.Limits).To(HaveLen(1))
+			Expect(limitRange.Spec.Limits[0].DefaultRequest.Cpu().Cmp(resource.MustParse("100m"))).To(BeZero())
+			Expect(limitRange.Spec.Limits[0].Default.Memory().Cmp(resource.MustParse("512Mi"))).To(BeZero())
+
+			_, err = clientset.NetworkingV1().NetworkPolicies(namespace.Name).Get(ctx, NetworkPolicyName, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Changing the policies and enforcing them again")
+			policies.DefaultLimits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}
+			Expect(manager.enforcePolicies(ctx, namespace, cluster)).To(Succeed())
+
+			limitRange, err = clientset.CoreV1().LimitRanges(namespace.Name).Get(ctx, LimitRangeName, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(limitRange.Spec.Limits[0].Default.Memory().Cmp(resource.MustParse("1Gi"))).To(BeZero())
+		})
+
+		It("should enforce the policies only once per namespace", func() {
+			namespace.UID = "uid"
+			Expect(manager.ensurePolicies(ctx, namespace, cluster)).To(Succeed())
+			actions := len(clientset.Actions())
+			Expect(actions).ToNot(BeZero())
+
+			Expect(manager.ensurePolicies(ctx, namespace, cluster)).To(Succeed())
+			Expect(clientset.Actions()).To(HaveLen(actions))
+
+			By("Recreating the namespace")
+			namespace.UID = "another-uid"
+			Expect(manager.ensurePolicies(ctx, namespace, cluster)).To(Succeed())
+			Expect(len(clientset.Actions())).To(BeNumerically(">", actions))
+		})
+
+		It("should not create any object if no policy is configured", func() {
+			manager = NewManager(clientset, nil).(*tenantNamespaceManager)
+			Expect(manager.enforcePolicies(ctx, namespace, cluster)).To(Succeed())
+
+			limitRanges, err := clientset.CoreV1().LimitRanges(namespace.Name).List(ctx, metav1.ListOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(limitRanges.Items).To(BeEmpty())
+			policies, err := clientset.NetworkingV1().NetworkPolicies(namespace.Name).List(ctx, metav1.ListOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policies.Items).To(BeEmpty())
+		})
+	})
+})
@@ -15,8 +15,12 @@
 package csr
 
 import (
+	"crypto"
+	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
@@ -24,9 +28,56 @@ import (
 	"fmt"
 )
 
-// NewKeyAndRequest returns a new private key, and the corresponding CSR for the given subject.
-func NewKeyAndRequest(commonName string) (keyBytes, csrBytes []byte, err error) {
-	_, key, err := ed25519.GenerateKey(rand.Reader)
+// KeyAlgorithm is the algorithm of a private key.
+type KeyAlgorithm string
+
+const (
+	// KeyAlgorithmEd25519 identifies Ed25519 keys.
+	KeyAlgorithmEd25519 KeyAlgorithm = "Ed25519"
+	// KeyAlgorithmECDSA identifies ECDSA keys, on the P-256 curve.
+	KeyAlgorithmECDSA KeyAlgorithm = "ECDSA"
+	// KeyAlgorithmRSA identifies 2048 bits RSA keys.
+	KeyAlgorithmRSA KeyAlgorithm = "RSA"
+)
+
+// KeyAlgorithms returns the list of the supported key algorithms.
+func KeyAlgorithms() []string {
+	return []string{string(KeyAlgorithmEd25519), string(KeyAlgorithmECDSA), string(KeyAlgorithmRSA)}
+}
+
+// NewKey returns a new private key of the given algorithm (Ed25519 if empty).
+func NewKey(algorithm KeyAlgorithm) (crypto.Signer, error) {
+	switch algorithm {
+	case KeyAlgorithmEd25519, "":
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	case KeyAlgorithmECDSA:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyAlgorithmRSA:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	default:
+		return nil, fmt.Errorf("unsupported key algorithm %q", algorithm)
+	}
+}
+
+// SignatureAlgorithm returns the algorithm used to sign the requests with the given private key.
+func SignatureAlgorithm(key crypto.Signer) (x509.SignatureAlgorithm, error) {
+	switch key.Public().(type) {
+	case ed25519.PublicKey:
+		return x509.PureEd25519, nil
+	case *ecdsa.PublicKey:
+		return x509.ECDSAWithSHA256, nil
+	case *rsa.PublicKey:
+		return x509.SHA256WithRSA, nil
+	default:
+		return x509.UnknownSignatureAlgorithm, fmt.Errorf("unsupported private key type %T", key)
+	}
+}
+
+// NewKeyAndRequest returns a new private key of the given algorithm (Ed25519 if empty),
+// and the corresponding CSR for the given subject.
+func NewKeyAndRequest(commonName string, algorithm KeyAlgorithm) (keyBytes, csrBytes []byte, err error) {
+	key, err := NewKey(algorithm)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate private key: %w", err)
 	}
@@ -36,6 +87,11 @@ func NewKeyAndRequest(commonName string) (keyBytes, csrBytes []byte, err error)
 		return nil, nil, fmt.Errorf("failed to marshal private key: %w", err)
 	}
 
+	signatureAlgorithm, err := SignatureAlgorithm(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	asn1Subj, err := asn1.Marshal(pkix.Name{CommonName: commonName, Organization: []string{"liqo.io"}}.ToRDNSequence())
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to marshal subject information: %w", err)
@@ -43,7 +99,7 @@ func NewKeyAndRequest(commonName string) (keyBytes, csrBytes []byte, err error)
 
 	template := x509.CertificateRequest{
 		RawSubject:         asn1Subj,
-		SignatureAlgorithm: x509.PureEd25519,
+		SignatureAlgorithm: signatureAlgorithm,
 	}
 
 	csrBytes, err = x509.CreateCertificateRequest(rand.Reader, &template, key)
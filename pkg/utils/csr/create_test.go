@@ -0,0 +1,58 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csr
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+)
+
+var _ = Describe("Creation functions", func() {
+	DescribeTable("generating a key and the corresponding CSR",
+		func(algorithm KeyAlgorithm, publicKeyMatcher types.GomegaMatcher) {
+			keyBytes, csrBytes, err := NewKeyAndRequest("common-name", algorithm)
+			Expect(err).ToNot(HaveOccurred())
+
+			keyBlock, _ := pem.Decode(keyBytes)
+			Expect(keyBlock).ToNot(BeNil())
+			_, err = x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+			Expect(err).ToNot(HaveOccurred())
+
+			csrBlock, _ := pem.Decode(csrBytes)
+			Expect(csrBlock).ToNot(BeNil())
+			request, err := x509.ParseCertificateRequest(csrBlock.Bytes)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(request.CheckSignature()).To(Succeed())
+			Expect(request.Subject.CommonName).To(Equal("common-name"))
+			Expect(request.PublicKey).To(publicKeyMatcher)
+		},
+		Entry("with the default algorithm", KeyAlgorithm(""), BeAssignableToTypeOf(ed25519.PublicKey{})),
+		Entry("with the Ed25519 algorithm", KeyAlgorithmEd25519, BeAssignableToTypeOf(ed25519.PublicKey{})),
+		Entry("with the ECDSA algorithm", KeyAlgorithmECDSA, BeAssignableToTypeOf(&ecdsa.PublicKey{})),
+		Entry("with the RSA algorithm", KeyAlgorithmRSA, BeAssignableToTypeOf(&rsa.PublicKey{})),
+	)
+
+	It("should fail with an unsupported algorithm", func() {
+		_, _, err := NewKeyAndRequest("common-name", KeyAlgorithm("DSA"))
+		Expect(err).To(HaveOccurred())
+	})
+})
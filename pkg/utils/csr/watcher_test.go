@@ -54,7 +54,7 @@ var _ = Describe("Watcher functions", func() {
 		)
 
 		CSRForger := func(name, label string) *certv1.CertificateSigningRequest {
-			_, req, err := NewKeyAndRequest("foobar")
+			_, req, err := NewKeyAndRequest("foobar", KeyAlgorithmEd25519)
 			Expect(err).ToNot(HaveOccurred())
 
 			return &certv1.CertificateSigningRequest{
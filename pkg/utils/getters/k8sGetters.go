@@ -469,6 +469,15 @@ func ListShadowPodsByCreator(ctx context.Context, cl client.Client, creator stri
 	return list, nil
 }
 
+// ListShadowJobsByCreator returns the list of ShadowJobs created by the given user.
+func ListShadowJobsByCreator(ctx context.Context, cl client.Client, creator string) (*offloadingv1beta1.ShadowJobList, error) {
+	list := new(offloadingv1beta1.ShadowJobList)
+	if err := cl.List(ctx, list, client.MatchingLabels{consts.CreatorLabelKey: creator}); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
 // GetQuotaByUser returns the list of Quotas for the given user.
 func GetQuotaByUser(ctx context.Context, cl client.Client,
 	user string) (*offloadingv1beta1.Quota, error) {
@@ -527,6 +536,40 @@ func ListVirtualNodesByClusterID(ctx context.Context, cl client.Client,
 	return virtualNodes.Items, nil
 }
 
+// ListVirtualNodesByResourceSlice returns the VirtualNodes of the given ResourceSlice, i.e., the ones labeled with its name
+// or controlled by it.
+func ListVirtualNodesByResourceSlice(ctx context.Context, cl client.Client,
+	resourceSlice *authv1beta1.ResourceSlice) ([]offloadingv1beta1.VirtualNode, error) {
+	var virtualNodes offloadingv1beta1.VirtualNodeList
+	if err := cl.List(ctx, &virtualNodes, client.InNamespace(resourceSlice.Namespace)); err != nil {
+		return nil, fmt.Errorf("unable to list the VirtualNodes in namespace %q: %w", resourceSlice.Namespace, err)
+	}
+
+	var result []offloadingv1beta1.VirtualNode
+	for i := range virtualNodes.Items {
+		virtualNode := &virtualNodes.Items[i]
+		if virtualNode.Labels[consts.ResourceSliceNameLabelKey] == resourceSlice.Name || metav1.IsControlledBy(virtualNode, resourceSlice) {
+			result = append(result, *virtualNode)
+		}
+	}
+	return result, nil
+}
+
+// GetVirtualNodeByResourceSlice returns the VirtualNode of the given ResourceSlice, i.e., the one labeled with its name
+// or controlled by it. A NotFound error is returned if no VirtualNode refers to the ResourceSlice.
+func GetVirtualNodeByResourceSlice(ctx context.Context, cl client.Client,
+	resourceSlice *authv1beta1.ResourceSlice) (*offloadingv1beta1.VirtualNode, error) {
+	virtualNodes, err := ListVirtualNodesByResourceSlice(ctx, cl, resourceSlice)
+	if err != nil {
+		return nil, err
+	}
+	if len(virtualNodes) == 0 {
+		return nil, kerrors.NewNotFound(offloadingv1beta1.VirtualNodeGroupResource,
+			fmt.Sprintf("of ResourceSlice %s", client.ObjectKeyFromObject(resourceSlice)))
+	}
+	return &virtualNodes[0], nil
+}
+
 // GetNodeFromVirtualNode returns the node object from the given virtual node name.
 func GetNodeFromVirtualNode(ctx context.Context, cl client.Client, virtualNode *offloadingv1beta1.VirtualNode) (*corev1.Node, error) {
 	nodename := virtualNode.Name
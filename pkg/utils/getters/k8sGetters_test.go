@@ -0,0 +1,67 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package getters_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+)
+
+var _ = Describe("the GetVirtualNodeByResourceSlice function", func() {
+	var (
+		scheme        *runtime.Scheme
+		resourceSlice *authv1beta1.ResourceSlice
+	)
+
+	BeforeEach(func() {
+		scheme = runtime.NewScheme()
+		Expect(authv1beta1.AddToScheme(scheme)).To(Succeed())
+		Expect(offloadingv1beta1.AddToScheme(scheme)).To(Succeed())
+
+		resourceSlice = &authv1beta1.ResourceSlice{ObjectMeta: metav1.ObjectMeta{Name: "slice", Namespace: "tenant", UID: "uid"}}
+	})
+
+	It("should return the VirtualNode labeled with the name of the ResourceSlice", func() {
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&offloadingv1beta1.VirtualNode{ObjectMeta: metav1.ObjectMeta{Name: "slice", Namespace: "tenant"}},
+			&offloadingv1beta1.VirtualNode{ObjectMeta: metav1.ObjectMeta{Name: "custom", Namespace: "tenant",
+				Labels: map[string]string{consts.ResourceSliceNameLabelKey: "slice"}}},
+		).Build()
+
+		virtualNode, err := getters.GetVirtualNodeByResourceSlice(context.Background(), cl, resourceSlice)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(virtualNode.Name).To(Equal("custom"))
+	})
+
+	It("should return a NotFound error if no VirtualNode refers to the ResourceSlice", func() {
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&offloadingv1beta1.VirtualNode{ObjectMeta: metav1.ObjectMeta{Name: "slice", Namespace: "other"}},
+		).Build()
+
+		_, err := getters.GetVirtualNodeByResourceSlice(context.Background(), cl, resourceSlice)
+		Expect(kerrors.IsNotFound(err)).To(BeTrue())
+	})
+})
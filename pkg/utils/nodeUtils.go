@@ -80,6 +80,16 @@ func IsVirtualNode(node *corev1.Node) bool {
 	return found && nodeType == liqoconst.TypeNode
 }
 
+// IsNodeFailedOver returns true if the passed node has been tainted as part of the failover process, false otherwise.
+func IsNodeFailedOver(node *corev1.Node) bool {
+	for i := range node.Spec.Taints {
+		if node.Spec.Taints[i].Key == liqoconst.ProviderUnavailableTaintKey {
+			return true
+		}
+	}
+	return false
+}
+
 // MergeNodeSelector merges two nodeSelectors.
 // Every MatchExpression of the first one must be merged with all the MatchExpressions of the second one:
 // n first MatchExpressions.
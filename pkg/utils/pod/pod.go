@@ -18,6 +18,7 @@ import (
 	"reflect"
 
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/ptr"
 )
@@ -55,13 +56,16 @@ func GetPodCondition(status *corev1.PodStatus, conditionType corev1.PodCondition
 func IsPodSpecEqual(previous, updated *corev1.PodSpec) bool {
 	// The only fields that can be mutated are:
 	// * spec.containers[*].image
+	// * spec.containers[*].resources (through in-place resize)
 	// * spec.initContainers[*].image
 	// * spec.activeDeadlineSeconds
 	// * spec.tolerations (only new entries can be added)
+	// * spec.ephemeralContainers (only new entries can be added)
 	return AreContainersEqual(previous.Containers, updated.Containers) &&
 		AreContainersEqual(previous.InitContainers, updated.InitContainers) &&
 		ptr.Equal(previous.ActiveDeadlineSeconds, updated.ActiveDeadlineSeconds) &&
-		len(previous.Tolerations) == len(updated.Tolerations)
+		len(previous.Tolerations) == len(updated.Tolerations) &&
+		len(previous.EphemeralContainers) == len(updated.EphemeralContainers)
 }
 
 // CheckShadowPodUpdate returns whether updated equals previous, except for the fields that are allowed to be updated.
@@ -69,11 +73,23 @@ func IsPodSpecEqual(previous, updated *corev1.PodSpec) bool {
 func CheckShadowPodUpdate(previous, updated *corev1.PodSpec) bool {
 	// The only fields that can be mutated are:
 	// * spec.containers[*].image
+	// * spec.containers[*].resources (through in-place resize)
 	// * spec.initContainers[*].image
 	// * spec.activeDeadlineSeconds
 	// * spec.tolerations (only new entries can be added)
+	// * spec.ephemeralContainers (only new entries can be added)
+	if !AreEphemeralContainersAppended(previous.EphemeralContainers, updated.EphemeralContainers) {
+		return false
+	}
+	updated.EphemeralContainers = previous.EphemeralContainers
+
+	if len(previous.Containers) != len(updated.Containers) || len(previous.InitContainers) != len(updated.InitContainers) {
+		return false
+	}
+
 	for i := range updated.Containers {
 		updated.Containers[i].Image = previous.Containers[i].Image
+		updated.Containers[i].Resources = previous.Containers[i].Resources
 	}
 	for i := range updated.InitContainers {
 		updated.InitContainers[i].Image = previous.InitContainers[i].Image
@@ -84,7 +100,7 @@ func CheckShadowPodUpdate(previous, updated *corev1.PodSpec) bool {
 }
 
 // AreContainersEqual returns whether two container lists are equal according to the
-// fields that can be modified after start-up time (i.e. the image field).
+// fields that can be modified after start-up time (i.e. the image and resources fields).
 func AreContainersEqual(previous, updated []corev1.Container) bool {
 	if len(previous) != len(updated) {
 		return false
@@ -94,7 +110,8 @@ outer:
 	for i := range previous {
 		for j := range updated {
 			if previous[i].Name == updated[j].Name {
-				if previous[i].Image == updated[j].Image {
+				if previous[i].Image == updated[j].Image &&
+					apiequality.Semantic.DeepEqual(previous[i].Resources, updated[j].Resources) {
 					continue outer
 				}
 				return false
@@ -106,6 +123,35 @@ outer:
 	return true
 }
 
+// AreContainersResourcesEqual returns whether the resources of the containers with the same name are equal.
+func AreContainersResourcesEqual(previous, updated []corev1.Container) bool {
+	for i := range previous {
+		for j := range updated {
+			if previous[i].Name == updated[j].Name && !apiequality.Semantic.DeepEqual(previous[i].Resources, updated[j].Resources) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// AreEphemeralContainersAppended returns whether the updated ephemeral containers list is obtained from the previous
+// one only by appending new entries, since existing ephemeral containers can be neither modified nor removed.
+func AreEphemeralContainersAppended(previous, updated []corev1.EphemeralContainer) bool {
+	if len(updated) < len(previous) {
+		return false
+	}
+
+	for i := range previous {
+		if !reflect.DeepEqual(previous[i], updated[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // ForgeContainerResources forges the container resource requirements, leaving unset the ones not specified.
 func ForgeContainerResources(cpuRequests, cpuLimits, ramRequests, ramLimits resource.Quantity) corev1.ResourceRequirements {
 	configure := func(rl corev1.ResourceList, key corev1.ResourceName, value resource.Quantity) {
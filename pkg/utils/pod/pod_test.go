@@ -110,6 +110,68 @@ var _ = Describe("Pod utility functions", func() {
 				updated:  corev1.PodSpec{ActiveDeadlineSeconds: nil},
 				expected: BeFalse(),
 			}),
+			Entry("more ephemeral containers are present", TestCase{
+				previous: corev1.PodSpec{},
+				updated: corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{
+					{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger", Image: "busybox"}}}},
+				expected: BeFalse(),
+			}),
+		)
+	})
+
+	Describe("The CheckShadowPodUpdate function", func() {
+		type TestCase struct {
+			previous corev1.PodSpec
+			updated  corev1.PodSpec
+			expected types.GomegaMatcher
+		}
+
+		debugger := func(name string) corev1.EphemeralContainer {
+			return corev1.EphemeralContainer{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: name, Image: "busybox"}}
+		}
+
+		DescribeTable("tests table",
+			func(c TestCase) {
+				Expect(pod.CheckShadowPodUpdate(&c.previous, &c.updated)).To(c.expected)
+			},
+			Entry("both specs are empty", TestCase{expected: BeTrue()}),
+			Entry("the container images are different", TestCase{
+				previous: corev1.PodSpec{Containers: []corev1.Container{{Name: "foo", Image: "bar"}}},
+				updated:  corev1.PodSpec{Containers: []corev1.Container{{Name: "foo", Image: "baz"}}},
+				expected: BeTrue(),
+			}),
+			Entry("the restart policy is different", TestCase{
+				previous: corev1.PodSpec{RestartPolicy: corev1.RestartPolicyAlways},
+				updated:  corev1.PodSpec{RestartPolicy: corev1.RestartPolicyNever},
+				expected: BeFalse(),
+			}),
+			Entry("the container resources are different", TestCase{
+				previous: corev1.PodSpec{Containers: []corev1.Container{{Name: "foo", Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}}}}},
+				updated: corev1.PodSpec{Containers: []corev1.Container{{Name: "foo", Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")}}}}},
+				expected: BeTrue(),
+			}),
+			Entry("a container is added", TestCase{
+				previous: corev1.PodSpec{Containers: []corev1.Container{{Name: "foo", Image: "bar"}}},
+				updated:  corev1.PodSpec{Containers: []corev1.Container{{Name: "foo", Image: "bar"}, {Name: "bar", Image: "baz"}}},
+				expected: BeFalse(),
+			}),
+			Entry("an ephemeral container is appended", TestCase{
+				previous: corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{debugger("foo")}},
+				updated:  corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{debugger("foo"), debugger("bar")}},
+				expected: BeTrue(),
+			}),
+			Entry("an ephemeral container is removed", TestCase{
+				previous: corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{debugger("foo"), debugger("bar")}},
+				updated:  corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{debugger("foo")}},
+				expected: BeFalse(),
+			}),
+			Entry("an ephemeral container is modified", TestCase{
+				previous: corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{debugger("foo")}},
+				updated:  corev1.PodSpec{EphemeralContainers: []corev1.EphemeralContainer{debugger("bar")}},
+				expected: BeFalse(),
+			}),
 		)
 	})
 
@@ -146,6 +208,43 @@ var _ = Describe("Pod utility functions", func() {
 				updated:  []corev1.Container{{Name: "bar", Image: "baz"}},
 				expected: BeFalse(),
 			}),
+			Entry("the two lists have elements with different resources", TestCase{
+				previous: []corev1.Container{{Name: "foo", Image: "bar", Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("100Mi")}}}},
+				updated: []corev1.Container{{Name: "foo", Image: "bar", Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("200Mi")}}}},
+				expected: BeFalse(),
+			}),
+		)
+	})
+
+	Describe("The AreContainersResourcesEqual function", func() {
+		type TestCase struct {
+			previous []corev1.Container
+			updated  []corev1.Container
+			expected types.GomegaMatcher
+		}
+
+		container := func(name, cpu string) corev1.Container {
+			return corev1.Container{Name: name, Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}}}
+		}
+
+		DescribeTable("tests table",
+			func(c TestCase) {
+				Expect(pod.AreContainersResourcesEqual(c.previous, c.updated)).To(c.expected)
+			},
+			Entry("both lists are nil", TestCase{expected: BeTrue()}),
+			Entry("the resources are semantically equal", TestCase{
+				previous: []corev1.Container{container("foo", "1"), container("bar", "500m")},
+				updated:  []corev1.Container{container("bar", "0.5"), container("foo", "1000m")},
+				expected: BeTrue(),
+			}),
+			Entry("the resources are different", TestCase{
+				previous: []corev1.Container{container("foo", "1"), container("bar", "500m")},
+				updated:  []corev1.Container{container("foo", "1"), container("bar", "1")},
+				expected: BeFalse(),
+			}),
 		)
 	})
 
@@ -39,6 +39,27 @@ var WellKnownResources = []string{
 	corev1.ResourcePods.String(),
 }
 
+// ObjectQuotaResources contains the resources limiting the objects reflected to the provider cluster
+// (enforced through the Quota), rather than the capacity exposed by the virtual nodes.
+var ObjectQuotaResources = []corev1.ResourceName{
+	corev1.ResourceServices,
+	corev1.ResourceConfigMaps,
+	corev1.ResourceSecrets,
+	corev1.ResourcePersistentVolumeClaims,
+	corev1.ResourceRequestsStorage,
+}
+
+// NodeResources returns the given resources, excluding the ones limiting the reflected objects.
+func NodeResources(r corev1.ResourceList) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	for k, v := range r {
+		if !slices.Contains(ObjectQuotaResources, k) {
+			result[k] = v.DeepCopy()
+		}
+	}
+	return result
+}
+
 // CPU returns the CPU quantity as a string.
 func CPU(r corev1.ResourceList) string {
 	result := r.Cpu().ScaledValue(resource.Milli)
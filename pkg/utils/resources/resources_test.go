@@ -135,4 +135,19 @@ var _ = Describe("Shared Resources utility functions", func() {
 			Expect(resources.Others(res)).To(Equal(resources.Others(resExpected)))
 		})
 	})
+	When("the resources exposed by a virtual node are retrieved", func() {
+		It("Should exclude the resources limiting the reflected objects", func() {
+			res := resources.NodeResources(corev1.ResourceList{
+				corev1.ResourceCPU:             *cpuQuantityShared,
+				corev1.ResourcePods:            *podsQuantityShared,
+				corev1.ResourceServices:        *podsQuantityShared,
+				corev1.ResourceRequestsStorage: *memQuantityShared,
+				"nvidia.com/gpu":               *otherQuantityShared,
+			})
+			Expect(res).To(HaveLen(3))
+			Expect(res).To(HaveKey(corev1.ResourceCPU))
+			Expect(res).To(HaveKey(corev1.ResourcePods))
+			Expect(res).To(HaveKey(corev1.ResourceName("nvidia.com/gpu")))
+		})
+	})
 })
@@ -39,6 +39,12 @@ const (
 
 	// EventFailedSATokensReflection -> the reason for the event when the reflection of service account tokens fails.
 	EventFailedSATokensReflection = "FailedSATokensReflection"
+
+	// EventPreempted -> the reason for the event when the remote object has been preempted by the provider cluster.
+	EventPreempted = "Preempted"
+
+	// EventRejected -> the reason for the event when the remote object has been rejected by the policies of the provider cluster.
+	EventRejected = "Rejected"
 )
 
 // EventSuccessfulReflectionMsg returns the message for the event when the outgoing reflection completes successfully.
@@ -67,6 +73,16 @@ func EventFailedReflectionAlreadyExistsMsg() string {
 	return fmt.Sprintf("Error reflecting object to cluster %q: remote object already exists", RemoteCluster)
 }
 
+// EventPreemptedMsg returns the message for the event when the remote object has been preempted by the provider cluster.
+func EventPreemptedMsg(message string) string {
+	return fmt.Sprintf("Remote object preempted by cluster %q: %s", RemoteCluster, message)
+}
+
+// EventRejectedMsg returns the message for the event when the remote object has been rejected by the policies of the provider cluster.
+func EventRejectedMsg(message string) string {
+	return fmt.Sprintf("Remote object rejected by the policies of cluster %q: %s", RemoteCluster, message)
+}
+
 // EventFailedLabelsUpdateMsg returns the message for the event when it is impossible to update the labels of a local object.
 func EventFailedLabelsUpdateMsg(err error) string {
 	return fmt.Sprintf("Error updating local object labels: %v", err)
@@ -0,0 +1,191 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/maps"
+)
+
+const (
+	// legacyJobNameLabel is the legacy label key added by the job controller to the pods, including the name of the job.
+	legacyJobNameLabel = "job-name"
+	// legacyControllerUIDLabel is the legacy label key added by the job controller to the pods, including the UID of the job.
+	legacyControllerUIDLabel = "controller-uid"
+
+	// RemoteJobPodLabel is the label key added to the local placeholders of the pods of the jobs executed remotely,
+	// whose value is the name of the corresponding job.
+	RemoteJobPodLabel = "liqo.io/remote-job"
+)
+
+// IsShadowJobCandidate returns whether the given job should be executed remotely through a ShadowJob by the given virtual node,
+// either because explicitly bound to it, or because it has been scheduled onto it.
+func IsShadowJobCandidate(job *batchv1.Job, nodeName string) bool {
+	if job.Spec.ManagedBy == nil || *job.Spec.ManagedBy != liqoconst.ShadowJobManagedBy {
+		return false
+	}
+	if job.Spec.Template.Spec.NodeName != "" {
+		return job.Spec.Template.Spec.NodeName == nodeName
+	}
+	return job.Annotations[liqoconst.ShadowJobNodeAnnotationKey] == nodeName
+}
+
+// ShadowJobAPIServerSupport returns the API server support mode enforced for the pods of the jobs executed remotely,
+// given the one configured for the virtual node. The token-based modes fall back to disabled, since the pods are created
+// by the provider cluster, and no token bound to them can be issued by the local one.
+func ShadowJobAPIServerSupport(apiServerSupport APIServerSupportType) APIServerSupportType {
+	if apiServerSupport == APIServerSupportRemote {
+		return APIServerSupportRemote
+	}
+	return APIServerSupportDisabled
+}
+
+// RemoteShadowJob forges the reflected shadowjob, given the local job.
+func RemoteShadowJob(local *batchv1.Job, remote *offloadingv1beta1.ShadowJob,
+	targetNamespace string, forgingOpts *ForgingOpts, mutators ...RemotePodSpecMutator) *offloadingv1beta1.ShadowJob {
+	var creation bool
+	if remote == nil {
+		// The remote is nil if not already created.
+		creation = true
+		remote = &offloadingv1beta1.ShadowJob{ObjectMeta: metav1.ObjectMeta{Name: local.GetName(), Namespace: targetNamespace}}
+	}
+
+	localMetaFiltered := local.ObjectMeta.DeepCopy()
+	if localMetaFiltered.GetLabels() == nil {
+		localMetaFiltered.Labels = map[string]string{}
+	}
+	localMetaFiltered.GetLabels()[LiqoOriginClusterNodeName] = LiqoNodeName
+
+	// Filter out the labels and annotations not to be reflected.
+	localMetaFiltered.SetLabels(FilterNotReflected(localMetaFiltered.GetLabels(), forgingOpts.LabelsNotReflected))
+	localMetaFiltered.SetAnnotations(FilterNotReflected(localMetaFiltered.GetAnnotations(), forgingOpts.AnnotationsNotReflected))
+
+	return &offloadingv1beta1.ShadowJob{
+		ObjectMeta: RemoteObjectMeta(localMetaFiltered, &remote.ObjectMeta),
+		Spec: offloadingv1beta1.ShadowJobSpec{
+			Job: RemoteJobSpec(creation, local.Spec.DeepCopy(), remote.Spec.Job.DeepCopy(), forgingOpts, mutators...),
+		},
+	}
+}
+
+// RemoteJobSpec forges the specs of the reflected job, given the local ones.
+// It expects the local and remote objects to be deepcopies, as they are mutated.
+func RemoteJobSpec(creation bool, local, remote *batchv1.JobSpec, forgingOpts *ForgingOpts,
+	mutators ...RemotePodSpecMutator) batchv1.JobSpec {
+	// The pod template is immutable once the job has been created, hence only the fields
+	// that can be modified at run time (e.g., to suspend or scale the job) are propagated.
+	if !creation {
+		remote.Parallelism = local.Parallelism
+		remote.Suspend = local.Suspend
+		remote.ActiveDeadlineSeconds = local.ActiveDeadlineSeconds
+		return *remote
+	}
+
+	// The remote job is handled by the built-in job controller of the provider cluster,
+	// which also takes care of generating the appropriate selector.
+	local.ManagedBy = nil
+	local.Selector = nil
+	local.ManualSelector = nil
+
+	template := &local.Template
+	template.Labels = maps.Filter(template.Labels, maps.FilterBlacklist(batchv1.JobNameLabel, batchv1.ControllerUidLabel,
+		legacyJobNameLabel, legacyControllerUIDLabel))
+	template.Labels = FilterNotReflected(template.Labels, forgingOpts.LabelsNotReflected)
+	template.Annotations = FilterNotReflected(template.Annotations, forgingOpts.AnnotationsNotReflected)
+
+	mutators = append(mutators, RuntimeClassNameMutator(&corev1.Pod{ObjectMeta: template.ObjectMeta, Spec: template.Spec}, forgingOpts))
+	template.Spec = RemotePodSpec(true, template.Spec.DeepCopy(), &corev1.PodSpec{}, mutators...)
+
+	return *local
+}
+
+// IsRemoteJobPod returns whether the given local pod is the placeholder of a pod of a job executed remotely.
+func IsRemoteJobPod(pod *corev1.Pod) bool {
+	name, found := pod.Labels[RemoteJobPodLabel]
+	owner := metav1.GetControllerOf(pod)
+	return found && owner != nil && owner.Kind == "Job" && owner.Name == name
+}
+
+// IsRemoteJobPodOf returns whether the given remote pod has been created by the job with the given name.
+func IsRemoteJobPodOf(pod *corev1.Pod, name string) bool {
+	owner := metav1.GetControllerOf(pod)
+	return owner != nil && owner.Kind == "Job" && owner.Name == name
+}
+
+// LocalJobPod forges the local placeholder of the given remote pod, belonging to the given local job.
+// Placeholders are bound to the virtual node and expose the status of the remote pods, so that they can be
+// listed through the selector of the local job, and their logs retrieved as for any other offloaded pod.
+func LocalJobPod(local *batchv1.Job, remote *corev1.Pod) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            remote.GetName(),
+			Namespace:       local.GetNamespace(),
+			Labels:          labels.Merge(local.Spec.Template.Labels, labels.Set{RemoteJobPodLabel: local.GetName()}),
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(local, batchv1.SchemeGroupVersion.WithKind("Job"))},
+		},
+		Spec: LocalJobPodSpec(&local.Spec.Template.Spec),
+	}
+}
+
+// LocalJobPodSpec forges the specs of the local placeholders of the pods of a job executed remotely, given its pod template.
+// Only the fields describing the containers are retained, as the placeholders are never executed locally,
+// and they tolerate all taints, not to be evicted from the virtual node while the remote pods are running.
+func LocalJobPodSpec(template *corev1.PodSpec) corev1.PodSpec {
+	placeholders := func(containers []corev1.Container) []corev1.Container {
+		output := make([]corev1.Container, 0, len(containers))
+		for i := range containers {
+			output = append(output, corev1.Container{
+				Name:          containers[i].Name,
+				Image:         containers[i].Image,
+				Command:       containers[i].Command,
+				Args:          containers[i].Args,
+				Ports:         containers[i].Ports,
+				Resources:     containers[i].Resources,
+				RestartPolicy: containers[i].RestartPolicy,
+			})
+		}
+		return output
+	}
+
+	return corev1.PodSpec{
+		NodeName:                      LiqoNodeName,
+		RestartPolicy:                 template.RestartPolicy,
+		InitContainers:                placeholders(template.InitContainers),
+		Containers:                    placeholders(template.Containers),
+		AutomountServiceAccountToken:  ptr.To(false),
+		TerminationGracePeriodSeconds: ptr.To[int64](0),
+		Tolerations:                   []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+	}
+}
+
+// LocalJobPodStatus forges the status of the local placeholder of a pod of a job executed remotely.
+// The pod IPs are not exposed, as the placeholders are not reachable from the local cluster.
+func LocalJobPodStatus(local *corev1.Pod, remote *corev1.PodStatus) corev1.PodStatus {
+	status := remote.DeepCopy()
+	status.PodIP = ""
+	status.PodIPs = nil
+	status.HostIP = LiqoNodeIP
+	status.HostIPs = []corev1.HostIP{{IP: LiqoNodeIP}}
+	// The QoS class is immutable, and it is computed by the local API server starting from the specs of the placeholder.
+	status.QOSClass = local.Status.QOSClass
+	return *status
+}
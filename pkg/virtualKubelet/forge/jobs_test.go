@@ -0,0 +1,180 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/testutil"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+var _ = Describe("Jobs Forging", func() {
+	var local *batchv1.Job
+
+	BeforeEach(func() {
+		local = &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "local-name", Namespace: "local-namespace",
+				Labels: map[string]string{
+					"foo":                             "bar",
+					testutil.FakeNotReflectedLabelKey: "true",
+				},
+			},
+			Spec: batchv1.JobSpec{
+				Parallelism:    ptr.To[int32](3),
+				ManagedBy:      ptr.To(consts.ShadowJobManagedBy),
+				ManualSelector: ptr.To(false),
+				Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{batchv1.ControllerUidLabel: "local-uid"}},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+						"app":                             "batch",
+						batchv1.ControllerUidLabel:        "local-uid",
+						batchv1.JobNameLabel:              "local-name",
+						"controller-uid":                  "local-uid",
+						"job-name":                        "local-name",
+						testutil.FakeNotReflectedLabelKey: "true",
+					}},
+					Spec: corev1.PodSpec{
+						NodeName:                      LiqoNodeName,
+						RestartPolicy:                 corev1.RestartPolicyNever,
+						TerminationGracePeriodSeconds: ptr.To[int64](15),
+					},
+				},
+			},
+		}
+	})
+
+	Describe("the IsShadowJobCandidate function", func() {
+		It("should return true for jobs managed by liqo and targeting the given node", func() {
+			Expect(forge.IsShadowJobCandidate(local, LiqoNodeName)).To(BeTrue())
+		})
+
+		It("should return false for jobs targeting a different node", func() {
+			Expect(forge.IsShadowJobCandidate(local, "other-node")).To(BeFalse())
+		})
+
+		It("should return false for jobs not managed by liqo", func() {
+			local.Spec.ManagedBy = nil
+			Expect(forge.IsShadowJobCandidate(local, LiqoNodeName)).To(BeFalse())
+		})
+
+		It("should return true for jobs scheduled onto the given node", func() {
+			local.Spec.Template.Spec.NodeName = ""
+			local.Annotations = map[string]string{consts.ShadowJobNodeAnnotationKey: LiqoNodeName}
+			Expect(forge.IsShadowJobCandidate(local, LiqoNodeName)).To(BeTrue())
+			Expect(forge.IsShadowJobCandidate(local, "other-node")).To(BeFalse())
+		})
+
+		It("should return false for jobs not yet scheduled", func() {
+			local.Spec.Template.Spec.NodeName = ""
+			Expect(forge.IsShadowJobCandidate(local, LiqoNodeName)).To(BeFalse())
+		})
+	})
+
+	DescribeTable("the ShadowJobAPIServerSupport function",
+		func(configured, expected forge.APIServerSupportType) {
+			Expect(forge.ShadowJobAPIServerSupport(configured)).To(Equal(expected))
+		},
+		Entry("remote", forge.APIServerSupportRemote, forge.APIServerSupportRemote),
+		Entry("token API", forge.APIServerSupportTokenAPI, forge.APIServerSupportDisabled),
+		Entry("legacy", forge.APIServerSupportLegacy, forge.APIServerSupportDisabled),
+		Entry("disabled", forge.APIServerSupportDisabled, forge.APIServerSupportDisabled),
+	)
+
+	Describe("the RemoteShadowJob function", func() {
+		var (
+			remote, output *offloadingv1beta1.ShadowJob
+			forgingOpts    *forge.ForgingOpts
+		)
+
+		Mutator := func(remote *corev1.PodSpec) {
+			remote.ActiveDeadlineSeconds = ptr.To[int64](99)
+		}
+
+		BeforeEach(func() {
+			remote = nil
+			forgingOpts = testutil.FakeForgingOpts()
+		})
+
+		JustBeforeEach(func() {
+			output = forge.RemoteShadowJob(local, remote, "remote-namespace", forgingOpts, Mutator)
+		})
+
+		Context("the remote shadowjob does not exist", func() {
+			It("should correctly forge the object meta", func() {
+				Expect(output.GetName()).To(Equal("local-name"))
+				Expect(output.GetNamespace()).To(Equal("remote-namespace"))
+				Expect(output.Labels).To(HaveKeyWithValue("foo", "bar"))
+				Expect(output.Labels).To(HaveKeyWithValue(forge.LiqoOriginClusterIDKey, string(LocalClusterID)))
+				Expect(output.Labels).To(HaveKeyWithValue(forge.LiqoDestinationClusterIDKey, string(RemoteClusterID)))
+				Expect(output.Labels).To(HaveKeyWithValue(forge.LiqoOriginClusterNodeName, LiqoNodeName))
+				Expect(output.Labels).ToNot(HaveKey(testutil.FakeNotReflectedLabelKey))
+			})
+
+			It("should delegate the job to the built-in controller of the remote cluster", func() {
+				Expect(output.Spec.Job.ManagedBy).To(BeNil())
+				Expect(output.Spec.Job.ManualSelector).To(BeNil())
+				Expect(output.Spec.Job.Selector).To(BeNil())
+				Expect(output.Spec.Job.Parallelism).To(PointTo(BeNumerically("==", 3)))
+			})
+
+			It("should filter out the labels generated by the local job controller", func() {
+				Expect(output.Spec.Job.Template.Labels).To(Equal(map[string]string{"app": "batch"}))
+			})
+
+			It("should correctly reflect the pod template spec", func() {
+				Expect(output.Spec.Job.Template.Spec.NodeName).To(BeEmpty())
+				Expect(output.Spec.Job.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+				Expect(output.Spec.Job.Template.Spec.TerminationGracePeriodSeconds).To(PointTo(BeNumerically("==", 15)))
+				Expect(output.Spec.Job.Template.Spec.AutomountServiceAccountToken).To(PointTo(BeFalse()))
+			})
+
+			It("should correctly trigger the additional mutators", func() {
+				Expect(output.Spec.Job.Template.Spec.ActiveDeadlineSeconds).To(PointTo(BeNumerically("==", 99)))
+			})
+		})
+
+		Context("the remote shadowjob already exists", func() {
+			BeforeEach(func() {
+				remote = &offloadingv1beta1.ShadowJob{
+					ObjectMeta: metav1.ObjectMeta{Name: "local-name", Namespace: "remote-namespace", UID: "remote-uid"},
+					Spec: offloadingv1beta1.ShadowJobSpec{Job: batchv1.JobSpec{
+						Parallelism: ptr.To[int32](1),
+						Template:    corev1.PodTemplateSpec{Spec: corev1.PodSpec{RestartPolicy: corev1.RestartPolicyOnFailure}},
+					}},
+				}
+				local.Spec.Suspend = ptr.To(true)
+			})
+
+			It("should preserve the remote object identity", func() {
+				Expect(output.UID).To(BeEquivalentTo("remote-uid"))
+			})
+
+			It("should propagate only the mutable fields", func() {
+				Expect(output.Spec.Job.Parallelism).To(PointTo(BeNumerically("==", 3)))
+				Expect(output.Spec.Job.Suspend).To(PointTo(BeTrue()))
+				Expect(output.Spec.Job.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyOnFailure))
+			})
+		})
+	})
+})
@@ -15,6 +15,7 @@
 package forge
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
 	"strings"
@@ -24,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	corev1apply "k8s.io/client-go/applyconfigurations/core/v1"
 	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	"k8s.io/utils/ptr"
@@ -38,6 +40,10 @@ const (
 	PodOffloadingBackOffReason = "OffloadingBackOff"
 	// PodOffloadingAbortedReason -> the reason assigned to pods rejected by the virtual kubelet after offloading has started.
 	PodOffloadingAbortedReason = "OffloadingAborted"
+	// PodOffloadingPreemptedReason -> the reason assigned to pods whose remote counterpart has been preempted by the provider.
+	PodOffloadingPreemptedReason = "OffloadingPreempted"
+	// PodOffloadingRejectedReason -> the reason assigned to pods whose remote counterpart violates the policies of the provider.
+	PodOffloadingRejectedReason = "OffloadingRejected"
 
 	// ServiceAccountVolumeName is the prefix name that will be added to volumes that mount ServiceAccount secrets.
 	// This constant is taken from kubernetes/kubernetes (plugin/pkg/admission/serviceaccount/admission.go).
@@ -198,12 +204,18 @@ func RemotePodSpec(creation bool, local, remote *corev1.PodSpec, mutators ...Rem
 	// Do not mutate the pod specifications after it has been created, since it is likely the modification
 	// would be rejected by the API server, as only a very limited set of fields can be mutated.
 	// Additionally, such modification would not be currently propagated by the remote ShadowPod controller.
+	// Ephemeral containers and container resources are the only exceptions, as they can be appended (e.g., through
+	// kubectl debug) and resized in-place respectively, and they are propagated by the remote ShadowPod controller
+	// through the dedicated subresources.
 	if !creation {
+		remote.EphemeralContainers = local.EphemeralContainers
+		remote.Containers = RemoteContainersResources(remote.Containers, local.Containers)
 		return *remote
 	}
 
 	remote.Containers = local.Containers
 	remote.InitContainers = local.InitContainers
+	remote.EphemeralContainers = local.EphemeralContainers
 
 	remote.Tolerations = RemoteTolerations(local.Tolerations)
 	remote.Volumes = local.Volumes
@@ -413,6 +425,93 @@ func AffinityMutator(affinity *offloadingv1beta1.Affinity) RemotePodSpecMutator
 	}
 }
 
+// NodeAffinityIntersectionMutator is a mutator which implements the support to further restrict the node affinity
+// constraints. Differently from the AffinityMutator, the required node selector terms are ANDed with the existing ones,
+// so that the resulting constraint is never wider than the original one. The preferred terms are appended instead.
+func NodeAffinityIntersectionMutator(nodeAffinity *corev1.NodeAffinity) RemotePodSpecMutator {
+	return func(remote *corev1.PodSpec) {
+		if nodeAffinity == nil {
+			return
+		}
+
+		if remote.Affinity == nil {
+			remote.Affinity = &corev1.Affinity{}
+		}
+		if remote.Affinity.NodeAffinity == nil {
+			remote.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+		}
+
+		current := remote.Affinity.NodeAffinity
+		if required := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+			if current.RequiredDuringSchedulingIgnoredDuringExecution == nil ||
+				len(current.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) == 0 {
+				current.RequiredDuringSchedulingIgnoredDuringExecution = required.DeepCopy()
+			} else {
+				// (A1 || A2) && (B1 || B2) is equivalent to (A1 && B1) || (A1 && B2) || (A2 && B1) || (A2 && B2).
+				var terms []corev1.NodeSelectorTerm
+				for i := range current.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+					existing := &current.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[i]
+					for j := range required.NodeSelectorTerms {
+						hint := required.NodeSelectorTerms[j].DeepCopy()
+						term := existing.DeepCopy()
+						term.MatchExpressions = append(term.MatchExpressions, hint.MatchExpressions...)
+						term.MatchFields = append(term.MatchFields, hint.MatchFields...)
+						terms = append(terms, *term)
+					}
+				}
+				current.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms = terms
+			}
+		}
+
+		for i := range nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			current.PreferredDuringSchedulingIgnoredDuringExecution = append(current.PreferredDuringSchedulingIgnoredDuringExecution,
+				*nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution[i].DeepCopy())
+		}
+	}
+}
+
+// TopologySpreadConstraintsMutator is a mutator which implements the support to override the topology spread constraints.
+func TopologySpreadConstraintsMutator(constraints []corev1.TopologySpreadConstraint) RemotePodSpecMutator {
+	return func(remote *corev1.PodSpec) {
+		if constraints != nil {
+			remote.TopologySpreadConstraints = constraints
+		}
+	}
+}
+
+// SchedulingHintsMutators returns the mutators implementing the remote scheduling hints specified through
+// the annotations of the local pod (i.e., remote node selector, node affinity and topology spread constraints).
+func SchedulingHintsMutators(annotations map[string]string) ([]RemotePodSpecMutator, error) {
+	var mutators []RemotePodSpecMutator
+
+	if value, found := annotations[liqoconst.RemoteNodeSelectorAnnotKey]; found && value != "" {
+		nodeSelector, err := labels.ConvertSelectorToLabelsMap(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %q annotation: %w", liqoconst.RemoteNodeSelectorAnnotKey, err)
+		}
+		mutators = append(mutators, NodeSelectorMutator(nodeSelector))
+	}
+
+	if value, found := annotations[liqoconst.RemoteAffinityAnnotKey]; found && value != "" {
+		var nodeAffinity corev1.NodeAffinity
+		if err := json.Unmarshal([]byte(value), &nodeAffinity); err != nil {
+			return nil, fmt.Errorf("failed to parse %q annotation: %w", liqoconst.RemoteAffinityAnnotKey, err)
+		}
+		// The hint restricts the constraints enforced through the virtual node, rather than providing alternatives to them.
+		mutators = append(mutators, NodeAffinityIntersectionMutator(&nodeAffinity))
+	}
+
+	if value, found := annotations[liqoconst.RemoteTopologySpreadConstraintsAnnotKey]; found && value != "" {
+		var constraints []corev1.TopologySpreadConstraint
+		if err := json.Unmarshal([]byte(value), &constraints); err != nil {
+			return nil, fmt.Errorf("failed to parse %q annotation: %w", liqoconst.RemoteTopologySpreadConstraintsAnnotKey, err)
+		}
+		mutators = append(mutators, TopologySpreadConstraintsMutator(constraints))
+	}
+
+	return mutators, nil
+}
+
 // FilterAntiAffinityLabels filters the label keys which are used to implement the anti-affinity constraints, based on the specified whitelist.
 func FilterAntiAffinityLabels(labels map[string]string, whitelist string) map[string]string {
 	if whitelist != "" {
@@ -502,6 +601,21 @@ func RemoteHostAliasesAPIServerSupport(aliases []corev1.HostAlias, retriever Kub
 		IP: address, Hostnames: []string{KubernetesAPIService, KubernetesAPIService + ".svc"}})
 }
 
+// RemoteContainersResources aligns the resources of the remote containers to the ones of the corresponding local containers,
+// to propagate the in-place resize requests.
+func RemoteContainersResources(remote, local []corev1.Container) []corev1.Container {
+	for i := range remote {
+		for j := range local {
+			if remote[i].Name == local[j].Name {
+				remote[i].Resources = local[j].Resources
+				break
+			}
+		}
+	}
+
+	return remote
+}
+
 // RemoteTolerations forges the tolerations for a reflected pod.
 func RemoteTolerations(inputTolerations []corev1.Toleration) []corev1.Toleration {
 	tolerations := make([]corev1.Toleration, 0)
@@ -568,19 +682,46 @@ func LocalNodeStats(pods []statsv1alpha1.PodStats) *statsv1alpha1.Summary {
 		Node: statsv1alpha1.NodeStats{
 			NodeName: LiqoNodeName, StartTime: metav1.NewTime(StartTime),
 			CPU: &statsv1alpha1.CPUStats{
-				Time:           now,
-				UsageNanoCores: SumPodStats(pods, func(s statsv1alpha1.PodStats) uint64 { return *s.CPU.UsageNanoCores }),
+				Time: now,
+				UsageNanoCores: SumPodStats(pods, func(s statsv1alpha1.PodStats) uint64 {
+					return valueOrZero(s.CPU, func(c *statsv1alpha1.CPUStats) *uint64 { return c.UsageNanoCores })
+				}),
 			},
 			Memory: &statsv1alpha1.MemoryStats{
-				Time:            now,
-				UsageBytes:      SumPodStats(pods, func(s statsv1alpha1.PodStats) uint64 { return *s.Memory.UsageBytes }),
-				WorkingSetBytes: SumPodStats(pods, func(s statsv1alpha1.PodStats) uint64 { return *s.Memory.WorkingSetBytes }),
+				Time: now,
+				UsageBytes: SumPodStats(pods, func(s statsv1alpha1.PodStats) uint64 {
+					return valueOrZero(s.Memory, func(m *statsv1alpha1.MemoryStats) *uint64 { return m.UsageBytes })
+				}),
+				WorkingSetBytes: SumPodStats(pods, func(s statsv1alpha1.PodStats) uint64 {
+					return valueOrZero(s.Memory, func(m *statsv1alpha1.MemoryStats) *uint64 { return m.WorkingSetBytes })
+				}),
 			},
 		},
 		Pods: pods,
 	}
 }
 
+// LocalPodStatsFromRemote forges the stats for a local pod managed by the virtual kubelet, given the ones retrieved
+// from the remote stats summary (which include also network, filesystem and ephemeral-storage stats).
+func LocalPodStatsFromRemote(pod *corev1.Pod, remote *statsv1alpha1.PodStats) statsv1alpha1.PodStats {
+	stats := *remote
+	stats.PodRef = statsv1alpha1.PodReference{
+		Name:      pod.GetName(),
+		Namespace: pod.GetNamespace(),
+		UID:       string(pod.GetUID()),
+	}
+
+	return stats
+}
+
+// valueOrZero returns the value retrieved from the given stats, or zero if either the stats or the value are not set.
+func valueOrZero[T any](stats *T, retriever func(*T) *uint64) uint64 {
+	if stats == nil {
+		return 0
+	}
+	return ptr.Deref(retriever(stats), 0)
+}
+
 // LocalPodStats forges the metric stats for a local pod managed by the virtual kubelet.
 func LocalPodStats(pod *corev1.Pod, metrics *metricsv1beta1.PodMetrics) statsv1alpha1.PodStats {
 	now := metav1.Now()
@@ -213,6 +213,32 @@ var _ = Describe("Pod forging", func() {
 			It("should not update the pod spec", func() {
 				Expect(output.Spec.Pod).To(Equal(corev1.PodSpec{}))
 			})
+
+			When("an ephemeral container has been added to the local pod", func() {
+				BeforeEach(func() {
+					local.Spec.EphemeralContainers = []corev1.EphemeralContainer{
+						{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger", Image: "busybox"}}}
+				})
+
+				It("should propagate the ephemeral containers only", func() {
+					Expect(output.Spec.Pod.EphemeralContainers).To(Equal(local.Spec.EphemeralContainers))
+					Expect(output.Spec.Pod.TerminationGracePeriodSeconds).To(BeNil())
+				})
+			})
+
+			When("the local pod has been resized in-place", func() {
+				BeforeEach(func() {
+					resources := corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}}
+					local.Spec.Containers = []corev1.Container{{Name: "foo", Image: "foo:v2", Resources: resources}}
+					remote.Spec.Pod.Containers = []corev1.Container{{Name: "foo", Image: "foo:v1"}}
+				})
+
+				It("should propagate the container resources only", func() {
+					Expect(output.Spec.Pod.Containers).To(HaveLen(1))
+					Expect(output.Spec.Pod.Containers[0].Image).To(Equal("foo:v1"))
+					Expect(output.Spec.Pod.Containers[0].Resources).To(Equal(local.Spec.Containers[0].Resources))
+				})
+			})
 		})
 	})
 
@@ -663,6 +689,98 @@ var _ = Describe("Pod forging", func() {
 		})
 	})
 
+	Describe("the SchedulingHintsMutators function", func() {
+		var (
+			annotations map[string]string
+			spec        corev1.PodSpec
+			err         error
+		)
+
+		BeforeEach(func() {
+			annotations = map[string]string{}
+			spec = corev1.PodSpec{NodeSelector: map[string]string{"existing": "value"}}
+		})
+
+		JustBeforeEach(func() {
+			var mutators []forge.RemotePodSpecMutator
+			mutators, err = forge.SchedulingHintsMutators(annotations)
+			for _, mutator := range mutators {
+				mutator(&spec)
+			}
+		})
+
+		When("no hint is specified", func() {
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("should leave the spec unmodified", func() {
+				Expect(spec).To(Equal(corev1.PodSpec{NodeSelector: map[string]string{"existing": "value"}}))
+			})
+		})
+
+		When("all the hints are specified", func() {
+			BeforeEach(func() {
+				annotations[consts.RemoteNodeSelectorAnnotKey] = "foo=bar,baz=qux"
+				annotations[consts.RemoteAffinityAnnotKey] = `{"requiredDuringSchedulingIgnoredDuringExecution":` +
+					`{"nodeSelectorTerms":[{"matchExpressions":[{"key":"zone","operator":"In","values":["a"]}]}]}}`
+				annotations[consts.RemoteTopologySpreadConstraintsAnnotKey] = `[{"maxSkew":1,"topologyKey":"zone","whenUnsatisfiable":"DoNotSchedule"}]`
+			})
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("should merge the node selector", func() {
+				Expect(spec.NodeSelector).To(Equal(map[string]string{"existing": "value", "foo": "bar", "baz": "qux"}))
+			})
+			It("should configure the node affinity", func() {
+				Expect(spec.Affinity).ToNot(BeNil())
+				Expect(spec.Affinity.NodeAffinity).ToNot(BeNil())
+				Expect(spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(ConsistOf(
+					corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+					}},
+				))
+			})
+			It("should configure the topology spread constraints", func() {
+				Expect(spec.TopologySpreadConstraints).To(ConsistOf(corev1.TopologySpreadConstraint{
+					MaxSkew: 1, TopologyKey: "zone", WhenUnsatisfiable: corev1.DoNotSchedule,
+				}))
+			})
+		})
+
+		When("the affinity hint is specified together with the virtual node affinity", func() {
+			BeforeEach(func() {
+				forge.AffinityMutator(&offloadingv1beta1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"liqo"}}}},
+						{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"shared"}}}},
+					}},
+				}})(&spec)
+				annotations[consts.RemoteAffinityAnnotKey] = `{"requiredDuringSchedulingIgnoredDuringExecution":` +
+					`{"nodeSelectorTerms":[{"matchExpressions":[{"key":"zone","operator":"In","values":["a"]}]}]}}`
+			})
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("should restrict each virtual node term with the hint", func() {
+				zone := corev1.NodeSelectorRequirement{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}
+				Expect(spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(ConsistOf(
+					corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"liqo"}}, zone,
+					}},
+					corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"shared"}}, zone,
+					}},
+				))
+			})
+		})
+
+		When("the node selector hint is malformed", func() {
+			BeforeEach(func() { annotations[consts.RemoteNodeSelectorAnnotKey] = "foo" })
+			It("should fail", func() { Expect(err).To(HaveOccurred()) })
+		})
+
+		When("the affinity hint is malformed", func() {
+			BeforeEach(func() { annotations[consts.RemoteAffinityAnnotKey] = "{" })
+			It("should fail", func() { Expect(err).To(HaveOccurred()) })
+		})
+	})
+
 	Describe("the RemoteTolerations function", func() {
 		var (
 			included, excluded corev1.Toleration
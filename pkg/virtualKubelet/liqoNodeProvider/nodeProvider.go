@@ -53,9 +53,11 @@ type LiqoNodeProvider struct {
 
 	networkModuleEnabled bool
 	networkReady         bool
+	failedOver           bool
 
-	onNodeChangeCallback func(*corev1.Node)
-	updateMutex          sync.Mutex
+	onNodeChangeCallback       func(*corev1.Node)
+	onFailoverRecoveryCallback func()
+	updateMutex                sync.Mutex
 }
 
 // Ping checks if the node is still active.
@@ -98,6 +100,11 @@ func (p *LiqoNodeProvider) NotifyNodeStatus(_ context.Context, f func(*corev1.No
 	p.onNodeChangeCallback = f
 }
 
+// NotifyFailoverRecovery configures the function invoked when the node is restored after having been failed over.
+func (p *LiqoNodeProvider) NotifyFailoverRecovery(f func()) {
+	p.onFailoverRecoveryCallback = f
+}
+
 // IsTerminating indicates if the node is in terminating (and in the draining phase).
 func (p *LiqoNodeProvider) IsTerminating() bool {
 	p.updateMutex.Lock()
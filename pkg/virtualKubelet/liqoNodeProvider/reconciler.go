@@ -16,29 +16,100 @@ package liqonodeprovider
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"reflect"
 	"strconv"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/klog/v2"
 
 	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
 	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils"
 	fcutils "github.com/liqotech/liqo/pkg/utils/foreigncluster"
 	"github.com/liqotech/liqo/pkg/utils/maps"
 	"github.com/liqotech/liqo/pkg/utils/slice"
 )
 
-func (p *LiqoNodeProvider) reconcileNodeFromNode(_ watch.Event) error {
+func (p *LiqoNodeProvider) reconcileNodeFromNode(event watch.Event) error {
+	var node v1.Node
+	unstruct, ok := event.Object.(*unstructured.Unstructured)
+	if !ok {
+		return errors.New("error in casting Node")
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstruct.Object, &node); err != nil {
+		klog.Error(err)
+		return err
+	}
+	p.checkFailoverRecovery(context.Background(), &node)
+
 	// enforce the node to be the same as the one we are managing
 	return p.updateNode()
 }
 
+// checkFailoverRecovery invokes the failover recovery callback in case the node is no longer marked as failed over,
+// to garbage collect the remote resources corresponding to the workloads rescheduled elsewhere in the meanwhile.
+// The failover state is persisted through an annotation of the node, so that the recovery is not missed in case
+// the virtual kubelet restarts while (or after) the node is failed over.
+func (p *LiqoNodeProvider) checkFailoverRecovery(ctx context.Context, node *v1.Node) {
+	p.updateMutex.Lock()
+	failedOver := utils.IsNodeFailedOver(node)
+	_, pending := node.GetAnnotations()[consts.FailoverRecoveryPendingAnnotationKey]
+	recovered := (p.failedOver || pending) && !failedOver
+	p.failedOver = failedOver
+
+	if failedOver && !pending {
+		if err := p.patchFailoverRecoveryPending(ctx, true); err != nil {
+			klog.Errorf("failed to persist the failover state of node %v: %v", node.GetName(), err)
+		}
+	}
+	p.updateMutex.Unlock()
+
+	if recovered && p.onFailoverRecoveryCallback != nil {
+		klog.Infof("node %v recovered after failover", node.GetName())
+		p.onFailoverRecoveryCallback()
+	}
+
+	if recovered && pending {
+		p.updateMutex.Lock()
+		defer p.updateMutex.Unlock()
+		if err := p.patchFailoverRecoveryPending(ctx, false); err != nil {
+			klog.Errorf("failed to clear the failover state of node %v: %v", node.GetName(), err)
+		}
+	}
+}
+
+// patchFailoverRecoveryPending adds or removes the annotation marking the node as pending the recovery after a failover.
+func (p *LiqoNodeProvider) patchFailoverRecoveryPending(ctx context.Context, pending bool) error {
+	var value interface{}
+	if pending {
+		value = strconv.FormatBool(true)
+	}
+
+	bytes, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{consts.FailoverRecoveryPendingAnnotationKey: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	node, err := p.localClient.CoreV1().Nodes().Patch(ctx, p.nodeName, types.MergePatchType, bytes, metav1.PatchOptions{})
+	if err != nil {
+		return err
+	}
+	p.node = node
+	return nil
+}
+
 func (p *LiqoNodeProvider) reconcileNodeFromVirtualNode(event watch.Event) error {
 	ctx := context.Background()
 	var virtualNode offloadingv1beta1.VirtualNode
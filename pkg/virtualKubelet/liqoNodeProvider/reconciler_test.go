@@ -0,0 +1,94 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package liqonodeprovider
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("Failover recovery", func() {
+	var (
+		ctx       context.Context
+		node      *v1.Node
+		provider  *LiqoNodeProvider
+		recovered int
+	)
+
+	failedOver := func(node *v1.Node) *v1.Node {
+		node = node.DeepCopy()
+		node.Spec.Taints = append(node.Spec.Taints, v1.Taint{Key: consts.ProviderUnavailableTaintKey, Effect: v1.TaintEffectNoSchedule})
+		return node
+	}
+
+	restored := func(node *v1.Node) *v1.Node {
+		node = node.DeepCopy()
+		node.Spec.Taints = nil
+		return node
+	}
+
+	current := func() *v1.Node {
+		node, err := provider.localClient.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return node
+	}
+
+	newProvider := func() *LiqoNodeProvider {
+		p := &LiqoNodeProvider{localClient: fake.NewClientset(node), nodeName: nodeName, node: node.DeepCopy()}
+		p.NotifyFailoverRecovery(func() { recovered++ })
+		return p
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		node = &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+		recovered = 0
+		provider = newProvider()
+	})
+
+	It("should persist the failover state and clear it once the node is restored", func() {
+		provider.checkFailoverRecovery(ctx, failedOver(node))
+		Expect(recovered).To(BeZero())
+		Expect(current().Annotations).To(HaveKey(consts.FailoverRecoveryPendingAnnotationKey))
+
+		provider.checkFailoverRecovery(ctx, restored(current()))
+		Expect(recovered).To(Equal(1))
+		Expect(current().Annotations).ToNot(HaveKey(consts.FailoverRecoveryPendingAnnotationKey))
+	})
+
+	It("should recover the node restored while the virtual kubelet was not running", func() {
+		provider.checkFailoverRecovery(ctx, failedOver(node))
+		node = restored(current())
+
+		// Simulate a restart of the virtual kubelet, which loses the in-memory state.
+		provider = newProvider()
+		provider.checkFailoverRecovery(ctx, node)
+		Expect(recovered).To(Equal(1))
+		Expect(current().Annotations).ToNot(HaveKey(consts.FailoverRecoveryPendingAnnotationKey))
+	})
+
+	It("should not invoke the callback for nodes never failed over", func() {
+		provider.checkFailoverRecovery(ctx, node)
+		Expect(recovered).To(BeZero())
+		Expect(current().Annotations).ToNot(HaveKey(consts.FailoverRecoveryPendingAnnotationKey))
+	})
+})
@@ -0,0 +1,380 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload
+
+import (
+	"context"
+	"sync"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	batchv1clients "k8s.io/client-go/kubernetes/typed/batch/v1"
+	corev1clients "k8s.io/client-go/kubernetes/typed/core/v1"
+	batchv1listers "k8s.io/client-go/listers/batch/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	offloadingv1beta1clients "github.com/liqotech/liqo/pkg/client/clientset/versioned/typed/offloading/v1beta1"
+	offloadingv1beta1listers "github.com/liqotech/liqo/pkg/client/listers/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/utils/pod"
+	"github.com/liqotech/liqo/pkg/utils/virtualkubelet"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/generic"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/manager"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
+)
+
+const (
+	// JobReflectorName is the name associated with the Job reflector.
+	JobReflectorName = "Job"
+)
+
+// NamespacedJobReflector manages the reflection of the Jobs to be executed remotely through ShadowJobs.
+type NamespacedJobReflector struct {
+	generic.NamespacedReflector
+
+	localJobs              batchv1listers.JobNamespaceLister
+	remoteShadowJobs       offloadingv1beta1listers.ShadowJobNamespaceLister
+	localPods              corev1listers.PodNamespaceLister
+	remotePods             corev1listers.PodNamespaceLister
+	localJobsClient        batchv1clients.JobInterface
+	localPodsClient        corev1clients.PodInterface
+	remoteShadowJobsClient offloadingv1beta1clients.ShadowJobInterface
+
+	apiServerSupport forge.APIServerSupportType
+
+	// rejected tracks the UIDs of the local jobs whose shadowjob has been rejected by the policies of the provider cluster,
+	// indexed by name. Since the pod template of a job is immutable, they would be rejected again, hence they are not retried.
+	rejected sync.Map
+}
+
+// NewJobReflector builds a JobReflector.
+func NewJobReflector(apiServerSupport forge.APIServerSupportType, reflectorConfig *offloadingv1beta1.ReflectorConfig) manager.Reflector {
+	return generic.NewReflector(JobReflectorName, NewNamespacedJobReflector(apiServerSupport),
+		generic.WithoutFallback(), reflectorConfig.NumWorkers, offloadingv1beta1.CustomLiqo, generic.ConcurrencyModeLeader)
+}
+
+// NewNamespacedJobReflector returns a function generating NamespacedJobReflector instances.
+func NewNamespacedJobReflector(apiServerSupport forge.APIServerSupportType) func(*options.NamespacedOpts) manager.NamespacedReflector {
+	return func(opts *options.NamespacedOpts) manager.NamespacedReflector {
+		local := opts.LocalFactory.Batch().V1().Jobs()
+		remote := opts.RemoteLiqoFactory.Offloading().V1beta1().ShadowJobs()
+		localPods := opts.LocalFactory.Core().V1().Pods()
+		remotePods := opts.RemoteFactory.Core().V1().Pods()
+
+		_, err := local.Informer().AddEventHandler(opts.HandlerFactory(generic.NamespacedKeyer(opts.LocalNamespace)))
+		utilruntime.Must(err)
+		_, err = remote.Informer().AddEventHandler(opts.HandlerFactory(RemoteShadowNamespacedKeyer(opts.LocalNamespace, forge.LiqoNodeName)))
+		utilruntime.Must(err)
+		_, err = localPods.Informer().AddEventHandler(opts.HandlerFactory(jobPodsKeyer(opts.LocalNamespace, forge.RemoteJobPodLabel)))
+		utilruntime.Must(err)
+		_, err = remotePods.Informer().AddEventHandler(opts.HandlerFactory(jobPodsKeyer(opts.LocalNamespace, batchv1.JobNameLabel)))
+		utilruntime.Must(err)
+
+		return &NamespacedJobReflector{
+			NamespacedReflector:    generic.NewNamespacedReflector(opts, JobReflectorName),
+			localJobs:              local.Lister().Jobs(opts.LocalNamespace),
+			remoteShadowJobs:       remote.Lister().ShadowJobs(opts.RemoteNamespace),
+			localPods:              localPods.Lister().Pods(opts.LocalNamespace),
+			remotePods:             remotePods.Lister().Pods(opts.RemoteNamespace),
+			localJobsClient:        opts.LocalClient.BatchV1().Jobs(opts.LocalNamespace),
+			localPodsClient:        opts.LocalClient.CoreV1().Pods(opts.LocalNamespace),
+			remoteShadowJobsClient: opts.RemoteLiqoClient.OffloadingV1beta1().ShadowJobs(opts.RemoteNamespace),
+			apiServerSupport:       apiServerSupport,
+		}
+	}
+}
+
+// Handle is responsible for reconciling the given object and ensuring it is correctly reflected.
+func (njr *NamespacedJobReflector) Handle(ctx context.Context, name string) error {
+	tracer := trace.FromContext(ctx)
+
+	// Retrieve the local and remote objects (only not found errors can occur).
+	klog.V(4).Infof("Handling reflection of local Job %q (remote: %q)", njr.LocalRef(name), njr.RemoteRef(name))
+
+	local, lerr := njr.localJobs.Get(name)
+	utilruntime.Must(client.IgnoreNotFound(lerr))
+	shadow, rerr := njr.remoteShadowJobs.Get(name)
+	utilruntime.Must(client.IgnoreNotFound(rerr))
+	tracer.Step("Retrieved the local and remote objects")
+
+	// Abort the reflection if the remote object is not managed by us, as we do not want to mutate others' objects.
+	if rerr == nil && (!forge.IsReflected(shadow) || shadow.GetLabels()[forge.LiqoOriginClusterNodeName] != forge.LiqoNodeName) {
+		if lerr == nil && forge.IsShadowJobCandidate(local, forge.LiqoNodeName) {
+			klog.Infof("Skipping reflection of local Job %q as remote already exists and is not managed by us", njr.LocalRef(name))
+			njr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedReflectionAlreadyExistsMsg())
+		}
+		return nil
+	}
+
+	// Only the jobs explicitly delegated to liqo and targeting this virtual node are reflected. The other ones are
+	// considered as not existing, so that the remote shadowjob is deleted if the local job is no longer a candidate.
+	if lerr == nil && !forge.IsShadowJobCandidate(local, forge.LiqoNodeName) {
+		lerr = kerrors.NewNotFound(batchv1.Resource("job"), name)
+	}
+
+	tracer.Step("Performed the sanity checks")
+
+	if kerrors.IsNotFound(lerr) {
+		defer tracer.Step("Ensured the absence of the remote object")
+		njr.rejected.Delete(name)
+		if err := njr.HandlePods(ctx, name, nil, nil); err != nil {
+			return err
+		}
+
+		if !kerrors.IsNotFound(rerr) {
+			klog.V(4).Infof("Deleting remote ShadowJob %q, since local Job %q does no longer exist or it is not targeting this node",
+				njr.RemoteRef(name), njr.LocalRef(name))
+			return njr.DeleteRemote(ctx, njr.remoteShadowJobsClient, "ShadowJob", shadow.GetName(), shadow.GetUID())
+		}
+
+		klog.V(4).Infof("Local Job %q and remote ShadowJob %q both vanished", njr.LocalRef(name), njr.RemoteRef(name))
+		return nil
+	}
+
+	if kerrors.IsNotFound(rerr) {
+		shadow = nil
+
+		if uid, found := njr.rejected.Load(name); found && uid == local.GetUID() {
+			klog.V(4).Infof("Skipping reflection of local Job %q, as previously rejected by the policies of the provider cluster", njr.LocalRef(name))
+			return nil
+		}
+	}
+
+	if err := njr.HandleSpec(ctx, local, shadow); err != nil {
+		njr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedReflectionMsg(err))
+		return err
+	}
+
+	if err := njr.HandleStatus(ctx, local, shadow); err != nil {
+		njr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedStatusReflectionMsg(err))
+		return err
+	}
+
+	return njr.HandlePods(ctx, name, local, shadow)
+}
+
+// HandleSpec ensures the remote shadowjob is aligned with the specifications of the local job.
+func (njr *NamespacedJobReflector) HandleSpec(ctx context.Context, local *batchv1.Job, shadow *offloadingv1beta1.ShadowJob) error {
+	tracer := trace.FromContext(ctx)
+
+	// The same mutators of the pod reflector are applied to the pod template, except for the token-based API server support.
+	template := &local.Spec.Template
+	apiServerSupport := forge.ShadowJobAPIServerSupport(njr.apiServerSupport)
+	mutators := []forge.RemotePodSpecMutator{
+		forge.APIServerSupportMutator(apiServerSupport, template.Annotations, pod.ServiceAccountName(&corev1.Pod{Spec: template.Spec}),
+			func(string) string { return "" }, func() string { return "" }, "", ""),
+		forge.ServiceAccountMutator(apiServerSupport, template.Annotations),
+	}
+
+	if njr.ForgingOpts != nil {
+		mutators = append(mutators,
+			forge.NodeSelectorMutator(njr.ForgingOpts.NodeSelector),
+			forge.TolerationsMutator(njr.ForgingOpts.Tolerations),
+			forge.AffinityMutator(njr.ForgingOpts.Affinity))
+	}
+
+	// Append the mutators implementing the per-pod scheduling hints, so that they are merged with the virtual node ones.
+	hints, err := forge.SchedulingHintsMutators(template.Annotations)
+	if err != nil {
+		return err
+	}
+	mutators = append(mutators, hints...)
+
+	forgingOpts := njr.ForgingOpts
+	if forgingOpts == nil {
+		forgingOpts = &forge.ForgingOpts{}
+	}
+	target := forge.RemoteShadowJob(local, shadow, njr.RemoteNamespace(), forgingOpts, mutators...)
+	tracer.Step("Remote mutation created")
+
+	if shadow == nil {
+		defer tracer.Step("Ensured the presence of the remote object")
+		if _, err := njr.remoteShadowJobsClient.Create(ctx, target, metav1.CreateOptions{FieldManager: forge.ReflectionFieldManager}); err != nil {
+			if kerrors.ReasonForError(err) == offloadingv1beta1.ShadowPodPolicyViolationReason {
+				njr.HandlePolicyViolation(local, err)
+				return nil
+			}
+			klog.Errorf("Failed to create remote ShadowJob %q (local: %q): %v", njr.RemoteRef(local.GetName()), njr.LocalRef(local.GetName()), err)
+			return err
+		}
+
+		klog.Infof("Remote ShadowJob %q successfully created (local: %q)", njr.RemoteRef(local.GetName()), njr.LocalRef(local.GetName()))
+		njr.Event(local, corev1.EventTypeNormal, forge.EventSuccessfulReflection, forge.EventSuccessfulReflectionMsg())
+		return nil
+	}
+
+	if labels.Equals(shadow.GetLabels(), target.GetLabels()) && labels.Equals(shadow.GetAnnotations(), target.GetAnnotations()) &&
+		equality.Semantic.DeepEqual(shadow.Spec, target.Spec) {
+		klog.V(4).Infof("Skipping remote ShadowJob %q update, as already synced", njr.RemoteRef(local.GetName()))
+		return nil
+	}
+
+	defer tracer.Step("Updated the remote object")
+	if _, err := njr.remoteShadowJobsClient.Update(ctx, target, metav1.UpdateOptions{FieldManager: forge.ReflectionFieldManager}); err != nil {
+		klog.Errorf("Failed to update remote ShadowJob %q (local: %q): %v", njr.RemoteRef(local.GetName()), njr.LocalRef(local.GetName()), err)
+		return err
+	}
+
+	klog.Infof("Remote ShadowJob %q successfully updated (local: %q)", njr.RemoteRef(local.GetName()), njr.LocalRef(local.GetName()))
+	njr.Event(local, corev1.EventTypeNormal, forge.EventSuccessfulReflection, forge.EventSuccessfulReflectionMsg())
+	return nil
+}
+
+// HandlePolicyViolation reports that the local job has been rejected by the policies of the provider cluster,
+// and records it not to retry the creation of the remote shadowjob, which would be rejected again.
+func (njr *NamespacedJobReflector) HandlePolicyViolation(local *batchv1.Job, violation error) {
+	njr.rejected.Store(local.GetName(), local.GetUID())
+	klog.Warningf("Local Job %q rejected by the policies of the provider cluster (remote: %q): %v",
+		njr.LocalRef(local.GetName()), njr.RemoteRef(local.GetName()), violation)
+	njr.Event(local, corev1.EventTypeWarning, forge.EventRejected, forge.EventRejectedMsg(violation.Error()))
+}
+
+// HandleStatus reflects the status of the remote shadowjob (i.e., of the corresponding job) to the local one.
+func (njr *NamespacedJobReflector) HandleStatus(ctx context.Context, local *batchv1.Job, shadow *offloadingv1beta1.ShadowJob) error {
+	// Do not handle the status in case the remote shadowjob has not yet been created.
+	if shadow == nil || equality.Semantic.DeepEqual(local.Status, shadow.Status.Job) {
+		return nil
+	}
+
+	defer trace.FromContext(ctx).Step("Updated the local job status")
+	updated := local.DeepCopy()
+	updated.Status = *shadow.Status.Job.DeepCopy()
+	if _, err := njr.localJobsClient.UpdateStatus(ctx, updated, metav1.UpdateOptions{FieldManager: forge.ReflectionFieldManager}); err != nil {
+		klog.Errorf("Failed to update the status of local Job %q (remote: %q): %v", njr.LocalRef(local.GetName()), njr.RemoteRef(local.GetName()), err)
+		return err
+	}
+
+	njr.Event(local, corev1.EventTypeNormal, forge.EventSuccessfulReflection, forge.EventSuccessfulStatusReflectionMsg())
+	klog.Infof("Local Job %q status successfully updated (remote: %q)", njr.LocalRef(local.GetName()), njr.RemoteRef(local.GetName()))
+	return nil
+}
+
+// HandlePods ensures the pods of the remote job are exposed in the local cluster through placeholders bound to the virtual node,
+// reflecting their status. The placeholders no longer matching any remote pod (e.g., because the local job has been deleted)
+// are immediately removed, as no kubelet takes care of their termination.
+func (njr *NamespacedJobReflector) HandlePods(ctx context.Context, name string, local *batchv1.Job, shadow *offloadingv1beta1.ShadowJob) error {
+	placeholders, err := njr.localPods.List(labels.SelectorFromSet(labels.Set{forge.RemoteJobPodLabel: name}))
+	utilruntime.Must(err)
+
+	remotes := make(map[string]*corev1.Pod)
+	if local != nil && shadow != nil {
+		pods, err := njr.remotePods.List(labels.SelectorFromSet(labels.Set{batchv1.JobNameLabel: name}))
+		utilruntime.Must(err)
+		for _, pod := range pods {
+			if forge.IsRemoteJobPodOf(pod, name) {
+				remotes[pod.GetName()] = pod
+			}
+		}
+	}
+
+	for _, placeholder := range placeholders {
+		remote, found := remotes[placeholder.GetName()]
+		delete(remotes, placeholder.GetName())
+
+		if !found || !metav1.IsControlledBy(placeholder, local) || !placeholder.DeletionTimestamp.IsZero() {
+			opts := metav1.NewDeleteOptions(0 /* no kubelet is in charge of terminating the placeholder */)
+			opts.Preconditions = metav1.NewUIDPreconditions(string(placeholder.GetUID()))
+			if err := njr.localPodsClient.Delete(ctx, placeholder.GetName(), *opts); err != nil && !kerrors.IsNotFound(err) {
+				klog.Errorf("Failed to delete placeholder pod %q of local Job %q: %v", njr.LocalRef(placeholder.GetName()), njr.LocalRef(name), err)
+				return err
+			}
+			klog.V(4).Infof("Placeholder pod %q of local Job %q successfully deleted", njr.LocalRef(placeholder.GetName()), njr.LocalRef(name))
+			continue
+		}
+
+		if err := njr.handlePodStatus(ctx, placeholder, remote); err != nil {
+			return err
+		}
+	}
+
+	// Create the placeholders of the remote pods not yet exposed.
+	for _, remote := range remotes {
+		placeholder, err := njr.localPodsClient.Create(ctx, forge.LocalJobPod(local, remote),
+			metav1.CreateOptions{FieldManager: forge.ReflectionFieldManager})
+		if kerrors.IsAlreadyExists(err) {
+			// A local pod with the same name already exists, and it is not a placeholder of the current job.
+			klog.Warningf("Skipping placeholder creation for pod %q of local Job %q, as the name is already in use",
+				njr.LocalRef(remote.GetName()), njr.LocalRef(name))
+			continue
+		}
+		if err != nil {
+			klog.Errorf("Failed to create placeholder pod %q of local Job %q: %v", njr.LocalRef(remote.GetName()), njr.LocalRef(name), err)
+			return err
+		}
+
+		klog.V(4).Infof("Placeholder pod %q of local Job %q successfully created", njr.LocalRef(remote.GetName()), njr.LocalRef(name))
+		if err := njr.handlePodStatus(ctx, placeholder, remote); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handlePodStatus reflects the status of the given remote pod to the corresponding local placeholder.
+func (njr *NamespacedJobReflector) handlePodStatus(ctx context.Context, placeholder, remote *corev1.Pod) error {
+	status := forge.LocalJobPodStatus(placeholder, &remote.Status)
+	if equality.Semantic.DeepEqual(placeholder.Status, status) {
+		return nil
+	}
+
+	updated := placeholder.DeepCopy()
+	updated.Status = status
+	if _, err := njr.localPodsClient.UpdateStatus(ctx, updated, metav1.UpdateOptions{FieldManager: forge.ReflectionFieldManager}); err != nil {
+		klog.Errorf("Failed to update the status of placeholder pod %q (remote: %q): %v",
+			njr.LocalRef(placeholder.GetName()), njr.RemoteRef(remote.GetName()), err)
+		return err
+	}
+
+	klog.V(4).Infof("Placeholder pod %q status successfully updated (remote: %q)", njr.LocalRef(placeholder.GetName()), njr.RemoteRef(remote.GetName()))
+	return nil
+}
+
+// jobPodsKeyer returns a keyer mapping the pods to the name of the job they belong to, as specified by the given label.
+func jobPodsKeyer(namespace, label string) options.Keyer {
+	return func(metadata metav1.Object) []types.NamespacedName {
+		if name, found := metadata.GetLabels()[label]; found {
+			return []types.NamespacedName{{Namespace: namespace, Name: name}}
+		}
+		return nil
+	}
+}
+
+// List returns the list of objects.
+func (njr *NamespacedJobReflector) List() ([]interface{}, error) {
+	listShJob, err := virtualkubelet.List[virtualkubelet.Lister[*offloadingv1beta1.ShadowJob], *offloadingv1beta1.ShadowJob](
+		njr.remoteShadowJobs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	listJob, err := virtualkubelet.List[virtualkubelet.Lister[*batchv1.Job], *batchv1.Job](
+		njr.localJobs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return append(listShJob, listJob...), nil
+}
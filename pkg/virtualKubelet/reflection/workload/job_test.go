@@ -0,0 +1,275 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workload_test
+
+import (
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/trace"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	liqoclient "github.com/liqotech/liqo/pkg/client/clientset/versioned"
+	liqoclientfake "github.com/liqotech/liqo/pkg/client/clientset/versioned/fake"
+	liqoinformers "github.com/liqotech/liqo/pkg/client/informers/externalversions"
+	"github.com/liqotech/liqo/pkg/consts"
+	. "github.com/liqotech/liqo/pkg/utils/testutil"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/workload"
+)
+
+var _ = Describe("Namespaced Job Reflection Tests", func() {
+	const JobName = "batch"
+
+	var (
+		reflector  *workload.NamespacedJobReflector
+		client     *fake.Clientset
+		liqoClient liqoclient.Interface
+		local      *batchv1.Job
+		err        error
+	)
+
+	BeforeEach(func() {
+		client = fake.NewSimpleClientset()
+		liqoClient = liqoclientfake.NewSimpleClientset()
+		local = &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: JobName, Namespace: LocalNamespace},
+			Spec: batchv1.JobSpec{
+				ManagedBy: ptr.To(consts.ShadowJobManagedBy),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						NodeName:           LiqoNodeName,
+						ServiceAccountName: "local-sa",
+						RestartPolicy:      corev1.RestartPolicyNever,
+						Containers:         []corev1.Container{{Name: "batch", Image: "batch:v1"}},
+					},
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		factory := informers.NewSharedInformerFactory(client, 10*time.Hour)
+		liqoFactory := liqoinformers.NewSharedInformerFactory(liqoClient, 10*time.Hour)
+
+		forgingOpts := FakeForgingOpts()
+		forgingOpts.NodeSelector = map[string]string{"provider-pool": "batch"}
+
+		reflector = workload.NewNamespacedJobReflector(forge.APIServerSupportTokenAPI)(options.NewNamespaced().
+			WithLocal(LocalNamespace, client, factory).WithLiqoLocal(liqoClient, liqoFactory).
+			WithRemote(RemoteNamespace, client, factory).WithLiqoRemote(liqoClient, liqoFactory).
+			WithHandlerFactory(FakeEventHandler).WithEventBroadcaster(record.NewBroadcaster()).
+			WithForgingOpts(forgingOpts)).(*workload.NamespacedJobReflector)
+
+		factory.Start(ctx.Done())
+		liqoFactory.Start(ctx.Done())
+		factory.WaitForCacheSync(ctx.Done())
+		liqoFactory.WaitForCacheSync(ctx.Done())
+
+		err = reflector.HandleSpec(trace.ContextWithTrace(ctx, trace.New("Job")), local, nil)
+	})
+
+	getShadowJobTemplate := func() *corev1.PodSpec {
+		shadow, err := liqoClient.OffloadingV1beta1().ShadowJobs(RemoteNamespace).Get(ctx, JobName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return &shadow.Spec.Job.Template.Spec
+	}
+
+	When("the token-based API server support is configured", func() {
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+
+		It("should not propagate the local service account, nor mount any token", func() {
+			spec := getShadowJobTemplate()
+			Expect(spec.ServiceAccountName).To(BeEmpty())
+			Expect(spec.AutomountServiceAccountToken).To(PointTo(BeFalse()))
+		})
+
+		It("should apply the mutators of the virtual node", func() {
+			Expect(getShadowJobTemplate().NodeSelector).To(HaveKeyWithValue("provider-pool", "batch"))
+		})
+	})
+
+	When("the remote API server support is requested through the pod template annotations", func() {
+		BeforeEach(func() {
+			local.Spec.Template.Annotations = map[string]string{
+				consts.APIServerSupportAnnotation:         consts.APIServerSupportAnnotationValueRemote,
+				consts.RemoteServiceAccountNameAnnotation: "remote-sa",
+			}
+		})
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+
+		It("should configure the remote service account", func() {
+			spec := getShadowJobTemplate()
+			Expect(spec.ServiceAccountName).To(Equal("remote-sa"))
+			Expect(spec.AutomountServiceAccountToken).To(PointTo(BeTrue()))
+		})
+	})
+
+	When("the remote scheduling hints are specified through the pod template annotations", func() {
+		BeforeEach(func() {
+			local.Spec.Template.Annotations = map[string]string{consts.RemoteNodeSelectorAnnotKey: "disk=ssd"}
+		})
+
+		It("should merge them with the ones of the virtual node", func() {
+			Expect(getShadowJobTemplate().NodeSelector).To(And(
+				HaveKeyWithValue("provider-pool", "batch"), HaveKeyWithValue("disk", "ssd")))
+		})
+	})
+
+	When("the remote object creation is rejected by the policies of the provider cluster", func() {
+		var attempts int
+
+		BeforeEach(func() {
+			attempts = 0
+			local.SetUID("job-uid")
+			_, err := client.BatchV1().Jobs(LocalNamespace).Create(ctx, local, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			liqoClient.(*liqoclientfake.Clientset).PrependReactor("create", "shadowjobs", func(testing.Action) (bool, runtime.Object, error) {
+				attempts++
+				return true, nil, &kerrors.StatusError{ErrStatus: metav1.Status{
+					Status: metav1.StatusFailure, Code: http.StatusForbidden,
+					Reason: offloadingv1beta1.ShadowPodPolicyViolationReason, Message: "forbidden image registry",
+				}}
+			})
+		})
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should not retry the creation of the remote object", func() {
+			Expect(reflector.Handle(trace.ContextWithTrace(ctx, trace.New("Job")), JobName)).To(Succeed())
+			Expect(attempts).To(Equal(1))
+		})
+	})
+})
+
+var _ = Describe("Namespaced Job Pods Reflection Tests", func() {
+	const JobName = "batch"
+
+	var (
+		reflector *workload.NamespacedJobReflector
+		client    *fake.Clientset
+		local     *batchv1.Job
+		shadow    *offloadingv1beta1.ShadowJob
+		remote    *corev1.Pod
+		stale     *corev1.Pod
+		err       error
+	)
+
+	BeforeEach(func() {
+		local = &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: JobName, Namespace: LocalNamespace, UID: "job-uid"},
+			Spec: batchv1.JobSpec{
+				ManagedBy: ptr.To(consts.ShadowJobManagedBy),
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{batchv1.ControllerUidLabel: "job-uid"}},
+					Spec: corev1.PodSpec{
+						NodeName:      LiqoNodeName,
+						RestartPolicy: corev1.RestartPolicyNever,
+						Containers: []corev1.Container{{Name: "batch", Image: "batch:v1",
+							VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}}}},
+						Volumes: []corev1.Volume{{Name: "data"}},
+					},
+				},
+			},
+		}
+		shadow = &offloadingv1beta1.ShadowJob{ObjectMeta: metav1.ObjectMeta{Name: JobName, Namespace: RemoteNamespace}}
+		remote = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "batch-abcde", Namespace: RemoteNamespace,
+				Labels:          map[string]string{batchv1.JobNameLabel: JobName},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: JobName, UID: "remote-uid", Controller: ptr.To(true)}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1", PodIPs: []corev1.PodIP{{IP: "10.0.0.1"}}},
+		}
+		stale = forge.LocalJobPod(local, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "batch-stale"}})
+		client = fake.NewSimpleClientset(remote, stale)
+	})
+
+	JustBeforeEach(func() {
+		factory := informers.NewSharedInformerFactory(client, 10*time.Hour)
+		liqoClient := liqoclientfake.NewSimpleClientset()
+		liqoFactory := liqoinformers.NewSharedInformerFactory(liqoClient, 10*time.Hour)
+
+		reflector = workload.NewNamespacedJobReflector(forge.APIServerSupportTokenAPI)(options.NewNamespaced().
+			WithLocal(LocalNamespace, client, factory).WithLiqoLocal(liqoClient, liqoFactory).
+			WithRemote(RemoteNamespace, client, factory).WithLiqoRemote(liqoClient, liqoFactory).
+			WithHandlerFactory(FakeEventHandler).WithEventBroadcaster(record.NewBroadcaster()).
+			WithForgingOpts(FakeForgingOpts())).(*workload.NamespacedJobReflector)
+
+		factory.Start(ctx.Done())
+		liqoFactory.Start(ctx.Done())
+		factory.WaitForCacheSync(ctx.Done())
+		liqoFactory.WaitForCacheSync(ctx.Done())
+	})
+
+	getPlaceholder := func(name string) (*corev1.Pod, error) {
+		return client.CoreV1().Pods(LocalNamespace).Get(ctx, name, metav1.GetOptions{})
+	}
+
+	When("the remote job is running", func() {
+		JustBeforeEach(func() { err = reflector.HandlePods(ctx, JobName, local, shadow) })
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+
+		It("should create a placeholder for each remote pod, bound to the virtual node and owned by the local job", func() {
+			placeholder, err := getPlaceholder(remote.Name)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(forge.IsRemoteJobPod(placeholder)).To(BeTrue())
+			Expect(metav1.IsControlledBy(placeholder, local)).To(BeTrue())
+			Expect(placeholder.Labels).To(HaveKeyWithValue(batchv1.ControllerUidLabel, "job-uid"))
+			Expect(placeholder.Spec.NodeName).To(Equal(LiqoNodeName))
+			Expect(placeholder.Spec.Volumes).To(BeEmpty())
+			Expect(placeholder.Spec.Containers).To(ConsistOf(corev1.Container{Name: "batch", Image: "batch:v1"}))
+		})
+
+		It("should reflect the status of the remote pod, without exposing its IPs", func() {
+			placeholder, err := getPlaceholder(remote.Name)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(placeholder.Status.Phase).To(Equal(corev1.PodRunning))
+			Expect(placeholder.Status.PodIP).To(BeEmpty())
+			Expect(placeholder.Status.PodIPs).To(BeEmpty())
+		})
+
+		It("should delete the placeholders not matching any remote pod", func() {
+			_, err := getPlaceholder(stale.Name)
+			Expect(err).To(BeNotFound())
+		})
+	})
+
+	When("the local job no longer exists", func() {
+		JustBeforeEach(func() { err = reflector.HandlePods(ctx, JobName, nil, nil) })
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should delete all the placeholders, without creating new ones", func() {
+			_, err := getPlaceholder(stale.Name)
+			Expect(err).To(BeNotFound())
+			_, err = getPlaceholder(remote.Name)
+			Expect(err).To(BeNotFound())
+		})
+	})
+})
@@ -97,6 +97,11 @@ type PodReflectorConfig struct {
 
 	KubernetesServiceIPMapper func(context.Context) (string, error)
 	NetConfiguration          *networkingv1beta1.Configuration
+
+	// RemoteStatsSummaryRetriever retrieves the stats summary of the offloaded pods from the remote cluster, including also
+	// network, filesystem and ephemeral-storage stats. If unset or failing, only CPU and memory stats are retrieved from
+	// the remote metrics server.
+	RemoteStatsSummaryRetriever func(context.Context) (*statsv1alpha1.Summary, error)
 }
 
 // FallbackPodReflector handles the "orphan" pods outside the managed namespaces.
@@ -228,6 +233,14 @@ func (pr *PodReflector) Logs(ctx context.Context, namespace, pod, container stri
 
 // Stats retrieves the stats of the reflected pods.
 func (pr *PodReflector) Stats(ctx context.Context) (*statsv1alpha1.Summary, error) {
+	if pr.config.RemoteStatsSummaryRetriever != nil {
+		pods, err := pr.RemoteStats(ctx)
+		if err == nil {
+			return forge.LocalNodeStats(pods), nil
+		}
+		klog.Warningf("Failed to retrieve the remote stats summary, falling back to the metrics server: %v", err)
+	}
+
 	var pods []statsv1alpha1.PodStats
 	var err error
 
@@ -245,6 +258,38 @@ func (pr *PodReflector) Stats(ctx context.Context) (*statsv1alpha1.Summary, erro
 	return forge.LocalNodeStats(pods), nil
 }
 
+// RemoteStats retrieves the stats of the reflected pods from the remote stats summary.
+func (pr *PodReflector) RemoteStats(ctx context.Context) ([]statsv1alpha1.PodStats, error) {
+	summary, err := pr.config.RemoteStatsSummaryRetriever(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := make([]statsv1alpha1.PodStats, 0, len(summary.Pods))
+	for i := range summary.Pods {
+		ref := &summary.Pods[i].PodRef
+
+		// Skip the pods belonging to namespaces no longer reflected.
+		if _, found := pr.handlers.Load(ref.Namespace); !found {
+			continue
+		}
+
+		// Retrieve the local pod corresponding to the remote stats.
+		local, err := pr.localPods.Pods(ref.Namespace).Get(ref.Name)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		pods = append(pods, forge.LocalPodStatsFromRemote(local, &summary.Pods[i]))
+	}
+
+	klog.V(4).Infof("Stats for %d reflected pods correctly retrieved from the remote stats summary", len(pods))
+	return pods, nil
+}
+
 // KubernetesServiceIPGetter returns a function to retrieve the IP associated with the kubernetes.default service.
 func (pr *PodReflector) KubernetesServiceIPGetter() func(ctx context.Context) (string, error) {
 	var address string
@@ -123,6 +123,12 @@ func (npr *NamespacedPodReflector) Handle(ctx context.Context, name string) erro
 	utilruntime.Must(client.IgnoreNotFound(lerr))
 	localExists := !kerrors.IsNotFound(lerr)
 
+	// The placeholders of the pods of the jobs executed remotely are handled by the job reflector.
+	if localExists && forge.IsRemoteJobPod(local) {
+		klog.V(4).Infof("Skipping reflection of local pod %q, as placeholder of a remote job pod", npr.LocalRef(name))
+		return nil
+	}
+
 	remote, rerr := npr.remotePods.Get(name)
 	utilruntime.Must(client.IgnoreNotFound(rerr))
 	remoteExists := !kerrors.IsNotFound(rerr)
@@ -186,8 +192,16 @@ func (npr *NamespacedPodReflector) Handle(ctx context.Context, name string) erro
 		return npr.HandleStatus(ctx, local, remote, npr.RetrievePodInfo(local.GetName()))
 	}
 
+	// The remote shadowpod has been preempted by the provider cluster, hence mark the local pod as failed to cause its controller
+	// to recreate it (possibly elsewhere). The shadowpod will be deleted afterwards, either by the provider or by the check below.
+	if shadowExists && shadow.Status.Preemption != nil && local.Status.Phase != corev1.PodFailed {
+		defer tracer.Step("Marked the local pod as preempted")
+		return npr.HandlePreemption(ctx, local, shadow.Status.Preemption)
+	}
+
 	// Do not offload the pod if it was previously rejected, as new copies should have already been re-created.
-	if local.Status.Phase == corev1.PodFailed && local.Status.Reason == forge.PodOffloadingAbortedReason {
+	if local.Status.Phase == corev1.PodFailed && (local.Status.Reason == forge.PodOffloadingAbortedReason ||
+		local.Status.Reason == forge.PodOffloadingPreemptedReason || local.Status.Reason == forge.PodOffloadingRejectedReason) {
 		// Ensure the corresponding remote shadowpod is not still present due to transients.
 		if shadowExists && shadow.DeletionTimestamp.IsZero() {
 			defer tracer.Step("Ensured the absence of the remote object")
@@ -232,6 +246,9 @@ func (npr *NamespacedPodReflector) Handle(ctx context.Context, name string) erro
 				klog.Infof("Remote shadowpod %q already exists (local pod: %q)", npr.RemoteRef(name), npr.LocalRef(name))
 				return nil
 			}
+			if kerrors.ReasonForError(err) == offloadingv1beta1.ShadowPodPolicyViolationReason {
+				return npr.HandlePolicyViolation(ctx, local, err)
+			}
 			klog.Errorf("Failed to create remote shadowpod %q (local pod: %q): %v", npr.RemoteRef(name), npr.LocalRef(name), err)
 			if !kerrors.IsConflict(err) {
 				npr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedReflectionMsg(err))
@@ -275,6 +292,43 @@ func (npr *NamespacedPodReflector) Handle(ctx context.Context, name string) erro
 	return npr.HandleStatus(ctx, local, remote, info)
 }
 
+// HandlePreemption marks the local pod as failed, since the corresponding remote shadowpod has been preempted by the provider cluster.
+func (npr *NamespacedPodReflector) HandlePreemption(ctx context.Context, local *corev1.Pod, preemption *offloadingv1beta1.ShadowPodPreemption) error {
+	po := forge.LocalRejectedPod(local, corev1.PodFailed, forge.PodOffloadingPreemptedReason)
+	po.Status.Message = preemption.Message
+
+	if _, err := npr.localPodsClient.UpdateStatus(ctx, po, metav1.UpdateOptions{FieldManager: forge.ReflectionFieldManager}); err != nil {
+		klog.Errorf("Failed to mark local pod %q as preempted (remote: %q): %v", npr.LocalRef(local.GetName()), npr.RemoteRef(local.GetName()), err)
+		if !kerrors.IsConflict(err) {
+			npr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedStatusReflectionMsg(err))
+		}
+		return err
+	}
+
+	klog.Infof("Local pod %q marked as preempted (remote: %q): %s", npr.LocalRef(local.GetName()), npr.RemoteRef(local.GetName()), preemption.Message)
+	npr.Event(local, corev1.EventTypeWarning, forge.EventPreempted, forge.EventPreemptedMsg(preemption.Message))
+	return nil
+}
+
+// HandlePolicyViolation marks the local pod as rejected, since the corresponding remote shadowpod violates the policies of the
+// provider cluster. The pod is marked as failed, so that its controller (if any) recreates it, possibly on a different node.
+func (npr *NamespacedPodReflector) HandlePolicyViolation(ctx context.Context, local *corev1.Pod, violation error) error {
+	po := forge.LocalRejectedPod(local, corev1.PodFailed, forge.PodOffloadingRejectedReason)
+	po.Status.Message = violation.Error()
+
+	if _, err := npr.localPodsClient.UpdateStatus(ctx, po, metav1.UpdateOptions{FieldManager: forge.ReflectionFieldManager}); err != nil {
+		klog.Errorf("Failed to mark local pod %q as rejected (remote: %q): %v", npr.LocalRef(local.GetName()), npr.RemoteRef(local.GetName()), err)
+		if !kerrors.IsConflict(err) {
+			npr.Event(local, corev1.EventTypeWarning, forge.EventFailedReflection, forge.EventFailedStatusReflectionMsg(err))
+		}
+		return err
+	}
+
+	klog.Warningf("Local pod %q marked as rejected (remote: %q): %v", npr.LocalRef(local.GetName()), npr.RemoteRef(local.GetName()), violation)
+	npr.Event(local, corev1.EventTypeWarning, forge.EventRejected, forge.EventRejectedMsg(violation.Error()))
+	return nil
+}
+
 // HandleLabels mutates the local object labels, to mark the pod as offloaded and allow filtering at the informer level.
 func (npr *NamespacedPodReflector) HandleLabels(ctx context.Context, local *corev1.Pod) error {
 	// Forge the mutation to be applied to the local pod.
@@ -341,6 +395,13 @@ func (npr *NamespacedPodReflector) ForgeShadowPod(ctx context.Context, local *co
 			forge.AffinityMutator(forgingOpts.Affinity))
 	}
 
+	// Append the mutators implementing the per-pod scheduling hints, so that they are merged with the virtual node ones.
+	hints, err := forge.SchedulingHintsMutators(local.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	mutators = append(mutators, hints...)
+
 	// Forge the target shadowpod object.
 	target := forge.RemoteShadowPod(local, shadow, npr.RemoteNamespace(), forgingOpts, mutators...)
 
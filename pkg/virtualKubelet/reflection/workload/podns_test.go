@@ -17,11 +17,13 @@ package workload_test
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,6 +33,7 @@ import (
 	"k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/record"
 	metricsv1beta1 "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+	"k8s.io/utils/ptr"
 	"k8s.io/utils/trace"
 
 	networkingv1beta1 "github.com/liqotech/liqo/apis/networking/v1beta1"
@@ -92,7 +95,7 @@ var _ = Describe("Namespaced Pod Reflection Tests", func() {
 								},
 							},
 						},
-					}}, &reflectorConfig)
+					}, nil}, &reflectorConfig)
 			rfl.Start(ctx, options.New(client, factory.Core().V1().Pods()).WithEventBroadcaster(broadcaster))
 			reflector = rfl.NewNamespaced(options.NewNamespaced().
 				WithLocal(LocalNamespace, client, factory).WithLiqoLocal(liqoClient, liqoFactory).
@@ -216,6 +219,25 @@ var _ = Describe("Namespaced Pod Reflection Tests", func() {
 					})
 				})
 
+				When("the remote object creation is rejected by the policies of the provider cluster", func() {
+					BeforeEach(func() {
+						liqoClient.(*liqoclientfake.Clientset).PrependReactor("create", "shadowpods", func(testing.Action) (bool, runtime.Object, error) {
+							return true, nil, &kerrors.StatusError{ErrStatus: metav1.Status{
+								Status: metav1.StatusFailure, Code: http.StatusForbidden,
+								Reason: offloadingv1beta1.ShadowPodPolicyViolationReason, Message: "forbidden image registry",
+							}}
+						})
+					})
+
+					It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+					It("should mark the local pod as rejected", func() {
+						localAfter := GetPod(client, LocalNamespace, PodName)
+						Expect(localAfter.Status.Phase).To(Equal(corev1.PodFailed))
+						Expect(localAfter.Status.Reason).To(Equal(forge.PodOffloadingRejectedReason))
+						Expect(localAfter.Status.Message).To(ContainSubstring("forbidden image registry"))
+					})
+				})
+
 				When("the remote object already exists and needs to be updated", func() {
 					BeforeEach(func() {
 						shadow.SetLabels(labels.Merge(forge.ReflectionLabels(), map[string]string{FakeNotReflectedLabelKey: "true"}))
@@ -349,6 +371,25 @@ var _ = Describe("Namespaced Pod Reflection Tests", func() {
 				})
 			})
 
+			When("the local object does exist and the remote shadowpod has been preempted", func() {
+				BeforeEach(func() {
+					local.Status.Phase = corev1.PodRunning
+					CreatePod(client, &local)
+
+					shadow.SetLabels(forge.ReflectionLabels())
+					shadow.Status.Preemption = &offloadingv1beta1.ShadowPodPreemption{Message: "reclaimed"}
+					CreateShadowPod(liqoClient, &shadow)
+				})
+
+				It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+				It("should mark the local pod as preempted", func() {
+					localAfter := GetPod(client, LocalNamespace, PodName)
+					Expect(localAfter.Status.Phase).To(Equal(corev1.PodFailed))
+					Expect(localAfter.Status.Reason).To(Equal(forge.PodOffloadingPreemptedReason))
+					Expect(localAfter.Status.Message).To(Equal("reclaimed"))
+				})
+			})
+
 			When("the local object does exist and has been rejected (OffloadingAborted)", func() {
 				BeforeEach(func() {
 					local.Status.Phase = corev1.PodFailed
@@ -365,6 +406,20 @@ var _ = Describe("Namespaced Pod Reflection Tests", func() {
 					Expect(GetShadowPodError(liqoClient, RemoteNamespace, PodName)).To(BeNotFound())
 				})
 			})
+
+			When("the local object is the placeholder of a pod of a remote job", func() {
+				BeforeEach(func() {
+					local.SetLabels(map[string]string{forge.RemoteJobPodLabel: "job"})
+					local.SetOwnerReferences([]metav1.OwnerReference{{Kind: "Job", Name: "job", UID: "job-uid", Controller: ptr.To(true)}})
+					CreatePod(client, &local)
+					CreatePod(client, &remote)
+				})
+
+				It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+				It("should not create the remote shadow pod", func() {
+					Expect(GetShadowPodError(liqoClient, RemoteNamespace, PodName)).To(BeNotFound())
+				})
+			})
 		})
 
 		Context("status reflection", func() {
@@ -15,4 +15,4 @@
 // Package remoteclusterwide defines the ClusterRole containing the permissions required by the virtual kubelet in the remote cluster.
 package remoteclusterwide
 
-// +kubebuilder:rbac:groups=metrics.liqo.io,resources=scrape/metrics,verbs=get;list;watch
+// +kubebuilder:rbac:groups=metrics.liqo.io,resources=scrape/metrics;scrape/stats,verbs=get;list;watch
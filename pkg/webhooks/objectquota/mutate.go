@@ -0,0 +1,102 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectquota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
+)
+
+// cluster-role
+// +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch
+
+type mutatorWebhook struct {
+	client client.Client
+}
+
+// NewMutator returns a new mutating webhook, labeling the reflected objects with the name of the user who created them.
+func NewMutator(cl client.Client) *webhook.Admission {
+	return &webhook.Admission{Handler: &mutatorWebhook{client: cl}}
+}
+
+// Handle implements the mutating webhook logic.
+//
+//nolint:gocritic // The signature of this method is imposed by controller runtime.
+func (w *mutatorWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	if req.UserInfo.Username == "" {
+		return admission.Denied("missing creator name")
+	}
+
+	var obj unstructured.Unstructured
+	if err := json.Unmarshal(req.Object.Raw, &obj); err != nil {
+		klog.Errorf("Failed decoding %s object: %v", req.Kind.Kind, err)
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed decoding %s object: %w", req.Kind.Kind, err))
+	}
+
+	// The requests authenticated through token identities are attributed to the user of the equivalent certificate identity.
+	creatorName, _, err := authentication.ResolveUser(ctx, w.client, req.UserInfo.Username, req.UserInfo.Groups)
+	if err != nil {
+		klog.Errorf("Failed resolving user %q: %v", req.UserInfo.Username, err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	if req.Operation == admissionv1.Update {
+		// The creator of an existing object is preserved, since the object might be updated by a different
+		// virtual kubelet of the same consumer (e.g., in case of leader change), and the usage shall not move across users.
+		var oldObj unstructured.Unstructured
+		if err := json.Unmarshal(req.OldObject.Raw, &oldObj); err != nil {
+			klog.Errorf("Failed decoding %s object: %v", req.Kind.Kind, err)
+			return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed decoding %s object: %w", req.Kind.Kind, err))
+		}
+		if oldCreatorName, found := oldObj.GetLabels()[consts.CreatorLabelKey]; found {
+			creatorName = oldCreatorName
+		}
+	}
+
+	if obj.GetLabels()[consts.CreatorLabelKey] == creatorName {
+		return admission.Allowed("")
+	}
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[consts.CreatorLabelKey] = creatorName
+	obj.SetLabels(labels)
+
+	marshaled, err := json.Marshal(&obj)
+	if err != nil {
+		klog.Errorf("Failed encoding %s in admission response: %v", req.Kind.Kind, err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
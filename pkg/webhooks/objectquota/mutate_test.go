@@ -0,0 +1,91 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectquota_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
+	objectquotawh "github.com/liqotech/liqo/pkg/webhooks/objectquota"
+)
+
+var _ = Describe("Mutation webhook tests", func() {
+	const user = "fake-user"
+
+	var cl client.Client
+
+	BeforeEach(func() {
+		cl = fake.NewClientBuilder().WithObjects(&corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "liqo-remote-identity", Namespace: "liqo-tenant-remote",
+				Labels:      map[string]string{authentication.TokenIdentityLabel: "true"},
+				Annotations: map[string]string{authentication.TokenIdentityUserAnnotation: user},
+			},
+		}).Build()
+	})
+
+	forgeService := func(creator string) *corev1.Service {
+		svc := &corev1.Service{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+			ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "ns", Labels: map[string]string{"foo": "bar"}},
+		}
+		if creator != "" {
+			svc.Labels[consts.CreatorLabelKey] = creator
+		}
+		return svc
+	}
+
+	It("should add the creator label upon creation", func() {
+		res := objectquotawh.NewMutator(cl).Handle(context.TODO(),
+			generateAdmissionRequest(forgeService(""), nil, "services", admissionv1.Create, user))
+		Expect(res.Allowed).To(BeTrue())
+		Expect(res.Patches).To(ConsistOf(jsonpatch.JsonPatchOperation{
+			Operation: "add", Path: "/metadata/labels/liqo.io~1creator-user", Value: user,
+		}))
+	})
+
+	It("should attribute the requests of token identities to the equivalent certificate identity", func() {
+		res := objectquotawh.NewMutator(cl).Handle(context.TODO(), generateAdmissionRequest(forgeService(""), nil, "services",
+			admissionv1.Create, "system:serviceaccount:liqo-tenant-remote:liqo-remote-identity"))
+		Expect(res.Allowed).To(BeTrue())
+		Expect(res.Patches).To(ConsistOf(jsonpatch.JsonPatchOperation{
+			Operation: "add", Path: "/metadata/labels/liqo.io~1creator-user", Value: user,
+		}))
+	})
+
+	It("should preserve the original creator upon update", func() {
+		res := objectquotawh.NewMutator(cl).Handle(context.TODO(),
+			generateAdmissionRequest(forgeService(user), forgeService(user), "services", admissionv1.Update, "other-user"))
+		Expect(res.Allowed).To(BeTrue())
+		Expect(res.Patches).To(BeEmpty())
+	})
+
+	It("should add the creator label upon update, if missing", func() {
+		res := objectquotawh.NewMutator(cl).Handle(context.TODO(),
+			generateAdmissionRequest(forgeService(""), forgeService(""), "services", admissionv1.Update, user))
+		Expect(res.Allowed).To(BeTrue())
+		Expect(res.Patches).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,73 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectquota_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	testutil "github.com/liqotech/liqo/pkg/utils/testutil"
+)
+
+var scheme *runtime.Scheme
+
+var _ = BeforeSuite(func() {
+	scheme = runtime.NewScheme()
+	testutil.LogsToGinkgoWriter()
+	Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	Expect(offloadingv1beta1.AddToScheme(scheme)).To(Succeed())
+})
+
+func TestObjectQuotaWebhooks(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Object Quota Suite")
+}
+
+func generateAdmissionRequest(obj, oldObj client.Object, resource string, op admissionv1.Operation, user string) admission.Request {
+	req := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Version: "v1", Kind: obj.GetObjectKind().GroupVersionKind().Kind},
+			Resource:  metav1.GroupVersionResource{Version: "v1", Resource: resource},
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+			Operation: op,
+			UserInfo:  authenticationv1.UserInfo{Username: user},
+			Object:    toRawExtension(obj),
+			DryRun:    ptr.To(false),
+		},
+	}
+	if oldObj != nil {
+		req.OldObject = toRawExtension(oldObj)
+	}
+	return req
+}
+
+func toRawExtension(obj client.Object) runtime.RawExtension {
+	marshaled, err := json.Marshal(obj)
+	Expect(err).ToNot(HaveOccurred())
+	return runtime.RawExtension{Raw: marshaled}
+}
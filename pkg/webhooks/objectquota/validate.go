@@ -0,0 +1,265 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectquota
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+)
+
+// cluster-role
+// +kubebuilder:rbac:groups=core,resources=services;configmaps;secrets;persistentvolumeclaims,verbs=get;list;watch
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=quotas,verbs=get;list;watch
+
+// admittedObjectTimeout is the time after which an admitted object is no longer accounted, if not yet observed in the cache.
+const admittedObjectTimeout = 30 * time.Second
+
+// admittedObject represents an object admitted by the webhook, which might not be yet observed in the cache.
+type admittedObject struct {
+	storage   resource.Quantity
+	timestamp time.Time
+}
+
+// usage represents the objects of a given type accounted to a user, along with the storage they request.
+type usage struct {
+	objects map[string]resource.Quantity
+}
+
+func (u *usage) count() int64 {
+	return int64(len(u.objects))
+}
+
+func (u *usage) storage() resource.Quantity {
+	total := resource.Quantity{}
+	for objKey := range u.objects {
+		total.Add(u.objects[objKey])
+	}
+	return total
+}
+
+// Validator is the handler used by the validating webhook to enforce the quota on the reflected objects.
+type Validator struct {
+	client                   client.Client
+	decoder                  admission.Decoder
+	enableResourceValidation bool
+
+	mu sync.Mutex
+	// admitted contains the objects admitted by the webhook, indexed by user and object key.
+	admitted map[string]map[string]admittedObject
+}
+
+// NewValidator returns a new validating webhook enforcing the quota on the reflected objects.
+func NewValidator(cl client.Client, enableResourceValidation bool) *webhook.Admission {
+	return &webhook.Admission{Handler: &Validator{
+		client:                   cl,
+		decoder:                  admission.NewDecoder(runtime.NewScheme()),
+		enableResourceValidation: enableResourceValidation,
+		admitted:                 map[string]map[string]admittedObject{},
+	}}
+}
+
+// Handle implements the validating webhook logic.
+//
+//nolint:gocritic // The signature of this method is imposed by controller runtime.
+func (v *Validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if !v.enableResourceValidation {
+		return admission.Allowed("")
+	}
+
+	switch req.Operation {
+	case admissionv1.Create, admissionv1.Update:
+		return v.handleCreateOrUpdate(ctx, &req)
+	default:
+		return admission.Allowed("")
+	}
+}
+
+func (v *Validator) handleCreateOrUpdate(ctx context.Context, req *admission.Request) admission.Response {
+	var obj metav1.PartialObjectMetadata
+	if err := v.decoder.DecodeRaw(req.Object, &obj); err != nil {
+		klog.Errorf("Failed decoding %s object: %v", req.Kind.Kind, err)
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed decoding %s object: %w", req.Kind.Kind, err))
+	}
+
+	creatorName, found := obj.GetLabels()[consts.CreatorLabelKey]
+	if !found {
+		return admission.Denied("missing creator label")
+	}
+
+	quota, err := getters.GetQuotaByUser(ctx, v.client, creatorName)
+	switch {
+	case apierrors.IsNotFound(err):
+		// The object has not been created by a consumer cluster subject to a quota.
+		klog.V(4).Infof("No quota found for user %q, skipping the enforcement on %s %q", creatorName, req.Kind.Kind, req.Name)
+		return admission.Allowed("")
+	case err != nil:
+		klog.Errorf("Failed getting quota for user %q: %v", creatorName, err)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed getting quota"))
+	}
+
+	objKey := key(req.Resource.Resource, req.Namespace, obj.GetName())
+	countResource := corev1.ResourceName(req.Resource.Resource)
+	countLimit, countEnforced := quota.Spec.Resources[countResource]
+	storageLimit, storageEnforced := quota.Spec.Resources[corev1.ResourceRequestsStorage]
+	storageEnforced = storageEnforced && req.Resource.Resource == string(corev1.ResourcePersistentVolumeClaims)
+
+	// The number of objects does not change upon updates.
+	countEnforced = countEnforced && req.Operation == admissionv1.Create
+	if !countEnforced && !storageEnforced {
+		return admission.Allowed("")
+	}
+
+	var storage resource.Quantity
+	if storageEnforced {
+		if storage, err = v.getStorageRequest(req, quota.Spec.LimitsEnforcement); err != nil {
+			klog.Warningf("%s %q of user %q: %v", req.Kind.Kind, objKey, creatorName, err)
+			return admission.Denied(err.Error())
+		}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	used, err := v.getUsage(ctx, creatorName, req.Resource, req.Kind)
+	if err != nil {
+		klog.Errorf("Failed retrieving the %s of user %q: %v", req.Resource.Resource, creatorName, err)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed retrieving the current usage"))
+	}
+	// The object itself is not accounted, as its usage (if any) is replaced by the new one.
+	delete(used.objects, objKey)
+
+	if countEnforced && used.count()+1 > countLimit.Value() {
+		err := fmt.Errorf("peering %s quota usage exceeded - limit %s / used %d", countResource, countLimit.String(), used.count())
+		klog.Warningf("%s %q of user %q: %v", req.Kind.Kind, objKey, creatorName, err)
+		return admission.Denied(err.Error())
+	}
+
+	if storageEnforced {
+		total := used.storage()
+		total.Add(storage)
+		if total.Cmp(storageLimit) > 0 {
+			err := fmt.Errorf("peering %s quota usage exceeded - limit %s / requested %s", corev1.ResourceRequestsStorage,
+				storageLimit.String(), total.String())
+			klog.Warningf("%s %q of user %q: %v", req.Kind.Kind, objKey, creatorName, err)
+			return admission.Denied(err.Error())
+		}
+	}
+
+	if req.DryRun == nil || !*req.DryRun {
+		if v.admitted[creatorName] == nil {
+			v.admitted[creatorName] = map[string]admittedObject{}
+		}
+		v.admitted[creatorName][objKey] = admittedObject{storage: storage, timestamp: time.Now()}
+	}
+
+	return admission.Allowed("")
+}
+
+// getStorageRequest returns the storage requested by the PersistentVolumeClaim in the given request,
+// validating it according to the given limits enforcement.
+func (v *Validator) getStorageRequest(req *admission.Request, limitsEnforcement offloadingv1beta1.LimitsEnforcement) (resource.Quantity, error) {
+	var pvc corev1.PersistentVolumeClaim
+	if err := v.decoder.DecodeRaw(req.Object, &pvc); err != nil {
+		return resource.Quantity{}, fmt.Errorf("failed decoding %s object: %w", req.Kind.Kind, err)
+	}
+
+	request, requestFound := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	limit, limitFound := pvc.Spec.Resources.Limits[corev1.ResourceStorage]
+	if !requestFound && limitFound {
+		request, requestFound = limit, true
+	}
+
+	switch {
+	case limitsEnforcement == offloadingv1beta1.NoLimitsEnforcement:
+	case !requestFound:
+		return resource.Quantity{}, fmt.Errorf("storage request not set")
+	case limitsEnforcement == offloadingv1beta1.HardLimitsEnforcement && limitFound && request.Cmp(limit) != 0:
+		return resource.Quantity{}, fmt.Errorf("storage limits and requests are not equal")
+	}
+
+	return request, nil
+}
+
+// getUsage returns the objects of the given type accounted to the given user, merging those observed in the cache
+// with the ones recently admitted by the webhook. It shall be invoked while holding the lock.
+func (v *Validator) getUsage(ctx context.Context, creatorName string,
+	gvr metav1.GroupVersionResource, gvk metav1.GroupVersionKind) (*usage, error) {
+	used := usage{objects: map[string]resource.Quantity{}}
+	selector := client.MatchingLabels{consts.CreatorLabelKey: creatorName}
+
+	if gvr.Resource == string(corev1.ResourcePersistentVolumeClaims) {
+		var pvcs corev1.PersistentVolumeClaimList
+		if err := v.client.List(ctx, &pvcs, selector); err != nil {
+			return nil, err
+		}
+		for i := range pvcs.Items {
+			used.objects[key(gvr.Resource, pvcs.Items[i].Namespace, pvcs.Items[i].Name)] =
+				pvcs.Items[i].Spec.Resources.Requests[corev1.ResourceStorage]
+		}
+	} else {
+		var objects metav1.PartialObjectMetadataList
+		objects.SetGroupVersionKind(schema.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind + "List"})
+		if err := v.client.List(ctx, &objects, selector); err != nil {
+			return nil, err
+		}
+		for i := range objects.Items {
+			used.objects[key(gvr.Resource, objects.Items[i].Namespace, objects.Items[i].Name)] = resource.Quantity{}
+		}
+	}
+
+	// Account also for the objects admitted by the webhook and not yet observed in the cache (or not yet updated).
+	for objKey, admitted := range v.admitted[creatorName] {
+		if time.Since(admitted.timestamp) > admittedObjectTimeout {
+			delete(v.admitted[creatorName], objKey)
+			continue
+		}
+		if !hasResource(objKey, gvr.Resource) {
+			continue
+		}
+		if current, found := used.objects[objKey]; !found || current.Cmp(admitted.storage) < 0 {
+			used.objects[objKey] = admitted.storage
+		}
+	}
+
+	return &used, nil
+}
+
+func key(res, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", res, namespace, name)
+}
+
+func hasResource(objKey, res string) bool {
+	return strings.HasPrefix(objKey, res+"/")
+}
@@ -0,0 +1,171 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectquota_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	objectquotawh "github.com/liqotech/liqo/pkg/webhooks/objectquota"
+)
+
+var _ = Describe("Validation webhook tests", func() {
+	const (
+		user      = "fake-user"
+		namespace = "fake-namespace"
+	)
+
+	var (
+		validator  *webhook.Admission
+		objects    []client.Object
+		quota      *offloadingv1beta1.Quota
+		enforced   bool
+		fakeClient client.Client
+	)
+
+	forgeService := func(name string) *corev1.Service {
+		return &corev1.Service{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{consts.CreatorLabelKey: user}},
+		}
+	}
+
+	forgePVC := func(name, request, limit string) *corev1.PersistentVolumeClaim {
+		pvc := &corev1.PersistentVolumeClaim{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaim"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{consts.CreatorLabelKey: user}},
+			Spec: corev1.PersistentVolumeClaimSpec{Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{}, Limits: corev1.ResourceList{},
+			}},
+		}
+		if request != "" {
+			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse(request)
+		}
+		if limit != "" {
+			pvc.Spec.Resources.Limits[corev1.ResourceStorage] = resource.MustParse(limit)
+		}
+		return pvc
+	}
+
+	handle := func(obj, oldObj client.Object, res string, op admissionv1.Operation) bool {
+		return validator.Handle(context.TODO(), generateAdmissionRequest(obj, oldObj, res, op, user)).Allowed
+	}
+
+	BeforeEach(func() {
+		objects = nil
+		enforced = true
+		quota = &offloadingv1beta1.Quota{
+			ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "tenant-namespace"},
+			Spec: offloadingv1beta1.QuotaSpec{
+				User:              user,
+				LimitsEnforcement: offloadingv1beta1.SoftLimitsEnforcement,
+				Resources: corev1.ResourceList{
+					corev1.ResourceServices:        resource.MustParse("2"),
+					corev1.ResourceRequestsStorage: resource.MustParse("10Gi"),
+				},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objects, quota)...).Build()
+		validator = objectquotawh.NewValidator(fakeClient, enforced)
+	})
+
+	When("the number of objects is within the quota", func() {
+		BeforeEach(func() { objects = append(objects, forgeService("existing")) })
+		It("should admit the creation", func() {
+			Expect(handle(forgeService("new"), nil, "services", admissionv1.Create)).To(BeTrue())
+		})
+		It("should account for the objects admitted and not yet observed", func() {
+			Expect(handle(forgeService("new"), nil, "services", admissionv1.Create)).To(BeTrue())
+			Expect(handle(forgeService("another"), nil, "services", admissionv1.Create)).To(BeFalse())
+		})
+	})
+
+	When("the number of objects exceeds the quota", func() {
+		BeforeEach(func() { objects = append(objects, forgeService("existing-1"), forgeService("existing-2")) })
+		It("should deny the creation", func() {
+			Expect(handle(forgeService("new"), nil, "services", admissionv1.Create)).To(BeFalse())
+		})
+		It("should admit the updates", func() {
+			Expect(handle(forgeService("existing-1"), forgeService("existing-1"), "services", admissionv1.Update)).To(BeTrue())
+		})
+		When("the enforcement is disabled", func() {
+			BeforeEach(func() { enforced = false })
+			It("should admit the creation", func() {
+				Expect(handle(forgeService("new"), nil, "services", admissionv1.Create)).To(BeTrue())
+			})
+		})
+		It("should not deny the creation of objects not limited by the quota", func() {
+			cm := &corev1.ConfigMap{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+				ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: namespace, Labels: map[string]string{consts.CreatorLabelKey: user}},
+			}
+			Expect(handle(cm, nil, "configmaps", admissionv1.Create)).To(BeTrue())
+		})
+	})
+
+	When("the creator is not subject to a quota", func() {
+		BeforeEach(func() {
+			quota.Spec.User = "other-user"
+			objects = append(objects, forgeService("existing-1"), forgeService("existing-2"))
+		})
+		It("should admit the creation", func() {
+			Expect(handle(forgeService("new"), nil, "services", admissionv1.Create)).To(BeTrue())
+		})
+	})
+
+	DescribeTable("the storage requested by PersistentVolumeClaims",
+		func(enforcement offloadingv1beta1.LimitsEnforcement, request, limit string, expected bool) {
+			quota.Spec.LimitsEnforcement = enforcement
+			Expect(fakeClient.Update(context.TODO(), quota)).To(Succeed())
+			Expect(fakeClient.Create(context.TODO(), forgePVC("existing", "4Gi", ""))).To(Succeed())
+			Expect(handle(forgePVC("new", request, limit), nil, "persistentvolumeclaims", admissionv1.Create)).To(Equal(expected))
+		},
+		Entry("within the quota", offloadingv1beta1.SoftLimitsEnforcement, "6Gi", "", true),
+		Entry("exceeding the quota", offloadingv1beta1.SoftLimitsEnforcement, "7Gi", "", false),
+		Entry("exceeding the quota through the limits only", offloadingv1beta1.SoftLimitsEnforcement, "", "7Gi", false),
+		Entry("without requests and soft enforcement", offloadingv1beta1.SoftLimitsEnforcement, "", "", false),
+		Entry("without requests and no enforcement", offloadingv1beta1.NoLimitsEnforcement, "", "", true),
+		Entry("with different limits and hard enforcement", offloadingv1beta1.HardLimitsEnforcement, "1Gi", "2Gi", false),
+		Entry("with different limits and soft enforcement", offloadingv1beta1.SoftLimitsEnforcement, "1Gi", "2Gi", true),
+	)
+
+	When("a PersistentVolumeClaim is resized", func() {
+		BeforeEach(func() { objects = append(objects, forgePVC("existing", "4Gi", ""), forgePVC("other", "4Gi", "")) })
+		for _, size := range []struct {
+			value    string
+			expected bool
+		}{{"6Gi", true}, {"7Gi", false}} {
+			It(fmt.Sprintf("should check the new size against the quota (%s)", size.value), func() {
+				Expect(handle(forgePVC("existing", size.value, ""), forgePVC("existing", "4Gi", ""),
+					"persistentvolumeclaims", admissionv1.Update)).To(Equal(size.expected))
+			})
+		}
+	})
+})
@@ -16,10 +16,15 @@ package pod
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/utils"
@@ -53,7 +58,20 @@ func createTolerationFromNamespaceOffloading(strategy offloadingv1beta1.PodOfflo
 
 // createNodeSelectorFromNamespaceOffloading creates the right NodeSelector according to the PodOffloadingStrategy chosen.
 func createNodeSelectorFromNamespaceOffloading(nsoff *offloadingv1beta1.NamespaceOffloading) (*corev1.NodeSelector, error) {
-	nodeSelector := nsoff.Spec.ClusterSelector
+	nodeSelector := *nsoff.Spec.ClusterSelector.DeepCopy()
+	if nsoff.Spec.PlacementPolicy != nil {
+		// In case a placement policy is configured, the pod shall be scheduled only on the virtual nodes
+		// corresponding to the clusters selected by the NamespaceOffloading controller.
+		if len(nodeSelector.NodeSelectorTerms) == 0 {
+			nodeSelector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+		}
+
+		requirement := createSelectedClustersRequirement(nsoff.Status.SelectedClusters)
+		for i := range nodeSelector.NodeSelectorTerms {
+			nodeSelector.NodeSelectorTerms[i].MatchExpressions = append(nodeSelector.NodeSelectorTerms[i].MatchExpressions, requirement)
+		}
+	}
+
 	switch nsoff.Spec.PodOffloadingStrategy {
 	case offloadingv1beta1.RemotePodOffloadingStrategyType:
 		// To ensure that the pod is not scheduled on local nodes is necessary to add to every NodeSelectorTerm a
@@ -96,6 +114,27 @@ func createNodeSelectorFromNamespaceOffloading(nsoff *offloadingv1beta1.Namespac
 	return &nodeSelector, nil
 }
 
+// createSelectedClustersRequirement returns the NodeSelectorRequirement matching only the virtual nodes
+// associated with the given clusters. In case no cluster is selected, the returned requirement matches no virtual node.
+func createSelectedClustersRequirement(clusters []liqov1beta1.ClusterID) corev1.NodeSelectorRequirement {
+	if len(clusters) == 0 {
+		return corev1.NodeSelectorRequirement{
+			Key:      liqoconst.RemoteClusterID,
+			Operator: corev1.NodeSelectorOpDoesNotExist,
+		}
+	}
+
+	values := make([]string, len(clusters))
+	for i := range clusters {
+		values[i] = string(clusters[i])
+	}
+	return corev1.NodeSelectorRequirement{
+		Key:      liqoconst.RemoteClusterID,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   values,
+	}
+}
+
 // fillPodWithTheNewNodeSelector gets the previously computed NodeSelector imposed by the PodOffloadingStrategy and
 // merges it with the Pod NodeSelector if it is already present. It simply adds it to the Pod if previously unset.
 func fillPodWithTheNewNodeSelector(imposedNodeSelector *corev1.NodeSelector, pod *corev1.Pod) {
@@ -175,3 +214,53 @@ func mutatePod(namespaceOffloading *offloadingv1beta1.NamespaceOffloading, pod *
 
 	return nil
 }
+
+// mutatePodGroup prepares the members of a pod group (i.e., the pods annotated with liqo.io/pod-group) for gang scheduling.
+// The members are labeled with the name of the group, annotated with the UID of their controller, gated until the minimum
+// number of members has been created, and constrained to be scheduled on virtual nodes targeting the same provider cluster.
+// No changes are applied to the pods not belonging to any group, as well as if the Local PodOffloadingStrategy is selected.
+func mutatePodGroup(namespaceOffloading *offloadingv1beta1.NamespaceOffloading, pod *corev1.Pod) error {
+	name, found := pod.Annotations[liqoconst.PodGroupAnnotationKey]
+	if !found || name == "" || namespaceOffloading.Spec.PodOffloadingStrategy == offloadingv1beta1.LocalPodOffloadingStrategyType {
+		return nil
+	}
+
+	if errs := validation.IsValidLabelValue(name); len(errs) > 0 {
+		return fmt.Errorf("invalid %s annotation %q: %s", liqoconst.PodGroupAnnotationKey, name, strings.Join(errs, ", "))
+	}
+	if value, found := pod.Annotations[liqoconst.PodGroupMinMemberAnnotationKey]; found {
+		if minMember, err := strconv.Atoi(value); err != nil || minMember < 1 {
+			return fmt.Errorf("invalid %s annotation %q: expected a positive integer", liqoconst.PodGroupMinMemberAnnotationKey, value)
+		}
+	}
+
+	if pod.Labels[liqoconst.PodGroupLabelKey] == name {
+		// The pod has already been mutated.
+		return nil
+	}
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[liqoconst.PodGroupLabelKey] = name
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		pod.Annotations[liqoconst.PodGroupOwnerUIDAnnotationKey] = string(owner.UID)
+	}
+
+	// The gate is removed by the pod group controller once enough members exist, so that they are bound altogether.
+	pod.Spec.SchedulingGates = append(pod.Spec.SchedulingGates, corev1.PodSchedulingGate{Name: liqoconst.PodGroupSchedulingGate})
+
+	// All the members shall be offloaded to the same provider cluster, as the group is admitted atomically by the provider.
+	term := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{liqoconst.PodGroupLabelKey: name}},
+		TopologyKey:   liqoconst.RemoteClusterID,
+	}
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pod.Spec.Affinity.PodAffinity == nil {
+		pod.Spec.Affinity.PodAffinity = &corev1.PodAffinity{}
+	}
+	pod.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+		pod.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution, term)
+	return nil
+}
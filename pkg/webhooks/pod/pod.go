@@ -20,7 +20,9 @@ import (
 	"errors"
 	"net/http"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
@@ -30,10 +32,12 @@ import (
 
 	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	offloadingutils "github.com/liqotech/liqo/pkg/liqo-controller-manager/offloading/utils"
 )
 
 // cluster-role
 // +kubebuilder:rbac:groups=offloading.liqo.io,resources=namespaceoffloadings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch
 
 type podwh struct {
 	client  client.Client
@@ -80,6 +84,17 @@ func (w *podwh) Handle(ctx context.Context, req admission.Request) admission.Res
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
+	// The pods of the DaemonSets offloaded through Liqo shall not be mutated, as the DaemonSet controller keeps
+	// handling the local nodes only, while the offloaded pods are directly bound to the virtual nodes.
+	skip, err := w.isOffloadedDaemonSetPod(ctx, req.Namespace, pod)
+	if err != nil {
+		klog.Errorf("Failed checking whether pod %q belongs to an offloaded DaemonSet: %v", klog.KRef(req.Namespace, pod.Name), err)
+		return admission.Errored(http.StatusInternalServerError, errors.New("failed retrieving DaemonSet"))
+	}
+	if skip {
+		return admission.Allowed("pod of an offloaded DaemonSet")
+	}
+
 	// Get the NamespaceOffloading associated with the pod Namespace. If there is no NamespaceOffloading for that
 	// Namespace, it is an error, since the liqo.io/scheduling label should not be present on this namespace.
 	nsoff := &offloadingv1beta1.NamespaceOffloading{}
@@ -95,5 +110,34 @@ func (w *podwh) Handle(ctx context.Context, req admission.Request) admission.Res
 		return admission.Errored(http.StatusInternalServerError, errors.New("failed constructing pod mutation"))
 	}
 
+	if err = mutatePodGroup(nsoff, pod); err != nil {
+		klog.Warningf("Rejected pod %q: %v", klog.KRef(req.Namespace, pod.Name), err)
+		return admission.Denied(err.Error())
+	}
+
 	return w.CreatePatchResponse(&req, pod)
 }
+
+// isOffloadedDaemonSetPod returns whether the given pod has been created on behalf of a DaemonSet
+// opted in for the offloading, either by Liqo or by the DaemonSet controller itself.
+// The label set by Liqo is not trusted alone, as it is set by the pod creator: the pod shall also be owned by that DaemonSet.
+func (w *podwh) isOffloadedDaemonSetPod(ctx context.Context, namespace string, pod *corev1.Pod) (bool, error) {
+	owner := offloadingutils.OffloadedDaemonSetReference(pod)
+	if owner == nil {
+		owner = metav1.GetControllerOf(pod)
+	}
+	if owner == nil || owner.Kind != "DaemonSet" || owner.APIVersion != appsv1.SchemeGroupVersion.String() {
+		return false, nil
+	}
+
+	ds := &appsv1.DaemonSet{}
+	if err := w.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: owner.Name}, ds); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if ds.UID != owner.UID {
+		return false, nil
+	}
+
+	_, found := ds.Annotations[liqoconst.DaemonSetOffloadingAnnotationKey]
+	return found, nil
+}
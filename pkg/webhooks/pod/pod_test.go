@@ -15,15 +15,22 @@
 package pod
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/utils"
@@ -98,6 +105,46 @@ var _ = Describe("Webhook", func() {
 		)
 	})
 
+	Context("Check the NodeSelector imposed by a NamespaceOffloading with a placement policy", func() {
+		var namespaceOffloading offloadingv1beta1.NamespaceOffloading
+
+		BeforeEach(func() {
+			namespaceOffloading = offloadingv1beta1.NamespaceOffloading{
+				Spec: offloadingv1beta1.NamespaceOffloadingSpec{
+					PodOffloadingStrategy: offloadingv1beta1.RemotePodOffloadingStrategyType,
+					PlacementPolicy:       &offloadingv1beta1.PlacementPolicy{MaxClusters: 1},
+				},
+			}
+		})
+
+		It("should restrict the pods to the virtual nodes of the selected clusters", func() {
+			namespaceOffloading.Status.SelectedClusters = []liqov1beta1.ClusterID{"cluster-1"}
+			nodeSelector, err := createNodeSelectorFromNamespaceOffloading(&namespaceOffloading)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nodeSelector.NodeSelectorTerms).To(HaveLen(1))
+			Expect(nodeSelector.NodeSelectorTerms[0].MatchExpressions).To(ContainElement(corev1.NodeSelectorRequirement{
+				Key: liqoconst.RemoteClusterID, Operator: corev1.NodeSelectorOpIn, Values: []string{"cluster-1"},
+			}))
+		})
+
+		It("should match no virtual node if no cluster is selected", func() {
+			nodeSelector, err := createNodeSelectorFromNamespaceOffloading(&namespaceOffloading)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nodeSelector.NodeSelectorTerms).To(HaveLen(1))
+			Expect(nodeSelector.NodeSelectorTerms[0].MatchExpressions).To(ContainElement(corev1.NodeSelectorRequirement{
+				Key: liqoconst.RemoteClusterID, Operator: corev1.NodeSelectorOpDoesNotExist,
+			}))
+		})
+
+		It("should still allow local scheduling with the LocalAndRemote strategy", func() {
+			namespaceOffloading.Spec.PodOffloadingStrategy = offloadingv1beta1.LocalAndRemotePodOffloadingStrategyType
+			namespaceOffloading.Status.SelectedClusters = []liqov1beta1.ClusterID{"cluster-1"}
+			nodeSelector, err := createNodeSelectorFromNamespaceOffloading(&namespaceOffloading)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nodeSelector.NodeSelectorTerms).To(HaveLen(2))
+		})
+	})
+
 	Context("Check if the pod NodeSelector is correctly merged with the NamespaceOffloading NodeSelector", func() {
 		It("Check the merged NodeSelector", func() {
 			podNodeSelector := testutils.GetPodNodeSelector()
@@ -342,4 +389,113 @@ var _ = Describe("Webhook", func() {
 			)
 		})
 	})
+
+	Context("Mutate the members of a pod group", func() {
+		var podTest *corev1.Pod
+
+		BeforeEach(func() {
+			podTest = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Annotations: map[string]string{
+				liqoconst.PodGroupAnnotationKey: "group", liqoconst.PodGroupMinMemberAnnotationKey: "3"}}}
+		})
+
+		It("should gate the members and co-locate them in the same provider cluster", func() {
+			nsoff := testutils.GetNamespaceOffloading(offloadingv1beta1.RemotePodOffloadingStrategyType)
+			Expect(mutatePodGroup(&nsoff, podTest)).To(Succeed())
+			Expect(podTest.Labels).To(HaveKeyWithValue(liqoconst.PodGroupLabelKey, "group"))
+			Expect(podTest.Spec.SchedulingGates).To(ConsistOf(corev1.PodSchedulingGate{Name: liqoconst.PodGroupSchedulingGate}))
+			Expect(podTest.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(ConsistOf(corev1.PodAffinityTerm{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{liqoconst.PodGroupLabelKey: "group"}},
+				TopologyKey:   liqoconst.RemoteClusterID,
+			}))
+
+			// A second invocation shall not add the gate twice.
+			Expect(mutatePodGroup(&nsoff, podTest)).To(Succeed())
+			Expect(podTest.Spec.SchedulingGates).To(HaveLen(1))
+		})
+
+		It("should annotate the members with the UID of their controller", func() {
+			nsoff := testutils.GetNamespaceOffloading(offloadingv1beta1.RemotePodOffloadingStrategyType)
+			podTest.OwnerReferences = []metav1.OwnerReference{{APIVersion: "batch/v1", Kind: "Job", Name: "job", UID: "job-uid", Controller: ptr.To(true)}}
+			Expect(mutatePodGroup(&nsoff, podTest)).To(Succeed())
+			Expect(podTest.Annotations).To(HaveKeyWithValue(liqoconst.PodGroupOwnerUIDAnnotationKey, "job-uid"))
+		})
+
+		It("should not mutate the pods with the Local strategy", func() {
+			nsoff := testutils.GetNamespaceOffloading(offloadingv1beta1.LocalPodOffloadingStrategyType)
+			Expect(mutatePodGroup(&nsoff, podTest)).To(Succeed())
+			Expect(podTest.Labels).To(BeEmpty())
+			Expect(podTest.Spec.SchedulingGates).To(BeEmpty())
+		})
+
+		It("should reject an invalid minimum number of members", func() {
+			nsoff := testutils.GetNamespaceOffloading(offloadingv1beta1.RemotePodOffloadingStrategyType)
+			podTest.Annotations[liqoconst.PodGroupMinMemberAnnotationKey] = "zero"
+			Expect(mutatePodGroup(&nsoff, podTest)).ToNot(Succeed())
+		})
+	})
+
+	Context("Check whether pods belong to an offloaded DaemonSet", func() {
+		const namespace = "foo"
+
+		var (
+			ctx     context.Context
+			webhook *podwh
+			podTest *corev1.Pod
+		)
+
+		newDaemonSet := func(name string, offloaded bool) *appsv1.DaemonSet {
+			ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: types.UID(name)}}
+			if offloaded {
+				ds.Annotations = map[string]string{liqoconst.DaemonSetOffloadingAnnotationKey: liqoconst.DaemonSetOffloadingPerCluster}
+			}
+			return ds
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			scheme := runtime.NewScheme()
+			Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+			webhook = &podwh{client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				newDaemonSet("offloaded", true), newDaemonSet("regular", false)).Build()}
+			podTest = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: namespace}}
+		})
+
+		DescribeTable("isOffloadedDaemonSetPod",
+			func(mutator func(pod *corev1.Pod), expected bool) {
+				mutator(podTest)
+				skip, err := webhook.isOffloadedDaemonSetPod(ctx, namespace, podTest)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(skip).To(Equal(expected))
+			},
+			Entry("a pod not controlled by any DaemonSet", func(_ *corev1.Pod) {}, false),
+			Entry("a pod created by Liqo on behalf of an offloaded DaemonSet", func(pod *corev1.Pod) {
+				pod.Labels = map[string]string{liqoconst.DaemonSetOffloadingLabelKey: "offloaded"}
+				pod.Annotations = map[string]string{liqoconst.DaemonSetOffloadingTemplateHashAnnotationKey: "hash"}
+				pod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "offloaded", UID: "offloaded"}}
+			}, true),
+			Entry("a pod labeled as belonging to an offloaded DaemonSet, but not owned by it", func(pod *corev1.Pod) {
+				pod.Labels = map[string]string{liqoconst.DaemonSetOffloadingLabelKey: "offloaded"}
+				pod.Annotations = map[string]string{liqoconst.DaemonSetOffloadingTemplateHashAnnotationKey: "hash"}
+			}, false),
+			Entry("a pod owned by a previous DaemonSet with the same name", func(pod *corev1.Pod) {
+				pod.Labels = map[string]string{liqoconst.DaemonSetOffloadingLabelKey: "offloaded"}
+				pod.Annotations = map[string]string{liqoconst.DaemonSetOffloadingTemplateHashAnnotationKey: "hash"}
+				pod.OwnerReferences = []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "DaemonSet", Name: "offloaded", UID: "previous"}}
+			}, false),
+			Entry("a pod controlled by an offloaded DaemonSet", func(pod *corev1.Pod) {
+				pod.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(
+					newDaemonSet("offloaded", true), appsv1.SchemeGroupVersion.WithKind("DaemonSet"))}
+			}, true),
+			Entry("a pod controlled by a regular DaemonSet", func(pod *corev1.Pod) {
+				pod.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(
+					newDaemonSet("regular", false), appsv1.SchemeGroupVersion.WithKind("DaemonSet"))}
+			}, false),
+			Entry("a pod controlled by a no longer existing DaemonSet", func(pod *corev1.Pod) {
+				pod.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(
+					newDaemonSet("missing", true), appsv1.SchemeGroupVersion.WithKind("DaemonSet"))}
+			}, false),
+		)
+	})
 })
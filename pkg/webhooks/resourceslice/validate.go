@@ -37,6 +37,7 @@ import (
 
 // cluster-role
 // +kubebuilder:rbac:groups=authentication.liqo.io,resources=resourceslices,verbs=get;list;watch;
+// +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch
 
 type rswh struct {
 	decoder admission.Decoder
@@ -97,8 +98,15 @@ func (w *rswhv) handleCreate(ctx context.Context, req *admission.Request) admiss
 	return admission.Allowed("")
 }
 
-func (w *rswhv) handleUpdate(_ context.Context, req *admission.Request) admission.Response {
-	if !authetication.IsControlPlaneUser(req.UserInfo.Groups) {
+func (w *rswhv) handleUpdate(ctx context.Context, req *admission.Request) admission.Response {
+	// The requests authenticated through token identities are handled as the ones of the equivalent certificate identity.
+	_, groups, err := authetication.ResolveUser(ctx, w.client, req.UserInfo.Username, req.UserInfo.Groups)
+	if err != nil {
+		klog.Errorf("Failed resolving user %q: %v", req.UserInfo.Username, err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	if !authetication.IsControlPlaneUser(groups) {
 		return admission.Allowed("")
 	}
 
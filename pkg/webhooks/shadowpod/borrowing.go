@@ -0,0 +1,374 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowpod
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+)
+
+// preemption identifies a ShadowPod preempted to reclaim the resources borrowed by its creator.
+type preemption struct {
+	namespacedName types.NamespacedName
+	uid            types.UID
+	user           string
+	message        string
+	// preemptor is the object (either a ShadowPod or a ShadowJob) whose admission caused the preemption. Since it might
+	// still be rejected after being admitted by this webhook, the preemption is carried out only once it has been persisted.
+	preemptor client.Object
+	admitted  time.Time
+}
+
+// candidate is a ShadowPod which might be preempted, since its creator is borrowing resources.
+type candidate struct {
+	pi                *peeringInfo
+	spd               *Description
+	quota             corev1.ResourceList
+	creationTimestamp time.Time
+}
+
+// snapshot is a point in time copy of the quota usage of a PeeringInfo.
+type snapshot struct {
+	pi         *peeringInfo
+	priority   int32
+	borrowing  bool
+	limit      corev1.ResourceList
+	total      corev1.ResourceList
+	used       corev1.ResourceList
+	candidates []candidate
+}
+
+// alignBorrowingPolicy aligns the priority and the borrowing limit of the PeeringInfo with the given Quota.
+func (pi *peeringInfo) alignBorrowingPolicy(quota *offloadingv1beta1.Quota) {
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+	pi.priority = quota.Spec.Priority
+	pi.borrowingLimit = quota.Spec.BorrowingLimit.DeepCopy()
+}
+
+// snapshot returns a point in time copy of the quota usage of the PeeringInfo.
+func (pi *peeringInfo) snapshot() *snapshot {
+	pi.mu.RLock()
+	defer pi.mu.RUnlock()
+
+	snap := &snapshot{
+		pi:        pi,
+		priority:  pi.priority,
+		borrowing: len(pi.borrowingLimit) > 0,
+		limit:     pi.borrowingLimit.DeepCopy(),
+		total:     pi.totalQuota.DeepCopy(),
+		used:      pi.usedQuota.DeepCopy(),
+	}
+
+	// Only the ShadowPods of the users allowed to borrow resources are candidates for preemption.
+	// ShadowJobs and the members of pod groups are never preempted, as they would be disrupted as a whole.
+	if !snap.borrowing {
+		return snap
+	}
+
+	grouped := map[string]struct{}{}
+	for _, group := range pi.podGroups {
+		for member := range group.members {
+			grouped[member] = struct{}{}
+		}
+	}
+
+	for _, spd := range pi.shadowPods {
+		if _, found := grouped[spd.namespacedName.String()]; found || !spd.running || spd.job {
+			continue
+		}
+		snap.candidates = append(snap.candidates, candidate{pi: pi, spd: spd, quota: spd.quota.DeepCopy(), creationTimestamp: spd.creationTimestamp})
+	}
+	return snap
+}
+
+// borrowed returns the amount of resources of the given type borrowed by the user (i.e., used beyond the quota).
+func (snap *snapshot) borrowed(key corev1.ResourceName) resource.Quantity {
+	borrowed := snap.used[key].DeepCopy()
+	borrowed.Sub(snap.total[key])
+	if borrowed.Sign() < 0 {
+		return resource.Quantity{}
+	}
+	return borrowed
+}
+
+// snapshots returns the snapshots of all the PeeringInfos in the cache.
+func (pc *peeringCache) snapshots() []*snapshot {
+	var snapshots []*snapshot
+	pc.peeringInfo.Range(func(_, value interface{}) bool {
+		snapshots = append(snapshots, value.(*peeringInfo).snapshot())
+		return true
+	})
+	return snapshots
+}
+
+// deficit returns, for each resource, the amount exceeding the sum of the quotas of all users, once the given additional
+// resources are used. Only the resources with a positive deficit are returned.
+func deficit(snapshots []*snapshot, additional corev1.ResourceList) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	for _, snap := range snapshots {
+		for key := range snap.total {
+			result[key] = resource.Quantity{}
+		}
+	}
+
+	for key := range result {
+		value := additional[key].DeepCopy()
+		for _, snap := range snapshots {
+			value.Add(snap.used[key])
+			value.Sub(snap.total[key])
+		}
+		if value.Sign() > 0 {
+			result[key] = value
+		} else {
+			delete(result, key)
+		}
+	}
+	return result
+}
+
+// selectVictims selects the ShadowPods to be preempted to recover the given deficit, among those whose creator is borrowing
+// resources and satisfies the given filter. Victims are selected starting from the lowest priority users, and from the most
+// recent ShadowPods. It returns whether the selected victims are sufficient to recover the whole deficit.
+func selectVictims(snapshots []*snapshot, deficit corev1.ResourceList, filter func(*snapshot) bool) (victims []candidate, ok bool) {
+	var candidates []candidate
+	priorities := map[*peeringInfo]int32{}
+	borrowed := map[*peeringInfo]corev1.ResourceList{}
+	for _, snap := range snapshots {
+		if !snap.borrowing || !filter(snap) {
+			continue
+		}
+		candidates = append(candidates, snap.candidates...)
+		priorities[snap.pi] = snap.priority
+		borrowed[snap.pi] = corev1.ResourceList{}
+		for key := range deficit {
+			borrowed[snap.pi][key] = snap.borrowed(key)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if priorities[candidates[i].pi] != priorities[candidates[j].pi] {
+			return priorities[candidates[i].pi] < priorities[candidates[j].pi]
+		}
+		return candidates[i].creationTimestamp.After(candidates[j].creationTimestamp)
+	})
+
+	remaining := deficit.DeepCopy()
+	for i := range candidates {
+		if len(remaining) == 0 {
+			break
+		}
+
+		// A ShadowPod is preempted only if it contributes to recover the deficit,
+		// and its creator is still borrowing the corresponding resources.
+		useful := false
+		for key := range remaining {
+			current := borrowed[candidates[i].pi][key]
+			requested := candidates[i].quota[key]
+			if requested.Sign() > 0 && current.Sign() > 0 {
+				useful = true
+				break
+			}
+		}
+		if !useful {
+			continue
+		}
+
+		victims = append(victims, candidates[i])
+		for key, value := range candidates[i].quota {
+			if current, found := borrowed[candidates[i].pi][key]; found {
+				current.Sub(value)
+				borrowed[candidates[i].pi][key] = current
+			}
+			if current, found := remaining[key]; found {
+				current.Sub(value)
+				if current.Sign() <= 0 {
+					delete(remaining, key)
+				} else {
+					remaining[key] = current
+				}
+			}
+		}
+	}
+
+	return victims, len(remaining) == 0
+}
+
+// preempt releases the resources accounted to the given victims, returning the corresponding preemptions.
+func preempt(victims []candidate, preemptor client.Object, message string) []preemption {
+	preemptions := make([]preemption, 0, len(victims))
+	admitted := time.Now()
+	for i := range victims {
+		victim := &victims[i]
+		victim.pi.mu.Lock()
+		if spd, found := victim.pi.shadowPods[victim.spd.namespacedName.String()]; found && spd == victim.spd && spd.running {
+			victim.pi.terminateShadowPod(spd)
+			klog.Infof("ShadowPod %s of user %q preempted: %s", spd.namespacedName, victim.pi.userName, message)
+			preemptions = append(preemptions, preemption{namespacedName: spd.namespacedName, uid: spd.uid, user: victim.pi.userName,
+				message: message, preemptor: preemptor, admitted: admitted})
+		}
+		victim.pi.mu.Unlock()
+	}
+	return preemptions
+}
+
+// restorePreemption accounts again the resources of a preempted ShadowPod, as the corresponding preemption has been aborted.
+func (pc *peeringCache) restorePreemption(p *preemption) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	pi, found := pc.getPeeringInfo(p.user)
+	if !found {
+		return
+	}
+
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+	if spd, found := pi.shadowPods[p.namespacedName.String()]; found && spd.uid == p.uid && !spd.running {
+		spd.running = true
+		pi.addUsedResources(spd.quota)
+		klog.V(5).Infof("Cluster %q updated used quota %s", pi.userName, quotaFormatter(pi.usedQuota))
+	}
+}
+
+// reclaim returns the ShadowPods to be preempted to reclaim the resources borrowed from the given user, once the given object
+// has been admitted within its quota. It shall be invoked while holding the cache lock.
+func (pc *peeringCache) reclaim(pi *peeringInfo, preemptor client.Object) []preemption {
+	snapshots := pc.snapshots()
+	missing := deficit(snapshots, nil)
+	if len(missing) == 0 {
+		return nil
+	}
+	victims, _ := selectVictims(snapshots, missing, func(snap *snapshot) bool { return snap.pi != pi })
+	return preempt(victims, preemptor, fmt.Sprintf("borrowed resources reclaimed by user %q", pi.userName))
+}
+
+// testAndUpdateCreation checks whether the given ShadowPod can be admitted, either within the quota of its creator, or borrowing
+// the resources not currently used by the other users (if allowed). It returns the ShadowPods to be preempted as a consequence.
+func (pc *peeringCache) testAndUpdateCreation(ctx context.Context, c client.Client, pi *peeringInfo,
+	sp *offloadingv1beta1.ShadowPod, limitsEnforcement offloadingv1beta1.LimitsEnforcement, dryRun bool) ([]preemption, error) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	preemptor := &offloadingv1beta1.ShadowPod{ObjectMeta: metav1.ObjectMeta{Name: sp.GetName(), Namespace: sp.GetNamespace(), UID: sp.GetUID()}}
+
+	err := pi.testAndUpdateCreation(ctx, c, sp, limitsEnforcement, dryRun)
+	if err == nil {
+		if dryRun {
+			return nil, nil
+		}
+		// The ShadowPod has been admitted within the quota of its creator, possibly reclaiming resources borrowed by other users.
+		return pc.reclaim(pi, preemptor), nil
+	}
+
+	if group, _, _ := getPodGroup(sp); group != "" || !pi.snapshot().borrowing {
+		return nil, err
+	}
+
+	// The ShadowPod does not fit within the quota of its creator, hence attempt to borrow the missing resources.
+	klog.V(4).Infof("ShadowPod %s does not fit in the quota of user %q (%v), attempting to borrow resources", klog.KObj(sp), pi.userName, err)
+	return pc.testAndUpdateBorrowing(ctx, c, pi, sp, preemptor, limitsEnforcement, dryRun)
+}
+
+// testAndUpdateJobCreation checks whether the given ShadowJob can be admitted within the quota of its creator, possibly reclaiming
+// the resources borrowed by other users. ShadowJobs cannot borrow resources, as they are never preempted (like pod groups).
+func (pc *peeringCache) testAndUpdateJobCreation(ctx context.Context, c client.Client, pi *peeringInfo,
+	sj *offloadingv1beta1.ShadowJob, limitsEnforcement offloadingv1beta1.LimitsEnforcement, dryRun bool) ([]preemption, error) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if err := pi.testAndUpdateJobCreation(ctx, c, sj, limitsEnforcement, dryRun); err != nil || dryRun {
+		return nil, err
+	}
+
+	preemptor := &offloadingv1beta1.ShadowJob{ObjectMeta: metav1.ObjectMeta{Name: sj.GetName(), Namespace: sj.GetNamespace(), UID: sj.GetUID()}}
+	return pc.reclaim(pi, preemptor), nil
+}
+
+// testAndUpdateBorrowing checks whether the given ShadowPod can be admitted borrowing the resources not currently used by the other users,
+// possibly preempting the ShadowPods borrowing resources on behalf of lower priority users. It shall be invoked while holding the cache lock.
+func (pc *peeringCache) testAndUpdateBorrowing(ctx context.Context, c client.Client, pi *peeringInfo,
+	sp *offloadingv1beta1.ShadowPod, preemptor client.Object, limitsEnforcement offloadingv1beta1.LimitsEnforcement, dryRun bool) ([]preemption, error) {
+	pi.mu.Lock()
+	spd, err := pi.getOrCreateShadowPodDescription(ctx, c, sp, limitsEnforcement)
+	pi.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := pc.snapshots()
+	var own *snapshot
+	for _, snap := range snapshots {
+		if snap.pi == pi {
+			own = snap
+		}
+	}
+
+	// Check that the resources to be borrowed do not exceed the borrowing limit of the user.
+	for key, value := range spd.quota {
+		total, found := own.total[key]
+		if !found {
+			if isObjectCountResource(key) {
+				continue
+			}
+			return nil, fmt.Errorf("%s quota limit not found for this peering", key)
+		}
+
+		borrowing := own.used[key].DeepCopy()
+		borrowing.Add(value)
+		borrowing.Sub(total)
+		if borrowing.Sign() <= 0 {
+			continue
+		}
+
+		if limit := own.limit[key]; limit.Cmp(borrowing) < 0 {
+			return nil, fmt.Errorf("peering %s borrowing limit exceeded - limit %s / requested %s", key, limit.String(), borrowing.String())
+		}
+	}
+
+	// Check that the resources to be borrowed are not currently used by the other users, possibly preempting lower priority ones.
+	var victims []candidate
+	if missing := deficit(snapshots, spd.quota); len(missing) > 0 {
+		var ok bool
+		victims, ok = selectVictims(snapshots, missing, func(snap *snapshot) bool { return snap.pi != pi && snap.priority < own.priority })
+		if !ok {
+			return nil, fmt.Errorf("peering quota usage exceeded and not enough unused resources to be borrowed")
+		}
+	}
+
+	if dryRun {
+		return nil, nil
+	}
+
+	preemptions := preempt(victims, preemptor, fmt.Sprintf("borrowed resources reclaimed by higher priority user %q", pi.userName))
+
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+	pi.addShadowPod(spd)
+	klog.Infof("ShadowPod %s of user %q admitted borrowing resources (preempted %d ShadowPods)", klog.KObj(sp), pi.userName, len(preemptions))
+	klog.V(5).Infof("Cluster %q updated used quota %s", pi.userName, quotaFormatter(pi.usedQuota))
+	return preemptions, nil
+}
@@ -0,0 +1,303 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowpod
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("Borrowing and preemption", func() {
+	const (
+		lowPriority  = "low-priority-user"
+		highPriority = "high-priority-user"
+		guaranteed   = "guaranteed-user"
+	)
+
+	var (
+		pc          *peeringCache
+		counter     int
+		preemptions []preemption
+		err         error
+	)
+
+	// Each ShadowPod requests a quarter of the overall resources, which are split among the three users.
+	newPeeringInfo := func(user string, resources *corev1.ResourceList, priority int32, borrowingLimit *corev1.ResourceList) {
+		pi := createPeeringInfo(user, resources.DeepCopy())
+		quota := &offloadingv1beta1.Quota{Spec: offloadingv1beta1.QuotaSpec{Priority: priority}}
+		if borrowingLimit != nil {
+			quota.Spec.BorrowingLimit = borrowingLimit.DeepCopy()
+		}
+		pi.alignBorrowingPolicy(quota)
+		pc.peeringInfo.Store(user, pi)
+	}
+
+	get := func(user string) *peeringInfo {
+		pi, found := pc.getPeeringInfo(user)
+		Expect(found).To(BeTrue())
+		return pi
+	}
+
+	create := func(user string, dryRun bool) ([]preemption, error) {
+		counter++
+		sp := forgeShadowPod(fmt.Sprintf("%s-%d", user, counter), testNamespace, fmt.Sprintf("uid-%d", counter), user)
+		return pc.testAndUpdateCreation(ctx, fake.NewClientBuilder().WithScheme(scheme).Build(),
+			get(user), sp, offloadingv1beta1.SoftLimitsEnforcement, dryRun)
+	}
+
+	mustCreate := func(user string, times int) {
+		for i := 0; i < times; i++ {
+			victims, err := create(user, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(victims).To(BeEmpty())
+		}
+	}
+
+	BeforeEach(func() {
+		pc = &peeringCache{ready: true}
+		counter = 0
+		newPeeringInfo(lowPriority, resourceQuota4, 0, resourceQuota2)
+		newPeeringInfo(highPriority, resourceQuota4, 10, resourceQuota2)
+		newPeeringInfo(guaranteed, resourceQuota2, 0, nil)
+	})
+
+	When("the user is not allowed to borrow resources", func() {
+		BeforeEach(func() { mustCreate(guaranteed, 2) })
+		JustBeforeEach(func() { preemptions, err = create(guaranteed, false) })
+
+		It("should deny the ShadowPod exceeding the quota", func() { Expect(err).To(HaveOccurred()) })
+		It("should not preempt any ShadowPod", func() { Expect(preemptions).To(BeEmpty()) })
+	})
+
+	When("the user borrows unused resources", func() {
+		BeforeEach(func() { mustCreate(lowPriority, 1) })
+		JustBeforeEach(func() { preemptions, err = create(lowPriority, false) })
+
+		It("should admit the ShadowPod", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should not preempt any ShadowPod", func() { Expect(preemptions).To(BeEmpty()) })
+		It("should account the borrowed resources", func() {
+			Expect(get(lowPriority).usedQuota).To(Equal(withPods(resourceQuota2, 2)))
+		})
+
+		When("the borrowing limit is exceeded", func() {
+			JustBeforeEach(func() {
+				mustCreate(lowPriority, 1)
+				preemptions, err = create(lowPriority, false)
+			})
+
+			It("should deny the ShadowPod", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("borrowing limit exceeded"))
+			})
+		})
+	})
+
+	When("the dry-run flag is set", func() {
+		BeforeEach(func() { mustCreate(lowPriority, 1) })
+		JustBeforeEach(func() { preemptions, err = create(lowPriority, true) })
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should not account any resource", func() {
+			Expect(get(lowPriority).usedQuota).To(Equal(withPods(resourceQuota4, 1)))
+		})
+	})
+
+	When("the resources borrowed by a user are reclaimed by the owner", func() {
+		BeforeEach(func() {
+			mustCreate(lowPriority, 3)
+			mustCreate(guaranteed, 1)
+		})
+		JustBeforeEach(func() { preemptions, err = create(guaranteed, false) })
+
+		It("should admit the ShadowPod", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should preempt one ShadowPod of the borrowing user", func() {
+			Expect(preemptions).To(HaveLen(1))
+			Expect(preemptions[0].namespacedName.Name).To(HavePrefix(lowPriority))
+			Expect(preemptions[0].message).To(ContainSubstring(guaranteed))
+			Expect(preemptions[0].user).To(Equal(lowPriority))
+			Expect(preemptions[0].preemptor.GetName()).To(HavePrefix(guaranteed))
+		})
+		It("should release the resources of the preempted ShadowPod", func() {
+			Expect(get(lowPriority).usedQuota).To(Equal(withPods(resourceQuota2, 2)))
+			Expect(get(lowPriority).shadowPods[preemptions[0].namespacedName.String()].running).To(BeFalse())
+		})
+	})
+
+	When("the resources borrowed by a user are reclaimed by the owner through a ShadowJob", func() {
+		BeforeEach(func() {
+			mustCreate(lowPriority, 3)
+			mustCreate(guaranteed, 1)
+		})
+		JustBeforeEach(func() {
+			sj := forgeShadowJob("batch", "uid-batch", nil, nil)
+			sj.Labels[consts.CreatorLabelKey] = guaranteed
+			preemptions, err = pc.testAndUpdateJobCreation(ctx, fake.NewClientBuilder().WithScheme(scheme).Build(),
+				get(guaranteed), sj, offloadingv1beta1.SoftLimitsEnforcement, false)
+		})
+
+		It("should admit the ShadowJob", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should preempt one ShadowPod of the borrowing user", func() {
+			Expect(preemptions).To(HaveLen(1))
+			Expect(preemptions[0].namespacedName.Name).To(HavePrefix(lowPriority))
+			Expect(preemptions[0].preemptor).To(BeAssignableToTypeOf(&offloadingv1beta1.ShadowJob{}))
+		})
+	})
+
+	When("a ShadowJob does not fit in the quota of a user allowed to borrow resources", func() {
+		BeforeEach(func() { mustCreate(lowPriority, 1) })
+		JustBeforeEach(func() {
+			sj := forgeShadowJob("batch", "uid-batch", nil, nil)
+			sj.Labels[consts.CreatorLabelKey] = lowPriority
+			preemptions, err = pc.testAndUpdateJobCreation(ctx, fake.NewClientBuilder().WithScheme(scheme).Build(),
+				get(lowPriority), sj, offloadingv1beta1.SoftLimitsEnforcement, false)
+		})
+
+		It("should deny the ShadowJob, as ShadowJobs cannot borrow resources", func() { Expect(err).To(HaveOccurred()) })
+		It("should not preempt any ShadowPod", func() { Expect(preemptions).To(BeEmpty()) })
+	})
+
+	When("a higher priority user needs the resources borrowed by a lower priority one", func() {
+		BeforeEach(func() {
+			mustCreate(lowPriority, 3)
+			mustCreate(highPriority, 1)
+		})
+		JustBeforeEach(func() { preemptions, err = create(highPriority, false) })
+
+		It("should admit the ShadowPod", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should preempt one ShadowPod of the lower priority user", func() {
+			Expect(preemptions).To(HaveLen(1))
+			Expect(preemptions[0].namespacedName.Name).To(HavePrefix(lowPriority))
+		})
+
+		When("the lower priority user attempts to borrow the resources back", func() {
+			JustBeforeEach(func() { preemptions, err = create(lowPriority, false) })
+
+			It("should deny the ShadowPod", func() { Expect(err).To(HaveOccurred()) })
+			It("should not preempt any ShadowPod", func() { Expect(preemptions).To(BeEmpty()) })
+		})
+
+		When("the owner reclaims its resources", func() {
+			JustBeforeEach(func() { preemptions, err = create(guaranteed, false) })
+
+			It("should preempt the lower priority user first", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(preemptions).To(HaveLen(1))
+				Expect(preemptions[0].namespacedName.Name).To(HavePrefix(lowPriority))
+			})
+		})
+	})
+})
+
+var _ = Describe("Preemption evictor", func() {
+	const preemptorName = "preemptor"
+
+	var (
+		cl        client.Client
+		spv       *Validator
+		sp        *offloadingv1beta1.ShadowPod
+		preemptor *offloadingv1beta1.ShadowPod
+		p         preemption
+	)
+
+	getCurrent := func() *offloadingv1beta1.ShadowPod {
+		var current offloadingv1beta1.ShadowPod
+		Expect(cl.Get(ctx, nsName, &current)).To(Succeed())
+		return &current
+	}
+
+	BeforeEach(func() {
+		sp = forgeShadowPod(testShadowPodName, testNamespace, string(testShadowPodUID), userName)
+		preemptor = forgeShadowPod(preemptorName, testNamespace, "preemptor-uid", "other-user")
+		cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(sp, preemptor).WithStatusSubresource(sp).Build()
+		spv = NewValidator(cl, true)
+		p = preemption{namespacedName: nsName, uid: testShadowPodUID, user: userName, message: "preempted", admitted: time.Now(),
+			preemptor: &offloadingv1beta1.ShadowPod{ObjectMeta: metav1.ObjectMeta{Name: preemptorName, Namespace: testNamespace, UID: "preemptor-uid"}}}
+	})
+
+	It("should first notify the preemption, and then delete the ShadowPod", func() {
+		requeueAfter, err := spv.handlePreemption(ctx, &p)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requeueAfter).To(Equal(PreemptionGracePeriod))
+		Expect(getCurrent().Status.Preemption).To(Equal(&offloadingv1beta1.ShadowPodPreemption{Message: "preempted"}))
+
+		requeueAfter, err = spv.handlePreemption(ctx, &p)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requeueAfter).To(BeZero())
+
+		var current offloadingv1beta1.ShadowPod
+		Expect(k8serrors.IsNotFound(cl.Get(ctx, nsName, &current))).To(BeTrue())
+	})
+
+	It("should ignore a ShadowPod with a different UID", func() {
+		p.uid = types.UID("other")
+		requeueAfter, err := spv.handlePreemption(ctx, &p)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requeueAfter).To(BeZero())
+		Expect(getCurrent().Status.Preemption).To(BeNil())
+	})
+
+	When("the preemptor has not been persisted yet", func() {
+		BeforeEach(func() { p.preemptor.SetUID("not-yet-persisted") })
+
+		It("should wait for the preemptor, without notifying the preemption", func() {
+			requeueAfter, err := spv.handlePreemption(ctx, &p)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(requeueAfter).To(Equal(preemptionConfirmationPeriod))
+			Expect(getCurrent().Status.Preemption).To(BeNil())
+		})
+
+		When("the preemptor is not persisted before the timeout", func() {
+			var pi *peeringInfo
+
+			BeforeEach(func() {
+				p.admitted = time.Now().Add(-PreemptionConfirmationTimeout)
+				pi = spv.PeeringCache.getOrCreatePeeringInfo(userName, *resourceQuota)
+				spd := createShadowPodDescription(testShadowPodName, testNamespace, testShadowPodUID, *resourceQuota4)
+				pi.addShadowPod(spd)
+				pi.terminateShadowPod(spd)
+			})
+
+			It("should abort the preemption, and account again the resources of the ShadowPod", func() {
+				requeueAfter, err := spv.handlePreemption(ctx, &p)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(requeueAfter).To(BeZero())
+				Expect(getCurrent().Status.Preemption).To(BeNil())
+				Expect(pi.shadowPods[nsName.String()].running).To(BeTrue())
+				Expect(pi.usedQuota).To(Equal(*resourceQuota4))
+			})
+		})
+	})
+
+	It("should process the enqueued preemptions", func() {
+		spv.enqueuePreemptions([]preemption{p})
+		Expect(spv.processNextPreemption(ctx)).To(BeTrue())
+		Expect(getCurrent().Status.Preemption).ToNot(BeNil())
+
+		spv.preemptions.ShutDown()
+		Expect(spv.processNextPreemption(ctx)).To(BeFalse())
+	})
+})
@@ -32,6 +32,8 @@ import (
 type peeringCache struct {
 	peeringInfo sync.Map
 	ready       bool
+	// mu serializes the admission of new ShadowPods, as borrowing resources requires a consistent view of all the peerings.
+	mu sync.Mutex
 }
 
 /**
@@ -56,6 +58,7 @@ func (spv *Validator) initializeCache(ctx context.Context) (err error) {
 		q := &quotaList.Items[i]
 		klog.V(4).Infof("Generating PeeringInfo in cache for corresponding Quota %s", klog.KObj(q))
 		pi := createPeeringInfo(q.Spec.User, q.Spec.Resources)
+		pi.alignBorrowingPolicy(q)
 
 		// Get the List of shadow pods running on the cluster with a given creator
 		shadowPodList, err := getters.ListShadowPodsByCreator(ctx, spv.client, q.Spec.User)
@@ -66,6 +69,15 @@ func (spv *Validator) initializeCache(ctx context.Context) (err error) {
 		klog.V(5).Infof("Found %d ShadowPods running for user %s", len(shadowPodList.Items), q.Spec.User)
 		pi.alignExistingShadowPods(shadowPodList)
 
+		// Get the List of shadow jobs running on the cluster with a given creator
+		shadowJobList, err := getters.ListShadowJobsByCreator(ctx, spv.client, q.Spec.User)
+		if err != nil {
+			return err
+		}
+
+		klog.V(5).Infof("Found %d ShadowJobs for user %s", len(shadowJobList.Items), q.Spec.User)
+		pi.alignExistingShadowJobs(shadowJobList)
+
 		spv.PeeringCache.peeringInfo.Store(q.Spec.User, pi)
 	}
 	klog.Infof("Cache initialization completed. Found %d Quotas", len(quotaList.Items))
@@ -96,6 +108,15 @@ func (spv *Validator) refreshCache(ctx context.Context) (done bool, err error) {
 			// Flush terminating ShadowPods and check the correct alignment between users and cache
 			klog.V(5).Infof("Aligning ShadowPods for user %q", pi.userName)
 			pi.alignTerminatingOrNotExistingShadowPods(shadowPodList)
+
+			// Get the List of shadow jobs for a given creator
+			shadowJobList, err := getters.ListShadowJobsByCreator(ctx, spv.client, pi.userName)
+			if err != nil {
+				klog.Warning(err)
+				return true
+			}
+			klog.V(5).Infof("Found %d ShadowJobs for user %q", len(shadowJobList.Items), pi.userName)
+			pi.alignTerminatingOrNotExistingShadowJobs(shadowJobList)
 			return true
 		},
 	)
@@ -124,11 +145,16 @@ func (pi *peeringInfo) alignTerminatingOrNotExistingShadowPods(shadowPodList *of
 	}
 	klog.V(5).Infof("Searching for terminated ShadowPodDescription to be removed from cache")
 	// Alignment of all ShadowPodDescriptions in cache
-	pi.alignTerminatingShadowPodDescriptions(spMap)
+	pi.alignTerminatingShadowPodDescriptions(spMap, false)
+	pi.alignPodGroups()
 }
 
-func (pi *peeringInfo) alignTerminatingShadowPodDescriptions(spMap map[string]struct{}) {
+// alignTerminatingShadowPodDescriptions aligns the descriptions of either the shadowpods or the shadowjobs (depending on the jobs parameter).
+func (pi *peeringInfo) alignTerminatingShadowPodDescriptions(spMap map[string]struct{}, jobs bool) {
 	for _, shadowPodDescription := range pi.shadowPods {
+		if shadowPodDescription.job != jobs {
+			continue
+		}
 		// Check if the ShadowPod is in terminating phase in cache and has been already terminated/deleted from the cluster
 		// if true ShadowPodDescription can be also deleted from the cache
 		if _, stillPresent := spMap[shadowPodDescription.namespacedName.String()]; !stillPresent {
@@ -181,8 +207,8 @@ func (spv *Validator) checkAlignmentQuotaPeeringInfo(ctx context.Context) error
 		quotaMap[quota.Spec.User] = struct{}{}
 
 		// Check if the Quota is not present in the cache
-		if newPI, found := spv.PeeringCache.peeringInfo.LoadOrStore(quota.Spec.User,
-			createPeeringInfo(quota.Spec.User, quota.Spec.Resources)); !found {
+		newPI, found := spv.PeeringCache.peeringInfo.LoadOrStore(quota.Spec.User, createPeeringInfo(quota.Spec.User, quota.Spec.Resources))
+		if !found {
 			klog.V(4).Infof("Quota for user %q not found in cache, adding it", quota.Spec.User)
 			// Get the List of ShadowPods running for a given creator
 			shadowPodList, err := getters.ListShadowPodsByCreator(ctx, spv.client, quota.Spec.User)
@@ -191,7 +217,16 @@ func (spv *Validator) checkAlignmentQuotaPeeringInfo(ctx context.Context) error
 			}
 			klog.V(5).Infof("Found %d ShadowPods running for user %s", len(shadowPodList.Items), quota.Spec.User)
 			newPI.(*peeringInfo).alignExistingShadowPods(shadowPodList)
+
+			// Get the List of ShadowJobs for a given creator
+			shadowJobList, err := getters.ListShadowJobsByCreator(ctx, spv.client, quota.Spec.User)
+			if err != nil {
+				return err
+			}
+			klog.V(5).Infof("Found %d ShadowJobs for user %s", len(shadowJobList.Items), quota.Spec.User)
+			newPI.(*peeringInfo).alignExistingShadowJobs(shadowJobList)
 		}
+		newPI.(*peeringInfo).alignBorrowingPolicy(quota)
 	}
 
 	// Check if PeeringInfos still have corresponding Quotas
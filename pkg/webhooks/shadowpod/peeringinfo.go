@@ -31,9 +31,14 @@ import (
 type peeringInfo struct {
 	userName   string
 	shadowPods map[string]*Description
+	podGroups  map[string]*podGroup
 	totalQuota corev1.ResourceList
 	usedQuota  corev1.ResourceList
-	mu         sync.RWMutex
+	// priority is the priority of the user, when competing for the resources borrowed from other users.
+	priority int32
+	// borrowingLimit is the maximum amount of resources the user is allowed to borrow beyond its quota.
+	borrowingLimit corev1.ResourceList
+	mu             sync.RWMutex
 }
 
 /**
@@ -45,6 +50,7 @@ func createPeeringInfo(userName string, resources corev1.ResourceList) *peeringI
 	return &peeringInfo{
 		userName:   userName,
 		shadowPods: map[string]*Description{},
+		podGroups:  map[string]*podGroup{},
 		totalQuota: resources,
 		usedQuota:  generateQuotaPattern(resources),
 	}
@@ -137,6 +143,16 @@ func (pi *peeringInfo) testAndUpdateCreation(ctx context.Context, c client.Clien
 	klog.V(5).Infof("Cluster %q used quota %s", pi.userName, quotaFormatter(pi.usedQuota))
 	klog.V(5).Infof("Cluster %q free quota %s", pi.userName, quotaFormatter(pi.getFreeQuota()))
 
+	group, minMember, err := getPodGroup(sp)
+	if err != nil {
+		return err
+	}
+	if group != "" {
+		return pi.testAndUpdatePodGroupCreation(spd, group, minMember, dryRun)
+	}
+
+	pi.releaseExpiredPodGroupReservations()
+
 	if err := pi.checkResources(spd); err != nil {
 		return err
 	}
@@ -149,6 +165,44 @@ func (pi *peeringInfo) testAndUpdateCreation(ctx context.Context, c client.Clien
 	return nil
 }
 
+func (pi *peeringInfo) testAndUpdateResize(sp *offloadingv1beta1.ShadowPod,
+	limitsEnforcement offloadingv1beta1.LimitsEnforcement, dryRun bool) error {
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+
+	spd, err := pi.getShadowPodDescription(sp)
+	if err != nil {
+		return err
+	}
+
+	spQuota, err := getQuotaFromShadowPod(sp, limitsEnforcement)
+	if err != nil {
+		return err
+	}
+
+	klog.V(5).Infof("ShadowPod resource limits %s (previous %s)", quotaFormatter(*spQuota), quotaFormatter(spd.quota))
+	return pi.replaceQuota(spd, *spQuota, dryRun)
+}
+
+// replaceQuota replaces the quota accounted to the given description, provided that the new one fits in the free quota.
+func (pi *peeringInfo) replaceQuota(spd *Description, quota corev1.ResourceList, dryRun bool) error {
+	klog.V(5).Infof("Cluster %q used quota %s", pi.userName, quotaFormatter(pi.usedQuota))
+	klog.V(5).Infof("Cluster %q free quota %s", pi.userName, quotaFormatter(pi.getFreeQuota()))
+
+	// Temporarily release the resources currently accounted to the description, to check whether the new ones fit.
+	pi.subUsedResources(spd.quota)
+	if err := pi.checkResources(&Description{quota: quota}); err != nil || dryRun {
+		pi.addUsedResources(spd.quota)
+		return err
+	}
+
+	spd.quota = quota
+	pi.addUsedResources(spd.quota)
+	klog.V(5).Infof("Cluster %q updated used quota %s", pi.userName, quotaFormatter(pi.usedQuota))
+	klog.V(5).Infof("Cluster %q updated free quota %s", pi.userName, quotaFormatter(pi.getFreeQuota()))
+	return nil
+}
+
 func (pi *peeringInfo) updateDeletion(sp *offloadingv1beta1.ShadowPod, dryRun bool) error {
 	pi.mu.Lock()
 	defer pi.mu.Unlock()
@@ -164,7 +218,8 @@ func (pi *peeringInfo) updateDeletion(sp *offloadingv1beta1.ShadowPod, dryRun bo
 	klog.V(5).Infof("Cluster %q used quota %s", pi.userName, quotaFormatter(pi.usedQuota))
 	klog.V(5).Infof("Cluster %q free quota %s", pi.userName, quotaFormatter(pi.getFreeQuota()))
 
-	if !dryRun {
+	// The resources of preempted ShadowPods have been already released upon preemption.
+	if !dryRun && spd.running {
 		pi.terminateShadowPod(spd)
 		klog.V(5).Infof("Cluster %q updated total quota %s", pi.userName, quotaFormatter(pi.totalQuota))
 		klog.V(5).Infof("Cluster %q updated used quota %s", pi.userName, quotaFormatter(pi.usedQuota))
@@ -182,13 +237,19 @@ func (pi *peeringInfo) checkResources(spd *Description) error {
 				return fmt.Errorf("peering %s quota usage exceeded - free %s / requested %s",
 					key, freeQuota.String(), val.String())
 			}
-		} else {
+		} else if !isObjectCountResource(key) {
 			return fmt.Errorf("%s quota limit not found for this peering", key)
 		}
 	}
 	return nil
 }
 
+// isObjectCountResource returns whether the given resource refers to a number of objects (e.g., pods), rather than to compute resources.
+// Differently from compute resources, object counts are enforced only if explicitly included in the quota.
+func isObjectCountResource(key corev1.ResourceName) bool {
+	return key == corev1.ResourcePods
+}
+
 func (pi *peeringInfo) updateQuotas(newQuota corev1.ResourceList) {
 	klog.V(5).Infof("Cluster %q old total quota %s", pi.userName, quotaFormatter(pi.totalQuota))
 	pi.totalQuota = newQuota.DeepCopy()
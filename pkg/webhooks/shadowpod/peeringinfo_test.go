@@ -130,6 +130,25 @@ var _ = Describe("Peering Info", func() {
 				Expect(err).To(Equal(errTest))
 			})
 		})
+		When("The number of pods is not limited for a specific peering", func() {
+			BeforeEach(func() {
+				peeringInfo = createPeeringInfo(userName, *resourceQuota)
+				spd = createShadowPodDescription(testShadowPodName, testNamespace, testShadowPodUID, withPods(resourceQuota, 1))
+			})
+			It("should not return any error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+		When("The number of pods exceeds the quota", func() {
+			BeforeEach(func() {
+				peeringInfo = createPeeringInfo(userName, withPods(resourceQuota, 1))
+				peeringInfo.addShadowPod(createShadowPodDescription("other", testNamespace, "other-uid", withPods(forgeResourceList(0, 0), 1)))
+				spd = createShadowPodDescription(testShadowPodName, testNamespace, testShadowPodUID, withPods(forgeResourceList(1, 1), 1))
+			})
+			It("should return an error", func() {
+				Expect(err).To(MatchError(ContainSubstring("peering pods quota usage exceeded")))
+			})
+		})
 		When("A requested resource quota is not defined for a specific peering", func() {
 			BeforeEach(func() {
 				peeringInfo = createPeeringInfo(userName, *resourceQuota)
@@ -156,7 +175,7 @@ var _ = Describe("Peering Info", func() {
 			})
 			It("should not return any error and available resources will be decremented", func() {
 				Expect(err).To(BeNil())
-				Expect(peeringInfo.usedQuota).To(Equal(*resourceQuota))
+				Expect(peeringInfo.usedQuota).To(Equal(withPods(resourceQuota, 1)))
 				Expect(peeringInfo.getFreeQuota()).To(Equal(*freeQuotaZero))
 			})
 		})
@@ -196,6 +215,48 @@ var _ = Describe("Peering Info", func() {
 		})
 	})
 
+	Describe("Test and update resize", func() {
+		JustBeforeEach(func() {
+			err = peeringInfo.testAndUpdateResize(shadowPod, offloadingv1beta1.SoftLimitsEnforcement, dryRun)
+		})
+
+		BeforeEach(func() {
+			dryRun = false
+			peeringInfo = createPeeringInfo(userName, *resourceQuota)
+			peeringInfo.addShadowPod(createShadowPodDescription(testShadowPodName, testNamespace, testShadowPodUID, *resourceQuota2))
+		})
+
+		When("the resized shadow pod fits in the quota and dryRun flag is false", func() {
+			It("should not return any error and used resources will be updated", func() {
+				Expect(err).To(BeNil())
+				Expect(peeringInfo.usedQuota).To(Equal(withPods(resourceQuota, 1)))
+				Expect(peeringInfo.getFreeQuota()).To(Equal(*freeQuotaZero))
+			})
+		})
+		When("the resized shadow pod fits in the quota and dryRun flag is true", func() {
+			BeforeEach(func() { dryRun = true })
+			It("should not return any error and used resources will not be updated", func() {
+				Expect(err).To(BeNil())
+				Expect(peeringInfo.usedQuota.Cpu().Value()).To(Equal(resourceQuota2.Cpu().Value()))
+			})
+		})
+		When("the resized shadow pod does not fit in the quota", func() {
+			BeforeEach(func() {
+				shadowPod = forgeShadowPodWithResourceRequests([]containerResource{{cpu: int64(resourceCPU * 2), memory: int64(resourceMemory)}}, nil)
+			})
+			It("should return an error and used resources will not be updated", func() {
+				Expect(err).ToNot(BeNil())
+				Expect(peeringInfo.usedQuota.Cpu().Value()).To(Equal(resourceQuota2.Cpu().Value()))
+			})
+		})
+		When("the shadow pod description does not exist", func() {
+			BeforeEach(func() { peeringInfo = createPeeringInfo(userName, *resourceQuota) })
+			It("should return an error", func() {
+				Expect(err).ToNot(BeNil())
+			})
+		})
+	})
+
 	Describe("Update deletion", func() {
 		JustBeforeEach(func() {
 			err = peeringInfo.updateDeletion(shadowPod, dryRun)
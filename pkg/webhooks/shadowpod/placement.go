@@ -0,0 +1,137 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowpod
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+)
+
+// checkPlacement checks whether the scheduling constraints of the given pod are compatible with the node selector granted
+// to the consumer cluster, regardless of whether the resource enforcement is enabled. The ResourceSlice associated with
+// the creator is considered if known, while the pod shall be compatible with at least one of the consumer ones otherwise.
+func checkPlacement(ctx context.Context, cl client.Client, clusterID liqov1beta1.ClusterID, creatorName string, spec *corev1.PodSpec) error {
+	if creatorName != "" {
+		quota, err := getters.GetQuotaByUser(ctx, cl, creatorName)
+		switch {
+		case err == nil:
+			granted, err := getResourceSliceNodeSelector(ctx, cl, quota)
+			if err != nil {
+				klog.Warningf("Failed getting ResourceSlice for user %s: %v", creatorName, err)
+				return errors.New("failed getting ResourceSlice")
+			}
+			return checkSchedulingConstraints(spec, granted)
+		case !apierrors.IsNotFound(err):
+			klog.Warningf("Failed getting quota for user %s: %v", creatorName, err)
+			return errors.New("failed getting quota")
+		}
+	}
+
+	resourceSlices, err := getters.ListResourceSlicesByClusterID(ctx, cl, clusterID)
+	if err != nil {
+		klog.Warningf("Failed getting ResourceSlices of cluster %s: %v", clusterID, err)
+		return errors.New("failed getting ResourceSlices")
+	}
+
+	var checkErr error
+	for i := range resourceSlices {
+		if checkErr = checkSchedulingConstraints(spec, resourceSlices[i].Status.NodeSelector); checkErr == nil {
+			return nil
+		}
+	}
+	return checkErr
+}
+
+// getResourceSliceNodeSelector returns the node selector granted by the ResourceSlice owning the given quota, if any.
+func getResourceSliceNodeSelector(ctx context.Context, cl client.Client, quota *offloadingv1beta1.Quota) (map[string]string, error) {
+	owner := metav1.GetControllerOf(quota)
+	if owner == nil || owner.Kind != authv1beta1.ResourceSliceKind {
+		return nil, nil
+	}
+
+	var resourceSlice authv1beta1.ResourceSlice
+	if err := cl.Get(ctx, client.ObjectKey{Name: owner.Name, Namespace: quota.Namespace}, &resourceSlice); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	return resourceSlice.Status.NodeSelector, nil
+}
+
+// checkSchedulingConstraints checks whether the scheduling constraints of the given pod (i.e., the node selector and the
+// required node affinity, which can be customized by the consumer through the remote scheduling hints) are compatible
+// with the node selector granted by the ResourceSlice.
+func checkSchedulingConstraints(spec *corev1.PodSpec, granted map[string]string) error {
+	for key, value := range spec.NodeSelector {
+		if grantedValue, found := granted[key]; found && grantedValue != value {
+			return fmt.Errorf("node selector %s=%s conflicts with the one granted by the ResourceSlice (%s=%s)", key, value, key, grantedValue)
+		}
+	}
+
+	if spec.Affinity == nil || spec.Affinity.NodeAffinity == nil || spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return nil
+	}
+
+	// Node selector terms are ORed, hence it is sufficient that one of them is compatible with the granted node selector.
+	terms := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	for i := range terms {
+		if isNodeSelectorTermCompatible(&terms[i], granted) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("required node affinity conflicts with the node selector granted by the ResourceSlice")
+}
+
+// isNodeSelectorTermCompatible returns whether the label requirements of the given term, concerning the keys of the granted
+// node selector, are satisfied by the corresponding values. Requirements on other keys are not considered.
+func isNodeSelectorTermCompatible(term *corev1.NodeSelectorTerm, granted map[string]string) bool {
+	for i := range term.MatchExpressions {
+		req := &term.MatchExpressions[i]
+		value, found := granted[req.Key]
+		if !found {
+			continue
+		}
+
+		switch req.Operator {
+		case corev1.NodeSelectorOpIn:
+			if !slices.Contains(req.Values, value) {
+				return false
+			}
+		case corev1.NodeSelectorOpNotIn:
+			if slices.Contains(req.Values, value) {
+				return false
+			}
+		case corev1.NodeSelectorOpDoesNotExist:
+			return false
+		case corev1.NodeSelectorOpExists, corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+			// Either always satisfied, or not meaningful for the granted node selector.
+		}
+	}
+
+	return true
+}
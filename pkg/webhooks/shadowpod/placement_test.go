@@ -0,0 +1,79 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowpod
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Scheduling constraints validation", func() {
+	var (
+		spec    corev1.PodSpec
+		granted map[string]string
+		err     error
+	)
+
+	affinity := func(terms ...corev1.NodeSelectorTerm) *corev1.Affinity {
+		return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+		}}
+	}
+
+	term := func(key string, op corev1.NodeSelectorOperator, values ...string) corev1.NodeSelectorTerm {
+		return corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: key, Operator: op, Values: values}}}
+	}
+
+	BeforeEach(func() {
+		spec = corev1.PodSpec{}
+		granted = map[string]string{"pool": "liqo"}
+	})
+
+	JustBeforeEach(func() { err = checkSchedulingConstraints(&spec, granted) })
+
+	When("no constraint is specified", func() {
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+	})
+
+	When("the node selector is compatible", func() {
+		BeforeEach(func() { spec.NodeSelector = map[string]string{"pool": "liqo", "zone": "a"} })
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+	})
+
+	When("the node selector conflicts with the granted one", func() {
+		BeforeEach(func() { spec.NodeSelector = map[string]string{"pool": "other"} })
+		It("should fail", func() { Expect(err).To(HaveOccurred()) })
+	})
+
+	When("one of the required node affinity terms is compatible", func() {
+		BeforeEach(func() {
+			spec.Affinity = affinity(term("pool", corev1.NodeSelectorOpIn, "other"), term("pool", corev1.NodeSelectorOpIn, "liqo"))
+		})
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+	})
+
+	When("the required node affinity concerns other keys only", func() {
+		BeforeEach(func() { spec.Affinity = affinity(term("zone", corev1.NodeSelectorOpDoesNotExist)) })
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+	})
+
+	When("all the required node affinity terms conflict with the granted node selector", func() {
+		BeforeEach(func() {
+			spec.Affinity = affinity(term("pool", corev1.NodeSelectorOpNotIn, "liqo"), term("pool", corev1.NodeSelectorOpDoesNotExist))
+		})
+		It("should fail", func() { Expect(err).To(HaveOccurred()) })
+	})
+})
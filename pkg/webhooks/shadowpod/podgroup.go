@@ -0,0 +1,152 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowpod
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+// podGroupReservationTimeout is the maximum time the resources reserved for the members of a pod group
+// not yet created are retained, before being released.
+const podGroupReservationTimeout = 2 * time.Minute
+
+// podGroup is the struct that holds the information about a group of ShadowPods to be admitted atomically.
+type podGroup struct {
+	// members contains the namespaced names of the admitted members of the group.
+	members map[string]struct{}
+	// pending is the number of members whose resources are still reserved.
+	pending int
+	// perMember is the amount of resources reserved for each pending member.
+	perMember         corev1.ResourceList
+	creationTimestamp time.Time
+}
+
+// getPodGroup returns the key identifying the pod group the ShadowPod belongs to (empty if none) and its minimum number of members.
+func getPodGroup(sp *offloadingv1beta1.ShadowPod) (key string, minMember int, err error) {
+	name, found := sp.GetAnnotations()[consts.PodGroupAnnotationKey]
+	if !found || name == "" {
+		return "", 0, nil
+	}
+
+	minMember = 1
+	if value, found := sp.GetAnnotations()[consts.PodGroupMinMemberAnnotationKey]; found {
+		if minMember, err = strconv.Atoi(value); err != nil || minMember < 1 {
+			return "", 0, fmt.Errorf("invalid %s annotation %q: expected a positive integer", consts.PodGroupMinMemberAnnotationKey, value)
+		}
+	}
+
+	key = sp.GetNamespace() + "/" + name
+	// Different instances of the same workload (e.g., a job deleted and created again) share the name of the group,
+	// hence they are told apart through the UID of their owner, to prevent them from consuming each other's reservations.
+	if uid := sp.GetAnnotations()[consts.PodGroupOwnerUIDAnnotationKey]; uid != "" {
+		key += "/" + uid
+	}
+	return key, minMember, nil
+}
+
+// testAndUpdatePodGroupCreation checks whether the given ShadowPod, member of a pod group, can be admitted.
+// When the first member is received, the resources for all the minimum members of the group are checked and
+// reserved at once, so that either the whole group fits in the quota or it is entirely rejected.
+// The subsequent members consume the resources previously reserved.
+func (pi *peeringInfo) testAndUpdatePodGroupCreation(spd *Description, key string, minMember int, dryRun bool) error {
+	pi.releaseExpiredPodGroupReservations()
+
+	group, found := pi.podGroups[key]
+	if !found {
+		if err := pi.checkResources(&Description{quota: multiplyResources(spd.quota, minMember)}); err != nil {
+			return fmt.Errorf("pod group %s (min member %d) does not fit: %w", key, minMember, err)
+		}
+		if dryRun {
+			return nil
+		}
+
+		group = &podGroup{
+			members:           map[string]struct{}{},
+			pending:           minMember - 1,
+			perMember:         spd.quota.DeepCopy(),
+			creationTimestamp: time.Now(),
+		}
+		pi.podGroups[key] = group
+		pi.addUsedResources(multiplyResources(group.perMember, group.pending))
+		klog.V(4).Infof("Pod group %s: reserved resources for %d additional members", key, group.pending)
+	} else if group.pending > 0 {
+		// Temporarily release the resources reserved for one member, to check whether the current one fits.
+		pi.subUsedResources(group.perMember)
+		if err := pi.checkResources(spd); err != nil || dryRun {
+			pi.addUsedResources(group.perMember)
+			return err
+		}
+		group.pending--
+	} else if err := pi.checkResources(spd); err != nil || dryRun {
+		return err
+	}
+
+	group.members[spd.namespacedName.String()] = struct{}{}
+	pi.addShadowPod(spd)
+	return nil
+}
+
+// alignPodGroups releases the resources reserved for the members of the pod groups not created in due time,
+// and removes the pod groups without running members.
+func (pi *peeringInfo) alignPodGroups() {
+	pi.releaseExpiredPodGroupReservations()
+
+	for key, group := range pi.podGroups {
+		if group.pending == 0 && !pi.hasRunningMembers(group) {
+			delete(pi.podGroups, key)
+			klog.V(5).Infof("Pod group %s removed from cache", key)
+		}
+	}
+}
+
+// releaseExpiredPodGroupReservations releases the resources reserved for the members of the pod groups not created in due time.
+// It is invoked upon each admission as well, so that the expired reservations do not prevent other ShadowPods from being
+// admitted until the next alignment of the cache.
+func (pi *peeringInfo) releaseExpiredPodGroupReservations() {
+	for key, group := range pi.podGroups {
+		if group.pending > 0 && time.Since(group.creationTimestamp) > podGroupReservationTimeout {
+			klog.Warningf("Pod group %s: releasing resources reserved for %d members not yet created", key, group.pending)
+			pi.subUsedResources(multiplyResources(group.perMember, group.pending))
+			group.pending = 0
+		}
+	}
+}
+
+func (pi *peeringInfo) hasRunningMembers(group *podGroup) bool {
+	for member := range group.members {
+		if spd, found := pi.shadowPods[member]; found && spd.running {
+			return true
+		}
+	}
+	return false
+}
+
+func multiplyResources(resources corev1.ResourceList, factor int) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	for key, val := range resources {
+		tmp := val.DeepCopy()
+		tmp.Mul(int64(factor))
+		result[key] = tmp
+	}
+	return result
+}
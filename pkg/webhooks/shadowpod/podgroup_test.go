@@ -0,0 +1,135 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowpod
+
+import (
+	"fmt"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("Pod groups", func() {
+	var (
+		peeringInfo *peeringInfo
+		err         error
+	)
+
+	forgeMember := func(index, minMember int) *offloadingv1beta1.ShadowPod {
+		sp := forgeShadowPod(fmt.Sprintf("member-%d", index), testNamespace, fmt.Sprintf("uid-%d", index), userName)
+		sp.Annotations = map[string]string{
+			consts.PodGroupAnnotationKey:          "group",
+			consts.PodGroupMinMemberAnnotationKey: strconv.Itoa(minMember),
+		}
+		return sp
+	}
+
+	create := func(sp *offloadingv1beta1.ShadowPod, dryRun bool) error {
+		return peeringInfo.testAndUpdateCreation(ctx, fake.NewClientBuilder().WithScheme(scheme).Build(),
+			sp, offloadingv1beta1.SoftLimitsEnforcement, dryRun)
+	}
+
+	// Each member requests a quarter of the available resources.
+	BeforeEach(func() { peeringInfo = createPeeringInfo(userName, *resourceQuota) })
+
+	When("the whole group fits in the quota", func() {
+		JustBeforeEach(func() { err = create(forgeMember(0, 4), false) })
+
+		It("should admit the first member", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should reserve the resources for the whole group", func() {
+			Expect(peeringInfo.usedQuota).To(Equal(withPods(resourceQuota, 4)))
+			Expect(peeringInfo.podGroups).To(HaveKey(testNamespace + "/group"))
+			Expect(peeringInfo.podGroups[testNamespace+"/group"].pending).To(Equal(3))
+		})
+		It("should admit the other members, consuming the reserved resources", func() {
+			for i := 1; i < 4; i++ {
+				Expect(create(forgeMember(i, 4), false)).To(Succeed())
+			}
+			Expect(peeringInfo.usedQuota).To(Equal(withPods(resourceQuota, 4)))
+			Expect(peeringInfo.podGroups[testNamespace+"/group"].pending).To(BeZero())
+			Expect(peeringInfo.podGroups[testNamespace+"/group"].members).To(HaveLen(4))
+		})
+		It("should reject the shadowpods not belonging to the group", func() {
+			Expect(create(forgeShadowPod("other", testNamespace, "other", userName), false)).ToNot(Succeed())
+		})
+		It("should release the reserved resources when expired", func() {
+			peeringInfo.podGroups[testNamespace+"/group"].creationTimestamp = peeringInfo.podGroups[testNamespace+"/group"].
+				creationTimestamp.Add(-2 * podGroupReservationTimeout)
+			peeringInfo.alignPodGroups()
+			Expect(peeringInfo.podGroups[testNamespace+"/group"].pending).To(BeZero())
+			Expect(create(forgeShadowPod("other", testNamespace, "other", userName), false)).To(Succeed())
+		})
+		It("should release the expired reservations upon admission, without waiting for the cache alignment", func() {
+			peeringInfo.podGroups[testNamespace+"/group"].creationTimestamp = peeringInfo.podGroups[testNamespace+"/group"].
+				creationTimestamp.Add(-2 * podGroupReservationTimeout)
+			Expect(create(forgeShadowPod("other", testNamespace, "other", userName), false)).To(Succeed())
+			Expect(peeringInfo.podGroups[testNamespace+"/group"].pending).To(BeZero())
+		})
+	})
+
+	When("the members belong to different instances of the same workload", func() {
+		withOwner := func(sp *offloadingv1beta1.ShadowPod, uid string) *offloadingv1beta1.ShadowPod {
+			sp.Annotations[consts.PodGroupOwnerUIDAnnotationKey] = uid
+			return sp
+		}
+
+		JustBeforeEach(func() { err = create(withOwner(forgeMember(0, 2), "first"), false) })
+
+		It("should not consume the reservations of the other instance", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(peeringInfo.podGroups).To(HaveKey(testNamespace + "/group/first"))
+			Expect(create(withOwner(forgeMember(1, 2), "second"), false)).To(Succeed())
+			Expect(peeringInfo.podGroups).To(HaveKey(testNamespace + "/group/second"))
+			Expect(peeringInfo.podGroups[testNamespace+"/group/first"].pending).To(Equal(1))
+			Expect(peeringInfo.podGroups[testNamespace+"/group/second"].pending).To(Equal(1))
+			Expect(peeringInfo.usedQuota).To(Equal(withPods(resourceQuota, 4)))
+		})
+	})
+
+	When("the whole group does not fit in the quota", func() {
+		JustBeforeEach(func() { err = create(forgeMember(0, 5), false) })
+
+		It("should reject the first member", func() { Expect(err).To(HaveOccurred()) })
+		It("should not reserve any resource", func() {
+			Expect(peeringInfo.usedQuota.Cpu().Value()).To(BeZero())
+			Expect(peeringInfo.podGroups).To(BeEmpty())
+		})
+	})
+
+	When("the dry-run flag is set", func() {
+		JustBeforeEach(func() { err = create(forgeMember(0, 4), true) })
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should not reserve any resource", func() {
+			Expect(peeringInfo.usedQuota.Cpu().Value()).To(BeZero())
+			Expect(peeringInfo.podGroups).To(BeEmpty())
+		})
+	})
+
+	When("the min member annotation is invalid", func() {
+		JustBeforeEach(func() {
+			sp := forgeMember(0, 1)
+			sp.Annotations[consts.PodGroupMinMemberAnnotationKey] = "invalid"
+			err = create(sp, false)
+		})
+
+		It("should fail", func() { Expect(err).To(HaveOccurred()) })
+	})
+})
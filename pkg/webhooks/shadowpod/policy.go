@@ -0,0 +1,249 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowpod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+)
+
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=shadowpodpolicies,verbs=get;list;watch
+
+const allCapabilities corev1.Capability = "ALL"
+
+// policyViolationsCounter is the counter of the ShadowPods and ShadowJobs rejected due to the violation of a ShadowPodPolicy.
+var policyViolationsCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "liqo_webhook_shadowpod_policy_violations_total",
+		Help: "The number of ShadowPods and ShadowJobs rejected due to the violation of a ShadowPodPolicy.",
+	},
+	[]string{"policy", "cluster_id"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(policyViolationsCounter)
+}
+
+// policyViolation describes the violations of a given ShadowPodPolicy.
+type policyViolation struct {
+	policy     string
+	violations []string
+}
+
+func (pv *policyViolation) String() string {
+	return fmt.Sprintf("ShadowPodPolicy %q violated: %s", pv.policy, strings.Join(pv.violations, "; "))
+}
+
+// checkShadowPodPolicies evaluates the given pod against all ShadowPodPolicies, returning the ones which are violated.
+func checkShadowPodPolicies(ctx context.Context, cl client.Client, cluster liqov1beta1.ClusterID,
+	pod *corev1.PodSpec) ([]policyViolation, error) {
+	var policies offloadingv1beta1.ShadowPodPolicyList
+	if err := cl.List(ctx, &policies); err != nil {
+		return nil, fmt.Errorf("failed retrieving the ShadowPodPolicies: %w", err)
+	}
+
+	var violated []policyViolation
+	for i := range policies.Items {
+		rules := effectiveRules(&policies.Items[i].Spec, cluster)
+		if violations := checkRules(rules, pod); len(violations) > 0 {
+			violated = append(violated, policyViolation{policy: policies.Items[i].Name, violations: violations})
+		}
+	}
+	return violated, nil
+}
+
+// effectiveRules returns the rules applying to the given cluster, that is the default ones replaced by the matching overrides.
+func effectiveRules(spec *offloadingv1beta1.ShadowPodPolicySpec, cluster liqov1beta1.ClusterID) *offloadingv1beta1.ShadowPodPolicyRules {
+	rules := spec.ShadowPodPolicyRules.DeepCopy()
+	for i := range spec.Overrides {
+		override := &spec.Overrides[i]
+		if !slices.Contains(override.ClusterIDs, cluster) {
+			continue
+		}
+
+		if override.AllowedImageRegistries != nil {
+			rules.AllowedImageRegistries = override.AllowedImageRegistries
+		}
+		if override.ForbiddenHostPaths != nil {
+			rules.ForbiddenHostPaths = override.ForbiddenHostPaths
+		}
+		if override.ForbiddenCapabilities != nil {
+			rules.ForbiddenCapabilities = override.ForbiddenCapabilities
+		}
+		if override.RequireSeccompProfile != nil {
+			rules.RequireSeccompProfile = override.RequireSeccompProfile
+		}
+		if override.MaxContainers != nil {
+			rules.MaxContainers = override.MaxContainers
+		}
+		if override.AllowedVolumeTypes != nil {
+			rules.AllowedVolumeTypes = override.AllowedVolumeTypes
+		}
+	}
+	return rules
+}
+
+// checkRules returns the list of violations of the given rules by the given pod.
+func checkRules(rules *offloadingv1beta1.ShadowPodPolicyRules, pod *corev1.PodSpec) []string {
+	var violations []string
+
+	containers := make([]*corev1.Container, 0, len(pod.InitContainers)+len(pod.Containers)+len(pod.EphemeralContainers))
+	for i := range pod.InitContainers {
+		containers = append(containers, &pod.InitContainers[i])
+	}
+	for i := range pod.Containers {
+		containers = append(containers, &pod.Containers[i])
+	}
+	// Ephemeral containers share the same fields as the regular ones.
+	for i := range pod.EphemeralContainers {
+		containers = append(containers, (*corev1.Container)(&pod.EphemeralContainers[i].EphemeralContainerCommon))
+	}
+
+	if rules.MaxContainers != nil && len(containers) > int(*rules.MaxContainers) {
+		violations = append(violations, fmt.Sprintf("the pod has %d containers, exceeding the maximum of %d", len(containers), *rules.MaxContainers))
+	}
+
+	for _, container := range containers {
+		if len(rules.AllowedImageRegistries) > 0 && !isImageAllowed(container.Image, rules.AllowedImageRegistries) {
+			violations = append(violations, fmt.Sprintf("image %q of container %q is not pulled from an allowed registry", container.Image, container.Name))
+		}
+
+		if capability, found := forbiddenCapability(container, rules.ForbiddenCapabilities); found {
+			violations = append(violations, fmt.Sprintf("container %q adds the forbidden capability %q", container.Name, capability))
+		}
+
+		if ptr.Deref(rules.RequireSeccompProfile, false) && !isSeccompConfined(container, pod.SecurityContext) {
+			violations = append(violations, fmt.Sprintf("container %q is not confined by a seccomp profile", container.Name))
+		}
+	}
+
+	for i := range pod.Volumes {
+		volume := &pod.Volumes[i]
+		if len(rules.AllowedVolumeTypes) > 0 {
+			if volumeType := getVolumeType(volume); !slices.Contains(rules.AllowedVolumeTypes, volumeType) {
+				violations = append(violations, fmt.Sprintf("volume %q has the forbidden type %q", volume.Name, volumeType))
+			}
+		}
+
+		if volume.HostPath != nil && isHostPathForbidden(volume.HostPath.Path, rules.ForbiddenHostPaths) {
+			violations = append(violations, fmt.Sprintf("volume %q mounts the forbidden host path %q", volume.Name, volume.HostPath.Path))
+		}
+	}
+
+	return violations
+}
+
+// isImageAllowed returns whether the given image is pulled from one of the allowed registries.
+func isImageAllowed(image string, registries []string) bool {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return false
+	}
+
+	name := named.Name()
+	for _, registry := range registries {
+		registry = strings.TrimSuffix(registry, "/")
+		if name == registry || strings.HasPrefix(name, registry+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// forbiddenCapability returns the first forbidden capability added by the given container, if any.
+func forbiddenCapability(container *corev1.Container, forbidden []corev1.Capability) (corev1.Capability, bool) {
+	if len(forbidden) == 0 || container.SecurityContext == nil {
+		return "", false
+	}
+
+	normalize := func(capability corev1.Capability) corev1.Capability {
+		return corev1.Capability(strings.TrimPrefix(strings.ToUpper(string(capability)), "CAP_"))
+	}
+
+	added := []corev1.Capability{}
+	if container.SecurityContext.Capabilities != nil {
+		added = container.SecurityContext.Capabilities.Add
+	}
+	// Privileged containers are granted all capabilities.
+	if ptr.Deref(container.SecurityContext.Privileged, false) {
+		added = append(slices.Clone(added), allCapabilities)
+	}
+
+	for _, capability := range forbidden {
+		for _, add := range added {
+			if normalize(capability) == allCapabilities || normalize(add) == allCapabilities || normalize(capability) == normalize(add) {
+				return add, true
+			}
+		}
+	}
+	return "", false
+}
+
+// isSeccompConfined returns whether the given container is confined by a seccomp profile,
+// either configured at the container level or inherited from the pod one.
+func isSeccompConfined(container *corev1.Container, podContext *corev1.PodSecurityContext) bool {
+	var profile *corev1.SeccompProfile
+	switch {
+	case container.SecurityContext != nil && container.SecurityContext.SeccompProfile != nil:
+		profile = container.SecurityContext.SeccompProfile
+	case podContext != nil:
+		profile = podContext.SeccompProfile
+	}
+
+	return profile != nil && (profile.Type == corev1.SeccompProfileTypeRuntimeDefault || profile.Type == corev1.SeccompProfileTypeLocalhost)
+}
+
+// isHostPathForbidden returns whether the given host path corresponds to (or is a subdirectory of) a forbidden one.
+func isHostPathForbidden(hostPath string, forbidden []string) bool {
+	hostPath = path.Clean("/" + hostPath)
+	for _, prefix := range forbidden {
+		prefix = path.Clean("/" + prefix)
+		if prefix == "/" || hostPath == prefix || strings.HasPrefix(hostPath, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// getVolumeType returns the type of the given volume, named after the corresponding field of the volume source.
+func getVolumeType(volume *corev1.Volume) string {
+	raw, err := json.Marshal(&volume.VolumeSource)
+	if err != nil {
+		return ""
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return ""
+	}
+	for field := range fields {
+		return field
+	}
+	return ""
+}
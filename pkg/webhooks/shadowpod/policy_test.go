@@ -0,0 +1,182 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowpod
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+)
+
+var _ = Describe("ShadowPodPolicy validation", func() {
+	var (
+		rules      offloadingv1beta1.ShadowPodPolicyRules
+		spec       corev1.PodSpec
+		violations []string
+	)
+
+	BeforeEach(func() {
+		rules = offloadingv1beta1.ShadowPodPolicyRules{}
+		spec = corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "nginx:latest"}}}
+	})
+
+	JustBeforeEach(func() {
+		violations = checkRules(&rules, &spec)
+	})
+
+	When("no rule is configured", func() {
+		It("should admit the pod", func() { Expect(violations).To(BeEmpty()) })
+	})
+
+	When("the allowed image registries are configured", func() {
+		BeforeEach(func() {
+			rules.AllowedImageRegistries = []string{"docker.io/library", "ghcr.io/liqotech/"}
+			spec.InitContainers = []corev1.Container{{Name: "init", Image: "ghcr.io/liqotech/liqo:v1.0.0"}}
+		})
+
+		It("should admit the images pulled from the allowed registries", func() { Expect(violations).To(BeEmpty()) })
+
+		When("an image is pulled from a different registry", func() {
+			BeforeEach(func() { spec.Containers[0].Image = "ghcr.io/liqotech-fake/nginx" })
+			It("should reject the pod", func() { Expect(violations).To(ConsistOf(ContainSubstring("allowed registry"))) })
+		})
+
+		When("the image of an ephemeral container is pulled from a different registry", func() {
+			BeforeEach(func() {
+				spec.EphemeralContainers = []corev1.EphemeralContainer{{EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+					Name: "debug", Image: "ghcr.io/liqotech-fake/busybox"}}}
+			})
+			It("should reject the pod", func() { Expect(violations).To(ConsistOf(ContainSubstring(`container "debug"`))) })
+		})
+	})
+
+	When("the forbidden host paths are configured", func() {
+		BeforeEach(func() {
+			rules.ForbiddenHostPaths = []string{"/var/run"}
+			spec.Volumes = []corev1.Volume{{Name: "host", VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{Path: "/var/log"}}}}
+		})
+
+		It("should admit the other host paths", func() { Expect(violations).To(BeEmpty()) })
+
+		When("a subdirectory of a forbidden host path is mounted", func() {
+			BeforeEach(func() { spec.Volumes[0].HostPath.Path = "/var/run/../run/docker.sock" })
+			It("should reject the pod", func() { Expect(violations).To(ConsistOf(ContainSubstring("forbidden host path"))) })
+		})
+	})
+
+	When("the forbidden capabilities are configured", func() {
+		BeforeEach(func() {
+			rules.ForbiddenCapabilities = []corev1.Capability{"SYS_ADMIN"}
+			spec.Containers[0].SecurityContext = &corev1.SecurityContext{
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_BIND_SERVICE"}}}
+		})
+
+		It("should admit the other capabilities", func() { Expect(violations).To(BeEmpty()) })
+
+		When("a forbidden capability is added", func() {
+			BeforeEach(func() { spec.Containers[0].SecurityContext.Capabilities.Add = []corev1.Capability{"CAP_SYS_ADMIN"} })
+			It("should reject the pod", func() { Expect(violations).To(ConsistOf(ContainSubstring("forbidden capability"))) })
+		})
+
+		When("the container is privileged", func() {
+			BeforeEach(func() { spec.Containers[0].SecurityContext.Privileged = ptr.To(true) })
+			It("should reject the pod", func() { Expect(violations).To(ConsistOf(ContainSubstring("forbidden capability"))) })
+		})
+	})
+
+	When("the seccomp profile is required", func() {
+		BeforeEach(func() {
+			rules.RequireSeccompProfile = ptr.To(true)
+			spec.SecurityContext = &corev1.PodSecurityContext{SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}}
+		})
+
+		It("should admit the pods inheriting the pod profile", func() { Expect(violations).To(BeEmpty()) })
+
+		When("a container is unconfined", func() {
+			BeforeEach(func() {
+				spec.Containers[0].SecurityContext = &corev1.SecurityContext{
+					SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeUnconfined}}
+			})
+			It("should reject the pod", func() { Expect(violations).To(ConsistOf(ContainSubstring("seccomp profile"))) })
+		})
+	})
+
+	When("the maximum number of containers is configured", func() {
+		BeforeEach(func() {
+			rules.MaxContainers = ptr.To[int32](1)
+			spec.InitContainers = []corev1.Container{{Name: "init", Image: "busybox"}}
+		})
+		It("should reject the pods exceeding it", func() { Expect(violations).To(ConsistOf(ContainSubstring("exceeding the maximum"))) })
+	})
+
+	When("the allowed volume types are configured", func() {
+		BeforeEach(func() {
+			rules.AllowedVolumeTypes = []string{"configMap", "emptyDir"}
+			spec.Volumes = []corev1.Volume{
+				{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{}}},
+				{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+			}
+		})
+		It("should reject the forbidden volume types", func() {
+			Expect(violations).To(ConsistOf(`volume "data" has the forbidden type "persistentVolumeClaim"`))
+		})
+	})
+
+	Describe("the checkShadowPodPolicies function", func() {
+		var (
+			cluster  liqov1beta1.ClusterID
+			violated []policyViolation
+		)
+
+		BeforeEach(func() {
+			cluster = clusterID
+			spec.Containers = append(spec.Containers, corev1.Container{Name: "sidecar", Image: "envoyproxy/envoy"})
+		})
+
+		JustBeforeEach(func() {
+			policy := &offloadingv1beta1.ShadowPodPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+				Spec: offloadingv1beta1.ShadowPodPolicySpec{
+					ShadowPodPolicyRules: offloadingv1beta1.ShadowPodPolicyRules{MaxContainers: ptr.To[int32](1)},
+					Overrides: []offloadingv1beta1.ShadowPodPolicyOverride{{
+						ClusterIDs:           []liqov1beta1.ClusterID{clusterID2},
+						ShadowPodPolicyRules: offloadingv1beta1.ShadowPodPolicyRules{MaxContainers: ptr.To[int32](2)},
+					}},
+				},
+			}
+
+			var err error
+			violated, err = checkShadowPodPolicies(ctx, fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy).Build(), cluster, &spec)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should return the violated policies", func() {
+			Expect(violated).To(HaveLen(1))
+			Expect(violated[0].String()).To(HavePrefix(`ShadowPodPolicy "policy" violated`))
+		})
+
+		When("the cluster has an override", func() {
+			BeforeEach(func() { cluster = clusterID2 })
+			It("should apply the override", func() { Expect(violated).To(BeEmpty()) })
+		})
+	})
+})
@@ -0,0 +1,158 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowpod
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+)
+
+// cluster-role
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=shadowpods,verbs=delete
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=shadowpods/status,verbs=get;update;patch
+
+const (
+	// PreemptionGracePeriod is the time elapsing between the notification of the preemption of a ShadowPod
+	// (through its status) and its actual deletion, to allow the consumer to react accordingly.
+	PreemptionGracePeriod = 10 * time.Second
+
+	// PreemptionConfirmationTimeout is the maximum time waiting for the object causing a preemption to be persisted, after it
+	// has been admitted. Once elapsed, the object is assumed to have been rejected later on, and the preemption is aborted.
+	PreemptionConfirmationTimeout = time.Minute
+
+	// preemptionConfirmationPeriod is the period between two checks of whether the object causing a preemption has been persisted.
+	preemptionConfirmationPeriod = time.Second
+)
+
+// enqueuePreemptions enqueues the given preemptions, to be asynchronously carried out.
+func (spv *Validator) enqueuePreemptions(preemptions []preemption) {
+	for i := range preemptions {
+		spv.preemptions.Add(preemptions[i])
+	}
+}
+
+// PreemptionEvictor returns a function which carries out the preemption of the ShadowPods, until the context is canceled.
+// Once the object causing the preemption has been persisted, the preemption is notified to the consumer through the status
+// of the ShadowPod, and then the ShadowPod is deleted.
+func (spv *Validator) PreemptionEvictor() func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		go func() {
+			<-ctx.Done()
+			spv.preemptions.ShutDown()
+		}()
+
+		wait.UntilWithContext(ctx, func(ctx context.Context) {
+			for spv.processNextPreemption(ctx) {
+			}
+		}, time.Second)
+		return nil
+	}
+}
+
+func (spv *Validator) processNextPreemption(ctx context.Context) bool {
+	item, quit := spv.preemptions.Get()
+	if quit {
+		return false
+	}
+	defer spv.preemptions.Done(item)
+
+	p, ok := item.(preemption)
+	if !ok {
+		klog.Errorf("expected preemption in workqueue but got %#v", item)
+		spv.preemptions.Forget(item)
+		return true
+	}
+
+	requeueAfter, err := spv.handlePreemption(ctx, &p)
+	switch {
+	case err != nil:
+		klog.Errorf("Failed preempting ShadowPod %s (will retry): %v", p.namespacedName, err)
+		spv.preemptions.AddRateLimited(item)
+	case requeueAfter > 0:
+		spv.preemptions.Forget(item)
+		spv.preemptions.AddAfter(item, requeueAfter)
+	default:
+		spv.preemptions.Forget(item)
+	}
+	return true
+}
+
+// handlePreemption carries out a single step of the preemption of a ShadowPod, returning after how long the next step shall be performed
+// (or zero if the preemption is completed).
+func (spv *Validator) handlePreemption(ctx context.Context, p *preemption) (requeueAfter time.Duration, err error) {
+	var sp offloadingv1beta1.ShadowPod
+	if err := spv.client.Get(ctx, p.namespacedName, &sp); err != nil {
+		return 0, client.IgnoreNotFound(err)
+	}
+
+	if sp.GetUID() != p.uid || !sp.GetDeletionTimestamp().IsZero() {
+		// The ShadowPod has been already deleted (and possibly recreated).
+		return 0, nil
+	}
+
+	if sp.Status.Preemption == nil {
+		persisted, err := spv.isPreemptorPersisted(ctx, p)
+		if err != nil {
+			return 0, fmt.Errorf("failed to retrieve the preemptor: %w", err)
+		}
+
+		if !persisted {
+			if time.Since(p.admitted) < PreemptionConfirmationTimeout {
+				return preemptionConfirmationPeriod, nil
+			}
+			// The preemptor has been rejected after being admitted, hence the ShadowPod is no longer preempted.
+			spv.PeeringCache.restorePreemption(p)
+			klog.Infof("Preemption of ShadowPod %s aborted, as the preemptor %s has not been persisted", p.namespacedName, klog.KObj(p.preemptor))
+			return 0, nil
+		}
+
+		sp.Status.Preemption = &offloadingv1beta1.ShadowPodPreemption{Message: p.message}
+		if err := spv.client.Status().Update(ctx, &sp); err != nil {
+			return 0, fmt.Errorf("failed to update status: %w", err)
+		}
+		klog.Infof("ShadowPod %s marked as preempted: %s", p.namespacedName, p.message)
+		return PreemptionGracePeriod, nil
+	}
+
+	if err := spv.client.Delete(ctx, &sp, client.Preconditions{UID: &p.uid}); err != nil && !k8serrors.IsNotFound(err) {
+		return 0, fmt.Errorf("failed to delete: %w", err)
+	}
+	klog.Infof("ShadowPod %s deleted, as preempted", p.namespacedName)
+	return 0, nil
+}
+
+// isPreemptorPersisted returns whether the object causing the given preemption has been persisted.
+func (spv *Validator) isPreemptorPersisted(ctx context.Context, p *preemption) (bool, error) {
+	preemptor, ok := p.preemptor.DeepCopyObject().(client.Object)
+	if !ok {
+		return false, fmt.Errorf("unexpected preemptor type %T", p.preemptor)
+	}
+
+	uid := p.preemptor.GetUID()
+	if err := spv.client.Get(ctx, client.ObjectKeyFromObject(p.preemptor), preemptor); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+
+	// The UID is checked (when available) to ensure that the preemptor has not been replaced by a different object.
+	return uid == "" || preemptor.GetUID() == uid, nil
+}
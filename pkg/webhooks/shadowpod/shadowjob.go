@@ -0,0 +1,529 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowpod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/getters"
+	jobutils "github.com/liqotech/liqo/pkg/utils/job"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+// cluster-role
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=shadowjobs,verbs=get;list;watch
+
+// shadowJobKeyPrefix is the prefix of the name used to store the shadowjob descriptions in the peering cache,
+// to prevent conflicts with the ones of the shadowpods (as object names cannot contain slashes).
+const (
+	shadowJobKeyPrefix = "shadowjob/"
+	// statusSubResource is the name of the status subresource, whose updates signal the completion of the shadowjobs.
+	statusSubResource = "status"
+)
+
+var _ webhook.AdmissionHandler = &JobValidator{}
+
+// JobValidator is the handler used by the Validating Webhook to validate shadow jobs.
+// It shares the peering cache with the shadow pod validator, so that the same quota is enforced to both resources.
+type JobValidator struct {
+	*Validator
+}
+
+// NewJobValidator creates a new shadow job validator, sharing the peering cache with the given shadow pod validator.
+func NewJobValidator(spv *Validator) *JobValidator {
+	return &JobValidator{Validator: spv}
+}
+
+// Handle is the function in charge of handling the webhook validation request about the creation, update and deletion of shadowjobs.
+//
+//nolint:gocritic // the signature of this method is imposed by controller runtime.
+func (sjv *JobValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	klog.V(4).Infof("Operation: %s", req.Operation)
+
+	if !sjv.PeeringCache.ready && sjv.enableResourceValidation {
+		klog.Infof("Unable to process the request: Resource Validator cache not ready")
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("initialization in progress"))
+	}
+
+	switch req.Operation {
+	case admissionv1.Create:
+		return sjv.HandleCreate(ctx, &req)
+	case admissionv1.Delete:
+		return sjv.HandleDelete(&req)
+	case admissionv1.Update:
+		return sjv.HandleUpdate(ctx, &req)
+	default:
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("unsupported operation %s", req.Operation))
+	}
+}
+
+// HandleCreate is the function in charge of handling Creation requests.
+func (sjv *JobValidator) HandleCreate(ctx context.Context, req *admission.Request) admission.Response {
+	shadowjob, err := decodeShadowJob(sjv.decoder, req.Object)
+	if err != nil {
+		klog.Errorf("Failed decoding shadow job: %v", err)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed decoding of ShadowJob: %w", err))
+	}
+
+	// Check existence and get shadow job origin Cluster ID label
+	clusterID, found := shadowjob.Labels[forge.LiqoOriginClusterIDKey]
+	if !found {
+		klog.Warningf("Missing origin Cluster ID label on ShadowJob %q", shadowjob.Name)
+		return admission.Denied("missing origin Cluster ID label")
+	}
+
+	code, err := sjv.validateShadowPodClusterID(ctx, shadowjob.GetNamespace(), clusterID)
+	if err != nil {
+		if code == http.StatusBadRequest {
+			return admission.Errored(code, err)
+		}
+		return admission.Denied(err.Error())
+	}
+
+	if err := sjv.validateShadowPodRuntimeClass(ctx, shadowjob.GetNamespace(), &shadowjob.Spec.Job.Template.Spec); err != nil {
+		klog.Warningf("ShadowJob %q: %v", klog.KObj(shadowjob), err)
+		return admission.Denied(err.Error())
+	}
+
+	violated, err := checkShadowPodPolicies(ctx, sjv.client, liqov1beta1.ClusterID(clusterID), &shadowjob.Spec.Job.Template.Spec)
+	if err != nil {
+		klog.Errorf("Failed checking the policies of ShadowJob %q: %v", klog.KObj(shadowjob), err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(violated) > 0 {
+		return sjv.denyPolicyViolation("ShadowJob", shadowjob, clusterID, violated, ptr.Deref(req.DryRun, false))
+	}
+
+	if err := checkPlacement(ctx, sjv.client, liqov1beta1.ClusterID(clusterID), shadowjob.Labels[consts.CreatorLabelKey],
+		&shadowjob.Spec.Job.Template.Spec); err != nil {
+		klog.Warningf("ShadowJob %q: %v", klog.KObj(shadowjob), err)
+		return admission.Denied(err.Error())
+	}
+
+	if !sjv.enableResourceValidation {
+		return admission.Allowed("")
+	}
+
+	creatorName, found := shadowjob.Labels[consts.CreatorLabelKey]
+	if !found {
+		return admission.Denied("missing creator label")
+	}
+
+	quota, err := getters.GetQuotaByUser(ctx, sjv.client, creatorName)
+	if err != nil {
+		klog.Warningf("Failed getting quota for user %s: %v", creatorName, err)
+		return admission.Denied("failed getting quota")
+	}
+
+	if quota.Spec.Cordoned != nil && *quota.Spec.Cordoned {
+		klog.Warningf("User %q is cordoned", creatorName)
+		return admission.Denied("user is cordoned")
+	}
+
+	peeringInfo := sjv.PeeringCache.getOrCreatePeeringInfo(creatorName, quota.Spec.Resources)
+
+	// The up-to-date priority and borrowing limit are retrieved from the Quota, as the cache is only periodically refreshed.
+	peeringInfo.alignBorrowingPolicy(quota)
+
+	preemptions, err := sjv.PeeringCache.testAndUpdateJobCreation(ctx, sjv.client, peeringInfo, shadowjob,
+		quota.Spec.LimitsEnforcement, ptr.Deref(req.DryRun, false))
+	if err != nil {
+		klog.Warning(err)
+		return admission.Denied(err.Error())
+	}
+	sjv.enqueuePreemptions(preemptions)
+
+	return admission.Allowed("")
+}
+
+// HandleUpdate is the function in charge of handling Update requests.
+func (sjv *JobValidator) HandleUpdate(ctx context.Context, req *admission.Request) admission.Response {
+	shadowjob, err := decodeShadowJob(sjv.decoder, req.Object)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed decoding of ShadowJob: %w", err))
+	}
+
+	oldShadowjob, err := decodeShadowJob(sjv.decoder, req.OldObject)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed decoding of ShadowJob: %w", err))
+	}
+
+	if shadowjob.Labels[forge.LiqoOriginClusterIDKey] != oldShadowjob.Labels[forge.LiqoOriginClusterIDKey] {
+		klog.Warningf("The Cluster ID label of the updated shadowjob %q is different from the old one", shadowjob.Name)
+		return admission.Denied("shadowjob Cluster ID label is changed")
+	}
+
+	// The pod template of a job is immutable, hence modifications could not be propagated to the corresponding job.
+	if !equality.Semantic.DeepEqual(oldShadowjob.Spec.Job.Template, shadowjob.Spec.Job.Template) {
+		return admission.Denied("the pod template of a ShadowJob cannot be modified")
+	}
+
+	if !sjv.enableResourceValidation {
+		return admission.Allowed("")
+	}
+
+	// The parallelism of the shadowjob might have changed, hence we need to check whether the new resources fit in the quota.
+	creatorName, found := shadowjob.Labels[consts.CreatorLabelKey]
+	if !found {
+		return admission.Denied("missing creator label")
+	}
+
+	quota, err := getters.GetQuotaByUser(ctx, sjv.client, creatorName)
+	if err != nil {
+		klog.Warningf("Failed getting quota for user %s: %v", creatorName, err)
+		return admission.Denied("failed getting quota")
+	}
+
+	peeringInfo := sjv.PeeringCache.getOrCreatePeeringInfo(creatorName, quota.Spec.Resources)
+	if err := peeringInfo.testAndUpdateJobUpdate(shadowjob, quota.Spec.LimitsEnforcement, ptr.Deref(req.DryRun, false)); err != nil {
+		klog.Warning(err)
+		if req.SubResource == statusSubResource {
+			// Status updates are always allowed, as they do not increase the resources requested by the shadowjob,
+			// and the next refreshing process will align the cache.
+			return admission.Allowed(err.Error())
+		}
+		return admission.Denied(err.Error())
+	}
+
+	return admission.Allowed("")
+}
+
+// HandleDelete is the function in charge of handling Deletion requests.
+func (sjv *JobValidator) HandleDelete(req *admission.Request) admission.Response {
+	shadowjob, err := decodeShadowJob(sjv.decoder, req.OldObject)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed decoding of ShadowJob: %w", err))
+	}
+
+	creatorName, found := shadowjob.Labels[consts.CreatorLabelKey]
+	if !found {
+		klog.Warningf("Missing creator label on ShadowJob %q", shadowjob.Name)
+		return admission.Denied("missing creator label")
+	}
+
+	if !sjv.enableResourceValidation {
+		return admission.Allowed("")
+	}
+
+	peeringInfo, found := sjv.PeeringCache.getPeeringInfo(creatorName)
+	if !found {
+		// The deletion of shadowjobs is always allowed, and the next refreshing process will align the cache.
+		klog.Warningf("PeeringInfo not found in cache for user %q", creatorName)
+		return admission.Allowed(fmt.Sprintf("Peering not found in cache for user %q", creatorName))
+	}
+
+	if err := peeringInfo.updateJobDeletion(shadowjob, ptr.Deref(req.DryRun, false)); err != nil {
+		// The deletion is always allowed, and the next refreshing process will align the cache.
+		klog.Warning(err)
+		return admission.Allowed(err.Error())
+	}
+
+	return admission.Allowed("")
+}
+
+// decodeShadowJob decodes a shadow job from a given runtime object.
+func decodeShadowJob(decoder admission.Decoder, obj runtime.RawExtension) (shadowjob *offloadingv1beta1.ShadowJob, err error) {
+	shadowjob = &offloadingv1beta1.ShadowJob{}
+	err = decoder.DecodeRaw(obj, shadowjob)
+	return
+}
+
+var _ webhook.AdmissionHandler = &JobMutator{}
+
+// JobMutator is the handler used by the Mutating Webhook to mutate shadow jobs.
+type JobMutator struct {
+	client  client.Client
+	decoder admission.Decoder
+}
+
+// NewJobMutator creates a new shadow job mutator.
+func NewJobMutator(c client.Client) *webhook.Admission {
+	return &webhook.Admission{Handler: &JobMutator{
+		client:  c,
+		decoder: admission.NewDecoder(runtime.NewScheme()),
+	}}
+}
+
+// Handle is the function in charge of handling the webhook mutating request about the creation and update of shadowjobs,
+// which are labeled with the name of their creator.
+//
+//nolint:gocritic // the signature of this method is imposed by controller runtime.
+func (sjm *JobMutator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	klog.V(4).Infof("Operation: %s", req.Operation)
+
+	switch req.Operation {
+	case admissionv1.Create, admissionv1.Update:
+		return sjm.HandleCreateOrUpdate(ctx, &req)
+	case admissionv1.Delete:
+		return admission.Allowed("")
+	default:
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("unsupported operation %s", req.Operation))
+	}
+}
+
+// HandleCreateOrUpdate is the function in charge of handling Creation and Update requests.
+func (sjm *JobMutator) HandleCreateOrUpdate(ctx context.Context, req *admission.Request) admission.Response {
+	sj, err := decodeShadowJob(sjm.decoder, req.Object)
+	if err != nil {
+		klog.Errorf("Failed decoding shadow job: %v", err)
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed decoding of ShadowJob: %w", err))
+	}
+
+	creatorName, err := extractCreatorInfo(ctx, sjm.client, &req.UserInfo)
+	if err != nil {
+		klog.Warningf("Failed extracting creator info: %v", err)
+		return admission.Denied(err.Error())
+	}
+
+	if req.Operation == admissionv1.Update {
+		oldSj, err := decodeShadowJob(sjm.decoder, req.OldObject)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed decoding of ShadowJob: %w", err))
+		}
+
+		if oldCreatorName, ok := oldSj.Labels[consts.CreatorLabelKey]; !ok || oldCreatorName != creatorName {
+			return admission.Denied("creator name cannot be modified")
+		}
+	}
+
+	if sj.Labels == nil {
+		sj.Labels = map[string]string{}
+	}
+	sj.Labels[consts.CreatorLabelKey] = creatorName
+
+	// The pod template of a job is immutable, hence the runtime class is defaulted at creation time only.
+	if req.Operation == admissionv1.Create {
+		if err := defaultRuntimeClass(ctx, sjm.client, sj.GetNamespace(), &sj.Spec.Job.Template.Spec); err != nil {
+			klog.Errorf("Failed retrieving the runtime class of ShadowJob %q: %v", klog.KObj(sj), err)
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+	}
+
+	marshaledShadowJob, err := json.Marshal(sj)
+	if err != nil {
+		klog.Errorf("Failed marshaling ShadowJob object: %v", err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledShadowJob)
+}
+
+// shadowJobNamespacedName returns the key used to store the description of the given shadowjob in the peering cache.
+func shadowJobNamespacedName(sj *offloadingv1beta1.ShadowJob) types.NamespacedName {
+	return types.NamespacedName{Namespace: sj.GetNamespace(), Name: shadowJobKeyPrefix + sj.GetName()}
+}
+
+func createShadowJobDescription(sj *offloadingv1beta1.ShadowJob, resources corev1.ResourceList) *Description {
+	nsname := shadowJobNamespacedName(sj)
+	spd := createShadowPodDescription(nsname.Name, nsname.Namespace, sj.GetUID(), resources)
+	spd.job = true
+	return spd
+}
+
+func (pi *peeringInfo) testAndUpdateJobCreation(ctx context.Context, c client.Client,
+	sj *offloadingv1beta1.ShadowJob, limitsEnforcement offloadingv1beta1.LimitsEnforcement, dryRun bool) error {
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+
+	sjQuota, err := getQuotaFromShadowJob(sj, limitsEnforcement)
+	if err != nil {
+		return err
+	}
+	klog.V(5).Infof("ShadowJob %q resource limits %s", klog.KObj(sj), quotaFormatter(*sjQuota))
+
+	if spd, found := pi.shadowPods[shadowJobNamespacedName(sj).String()]; found {
+		if spd.running {
+			return fmt.Errorf("ShadowJob %s is already running", sj.GetName())
+		}
+		if err := checkShadowJobExistence(ctx, c, types.NamespacedName{Namespace: sj.GetNamespace(), Name: sj.GetName()}); err != nil {
+			return err
+		}
+		// Removing the old description from the cache, as not yet deleted by the cache refreshing.
+		pi.removeShadowPod(spd)
+	}
+
+	spd := createShadowJobDescription(sj, *sjQuota)
+	if err := pi.checkResources(spd); err != nil {
+		return err
+	}
+	if !dryRun {
+		pi.addShadowPod(spd)
+		klog.V(5).Infof("Cluster %q updated used quota %s", pi.userName, quotaFormatter(pi.usedQuota))
+	}
+	return nil
+}
+
+func (pi *peeringInfo) testAndUpdateJobUpdate(sj *offloadingv1beta1.ShadowJob,
+	limitsEnforcement offloadingv1beta1.LimitsEnforcement, dryRun bool) error {
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+
+	spd, err := pi.getShadowJobDescription(sj)
+	if err != nil {
+		return err
+	}
+
+	// Finished jobs no longer consume resources, hence they are released as soon as the status is updated accordingly.
+	if jobutils.IsFinished(&sj.Status.Job) {
+		if !dryRun && spd.running {
+			pi.terminateShadowPod(spd)
+			klog.V(5).Infof("Cluster %q updated used quota %s", pi.userName, quotaFormatter(pi.usedQuota))
+		}
+		return nil
+	}
+
+	if !spd.running {
+		return nil
+	}
+
+	sjQuota, err := getQuotaFromShadowJob(sj, limitsEnforcement)
+	if err != nil {
+		return err
+	}
+
+	if quotav1.Equals(spd.quota, *sjQuota) {
+		return nil
+	}
+
+	klog.V(5).Infof("ShadowJob %q resource limits %s (previous %s)", klog.KObj(sj), quotaFormatter(*sjQuota), quotaFormatter(spd.quota))
+	return pi.replaceQuota(spd, *sjQuota, dryRun)
+}
+
+func (pi *peeringInfo) updateJobDeletion(sj *offloadingv1beta1.ShadowJob, dryRun bool) error {
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+
+	spd, err := pi.getShadowJobDescription(sj)
+	if err != nil {
+		return err
+	}
+
+	if !dryRun && spd.running {
+		pi.terminateShadowPod(spd)
+		klog.V(5).Infof("Cluster %q updated used quota %s", pi.userName, quotaFormatter(pi.usedQuota))
+	}
+	return nil
+}
+
+func (pi *peeringInfo) getShadowJobDescription(sj *offloadingv1beta1.ShadowJob) (*Description, error) {
+	spd, found := pi.shadowPods[shadowJobNamespacedName(sj).String()]
+	if !found {
+		return nil, fmt.Errorf("ShadowJob %s not found (Maybe Cache problem)", sj.GetName())
+	} else if spd.uid != sj.GetUID() {
+		return nil, fmt.Errorf("ShadowJob %s: UID mismatch", sj.GetName())
+	}
+	return spd, nil
+}
+
+func (pi *peeringInfo) alignExistingShadowJobs(shadowJobList *offloadingv1beta1.ShadowJobList) {
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+	for i := range shadowJobList.Items {
+		if !pi.checkAndAddShadowJob(&shadowJobList.Items[i]) {
+			klog.V(4).Infof("ShadowJob %q added in cache", klog.KObj(&shadowJobList.Items[i]))
+		}
+	}
+}
+
+func (pi *peeringInfo) alignTerminatingOrNotExistingShadowJobs(shadowJobList *offloadingv1beta1.ShadowJobList) {
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+	sjMap := make(map[string]struct{})
+	for i := range shadowJobList.Items {
+		// Finished shadowjobs are considered as not existing, and the corresponding resources are immediately released.
+		if jobutils.IsFinished(&shadowJobList.Items[i].Status.Job) {
+			if spd, err := pi.getShadowJobDescription(&shadowJobList.Items[i]); err == nil && spd.running {
+				pi.terminateShadowPod(spd)
+			}
+			continue
+		}
+		if !pi.checkAndAddShadowJob(&shadowJobList.Items[i]) {
+			klog.Warningf("Warning: ShadowJob %q not found in cache, added", klog.KObj(&shadowJobList.Items[i]))
+		}
+		sjMap[shadowJobNamespacedName(&shadowJobList.Items[i]).String()] = struct{}{}
+	}
+	pi.alignTerminatingShadowPodDescriptions(sjMap, true)
+}
+
+func (pi *peeringInfo) checkAndAddShadowJob(sj *offloadingv1beta1.ShadowJob) (found bool) {
+	_, found = pi.shadowPods[shadowJobNamespacedName(sj).String()]
+	if !found && !jobutils.IsFinished(&sj.Status.Job) {
+		// Errors are intentionally ignored here.
+		sjQuota, _ := getQuotaFromShadowJob(sj, offloadingv1beta1.NoLimitsEnforcement)
+		pi.addShadowPod(createShadowJobDescription(sj, *sjQuota))
+	}
+	return
+}
+
+func checkShadowJobExistence(ctx context.Context, c client.Client, namespacedName types.NamespacedName) error {
+	sj := &offloadingv1beta1.ShadowJob{}
+	err := c.Get(ctx, namespacedName, sj)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	return fmt.Errorf("ShadowJob still exists in the system")
+}
+
+// getQuotaFromShadowJob returns the resources consumed by the given shadowjob, that is the ones of
+// each pod multiplied by the maximum number of pods which can be running at the same time.
+func getQuotaFromShadowJob(sj *offloadingv1beta1.ShadowJob,
+	limitsEnforcement offloadingv1beta1.LimitsEnforcement) (*corev1.ResourceList, error) {
+	podQuota, err := getQuotaFromShadowPod(&offloadingv1beta1.ShadowPod{
+		ObjectMeta: sj.ObjectMeta,
+		Spec:       offloadingv1beta1.ShadowPodSpec{Pod: sj.Spec.Job.Template.Spec},
+	}, limitsEnforcement)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := jobMaxActivePods(&sj.Spec.Job)
+	result := corev1.ResourceList{}
+	for key, value := range *podQuota {
+		value.Mul(pods)
+		result[key] = value
+	}
+	return &result, nil
+}
+
+// jobMaxActivePods returns the maximum number of pods of the given job that can be running at the same time.
+func jobMaxActivePods(spec *batchv1.JobSpec) int64 {
+	pods := int64(1)
+	if spec.Parallelism != nil {
+		pods = int64(*spec.Parallelism)
+	}
+	if spec.Completions != nil && int64(*spec.Completions) < pods {
+		pods = int64(*spec.Completions)
+	}
+	return pods
+}
@@ -0,0 +1,233 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowpod
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/testutil"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+func forgeShadowJob(name, uid string, parallelism, completions *int32) *offloadingv1beta1.ShadowJob {
+	sp := forgeShadowPod(name, testNamespace, uid, userName)
+	return &offloadingv1beta1.ShadowJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			UID:       types.UID(uid),
+			Labels:    map[string]string{consts.CreatorLabelKey: userName},
+		},
+		Spec: offloadingv1beta1.ShadowJobSpec{
+			Job: batchv1.JobSpec{
+				Parallelism: parallelism,
+				Completions: completions,
+				Template:    corev1.PodTemplateSpec{Spec: sp.Spec.Pod},
+			},
+		},
+	}
+}
+
+func forgeJobRequest(op admissionv1.Operation, sj *offloadingv1beta1.ShadowJob) admission.Request {
+	data, err := json.Marshal(sj)
+	Expect(err).ToNot(HaveOccurred())
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: op, Name: sj.Name}}
+	req.Object = runtime.RawExtension{Raw: data}
+	req.DryRun = ptr.To(false)
+	return req
+}
+
+var _ = Describe("ShadowJob webhooks", func() {
+	var (
+		fakeClient client.Client
+		shadowjob  *offloadingv1beta1.ShadowJob
+	)
+
+	BeforeEach(func() {
+		namespace := testutil.FakeNamespaceWithClusterID(clusterID, testNamespace)
+		namespace.Annotations = map[string]string{consts.RemoteNamespaceRuntimeClassAnnotationKey: "gvisor"}
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespace).Build()
+
+		shadowjob = forgeShadowJob("job", "uid", nil, nil)
+		shadowjob.Labels[forge.LiqoOriginClusterIDKey] = string(clusterID)
+	})
+
+	When("the shadowjob namespace enforces a runtime class", func() {
+		It("should deny the shadowjobs not specifying the enforced runtime class", func() {
+			response := NewJobValidator(NewValidator(fakeClient, false)).Handle(ctx, forgeJobRequest(admissionv1.Create, shadowjob))
+			Expect(response.Allowed).To(BeFalse())
+		})
+
+		It("should admit the shadowjobs specifying the enforced runtime class", func() {
+			shadowjob.Spec.Job.Template.Spec.RuntimeClassName = ptr.To("gvisor")
+			response := NewJobValidator(NewValidator(fakeClient, false)).Handle(ctx, forgeJobRequest(admissionv1.Create, shadowjob))
+			Expect(response.Allowed).To(BeTrue())
+		})
+
+		It("should default the enforced runtime class of the shadowjobs being created", func() {
+			req := forgeJobRequest(admissionv1.Create, shadowjob)
+			req.UserInfo.Username = userName
+			response := NewJobMutator(fakeClient).Handle(ctx, req)
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Patches).To(ContainElement(HaveField("Path", "/spec/job/template/spec/runtimeClassName")))
+		})
+	})
+
+	When("the shadowjob violates a ShadowPodPolicy", func() {
+		BeforeEach(func() {
+			Expect(fakeClient.Create(ctx, &offloadingv1beta1.ShadowPodPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+				Spec: offloadingv1beta1.ShadowPodPolicySpec{ShadowPodPolicyRules: offloadingv1beta1.ShadowPodPolicyRules{
+					AllowedImageRegistries: []string{"registry.example.com"},
+				}},
+			})).To(Succeed())
+			shadowjob.Spec.Job.Template.Spec.RuntimeClassName = ptr.To("gvisor")
+			shadowjob.Spec.Job.Template.Spec.Containers = []corev1.Container{{Name: "app", Image: "nginx"}}
+		})
+
+		It("should deny the request with the policy violation reason", func() {
+			response := NewJobValidator(NewValidator(fakeClient, false)).Handle(ctx, forgeJobRequest(admissionv1.Create, shadowjob))
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Reason).To(Equal(offloadingv1beta1.ShadowPodPolicyViolationReason))
+			Expect(response.Result.Message).To(ContainSubstring(`ShadowPodPolicy "policy" violated`))
+		})
+	})
+})
+
+var _ = Describe("ShadowJob quota", func() {
+	var (
+		fakeClient client.Client
+		pi         *peeringInfo
+	)
+
+	freeCPU := func() int64 {
+		free := pi.getFreeQuota()[corev1.ResourceCPU]
+		return free.Value()
+	}
+
+	BeforeEach(func() {
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).Build()
+		pi = createPeeringInfo(userName, *resourceQuota)
+	})
+
+	DescribeTable("getQuotaFromShadowJob",
+		func(parallelism, completions *int32, expected *corev1.ResourceList) {
+			quota, err := getQuotaFromShadowJob(forgeShadowJob(testShadowPodName, string(testShadowPodUID), parallelism, completions),
+				offloadingv1beta1.SoftLimitsEnforcement)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(quota.Cpu().Cmp(*expected.Cpu())).To(BeZero())
+			Expect(quota.Memory().Cmp(*expected.Memory())).To(BeZero())
+		},
+		Entry("with default parallelism", nil, nil, resourceQuota4),
+		Entry("with parallelism greater than one", ptr.To[int32](2), nil, resourceQuota2),
+		Entry("with completions lower than parallelism", ptr.To[int32](4), ptr.To[int32](2), resourceQuota2),
+		Entry("with zero parallelism", ptr.To[int32](0), nil, forgeResourceList(0, 0)),
+	)
+
+	When("creating a shadowjob", func() {
+		It("should account the resources of all the pods running in parallel", func() {
+			Expect(pi.testAndUpdateJobCreation(ctx, fakeClient,
+				forgeShadowJob(testShadowPodName, string(testShadowPodUID), ptr.To[int32](3), nil),
+				offloadingv1beta1.SoftLimitsEnforcement, false)).To(Succeed())
+			Expect(pi.usedQuota.Cpu().Value()).To(BeNumerically("==", 3*resourceCPU/4))
+
+			By("rejecting further shadowjobs exceeding the quota")
+			Expect(pi.testAndUpdateJobCreation(ctx, fakeClient,
+				forgeShadowJob(testShadowPodName2, string(testShadowPodUID2), ptr.To[int32](2), nil),
+				offloadingv1beta1.SoftLimitsEnforcement, false)).ToNot(Succeed())
+
+			By("sharing the quota with the shadowpods")
+			Expect(pi.testAndUpdateCreation(ctx, fakeClient, fakeShadowPod, offloadingv1beta1.SoftLimitsEnforcement, false)).To(Succeed())
+			Expect(freeCPU()).To(BeZero())
+		})
+
+		It("should not account the resources of dry-run requests", func() {
+			Expect(pi.testAndUpdateJobCreation(ctx, fakeClient,
+				forgeShadowJob(testShadowPodName, string(testShadowPodUID), nil, nil),
+				offloadingv1beta1.SoftLimitsEnforcement, true)).To(Succeed())
+			Expect(pi.usedQuota.Cpu().IsZero()).To(BeTrue())
+		})
+	})
+
+	When("updating and deleting a shadowjob", func() {
+		var sj *offloadingv1beta1.ShadowJob
+
+		BeforeEach(func() {
+			sj = forgeShadowJob(testShadowPodName, string(testShadowPodUID), ptr.To[int32](2), nil)
+			Expect(pi.testAndUpdateJobCreation(ctx, fakeClient, sj, offloadingv1beta1.SoftLimitsEnforcement, false)).To(Succeed())
+		})
+
+		It("should update the accounted resources if the parallelism changes", func() {
+			sj.Spec.Job.Parallelism = ptr.To[int32](4)
+			Expect(pi.testAndUpdateJobUpdate(sj, offloadingv1beta1.SoftLimitsEnforcement, false)).To(Succeed())
+			Expect(freeCPU()).To(BeZero())
+
+			By("rejecting an increase exceeding the quota")
+			sj.Spec.Job.Parallelism = ptr.To[int32](5)
+			Expect(pi.testAndUpdateJobUpdate(sj, offloadingv1beta1.SoftLimitsEnforcement, false)).ToNot(Succeed())
+			Expect(freeCPU()).To(BeZero())
+		})
+
+		It("should release the resources when deleted", func() {
+			Expect(pi.updateJobDeletion(sj, false)).To(Succeed())
+			Expect(pi.usedQuota.Cpu().IsZero()).To(BeTrue())
+		})
+
+		It("should release the resources as soon as the status turns finished", func() {
+			sj.Status.Job.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+
+			By("ignoring dry-run requests")
+			Expect(pi.testAndUpdateJobUpdate(sj, offloadingv1beta1.SoftLimitsEnforcement, true)).To(Succeed())
+			Expect(pi.usedQuota.Cpu().IsZero()).To(BeFalse())
+
+			Expect(pi.testAndUpdateJobUpdate(sj, offloadingv1beta1.SoftLimitsEnforcement, false)).To(Succeed())
+			Expect(pi.usedQuota.Cpu().IsZero()).To(BeTrue())
+
+			By("removing the description upon the cache refreshing")
+			pi.alignTerminatingOrNotExistingShadowJobs(&offloadingv1beta1.ShadowJobList{Items: []offloadingv1beta1.ShadowJob{*sj}})
+			Expect(pi.usedQuota.Cpu().IsZero()).To(BeTrue())
+			Expect(pi.shadowPods).To(BeEmpty())
+		})
+
+		It("should release the resources when finished", func() {
+			sj.Status.Job.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+			pi.alignTerminatingOrNotExistingShadowJobs(&offloadingv1beta1.ShadowJobList{Items: []offloadingv1beta1.ShadowJob{*sj}})
+			Expect(pi.usedQuota.Cpu().IsZero()).To(BeTrue())
+			Expect(pi.shadowPods).To(BeEmpty())
+		})
+
+		It("should not release the resources of running shadowjobs, nor affect the shadowpods", func() {
+			Expect(pi.testAndUpdateCreation(ctx, fakeClient, fakeShadowPod, offloadingv1beta1.SoftLimitsEnforcement, false)).To(Succeed())
+			pi.alignTerminatingOrNotExistingShadowJobs(&offloadingv1beta1.ShadowJobList{Items: []offloadingv1beta1.ShadowJob{*sj}})
+			Expect(pi.usedQuota.Cpu().Value()).To(BeNumerically("==", 3*resourceCPU/4))
+			Expect(pi.shadowPods).To(HaveLen(2))
+		})
+	})
+})
@@ -30,6 +30,7 @@ import (
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
 	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
 	"github.com/liqotech/liqo/pkg/consts"
@@ -90,6 +91,7 @@ var _ = BeforeSuite(func() {
 	scheme = runtime.NewScheme()
 	testutil.LogsToGinkgoWriter()
 	Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	Expect(authv1beta1.AddToScheme(scheme)).To(Succeed())
 	Expect(liqov1beta1.AddToScheme(scheme)).To(Succeed())
 	Expect(offloadingv1beta1.AddToScheme(scheme)).To(Succeed())
 })
@@ -133,6 +135,13 @@ func forgeResourceList(cpu, memory int64, gpu ...int64) *corev1.ResourceList {
 	return &resourceList
 }
 
+// withPods returns a copy of the given resource list, including also the given number of pods.
+func withPods(resourceList *corev1.ResourceList, pods int64) corev1.ResourceList {
+	result := resourceList.DeepCopy()
+	result[corev1.ResourcePods] = *resource.NewQuantity(pods, resource.DecimalSI)
+	return result
+}
+
 func forgeShadowPodWithClusterID(clusterID liqov1beta1.ClusterID, userName, namespace string) *offloadingv1beta1.ShadowPod {
 	return &offloadingv1beta1.ShadowPod{
 		ObjectMeta: metav1.ObjectMeta{
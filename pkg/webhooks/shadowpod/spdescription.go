@@ -20,6 +20,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
 	klog "k8s.io/klog/v2"
@@ -35,6 +36,8 @@ type Description struct {
 	quota             corev1.ResourceList
 	running           bool
 	creationTimestamp time.Time
+	// job is true if the description refers to a shadowjob, rather than to a shadowpod.
+	job bool
 }
 
 func createShadowPodDescription(name, namespace string, uid types.UID, resources corev1.ResourceList) *Description {
@@ -100,94 +103,79 @@ func checkShadowPodExistence(ctx context.Context, spvclient client.Client, names
 
 func getQuotaFromShadowPod(shadowpod *offloadingv1beta1.ShadowPod,
 	limitsEnforcement offloadingv1beta1.LimitsEnforcement) (*corev1.ResourceList, error) {
-	conResources := corev1.ResourceList{}
-	initConResources := corev1.ResourceList{}
-
 	// At least one container is required
 	if shadowpod.Spec.Pod.Containers == nil {
 		return nil, fmt.Errorf("ShadowPod %s has no containers defined", shadowpod.GetName())
 	}
 
 	// Calculating the sum of the resources of all containers
-	for i := range shadowpod.Spec.Pod.Containers {
-		// This flags are used to check if each container in range has CPU and Memory requests defined
-		cpuFlag := false
-		memoryFlag := false
-		for key, value := range shadowpod.Spec.Pod.Containers[i].Resources.Requests {
-			if key == corev1.ResourceCPU {
-				cpuFlag = true
-			}
-			if key == corev1.ResourceMemory {
-				memoryFlag = true
-			}
-			if prevResources, ok := conResources[key]; ok {
-				prevResources.Add(value)
-				conResources[key] = prevResources
-			} else {
-				conResources[key] = value.DeepCopy()
-			}
-
-			if limitsEnforcement == offloadingv1beta1.HardLimitsEnforcement {
-				req := shadowpod.Spec.Pod.Containers[i].Resources.Requests[key]
-				lim := shadowpod.Spec.Pod.Containers[i].Resources.Limits[key]
-				if req.Cmp(lim) != 0 {
-					return nil, fmt.Errorf("%s limits and requests are not equal for container %s",
-						key, shadowpod.Spec.Pod.Containers[i].Name)
-				}
-			}
-		}
-		// If the container has no CPU or Memory requests defined and this kind of validation is required, an error is returned
-		if limitsEnforcement == offloadingv1beta1.NoLimitsEnforcement {
-			continue
-		}
-		if !cpuFlag || !memoryFlag {
-			return nil, fmt.Errorf("CPU and/or memory requests not set for container %s", shadowpod.Spec.Pod.Containers[i].Name)
-		}
+	conResources, err := getQuotaFromContainers(shadowpod.Spec.Pod.Containers, "container", limitsEnforcement, quotav1.Add)
+	if err != nil {
+		return nil, err
 	}
 
 	// Calculating the max of each resource type between the init containers
-	for i := range shadowpod.Spec.Pod.InitContainers {
-		// This flags are used to check if each container in range has CPU and Memory requests defined
-		cpuFlag := false
-		memoryFlag := false
-		for key, value := range shadowpod.Spec.Pod.InitContainers[i].Resources.Requests {
-			if key == corev1.ResourceCPU {
-				cpuFlag = true
-			}
-			if key == corev1.ResourceMemory {
-				memoryFlag = true
-			}
-			if prevResources, ok := initConResources[key]; ok {
-				if prevResources.Value() < value.Value() {
-					initConResources[key] = value.DeepCopy()
-				}
-			} else {
-				initConResources[key] = value.DeepCopy()
-			}
+	initConResources, err := getQuotaFromContainers(shadowpod.Spec.Pod.InitContainers, "initContainer", limitsEnforcement, quotav1.Max)
+	if err != nil {
+		return nil, err
+	}
+
+	result := quotav1.Max(conResources, initConResources)
 
-			if limitsEnforcement == offloadingv1beta1.HardLimitsEnforcement {
-				req := shadowpod.Spec.Pod.InitContainers[i].Resources.Requests[key]
-				lim := shadowpod.Spec.Pod.InitContainers[i].Resources.Limits[key]
+	// The overhead associated with the runtime class is accounted as well, consistently with the Kubernetes ResourceQuotas.
+	result = quotav1.Add(result, shadowpod.Spec.Pod.Overhead)
+	// Each ShadowPod accounts for one pod.
+	result[corev1.ResourcePods] = *resource.NewQuantity(1, resource.DecimalSI)
+	return &result, nil
+}
+
+// getQuotaFromContainers returns the resources requested by the given containers, aggregated through the given function.
+// Resources (e.g., extended ones) specifying only the limits are accounted as if the requests were equal to the limits,
+// consistently with the defaulting performed by the Kubernetes API server.
+func getQuotaFromContainers(containers []corev1.Container, kind string, limitsEnforcement offloadingv1beta1.LimitsEnforcement,
+	aggregate func(a, b corev1.ResourceList) corev1.ResourceList) (corev1.ResourceList, error) {
+	result := corev1.ResourceList{}
+	for i := range containers {
+		requests := getContainerRequests(&containers[i])
+
+		if limitsEnforcement == offloadingv1beta1.HardLimitsEnforcement {
+			for key, req := range requests {
+				lim := containers[i].Resources.Limits[key]
 				if req.Cmp(lim) != 0 {
-					return nil, fmt.Errorf("%s limits and requests are not equal for container %s",
-						key, shadowpod.Spec.Pod.InitContainers[i].Name)
+					return nil, fmt.Errorf("%s limits and requests are not equal for %s %s", key, kind, containers[i].Name)
 				}
 			}
 		}
-		// If the init container has no CPU or Memory requests defined and this kind of validation is required, an error is returned
-		if limitsEnforcement == offloadingv1beta1.NoLimitsEnforcement {
-			continue
+
+		// If the container has no CPU or Memory requests defined and this kind of validation is required, an error is returned
+		if limitsEnforcement != offloadingv1beta1.NoLimitsEnforcement {
+			_, cpuFlag := requests[corev1.ResourceCPU]
+			_, memoryFlag := requests[corev1.ResourceMemory]
+			if !cpuFlag || !memoryFlag {
+				return nil, fmt.Errorf("CPU and/or memory requests not set for %s %s", kind, containers[i].Name)
+			}
 		}
-		if !cpuFlag || !memoryFlag {
-			return nil, fmt.Errorf("CPU and/or memory requests not set for initContainer %s",
-				shadowpod.Spec.Pod.InitContainers[i].Name)
+
+		result = aggregate(result, requests)
+	}
+	return result, nil
+}
+
+// getContainerRequests returns the requests of the given container, defaulting the missing ones to the corresponding limits.
+func getContainerRequests(container *corev1.Container) corev1.ResourceList {
+	requests := container.Resources.Requests.DeepCopy()
+	if requests == nil {
+		requests = corev1.ResourceList{}
+	}
+	for key, lim := range container.Resources.Limits {
+		if _, found := requests[key]; !found {
+			requests[key] = lim.DeepCopy()
 		}
 	}
-	result := quotav1.Max(conResources, initConResources)
-	return &result, nil
+	return requests
 }
 
 func quotaFormatter(quota corev1.ResourceList) string {
-	return fmt.Sprintf("[ cpu: %v, memory %v, storage: %v, ephemeral-storage: %v ]",
-		quota.Cpu(), quota.Memory(), quota.Storage(), quota.StorageEphemeral())
+	return fmt.Sprintf("[ cpu: %v, memory %v, storage: %v, ephemeral-storage: %v, pods: %v ]",
+		quota.Cpu(), quota.Memory(), quota.Storage(), quota.StorageEphemeral(), quota.Pods())
 }
@@ -213,10 +213,32 @@ var _ = Describe("ShadowPod Description", func() {
 				errTest = fmt.Errorf("ShadowPod %s has no containers defined", shadowPod.GetName())
 			})
 			It("should return a ResourceList which is the Max between the sum of all containers resources and the Max of initContainers", func() {
-				Expect(quota).To(Equal(forgeResourceList(300, 300)))
+				Expect(*quota).To(Equal(withPods(forgeResourceList(300, 300), 1)))
 				Expect(err).To(BeNil())
 			})
 		})
+		When("The ShadowPod specifies the overhead associated with the runtime class", func() {
+			BeforeEach(func() {
+				containers = []containerResource{{cpu: 100, memory: 100}}
+				shadowPod = forgeShadowPodWithResourceRequests(containers, nil)
+				shadowPod.Spec.Pod.Overhead = *forgeResourceList(50, 50)
+			})
+			It("should return a ResourceList including also the overhead", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(*quota).To(Equal(withPods(forgeResourceList(150, 150), 1)))
+			})
+		})
+		When("The ShadowPod has some resources with only the limits specified", func() {
+			BeforeEach(func() {
+				containers = []containerResource{{cpu: 100, memory: 100}}
+				shadowPod = forgeShadowPodWithResourceRequests(containers, nil)
+				shadowPod.Spec.Pod.Containers[0].Resources.Limits = *forgeResourceList(0, 0, 2)
+			})
+			It("should account the limits as requests", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(*quota).To(Equal(withPods(forgeResourceList(100, 100, 2), 1)))
+			})
+		})
 	})
 
 })
@@ -19,18 +19,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
 	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
 	"github.com/liqotech/liqo/pkg/utils/getters"
 	pod "github.com/liqotech/liqo/pkg/utils/pod"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
@@ -40,6 +46,8 @@ import (
 // +kubebuilder:rbac:groups=offloading.liqo.io,resources=shadowpods,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups=offloading.liqo.io,resources=quotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=authentication.liqo.io,resources=resourceslices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch
 
 // Validator is the handler used by the Validating Webhook to validate shadow pods.
 type Validator struct {
@@ -47,6 +55,8 @@ type Validator struct {
 	PeeringCache             *peeringCache
 	decoder                  admission.Decoder
 	enableResourceValidation bool
+	// preemptions is the queue of the ShadowPods preempted to reclaim borrowed resources, and to be evicted.
+	preemptions workqueue.RateLimitingInterface
 }
 
 // NewValidator creates a new shadow pod validator.
@@ -56,6 +66,7 @@ func NewValidator(c client.Client, enableResourceValidation bool) *Validator {
 		PeeringCache:             &peeringCache{ready: false},
 		enableResourceValidation: enableResourceValidation,
 		decoder:                  admission.NewDecoder(runtime.NewScheme()),
+		preemptions:              workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "shadowpod-preemptions"),
 	}
 }
 
@@ -107,6 +118,26 @@ func (spv *Validator) HandleCreate(ctx context.Context, req *admission.Request)
 		return admission.Denied(err.Error())
 	}
 
+	if err := spv.validateShadowPodRuntimeClass(ctx, shadowpod.GetNamespace(), &shadowpod.Spec.Pod); err != nil {
+		klog.Warningf("ShadowPod %q: %v", klog.KObj(shadowpod), err)
+		return admission.Denied(err.Error())
+	}
+
+	violated, err := checkShadowPodPolicies(ctx, spv.client, liqov1beta1.ClusterID(clusterID), &shadowpod.Spec.Pod)
+	if err != nil {
+		klog.Errorf("Failed checking the policies of ShadowPod %q: %v", klog.KObj(shadowpod), err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(violated) > 0 {
+		return spv.denyPolicyViolation("ShadowPod", shadowpod, clusterID, violated, ptr.Deref(req.DryRun, false))
+	}
+
+	if err := checkPlacement(ctx, spv.client, liqov1beta1.ClusterID(clusterID), shadowpod.Labels[consts.CreatorLabelKey],
+		&shadowpod.Spec.Pod); err != nil {
+		klog.Warningf("ShadowPod %q: %v", klog.KObj(shadowpod), err)
+		return admission.Denied(err.Error())
+	}
+
 	if !spv.enableResourceValidation {
 		return admission.Allowed("")
 	}
@@ -135,11 +166,15 @@ func (spv *Validator) HandleCreate(ctx context.Context, req *admission.Request)
 
 	peeringInfo := spv.PeeringCache.getOrCreatePeeringInfo(creatorName, quota.Spec.Resources)
 
-	err = peeringInfo.testAndUpdateCreation(ctx, spv.client, shadowpod, quota.Spec.LimitsEnforcement, *req.DryRun)
+	// The up-to-date priority and borrowing limit are retrieved from the Quota, as the cache is only periodically refreshed.
+	peeringInfo.alignBorrowingPolicy(quota)
+
+	preemptions, err := spv.PeeringCache.testAndUpdateCreation(ctx, spv.client, peeringInfo, shadowpod, quota.Spec.LimitsEnforcement, *req.DryRun)
 	if err != nil {
 		klog.Warning(err)
 		return admission.Denied(err.Error())
 	}
+	spv.enqueuePreemptions(preemptions)
 
 	return admission.Allowed("")
 }
@@ -175,11 +210,52 @@ func (spv *Validator) HandleUpdate(ctx context.Context, req *admission.Request)
 		return admission.Denied("shadopow Cluster ID label is changed")
 	}
 
-	if pod.CheckShadowPodUpdate(&shadowpod.Spec.Pod, &oldShadowpod.Spec.Pod) {
+	if !pod.CheckShadowPodUpdate(&oldShadowpod.Spec.Pod, shadowpod.Spec.Pod.DeepCopy()) {
+		return admission.Denied("")
+	}
+
+	// The policies are evaluated only if the pod is modified, so that the updates of the metadata (e.g., the removal of
+	// the finalizers) are not denied because of the policies created after the ShadowPod.
+	if !equality.Semantic.DeepEqual(&oldShadowpod.Spec.Pod, &shadowpod.Spec.Pod) {
+		violated, err := checkShadowPodPolicies(ctx, spv.client, liqov1beta1.ClusterID(clusterID), &shadowpod.Spec.Pod)
+		if err != nil {
+			klog.Errorf("Failed checking the policies of ShadowPod %q: %v", klog.KObj(shadowpod), err)
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if len(violated) > 0 {
+			return spv.denyPolicyViolation("ShadowPod", shadowpod, clusterID, violated, ptr.Deref(req.DryRun, false))
+		}
+
+		if err := checkPlacement(ctx, spv.client, liqov1beta1.ClusterID(clusterID), shadowpod.Labels[consts.CreatorLabelKey],
+			&shadowpod.Spec.Pod); err != nil {
+			klog.Warningf("ShadowPod %q: %v", klog.KObj(shadowpod), err)
+			return admission.Denied(err.Error())
+		}
+	}
+
+	if !spv.enableResourceValidation || pod.AreContainersResourcesEqual(oldShadowpod.Spec.Pod.Containers, shadowpod.Spec.Pod.Containers) {
 		return admission.Allowed("")
 	}
 
-	return admission.Denied("")
+	// The shadowpod is being resized in-place, hence we need to check whether the new resources fit in the quota.
+	creatorName, found := shadowpod.Labels[consts.CreatorLabelKey]
+	if !found {
+		return admission.Denied("missing creator label")
+	}
+
+	quota, err := getters.GetQuotaByUser(ctx, spv.client, creatorName)
+	if err != nil {
+		klog.Warningf("Failed getting quota for user %s: %v", creatorName, err)
+		return admission.Denied("failed getting quota")
+	}
+
+	peeringInfo := spv.PeeringCache.getOrCreatePeeringInfo(creatorName, quota.Spec.Resources)
+	if err := peeringInfo.testAndUpdateResize(shadowpod, quota.Spec.LimitsEnforcement, *req.DryRun); err != nil {
+		klog.Warning(err)
+		return admission.Denied(err.Error())
+	}
+
+	return admission.Allowed("")
 }
 
 // HandleDelete is the function in charge of handling Deletion requests.
@@ -238,6 +314,61 @@ func (spv *Validator) validateShadowPodClusterID(ctx context.Context, ns, spClus
 	return http.StatusOK, nil
 }
 
+// denyPolicyViolation denies the request for a ShadowPod (or ShadowJob) violating the given ShadowPodPolicies. The dedicated reason
+// allows the consumer cluster to distinguish the rejection from transient errors, and report it in the status of the local pod.
+func (spv *Validator) denyPolicyViolation(kind string, obj client.Object, clusterID string,
+	violated []policyViolation, dryRun bool) admission.Response {
+	messages := make([]string, len(violated))
+	for i := range violated {
+		messages[i] = violated[i].String()
+		if !dryRun {
+			policyViolationsCounter.WithLabelValues(violated[i].policy, clusterID).Inc()
+		}
+	}
+
+	message := strings.Join(messages, ", ")
+	klog.Warningf("%s %q of cluster %q rejected: %s", kind, klog.KObj(obj), clusterID, message)
+
+	response := admission.Denied(message)
+	response.Result.Reason = offloadingv1beta1.ShadowPodPolicyViolationReason
+	return response
+}
+
+// validateShadowPodRuntimeClass checks whether the given pod complies with the runtime class enforced in its namespace, if any.
+func (spv *Validator) validateShadowPodRuntimeClass(ctx context.Context, ns string, podSpec *corev1.PodSpec) error {
+	runtimeClass, err := getEnforcedRuntimeClass(ctx, spv.client, ns)
+	if err != nil {
+		return err
+	}
+
+	if runtimeClass != "" && ptr.Deref(podSpec.RuntimeClassName, "") != runtimeClass {
+		return fmt.Errorf("runtime class %q is enforced in namespace %q", runtimeClass, ns)
+	}
+	return nil
+}
+
+// defaultRuntimeClass sets the runtime class enforced in the given namespace, if any, to the given pod, if not explicitly set
+// (any mismatch is denied by the validator).
+func defaultRuntimeClass(ctx context.Context, cl client.Client, ns string, podSpec *corev1.PodSpec) error {
+	runtimeClass, err := getEnforcedRuntimeClass(ctx, cl, ns)
+	if err != nil {
+		return err
+	}
+	if runtimeClass != "" && podSpec.RuntimeClassName == nil {
+		podSpec.RuntimeClassName = ptr.To(runtimeClass)
+	}
+	return nil
+}
+
+// getEnforcedRuntimeClass returns the runtime class enforced in the given namespace, or an empty string if none.
+func getEnforcedRuntimeClass(ctx context.Context, cl client.Client, ns string) (string, error) {
+	namespace := &corev1.Namespace{}
+	if err := cl.Get(ctx, client.ObjectKey{Name: ns}, namespace); err != nil {
+		return "", fmt.Errorf("failed retrieving namespace %q: %w", ns, err)
+	}
+	return namespace.Annotations[consts.RemoteNamespaceRuntimeClassAnnotationKey], nil
+}
+
 // decodeShadowPod decodes a shadow pod from a given runtime object.
 func decodeShadowPod(decoder admission.Decoder, obj runtime.RawExtension) (shadowpod *offloadingv1beta1.ShadowPod, err error) {
 	shadowpod = &offloadingv1beta1.ShadowPod{}
@@ -264,30 +395,30 @@ func NewMutator(c client.Client) *webhook.Admission {
 // Handle is the function in charge of handling the webhook mutating request about the creation, update and deletion of shadowpods.
 //
 //nolint:gocritic // the signature of this method is imposed by controller runtime.
-func (spm *Mutator) Handle(_ context.Context, req admission.Request) admission.Response {
+func (spm *Mutator) Handle(ctx context.Context, req admission.Request) admission.Response {
 	klog.V(4).Infof("Operation: %s", req.Operation)
 
 	switch req.Operation {
 	case admissionv1.Create:
-		return spm.HandleCreate(&req)
+		return spm.HandleCreate(ctx, &req)
 	case admissionv1.Delete:
 		return spm.HandleDelete()
 	case admissionv1.Update:
-		return spm.HandleUpdate(&req)
+		return spm.HandleUpdate(ctx, &req)
 	default:
 		return admission.Errored(http.StatusBadRequest, fmt.Errorf("unsupported operation %s", req.Operation))
 	}
 }
 
 // HandleCreate is the function in charge of handling Creation requests.
-func (spm *Mutator) HandleCreate(req *admission.Request) admission.Response {
+func (spm *Mutator) HandleCreate(ctx context.Context, req *admission.Request) admission.Response {
 	sp, err := decodeShadowPod(spm.decoder, req.Object)
 	if err != nil {
 		klog.Errorf("Failed decoding shadow pod: %v", err)
 		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed decoding of ShadowPod: %w", err))
 	}
 
-	creatorName, err := extractCreatorInfo(&req.UserInfo)
+	creatorName, err := extractCreatorInfo(ctx, spm.client, &req.UserInfo)
 	if err != nil {
 		klog.Warningf("Failed extracting creator info: %v", err)
 		return admission.Denied(err.Error())
@@ -298,6 +429,11 @@ func (spm *Mutator) HandleCreate(req *admission.Request) admission.Response {
 	}
 	sp.Labels[consts.CreatorLabelKey] = creatorName
 
+	if err := defaultRuntimeClass(ctx, spm.client, sp.GetNamespace(), &sp.Spec.Pod); err != nil {
+		klog.Errorf("Failed retrieving the runtime class of ShadowPod %q: %v", klog.KObj(sp), err)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
 	marshaledShadowPod, err := json.Marshal(sp)
 	if err != nil {
 		klog.Errorf("Failed marshaling ShadowPod object: %v", err)
@@ -308,7 +444,7 @@ func (spm *Mutator) HandleCreate(req *admission.Request) admission.Response {
 }
 
 // HandleUpdate is the function in charge of handling Update requests.
-func (spm *Mutator) HandleUpdate(req *admission.Request) admission.Response {
+func (spm *Mutator) HandleUpdate(ctx context.Context, req *admission.Request) admission.Response {
 	oldSp, err := decodeShadowPod(spm.decoder, req.OldObject)
 	if err != nil {
 		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed decoding of ShadowPod: %w", err))
@@ -327,7 +463,7 @@ func (spm *Mutator) HandleUpdate(req *admission.Request) admission.Response {
 		return admission.Denied("missing creator name")
 	}
 
-	creatorName, err := extractCreatorInfo(&req.UserInfo)
+	creatorName, err := extractCreatorInfo(ctx, spm.client, &req.UserInfo)
 	if err != nil {
 		klog.Warningf("Failed extracting creator info: %v", err)
 		return admission.Denied(err.Error())
@@ -356,11 +492,16 @@ func (spm *Mutator) HandleDelete() admission.Response {
 	return admission.Allowed("")
 }
 
-func extractCreatorInfo(userInfo *authenticationv1.UserInfo) (creatorName string, err error) {
-	creatorName = userInfo.Username
-	if creatorName == "" {
+// extractCreatorInfo returns the name of the user performing the request. The requests authenticated through token identities
+// are attributed to the user of the equivalent certificate identity, so that the same quota applies.
+func extractCreatorInfo(ctx context.Context, cl client.Client, userInfo *authenticationv1.UserInfo) (creatorName string, err error) {
+	if userInfo.Username == "" {
 		return "", fmt.Errorf("missing creator name")
 	}
 
-	return
+	creatorName, _, err = authentication.ResolveUser(ctx, cl, userInfo.Username, userInfo.Groups)
+	if err != nil {
+		return "", fmt.Errorf("failed resolving user %q: %w", userInfo.Username, err)
+	}
+	return creatorName, nil
 }
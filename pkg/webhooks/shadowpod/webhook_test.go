@@ -22,14 +22,18 @@ import (
 	. "github.com/onsi/gomega"
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
 	liqov1beta1 "github.com/liqotech/liqo/apis/core/v1beta1"
 	offloadingv1beta1 "github.com/liqotech/liqo/apis/offloading/v1beta1"
+	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/utils/testutil"
 )
 
@@ -56,6 +60,7 @@ var _ = Describe("Validating webhook", func() {
 		fakeClient = fake.NewClientBuilder().WithScheme(scheme).
 			WithObjects(fakeNamespace, foreignCluster, quota, quota2).
 			WithStatusSubresource(
+				&authv1beta1.ResourceSlice{},
 				&liqov1beta1.ForeignCluster{},
 				&offloadingv1beta1.ShadowPod{}).
 			Build()
@@ -105,6 +110,112 @@ var _ = Describe("Validating webhook", func() {
 				Expect(response.Result.Code).To(BeNumerically("==", http.StatusBadRequest))
 			})
 		})
+		When("the shadowpod namespace enforces a runtime class", func() {
+			BeforeEach(func() {
+				fakeNamespace.Annotations = map[string]string{consts.RemoteNamespaceRuntimeClassAnnotationKey: "gvisor"}
+				Expect(fakeClient.Update(ctx, fakeNamespace)).To(Succeed())
+				fakeNewShadowPod = forgeShadowPodWithClusterID(clusterID, userName, testNamespace)
+			})
+
+			When("the shadowpod does not specify the enforced runtime class", func() {
+				BeforeEach(func() {
+					request = forgeRequest(admissionv1.Create, fakeNewShadowPod, nil)
+				})
+				It("should return a forbidden response", func() {
+					Expect(response.Allowed).To(BeFalse())
+					Expect(response.Result.Code).To(BeNumerically("==", http.StatusForbidden))
+				})
+			})
+
+			When("the shadowpod specifies the enforced runtime class", func() {
+				BeforeEach(func() {
+					fakeNewShadowPod.Spec.Pod.RuntimeClassName = ptr.To("gvisor")
+					request = forgeRequest(admissionv1.Create, fakeNewShadowPod, nil)
+				})
+				It("should admit the request", func() {
+					Expect(response.Allowed).To(BeTrue())
+				})
+			})
+		})
+		When("the shadowpod violates a ShadowPodPolicy", func() {
+			BeforeEach(func() {
+				Expect(fakeClient.Create(ctx, &offloadingv1beta1.ShadowPodPolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+					Spec: offloadingv1beta1.ShadowPodPolicySpec{ShadowPodPolicyRules: offloadingv1beta1.ShadowPodPolicyRules{
+						AllowedImageRegistries: []string{"registry.example.com"},
+					}},
+				})).To(Succeed())
+				fakeNewShadowPod = forgeShadowPodWithClusterID(clusterID, userName, testNamespace)
+				fakeNewShadowPod.Spec.Pod.Containers = []corev1.Container{{Name: "app", Image: "nginx"}}
+				request = forgeRequest(admissionv1.Create, fakeNewShadowPod, nil)
+			})
+			It("should deny the request with the policy violation reason", func() {
+				Expect(response.Allowed).To(BeFalse())
+				Expect(response.Result.Code).To(BeNumerically("==", http.StatusForbidden))
+				Expect(response.Result.Reason).To(Equal(offloadingv1beta1.ShadowPodPolicyViolationReason))
+				Expect(response.Result.Message).To(ContainSubstring(`ShadowPodPolicy "policy" violated`))
+			})
+
+			When("the shadowpod is updated to violate it", func() {
+				BeforeEach(func() {
+					oldShadowPod := fakeNewShadowPod.DeepCopy()
+					oldShadowPod.Spec.Pod.Containers[0].Image = "registry.example.com/nginx"
+					request = forgeRequest(admissionv1.Update, fakeNewShadowPod, oldShadowPod)
+				})
+				It("should deny the request with the policy violation reason", func() {
+					Expect(response.Allowed).To(BeFalse())
+					Expect(response.Result.Reason).To(Equal(offloadingv1beta1.ShadowPodPolicyViolationReason))
+				})
+			})
+
+			When("the metadata of a shadowpod violating it is updated", func() {
+				BeforeEach(func() {
+					oldShadowPod := fakeNewShadowPod.DeepCopy()
+					oldShadowPod.Finalizers = []string{"finalizer"}
+					request = forgeRequest(admissionv1.Update, fakeNewShadowPod, oldShadowPod)
+				})
+				It("should admit the request", func() {
+					Expect(response.Allowed).To(BeTrue())
+				})
+			})
+		})
+	})
+
+	Describe("Validating the placement of a ShadowPod without resource validation", func() {
+		BeforeEach(func() {
+			Expect(fakeClient.Create(ctx, &authv1beta1.ResourceSlice{
+				ObjectMeta: metav1.ObjectMeta{Name: "slice", Namespace: tenantNamespace,
+					Labels: map[string]string{consts.RemoteClusterID: string(clusterID)}},
+			})).To(Succeed())
+
+			var resourceSlice authv1beta1.ResourceSlice
+			Expect(fakeClient.Get(ctx, client.ObjectKey{Name: "slice", Namespace: tenantNamespace}, &resourceSlice)).To(Succeed())
+			resourceSlice.Status.NodeSelector = map[string]string{"pool": "liqo"}
+			Expect(fakeClient.Status().Update(ctx, &resourceSlice)).To(Succeed())
+
+			// The creator has no Quota, hence the ResourceSlices of the consumer cluster are considered.
+			fakeNewShadowPod = forgeShadowPodWithClusterID(clusterID, userName3, testNamespace)
+		})
+
+		JustBeforeEach(func() {
+			request = forgeRequest(admissionv1.Create, fakeNewShadowPod, nil)
+			response = spValidator.Handle(ctx, request)
+		})
+
+		When("the node selector is compatible with the granted one", func() {
+			BeforeEach(func() { fakeNewShadowPod.Spec.Pod.NodeSelector = map[string]string{"pool": "liqo"} })
+			It("should admit the request", func() {
+				Expect(response.Allowed).To(BeTrue())
+			})
+		})
+
+		When("the node selector conflicts with the granted one", func() {
+			BeforeEach(func() { fakeNewShadowPod.Spec.Pod.NodeSelector = map[string]string{"pool": "other"} })
+			It("should return a forbidden response", func() {
+				Expect(response.Allowed).To(BeFalse())
+				Expect(response.Result.Code).To(BeNumerically("==", http.StatusForbidden))
+			})
+		})
 	})
 
 	Describe("Handle creation ShadowPod with resource validation", func() {
@@ -0,0 +1,114 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/authentication"
+)
+
+// +kubebuilder:rbac:groups=authentication.liqo.io,resources=revokedcertificates,verbs=get;list;watch
+
+// revocationAuthorizer is an authorization webhook denying the requests performed by revoked identities.
+type revocationAuthorizer struct {
+	client client.Client
+}
+
+// NewRevocationAuthorizer returns a new http handler implementing the kube-apiserver authorization webhook
+// protocol, which denies the requests authenticated through any RevokedCertificate, and expresses no opinion
+// on all the others (so that they are evaluated by the following authorizers, e.g., RBAC).
+func NewRevocationAuthorizer(cl client.Client) http.Handler {
+	return &revocationAuthorizer{client: cl}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (a *revocationAuthorizer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var review authorizationv1.SubjectAccessReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		klog.Errorf("Failed decoding SubjectAccessReview: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review.Status = a.review(r.Context(), &review.Spec)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&review); err != nil {
+		klog.Errorf("Failed encoding SubjectAccessReview: %v", err)
+	}
+}
+
+func (a *revocationAuthorizer) review(ctx context.Context, spec *authorizationv1.SubjectAccessReviewSpec) authorizationv1.SubjectAccessReviewStatus {
+	// When the API server provides the fingerprint of the client certificate, only that certificate is looked up,
+	// so that the new certificates issued to the same consumer (e.g., after peering again) are not affected.
+	if fingerprint, ok := authentication.CertificateFingerprint(spec); ok {
+		var revoked authv1beta1.RevokedCertificate
+		switch err := a.client.Get(ctx, client.ObjectKey{Name: fingerprint}, &revoked); {
+		case apierrors.IsNotFound(err):
+			return authorizationv1.SubjectAccessReviewStatus{Allowed: false}
+		case err != nil:
+			klog.Errorf("Failed retrieving RevokedCertificate %q: %v", fingerprint, err)
+			return authorizationv1.SubjectAccessReviewStatus{EvaluationError: fmt.Sprintf("failed retrieving RevokedCertificate: %v", err)}
+		default:
+			return deny(spec, &revoked)
+		}
+	}
+
+	// Otherwise, fall back to the user and groups of the certificates which are revoked and not yet expired.
+	var revoked authv1beta1.RevokedCertificateList
+	if err := a.client.List(ctx, &revoked); err != nil {
+		klog.Errorf("Failed listing RevokedCertificates: %v", err)
+		return authorizationv1.SubjectAccessReviewStatus{EvaluationError: fmt.Sprintf("failed listing RevokedCertificates: %v", err)}
+	}
+
+	now := time.Now()
+	for i := range revoked.Items {
+		crt := &revoked.Items[i].Spec
+		if crt.NotAfter.Time.Before(now) {
+			continue
+		}
+		if crt.User == spec.User || slices.ContainsFunc(crt.Groups, func(group string) bool {
+			return slices.Contains(spec.Groups, group)
+		}) {
+			return deny(spec, &revoked.Items[i])
+		}
+	}
+
+	// No opinion: let the other authorizers decide.
+	return authorizationv1.SubjectAccessReviewStatus{Allowed: false}
+}
+
+func deny(spec *authorizationv1.SubjectAccessReviewSpec, revoked *authv1beta1.RevokedCertificate) authorizationv1.SubjectAccessReviewStatus {
+	klog.V(4).Infof("Denying request of user %q: certificate %q of %s identity %q of cluster %q has been revoked",
+		spec.User, revoked.Name, revoked.Spec.IdentityType, revoked.Spec.Name, revoked.Spec.ConsumerClusterID)
+	return authorizationv1.SubjectAccessReviewStatus{
+		Allowed: false,
+		Denied:  true,
+		Reason: fmt.Sprintf("the certificate of the %s identity %q of cluster %q has been revoked",
+			revoked.Spec.IdentityType, revoked.Spec.Name, revoked.Spec.ConsumerClusterID),
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2019-2025 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	authv1beta1 "github.com/liqotech/liqo/apis/authentication/v1beta1"
+	tenantwk "github.com/liqotech/liqo/pkg/webhooks/tenant"
+)
+
+var _ = Describe("Revocation authorizer tests", func() {
+	const fingerprint = "0a1b2c3d"
+
+	var handler http.Handler
+
+	review := func(credentialID, user string, groups ...string) authorizationv1.SubjectAccessReviewStatus {
+		spec := authorizationv1.SubjectAccessReviewSpec{User: user, Groups: groups}
+		if credentialID != "" {
+			spec.Extra = map[string]authorizationv1.ExtraValue{"authentication.kubernetes.io/credential-id": {credentialID}}
+		}
+		body, err := json.Marshal(&authorizationv1.SubjectAccessReview{Spec: spec})
+		Expect(err).ToNot(HaveOccurred())
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		var res authorizationv1.SubjectAccessReview
+		Expect(json.NewDecoder(recorder.Body).Decode(&res)).To(Succeed())
+		return res.Status
+	}
+
+	revokedCertificate := func(name string, identityType authv1beta1.IdentityType, identity, user string,
+		notAfter time.Time, groups ...string) *authv1beta1.RevokedCertificate {
+		return &authv1beta1.RevokedCertificate{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: authv1beta1.RevokedCertificateSpec{
+				ConsumerClusterID: "fake-cluster",
+				IdentityType:      identityType,
+				Name:              identity,
+				User:              user,
+				Groups:            groups,
+				NotAfter:          metav1.NewTime(notAfter),
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		// No Tenant is created, as the revocations outlive it.
+		handler = tenantwk.NewRevocationAuthorizer(fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			revokedCertificate(fingerprint, authv1beta1.ControlPlaneIdentityType, "revoked-tenant", "revoked-user", time.Now().Add(time.Hour)),
+			revokedCertificate("ffff", authv1beta1.ResourceSliceIdentityType, "revoked-slice", "revoked-slice-user",
+				time.Now().Add(time.Hour), "revoked-group"),
+			revokedCertificate("eeee", authv1beta1.ControlPlaneIdentityType, "expired-tenant", "expired-user", time.Now().Add(-time.Hour)),
+		).Build())
+	})
+
+	It("Should deny the requests authenticated through a revoked certificate", func() {
+		status := review("X509SHA256=0A1B2C3D", "revoked-user", "system:authenticated")
+		Expect(status.Allowed).To(BeFalse())
+		Expect(status.Denied).To(BeTrue())
+		Expect(status.Reason).To(ContainSubstring("revoked-tenant"))
+	})
+
+	It("Should express no opinion on a new certificate issued to a revoked user", func() {
+		status := review("X509SHA256=9f9f9f9f", "revoked-user", "system:authenticated")
+		Expect(status.Allowed).To(BeFalse())
+		Expect(status.Denied).To(BeFalse())
+		Expect(status.EvaluationError).To(BeEmpty())
+	})
+
+	It("Should deny the requests of a revoked user, if the certificate fingerprint is not available", func() {
+		status := review("", "revoked-user", "system:authenticated")
+		Expect(status.Allowed).To(BeFalse())
+		Expect(status.Denied).To(BeTrue())
+		Expect(status.Reason).To(ContainSubstring("revoked-tenant"))
+	})
+
+	It("Should deny the requests of a member of a revoked group, if the certificate fingerprint is not available", func() {
+		status := review("", "another-user", "revoked-group")
+		Expect(status.Allowed).To(BeFalse())
+		Expect(status.Denied).To(BeTrue())
+		Expect(status.Reason).To(ContainSubstring("revoked-slice"))
+	})
+
+	It("Should express no opinion on the users of expired revoked certificates", func() {
+		status := review("", "expired-user", "system:authenticated")
+		Expect(status.Allowed).To(BeFalse())
+		Expect(status.Denied).To(BeFalse())
+	})
+
+	It("Should express no opinion on the requests of other identities", func() {
+		status := review("", "valid-user", "system:authenticated")
+		Expect(status.Allowed).To(BeFalse())
+		Expect(status.Denied).To(BeFalse())
+		Expect(status.EvaluationError).To(BeEmpty())
+	})
+})
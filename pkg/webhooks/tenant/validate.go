@@ -117,6 +117,20 @@ func (w *tenantValidatorWebhook) handleUpdate(ctx context.Context, req *admissio
 		return admission.Errored(status, err)
 	}
 
+	// Check that a revoked Tenant is not reactivated, as its identities would become valid again.
+	if len(req.OldObject.Raw) > 0 {
+		oldTenant, err := w.DecodeTenant(req.OldObject)
+		if err != nil {
+			klog.Errorf("Failed decoding old Tenant object: %v", err)
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+
+		if oldTenant.Spec.TenantCondition == authv1beta1.TenantConditionRevoked &&
+			tenant.Spec.TenantCondition != authv1beta1.TenantConditionRevoked {
+			return admission.Denied("a revoked Tenant cannot be reactivated: delete it and authenticate again")
+		}
+	}
+
 	// Check that the Tenant name is unique in the entire cluster.
 	tenantsInCluster, err := w.getTenants(ctx, corev1.NamespaceAll, &tenant.Name)
 	if err != nil {
@@ -173,6 +173,26 @@ var _ = Describe("Validating webhook tests", func() {
 				Expect(res.Result.Message).To(ContainSubstring("unique name across the cluster"))
 				Expect(res.Result.Code).To(Equal(int32(http.StatusForbidden)))
 			})
+
+			It("Should return an error if a revoked Tenant is reactivated", func() {
+				tenantNamespace := testutil.FakeNamespaceWithClusterID(liqov1beta1.ClusterID(clusterID), nsName)
+				oldTenant := generateFakeTenant("my-tenant", nsName, clusterID)
+				oldTenant.Spec.TenantCondition = authv1beta1.TenantConditionRevoked
+				newTenant := oldTenant.DeepCopy()
+				newTenant.Spec.TenantCondition = authv1beta1.TenantConditionActive
+				fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+					WithIndex(&authv1beta1.Tenant{}, "metadata.name", tenantwk.NameExtractor).
+					WithObjects(tenantNamespace, oldTenant).
+					Build()
+
+				validator := tenantwk.NewValidator(fakeClient)
+
+				req := generateAdmissionRequest(newTenant, admissionv1.Update)
+				req.OldObject = tenantToRawExtension(oldTenant)
+				res := validator.Handle(context.TODO(), req)
+				Expect(res.Allowed).To(BeFalse(), "Expected request to be denied")
+				Expect(res.Result.Message).To(ContainSubstring("cannot be reactivated"))
+			})
 		})
 	})
 })